@@ -11,6 +11,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	_ "time/tzdata"
 )
 
 func main() {
@@ -36,11 +37,13 @@ func main() {
 		}
 	}()
 
-	application := app.New(ctx, log, storages, cfg.JWT.TokenTTL, cfg.JWT.Secret, cfg.HTTPPort)
+	application := app.New(ctx, log, storages, cfg)
 
 	go func() {
 		application.HTTPServer.MustRun()
 	}()
 
+	go application.Jobs.Run(ctx)
+
 	<-ctx.Done()
 }
@@ -3,36 +3,166 @@ package app
 import (
 	"context"
 	httpapp "github.com/k6mil6/hackathon-game-backend/internal/app/http"
+	jobsapp "github.com/k6mil6/hackathon-game-backend/internal/app/jobs"
+	"github.com/k6mil6/hackathon-game-backend/internal/config"
+	achievementsservice "github.com/k6mil6/hackathon-game-backend/internal/service/achievements"
+	approvalsservice "github.com/k6mil6/hackathon-game-backend/internal/service/approvals"
 	authservice "github.com/k6mil6/hackathon-game-backend/internal/service/auth"
+	bountiesservice "github.com/k6mil6/hackathon-game-backend/internal/service/bounties"
+	budgetsservice "github.com/k6mil6/hackathon-game-backend/internal/service/budgets"
+	companionsservice "github.com/k6mil6/hackathon-game-backend/internal/service/companions"
+	dropsservice "github.com/k6mil6/hackathon-game-backend/internal/service/drops"
+	duelsservice "github.com/k6mil6/hackathon-game-backend/internal/service/duels"
+	eventsservice "github.com/k6mil6/hackathon-game-backend/internal/service/events"
+	intelservice "github.com/k6mil6/hackathon-game-backend/internal/service/intel"
+	interceptsservice "github.com/k6mil6/hackathon-game-backend/internal/service/intercepts"
+	kudosservice "github.com/k6mil6/hackathon-game-backend/internal/service/kudos"
+	leaderboardsservice "github.com/k6mil6/hackathon-game-backend/internal/service/leaderboards"
+	levelsservice "github.com/k6mil6/hackathon-game-backend/internal/service/levels"
+	notificationsservice "github.com/k6mil6/hackathon-game-backend/internal/service/notifications"
+	perksservice "github.com/k6mil6/hackathon-game-backend/internal/service/perks"
+	productionservice "github.com/k6mil6/hackathon-game-backend/internal/service/production"
+	questsservice "github.com/k6mil6/hackathon-game-backend/internal/service/quests"
+	quizzesservice "github.com/k6mil6/hackathon-game-backend/internal/service/quizzes"
+	rafflesservice "github.com/k6mil6/hackathon-game-backend/internal/service/raffles"
+	reviewsservice "github.com/k6mil6/hackathon-game-backend/internal/service/reviews"
+	searchservice "github.com/k6mil6/hackathon-game-backend/internal/service/search"
+	seasonsservice "github.com/k6mil6/hackathon-game-backend/internal/service/seasons"
+	shopservice "github.com/k6mil6/hackathon-game-backend/internal/service/shop"
+	streaksservice "github.com/k6mil6/hackathon-game-backend/internal/service/streaks"
 	tasksservice "github.com/k6mil6/hackathon-game-backend/internal/service/tasks"
+	teamsservice "github.com/k6mil6/hackathon-game-backend/internal/service/teams"
 	transactionsservice "github.com/k6mil6/hackathon-game-backend/internal/service/transactions"
 	usersservice "github.com/k6mil6/hackathon-game-backend/internal/service/users"
 	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres"
 	"log/slog"
-	"time"
 )
 
 type App struct {
 	HTTPServer *httpapp.App
+	Jobs       *jobsapp.App
 }
 
 func New(
 	ctx context.Context,
 	log *slog.Logger,
 	storages *postgres.Storages,
-	tokenTTL time.Duration,
-	secret string,
-	port int,
+	cfg *config.Config,
 ) *App {
-	auth := authservice.New(log, storages.UsersStorage, storages.AdminsStorage, tokenTTL, secret)
+	auth := authservice.New(log, storages.UsersStorage, storages.AdminsStorage, cfg.JWT.TokenTTL, cfg.JWT.Secret)
 
-	tasks := tasksservice.New(log, storages.TasksStorage)
-	transactions := transactionsservice.New(log, storages.TransactionsStorage)
+	companions := companionsservice.New(log, storages.CompanionsStorage, cfg.Companion)
+	perks := perksservice.New(log, storages.UsersStorage, companions, cfg.Perks)
+	levels := levelsservice.New(log, storages.XPStorage, storages.UsersStorage, storages.NotificationsStorage, cfg.Levels)
+	achievements := achievementsservice.New(log, storages.AchievementsStorage, storages.NotificationsStorage, levels)
+	streaks := streaksservice.New(log, storages.StreaksStorage, cfg.Location(), cfg.Streak)
+	quests := questsservice.New(
+		log,
+		storages.QuestsStorage,
+		storages.NotificationsStorage,
+		storages.BudgetsStorage,
+		cfg.Budget,
+	)
+	duels := duelsservice.New(log, storages.DuelsStorage, storages.NotificationsStorage, cfg.Duel)
+	bounties := bountiesservice.New(log, storages.BountiesStorage, storages.NotificationsStorage, cfg.Bounty)
+	drops := dropsservice.New(log, storages.DropsStorage, cfg.Drop)
+	raffles := rafflesservice.New(log, storages.RafflesStorage, storages.NotificationsStorage, cfg.Raffle)
+	events := eventsservice.New(
+		log,
+		storages.EventsStorage,
+		storages.NotificationsStorage,
+		storages.BudgetsStorage,
+		cfg.Event,
+		cfg.Budget,
+		cfg.JWT.Secret,
+	)
+	quizzes := quizzesservice.New(log, storages.QuizzesStorage, storages.BudgetsStorage, levels, streaks, cfg.Quiz, cfg.Budget)
+	production := productionservice.New(log, storages.ProductionStorage, storages.BusinessesStorage, achievements, cfg.Production)
+
+	tasks := tasksservice.New(
+		log,
+		storages.TasksStorage,
+		storages.NotificationsStorage,
+		storages.ApprovalsStorage,
+		storages.BudgetsStorage,
+		perks,
+		levels,
+		achievements,
+		streaks,
+		quests,
+		duels,
+		cfg.Approval,
+		cfg.Budget,
+	)
+	transactions := transactionsservice.New(log, storages.TransactionsStorage, achievements)
 	users := usersservice.New(log, storages.UsersStorage, storages.BalancesStorage)
+	search := searchservice.New(log, storages.SearchStorage)
+	notifications := notificationsservice.New(log, storages.NotificationsStorage)
+	reviews := reviewsservice.New(log, storages.ReviewsStorage, storages.TasksStorage, cfg.Review)
+	approvals := approvalsservice.New(
+		log,
+		storages.ApprovalsStorage,
+		storages.TasksStorage,
+		storages.NotificationsStorage,
+		storages.BudgetsStorage,
+		perks,
+		tasks,
+	)
+	budgets := budgetsservice.New(log, storages.BudgetsStorage)
+
+	intercepts := interceptsservice.New(
+		log,
+		storages.InterceptsStorage,
+		storages.UsersStorage,
+		storages.TasksStorage,
+		storages.NotificationsStorage,
+		perks,
+		cfg.Intercept,
+	)
+
+	shop := shopservice.New(log, storages.ShopItemsStorage, storages.PurchasesStorage, achievements)
+	kudos := kudosservice.New(log, storages.KudosStorage, storages.NotificationsStorage, cfg.Location(), cfg.Kudos)
+	teams := teamsservice.New(
+		log,
+		storages.TeamsStorage,
+		storages.NotificationsStorage,
+		storages.BudgetsStorage,
+		cfg.Team,
+		cfg.Approval,
+		cfg.Budget,
+	)
+
+	leaderboards := leaderboardsservice.New(log, storages.LeaderboardsStorage, storages.SeasonsStorage)
+	seasons := seasonsservice.New(
+		log,
+		storages.SeasonsStorage,
+		storages.LeaderboardsStorage,
+		storages.NotificationsStorage,
+		cfg.Season,
+	)
+
+	intel := intelservice.New(
+		log,
+		storages.IntelStorage,
+		storages.UsersStorage,
+		storages.TasksStorage,
+		storages.NotificationsStorage,
+		cfg.Intel,
+	)
+
+	httpApp := httpapp.New(ctx, log, cfg.HTTPPort, auth, storages.AdminsStorage, tasks, transactions, users, search, notifications, reviews, approvals, budgets, perks, intercepts, intel, levels, achievements, leaderboards, seasons, streaks, shop, kudos, teams, quests, duels, bounties, drops, raffles, events, quizzes, production, companions, cfg.JWT.Secret)
 
-	httpApp := httpapp.New(ctx, log, port, auth, tasks, transactions, users, secret)
+	jobsApp := jobsapp.New(log,
+		jobsapp.Job{Name: "season rollover", Interval: cfg.Season.RolloverInterval, Run: seasons.Rollover},
+		jobsapp.Job{Name: "quest reconcile", Interval: cfg.Quest.ReconcileInterval, Run: quests.Reconcile},
+		jobsapp.Job{Name: "duel expiry", Interval: cfg.Duel.ExpiryInterval, Run: duels.Expire},
+		jobsapp.Job{Name: "coin drops", Interval: cfg.Drop.SpawnInterval, Run: drops.Spawn},
+		jobsapp.Job{Name: "raffle draw", Interval: cfg.Raffle.DrawInterval, Run: raffles.Draw},
+		jobsapp.Job{Name: "production", Interval: cfg.Production.Interval, Run: production.Produce},
+	)
 
 	return &App{
 		HTTPServer: httpApp,
+		Jobs:       jobsApp,
 	}
 }
@@ -6,20 +6,151 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	adminApprovalsAll "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/approvals/all"
+	adminApprovalsApprove "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/approvals/approve"
+	adminApprovalsDeny "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/approvals/deny"
+	adminBountiesDisputes "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/bounties/disputes"
+	adminBountiesResolve "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/bounties/resolve"
+	adminBudgetsActive "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/budgets/active"
+	adminBudgetsAll "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/budgets/all"
+	adminBudgetsAllocate "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/budgets/allocate"
+	adminDropsAll "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/drops/all"
+	adminDropsCreate "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/drops/create"
+	adminDropsStop "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/drops/stop"
+	adminDuelsAll "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/duels/all"
+	adminDuelsResolve "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/duels/resolve"
+	adminEventsAll "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/events/all"
+	adminEventsCancel "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/events/cancel"
+	adminEventsCode "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/events/code"
+	adminEventsCreate "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/events/create"
+	adminEventsRSVPs "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/events/rsvps"
+	adminGroupsReviewer "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/groups/reviewer"
+	adminInterceptsAll "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/intercepts/all"
 	adminLogin "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/login"
+	adminQuestsAll "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/quests/all"
+	adminQuestsCreate "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/quests/create"
+	adminQuestsPublish "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/quests/publish"
+	adminQuestsUpdate "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/quests/update"
+	adminQuizzesAll "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/quizzes/all"
+	adminQuizzesClose "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/quizzes/close"
+	adminQuizzesCreate "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/quizzes/create"
+	adminQuizzesGet "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/quizzes/get"
+	adminQuizzesResults "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/quizzes/results"
+	adminRafflesAll "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/raffles/all"
+	adminRafflesCancel "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/raffles/cancel"
+	adminRafflesCreate "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/raffles/create"
 	adminRegister "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/register"
+	adminReviewDelegate "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/review/delegate"
+	adminReviewDelegations "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/review/delegations"
+	adminReviewQueue "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/review/queue"
+	adminReviewRevoke "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/review/revoke"
+	adminSeasonsCreate "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/seasons/create"
 	adminTasksAccept "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/tasks/accept"
 	adminTasksALl "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/tasks/all"
+	adminTasksCancel "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/tasks/cancel"
 	adminTasksCreate "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/tasks/create"
+	adminTasksReassign "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/tasks/reassign"
+	adminTasksReviewer "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/tasks/reviewer"
+	adminTasksUpdate "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/tasks/update"
+	adminTeamsAccept "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/teams/accept"
+	adminTeamsCompletions "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/teams/completions"
+	adminTeamsTask "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/teams/task"
 	adminUserAll "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/user/all"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/search"
+	userAchievementsAll "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/achievements/all"
+	userAchievementsMine "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/achievements/mine"
+	userBountiesAbandon "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/bounties/abandon"
+	userBountiesApprove "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/bounties/approve"
+	userBountiesCancel "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/bounties/cancel"
+	userBountiesClaim "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/bounties/claim"
+	userBountiesDispute "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/bounties/dispute"
+	userBountiesGet "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/bounties/get"
+	userBountiesMine "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/bounties/mine"
+	userBountiesOpen "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/bounties/open"
+	userBountiesPost "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/bounties/post"
+	userBountiesReject "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/bounties/reject"
+	userBountiesSubmit "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/bounties/submit"
+	userCompanionsFeed "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/companions/feed"
+	userCompanionsGet "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/companions/get"
+	userCompanionsPlay "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/companions/play"
+	userDropsActive "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/drops/active"
+	userDropsClaim "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/drops/claim"
+	userDuelsAccept "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/duels/accept"
+	userDuelsChallenge "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/duels/challenge"
+	userDuelsDecline "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/duels/decline"
+	userDuelsGet "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/duels/get"
+	userDuelsHistory "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/duels/history"
+	userDuelsMine "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/duels/mine"
+	userEventsCheckIn "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/events/checkin"
+	userEventsGet "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/events/get"
+	userEventsLeave "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/events/leave"
+	userEventsRSVP "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/events/rsvp"
+	userEventsUpcoming "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/events/upcoming"
+	userIntelAll "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/intel/all"
+	userIntelBuy "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/intel/buy"
+	userIntelTasks "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/intel/tasks"
+	userIntercepts "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/intercepts"
+	userKudosAllowance "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/kudos/allowance"
+	userKudosFeed "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/kudos/feed"
+	userKudosGive "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/kudos/give"
+	userKudosReceived "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/kudos/received"
+	userLeaderboardsBoard "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/leaderboards/board"
+	userLeaderboardsMe "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/leaderboards/me"
+	userLevel "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/level"
 	userLogin "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/login"
+	userNotificationsAll "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/notifications/all"
+	userNotificationsRead "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/notifications/read"
+	userPerks "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/perks"
+	userProductionBusinesses "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/production/businesses"
+	userProductionBuy "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/production/buy"
+	userProductionInventory "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/production/inventory"
+	userProductionOwned "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/production/owned"
+	userProductionRecipe "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/production/recipe"
+	userProductionRecipes "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/production/recipes"
+	userProductionSell "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/production/sell"
+	userProfile "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/profile"
+	userQuestsAll "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/quests/all"
+	userQuestsGet "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/quests/get"
+	userQuestsStart "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/quests/start"
+	userQuizzesGet "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/quizzes/get"
+	userQuizzesOpen "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/quizzes/open"
+	userQuizzesSubmission "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/quizzes/submission"
+	userQuizzesSubmit "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/quizzes/submit"
+	userRafflesAll "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/raffles/all"
+	userRafflesBuy "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/raffles/buy"
+	userRafflesGet "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/raffles/get"
+	userRafflesTickets "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/raffles/tickets"
 	userRegister "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/register"
+	userSeasonsAll "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/seasons/all"
+	userSeasonsStandings "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/seasons/standings"
+	userShopBuy "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/shop/buy"
+	userShopItems "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/shop/items"
+	userStreaksCheckin "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/streaks/checkin"
+	userStreaksMe "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/streaks/me"
 	userAllTasks "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/tasks/all"
 	userTasksComplete "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/tasks/complete"
 	userTasksDecline "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/tasks/decline"
+	userTasksGet "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/tasks/get"
+	userTasksIntercept "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/tasks/intercept"
+	userTeamsAccept "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/teams/accept"
+	userTeamsContribute "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/teams/contribute"
+	userTeamsCreate "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/teams/create"
+	userTeamsDecline "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/teams/decline"
+	userTeamsGet "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/teams/get"
+	userTeamsInvitations "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/teams/invitations"
+	userTeamsInvite "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/teams/invite"
+	userTeamsLeaderboard "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/teams/leaderboard"
+	userTeamsLeave "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/teams/leave"
+	userTeamsMine "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/teams/mine"
+	userTeamsPayout "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/teams/payout"
+	userTeamsSubmit "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/teams/submit"
+	userTeamsTasks "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/teams/tasks"
+	userTeamsWallet "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/teams/wallet"
 	userTop "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/top"
+	userXP "github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/xp"
 	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
 	mwlogger "github.com/k6mil6/hackathon-game-backend/internal/http/middleware/logger"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/jwt"
 	"log/slog"
 	"net/http"
 )
@@ -35,9 +166,35 @@ func New(
 	log *slog.Logger,
 	port int,
 	auth httpserver.Auth,
+	superAdmins identity.SuperAdmins,
 	tasks httpserver.Tasks,
 	transactions httpserver.Transactions,
 	users httpserver.Users,
+	searchService httpserver.Search,
+	notifications httpserver.Notifications,
+	reviews httpserver.Reviews,
+	approvals httpserver.Approvals,
+	budgets httpserver.Budgets,
+	perks httpserver.Perks,
+	intercepts httpserver.Intercepts,
+	intel httpserver.Intel,
+	levels httpserver.Levels,
+	achievements httpserver.Achievements,
+	leaderboards httpserver.Leaderboards,
+	seasons httpserver.Seasons,
+	streaks httpserver.Streaks,
+	shop httpserver.Shop,
+	kudos httpserver.Kudos,
+	teams httpserver.Teams,
+	quests httpserver.Quests,
+	duels httpserver.Duels,
+	bounties httpserver.Bounties,
+	drops httpserver.Drops,
+	raffles httpserver.Raffles,
+	events httpserver.Events,
+	quizzes httpserver.Quizzes,
+	production httpserver.Production,
+	companions httpserver.Companions,
 	secret string,
 ) *App {
 	router := chi.NewRouter()
@@ -55,16 +212,162 @@ func New(
 	routerWithAuth := chi.NewRouter()
 	routerWithAuth.Use(identity.New(secret))
 
-	routerWithAuth.Post("/admin/register", adminRegister.New(ctx, log, auth))
-	routerWithAuth.Post("/admin/task/create", adminTasksCreate.New(ctx, log, tasks))
+	routerWithAuth.Group(func(adminRouter chi.Router) {
+		adminRouter.Use(identity.RequireRole(jwt.RoleAdmin))
 
-	routerWithAuth.Get("/admin/user", adminUserAll.New(ctx, log, users))
-	routerWithAuth.Get("/admin/task", adminTasksALl.New(ctx, log, tasks))
-	routerWithAuth.Get("/admin/task/accept/{id}", adminTasksAccept.New(ctx, log, tasks, transactions))
+		adminRouter.Post("/admin/register", adminRegister.New(ctx, log, auth))
+		adminRouter.Post("/admin/task/create", adminTasksCreate.New(ctx, log, tasks))
+		adminRouter.Post("/admin/task/update/{id}", adminTasksUpdate.New(ctx, log, tasks))
+		adminRouter.Post("/admin/task/reassign/{id}", adminTasksReassign.New(ctx, log, tasks))
+		adminRouter.Post("/admin/task/cancel/{id}", adminTasksCancel.New(ctx, log, tasks))
+		adminRouter.Post("/admin/task/reviewer/{id}", adminTasksReviewer.New(ctx, log, reviews))
+		adminRouter.Post("/admin/review/delegate", adminReviewDelegate.New(ctx, log, reviews))
+		adminRouter.Post("/admin/approval/deny/{id}", adminApprovalsDeny.New(ctx, log, approvals))
+		adminRouter.Post("/admin/team/task", adminTeamsTask.New(ctx, log, teams))
+		adminRouter.Post("/admin/quest", adminQuestsCreate.New(ctx, log, quests))
+		adminRouter.Post("/admin/quest/update/{id}", adminQuestsUpdate.New(ctx, log, quests))
+		adminRouter.Post("/admin/duel/resolve/{id}", adminDuelsResolve.New(ctx, log, duels))
+		adminRouter.Post("/admin/bounty/dispute/resolve/{id}", adminBountiesResolve.New(ctx, log, bounties))
+		adminRouter.Post("/admin/drop/campaign", adminDropsCreate.New(ctx, log, drops))
+		adminRouter.Post("/admin/raffle", adminRafflesCreate.New(ctx, log, raffles))
+		adminRouter.Post("/admin/event", adminEventsCreate.New(ctx, log, events))
+		adminRouter.Post("/admin/event/code/{id}", adminEventsCode.New(ctx, log, events))
+		adminRouter.Post("/admin/quiz", adminQuizzesCreate.New(ctx, log, quizzes))
 
-	routerWithAuth.Get("/user/task", userAllTasks.New(ctx, log, tasks))
-	routerWithAuth.Get("/user/task/decline/{id}", userTasksDecline.New(ctx, log, tasks))
-	routerWithAuth.Get("/user/task/complete/{id}", userTasksComplete.New(ctx, log, tasks))
+		adminRouter.Get("/admin/user", adminUserAll.New(ctx, log, users))
+		adminRouter.Get("/admin/task", adminTasksALl.New(ctx, log, tasks))
+		adminRouter.Get("/admin/task/accept/{id}", adminTasksAccept.New(ctx, log, tasks))
+		adminRouter.Get("/admin/review", adminReviewQueue.New(ctx, log, reviews))
+		adminRouter.Get("/admin/review/delegation", adminReviewDelegations.New(ctx, log, reviews))
+		adminRouter.Get("/admin/review/delegation/revoke/{id}", adminReviewRevoke.New(ctx, log, reviews))
+		adminRouter.Get("/admin/approval", adminApprovalsAll.New(ctx, log, approvals))
+		adminRouter.Get("/admin/approval/approve/{id}", adminApprovalsApprove.New(ctx, log, approvals))
+		adminRouter.Get("/admin/budget", adminBudgetsActive.New(ctx, log, budgets))
+		adminRouter.Get("/admin/intercept", adminInterceptsAll.New(ctx, log, intercepts))
+		adminRouter.Get("/admin/team/completion", adminTeamsCompletions.New(ctx, log, teams))
+		adminRouter.Get("/admin/team/completion/accept/{id}", adminTeamsAccept.New(ctx, log, teams))
+		adminRouter.Get("/admin/quest", adminQuestsAll.New(ctx, log, quests))
+		adminRouter.Get("/admin/quest/publish/{id}", adminQuestsPublish.New(ctx, log, quests))
+		adminRouter.Get("/admin/duel", adminDuelsAll.New(ctx, log, duels))
+		adminRouter.Get("/admin/bounty/dispute", adminBountiesDisputes.New(ctx, log, bounties))
+		adminRouter.Get("/admin/drop/campaign", adminDropsAll.New(ctx, log, drops))
+		adminRouter.Get("/admin/drop/campaign/stop/{id}", adminDropsStop.New(ctx, log, drops))
+		adminRouter.Get("/admin/raffle", adminRafflesAll.New(ctx, log, raffles))
+		adminRouter.Get("/admin/raffle/cancel/{id}", adminRafflesCancel.New(ctx, log, raffles))
+		adminRouter.Get("/admin/event", adminEventsAll.New(ctx, log, events))
+		adminRouter.Get("/admin/event/cancel/{id}", adminEventsCancel.New(ctx, log, events))
+		adminRouter.Get("/admin/event/rsvp/{id}", adminEventsRSVPs.New(ctx, log, events))
+		adminRouter.Get("/admin/quiz", adminQuizzesAll.New(ctx, log, quizzes))
+		adminRouter.Get("/admin/quiz/{id}", adminQuizzesGet.New(ctx, log, quizzes))
+		adminRouter.Get("/admin/quiz/close/{id}", adminQuizzesClose.New(ctx, log, quizzes))
+		adminRouter.Get("/admin/quiz/results/{id}", adminQuizzesResults.New(ctx, log, quizzes))
+
+		adminRouter.Group(func(superAdminRouter chi.Router) {
+			superAdminRouter.Use(identity.RequireSuperAdmin(superAdmins))
+
+			superAdminRouter.Post("/admin/group/reviewer", adminGroupsReviewer.New(ctx, log, reviews))
+			superAdminRouter.Post("/admin/budget", adminBudgetsAllocate.New(ctx, log, budgets))
+			superAdminRouter.Post("/admin/season", adminSeasonsCreate.New(ctx, log, seasons))
+
+			superAdminRouter.Get("/admin/budget/all", adminBudgetsAll.New(ctx, log, budgets))
+		})
+	})
+
+	routerWithAuth.Group(func(userRouter chi.Router) {
+		userRouter.Use(identity.RequireRole(jwt.RoleUser))
+
+		userRouter.Post("/user/intel", userIntelBuy.New(ctx, log, intel))
+		userRouter.Post("/user/checkin", userStreaksCheckin.New(ctx, log, streaks))
+		userRouter.Post("/user/shop/buy/{id}", userShopBuy.New(ctx, log, shop))
+		userRouter.Post("/user/kudos", userKudosGive.New(ctx, log, kudos))
+		userRouter.Post("/user/team", userTeamsCreate.New(ctx, log, teams))
+		userRouter.Post("/user/team/invite", userTeamsInvite.New(ctx, log, teams))
+		userRouter.Post("/user/team/contribute", userTeamsContribute.New(ctx, log, teams))
+		userRouter.Post("/user/team/payout", userTeamsPayout.New(ctx, log, teams))
+		userRouter.Post("/user/duel", userDuelsChallenge.New(ctx, log, duels))
+		userRouter.Post("/user/bounty", userBountiesPost.New(ctx, log, bounties))
+		userRouter.Post("/user/bounty/submit/{id}", userBountiesSubmit.New(ctx, log, bounties))
+		userRouter.Post("/user/bounty/reject/{id}", userBountiesReject.New(ctx, log, bounties))
+		userRouter.Post("/user/bounty/dispute/{id}", userBountiesDispute.New(ctx, log, bounties))
+		userRouter.Post("/user/raffle/ticket/{id}", userRafflesBuy.New(ctx, log, raffles))
+		userRouter.Post("/user/event/checkin", userEventsCheckIn.New(ctx, log, events))
+		userRouter.Post("/user/quiz/submit/{id}", userQuizzesSubmit.New(ctx, log, quizzes))
+		userRouter.Post("/user/business/buy/{id}", userProductionBuy.New(ctx, log, production))
+		userRouter.Post("/user/business/recipe/{id}", userProductionRecipe.New(ctx, log, production))
+		userRouter.Post("/user/inventory/sell", userProductionSell.New(ctx, log, production))
+		userRouter.Post("/user/companion/feed", userCompanionsFeed.New(ctx, log, companions))
+		userRouter.Post("/user/companion/play", userCompanionsPlay.New(ctx, log, companions))
+
+		userRouter.Get("/user/task", userAllTasks.New(ctx, log, tasks))
+		userRouter.Get("/user/task/{id}", userTasksGet.New(ctx, log, tasks))
+		userRouter.Get("/user/task/decline/{id}", userTasksDecline.New(ctx, log, tasks))
+		userRouter.Get("/user/task/complete/{id}", userTasksComplete.New(ctx, log, tasks))
+		userRouter.Get("/user/task/intercept/{id}", userTasksIntercept.New(ctx, log, intercepts))
+		userRouter.Get("/user/intercept", userIntercepts.New(ctx, log, intercepts))
+		userRouter.Get("/user/intel", userIntelAll.New(ctx, log, intel))
+		userRouter.Get("/user/intel/{id}", userIntelTasks.New(ctx, log, intel))
+		userRouter.Get("/user/perk", userPerks.New(ctx, log, perks))
+		userRouter.Get("/user/level", userLevel.New(ctx, log, levels))
+		userRouter.Get("/user/xp", userXP.New(ctx, log, levels))
+		userRouter.Get("/user/achievement", userAchievementsMine.New(ctx, log, achievements))
+		userRouter.Get("/user/achievement/all", userAchievementsAll.New(ctx, log, achievements))
+		userRouter.Get("/user/leaderboard", userLeaderboardsBoard.New(ctx, log, leaderboards))
+		userRouter.Get("/user/leaderboard/me", userLeaderboardsMe.New(ctx, log, leaderboards))
+		userRouter.Get("/user/season", userSeasonsAll.New(ctx, log, seasons))
+		userRouter.Get("/user/season/{id}", userSeasonsStandings.New(ctx, log, seasons))
+		userRouter.Get("/user/streak", userStreaksMe.New(ctx, log, streaks))
+		userRouter.Get("/user/profile", userProfile.New(ctx, log, users, streaks))
+		userRouter.Get("/user/shop", userShopItems.New(ctx, log, shop))
+		userRouter.Get("/user/kudos", userKudosFeed.New(ctx, log, kudos))
+		userRouter.Get("/user/kudos/received", userKudosReceived.New(ctx, log, kudos))
+		userRouter.Get("/user/kudos/allowance", userKudosAllowance.New(ctx, log, kudos))
+		userRouter.Get("/user/team", userTeamsMine.New(ctx, log, teams))
+		userRouter.Get("/user/team/{id}", userTeamsGet.New(ctx, log, teams))
+		userRouter.Get("/user/team/invitation", userTeamsInvitations.New(ctx, log, teams))
+		userRouter.Get("/user/team/invitation/accept/{id}", userTeamsAccept.New(ctx, log, teams))
+		userRouter.Get("/user/team/invitation/decline/{id}", userTeamsDecline.New(ctx, log, teams))
+		userRouter.Get("/user/team/leave", userTeamsLeave.New(ctx, log, teams))
+		userRouter.Get("/user/team/wallet", userTeamsWallet.New(ctx, log, teams))
+		userRouter.Get("/user/team/task", userTeamsTasks.New(ctx, log, teams))
+		userRouter.Get("/user/team/task/submit/{id}", userTeamsSubmit.New(ctx, log, teams))
+		userRouter.Get("/user/team/leaderboard", userTeamsLeaderboard.New(ctx, log, teams))
+		userRouter.Get("/user/quest", userQuestsAll.New(ctx, log, quests))
+		userRouter.Get("/user/quest/{id}", userQuestsGet.New(ctx, log, quests))
+		userRouter.Get("/user/quest/start/{id}", userQuestsStart.New(ctx, log, quests))
+		userRouter.Get("/user/duel", userDuelsMine.New(ctx, log, duels))
+		userRouter.Get("/user/duel/history", userDuelsHistory.New(ctx, log, duels))
+		userRouter.Get("/user/duel/{id}", userDuelsGet.New(ctx, log, duels))
+		userRouter.Get("/user/duel/accept/{id}", userDuelsAccept.New(ctx, log, duels))
+		userRouter.Get("/user/duel/decline/{id}", userDuelsDecline.New(ctx, log, duels))
+		userRouter.Get("/user/bounty", userBountiesOpen.New(ctx, log, bounties))
+		userRouter.Get("/user/bounty/mine", userBountiesMine.New(ctx, log, bounties))
+		userRouter.Get("/user/bounty/{id}", userBountiesGet.New(ctx, log, bounties))
+		userRouter.Get("/user/bounty/claim/{id}", userBountiesClaim.New(ctx, log, bounties))
+		userRouter.Get("/user/bounty/abandon/{id}", userBountiesAbandon.New(ctx, log, bounties))
+		userRouter.Get("/user/bounty/approve/{id}", userBountiesApprove.New(ctx, log, bounties))
+		userRouter.Get("/user/bounty/cancel/{id}", userBountiesCancel.New(ctx, log, bounties))
+		userRouter.Get("/user/drop", userDropsActive.New(ctx, log, drops))
+		userRouter.Get("/user/drop/claim/{id}", userDropsClaim.New(ctx, log, drops))
+		userRouter.Get("/user/raffle", userRafflesAll.New(ctx, log, raffles))
+		userRouter.Get("/user/raffle/{id}", userRafflesGet.New(ctx, log, raffles))
+		userRouter.Get("/user/raffle/tickets/{id}", userRafflesTickets.New(ctx, log, raffles))
+		userRouter.Get("/user/event", userEventsUpcoming.New(ctx, log, events))
+		userRouter.Get("/user/event/{id}", userEventsGet.New(ctx, log, events))
+		userRouter.Get("/user/event/rsvp/{id}", userEventsRSVP.New(ctx, log, events))
+		userRouter.Get("/user/event/rsvp/cancel/{id}", userEventsLeave.New(ctx, log, events))
+		userRouter.Get("/user/quiz", userQuizzesOpen.New(ctx, log, quizzes))
+		userRouter.Get("/user/quiz/{id}", userQuizzesGet.New(ctx, log, quizzes))
+		userRouter.Get("/user/quiz/submission/{id}", userQuizzesSubmission.New(ctx, log, quizzes))
+		userRouter.Get("/user/business", userProductionBusinesses.New(ctx, log, production))
+		userRouter.Get("/user/business/my", userProductionOwned.New(ctx, log, production))
+		userRouter.Get("/user/recipe", userProductionRecipes.New(ctx, log, production))
+		userRouter.Get("/user/inventory", userProductionInventory.New(ctx, log, production))
+		userRouter.Get("/user/companion", userCompanionsGet.New(ctx, log, companions))
+		userRouter.Get("/user/notification", userNotificationsAll.New(ctx, log, notifications))
+		userRouter.Get("/user/notification/read/{id}", userNotificationsRead.New(ctx, log, notifications))
+	})
+
+	routerWithAuth.Get("/search", search.New(ctx, log, searchService))
 
 	router.Mount("/", routerWithAuth)
 
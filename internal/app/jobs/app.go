@@ -0,0 +1,67 @@
+package jobsapp
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Job is background work that runs every Interval until the application stops.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+type App struct {
+	log  *slog.Logger
+	jobs []Job
+}
+
+func New(log *slog.Logger, jobs ...Job) *App {
+	return &App{
+		log:  log,
+		jobs: jobs,
+	}
+}
+
+// Run starts every job and blocks until the context is done. Jobs run once right away,
+// a failed run is logged and the job is tried again on the next tick.
+func (a *App) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for _, job := range a.jobs {
+		wg.Add(1)
+
+		go func(job Job) {
+			defer wg.Done()
+
+			a.run(ctx, job)
+		}(job)
+	}
+
+	wg.Wait()
+}
+
+func (a *App) run(ctx context.Context, job Job) {
+	log := a.log.With(slog.String("job", job.Name))
+
+	log.Info("starting job", slog.Duration("interval", job.Interval))
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := job.Run(ctx); err != nil {
+			log.Error("job failed", slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info("stopping job")
+			return
+		case <-ticker.C:
+		}
+	}
+}
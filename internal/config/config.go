@@ -3,16 +3,38 @@ package config
 import (
 	"flag"
 	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/levels"
 	"os"
 	"time"
 )
 
 type Config struct {
-	Env            string    `yaml:"env" env-default:"local"`
-	DB             DBConfig  `yaml:"db" env-required:"true"`
-	JWT            JWTConfig `yaml:"jwt" env-required:"true"`
-	HTTPPort       int       `yaml:"http_port" env-default:"8080"`
-	MigrationsPath string    `yaml:"migrations_path" env-default:"./migrations"`
+	Env            string           `yaml:"env" env-default:"local"`
+	DB             DBConfig         `yaml:"db" env-required:"true"`
+	JWT            JWTConfig        `yaml:"jwt" env-required:"true"`
+	Review         ReviewConfig     `yaml:"review"`
+	Approval       ApprovalConfig   `yaml:"approval"`
+	Budget         BudgetConfig     `yaml:"budget"`
+	Perks          PerksConfig      `yaml:"perks"`
+	Intercept      InterceptConfig  `yaml:"intercept"`
+	Intel          IntelConfig      `yaml:"intel"`
+	Levels         LevelsConfig     `yaml:"levels"`
+	Season         SeasonConfig     `yaml:"season"`
+	Streak         StreakConfig     `yaml:"streak"`
+	Kudos          KudosConfig      `yaml:"kudos"`
+	Team           TeamConfig       `yaml:"team"`
+	Duel           DuelConfig       `yaml:"duel"`
+	Bounty         BountyConfig     `yaml:"bounty"`
+	Drop           DropConfig       `yaml:"drop"`
+	Raffle         RaffleConfig     `yaml:"raffle"`
+	Event          EventConfig      `yaml:"event"`
+	Quest          QuestConfig      `yaml:"quest"`
+	Quiz           QuizConfig       `yaml:"quiz"`
+	Production     ProductionConfig `yaml:"production"`
+	Companion      CompanionConfig  `yaml:"companion"`
+	TimeZone       string           `yaml:"time_zone" env-default:"UTC"`
+	HTTPPort       int              `yaml:"http_port" env-default:"8080"`
+	MigrationsPath string           `yaml:"migrations_path" env-default:"./migrations"`
 }
 
 type DBConfig struct {
@@ -26,6 +48,168 @@ type JWTConfig struct {
 	TokenTTL time.Duration `yaml:"token_ttl" env-default:"1h"`
 }
 
+// ReviewConfig sets how long a submitted task may wait for acceptance
+// before it is marked as aging (warning) and as overdue.
+type ReviewConfig struct {
+	SLAWarning time.Duration `yaml:"sla_warning" env-default:"24h"`
+	SLAOverdue time.Duration `yaml:"sla_overdue" env-default:"72h"`
+}
+
+// ApprovalConfig sets the reward from which creating and accepting a task
+// has to be confirmed by a second admin. Zero disables approvals.
+type ApprovalConfig struct {
+	Threshold float64 `yaml:"threshold" env-default:"1000"`
+}
+
+// BudgetConfig controls whether admins without an allocated budget may still create tasks,
+// by default they may not.
+type BudgetConfig struct {
+	Enforce bool `yaml:"enforce" env-default:"true"`
+}
+
+// PerksConfig tunes class perks: cats get CatRewardMultiplier times the reward,
+// dogs get DogDeadlineExtension of the task time window on top of the deadline.
+// The *Level fields set the level from which each perk is unlocked. The perks scale with the well-being
+// of the companion of the user and are off while it is below MinWellBeing.
+type PerksConfig struct {
+	CatRewardMultiplier  float64 `yaml:"cat_reward_multiplier" env-default:"1.2"`
+	DogDeadlineExtension float64 `yaml:"dog_deadline_extension" env-default:"0.5"`
+	CatRewardLevel       int     `yaml:"cat_reward_level" env-default:"1"`
+	DogDeadlineLevel     int     `yaml:"dog_deadline_level" env-default:"1"`
+	RacoonInterceptLevel int     `yaml:"racoon_intercept_level" env-default:"1"`
+	MinWellBeing         float64 `yaml:"min_well_being" env-default:"0.25"`
+}
+
+// InterceptConfig sets the rules of racoon intercepts: the chance of success, the penalty
+// the thief pays the victim when caught, how often a racoon may try and how long a victim
+// is protected after an attempt. A non-zero Seed makes the outcomes reproducible.
+type InterceptConfig struct {
+	SuccessChance  float64       `yaml:"success_chance" env-default:"0.5"`
+	Penalty        float64       `yaml:"penalty" env-default:"50"`
+	Cooldown       time.Duration `yaml:"cooldown" env-default:"24h"`
+	VictimCooldown time.Duration `yaml:"victim_cooldown" env-default:"12h"`
+	Seed           int64         `yaml:"seed" env-default:"0"`
+}
+
+// IntelConfig sets the price of a look at another user's tasks in progress, how long it lasts,
+// which classes from which level may buy it and whether the target learns about the purchase.
+type IntelConfig struct {
+	Fee         float64       `yaml:"fee" env-default:"20"`
+	Window      time.Duration `yaml:"window" env-default:"1h"`
+	ClassIDs    []int         `yaml:"class_ids" env-default:"3"`
+	AlertTarget bool          `yaml:"alert_target" env-default:"true"`
+	MinLevel    int           `yaml:"min_level" env-default:"1"`
+}
+
+// LevelsConfig sets the level curve: reaching level n takes BaseXP * (n-1)^Growth XP in total.
+// Tasks without their own XP give XPPerCoin XP for every coin of the reward.
+type LevelsConfig struct {
+	BaseXP    float64 `yaml:"base_xp" env-default:"100"`
+	Growth    float64 `yaml:"growth" env-default:"1.5"`
+	MaxLevel  int     `yaml:"max_level" env-default:"50"`
+	XPPerCoin float64 `yaml:"xp_per_coin" env-default:"1"`
+}
+
+// SeasonConfig sets the prizes of a finished season: Prizes[i] coins go to the user on place i+1.
+// RolloverInterval is how often the rollover job looks for finished seasons.
+type SeasonConfig struct {
+	Prizes           []float64     `yaml:"prizes" env-default:"500,300,100"`
+	RolloverInterval time.Duration `yaml:"rollover_interval" env-default:"1m"`
+}
+
+// StreakConfig sets the daily check-in bonus: a check-in on day n of a streak pays Bonuses[n-1],
+// longer streaks get the last bonus of the list.
+type StreakConfig struct {
+	Bonuses []float64 `yaml:"bonuses" env-default:"10,15,20,25,30,40,50"`
+}
+
+// KudosConfig sets how many kudos every user may give per calendar month, how many coins
+// the receiver gets for one kudo and how long the message may be.
+type KudosConfig struct {
+	MonthlyAllowance int     `yaml:"monthly_allowance" env-default:"100"`
+	CoinsPerKudo     float64 `yaml:"coins_per_kudo" env-default:"1"`
+	MaxMessageLength int     `yaml:"max_message_length" env-default:"500"`
+}
+
+// TeamConfig limits the size of a team and the length of its name.
+type TeamConfig struct {
+	MaxMembers    int `yaml:"max_members" env-default:"10"`
+	MaxNameLength int `yaml:"max_name_length" env-default:"50"`
+}
+
+// QuestConfig sets how often the reconcile job advances quests whose accepted step tasks
+// failed to advance them.
+type QuestConfig struct {
+	ReconcileInterval time.Duration `yaml:"reconcile_interval" env-default:"5m"`
+}
+
+// DuelConfig limits the stake of a duel, sets how long the opponent has to answer a challenge and how
+// long an accepted duel may stay unresolved. Duels past either time are refunded by a job running every
+// ExpiryInterval.
+type DuelConfig struct {
+	MaxStake        float64       `yaml:"max_stake" env-default:"500"`
+	ResponseTimeout time.Duration `yaml:"response_timeout" env-default:"24h"`
+	Duration        time.Duration `yaml:"duration" env-default:"168h"`
+	ExpiryInterval  time.Duration `yaml:"expiry_interval" env-default:"1m"`
+	MaxTermsLength  int           `yaml:"max_terms_length" env-default:"500"`
+}
+
+// BountyConfig limits the reward users may put on a bounty and the length of its title and texts.
+type BountyConfig struct {
+	MaxReward      float64 `yaml:"max_reward" env-default:"1000"`
+	MaxTitleLength int     `yaml:"max_title_length" env-default:"100"`
+	MaxTextLength  int     `yaml:"max_text_length" env-default:"2000"`
+}
+
+// DropConfig sets how often a job rolls the dice for the running drop campaigns, a campaign spawns a drop
+// with the chance of its rate per hour scaled to SpawnInterval. A non-zero Seed makes the rolls reproducible.
+type DropConfig struct {
+	SpawnInterval time.Duration `yaml:"spawn_interval" env-default:"1m"`
+	Seed          int64         `yaml:"seed" env-default:"0"`
+}
+
+// RaffleConfig sets how often a job draws the raffles whose draw time has come, and limits the tickets
+// a user may buy at once.
+type RaffleConfig struct {
+	DrawInterval  time.Duration `yaml:"draw_interval" env-default:"1m"`
+	MaxTicketsBuy int           `yaml:"max_tickets_buy" env-default:"100"`
+}
+
+// EventConfig sets how long a check-in code stays valid unless the admin asks otherwise, and the longest
+// lifetime an admin may give it.
+type EventConfig struct {
+	CodeTTL    time.Duration `yaml:"code_ttl" env-default:"5m"`
+	MaxCodeTTL time.Duration `yaml:"max_code_ttl" env-default:"24h"`
+}
+
+// QuizConfig limits the size of quizzes and polls and the length of their texts and short answers.
+// MaxResultTexts is how many of the most frequent short answers the results show per question.
+type QuizConfig struct {
+	MaxQuestions   int `yaml:"max_questions" env-default:"50"`
+	MaxOptions     int `yaml:"max_options" env-default:"10"`
+	MaxTextLength  int `yaml:"max_text_length" env-default:"500"`
+	MaxResultTexts int `yaml:"max_result_texts" env-default:"10"`
+}
+
+// ProductionConfig sets how often businesses are checked for due production, how many resource units
+// a user can store without warehouses and how many units can be sold at once.
+type ProductionConfig struct {
+	Interval     time.Duration `yaml:"interval" env-default:"1m"`
+	BaseCapacity int           `yaml:"base_capacity" env-default:"100"`
+	MaxSell      int           `yaml:"max_sell" env-default:"1000"`
+}
+
+// CompanionConfig sets how many points per hour companions get hungrier and sadder, how much playing
+// cheers them up and makes them hungry, how often they want to play and how much food they eat at once.
+type CompanionConfig struct {
+	HungerPerHour    float64       `yaml:"hunger_per_hour" env-default:"4"`
+	HappinessPerHour float64       `yaml:"happiness_per_hour" env-default:"3"`
+	PlayHappiness    float64       `yaml:"play_happiness" env-default:"20"`
+	PlayHunger       float64       `yaml:"play_hunger" env-default:"5"`
+	PlayCooldown     time.Duration `yaml:"play_cooldown" env-default:"1h"`
+	MaxFeed          int           `yaml:"max_feed" env-default:"20"`
+}
+
 func MustLoad() *Config {
 	configPath := fetchConfigPath()
 	if configPath == "" {
@@ -47,9 +231,29 @@ func MustLoadPath(configPath string) *Config {
 		panic("cannot read config: " + err.Error())
 	}
 
+	if _, err := time.LoadLocation(cfg.TimeZone); err != nil {
+		panic("invalid time zone: " + err.Error())
+	}
+
+	curve := levels.Curve{Base: cfg.Levels.BaseXP, Growth: cfg.Levels.Growth, MaxLevel: cfg.Levels.MaxLevel}
+	if err := curve.Validate(); err != nil {
+		panic("invalid level curve: " + err.Error())
+	}
+
 	return &cfg
 }
 
+// Location returns the company time zone days are counted in.
+func (c *Config) Location() *time.Location {
+	location, err := time.LoadLocation(c.TimeZone)
+	if err != nil {
+		// the time zone is validated on load
+		return time.UTC
+	}
+
+	return location
+}
+
 // fetchConfigPath fetches config path from command line flag or environment variable.
 // Priority: flag > env > default.
 // Default value is empty string.
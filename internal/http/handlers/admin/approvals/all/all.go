@@ -0,0 +1,113 @@
+package all
+
+import (
+	"context"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/request"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type Response struct {
+	resp.Response
+	Approvals []ResponseApproval `json:"approvals"`
+}
+
+type ResponseApproval struct {
+	ID          int        `json:"id"`
+	TaskID      int        `json:"task_id"`
+	ActionID    int        `json:"action_id"`
+	StatusID    int        `json:"status_id"`
+	Amount      float64    `json:"amount"`
+	RequestedBy int        `json:"requested_by"`
+	DecidedBy   int        `json:"decided_by,omitempty"`
+	Comment     string     `json:"comment,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty"`
+}
+
+func New(ctx context.Context, log *slog.Logger, approvals httpserver.Approvals) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.approvals.all.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		statusID, err := request.QueryInt(r, "status_id")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse status_id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		taskID, err := request.QueryInt(r, "task_id")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse task_id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		limit, err := request.QueryInt(r, "limit")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse limit", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		list, err := approvals.GetAll(ctx, statusID, taskID, limit)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get approvals", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get approvals"))
+
+			return
+		}
+
+		approvalsRes := make([]ResponseApproval, 0, len(list))
+
+		for _, approval := range list {
+			var decidedAt *time.Time
+			if !approval.DecidedAt.IsZero() {
+				decidedAt = &approval.DecidedAt
+			}
+
+			approvalsRes = append(approvalsRes, ResponseApproval{
+				ID:          approval.ID,
+				TaskID:      approval.TaskID,
+				ActionID:    approval.ActionID,
+				StatusID:    approval.StatusID,
+				Amount:      approval.Amount,
+				RequestedBy: approval.RequestedBy,
+				DecidedBy:   approval.DecidedBy,
+				Comment:     approval.Comment,
+				CreatedAt:   approval.CreatedAt,
+				DecidedAt:   decidedAt,
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response:  resp.OK(),
+			Approvals: approvalsRes,
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package deny
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	approvalsservice "github.com/k6mil6/hackathon-game-backend/internal/service/approvals"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type Request struct {
+	Reason string `json:"reason"`
+}
+
+type Response struct {
+	resp.Response
+	TaskID int `json:"task_id"`
+}
+
+func New(ctx context.Context, log *slog.Logger, approvals httpserver.Approvals) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.approvals.deny.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		approvalID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		req.Reason = strings.TrimSpace(req.Reason)
+		if req.Reason == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("reason is required")
+
+			render.JSON(w, r, resp.Error("reason is required"))
+
+			return
+		}
+
+		adminID, err := identity.GetAdminID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		approval, err := approvals.Deny(ctx, approvalID, adminID, req.Reason)
+		if err != nil {
+			switch {
+			case errors.Is(err, approvalsservice.ErrApprovalNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, approvalsservice.ErrSelfApproval),
+				errors.Is(err, approvalsservice.ErrCreatorApproval):
+				w.WriteHeader(http.StatusForbidden)
+			case errors.Is(err, approvalsservice.ErrApprovalStale):
+				w.WriteHeader(http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to deny", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to deny"))
+
+				return
+			}
+
+			log.Error("failed to deny", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		log.Info("denied", slog.Int("taskID", approval.TaskID))
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			TaskID:   approval.TaskID,
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package bounties
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"time"
+)
+
+const (
+	DecisionPay    = "pay"
+	DecisionRefund = "refund"
+)
+
+type ResponseDispute struct {
+	ID          int        `json:"id"`
+	BountyID    int        `json:"bounty_id"`
+	BountyTitle string     `json:"bounty_title"`
+	Reward      float64    `json:"reward"`
+	PosterID    int        `json:"poster_id"`
+	HunterID    int        `json:"hunter_id"`
+	RaisedBy    int        `json:"raised_by"`
+	Reason      string     `json:"reason"`
+	Decision    string     `json:"decision,omitempty"`
+	Note        string     `json:"note,omitempty"`
+	ResolvedBy  int        `json:"resolved_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+}
+
+func ToResponse(dispute model.BountyDispute) ResponseDispute {
+	res := ResponseDispute{
+		ID:          dispute.ID,
+		BountyID:    dispute.BountyID,
+		BountyTitle: dispute.BountyTitle,
+		Reward:      dispute.Reward,
+		PosterID:    dispute.PosterID,
+		HunterID:    dispute.HunterID,
+		RaisedBy:    dispute.RaisedBy,
+		Reason:      dispute.Reason,
+		Note:        dispute.Note,
+		ResolvedBy:  dispute.ResolvedBy,
+		CreatedAt:   dispute.CreatedAt,
+	}
+
+	if !dispute.ResolvedAt.IsZero() {
+		res.ResolvedAt = &dispute.ResolvedAt
+		res.Decision = DecisionRefund
+		if dispute.Paid {
+			res.Decision = DecisionPay
+		}
+	}
+
+	return res
+}
+
+func ToResponses(disputes []model.BountyDispute) []ResponseDispute {
+	disputesRes := make([]ResponseDispute, 0, len(disputes))
+
+	for _, dispute := range disputes {
+		disputesRes = append(disputesRes, ToResponse(dispute))
+	}
+
+	return disputesRes
+}
@@ -0,0 +1,70 @@
+package disputes
+
+import (
+	"context"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/bounties"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/request"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"log/slog"
+	"net/http"
+)
+
+type Response struct {
+	resp.Response
+	Disputes []bounties.ResponseDispute `json:"disputes"`
+}
+
+func New(ctx context.Context, log *slog.Logger, bountiesService httpserver.Bounties) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.bounties.disputes.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		limit, err := request.QueryInt(r, "limit")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse limit", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		pending := r.URL.Query().Get("all") != "true"
+
+		adminID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		disputes, err := bountiesService.GetDisputes(ctx, adminID, pending, limit)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get disputes", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get disputes"))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Disputes: bounties.ToResponses(disputes),
+		})
+	}
+}
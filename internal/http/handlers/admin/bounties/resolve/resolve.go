@@ -0,0 +1,126 @@
+package resolve
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/bounties"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	bountiesservice "github.com/k6mil6/hackathon-game-backend/internal/service/bounties"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Request struct {
+	Decision string `json:"decision"`
+	Note     string `json:"note,omitempty"`
+}
+
+type Response struct {
+	resp.Response
+	Dispute bounties.ResponseDispute `json:"dispute"`
+}
+
+func New(ctx context.Context, log *slog.Logger, bountiesService httpserver.Bounties) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.bounties.resolve.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		disputeID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		if req.Decision != bounties.DecisionPay && req.Decision != bounties.DecisionRefund {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("unknown decision", slog.String("decision", req.Decision))
+
+			render.JSON(w, r, resp.Error("decision must be pay or refund"))
+
+			return
+		}
+
+		adminID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		dispute, err := bountiesService.ResolveDispute(ctx, adminID, disputeID, req.Decision == bounties.DecisionPay, req.Note)
+		if err != nil {
+			switch {
+			case errors.Is(err, bountiesservice.ErrNotEnoughPermission):
+				w.WriteHeader(http.StatusForbidden)
+			case errors.Is(err, bountiesservice.ErrDisputeNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, bountiesservice.ErrDisputeAlreadyResolved):
+				w.WriteHeader(http.StatusConflict)
+			case errors.Is(err, bountiesservice.ErrTextTooLong):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to resolve dispute", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to resolve dispute"))
+
+				return
+			}
+
+			log.Error("failed to resolve dispute", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Dispute:  bounties.ToResponse(dispute),
+		})
+	}
+}
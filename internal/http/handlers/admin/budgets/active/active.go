@@ -0,0 +1,56 @@
+package active
+
+import (
+	"context"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/budgets"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"log/slog"
+	"net/http"
+)
+
+type Response struct {
+	resp.Response
+	Budgets []budgets.ResponseBudget `json:"budgets"`
+}
+
+func New(ctx context.Context, log *slog.Logger, budgetsService httpserver.Budgets) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.budgets.active.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		adminID, err := identity.GetAdminID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		active, err := budgetsService.GetActive(ctx, adminID)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get budgets", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get budgets"))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Budgets:  budgets.ToResponse(active),
+		})
+	}
+}
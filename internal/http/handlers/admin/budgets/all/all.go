@@ -0,0 +1,90 @@
+package all
+
+import (
+	"context"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/budgets"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/request"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"log/slog"
+	"net/http"
+)
+
+type Response struct {
+	resp.Response
+	Budgets []budgets.ResponseBudget `json:"budgets"`
+}
+
+func New(ctx context.Context, log *slog.Logger, budgetsService httpserver.Budgets) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.budgets.all.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		adminID, err := identity.GetAdminID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		filterAdminID, err := request.QueryInt(r, "admin_id")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse admin_id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		groupID, err := request.QueryInt(r, "group_id")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse group_id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		limit, err := request.QueryInt(r, "limit")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse limit", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		list, err := budgetsService.GetAll(ctx, adminID, filterAdminID, groupID, limit)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get budgets", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get budgets"))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Budgets:  budgets.ToResponse(list),
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package allocate
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	budgetsservice "github.com/k6mil6/hackathon-game-backend/internal/service/budgets"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type Request struct {
+	AdminID int       `json:"admin_id,omitempty"`
+	GroupID int       `json:"group_id,omitempty"`
+	Period  string    `json:"period"`
+	At      time.Time `json:"at,omitempty"`
+	Amount  float64   `json:"amount"`
+}
+
+type Response struct {
+	resp.Response
+	ID int `json:"id"`
+}
+
+func New(ctx context.Context, log *slog.Logger, budgets httpserver.Budgets) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.budgets.allocate.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		adminID, err := identity.GetAdminID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		id, err := budgets.Allocate(ctx, adminID, model.Budget{
+			AdminID: req.AdminID,
+			GroupID: req.GroupID,
+			Amount:  req.Amount,
+		}, req.Period, req.At)
+		if err != nil {
+			switch {
+			case errors.Is(err, budgetsservice.ErrOwnerNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, budgetsservice.ErrInvalidOwner),
+				errors.Is(err, budgetsservice.ErrInvalidPeriod),
+				errors.Is(err, budgetsservice.ErrInvalidAmount),
+				errors.Is(err, budgetsservice.ErrBudgetBelowUsage):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to allocate budget", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to allocate budget"))
+
+				return
+			}
+
+			log.Error("failed to allocate budget", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			ID:       id,
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package budgets
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"time"
+)
+
+type ResponseBudget struct {
+	ID          int       `json:"id"`
+	AdminID     int       `json:"admin_id,omitempty"`
+	GroupID     int       `json:"group_id,omitempty"`
+	PeriodID    int       `json:"period_id"`
+	StartsAt    time.Time `json:"starts_at"`
+	EndsAt      time.Time `json:"ends_at"`
+	Amount      float64   `json:"amount"`
+	Reserved    float64   `json:"reserved"`
+	Spent       float64   `json:"spent"`
+	Remaining   float64   `json:"remaining"`
+	AllocatedBy int       `json:"allocated_by"`
+}
+
+func ToResponse(budgets []model.Budget) []ResponseBudget {
+	budgetsRes := make([]ResponseBudget, 0, len(budgets))
+
+	for _, budget := range budgets {
+		budgetsRes = append(budgetsRes, ResponseBudget{
+			ID:          budget.ID,
+			AdminID:     budget.AdminID,
+			GroupID:     budget.GroupID,
+			PeriodID:    budget.PeriodID,
+			StartsAt:    budget.StartsAt,
+			EndsAt:      budget.EndsAt,
+			Amount:      budget.Amount,
+			Reserved:    budget.Reserved,
+			Spent:       budget.Spent,
+			Remaining:   budget.Amount - budget.Reserved - budget.Spent,
+			AllocatedBy: budget.AllocatedBy,
+		})
+	}
+
+	return budgetsRes
+}
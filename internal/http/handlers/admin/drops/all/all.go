@@ -0,0 +1,70 @@
+package all
+
+import (
+	"context"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/drops"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/request"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"log/slog"
+	"net/http"
+)
+
+type Response struct {
+	resp.Response
+	Campaigns []drops.ResponseCampaign `json:"campaigns"`
+}
+
+func New(ctx context.Context, log *slog.Logger, dropsService httpserver.Drops) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.drops.all.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		limit, err := request.QueryInt(r, "limit")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse limit", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		running := r.URL.Query().Get("running") == "true"
+
+		adminID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		campaigns, err := dropsService.GetCampaigns(ctx, adminID, running, limit)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get campaigns", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get campaigns"))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response:  resp.OK(),
+			Campaigns: drops.ToResponses(campaigns),
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package create
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/drops"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	dropsservice "github.com/k6mil6/hackathon-game-backend/internal/service/drops"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type Request struct {
+	Name            string    `json:"name"`
+	Budget          float64   `json:"budget"`
+	Reward          float64   `json:"reward"`
+	ClaimsPerDrop   int       `json:"claims_per_drop"`
+	Rate            float64   `json:"rate"`
+	LifetimeSeconds int       `json:"lifetime_seconds"`
+	StartsAt        time.Time `json:"starts_at,omitempty"`
+	EndsAt          time.Time `json:"ends_at"`
+}
+
+type Response struct {
+	resp.Response
+	Campaign drops.ResponseCampaign `json:"campaign"`
+}
+
+func New(ctx context.Context, log *slog.Logger, dropsService httpserver.Drops) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.drops.create.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		adminID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		campaign, err := dropsService.CreateCampaign(ctx, adminID, model.DropCampaign{
+			Name:          req.Name,
+			Budget:        req.Budget,
+			Reward:        req.Reward,
+			ClaimsPerDrop: req.ClaimsPerDrop,
+			Rate:          req.Rate,
+			Lifetime:      time.Duration(req.LifetimeSeconds) * time.Second,
+			StartsAt:      req.StartsAt,
+			EndsAt:        req.EndsAt,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, dropsservice.ErrNotEnoughPermission):
+				w.WriteHeader(http.StatusForbidden)
+			case errors.Is(err, dropsservice.ErrNameRequired),
+				errors.Is(err, dropsservice.ErrInvalidBudget),
+				errors.Is(err, dropsservice.ErrInvalidReward),
+				errors.Is(err, dropsservice.ErrInvalidClaims),
+				errors.Is(err, dropsservice.ErrInvalidRate),
+				errors.Is(err, dropsservice.ErrInvalidLifetime),
+				errors.Is(err, dropsservice.ErrInvalidPeriod),
+				errors.Is(err, dropsservice.ErrBudgetTooLow):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to create campaign", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to create campaign"))
+
+				return
+			}
+
+			log.Error("failed to create campaign", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Campaign: drops.ToResponse(campaign),
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package drops
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"time"
+)
+
+type ResponseCampaign struct {
+	ID              int        `json:"id"`
+	Name            string     `json:"name"`
+	Budget          float64    `json:"budget"`
+	Spent           float64    `json:"spent"`
+	Reward          float64    `json:"reward"`
+	ClaimsPerDrop   int        `json:"claims_per_drop"`
+	Rate            float64    `json:"rate"`
+	LifetimeSeconds int        `json:"lifetime_seconds"`
+	StartsAt        time.Time  `json:"starts_at"`
+	EndsAt          time.Time  `json:"ends_at"`
+	CreatedBy       int        `json:"created_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+	StoppedAt       *time.Time `json:"stopped_at,omitempty"`
+}
+
+func ToResponse(campaign model.DropCampaign) ResponseCampaign {
+	res := ResponseCampaign{
+		ID:              campaign.ID,
+		Name:            campaign.Name,
+		Budget:          campaign.Budget,
+		Spent:           campaign.Spent,
+		Reward:          campaign.Reward,
+		ClaimsPerDrop:   campaign.ClaimsPerDrop,
+		Rate:            campaign.Rate,
+		LifetimeSeconds: int(campaign.Lifetime / time.Second),
+		StartsAt:        campaign.StartsAt,
+		EndsAt:          campaign.EndsAt,
+		CreatedBy:       campaign.CreatedBy,
+		CreatedAt:       campaign.CreatedAt,
+	}
+
+	if !campaign.StoppedAt.IsZero() {
+		res.StoppedAt = &campaign.StoppedAt
+	}
+
+	return res
+}
+
+func ToResponses(campaigns []model.DropCampaign) []ResponseCampaign {
+	campaignsRes := make([]ResponseCampaign, 0, len(campaigns))
+
+	for _, campaign := range campaigns {
+		campaignsRes = append(campaignsRes, ToResponse(campaign))
+	}
+
+	return campaignsRes
+}
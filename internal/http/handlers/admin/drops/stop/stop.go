@@ -0,0 +1,95 @@
+package stop
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/drops"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	dropsservice "github.com/k6mil6/hackathon-game-backend/internal/service/drops"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Response struct {
+	resp.Response
+	Campaign drops.ResponseCampaign `json:"campaign"`
+}
+
+func New(ctx context.Context, log *slog.Logger, dropsService httpserver.Drops) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.drops.stop.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		campaignID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		adminID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		campaign, err := dropsService.StopCampaign(ctx, adminID, campaignID)
+		if err != nil {
+			switch {
+			case errors.Is(err, dropsservice.ErrCampaignNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, dropsservice.ErrCampaignStopped):
+				w.WriteHeader(http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to stop campaign", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to stop campaign"))
+
+				return
+			}
+
+			log.Error("failed to stop campaign", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Campaign: drops.ToResponse(campaign),
+		})
+	}
+}
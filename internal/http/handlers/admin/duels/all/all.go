@@ -0,0 +1,79 @@
+package all
+
+import (
+	"context"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/duels"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/request"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"log/slog"
+	"net/http"
+)
+
+type Response struct {
+	resp.Response
+	Duels []duels.ResponseDuel `json:"duels"`
+}
+
+func New(ctx context.Context, log *slog.Logger, duelsService httpserver.Duels) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.duels.all.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		statusID, err := request.QueryInt(r, "status_id")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse status_id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		limit, err := request.QueryInt(r, "limit")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse limit", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		adminID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		all, err := duelsService.GetAll(ctx, adminID, statusID, limit)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get duels", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get duels"))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Duels:    duels.ToResponses(all),
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package duels
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	duelsstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/duels"
+	"time"
+)
+
+var kinds = map[int]string{
+	duelsstorage.RaceKindID:  "race",
+	duelsstorage.WagerKindID: "wager",
+}
+
+var statuses = map[int]string{
+	duelsstorage.PendingStatusID:  "pending",
+	duelsstorage.ActiveStatusID:   "active",
+	duelsstorage.DeclinedStatusID: "declined",
+	duelsstorage.ResolvedStatusID: "resolved",
+	duelsstorage.RefundedStatusID: "refunded",
+}
+
+type ResponseDuel struct {
+	ID             int        `json:"id"`
+	Kind           string     `json:"kind"`
+	ChallengerID   int        `json:"challenger_id"`
+	ChallengerName string     `json:"challenger_name"`
+	OpponentID     int        `json:"opponent_id"`
+	OpponentName   string     `json:"opponent_name"`
+	Stake          float64    `json:"stake"`
+	TaskID         int        `json:"task_id,omitempty"`
+	Terms          string     `json:"terms,omitempty"`
+	Status         string     `json:"status"`
+	WinnerID       int        `json:"winner_id,omitempty"`
+	ResolvedBy     int        `json:"resolved_by,omitempty"`
+	Deadline       *time.Time `json:"deadline,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+}
+
+func ToResponse(duel model.Duel) ResponseDuel {
+	res := ResponseDuel{
+		ID:             duel.ID,
+		Kind:           kinds[duel.KindID],
+		ChallengerID:   duel.ChallengerID,
+		ChallengerName: duel.ChallengerName,
+		OpponentID:     duel.OpponentID,
+		OpponentName:   duel.OpponentName,
+		Stake:          duel.Stake,
+		TaskID:         duel.TaskID,
+		Terms:          duel.Terms,
+		Status:         statuses[duel.StatusID],
+		WinnerID:       duel.WinnerID,
+		ResolvedBy:     duel.ResolvedBy,
+		CreatedAt:      duel.CreatedAt,
+	}
+
+	if !duel.Deadline.IsZero() {
+		res.Deadline = &duel.Deadline
+	}
+
+	if !duel.ResolvedAt.IsZero() {
+		res.ResolvedAt = &duel.ResolvedAt
+	}
+
+	return res
+}
+
+func ToResponses(duels []model.Duel) []ResponseDuel {
+	duelsRes := make([]ResponseDuel, 0, len(duels))
+
+	for _, duel := range duels {
+		duelsRes = append(duelsRes, ToResponse(duel))
+	}
+
+	return duelsRes
+}
@@ -0,0 +1,113 @@
+package resolve
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/duels"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	duelsservice "github.com/k6mil6/hackathon-game-backend/internal/service/duels"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Request struct {
+	WinnerID int `json:"winner_id"`
+}
+
+type Response struct {
+	resp.Response
+	Duel duels.ResponseDuel `json:"duel"`
+}
+
+func New(ctx context.Context, log *slog.Logger, duelsService httpserver.Duels) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.duels.resolve.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		duelID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		adminID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		duel, err := duelsService.Resolve(ctx, duelID, adminID, req.WinnerID)
+		if err != nil {
+			switch {
+			case errors.Is(err, duelsservice.ErrDuelNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, duelsservice.ErrDuelNotActive):
+				w.WriteHeader(http.StatusConflict)
+			case errors.Is(err, duelsservice.ErrNotParticipant):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to resolve duel", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to resolve duel"))
+
+				return
+			}
+
+			log.Error("failed to resolve duel", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Duel:     duels.ToResponse(duel),
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package cancel
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/events"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	eventsservice "github.com/k6mil6/hackathon-game-backend/internal/service/events"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Response struct {
+	resp.Response
+	Event events.ResponseEvent `json:"event"`
+}
+
+func New(ctx context.Context, log *slog.Logger, eventsService httpserver.Events) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.events.cancel.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		eventID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		adminID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		event, err := eventsService.Cancel(ctx, adminID, eventID)
+		if err != nil {
+			switch {
+			case errors.Is(err, eventsservice.ErrEventNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, eventsservice.ErrEventCancelled),
+				errors.Is(err, eventsservice.ErrEventOver):
+				w.WriteHeader(http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to cancel event", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to cancel event"))
+
+				return
+			}
+
+			log.Error("failed to cancel event", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Event:    events.ToResponse(event),
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package code
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	eventsservice "github.com/k6mil6/hackathon-game-backend/internal/service/events"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type Request struct {
+	OneTime    bool `json:"one_time,omitempty"`
+	TTLSeconds int  `json:"ttl_seconds,omitempty"`
+}
+
+type Response struct {
+	resp.Response
+	Code      string    `json:"code"`
+	OneTime   bool      `json:"one_time"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func New(ctx context.Context, log *slog.Logger, eventsService httpserver.Events) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.events.code.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		eventID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		adminID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		code, err := eventsService.CreateCode(ctx, adminID, eventID, req.OneTime, time.Duration(req.TTLSeconds)*time.Second)
+		if err != nil {
+			switch {
+			case errors.Is(err, eventsservice.ErrNotEnoughPermission):
+				w.WriteHeader(http.StatusForbidden)
+			case errors.Is(err, eventsservice.ErrInvalidTTL):
+				w.WriteHeader(http.StatusBadRequest)
+			case errors.Is(err, eventsservice.ErrEventNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, eventsservice.ErrEventCancelled),
+				errors.Is(err, eventsservice.ErrEventOver):
+				w.WriteHeader(http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to create code", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to create code"))
+
+				return
+			}
+
+			log.Error("failed to create code", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response:  resp.OK(),
+			Code:      code.Token,
+			OneTime:   code.OneTime,
+			ExpiresAt: code.ExpiresAt,
+		})
+	}
+}
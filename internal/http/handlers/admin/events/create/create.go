@@ -0,0 +1,108 @@
+package create
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/events"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	eventsservice "github.com/k6mil6/hackathon-game-backend/internal/service/events"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type Request struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	Location    string    `json:"location,omitempty"`
+	StartsAt    time.Time `json:"starts_at"`
+	EndsAt      time.Time `json:"ends_at"`
+	Capacity    int       `json:"capacity,omitempty"`
+	Reward      float64   `json:"reward"`
+}
+
+type Response struct {
+	resp.Response
+	Event events.ResponseEvent `json:"event"`
+}
+
+func New(ctx context.Context, log *slog.Logger, eventsService httpserver.Events) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.events.create.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		adminID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		event, err := eventsService.Create(ctx, adminID, model.Event{
+			Title:       req.Title,
+			Description: req.Description,
+			Location:    req.Location,
+			StartsAt:    req.StartsAt,
+			EndsAt:      req.EndsAt,
+			Capacity:    req.Capacity,
+			Reward:      req.Reward,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, eventsservice.ErrNotEnoughPermission):
+				w.WriteHeader(http.StatusForbidden)
+			case errors.Is(err, eventsservice.ErrTitleRequired),
+				errors.Is(err, eventsservice.ErrInvalidPeriod),
+				errors.Is(err, eventsservice.ErrEventInPast),
+				errors.Is(err, eventsservice.ErrInvalidCapacity),
+				errors.Is(err, eventsservice.ErrInvalidReward),
+				errors.Is(err, eventsservice.ErrNoBudget):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to create event", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to create event"))
+
+				return
+			}
+
+			log.Error("failed to create event", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Event:    events.ToResponse(event),
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package events
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"time"
+)
+
+type ResponseEvent struct {
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Location    string     `json:"location,omitempty"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at"`
+	Capacity    int        `json:"capacity,omitempty"`
+	Reward      float64    `json:"reward"`
+	RSVPs       int        `json:"rsvps"`
+	CheckedIn   int        `json:"checked_in"`
+	CreatedBy   int        `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+}
+
+type ResponseRSVP struct {
+	UserID      int        `json:"user_id"`
+	Username    string     `json:"username"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CheckedInAt *time.Time `json:"checked_in_at,omitempty"`
+}
+
+func ToResponse(event model.Event) ResponseEvent {
+	res := ResponseEvent{
+		ID:          event.ID,
+		Title:       event.Title,
+		Description: event.Description,
+		Location:    event.Location,
+		StartsAt:    event.StartsAt,
+		EndsAt:      event.EndsAt,
+		Capacity:    event.Capacity,
+		Reward:      event.Reward,
+		RSVPs:       event.RSVPs,
+		CheckedIn:   event.CheckedIn,
+		CreatedBy:   event.CreatedBy,
+		CreatedAt:   event.CreatedAt,
+	}
+
+	if !event.CancelledAt.IsZero() {
+		res.CancelledAt = &event.CancelledAt
+	}
+
+	return res
+}
+
+func ToResponses(events []model.Event) []ResponseEvent {
+	eventsRes := make([]ResponseEvent, 0, len(events))
+
+	for _, event := range events {
+		eventsRes = append(eventsRes, ToResponse(event))
+	}
+
+	return eventsRes
+}
+
+func ToRSVPResponses(rsvps []model.EventRSVP) []ResponseRSVP {
+	rsvpsRes := make([]ResponseRSVP, 0, len(rsvps))
+
+	for _, rsvp := range rsvps {
+		res := ResponseRSVP{
+			UserID:    rsvp.UserID,
+			Username:  rsvp.Username,
+			CreatedAt: rsvp.CreatedAt,
+		}
+
+		if !rsvp.CheckedInAt.IsZero() {
+			checkedInAt := rsvp.CheckedInAt
+			res.CheckedInAt = &checkedInAt
+		}
+
+		rsvpsRes = append(rsvpsRes, res)
+	}
+
+	return rsvpsRes
+}
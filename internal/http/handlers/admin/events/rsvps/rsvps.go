@@ -0,0 +1,93 @@
+package rsvps
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/events"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	eventsservice "github.com/k6mil6/hackathon-game-backend/internal/service/events"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Response struct {
+	resp.Response
+	RSVPs []events.ResponseRSVP `json:"rsvps"`
+}
+
+func New(ctx context.Context, log *slog.Logger, eventsService httpserver.Events) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.events.rsvps.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		eventID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		adminID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		rsvps, err := eventsService.GetRSVPs(ctx, adminID, eventID)
+		if err != nil {
+			switch {
+			case errors.Is(err, eventsservice.ErrEventNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to get RSVPs", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to get RSVPs"))
+
+				return
+			}
+
+			log.Error("failed to get RSVPs", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			RSVPs:    events.ToRSVPResponses(rsvps),
+		})
+	}
+}
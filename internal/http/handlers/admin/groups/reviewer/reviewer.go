@@ -0,0 +1,102 @@
+package reviewer
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	reviewsservice "github.com/k6mil6/hackathon-game-backend/internal/service/reviews"
+	"log/slog"
+	"net/http"
+)
+
+type Request struct {
+	GroupID int `json:"group_id"`
+	AdminID int `json:"admin_id"`
+}
+
+type Response struct {
+	resp.Response
+}
+
+func New(ctx context.Context, log *slog.Logger, reviews httpserver.Reviews) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.groups.reviewer.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		if req.GroupID == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("group_id is required")
+
+			render.JSON(w, r, resp.Error("group_id is required"))
+
+			return
+		}
+
+		if req.AdminID == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("admin_id is required")
+
+			render.JSON(w, r, resp.Error("admin_id is required"))
+
+			return
+		}
+
+		adminID, err := identity.GetAdminID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		if err := reviews.AssignGroupReviewer(ctx, req.GroupID, req.AdminID, adminID); err != nil {
+			switch {
+			case errors.Is(err, reviewsservice.ErrAdminNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, reviewsservice.ErrInvalidGroup):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to assign reviewer", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to assign reviewer"))
+
+				return
+			}
+
+			log.Error("failed to assign reviewer", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{resp.OK()})
+	}
+}
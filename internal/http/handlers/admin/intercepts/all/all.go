@@ -0,0 +1,78 @@
+package all
+
+import (
+	"context"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/intercepts"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/request"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"log/slog"
+	"net/http"
+)
+
+type Response struct {
+	resp.Response
+	Intercepts []intercepts.ResponseIntercept `json:"intercepts"`
+}
+
+func New(ctx context.Context, log *slog.Logger, interceptsService httpserver.Intercepts) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.intercepts.all.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		userID, err := request.QueryInt(r, "user_id")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse user_id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		taskID, err := request.QueryInt(r, "task_id")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse task_id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		limit, err := request.QueryInt(r, "limit")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse limit", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		list, err := interceptsService.GetAll(ctx, userID, taskID, limit)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get intercepts", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get intercepts"))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response:   resp.OK(),
+			Intercepts: intercepts.ToResponseList(list),
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package create
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/quests"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	questslib "github.com/k6mil6/hackathon-game-backend/internal/lib/quests"
+	questsservice "github.com/k6mil6/hackathon-game-backend/internal/service/quests"
+	"log/slog"
+	"net/http"
+)
+
+type Response struct {
+	resp.Response
+	ID int `json:"id"`
+}
+
+func New(ctx context.Context, log *slog.Logger, questsService httpserver.Quests) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.quests.create.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		var req quests.Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		adminID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		id, err := questsService.Create(ctx, adminID, req.ToModel(), req.Ordered)
+		if err != nil {
+			switch {
+			case errors.Is(err, questsservice.ErrInvalidName),
+				errors.Is(err, questsservice.ErrInvalidStepName),
+				errors.Is(err, questsservice.ErrInvalidAmount),
+				errors.Is(err, questsservice.ErrInvalidBonus),
+				errors.Is(err, questslib.ErrNoSteps),
+				errors.Is(err, questslib.ErrUnknownStep),
+				errors.Is(err, questslib.ErrSelfPrerequisite),
+				errors.Is(err, questslib.ErrCycle):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to create quest", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to create quest"))
+
+				return
+			}
+
+			log.Error("failed to create quest", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			ID:       id,
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package quests
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"time"
+)
+
+// Request describes a quest being authored. Steps are numbered from 1 in the order they are given,
+// requires refers to those numbers and is ignored for ordered quests.
+type Request struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Bonus       float64       `json:"bonus,omitempty"`
+	Ordered     bool          `json:"ordered,omitempty"`
+	Steps       []RequestStep `json:"steps"`
+}
+
+type RequestStep struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Amount      float64 `json:"amount"`
+	Requires    []int   `json:"requires,omitempty"`
+}
+
+func (r Request) ToModel() model.Quest {
+	steps := make([]model.QuestStep, 0, len(r.Steps))
+	for _, step := range r.Steps {
+		steps = append(steps, model.QuestStep{
+			Name:        step.Name,
+			Description: step.Description,
+			Amount:      step.Amount,
+			Requires:    step.Requires,
+		})
+	}
+
+	return model.Quest{
+		Name:        r.Name,
+		Description: r.Description,
+		Bonus:       r.Bonus,
+		Steps:       steps,
+	}
+}
+
+type ResponseQuest struct {
+	ID          int            `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Bonus       float64        `json:"bonus"`
+	CreatedBy   int            `json:"created_by"`
+	PublishedAt *time.Time     `json:"published_at,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	Steps       []ResponseStep `json:"steps"`
+}
+
+type ResponseStep struct {
+	Position    int     `json:"position"`
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Amount      float64 `json:"amount"`
+	Requires    []int   `json:"requires,omitempty"`
+}
+
+func ToResponse(quest model.Quest) ResponseQuest {
+	res := ResponseQuest{
+		ID:          quest.ID,
+		Name:        quest.Name,
+		Description: quest.Description,
+		Bonus:       quest.Bonus,
+		CreatedBy:   quest.CreatedBy,
+		CreatedAt:   quest.CreatedAt,
+		Steps:       make([]ResponseStep, 0, len(quest.Steps)),
+	}
+
+	if !quest.PublishedAt.IsZero() {
+		res.PublishedAt = &quest.PublishedAt
+	}
+
+	for _, step := range quest.Steps {
+		res.Steps = append(res.Steps, ResponseStep{
+			Position:    step.Position,
+			Name:        step.Name,
+			Description: step.Description,
+			Amount:      step.Amount,
+			Requires:    step.Requires,
+		})
+	}
+
+	return res
+}
+
+func ToResponses(quests []model.Quest) []ResponseQuest {
+	questsRes := make([]ResponseQuest, 0, len(quests))
+
+	for _, quest := range quests {
+		questsRes = append(questsRes, ToResponse(quest))
+	}
+
+	return questsRes
+}
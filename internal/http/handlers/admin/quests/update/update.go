@@ -0,0 +1,113 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/quests"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	questslib "github.com/k6mil6/hackathon-game-backend/internal/lib/quests"
+	questsservice "github.com/k6mil6/hackathon-game-backend/internal/service/quests"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+func New(ctx context.Context, log *slog.Logger, questsService httpserver.Quests) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.quests.update.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		questID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		var req quests.Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		adminID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		quest := req.ToModel()
+		quest.ID = questID
+
+		if err := questsService.Update(ctx, adminID, quest, req.Ordered); err != nil {
+			switch {
+			case errors.Is(err, questsservice.ErrNotEnoughPermission):
+				w.WriteHeader(http.StatusForbidden)
+			case errors.Is(err, questsservice.ErrQuestNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, questsservice.ErrAlreadyPublished):
+				w.WriteHeader(http.StatusConflict)
+			case errors.Is(err, questsservice.ErrInvalidName),
+				errors.Is(err, questsservice.ErrInvalidStepName),
+				errors.Is(err, questsservice.ErrInvalidAmount),
+				errors.Is(err, questsservice.ErrInvalidBonus),
+				errors.Is(err, questslib.ErrNoSteps),
+				errors.Is(err, questslib.ErrUnknownStep),
+				errors.Is(err, questslib.ErrSelfPrerequisite),
+				errors.Is(err, questslib.ErrCycle):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to update quest", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to update quest"))
+
+				return
+			}
+
+			log.Error("failed to update quest", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, resp.OK())
+	}
+}
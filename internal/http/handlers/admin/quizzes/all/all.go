@@ -0,0 +1,92 @@
+package all
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/quizzes"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/request"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	quizzesservice "github.com/k6mil6/hackathon-game-backend/internal/service/quizzes"
+	"log/slog"
+	"net/http"
+)
+
+type Response struct {
+	resp.Response
+	Quizzes []quizzes.ResponseQuiz `json:"quizzes"`
+}
+
+func New(ctx context.Context, log *slog.Logger, quizzesService httpserver.Quizzes) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.quizzes.all.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		limit, err := request.QueryInt(r, "limit")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse limit", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		offset, err := request.QueryInt(r, "offset")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse offset", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		adminID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		all, err := quizzesService.GetAll(ctx, adminID, limit, offset)
+		if err != nil {
+			switch {
+			case errors.Is(err, quizzesservice.ErrInvalidOffset):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to get quizzes", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to get quizzes"))
+
+				return
+			}
+
+			log.Error("failed to get quizzes", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Quizzes:  quizzes.ToResponses(all),
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package close
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/quizzes"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	quizzesservice "github.com/k6mil6/hackathon-game-backend/internal/service/quizzes"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Response struct {
+	resp.Response
+	Quiz quizzes.ResponseQuiz `json:"quiz"`
+}
+
+func New(ctx context.Context, log *slog.Logger, quizzesService httpserver.Quizzes) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.quizzes.close.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		quizID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		adminID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		quiz, err := quizzesService.Close(ctx, adminID, quizID)
+		if err != nil {
+			switch {
+			case errors.Is(err, quizzesservice.ErrQuizNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, quizzesservice.ErrQuizClosed):
+				w.WriteHeader(http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to close quiz", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to close quiz"))
+
+				return
+			}
+
+			log.Error("failed to close quiz", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Quiz:     quizzes.ToResponse(quiz),
+		})
+	}
+}
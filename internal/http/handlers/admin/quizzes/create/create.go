@@ -0,0 +1,101 @@
+package create
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/quizzes"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	quizzeslib "github.com/k6mil6/hackathon-game-backend/internal/lib/quizzes"
+	quizzesservice "github.com/k6mil6/hackathon-game-backend/internal/service/quizzes"
+	"log/slog"
+	"net/http"
+)
+
+type Response struct {
+	resp.Response
+	Quiz quizzes.ResponseQuiz `json:"quiz"`
+}
+
+func New(ctx context.Context, log *slog.Logger, quizzesService httpserver.Quizzes) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.quizzes.create.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		var req quizzes.Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		adminID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		quiz, err := quizzesService.Create(ctx, adminID, req.ToModel())
+		if err != nil {
+			switch {
+			case errors.Is(err, quizzesservice.ErrNotEnoughPermission):
+				w.WriteHeader(http.StatusForbidden)
+			case errors.Is(err, quizzesservice.ErrNameRequired),
+				errors.Is(err, quizzesservice.ErrNoBudget),
+				errors.Is(err, quizzesservice.ErrUnknownType),
+				errors.Is(err, quizzesservice.ErrInvalidAmount),
+				errors.Is(err, quizzesservice.ErrInvalidPassScore),
+				errors.Is(err, quizzesservice.ErrClosesInPast),
+				errors.Is(err, quizzesservice.ErrTooManyQuestions),
+				errors.Is(err, quizzesservice.ErrTooManyOptions),
+				errors.Is(err, quizzesservice.ErrTextTooLong),
+				errors.Is(err, quizzeslib.ErrNoQuestions),
+				errors.Is(err, quizzeslib.ErrQuestionText),
+				errors.Is(err, quizzeslib.ErrUnknownKind),
+				errors.Is(err, quizzeslib.ErrTooFewOptions),
+				errors.Is(err, quizzeslib.ErrEmptyOption),
+				errors.Is(err, quizzeslib.ErrCorrectOption),
+				errors.Is(err, quizzeslib.ErrCorrectNumber),
+				errors.Is(err, quizzeslib.ErrCorrectText),
+				errors.Is(err, quizzeslib.ErrInvalidPoints):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to create quiz", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to create quiz"))
+
+				return
+			}
+
+			log.Error("failed to create quiz", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Quiz:     quizzes.ToResponse(quiz),
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package quizzes
+
+import (
+	quizzeslib "github.com/k6mil6/hackathon-game-backend/internal/lib/quizzes"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"time"
+)
+
+// Request describes a quiz or a poll being authored. Questions are numbered from 1 in the order they are given,
+// correct_options refers to option indexes from 0. Polls ignore pass_score and the correct answers.
+type Request struct {
+	TypeID      int               `json:"type_id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Amount      float64           `json:"amount"`
+	PassScore   int               `json:"pass_score,omitempty"`
+	ClosesAt    *time.Time        `json:"closes_at,omitempty"`
+	Questions   []RequestQuestion `json:"questions"`
+}
+
+type RequestQuestion struct {
+	KindID         int      `json:"kind_id"`
+	Text           string   `json:"text"`
+	Options        []string `json:"options,omitempty"`
+	CorrectOptions []int    `json:"correct_options,omitempty"`
+	CorrectNumber  *float64 `json:"correct_number,omitempty"`
+	Tolerance      float64  `json:"tolerance,omitempty"`
+	CorrectTexts   []string `json:"correct_texts,omitempty"`
+	Points         int      `json:"points,omitempty"`
+}
+
+func (r Request) ToModel() model.Quiz {
+	questions := make([]model.QuizQuestion, 0, len(r.Questions))
+	for _, question := range r.Questions {
+		questions = append(questions, model.QuizQuestion{
+			KindID:         question.KindID,
+			Text:           question.Text,
+			Options:        question.Options,
+			CorrectOptions: question.CorrectOptions,
+			CorrectNumber:  question.CorrectNumber,
+			Tolerance:      question.Tolerance,
+			CorrectTexts:   question.CorrectTexts,
+			Points:         question.Points,
+		})
+	}
+
+	quiz := model.Quiz{
+		TypeID:      r.TypeID,
+		Name:        r.Name,
+		Description: r.Description,
+		Amount:      r.Amount,
+		PassScore:   r.PassScore,
+		Questions:   questions,
+	}
+
+	if r.ClosesAt != nil {
+		quiz.ClosesAt = *r.ClosesAt
+	}
+
+	return quiz
+}
+
+type ResponseQuiz struct {
+	ID          int                `json:"id"`
+	TypeID      int                `json:"type_id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Amount      float64            `json:"amount"`
+	PassScore   int                `json:"pass_score,omitempty"`
+	ClosesAt    *time.Time         `json:"closes_at,omitempty"`
+	Submissions int                `json:"submissions"`
+	CreatedBy   int                `json:"created_by"`
+	CreatedAt   time.Time          `json:"created_at"`
+	ClosedAt    *time.Time         `json:"closed_at,omitempty"`
+	Questions   []ResponseQuestion `json:"questions,omitempty"`
+}
+
+type ResponseQuestion struct {
+	Position       int      `json:"position"`
+	KindID         int      `json:"kind_id"`
+	Text           string   `json:"text"`
+	Options        []string `json:"options,omitempty"`
+	CorrectOptions []int    `json:"correct_options,omitempty"`
+	CorrectNumber  *float64 `json:"correct_number,omitempty"`
+	Tolerance      float64  `json:"tolerance,omitempty"`
+	CorrectTexts   []string `json:"correct_texts,omitempty"`
+	Points         int      `json:"points"`
+}
+
+type ResponseResult struct {
+	Position     int                 `json:"position"`
+	KindID       int                 `json:"kind_id"`
+	Text         string              `json:"text"`
+	Answers      int                 `json:"answers"`
+	Options      []string            `json:"options,omitempty"`
+	OptionCounts []int               `json:"option_counts,omitempty"`
+	Average      *float64            `json:"average,omitempty"`
+	Min          *float64            `json:"min,omitempty"`
+	Max          *float64            `json:"max,omitempty"`
+	Texts        []ResponseTextCount `json:"texts,omitempty"`
+}
+
+type ResponseTextCount struct {
+	Text  string `json:"text"`
+	Count int    `json:"count"`
+}
+
+func ToResponse(quiz model.Quiz) ResponseQuiz {
+	res := ResponseQuiz{
+		ID:          quiz.ID,
+		TypeID:      quiz.TypeID,
+		Name:        quiz.Name,
+		Description: quiz.Description,
+		Amount:      quiz.Amount,
+		PassScore:   quiz.PassScore,
+		Submissions: quiz.Submissions,
+		CreatedBy:   quiz.CreatedBy,
+		CreatedAt:   quiz.CreatedAt,
+	}
+
+	if !quiz.ClosesAt.IsZero() {
+		res.ClosesAt = &quiz.ClosesAt
+	}
+
+	if !quiz.ClosedAt.IsZero() {
+		res.ClosedAt = &quiz.ClosedAt
+	}
+
+	for _, question := range quiz.Questions {
+		res.Questions = append(res.Questions, ResponseQuestion{
+			Position:       question.Position,
+			KindID:         question.KindID,
+			Text:           question.Text,
+			Options:        question.Options,
+			CorrectOptions: question.CorrectOptions,
+			CorrectNumber:  question.CorrectNumber,
+			Tolerance:      question.Tolerance,
+			CorrectTexts:   question.CorrectTexts,
+			Points:         question.Points,
+		})
+	}
+
+	return res
+}
+
+func ToResponses(quizzes []model.Quiz) []ResponseQuiz {
+	quizzesRes := make([]ResponseQuiz, 0, len(quizzes))
+
+	for _, quiz := range quizzes {
+		quizzesRes = append(quizzesRes, ToResponse(quiz))
+	}
+
+	return quizzesRes
+}
+
+// ToResultResponses leaves the numeric summary out of the questions that are not numeric.
+func ToResultResponses(results []model.QuizQuestionResult) []ResponseResult {
+	resultsRes := make([]ResponseResult, 0, len(results))
+
+	for _, result := range results {
+		res := ResponseResult{
+			Position:     result.Position,
+			KindID:       result.KindID,
+			Text:         result.Text,
+			Answers:      result.Answers,
+			Options:      result.Options,
+			OptionCounts: result.OptionCounts,
+		}
+
+		if result.KindID == quizzeslib.NumericKindID && result.Answers > 0 {
+			average, lowest, highest := result.Average, result.Min, result.Max
+			res.Average, res.Min, res.Max = &average, &lowest, &highest
+		}
+
+		for _, text := range result.Texts {
+			res.Texts = append(res.Texts, ResponseTextCount{
+				Text:  text.Text,
+				Count: text.Count,
+			})
+		}
+
+		resultsRes = append(resultsRes, res)
+	}
+
+	return resultsRes
+}
@@ -0,0 +1,104 @@
+package all
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/raffles"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/request"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	rafflesservice "github.com/k6mil6/hackathon-game-backend/internal/service/raffles"
+	"log/slog"
+	"net/http"
+)
+
+type Response struct {
+	resp.Response
+	Raffles []raffles.ResponseRaffle `json:"raffles"`
+}
+
+func New(ctx context.Context, log *slog.Logger, rafflesService httpserver.Raffles) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.raffles.all.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		status, err := request.QueryInt(r, "status")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse status", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		limit, err := request.QueryInt(r, "limit")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse limit", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		offset, err := request.QueryInt(r, "offset")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse offset", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		adminID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		all, err := rafflesService.GetAllForAdmin(ctx, adminID, status, limit, offset)
+		if err != nil {
+			switch {
+			case errors.Is(err, rafflesservice.ErrInvalidStatus),
+				errors.Is(err, rafflesservice.ErrInvalidOffset):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to get raffles", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to get raffles"))
+
+				return
+			}
+
+			log.Error("failed to get raffles", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Raffles:  raffles.ToResponses(all),
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package create
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/raffles"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	rafflesservice "github.com/k6mil6/hackathon-game-backend/internal/service/raffles"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type Request struct {
+	Name              string    `json:"name"`
+	Description       string    `json:"description,omitempty"`
+	TicketPrice       float64   `json:"ticket_price"`
+	MaxTicketsPerUser int       `json:"max_tickets_per_user"`
+	ItemID            int       `json:"item_id,omitempty"`
+	PrizeAmount       float64   `json:"prize_amount,omitempty"`
+	DrawAt            time.Time `json:"draw_at"`
+}
+
+type Response struct {
+	resp.Response
+	Raffle raffles.ResponseRaffle `json:"raffle"`
+}
+
+func New(ctx context.Context, log *slog.Logger, rafflesService httpserver.Raffles) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.raffles.create.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		adminID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		raffle, err := rafflesService.Create(ctx, adminID, model.Raffle{
+			Name:              req.Name,
+			Description:       req.Description,
+			TicketPrice:       req.TicketPrice,
+			MaxTicketsPerUser: req.MaxTicketsPerUser,
+			ItemID:            req.ItemID,
+			PrizeAmount:       req.PrizeAmount,
+			DrawAt:            req.DrawAt,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, rafflesservice.ErrNotEnoughPermission):
+				w.WriteHeader(http.StatusForbidden)
+			case errors.Is(err, rafflesservice.ErrNameRequired),
+				errors.Is(err, rafflesservice.ErrInvalidPrice),
+				errors.Is(err, rafflesservice.ErrInvalidTicketLimit),
+				errors.Is(err, rafflesservice.ErrPrizeRequired),
+				errors.Is(err, rafflesservice.ErrInvalidPrizeAmount),
+				errors.Is(err, rafflesservice.ErrInvalidDrawTime):
+				w.WriteHeader(http.StatusBadRequest)
+			case errors.Is(err, rafflesservice.ErrItemNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, rafflesservice.ErrItemSoldOut):
+				w.WriteHeader(http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to create raffle", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to create raffle"))
+
+				return
+			}
+
+			log.Error("failed to create raffle", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Raffle:   raffles.ToResponse(raffle),
+		})
+	}
+}
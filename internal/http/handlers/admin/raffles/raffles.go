@@ -0,0 +1,69 @@
+package raffles
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"time"
+)
+
+type ResponseRaffle struct {
+	ID                int        `json:"id"`
+	Name              string     `json:"name"`
+	Description       string     `json:"description,omitempty"`
+	TicketPrice       float64    `json:"ticket_price"`
+	MaxTicketsPerUser int        `json:"max_tickets_per_user"`
+	ItemID            int        `json:"item_id,omitempty"`
+	ItemName          string     `json:"item_name,omitempty"`
+	PrizeAmount       float64    `json:"prize_amount,omitempty"`
+	StatusID          int        `json:"status_id"`
+	Commitment        string     `json:"commitment"`
+	Seed              string     `json:"seed,omitempty"`
+	DrawAt            time.Time  `json:"draw_at"`
+	TicketsSold       int        `json:"tickets_sold"`
+	WinningNumber     int        `json:"winning_number,omitempty"`
+	WinnerID          int        `json:"winner_id,omitempty"`
+	WinnerName        string     `json:"winner_name,omitempty"`
+	PurchaseID        int        `json:"purchase_id,omitempty"`
+	CreatedBy         int        `json:"created_by"`
+	CreatedAt         time.Time  `json:"created_at"`
+	ClosedAt          *time.Time `json:"closed_at,omitempty"`
+}
+
+func ToResponse(raffle model.Raffle) ResponseRaffle {
+	res := ResponseRaffle{
+		ID:                raffle.ID,
+		Name:              raffle.Name,
+		Description:       raffle.Description,
+		TicketPrice:       raffle.TicketPrice,
+		MaxTicketsPerUser: raffle.MaxTicketsPerUser,
+		ItemID:            raffle.ItemID,
+		ItemName:          raffle.ItemName,
+		PrizeAmount:       raffle.PrizeAmount,
+		StatusID:          raffle.StatusID,
+		Commitment:        raffle.Commitment,
+		Seed:              raffle.Seed,
+		DrawAt:            raffle.DrawAt,
+		TicketsSold:       raffle.TicketsSold,
+		WinningNumber:     raffle.WinningNumber,
+		WinnerID:          raffle.WinnerID,
+		WinnerName:        raffle.WinnerName,
+		PurchaseID:        raffle.PurchaseID,
+		CreatedBy:         raffle.CreatedBy,
+		CreatedAt:         raffle.CreatedAt,
+	}
+
+	if !raffle.ClosedAt.IsZero() {
+		res.ClosedAt = &raffle.ClosedAt
+	}
+
+	return res
+}
+
+func ToResponses(raffles []model.Raffle) []ResponseRaffle {
+	rafflesRes := make([]ResponseRaffle, 0, len(raffles))
+
+	for _, raffle := range raffles {
+		rafflesRes = append(rafflesRes, ToResponse(raffle))
+	}
+
+	return rafflesRes
+}
@@ -2,10 +2,12 @@ package register
 
 import (
 	"context"
+	"errors"
 	"github.com/go-chi/render"
 	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
 	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
 	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	authservice "github.com/k6mil6/hackathon-game-backend/internal/service/auth"
 	"log/slog"
 	"net/http"
 )
@@ -83,6 +85,16 @@ func New(ctx context.Context, log *slog.Logger, auth httpserver.Auth) http.Handl
 
 		id, err := auth.RegisterAdmin(ctx, req.Username, req.Password, registrantID, roleID)
 		if err != nil {
+			if errors.Is(err, authservice.ErrNotEnoughPermission) {
+				w.WriteHeader(http.StatusForbidden)
+
+				log.Error("not enough permission", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("not enough permission"))
+
+				return
+			}
+
 			w.WriteHeader(http.StatusInternalServerError)
 
 			log.Error("error registering admin:", err)
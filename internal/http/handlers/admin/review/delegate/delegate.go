@@ -0,0 +1,109 @@
+package delegate
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	reviewsservice "github.com/k6mil6/hackathon-game-backend/internal/service/reviews"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type Request struct {
+	ToAdminID int       `json:"to_admin_id"`
+	StartsAt  time.Time `json:"starts_at,omitempty"`
+	EndsAt    time.Time `json:"ends_at"`
+}
+
+type Response struct {
+	resp.Response
+	ID int `json:"id"`
+}
+
+func New(ctx context.Context, log *slog.Logger, reviews httpserver.Reviews) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.review.delegate.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		if req.ToAdminID == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("to_admin_id is required")
+
+			render.JSON(w, r, resp.Error("to_admin_id is required"))
+
+			return
+		}
+
+		if req.EndsAt.IsZero() {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("ends_at is required")
+
+			render.JSON(w, r, resp.Error("ends_at is required"))
+
+			return
+		}
+
+		adminID, err := identity.GetAdminID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		id, err := reviews.Delegate(ctx, adminID, req.ToAdminID, req.StartsAt, req.EndsAt)
+		if err != nil {
+			switch {
+			case errors.Is(err, reviewsservice.ErrAdminNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, reviewsservice.ErrInvalidPeriod), errors.Is(err, reviewsservice.ErrSelfDelegation):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to delegate", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to delegate"))
+
+				return
+			}
+
+			log.Error("failed to delegate", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			ID:       id,
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package delegations
+
+import (
+	"context"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type Response struct {
+	resp.Response
+	Delegations []ResponseDelegation `json:"delegations"`
+}
+
+type ResponseDelegation struct {
+	ID          int        `json:"id"`
+	FromAdminID int        `json:"from_admin_id"`
+	ToAdminID   int        `json:"to_admin_id"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+}
+
+func New(ctx context.Context, log *slog.Logger, reviews httpserver.Reviews) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.review.delegations.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		adminID, err := identity.GetAdminID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		delegations, err := reviews.GetDelegations(ctx, adminID)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get delegations", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get delegations"))
+
+			return
+		}
+
+		delegationsRes := make([]ResponseDelegation, 0, len(delegations))
+
+		for _, delegation := range delegations {
+			var revokedAt *time.Time
+			if !delegation.RevokedAt.IsZero() {
+				revokedAt = &delegation.RevokedAt
+			}
+
+			delegationsRes = append(delegationsRes, ResponseDelegation{
+				ID:          delegation.ID,
+				FromAdminID: delegation.FromAdminID,
+				ToAdminID:   delegation.ToAdminID,
+				StartsAt:    delegation.StartsAt,
+				EndsAt:      delegation.EndsAt,
+				RevokedAt:   revokedAt,
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response:    resp.OK(),
+			Delegations: delegationsRes,
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package queue
+
+import (
+	"context"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/request"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type Response struct {
+	resp.Response
+	Tasks []ResponseTask `json:"tasks"`
+}
+
+type ResponseTask struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Amount      float64   `json:"amount"`
+	CreatedBy   int       `json:"created_by"`
+	ForGroupID  int       `json:"for_group_id"`
+	UserID      int       `json:"user_id,omitempty"`
+	SubmittedAt time.Time `json:"submitted_at"`
+	AgeSeconds  int64     `json:"age_seconds"`
+	SLAStatus   string    `json:"sla_status"`
+}
+
+func New(ctx context.Context, log *slog.Logger, reviews httpserver.Reviews) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.review.queue.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		adminID, err := identity.GetAdminID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		limit, err := request.QueryInt(r, "limit")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse limit", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		items, err := reviews.GetQueue(ctx, adminID, limit)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get review queue", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get review queue"))
+
+			return
+		}
+
+		tasksRes := make([]ResponseTask, 0, len(items))
+
+		for _, item := range items {
+			tasksRes = append(tasksRes, ResponseTask{
+				ID:          item.Task.ID,
+				Name:        item.Task.Name,
+				Amount:      item.Task.Amount,
+				CreatedBy:   item.Task.CreatedBy,
+				ForGroupID:  item.Task.ForGroupID,
+				UserID:      item.Task.UserID,
+				SubmittedAt: item.Task.SubmittedAt,
+				AgeSeconds:  int64(item.Age.Seconds()),
+				SLAStatus:   item.SLAStatus,
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Tasks:    tasksRes,
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package revoke
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	reviewsservice "github.com/k6mil6/hackathon-game-backend/internal/service/reviews"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Response struct {
+	resp.Response
+}
+
+func New(ctx context.Context, log *slog.Logger, reviews httpserver.Reviews) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.review.revoke.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		delegationID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		adminID, err := identity.GetAdminID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		if err := reviews.RevokeDelegation(ctx, delegationID, adminID); err != nil {
+			if errors.Is(err, reviewsservice.ErrDelegationNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+
+				log.Error("delegation not found", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("delegation not found"))
+
+				return
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to revoke delegation", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to revoke delegation"))
+
+			return
+		}
+
+		render.JSON(w, r, Response{resp.OK()})
+	}
+}
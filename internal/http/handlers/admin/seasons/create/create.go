@@ -0,0 +1,95 @@
+package create
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	seasonsservice "github.com/k6mil6/hackathon-game-backend/internal/service/seasons"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type Request struct {
+	Name     string    `json:"name"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+type Response struct {
+	resp.Response
+	ID int `json:"id"`
+}
+
+func New(ctx context.Context, log *slog.Logger, seasons httpserver.Seasons) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.seasons.create.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		adminID, err := identity.GetAdminID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		id, err := seasons.Create(ctx, adminID, model.Season{
+			Name:     req.Name,
+			StartsAt: req.StartsAt,
+			EndsAt:   req.EndsAt,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, seasonsservice.ErrSeasonOverlap):
+				w.WriteHeader(http.StatusConflict)
+			case errors.Is(err, seasonsservice.ErrInvalidName),
+				errors.Is(err, seasonsservice.ErrInvalidPeriod):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to create season", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to create season"))
+
+				return
+			}
+
+			log.Error("failed to create season", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			ID:       id,
+		})
+	}
+}
@@ -8,7 +8,6 @@ import (
 	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
 	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
 	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
-	"github.com/k6mil6/hackathon-game-backend/internal/model"
 	taskservice "github.com/k6mil6/hackathon-game-backend/internal/service/tasks"
 	"log/slog"
 	"net/http"
@@ -17,9 +16,10 @@ import (
 
 type Response struct {
 	resp.Response
+	ApprovalRequired bool `json:"approval_required,omitempty"`
 }
 
-func New(ctx context.Context, log *slog.Logger, tasks httpserver.Tasks, transactions httpserver.Transactions) http.HandlerFunc {
+func New(ctx context.Context, log *slog.Logger, tasks httpserver.Tasks) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		op := "handlers.admin.tasks.accept.New"
 
@@ -51,7 +51,7 @@ func New(ctx context.Context, log *slog.Logger, tasks httpserver.Tasks, transact
 			return
 		}
 
-		adminID, err := identity.GetID(r.Context())
+		adminID, err := identity.GetAdminID(r.Context())
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 
@@ -62,8 +62,33 @@ func New(ctx context.Context, log *slog.Logger, tasks httpserver.Tasks, transact
 			return
 		}
 
-		task, err := tasks.MarkAsCompleted(ctx, taskID, adminID)
+		_, err = tasks.MarkAsCompleted(ctx, taskID, adminID)
 		if err != nil {
+			if errors.Is(err, taskservice.ErrApprovalRequired) {
+				w.WriteHeader(http.StatusAccepted)
+
+				log.Info("task acceptance is waiting for approval")
+
+				render.JSON(w, r, Response{
+					Response:         resp.OK(),
+					ApprovalRequired: true,
+				})
+
+				return
+			}
+
+			if errors.Is(err, taskservice.ErrPendingApproval) ||
+				errors.Is(err, taskservice.ErrTaskNotWaiting) ||
+				errors.Is(err, taskservice.ErrBudgetExceeded) {
+				w.WriteHeader(http.StatusConflict)
+
+				log.Error("task can not be accepted", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error(err.Error()))
+
+				return
+			}
+
 			if errors.Is(err, taskservice.ErrNotEnoughPermission) {
 				w.WriteHeader(http.StatusBadRequest)
 
@@ -83,22 +108,6 @@ func New(ctx context.Context, log *slog.Logger, tasks httpserver.Tasks, transact
 			return
 		}
 
-		err = transactions.AddAdminTransaction(ctx, &model.Transaction{
-			Amount:     task.Amount,
-			SenderID:   adminID,
-			ReceiverID: task.UserID,
-		})
-
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-
-			log.Error("failed to add admin transaction", slog.String("error", err.Error()))
-
-			render.JSON(w, r, resp.Error("failed to add admin transaction"))
-
-			return
-		}
-
 		render.JSON(w, r, resp.OK())
 	}
 }
@@ -2,10 +2,14 @@ package all
 
 import (
 	"context"
+	"errors"
 	"github.com/go-chi/render"
 	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
 	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/request"
 	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	taskservice "github.com/k6mil6/hackathon-game-backend/internal/service/tasks"
 	"log/slog"
 	"net/http"
 	"time"
@@ -13,17 +17,25 @@ import (
 
 type Response struct {
 	resp.Response
-	Tasks []TaskResponse `json:"tasks"`
+	Tasks      []TaskResponse `json:"tasks"`
+	Total      int            `json:"total"`
+	NextCursor string         `json:"next_cursor,omitempty"`
 }
 
 type TaskResponse struct {
-	ID         int       `json:"id"`
-	Name       string    `json:"name"`
-	StatusID   int       `json:"status_id"`
-	Amount     float64   `json:"amount"`
-	CreatedAt  time.Time `json:"created_at"`
-	ForGroupID int       `json:"for_group_id"`
-	UserID     int       `json:"user_id,omitempty"`
+	ID           int        `json:"id"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description,omitempty"`
+	StatusID     int        `json:"status_id"`
+	Amount       float64    `json:"amount"`
+	CategoryID   int        `json:"category_id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	CancelReason string     `json:"cancel_reason,omitempty"`
+	Deadline     *time.Time `json:"deadline,omitempty"`
+	XP           int        `json:"xp,omitempty"`
+	MinLevel     int        `json:"min_level,omitempty"`
+	ForGroupID   int        `json:"for_group_id"`
+	UserID       int        `json:"user_id,omitempty"`
 }
 
 func New(ctx context.Context, log *slog.Logger, tasks httpserver.Tasks) http.HandlerFunc {
@@ -47,8 +59,29 @@ func New(ctx context.Context, log *slog.Logger, tasks httpserver.Tasks) http.Han
 
 		log.Info("request received")
 
-		tasks, err := tasks.GetAllAdminTasks(ctx, adminID)
+		filter, err := request.ParseTaskFilter(r)
 		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse filter", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		page, err := tasks.GetAllAdminTasks(ctx, adminID, filter)
+		if err != nil {
+			if errors.Is(err, taskservice.ErrInvalidFilter) {
+				w.WriteHeader(http.StatusBadRequest)
+
+				log.Error("invalid filter", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error(err.Error()))
+
+				return
+			}
+
 			w.WriteHeader(http.StatusInternalServerError)
 
 			log.Error("failed to get tasks", slog.String("error", err.Error()))
@@ -58,26 +91,42 @@ func New(ctx context.Context, log *slog.Logger, tasks httpserver.Tasks) http.Han
 			return
 		}
 
-		log.Info("tasks retrieved", slog.Any("tasks", tasks))
+		log.Info("tasks retrieved", slog.Int("count", len(page.Tasks)), slog.Int("total", page.Total))
 
-		var taskResponse []TaskResponse
+		taskResponse := make([]TaskResponse, 0, len(page.Tasks))
 
-		for _, task := range tasks {
+		for _, task := range page.Tasks {
 			taskResponse = append(taskResponse, TaskResponse{
-				ID:         task.ID,
-				Name:       task.Name,
-				StatusID:   task.StatusID,
-				Amount:     task.Amount,
-				CreatedAt:  task.CreatedAt,
-				ForGroupID: task.ForGroupID,
-				UserID:     task.UserID,
+				ID:           task.ID,
+				Name:         task.Name,
+				Description:  task.Description,
+				StatusID:     task.StatusID,
+				Amount:       task.Amount,
+				CategoryID:   task.CategoryID,
+				CreatedAt:    task.CreatedAt,
+				CancelReason: task.CancelReason,
+				Deadline:     deadline(task),
+				XP:           task.XP,
+				MinLevel:     task.MinLevel,
+				ForGroupID:   task.ForGroupID,
+				UserID:       task.UserID,
 			})
 		}
 
 		render.JSON(w, r, Response{
-			Response: resp.OK(),
-			Tasks:    taskResponse,
+			Response:   resp.OK(),
+			Tasks:      taskResponse,
+			Total:      page.Total,
+			NextCursor: page.NextCursor,
 		})
 
 	}
 }
+
+func deadline(task model.Task) *time.Time {
+	if task.Deadline.IsZero() {
+		return nil
+	}
+
+	return &task.Deadline
+}
@@ -0,0 +1,121 @@
+package cancel
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	taskservice "github.com/k6mil6/hackathon-game-backend/internal/service/tasks"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type Request struct {
+	Reason string `json:"reason"`
+}
+
+type Response struct {
+	resp.Response
+}
+
+func New(ctx context.Context, log *slog.Logger, tasks httpserver.Tasks) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.tasks.cancel.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		taskID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		req.Reason = strings.TrimSpace(req.Reason)
+		if req.Reason == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("reason is required")
+
+			render.JSON(w, r, resp.Error("reason is required"))
+
+			return
+		}
+
+		adminID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		if err := tasks.Cancel(ctx, taskID, adminID, req.Reason); err != nil {
+			switch {
+			case errors.Is(err, taskservice.ErrTaskNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, taskservice.ErrNotEnoughPermission):
+				w.WriteHeader(http.StatusForbidden)
+			case errors.Is(err, taskservice.ErrTaskClosed):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to cancel task", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to cancel task"))
+
+				return
+			}
+
+			log.Error("failed to cancel task", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		log.Info("task cancelled")
+
+		render.JSON(w, r, Response{resp.OK()})
+	}
+}
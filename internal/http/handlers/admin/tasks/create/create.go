@@ -2,25 +2,36 @@ package create
 
 import (
 	"context"
+	"errors"
 	"github.com/go-chi/render"
 	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
 	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
 	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
 	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	taskservice "github.com/k6mil6/hackathon-game-backend/internal/service/tasks"
 	"log/slog"
 	"net/http"
+	"time"
 )
 
 type Request struct {
-	Name       string  `json:"name"`
-	Amount     float64 `json:"amount"`
-	ForGroupID int     `json:"for_group_id"`
-	UserID     int     `json:"user_id,omitempty"`
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Amount      float64 `json:"amount"`
+	ForGroupID  int     `json:"for_group_id"`
+	UserID      int     `json:"user_id,omitempty"`
+	CategoryID  int     `json:"category_id,omitempty"`
+	// Deadline is optional, tasks without it can be submitted at any time
+	Deadline time.Time `json:"deadline,omitempty"`
+	// XP is optional, without it the XP is derived from the amount
+	XP       int `json:"xp,omitempty"`
+	MinLevel int `json:"min_level,omitempty"`
 }
 
 type Response struct {
 	resp.Response
-	ID int `json:"id"`
+	ID               int  `json:"id"`
+	ApprovalRequired bool `json:"approval_required"`
 }
 
 func New(ctx context.Context, log *slog.Logger, tasks httpserver.Tasks) http.HandlerFunc {
@@ -75,6 +86,26 @@ func New(ctx context.Context, log *slog.Logger, tasks httpserver.Tasks) http.Han
 			return
 		}
 
+		if !req.Deadline.IsZero() && req.Deadline.Before(time.Now()) {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("deadline is in the past")
+
+			render.JSON(w, r, resp.Error("deadline is in the past"))
+
+			return
+		}
+
+		if req.XP < 0 || req.MinLevel < 0 {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("xp and min_level can not be negative")
+
+			render.JSON(w, r, resp.Error("xp and min_level can not be negative"))
+
+			return
+		}
+
 		adminID, err := identity.GetID(r.Context())
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
@@ -87,13 +118,28 @@ func New(ctx context.Context, log *slog.Logger, tasks httpserver.Tasks) http.Han
 		}
 
 		id, err := tasks.Add(ctx, model.Task{
-			Name:       req.Name,
-			Amount:     req.Amount,
-			CreatedBy:  adminID,
-			ForGroupID: req.ForGroupID,
-			UserID:     req.UserID,
+			Name:        req.Name,
+			Description: req.Description,
+			Amount:      req.Amount,
+			CreatedBy:   adminID,
+			ForGroupID:  req.ForGroupID,
+			UserID:      req.UserID,
+			CategoryID:  req.CategoryID,
+			Deadline:    req.Deadline,
+			XP:          req.XP,
+			MinLevel:    req.MinLevel,
 		})
 		if err != nil {
+			if errors.Is(err, taskservice.ErrBudgetExceeded) || errors.Is(err, taskservice.ErrNoBudget) {
+				w.WriteHeader(http.StatusBadRequest)
+
+				log.Error("budget does not allow the task", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error(err.Error()))
+
+				return
+			}
+
 			w.WriteHeader(http.StatusInternalServerError)
 
 			log.Error("failed to create task", slog.String("error", err.Error()))
@@ -105,14 +151,15 @@ func New(ctx context.Context, log *slog.Logger, tasks httpserver.Tasks) http.Han
 
 		log.Info("response sent")
 
-		responseOK(w, r, id)
+		responseOK(w, r, id, tasks.RequiresApproval(req.Amount))
 	}
 }
 
-func responseOK(w http.ResponseWriter, r *http.Request, id int) {
+func responseOK(w http.ResponseWriter, r *http.Request, id int, approvalRequired bool) {
 	w.WriteHeader(http.StatusOK)
 	render.JSON(w, r, Response{
-		Response: resp.Response{Status: "ok"},
-		ID:       id,
+		Response:         resp.Response{Status: "ok"},
+		ID:               id,
+		ApprovalRequired: approvalRequired,
 	})
 }
@@ -0,0 +1,133 @@
+package reassign
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	taskservice "github.com/k6mil6/hackathon-game-backend/internal/service/tasks"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Request struct {
+	ForGroupID int `json:"for_group_id"`
+	UserID     int `json:"user_id,omitempty"`
+}
+
+type Response struct {
+	resp.Response
+	ID         int `json:"id"`
+	ForGroupID int `json:"for_group_id"`
+	UserID     int `json:"user_id,omitempty"`
+	StatusID   int `json:"status_id"`
+}
+
+func New(ctx context.Context, log *slog.Logger, tasks httpserver.Tasks) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.tasks.reassign.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		taskID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		if req.ForGroupID == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("for_group_id is required")
+
+			render.JSON(w, r, resp.Error("for_group_id is required"))
+
+			return
+		}
+
+		adminID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		task, err := tasks.Reassign(ctx, taskID, adminID, req.ForGroupID, req.UserID)
+		if err != nil {
+			switch {
+			case errors.Is(err, taskservice.ErrTaskNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, taskservice.ErrNotEnoughPermission):
+				w.WriteHeader(http.StatusForbidden)
+			case errors.Is(err, taskservice.ErrTaskClosed),
+				errors.Is(err, taskservice.ErrUserRequired),
+				errors.Is(err, taskservice.ErrInvalidGroup):
+				w.WriteHeader(http.StatusBadRequest)
+			case errors.Is(err, taskservice.ErrPendingApproval):
+				w.WriteHeader(http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to reassign task", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to reassign task"))
+
+				return
+			}
+
+			log.Error("failed to reassign task", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response:   resp.OK(),
+			ID:         task.ID,
+			ForGroupID: task.ForGroupID,
+			UserID:     task.UserID,
+			StatusID:   task.StatusID,
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package reviewer
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	reviewsservice "github.com/k6mil6/hackathon-game-backend/internal/service/reviews"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Request struct {
+	AdminID int `json:"admin_id"`
+}
+
+type Response struct {
+	resp.Response
+}
+
+func New(ctx context.Context, log *slog.Logger, reviews httpserver.Reviews) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.tasks.reviewer.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		taskID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		if req.AdminID == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("admin_id is required")
+
+			render.JSON(w, r, resp.Error("admin_id is required"))
+
+			return
+		}
+
+		adminID, err := identity.GetAdminID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		if err := reviews.AssignTaskReviewer(ctx, taskID, req.AdminID, adminID); err != nil {
+			switch {
+			case errors.Is(err, reviewsservice.ErrTaskNotFound), errors.Is(err, reviewsservice.ErrAdminNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, reviewsservice.ErrNotEnoughPermission):
+				w.WriteHeader(http.StatusForbidden)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to assign reviewer", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to assign reviewer"))
+
+				return
+			}
+
+			log.Error("failed to assign reviewer", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{resp.OK()})
+	}
+}
@@ -0,0 +1,169 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	taskservice "github.com/k6mil6/hackathon-game-backend/internal/service/tasks"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Request struct {
+	Name        *string  `json:"name,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Amount      *float64 `json:"amount,omitempty"`
+	CategoryID  *int     `json:"category_id,omitempty"`
+	XP          *int     `json:"xp,omitempty"`
+	MinLevel    *int     `json:"min_level,omitempty"`
+}
+
+type Response struct {
+	resp.Response
+	ID         int     `json:"id"`
+	Name       string  `json:"name"`
+	Amount     float64 `json:"amount"`
+	CategoryID int     `json:"category_id"`
+	XP         int     `json:"xp"`
+	MinLevel   int     `json:"min_level"`
+}
+
+func New(ctx context.Context, log *slog.Logger, tasks httpserver.Tasks) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.tasks.update.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		taskID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		if req.Name != nil && *req.Name == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("name can not be empty")
+
+			render.JSON(w, r, resp.Error("name can not be empty"))
+
+			return
+		}
+
+		if req.Amount != nil && *req.Amount <= 0 {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("amount must be positive")
+
+			render.JSON(w, r, resp.Error("amount must be positive"))
+
+			return
+		}
+
+		if (req.XP != nil && *req.XP < 0) || (req.MinLevel != nil && *req.MinLevel < 0) {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("xp and min_level can not be negative")
+
+			render.JSON(w, r, resp.Error("xp and min_level can not be negative"))
+
+			return
+		}
+
+		adminID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		task, err := tasks.Update(ctx, taskID, adminID, model.TaskUpdate{
+			Name:        req.Name,
+			Description: req.Description,
+			Amount:      req.Amount,
+			CategoryID:  req.CategoryID,
+			XP:          req.XP,
+			MinLevel:    req.MinLevel,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, taskservice.ErrTaskNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, taskservice.ErrNotEnoughPermission):
+				w.WriteHeader(http.StatusForbidden)
+			case errors.Is(err, taskservice.ErrTaskClosed),
+				errors.Is(err, taskservice.ErrNothingToUpdate),
+				errors.Is(err, taskservice.ErrBudgetExceeded):
+				w.WriteHeader(http.StatusBadRequest)
+			case errors.Is(err, taskservice.ErrPendingApproval):
+				w.WriteHeader(http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to update task", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to update task"))
+
+				return
+			}
+
+			log.Error("failed to update task", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response:   resp.OK(),
+			ID:         task.ID,
+			Name:       task.Name,
+			Amount:     task.Amount,
+			CategoryID: task.CategoryID,
+			XP:         task.XP,
+			MinLevel:   task.MinLevel,
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package accept
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/admin/teams"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	teamsservice "github.com/k6mil6/hackathon-game-backend/internal/service/teams"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Response struct {
+	resp.Response
+	Completion teams.ResponseCompletion `json:"completion"`
+}
+
+func New(ctx context.Context, log *slog.Logger, teamsService httpserver.Teams) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.teams.accept.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		completionID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		adminID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		completion, err := teamsService.AcceptCompletion(ctx, completionID, adminID)
+		if err != nil {
+			switch {
+			case errors.Is(err, teamsservice.ErrCompletionNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, teamsservice.ErrCompletionAccepted),
+				errors.Is(err, teamsservice.ErrBudgetExceeded):
+				w.WriteHeader(http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to accept completion", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to accept completion"))
+
+				return
+			}
+
+			log.Error("failed to accept completion", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response:   resp.OK(),
+			Completion: teams.ToResponseCompletion(completion),
+		})
+	}
+}
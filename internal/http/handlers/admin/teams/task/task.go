@@ -0,0 +1,102 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	teamsservice "github.com/k6mil6/hackathon-game-backend/internal/service/teams"
+	"log/slog"
+	"net/http"
+)
+
+type Request struct {
+	Name            string  `json:"name"`
+	Description     string  `json:"description,omitempty"`
+	Amount          float64 `json:"amount"`
+	RequiredMembers int     `json:"required_members"`
+	TeamID          int     `json:"team_id,omitempty"`
+}
+
+type Response struct {
+	resp.Response
+	ID int `json:"id"`
+}
+
+func New(ctx context.Context, log *slog.Logger, teamsService httpserver.Teams) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.admin.teams.task.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		adminID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get admin ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get admin ID"))
+
+			return
+		}
+
+		id, err := teamsService.CreateTask(ctx, adminID, model.TeamTask{
+			Name:            req.Name,
+			Description:     req.Description,
+			Amount:          req.Amount,
+			RequiredMembers: req.RequiredMembers,
+			TeamID:          req.TeamID,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, teamsservice.ErrTeamNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, teamsservice.ErrInvalidName),
+				errors.Is(err, teamsservice.ErrInvalidAmount),
+				errors.Is(err, teamsservice.ErrInvalidRequiredMembers),
+				errors.Is(err, teamsservice.ErrAmountNeedsApproval),
+				errors.Is(err, teamsservice.ErrNoBudget),
+				errors.Is(err, teamsservice.ErrBudgetExceeded):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to create team task", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to create team task"))
+
+				return
+			}
+
+			log.Error("failed to create team task", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			ID:       id,
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package teams
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"time"
+)
+
+type ResponseCompletion struct {
+	ID           int        `json:"id"`
+	TaskID       int        `json:"task_id"`
+	TaskName     string     `json:"task_name"`
+	TeamID       int        `json:"team_id"`
+	TeamName     string     `json:"team_name"`
+	Amount       float64    `json:"amount"`
+	Share        float64    `json:"share,omitempty"`
+	SubmitterIDs []int      `json:"submitter_ids,omitempty"`
+	CompletedAt  time.Time  `json:"completed_at"`
+	AcceptedAt   *time.Time `json:"accepted_at,omitempty"`
+}
+
+func ToResponseCompletion(completion model.TeamTaskCompletion) ResponseCompletion {
+	res := ResponseCompletion{
+		ID:           completion.ID,
+		TaskID:       completion.TeamTaskID,
+		TaskName:     completion.TaskName,
+		TeamID:       completion.TeamID,
+		TeamName:     completion.TeamName,
+		Amount:       completion.Amount,
+		Share:        completion.Share,
+		SubmitterIDs: completion.SubmitterIDs,
+		CompletedAt:  completion.CompletedAt,
+	}
+
+	if !completion.AcceptedAt.IsZero() {
+		res.AcceptedAt = &completion.AcceptedAt
+	}
+
+	return res
+}
+
+func ToResponseCompletions(completions []model.TeamTaskCompletion) []ResponseCompletion {
+	completionsRes := make([]ResponseCompletion, 0, len(completions))
+
+	for _, completion := range completions {
+		completionsRes = append(completionsRes, ToResponseCompletion(completion))
+	}
+
+	return completionsRes
+}
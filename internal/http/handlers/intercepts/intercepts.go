@@ -0,0 +1,46 @@
+package intercepts
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"time"
+)
+
+// ResponseIntercept carries everything needed to replay the outcome: the attempt succeeded
+// if the roll derived from the seed was below the chance.
+type ResponseIntercept struct {
+	ID        int       `json:"id"`
+	TaskID    int       `json:"task_id"`
+	ThiefID   int       `json:"thief_id"`
+	VictimID  int       `json:"victim_id"`
+	Seed      int64     `json:"seed"`
+	Roll      float64   `json:"roll"`
+	Chance    float64   `json:"chance"`
+	Success   bool      `json:"success"`
+	Penalty   float64   `json:"penalty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func ToResponse(intercept model.Intercept) ResponseIntercept {
+	return ResponseIntercept{
+		ID:        intercept.ID,
+		TaskID:    intercept.TaskID,
+		ThiefID:   intercept.ThiefID,
+		VictimID:  intercept.VictimID,
+		Seed:      intercept.Seed,
+		Roll:      intercept.Roll,
+		Chance:    intercept.Chance,
+		Success:   intercept.Success,
+		Penalty:   intercept.Penalty,
+		CreatedAt: intercept.CreatedAt,
+	}
+}
+
+func ToResponseList(intercepts []model.Intercept) []ResponseIntercept {
+	res := make([]ResponseIntercept, 0, len(intercepts))
+
+	for _, intercept := range intercepts {
+		res = append(res, ToResponse(intercept))
+	}
+
+	return res
+}
@@ -0,0 +1,121 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/request"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	searchservice "github.com/k6mil6/hackathon-game-backend/internal/service/search"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type Response struct {
+	resp.Response
+	Results []ResponseResult `json:"results"`
+}
+
+type ResponseResult struct {
+	Type    string  `json:"type"`
+	ID      int     `json:"id"`
+	Title   string  `json:"title"`
+	Snippet string  `json:"snippet,omitempty"`
+	Rank    float64 `json:"rank"`
+}
+
+func New(ctx context.Context, log *slog.Logger, search httpserver.Search) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.search.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		callerID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get caller ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get caller ID"))
+
+			return
+		}
+
+		role, err := identity.GetRole(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get caller role", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get caller role"))
+
+			return
+		}
+
+		limit, err := request.QueryInt(r, "limit")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse limit", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		query := model.SearchQuery{
+			Text:  r.URL.Query().Get("q"),
+			Limit: limit,
+		}
+
+		if types := r.URL.Query().Get("type"); types != "" {
+			query.Types = strings.Split(types, ",")
+		}
+
+		results, err := search.Search(ctx, callerID, role, query)
+		if err != nil {
+			if errors.Is(err, searchservice.ErrEmptyQuery) || errors.Is(err, searchservice.ErrUnknownType) {
+				w.WriteHeader(http.StatusBadRequest)
+
+				log.Error("invalid search query", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error(err.Error()))
+
+				return
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to search", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to search"))
+
+			return
+		}
+
+		resultsRes := make([]ResponseResult, 0, len(results))
+
+		for _, result := range results {
+			resultsRes = append(resultsRes, ResponseResult{
+				Type:    result.Type,
+				ID:      result.ID,
+				Title:   result.Title,
+				Snippet: result.Snippet,
+				Rank:    result.Rank,
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Results:  resultsRes,
+		})
+	}
+}
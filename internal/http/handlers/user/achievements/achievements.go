@@ -0,0 +1,31 @@
+package achievements
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+)
+
+type ResponseAchievement struct {
+	ID          int     `json:"id"`
+	Code        string  `json:"code"`
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Metric      string  `json:"metric"`
+	Threshold   int     `json:"threshold"`
+	Period      string  `json:"period"`
+	RewardCoins float64 `json:"reward_coins,omitempty"`
+	RewardXP    int     `json:"reward_xp,omitempty"`
+}
+
+func ToResponse(achievement model.Achievement) ResponseAchievement {
+	return ResponseAchievement{
+		ID:          achievement.ID,
+		Code:        achievement.Code,
+		Name:        achievement.Name,
+		Description: achievement.Description,
+		Metric:      achievement.Metric,
+		Threshold:   achievement.Threshold,
+		Period:      achievement.Period,
+		RewardCoins: achievement.RewardCoins,
+		RewardXP:    achievement.RewardXP,
+	}
+}
@@ -0,0 +1,79 @@
+package all
+
+import (
+	"context"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/achievements"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type Response struct {
+	resp.Response
+	Achievements []ResponseProgress `json:"achievements"`
+}
+
+type ResponseProgress struct {
+	achievements.ResponseAchievement
+	Value     int        `json:"value"`
+	Awarded   bool       `json:"awarded"`
+	AwardedAt *time.Time `json:"awarded_at,omitempty"`
+}
+
+func New(ctx context.Context, log *slog.Logger, achievementsService httpserver.Achievements) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.achievements.all.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		list, err := achievementsService.GetProgress(ctx, userID)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get achievements progress", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get achievements progress"))
+
+			return
+		}
+
+		progressRes := make([]ResponseProgress, 0, len(list))
+
+		for _, item := range list {
+			progress := ResponseProgress{
+				ResponseAchievement: achievements.ToResponse(item.Achievement),
+				Value:               item.Value,
+				Awarded:             item.Awarded,
+			}
+
+			if item.Awarded {
+				awardedAt := item.AwardedAt
+				progress.AwardedAt = &awardedAt
+			}
+
+			progressRes = append(progressRes, progress)
+		}
+
+		render.JSON(w, r, Response{
+			Response:     resp.OK(),
+			Achievements: progressRes,
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package mine
+
+import (
+	"context"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/achievements"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type Response struct {
+	resp.Response
+	Badges []ResponseBadge `json:"badges"`
+}
+
+type ResponseBadge struct {
+	achievements.ResponseAchievement
+	AwardedAt time.Time `json:"awarded_at"`
+}
+
+func New(ctx context.Context, log *slog.Logger, achievementsService httpserver.Achievements) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.achievements.mine.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		list, err := achievementsService.GetUserAchievements(ctx, userID)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get achievements", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get achievements"))
+
+			return
+		}
+
+		badgesRes := make([]ResponseBadge, 0, len(list))
+
+		for _, item := range list {
+			badgesRes = append(badgesRes, ResponseBadge{
+				ResponseAchievement: achievements.ToResponse(item.Achievement),
+				AwardedAt:           item.AwardedAt,
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Badges:   badgesRes,
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package abandon
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/bounties"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	bountiesservice "github.com/k6mil6/hackathon-game-backend/internal/service/bounties"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Response struct {
+	resp.Response
+	Bounty bounties.ResponseBounty `json:"bounty"`
+}
+
+func New(ctx context.Context, log *slog.Logger, bountiesService httpserver.Bounties) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.bounties.abandon.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		bountyID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		bounty, err := bountiesService.Abandon(ctx, bountyID, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, bountiesservice.ErrBountyNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, bountiesservice.ErrNotPoster),
+				errors.Is(err, bountiesservice.ErrNotHunter):
+				w.WriteHeader(http.StatusForbidden)
+			case errors.Is(err, bountiesservice.ErrBountyNotClaimed):
+				w.WriteHeader(http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to abandon bounty", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to abandon bounty"))
+
+				return
+			}
+
+			log.Error("failed to abandon bounty", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Bounty:   bounties.ToResponse(bounty),
+		})
+	}
+}
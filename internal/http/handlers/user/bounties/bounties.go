@@ -0,0 +1,93 @@
+package bounties
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	bountiesstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/bounties"
+	"time"
+)
+
+var statuses = map[int]string{
+	bountiesstorage.OpenStatusID:      "open",
+	bountiesstorage.ClaimedStatusID:   "claimed",
+	bountiesstorage.SubmittedStatusID: "submitted",
+	bountiesstorage.CompletedStatusID: "completed",
+	bountiesstorage.CancelledStatusID: "cancelled",
+	bountiesstorage.DisputedStatusID:  "disputed",
+}
+
+type ResponseBounty struct {
+	ID           int        `json:"id"`
+	PosterID     int        `json:"poster_id"`
+	PosterName   string     `json:"poster_name"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description,omitempty"`
+	Reward       float64    `json:"reward"`
+	Status       string     `json:"status"`
+	HunterID     int        `json:"hunter_id,omitempty"`
+	HunterName   string     `json:"hunter_name,omitempty"`
+	Submission   string     `json:"submission,omitempty"`
+	RejectReason string     `json:"reject_reason,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ClaimedAt    *time.Time `json:"claimed_at,omitempty"`
+	SubmittedAt  *time.Time `json:"submitted_at,omitempty"`
+	ClosedAt     *time.Time `json:"closed_at,omitempty"`
+}
+
+type ResponseDispute struct {
+	ID        int       `json:"id"`
+	BountyID  int       `json:"bounty_id"`
+	RaisedBy  int       `json:"raised_by"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func ToResponse(bounty model.Bounty) ResponseBounty {
+	res := ResponseBounty{
+		ID:           bounty.ID,
+		PosterID:     bounty.PosterID,
+		PosterName:   bounty.PosterName,
+		Title:        bounty.Title,
+		Description:  bounty.Description,
+		Reward:       bounty.Reward,
+		Status:       statuses[bounty.StatusID],
+		HunterID:     bounty.HunterID,
+		HunterName:   bounty.HunterName,
+		Submission:   bounty.Submission,
+		RejectReason: bounty.RejectReason,
+		CreatedAt:    bounty.CreatedAt,
+	}
+
+	if !bounty.ClaimedAt.IsZero() {
+		res.ClaimedAt = &bounty.ClaimedAt
+	}
+
+	if !bounty.SubmittedAt.IsZero() {
+		res.SubmittedAt = &bounty.SubmittedAt
+	}
+
+	if !bounty.ClosedAt.IsZero() {
+		res.ClosedAt = &bounty.ClosedAt
+	}
+
+	return res
+}
+
+func ToResponses(bounties []model.Bounty) []ResponseBounty {
+	bountiesRes := make([]ResponseBounty, 0, len(bounties))
+
+	for _, bounty := range bounties {
+		bountiesRes = append(bountiesRes, ToResponse(bounty))
+	}
+
+	return bountiesRes
+}
+
+func ToResponseDispute(dispute model.BountyDispute) ResponseDispute {
+	return ResponseDispute{
+		ID:        dispute.ID,
+		BountyID:  dispute.BountyID,
+		RaisedBy:  dispute.RaisedBy,
+		Reason:    dispute.Reason,
+		CreatedAt: dispute.CreatedAt,
+	}
+}
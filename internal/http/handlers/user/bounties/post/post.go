@@ -0,0 +1,98 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/bounties"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	bountiesservice "github.com/k6mil6/hackathon-game-backend/internal/service/bounties"
+	"log/slog"
+	"net/http"
+)
+
+type Request struct {
+	Title       string  `json:"title"`
+	Description string  `json:"description,omitempty"`
+	Reward      float64 `json:"reward"`
+}
+
+type Response struct {
+	resp.Response
+	Bounty bounties.ResponseBounty `json:"bounty"`
+}
+
+func New(ctx context.Context, log *slog.Logger, bountiesService httpserver.Bounties) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.bounties.post.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		bounty, err := bountiesService.Post(ctx, userID, model.Bounty{
+			Title:       req.Title,
+			Description: req.Description,
+			Reward:      req.Reward,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, bountiesservice.ErrUserNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, bountiesservice.ErrInsufficientFunds):
+				w.WriteHeader(http.StatusConflict)
+			case errors.Is(err, bountiesservice.ErrTitleRequired),
+				errors.Is(err, bountiesservice.ErrTitleTooLong),
+				errors.Is(err, bountiesservice.ErrTextTooLong),
+				errors.Is(err, bountiesservice.ErrInvalidReward),
+				errors.Is(err, bountiesservice.ErrRewardTooHigh):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to post bounty", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to post bounty"))
+
+				return
+			}
+
+			log.Error("failed to post bounty", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Bounty:   bounties.ToResponse(bounty),
+		})
+	}
+}
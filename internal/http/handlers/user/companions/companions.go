@@ -0,0 +1,34 @@
+package companions
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"time"
+)
+
+type ResponseCompanion struct {
+	Animal    string     `json:"animal"`
+	Hunger    float64    `json:"hunger"`
+	Happiness float64    `json:"happiness"`
+	WellBeing float64    `json:"well_being"`
+	FedAt     *time.Time `json:"fed_at,omitempty"`
+	PlayedAt  *time.Time `json:"played_at,omitempty"`
+}
+
+func ToCompanionResponse(companion model.Companion) ResponseCompanion {
+	res := ResponseCompanion{
+		Animal:    companion.Animal,
+		Hunger:    companion.Hunger,
+		Happiness: companion.Happiness,
+		WellBeing: companion.WellBeing,
+	}
+
+	if !companion.FedAt.IsZero() {
+		res.FedAt = &companion.FedAt
+	}
+
+	if !companion.PlayedAt.IsZero() {
+		res.PlayedAt = &companion.PlayedAt
+	}
+
+	return res
+}
@@ -0,0 +1,90 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/companions"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	companionsservice "github.com/k6mil6/hackathon-game-backend/internal/service/companions"
+	"log/slog"
+	"net/http"
+)
+
+type Request struct {
+	ResourceID int `json:"resource_id"`
+	Quantity   int `json:"quantity"`
+}
+
+type Response struct {
+	resp.Response
+	Companion companions.ResponseCompanion `json:"companion"`
+}
+
+func New(ctx context.Context, log *slog.Logger, companionsService httpserver.Companions) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.companions.feed.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		companion, err := companionsService.Feed(ctx, userID, req.ResourceID, req.Quantity)
+		if err != nil {
+			switch {
+			case errors.Is(err, companionsservice.ErrInvalidQuantity),
+				errors.Is(err, companionsservice.ErrNotFood):
+				w.WriteHeader(http.StatusBadRequest)
+			case errors.Is(err, companionsservice.ErrResourceNotFound),
+				errors.Is(err, companionsservice.ErrUserNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, companionsservice.ErrNotEnoughResources):
+				w.WriteHeader(http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to feed companion", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to feed companion"))
+
+				return
+			}
+
+			log.Error("failed to feed companion", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response:  resp.OK(),
+			Companion: companions.ToCompanionResponse(companion),
+		})
+	}
+}
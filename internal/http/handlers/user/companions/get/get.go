@@ -0,0 +1,66 @@
+package get
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/companions"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	companionsservice "github.com/k6mil6/hackathon-game-backend/internal/service/companions"
+	"log/slog"
+	"net/http"
+)
+
+type Response struct {
+	resp.Response
+	Companion companions.ResponseCompanion `json:"companion"`
+}
+
+func New(ctx context.Context, log *slog.Logger, companionsService httpserver.Companions) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.companions.get.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		companion, err := companionsService.Get(ctx, userID)
+		if err != nil {
+			if errors.Is(err, companionsservice.ErrUserNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+
+				log.Error("failed to get companion", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error(err.Error()))
+
+				return
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get companion", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get companion"))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response:  resp.OK(),
+			Companion: companions.ToCompanionResponse(companion),
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package play
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/companions"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	companionsservice "github.com/k6mil6/hackathon-game-backend/internal/service/companions"
+	"log/slog"
+	"net/http"
+)
+
+type Response struct {
+	resp.Response
+	Companion companions.ResponseCompanion `json:"companion"`
+}
+
+func New(ctx context.Context, log *slog.Logger, companionsService httpserver.Companions) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.companions.play.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		companion, err := companionsService.Play(ctx, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, companionsservice.ErrUserNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, companionsservice.ErrTooSoon):
+				w.WriteHeader(http.StatusTooManyRequests)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to play with companion", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to play with companion"))
+
+				return
+			}
+
+			log.Error("failed to play with companion", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response:  resp.OK(),
+			Companion: companions.ToCompanionResponse(companion),
+		})
+	}
+}
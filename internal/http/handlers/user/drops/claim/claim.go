@@ -0,0 +1,97 @@
+package claim
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/drops"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	dropsservice "github.com/k6mil6/hackathon-game-backend/internal/service/drops"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Response struct {
+	resp.Response
+	Drop drops.ResponseDrop `json:"drop"`
+}
+
+func New(ctx context.Context, log *slog.Logger, dropsService httpserver.Drops) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.drops.claim.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		dropID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		drop, err := dropsService.Claim(ctx, dropID, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, dropsservice.ErrDropNotFound),
+				errors.Is(err, dropsservice.ErrUserNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, dropsservice.ErrDropExpired):
+				w.WriteHeader(http.StatusGone)
+			case errors.Is(err, dropsservice.ErrDropExhausted),
+				errors.Is(err, dropsservice.ErrDropAlreadyClaimed):
+				w.WriteHeader(http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to claim drop", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to claim drop"))
+
+				return
+			}
+
+			log.Error("failed to claim drop", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Drop:     drops.ToResponse(drop),
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package drops
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"time"
+)
+
+type ResponseDrop struct {
+	ID           int       `json:"id"`
+	CampaignName string    `json:"campaign_name"`
+	Reward       float64   `json:"reward"`
+	ClaimsLeft   int       `json:"claims_left"`
+	AppearsAt    time.Time `json:"appears_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	Claimed      bool      `json:"claimed"`
+}
+
+func ToResponse(drop model.Drop) ResponseDrop {
+	return ResponseDrop{
+		ID:           drop.ID,
+		CampaignName: drop.CampaignName,
+		Reward:       drop.Reward,
+		ClaimsLeft:   drop.MaxClaims - drop.Claims,
+		AppearsAt:    drop.AppearsAt,
+		ExpiresAt:    drop.ExpiresAt,
+		Claimed:      drop.Claimed,
+	}
+}
+
+func ToResponses(drops []model.Drop) []ResponseDrop {
+	dropsRes := make([]ResponseDrop, 0, len(drops))
+
+	for _, drop := range drops {
+		dropsRes = append(dropsRes, ToResponse(drop))
+	}
+
+	return dropsRes
+}
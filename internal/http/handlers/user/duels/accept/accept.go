@@ -0,0 +1,95 @@
+package accept
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/duels"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	duelsservice "github.com/k6mil6/hackathon-game-backend/internal/service/duels"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Response struct {
+	resp.Response
+	Duel duels.ResponseDuel `json:"duel"`
+}
+
+func New(ctx context.Context, log *slog.Logger, duelsService httpserver.Duels) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.duels.accept.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		duelID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		duel, err := duelsService.Accept(ctx, duelID, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, duelsservice.ErrDuelNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, duelsservice.ErrDuelNotPending),
+				errors.Is(err, duelsservice.ErrDuelExpired),
+				errors.Is(err, duelsservice.ErrInsufficientFunds):
+				w.WriteHeader(http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to accept duel", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to accept duel"))
+
+				return
+			}
+
+			log.Error("failed to accept duel", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Duel:     duels.ToResponse(duel),
+		})
+	}
+}
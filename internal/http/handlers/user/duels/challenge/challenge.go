@@ -0,0 +1,108 @@
+package challenge
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/duels"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	duelsservice "github.com/k6mil6/hackathon-game-backend/internal/service/duels"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type Request struct {
+	Kind       string  `json:"kind"`
+	OpponentID int     `json:"opponent_id"`
+	Stake      float64 `json:"stake"`
+	TaskID     int     `json:"task_id,omitempty"`
+	Terms      string  `json:"terms,omitempty"`
+}
+
+type Response struct {
+	resp.Response
+	Duel duels.ResponseDuel `json:"duel"`
+}
+
+func New(ctx context.Context, log *slog.Logger, duelsService httpserver.Duels) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.duels.challenge.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		duel, err := duelsService.Challenge(ctx, userID, model.Duel{
+			KindID:     duels.Kinds[strings.ToLower(strings.TrimSpace(req.Kind))],
+			OpponentID: req.OpponentID,
+			Stake:      req.Stake,
+			TaskID:     req.TaskID,
+			Terms:      req.Terms,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, duelsservice.ErrUserNotFound),
+				errors.Is(err, duelsservice.ErrTaskNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, duelsservice.ErrTaskUnavailable),
+				errors.Is(err, duelsservice.ErrInsufficientFunds):
+				w.WriteHeader(http.StatusConflict)
+			case errors.Is(err, duelsservice.ErrUnknownKind),
+				errors.Is(err, duelsservice.ErrSelfChallenge),
+				errors.Is(err, duelsservice.ErrInvalidStake),
+				errors.Is(err, duelsservice.ErrStakeTooHigh),
+				errors.Is(err, duelsservice.ErrTaskRequired),
+				errors.Is(err, duelsservice.ErrTermsRequired),
+				errors.Is(err, duelsservice.ErrWagerTask),
+				errors.Is(err, duelsservice.ErrTermsTooLong):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to create duel", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to create duel"))
+
+				return
+			}
+
+			log.Error("failed to create duel", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Duel:     duels.ToResponse(duel),
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package duels
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	duelsstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/duels"
+	"time"
+)
+
+var Kinds = map[string]int{
+	"race":  duelsstorage.RaceKindID,
+	"wager": duelsstorage.WagerKindID,
+}
+
+var statuses = map[int]string{
+	duelsstorage.PendingStatusID:  "pending",
+	duelsstorage.ActiveStatusID:   "active",
+	duelsstorage.DeclinedStatusID: "declined",
+	duelsstorage.ResolvedStatusID: "resolved",
+	duelsstorage.RefundedStatusID: "refunded",
+}
+
+type ResponseDuel struct {
+	ID             int        `json:"id"`
+	Kind           string     `json:"kind"`
+	ChallengerID   int        `json:"challenger_id"`
+	ChallengerName string     `json:"challenger_name"`
+	OpponentID     int        `json:"opponent_id"`
+	OpponentName   string     `json:"opponent_name"`
+	Stake          float64    `json:"stake"`
+	TaskID         int        `json:"task_id,omitempty"`
+	Terms          string     `json:"terms,omitempty"`
+	Status         string     `json:"status"`
+	WinnerID       int        `json:"winner_id,omitempty"`
+	RespondBy      time.Time  `json:"respond_by"`
+	Deadline       *time.Time `json:"deadline,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+}
+
+type ResponseRecord struct {
+	Wins     int     `json:"wins"`
+	Losses   int     `json:"losses"`
+	Refunded int     `json:"refunded"`
+	Net      float64 `json:"net"`
+}
+
+func ToResponse(duel model.Duel) ResponseDuel {
+	res := ResponseDuel{
+		ID:             duel.ID,
+		ChallengerID:   duel.ChallengerID,
+		ChallengerName: duel.ChallengerName,
+		OpponentID:     duel.OpponentID,
+		OpponentName:   duel.OpponentName,
+		Stake:          duel.Stake,
+		TaskID:         duel.TaskID,
+		Terms:          duel.Terms,
+		Status:         statuses[duel.StatusID],
+		WinnerID:       duel.WinnerID,
+		RespondBy:      duel.RespondBy,
+		CreatedAt:      duel.CreatedAt,
+	}
+
+	for kind, kindID := range Kinds {
+		if kindID == duel.KindID {
+			res.Kind = kind
+		}
+	}
+
+	if !duel.Deadline.IsZero() {
+		res.Deadline = &duel.Deadline
+	}
+
+	if !duel.AcceptedAt.IsZero() {
+		res.AcceptedAt = &duel.AcceptedAt
+	}
+
+	if !duel.ResolvedAt.IsZero() {
+		res.ResolvedAt = &duel.ResolvedAt
+	}
+
+	return res
+}
+
+func ToResponses(duels []model.Duel) []ResponseDuel {
+	duelsRes := make([]ResponseDuel, 0, len(duels))
+
+	for _, duel := range duels {
+		duelsRes = append(duelsRes, ToResponse(duel))
+	}
+
+	return duelsRes
+}
+
+func ToResponseRecord(record model.DuelRecord) ResponseRecord {
+	return ResponseRecord(record)
+}
@@ -0,0 +1,95 @@
+package checkin
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/events"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	eventsservice "github.com/k6mil6/hackathon-game-backend/internal/service/events"
+	"log/slog"
+	"net/http"
+)
+
+type Request struct {
+	Code string `json:"code"`
+}
+
+type Response struct {
+	resp.Response
+	Event events.ResponseEvent `json:"event"`
+}
+
+func New(ctx context.Context, log *slog.Logger, eventsService httpserver.Events) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.events.checkin.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		event, err := eventsService.CheckIn(ctx, userID, req.Code)
+		if err != nil {
+			switch {
+			case errors.Is(err, eventsservice.ErrCodeRequired),
+				errors.Is(err, eventsservice.ErrInvalidCode):
+				w.WriteHeader(http.StatusBadRequest)
+			case errors.Is(err, eventsservice.ErrEventNotFound),
+				errors.Is(err, eventsservice.ErrNotRSVPed):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, eventsservice.ErrCodeExpired):
+				w.WriteHeader(http.StatusGone)
+			case errors.Is(err, eventsservice.ErrCodeAlreadyUsed),
+				errors.Is(err, eventsservice.ErrAlreadyCheckedIn),
+				errors.Is(err, eventsservice.ErrEventCancelled),
+				errors.Is(err, eventsservice.ErrEventOver),
+				errors.Is(err, eventsservice.ErrBudgetExceeded):
+				w.WriteHeader(http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to check in", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to check in"))
+
+				return
+			}
+
+			log.Error("failed to check in", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Event:    events.ToResponse(event),
+		})
+	}
+}
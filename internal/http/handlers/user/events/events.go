@@ -0,0 +1,56 @@
+package events
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"time"
+)
+
+type ResponseEvent struct {
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Location    string     `json:"location,omitempty"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at"`
+	Capacity    int        `json:"capacity,omitempty"`
+	Reward      float64    `json:"reward"`
+	RSVPs       int        `json:"rsvps"`
+	RSVPed      bool       `json:"rsvped"`
+	CheckedInAt *time.Time `json:"checked_in_at,omitempty"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+}
+
+func ToResponse(event model.Event) ResponseEvent {
+	res := ResponseEvent{
+		ID:          event.ID,
+		Title:       event.Title,
+		Description: event.Description,
+		Location:    event.Location,
+		StartsAt:    event.StartsAt,
+		EndsAt:      event.EndsAt,
+		Capacity:    event.Capacity,
+		Reward:      event.Reward,
+		RSVPs:       event.RSVPs,
+		RSVPed:      event.RSVPed,
+	}
+
+	if !event.CheckedInAt.IsZero() {
+		res.CheckedInAt = &event.CheckedInAt
+	}
+
+	if !event.CancelledAt.IsZero() {
+		res.CancelledAt = &event.CancelledAt
+	}
+
+	return res
+}
+
+func ToResponses(events []model.Event) []ResponseEvent {
+	eventsRes := make([]ResponseEvent, 0, len(events))
+
+	for _, event := range events {
+		eventsRes = append(eventsRes, ToResponse(event))
+	}
+
+	return eventsRes
+}
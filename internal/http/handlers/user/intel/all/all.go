@@ -0,0 +1,87 @@
+package all
+
+import (
+	"context"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/intel"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/request"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Response struct {
+	resp.Response
+	Intel []intel.ResponseIntel `json:"intel"`
+}
+
+func New(ctx context.Context, log *slog.Logger, intelService httpserver.Intel) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.intel.all.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		var activeOnly bool
+		if value := r.URL.Query().Get("active"); value != "" {
+			activeOnly, err = strconv.ParseBool(value)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+
+				log.Error("failed to parse active", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("invalid active"))
+
+				return
+			}
+		}
+
+		limit, err := request.QueryInt(r, "limit")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse limit", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		list, err := intelService.GetAll(ctx, userID, activeOnly, limit)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get intel", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get intel"))
+
+			return
+		}
+
+		intelRes := make([]intel.ResponseIntel, 0, len(list))
+
+		for _, item := range list {
+			intelRes = append(intelRes, intel.ToResponse(item))
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Intel:    intelRes,
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package buy
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/intel"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	intelservice "github.com/k6mil6/hackathon-game-backend/internal/service/intel"
+	"log/slog"
+	"net/http"
+)
+
+type Request struct {
+	TargetID int `json:"target_id"`
+}
+
+type Response struct {
+	resp.Response
+	Intel intel.ResponseIntel `json:"intel"`
+}
+
+func New(ctx context.Context, log *slog.Logger, intelService httpserver.Intel) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.intel.buy.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		if req.TargetID == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("target_id is required")
+
+			render.JSON(w, r, resp.Error("target_id is required"))
+
+			return
+		}
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		bought, err := intelService.Buy(ctx, userID, req.TargetID)
+		if err != nil {
+			switch {
+			case errors.Is(err, intelservice.ErrClassNotAllowed):
+				w.WriteHeader(http.StatusForbidden)
+			case errors.Is(err, intelservice.ErrTargetNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, intelservice.ErrIntelActive):
+				w.WriteHeader(http.StatusConflict)
+			case errors.Is(err, intelservice.ErrSelfIntel), errors.Is(err, intelservice.ErrInsufficientFunds):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to buy intel", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to buy intel"))
+
+				return
+			}
+
+			log.Error("failed to buy intel", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		log.Info("intel bought", slog.Int("id", bought.ID))
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Intel:    intel.ToResponse(bought),
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package intel
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"time"
+)
+
+type ResponseIntel struct {
+	ID        int       `json:"id"`
+	TargetID  int       `json:"target_id"`
+	Fee       float64   `json:"fee"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func ToResponse(intel model.Intel) ResponseIntel {
+	return ResponseIntel{
+		ID:        intel.ID,
+		TargetID:  intel.TargetID,
+		Fee:       intel.Fee,
+		ExpiresAt: intel.ExpiresAt,
+		CreatedAt: intel.CreatedAt,
+	}
+}
@@ -0,0 +1,116 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/intel"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	intelservice "github.com/k6mil6/hackathon-game-backend/internal/service/intel"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type Response struct {
+	resp.Response
+	Intel intel.ResponseIntel `json:"intel"`
+	Tasks []ResponseTask      `json:"tasks"`
+}
+
+type ResponseTask struct {
+	ID         int       `json:"id"`
+	Name       string    `json:"name"`
+	Amount     float64   `json:"amount"`
+	CategoryID int       `json:"category_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func New(ctx context.Context, log *slog.Logger, intelService httpserver.Intel) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.intel.tasks.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		intelID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		bought, tasks, err := intelService.GetTasks(ctx, intelID, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, intelservice.ErrIntelNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, intelservice.ErrIntelExpired):
+				w.WriteHeader(http.StatusGone)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to get tasks", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to get tasks"))
+
+				return
+			}
+
+			log.Error("failed to get tasks", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		tasksRes := make([]ResponseTask, 0, len(tasks))
+
+		for _, task := range tasks {
+			tasksRes = append(tasksRes, ResponseTask{
+				ID:         task.ID,
+				Name:       task.Name,
+				Amount:     task.Amount,
+				CategoryID: task.CategoryID,
+				CreatedAt:  task.CreatedAt,
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Intel:    intel.ToResponse(bought),
+			Tasks:    tasksRes,
+		})
+	}
+}
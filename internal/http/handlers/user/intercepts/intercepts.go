@@ -0,0 +1,85 @@
+package intercepts
+
+import (
+	"context"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/intercepts"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/request"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type Response struct {
+	resp.Response
+	Intercepts    []intercepts.ResponseIntercept `json:"intercepts"`
+	NextAttemptAt *time.Time                     `json:"next_attempt_at,omitempty"`
+}
+
+func New(ctx context.Context, log *slog.Logger, interceptsService httpserver.Intercepts) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.intercepts.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		limit, err := request.QueryInt(r, "limit")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse limit", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		list, err := interceptsService.GetAll(ctx, userID, 0, limit)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get intercepts", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get intercepts"))
+
+			return
+		}
+
+		nextAttemptAt, err := interceptsService.NextAttemptAt(ctx, userID)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get next attempt time", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get next attempt time"))
+
+			return
+		}
+
+		res := Response{
+			Response:   resp.OK(),
+			Intercepts: intercepts.ToResponseList(list),
+		}
+
+		if !nextAttemptAt.IsZero() {
+			res.NextAttemptAt = &nextAttemptAt
+		}
+
+		render.JSON(w, r, res)
+	}
+}
@@ -0,0 +1,60 @@
+package allowance
+
+import (
+	"context"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type Response struct {
+	resp.Response
+	Allowance int       `json:"allowance"`
+	Given     int       `json:"given"`
+	Remaining int       `json:"remaining"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func New(ctx context.Context, log *slog.Logger, kudosService httpserver.Kudos) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.kudos.allowance.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		allowance, err := kudosService.GetAllowance(ctx, userID)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get kudos allowance", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get kudos allowance"))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response:  resp.OK(),
+			Allowance: allowance.Allowance,
+			Given:     allowance.Given,
+			Remaining: allowance.Remaining,
+			ExpiresAt: allowance.ExpiresAt,
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/kudos"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/request"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	kudosservice "github.com/k6mil6/hackathon-game-backend/internal/service/kudos"
+	"log/slog"
+	"net/http"
+)
+
+type Response struct {
+	resp.Response
+	Kudos []kudos.ResponseKudos `json:"kudos"`
+}
+
+func New(ctx context.Context, log *slog.Logger, kudosService httpserver.Kudos) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.kudos.feed.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		limit, err := request.QueryInt(r, "limit")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse limit", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		offset, err := request.QueryInt(r, "offset")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse offset", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		feed, err := kudosService.GetFeed(ctx, limit, offset)
+		if err != nil {
+			if errors.Is(err, kudosservice.ErrInvalidOffset) {
+				w.WriteHeader(http.StatusBadRequest)
+
+				log.Error("invalid offset", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error(err.Error()))
+
+				return
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get kudos feed", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get kudos feed"))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Kudos:    kudos.ToResponseList(feed),
+		})
+	}
+}
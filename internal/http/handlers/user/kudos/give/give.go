@@ -0,0 +1,92 @@
+package give
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/kudos"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	kudosservice "github.com/k6mil6/hackathon-game-backend/internal/service/kudos"
+	"log/slog"
+	"net/http"
+)
+
+type Request struct {
+	ReceiverID int    `json:"receiver_id"`
+	Amount     int    `json:"amount"`
+	Message    string `json:"message"`
+}
+
+type Response struct {
+	resp.Response
+	Kudos kudos.ResponseKudos `json:"kudos"`
+}
+
+func New(ctx context.Context, log *slog.Logger, kudosService httpserver.Kudos) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.kudos.give.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		given, err := kudosService.Give(ctx, userID, req.ReceiverID, req.Amount, req.Message)
+		if err != nil {
+			switch {
+			case errors.Is(err, kudosservice.ErrReceiverNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, kudosservice.ErrAllowanceExceeded):
+				w.WriteHeader(http.StatusConflict)
+			case errors.Is(err, kudosservice.ErrSelfKudos),
+				errors.Is(err, kudosservice.ErrInvalidAmount),
+				errors.Is(err, kudosservice.ErrEmptyMessage),
+				errors.Is(err, kudosservice.ErrMessageTooLong):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to give kudos", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to give kudos"))
+
+				return
+			}
+
+			log.Error("failed to give kudos", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Kudos:    kudos.ToResponse(given),
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package kudos
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"time"
+)
+
+type ResponseKudos struct {
+	ID           int       `json:"id"`
+	SenderID     int       `json:"sender_id"`
+	SenderName   string    `json:"sender_name"`
+	ReceiverID   int       `json:"receiver_id"`
+	ReceiverName string    `json:"receiver_name"`
+	Amount       int       `json:"amount"`
+	Coins        float64   `json:"coins"`
+	Message      string    `json:"message"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func ToResponse(kudos model.Kudos) ResponseKudos {
+	return ResponseKudos{
+		ID:           kudos.ID,
+		SenderID:     kudos.SenderID,
+		SenderName:   kudos.SenderName,
+		ReceiverID:   kudos.ReceiverID,
+		ReceiverName: kudos.ReceiverName,
+		Amount:       kudos.Amount,
+		Coins:        kudos.Coins,
+		Message:      kudos.Message,
+		CreatedAt:    kudos.CreatedAt,
+	}
+}
+
+func ToResponseList(kudos []model.Kudos) []ResponseKudos {
+	kudosRes := make([]ResponseKudos, 0, len(kudos))
+
+	for _, k := range kudos {
+		kudosRes = append(kudosRes, ToResponse(k))
+	}
+
+	return kudosRes
+}
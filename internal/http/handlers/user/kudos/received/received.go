@@ -0,0 +1,103 @@
+package received
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/kudos"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/request"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	kudosservice "github.com/k6mil6/hackathon-game-backend/internal/service/kudos"
+	"log/slog"
+	"net/http"
+)
+
+type Response struct {
+	resp.Response
+	Kudos []kudos.ResponseKudos `json:"kudos"`
+}
+
+func New(ctx context.Context, log *slog.Logger, kudosService httpserver.Kudos) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.kudos.received.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		userID, err := request.QueryInt(r, "user_id")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse user_id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		// without user_id the caller sees their own kudos
+		if userID == 0 {
+			userID, err = identity.GetID(r.Context())
+			if err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+				return
+			}
+		}
+
+		limit, err := request.QueryInt(r, "limit")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse limit", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		offset, err := request.QueryInt(r, "offset")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse offset", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		received, err := kudosService.GetReceived(ctx, userID, limit, offset)
+		if err != nil {
+			if errors.Is(err, kudosservice.ErrInvalidOffset) {
+				w.WriteHeader(http.StatusBadRequest)
+
+				log.Error("invalid offset", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error(err.Error()))
+
+				return
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get received kudos", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get received kudos"))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Kudos:    kudos.ToResponseList(received),
+		})
+	}
+}
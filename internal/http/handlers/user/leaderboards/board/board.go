@@ -0,0 +1,73 @@
+package board
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/leaderboards"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/request"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	leaderboardsservice "github.com/k6mil6/hackathon-game-backend/internal/service/leaderboards"
+	"log/slog"
+	"net/http"
+)
+
+type Response struct {
+	resp.Response
+	Entries []leaderboards.ResponseEntry `json:"entries"`
+}
+
+func New(ctx context.Context, log *slog.Logger, leaderboardsService httpserver.Leaderboards) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.leaderboards.board.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		filter, err := request.ParseLeaderboardFilter(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse filter", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		entries, err := leaderboardsService.Get(ctx, filter)
+		if err != nil {
+			switch {
+			case errors.Is(err, leaderboardsservice.ErrUnknownMetric),
+				errors.Is(err, leaderboardsservice.ErrGroupNotSupported),
+				errors.Is(err, leaderboardsservice.ErrInvalidOffset):
+				w.WriteHeader(http.StatusBadRequest)
+			case errors.Is(err, leaderboardsservice.ErrNoActiveSeason):
+				w.WriteHeader(http.StatusNotFound)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to get leaderboard", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to get leaderboard"))
+
+				return
+			}
+
+			log.Error("failed to get leaderboard", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Entries:  leaderboards.ToResponseList(entries),
+		})
+	}
+}
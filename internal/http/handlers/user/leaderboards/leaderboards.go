@@ -0,0 +1,29 @@
+package leaderboards
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+)
+
+type ResponseEntry struct {
+	Rank     int     `json:"rank"`
+	UserID   int     `json:"user_id"`
+	Username string  `json:"username"`
+	ClassID  int     `json:"class_id"`
+	Score    float64 `json:"score"`
+}
+
+func ToResponseList(entries []model.LeaderboardEntry) []ResponseEntry {
+	entriesRes := make([]ResponseEntry, 0, len(entries))
+
+	for _, entry := range entries {
+		entriesRes = append(entriesRes, ResponseEntry{
+			Rank:     entry.Rank,
+			UserID:   entry.UserID,
+			Username: entry.Username,
+			ClassID:  entry.ClassID,
+			Score:    entry.Score,
+		})
+	}
+
+	return entriesRes
+}
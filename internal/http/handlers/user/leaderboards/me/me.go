@@ -0,0 +1,106 @@
+package me
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/leaderboards"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/request"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	leaderboardsservice "github.com/k6mil6/hackathon-game-backend/internal/service/leaderboards"
+	"log/slog"
+	"net/http"
+)
+
+type Response struct {
+	resp.Response
+	Rank    int                          `json:"rank"`
+	Score   float64                      `json:"score"`
+	Entries []leaderboards.ResponseEntry `json:"entries"`
+}
+
+func New(ctx context.Context, log *slog.Logger, leaderboardsService httpserver.Leaderboards) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.leaderboards.me.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		filter, err := request.ParseLeaderboardFilter(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse filter", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		neighbours, err := request.QueryInt(r, "neighbours")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse neighbours", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		entries, err := leaderboardsService.GetAround(ctx, userID, filter, neighbours)
+		if err != nil {
+			switch {
+			case errors.Is(err, leaderboardsservice.ErrUnknownMetric),
+				errors.Is(err, leaderboardsservice.ErrGroupNotSupported),
+				errors.Is(err, leaderboardsservice.ErrInvalidNeighbours):
+				w.WriteHeader(http.StatusBadRequest)
+			case errors.Is(err, leaderboardsservice.ErrNotRanked),
+				errors.Is(err, leaderboardsservice.ErrNoActiveSeason):
+				w.WriteHeader(http.StatusNotFound)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to get rank", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to get rank"))
+
+				return
+			}
+
+			log.Error("failed to get rank", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		res := Response{
+			Response: resp.OK(),
+			Entries:  leaderboards.ToResponseList(entries),
+		}
+
+		for _, entry := range entries {
+			if entry.UserID == userID {
+				res.Rank = entry.Rank
+				res.Score = entry.Score
+			}
+		}
+
+		render.JSON(w, r, res)
+	}
+}
@@ -0,0 +1,98 @@
+package level
+
+import (
+	"context"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// levelUpsLimit is how many of the latest level-ups are shown next to the progress.
+const levelUpsLimit = 10
+
+type Response struct {
+	resp.Response
+	XP          int               `json:"xp"`
+	Level       int               `json:"level"`
+	LevelXP     int               `json:"level_xp"`
+	NextLevelXP int               `json:"next_level_xp,omitempty"`
+	LevelUps    []ResponseLevelUp `json:"level_ups"`
+}
+
+type ResponseLevelUp struct {
+	Level     int       `json:"level"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func New(ctx context.Context, log *slog.Logger, levels httpserver.Levels) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.level.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		progress, err := levels.GetProgress(ctx, userID)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get level progress", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get level progress"))
+
+			return
+		}
+
+		levelUps, err := levels.GetLevelUps(ctx, userID, levelUpsLimit)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get level ups", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get level ups"))
+
+			return
+		}
+
+		log.Info("response sent")
+
+		responseOK(w, r, progress, levelUps)
+	}
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, progress model.LevelProgress, levelUps []model.LevelUp) {
+	levelUpsRes := make([]ResponseLevelUp, 0, len(levelUps))
+
+	for _, levelUp := range levelUps {
+		levelUpsRes = append(levelUpsRes, ResponseLevelUp{
+			Level:     levelUp.Level,
+			CreatedAt: levelUp.CreatedAt,
+		})
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, Response{
+		Response:    resp.OK(),
+		XP:          progress.XP,
+		Level:       progress.Level,
+		LevelXP:     progress.LevelXP,
+		NextLevelXP: progress.NextLevelXP,
+		LevelUps:    levelUpsRes,
+	})
+}
@@ -0,0 +1,97 @@
+package all
+
+import (
+	"context"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/request"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type Response struct {
+	resp.Response
+	Notifications []ResponseNotification `json:"notifications"`
+}
+
+type ResponseNotification struct {
+	ID        int        `json:"id"`
+	TypeID    int        `json:"type_id"`
+	Message   string     `json:"message"`
+	TaskID    int        `json:"task_id,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+}
+
+func New(ctx context.Context, log *slog.Logger, notifications httpserver.Notifications) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.notifications.all.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get user ID"))
+
+			return
+		}
+
+		limit, err := request.QueryInt(r, "limit")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse limit", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		unreadOnly := r.URL.Query().Get("unread") == "true"
+
+		notifications, err := notifications.GetAllUserNotifications(ctx, userID, unreadOnly, limit)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get notifications", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get notifications"))
+
+			return
+		}
+
+		notificationsRes := make([]ResponseNotification, 0, len(notifications))
+
+		for _, notification := range notifications {
+			var readAt *time.Time
+			if !notification.ReadAt.IsZero() {
+				readAt = &notification.ReadAt
+			}
+
+			notificationsRes = append(notificationsRes, ResponseNotification{
+				ID:        notification.ID,
+				TypeID:    notification.TypeID,
+				Message:   notification.Message,
+				TaskID:    notification.TaskID,
+				CreatedAt: notification.CreatedAt,
+				ReadAt:    readAt,
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response:      resp.OK(),
+			Notifications: notificationsRes,
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package read
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	notificationsservice "github.com/k6mil6/hackathon-game-backend/internal/service/notifications"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Response struct {
+	resp.Response
+}
+
+func New(ctx context.Context, log *slog.Logger, notifications httpserver.Notifications) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.notifications.read.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		notificationID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get user ID"))
+
+			return
+		}
+
+		if err := notifications.MarkAsRead(ctx, notificationID, userID); err != nil {
+			if errors.Is(err, notificationsservice.ErrNotificationNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+
+				log.Error("notification not found", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("notification not found"))
+
+				return
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to mark notification as read", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to mark notification as read"))
+
+			return
+		}
+
+		render.JSON(w, r, Response{resp.OK()})
+	}
+}
@@ -0,0 +1,81 @@
+package perks
+
+import (
+	"context"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"log/slog"
+	"net/http"
+)
+
+type Response struct {
+	resp.Response
+	Perks []ResponsePerk `json:"perks"`
+}
+
+type ResponsePerk struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	MinLevel    int     `json:"min_level"`
+	Unlocked    bool    `json:"unlocked"`
+	Strength    float64 `json:"strength"`
+}
+
+func New(ctx context.Context, log *slog.Logger, perks httpserver.Perks) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.perks.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		list, err := perks.GetUserPerks(ctx, userID)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get perks", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get perks"))
+
+			return
+		}
+
+		log.Info("response sent")
+
+		responseOK(w, r, list)
+	}
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, list []model.Perk) {
+	perksRes := make([]ResponsePerk, 0, len(list))
+
+	for _, perk := range list {
+		perksRes = append(perksRes, ResponsePerk{
+			Name:        perk.Name,
+			Description: perk.Description,
+			MinLevel:    perk.MinLevel,
+			Unlocked:    perk.Unlocked,
+			Strength:    perk.Strength,
+		})
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, Response{
+		Response: resp.OK(),
+		Perks:    perksRes,
+	})
+}
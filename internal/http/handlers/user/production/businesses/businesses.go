@@ -0,0 +1,44 @@
+package businesses
+
+import (
+	"context"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/production"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"log/slog"
+	"net/http"
+)
+
+type Response struct {
+	resp.Response
+	Businesses []production.ResponseBusiness `json:"businesses"`
+}
+
+func New(ctx context.Context, log *slog.Logger, productionService httpserver.Production) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.production.businesses.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		businesses, err := productionService.GetBusinesses(ctx)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get businesses", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get businesses"))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response:   resp.OK(),
+			Businesses: production.ToBusinessResponses(businesses),
+		})
+	}
+}
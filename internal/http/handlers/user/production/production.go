@@ -0,0 +1,126 @@
+package production
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"time"
+)
+
+type ResponseBusiness struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	TypeID     int        `json:"type_id"`
+	TypeName   string     `json:"type_name"`
+	Price      float64    `json:"price"`
+	OwnerID    int        `json:"owner_id,omitempty"`
+	RecipeID   int        `json:"recipe_id,omitempty"`
+	ProducedAt *time.Time `json:"produced_at,omitempty"`
+}
+
+type ResponseRecipe struct {
+	ID              int                  `json:"id"`
+	BusinessTypeID  int                  `json:"business_type_id"`
+	Name            string               `json:"name"`
+	Output          ResponseRecipeItem   `json:"output"`
+	Inputs          []ResponseRecipeItem `json:"inputs,omitempty"`
+	DurationMinutes int                  `json:"duration_minutes"`
+}
+
+type ResponseRecipeItem struct {
+	ResourceID int    `json:"resource_id"`
+	Name       string `json:"name"`
+	Quantity   int    `json:"quantity"`
+}
+
+type ResponseInventory struct {
+	Items    []ResponseInventoryItem `json:"items"`
+	Used     int                     `json:"used"`
+	Capacity int                     `json:"capacity"`
+}
+
+type ResponseInventoryItem struct {
+	ResourceID int     `json:"resource_id"`
+	Name       string  `json:"name"`
+	Price      float64 `json:"price"`
+	Nutrition  int     `json:"nutrition,omitempty"`
+	Joy        int     `json:"joy,omitempty"`
+	Quantity   int     `json:"quantity"`
+}
+
+func ToBusinessResponse(business model.Business) ResponseBusiness {
+	res := ResponseBusiness{
+		ID:       business.ID,
+		Name:     business.Name,
+		TypeID:   business.TypeID,
+		TypeName: business.TypeName,
+		Price:    business.Price,
+		OwnerID:  business.OwnerID,
+		RecipeID: business.RecipeID,
+	}
+
+	if !business.ProducedAt.IsZero() {
+		res.ProducedAt = &business.ProducedAt
+	}
+
+	return res
+}
+
+func ToBusinessResponses(businesses []model.Business) []ResponseBusiness {
+	businessesRes := make([]ResponseBusiness, 0, len(businesses))
+
+	for _, business := range businesses {
+		businessesRes = append(businessesRes, ToBusinessResponse(business))
+	}
+
+	return businessesRes
+}
+
+func ToRecipeResponses(recipes []model.Recipe) []ResponseRecipe {
+	recipesRes := make([]ResponseRecipe, 0, len(recipes))
+
+	for _, recipe := range recipes {
+		res := ResponseRecipe{
+			ID:              recipe.ID,
+			BusinessTypeID:  recipe.BusinessTypeID,
+			Name:            recipe.Name,
+			Output:          toItemResponse(recipe.Output),
+			DurationMinutes: int(recipe.Duration.Minutes()),
+		}
+
+		for _, input := range recipe.Inputs {
+			res.Inputs = append(res.Inputs, toItemResponse(input))
+		}
+
+		recipesRes = append(recipesRes, res)
+	}
+
+	return recipesRes
+}
+
+func ToInventoryResponse(inventory model.Inventory) ResponseInventory {
+	res := ResponseInventory{
+		Items:    make([]ResponseInventoryItem, 0, len(inventory.Items)),
+		Used:     inventory.Used,
+		Capacity: inventory.Capacity,
+	}
+
+	for _, item := range inventory.Items {
+		res.Items = append(res.Items, ResponseInventoryItem{
+			ResourceID: item.ResourceID,
+			Name:       item.Name,
+			Price:      item.Price,
+			Nutrition:  item.Nutrition,
+			Joy:        item.Joy,
+			Quantity:   item.Quantity,
+		})
+	}
+
+	return res
+}
+
+func toItemResponse(item model.RecipeItem) ResponseRecipeItem {
+	return ResponseRecipeItem{
+		ResourceID: item.ResourceID,
+		Name:       item.Name,
+		Quantity:   item.Quantity,
+	}
+}
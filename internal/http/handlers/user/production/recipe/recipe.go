@@ -0,0 +1,111 @@
+package recipe
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/production"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	productionservice "github.com/k6mil6/hackathon-game-backend/internal/service/production"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Request struct {
+	RecipeID int `json:"recipe_id"`
+}
+
+type Response struct {
+	resp.Response
+	Business production.ResponseBusiness `json:"business"`
+}
+
+func New(ctx context.Context, log *slog.Logger, productionService httpserver.Production) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.production.recipe.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		businessID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		business, err := productionService.SetRecipe(ctx, userID, businessID, req.RecipeID)
+		if err != nil {
+			switch {
+			case errors.Is(err, productionservice.ErrRecipeNotFound):
+				w.WriteHeader(http.StatusBadRequest)
+			case errors.Is(err, productionservice.ErrNotOwner):
+				w.WriteHeader(http.StatusForbidden)
+			case errors.Is(err, productionservice.ErrBusinessNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to set recipe", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to set recipe"))
+
+				return
+			}
+
+			log.Error("failed to set recipe", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Business: production.ToBusinessResponse(business),
+		})
+	}
+}
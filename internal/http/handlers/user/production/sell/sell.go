@@ -0,0 +1,92 @@
+package sell
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	productionservice "github.com/k6mil6/hackathon-game-backend/internal/service/production"
+	"log/slog"
+	"net/http"
+)
+
+type Request struct {
+	ResourceID int `json:"resource_id"`
+	Quantity   int `json:"quantity"`
+}
+
+type Response struct {
+	resp.Response
+	ResourceID int     `json:"resource_id"`
+	Quantity   int     `json:"quantity"`
+	Amount     float64 `json:"amount"`
+}
+
+func New(ctx context.Context, log *slog.Logger, productionService httpserver.Production) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.production.sell.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		sale, err := productionService.Sell(ctx, userID, req.ResourceID, req.Quantity)
+		if err != nil {
+			switch {
+			case errors.Is(err, productionservice.ErrInvalidQuantity):
+				w.WriteHeader(http.StatusBadRequest)
+			case errors.Is(err, productionservice.ErrResourceNotFound),
+				errors.Is(err, productionservice.ErrUserNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, productionservice.ErrNotEnoughResources):
+				w.WriteHeader(http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to sell resource", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to sell resource"))
+
+				return
+			}
+
+			log.Error("failed to sell resource", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response:   resp.OK(),
+			ResourceID: sale.ResourceID,
+			Quantity:   sale.Quantity,
+			Amount:     sale.Amount,
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/streaks"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	usersservice "github.com/k6mil6/hackathon-game-backend/internal/service/users"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type Response struct {
+	resp.Response
+	ID           int                    `json:"id"`
+	Username     string                 `json:"username"`
+	Email        string                 `json:"email,omitempty"`
+	ClassID      int                    `json:"class_id"`
+	Balance      float64                `json:"balance"`
+	XP           int                    `json:"xp"`
+	Level        int                    `json:"level"`
+	RegisteredAt time.Time              `json:"registered_at"`
+	Streak       streaks.ResponseStreak `json:"streak"`
+}
+
+func New(ctx context.Context, log *slog.Logger, users httpserver.Users, streaksService httpserver.Streaks) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.profile.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		user, err := users.GetProfile(ctx, userID)
+		if err != nil {
+			if errors.Is(err, usersservice.ErrUserNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+
+				log.Error("user not found", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error(err.Error()))
+
+				return
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get profile", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get profile"))
+
+			return
+		}
+
+		streak, checkedIn, nextBonus, err := streaksService.Get(ctx, userID)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get streak", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get streak"))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response:     resp.OK(),
+			ID:           user.ID,
+			Username:     user.Username,
+			Email:        user.Email,
+			ClassID:      user.ClassID,
+			Balance:      user.Balance,
+			XP:           user.XP,
+			Level:        user.Level,
+			RegisteredAt: user.RegisteredAt,
+			Streak:       streaks.ToResponse(streak, checkedIn, nextBonus),
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package quests
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"time"
+)
+
+const (
+	StatusNotStarted = "not_started"
+	StatusStarted    = "started"
+	StatusCompleted  = "completed"
+	StepLocked       = "locked"
+	StepUnlocked     = "unlocked"
+	StepCompleted    = "completed"
+)
+
+type ResponseQuest struct {
+	ID          int            `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Bonus       float64        `json:"bonus"`
+	Status      string         `json:"status"`
+	StartedAt   *time.Time     `json:"started_at,omitempty"`
+	CompletedAt *time.Time     `json:"completed_at,omitempty"`
+	Steps       []ResponseStep `json:"steps"`
+}
+
+// ResponseStep is a quest step with the progress of the user in it, TaskID is set once the step is unlocked.
+type ResponseStep struct {
+	Position    int     `json:"position"`
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Amount      float64 `json:"amount"`
+	Requires    []int   `json:"requires,omitempty"`
+	Status      string  `json:"status"`
+	TaskID      int     `json:"task_id,omitempty"`
+}
+
+func ToResponse(quest model.UserQuest) ResponseQuest {
+	res := ResponseQuest{
+		ID:          quest.Quest.ID,
+		Name:        quest.Quest.Name,
+		Description: quest.Quest.Description,
+		Bonus:       quest.Quest.Bonus,
+		Status:      StatusNotStarted,
+		Steps:       make([]ResponseStep, 0, len(quest.Quest.Steps)),
+	}
+
+	if !quest.Progress.StartedAt.IsZero() {
+		res.Status = StatusStarted
+		res.StartedAt = &quest.Progress.StartedAt
+	}
+
+	if !quest.Progress.CompletedAt.IsZero() {
+		res.Status = StatusCompleted
+		res.CompletedAt = &quest.Progress.CompletedAt
+	}
+
+	progress := make(map[int]model.QuestStepProgress, len(quest.Progress.Steps))
+	for _, step := range quest.Progress.Steps {
+		progress[step.Position] = step
+	}
+
+	for _, step := range quest.Quest.Steps {
+		stepRes := ResponseStep{
+			Position:    step.Position,
+			Name:        step.Name,
+			Description: step.Description,
+			Amount:      step.Amount,
+			Requires:    step.Requires,
+			Status:      StepLocked,
+		}
+
+		if p, ok := progress[step.Position]; ok {
+			stepRes.Status = StepUnlocked
+			stepRes.TaskID = p.TaskID
+
+			if !p.CompletedAt.IsZero() {
+				stepRes.Status = StepCompleted
+			}
+		}
+
+		res.Steps = append(res.Steps, stepRes)
+	}
+
+	return res
+}
+
+func ToResponses(quests []model.UserQuest) []ResponseQuest {
+	questsRes := make([]ResponseQuest, 0, len(quests))
+
+	for _, quest := range quests {
+		questsRes = append(questsRes, ToResponse(quest))
+	}
+
+	return questsRes
+}
@@ -0,0 +1,104 @@
+package start
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/quests"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	questsservice "github.com/k6mil6/hackathon-game-backend/internal/service/quests"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Response struct {
+	resp.Response
+	Quest quests.ResponseQuest `json:"quest"`
+}
+
+func New(ctx context.Context, log *slog.Logger, questsService httpserver.Quests) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.quests.start.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		questID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+		if _, err := questsService.Start(ctx, userID, questID); err != nil {
+			switch {
+			case errors.Is(err, questsservice.ErrQuestNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, questsservice.ErrAlreadyStarted),
+				errors.Is(err, questsservice.ErrBudgetExceeded):
+				w.WriteHeader(http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to start quest", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to start quest"))
+
+				return
+			}
+
+			log.Error("failed to start quest", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		// the quest is returned as the user now sees it, with the unlocked steps
+		quest, err := questsService.Get(ctx, userID, questID)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get quest", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get quest"))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Quest:    quests.ToResponse(quest),
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package quizzes
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"time"
+)
+
+type ResponseQuiz struct {
+	ID          int                `json:"id"`
+	TypeID      int                `json:"type_id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Amount      float64            `json:"amount"`
+	PassScore   int                `json:"pass_score,omitempty"`
+	ClosesAt    *time.Time         `json:"closes_at,omitempty"`
+	ClosedAt    *time.Time         `json:"closed_at,omitempty"`
+	Submitted   bool               `json:"submitted"`
+	Questions   []ResponseQuestion `json:"questions,omitempty"`
+}
+
+type ResponseQuestion struct {
+	Position int      `json:"position"`
+	KindID   int      `json:"kind_id"`
+	Text     string   `json:"text"`
+	Options  []string `json:"options,omitempty"`
+	Points   int      `json:"points"`
+}
+
+type ResponseSubmission struct {
+	QuizID      int              `json:"quiz_id"`
+	Score       float64          `json:"score"`
+	Passed      bool             `json:"passed"`
+	Reward      float64          `json:"reward"`
+	SubmittedAt time.Time        `json:"submitted_at"`
+	Answers     []ResponseAnswer `json:"answers"`
+}
+
+type ResponseAnswer struct {
+	Position int      `json:"position"`
+	Options  []int    `json:"options,omitempty"`
+	Number   *float64 `json:"number,omitempty"`
+	Text     string   `json:"text,omitempty"`
+	Correct  bool     `json:"correct"`
+}
+
+func ToResponse(quiz model.Quiz) ResponseQuiz {
+	res := ResponseQuiz{
+		ID:          quiz.ID,
+		TypeID:      quiz.TypeID,
+		Name:        quiz.Name,
+		Description: quiz.Description,
+		Amount:      quiz.Amount,
+		PassScore:   quiz.PassScore,
+		Submitted:   quiz.Submitted,
+	}
+
+	if !quiz.ClosesAt.IsZero() {
+		res.ClosesAt = &quiz.ClosesAt
+	}
+
+	if !quiz.ClosedAt.IsZero() {
+		res.ClosedAt = &quiz.ClosedAt
+	}
+
+	for _, question := range quiz.Questions {
+		res.Questions = append(res.Questions, ResponseQuestion{
+			Position: question.Position,
+			KindID:   question.KindID,
+			Text:     question.Text,
+			Options:  question.Options,
+			Points:   question.Points,
+		})
+	}
+
+	return res
+}
+
+func ToResponses(quizzes []model.Quiz) []ResponseQuiz {
+	quizzesRes := make([]ResponseQuiz, 0, len(quizzes))
+
+	for _, quiz := range quizzes {
+		quizzesRes = append(quizzesRes, ToResponse(quiz))
+	}
+
+	return quizzesRes
+}
+
+func ToSubmissionResponse(submission model.QuizSubmission) ResponseSubmission {
+	res := ResponseSubmission{
+		QuizID:      submission.QuizID,
+		Score:       submission.Score,
+		Passed:      submission.Passed,
+		Reward:      submission.Reward,
+		SubmittedAt: submission.SubmittedAt,
+		Answers:     make([]ResponseAnswer, 0, len(submission.Answers)),
+	}
+
+	for _, answer := range submission.Answers {
+		res.Answers = append(res.Answers, ResponseAnswer{
+			Position: answer.Position,
+			Options:  answer.Options,
+			Number:   answer.Number,
+			Text:     answer.Text,
+			Correct:  answer.Correct,
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,143 @@
+package submit
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/quizzes"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	quizzeslib "github.com/k6mil6/hackathon-game-backend/internal/lib/quizzes"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	quizzesservice "github.com/k6mil6/hackathon-game-backend/internal/service/quizzes"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// Request holds one answer per question: option indexes from 0 for choice questions,
+// a number for numeric ones and a text for short text ones.
+type Request struct {
+	Answers []RequestAnswer `json:"answers"`
+}
+
+type RequestAnswer struct {
+	Position int      `json:"position"`
+	Options  []int    `json:"options,omitempty"`
+	Number   *float64 `json:"number,omitempty"`
+	Text     string   `json:"text,omitempty"`
+}
+
+func (r Request) ToModel() []model.QuizAnswer {
+	answers := make([]model.QuizAnswer, 0, len(r.Answers))
+	for _, answer := range r.Answers {
+		answers = append(answers, model.QuizAnswer{
+			Position: answer.Position,
+			Options:  answer.Options,
+			Number:   answer.Number,
+			Text:     answer.Text,
+		})
+	}
+
+	return answers
+}
+
+type Response struct {
+	resp.Response
+	Submission quizzes.ResponseSubmission `json:"submission"`
+}
+
+func New(ctx context.Context, log *slog.Logger, quizzesService httpserver.Quizzes) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.quizzes.submit.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		quizID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		submission, err := quizzesService.Submit(ctx, quizID, userID, req.ToModel())
+		if err != nil {
+			switch {
+			case errors.Is(err, quizzesservice.ErrTextTooLong),
+				errors.Is(err, quizzeslib.ErrUnansweredQuestion),
+				errors.Is(err, quizzeslib.ErrUnknownQuestion),
+				errors.Is(err, quizzeslib.ErrDuplicateAnswer),
+				errors.Is(err, quizzeslib.ErrInvalidAnswer):
+				w.WriteHeader(http.StatusBadRequest)
+			case errors.Is(err, quizzesservice.ErrQuizNotFound),
+				errors.Is(err, quizzesservice.ErrUserNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, quizzesservice.ErrQuizClosed),
+				errors.Is(err, quizzesservice.ErrAlreadySubmitted),
+				errors.Is(err, quizzesservice.ErrBudgetExceeded):
+				w.WriteHeader(http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to submit quiz", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to submit quiz"))
+
+				return
+			}
+
+			log.Error("failed to submit quiz", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response:   resp.OK(),
+			Submission: quizzes.ToSubmissionResponse(submission),
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package buy
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/raffles"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	rafflesservice "github.com/k6mil6/hackathon-game-backend/internal/service/raffles"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Request struct {
+	Count int `json:"count"`
+}
+
+type Response struct {
+	resp.Response
+	Raffle raffles.ResponseRaffle `json:"raffle"`
+}
+
+func New(ctx context.Context, log *slog.Logger, rafflesService httpserver.Raffles) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.raffles.buy.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		raffleID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		raffle, err := rafflesService.BuyTickets(ctx, raffleID, userID, req.Count)
+		if err != nil {
+			switch {
+			case errors.Is(err, rafflesservice.ErrInvalidCount),
+				errors.Is(err, rafflesservice.ErrTooManyTickets):
+				w.WriteHeader(http.StatusBadRequest)
+			case errors.Is(err, rafflesservice.ErrRaffleNotFound),
+				errors.Is(err, rafflesservice.ErrUserNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, rafflesservice.ErrInsufficientFunds),
+				errors.Is(err, rafflesservice.ErrRaffleNotOpen),
+				errors.Is(err, rafflesservice.ErrSalesClosed),
+				errors.Is(err, rafflesservice.ErrTicketLimit):
+				w.WriteHeader(http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to buy tickets", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to buy tickets"))
+
+				return
+			}
+
+			log.Error("failed to buy tickets", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Raffle:   raffles.ToResponse(raffle),
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package raffles
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"time"
+)
+
+type ResponseRaffle struct {
+	ID                int        `json:"id"`
+	Name              string     `json:"name"`
+	Description       string     `json:"description,omitempty"`
+	TicketPrice       float64    `json:"ticket_price"`
+	MaxTicketsPerUser int        `json:"max_tickets_per_user"`
+	ItemID            int        `json:"item_id,omitempty"`
+	ItemName          string     `json:"item_name,omitempty"`
+	PrizeAmount       float64    `json:"prize_amount,omitempty"`
+	StatusID          int        `json:"status_id"`
+	Commitment        string     `json:"commitment"`
+	Seed              string     `json:"seed,omitempty"`
+	DrawAt            time.Time  `json:"draw_at"`
+	TicketsSold       int        `json:"tickets_sold"`
+	MyTickets         int        `json:"my_tickets"`
+	WinningNumber     int        `json:"winning_number,omitempty"`
+	WinnerID          int        `json:"winner_id,omitempty"`
+	WinnerName        string     `json:"winner_name,omitempty"`
+	ClosedAt          *time.Time `json:"closed_at,omitempty"`
+}
+
+type ResponseTicket struct {
+	Number    int       `json:"number"`
+	UserID    int       `json:"user_id"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func ToResponse(raffle model.Raffle) ResponseRaffle {
+	res := ResponseRaffle{
+		ID:                raffle.ID,
+		Name:              raffle.Name,
+		Description:       raffle.Description,
+		TicketPrice:       raffle.TicketPrice,
+		MaxTicketsPerUser: raffle.MaxTicketsPerUser,
+		ItemID:            raffle.ItemID,
+		ItemName:          raffle.ItemName,
+		PrizeAmount:       raffle.PrizeAmount,
+		StatusID:          raffle.StatusID,
+		Commitment:        raffle.Commitment,
+		Seed:              raffle.Seed,
+		DrawAt:            raffle.DrawAt,
+		TicketsSold:       raffle.TicketsSold,
+		MyTickets:         raffle.MyTickets,
+		WinningNumber:     raffle.WinningNumber,
+		WinnerID:          raffle.WinnerID,
+		WinnerName:        raffle.WinnerName,
+	}
+
+	if !raffle.ClosedAt.IsZero() {
+		res.ClosedAt = &raffle.ClosedAt
+	}
+
+	return res
+}
+
+func ToResponses(raffles []model.Raffle) []ResponseRaffle {
+	rafflesRes := make([]ResponseRaffle, 0, len(raffles))
+
+	for _, raffle := range raffles {
+		rafflesRes = append(rafflesRes, ToResponse(raffle))
+	}
+
+	return rafflesRes
+}
+
+func ToTicketResponses(tickets []model.RaffleTicket) []ResponseTicket {
+	ticketsRes := make([]ResponseTicket, 0, len(tickets))
+
+	for _, ticket := range tickets {
+		ticketsRes = append(ticketsRes, ResponseTicket{
+			Number:    ticket.Number,
+			UserID:    ticket.UserID,
+			Username:  ticket.Username,
+			CreatedAt: ticket.CreatedAt,
+		})
+	}
+
+	return ticketsRes
+}
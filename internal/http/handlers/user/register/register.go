@@ -2,9 +2,11 @@ package register
 
 import (
 	"context"
+	"errors"
 	"github.com/go-chi/render"
 	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
 	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	authservice "github.com/k6mil6/hackathon-game-backend/internal/service/auth"
 	"log/slog"
 	"net/http"
 )
@@ -75,6 +77,16 @@ func New(ctx context.Context, log *slog.Logger, auth httpserver.Auth, users http
 
 		id, err := auth.RegisterUser(ctx, req.Username, req.Password, req.ClassID)
 		if err != nil {
+			if errors.Is(err, authservice.ErrInvalidClass) {
+				w.WriteHeader(http.StatusBadRequest)
+
+				log.Error("invalid class", slog.Int("classID", req.ClassID))
+
+				render.JSON(w, r, resp.Error("invalid class_id"))
+
+				return
+			}
+
 			w.WriteHeader(http.StatusInternalServerError)
 
 			log.Error("error registering user:", err)
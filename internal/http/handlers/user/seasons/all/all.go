@@ -0,0 +1,52 @@
+package all
+
+import (
+	"context"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/seasons"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type Response struct {
+	resp.Response
+	Seasons []seasons.ResponseSeason `json:"seasons"`
+}
+
+func New(ctx context.Context, log *slog.Logger, seasonsService httpserver.Seasons) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.seasons.all.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		allSeasons, err := seasonsService.GetAll(ctx)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get seasons", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get seasons"))
+
+			return
+		}
+
+		now := time.Now()
+
+		seasonsRes := make([]seasons.ResponseSeason, 0, len(allSeasons))
+		for _, season := range allSeasons {
+			seasonsRes = append(seasonsRes, seasons.ToResponse(season, now))
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Seasons:  seasonsRes,
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package seasons
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"time"
+)
+
+const (
+	StatusUpcoming = "upcoming"
+	StatusActive   = "active"
+	StatusFinished = "finished"
+)
+
+type ResponseSeason struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at"`
+	FinalizedAt *time.Time `json:"finalized_at,omitempty"`
+}
+
+func ToResponse(season model.Season, now time.Time) ResponseSeason {
+	res := ResponseSeason{
+		ID:       season.ID,
+		Name:     season.Name,
+		Status:   StatusActive,
+		StartsAt: season.StartsAt,
+		EndsAt:   season.EndsAt,
+	}
+
+	switch {
+	case now.Before(season.StartsAt):
+		res.Status = StatusUpcoming
+	case !now.Before(season.EndsAt):
+		res.Status = StatusFinished
+	}
+
+	if !season.FinalizedAt.IsZero() {
+		res.FinalizedAt = &season.FinalizedAt
+	}
+
+	return res
+}
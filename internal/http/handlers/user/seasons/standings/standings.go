@@ -0,0 +1,130 @@
+package standings
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/seasons"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/request"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	seasonsservice "github.com/k6mil6/hackathon-game-backend/internal/service/seasons"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type Response struct {
+	resp.Response
+	Season    seasons.ResponseSeason `json:"season"`
+	Standings []ResponseStanding     `json:"standings"`
+}
+
+type ResponseStanding struct {
+	Rank     int     `json:"rank"`
+	UserID   int     `json:"user_id"`
+	Username string  `json:"username"`
+	ClassID  int     `json:"class_id"`
+	Score    float64 `json:"score"`
+	Prize    float64 `json:"prize,omitempty"`
+}
+
+func New(ctx context.Context, log *slog.Logger, seasonsService httpserver.Seasons) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.seasons.standings.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		seasonID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		limit, err := request.QueryInt(r, "limit")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse limit", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		offset, err := request.QueryInt(r, "offset")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse offset", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		season, standings, err := seasonsService.GetStandings(ctx, seasonID, limit, offset)
+		if err != nil {
+			switch {
+			case errors.Is(err, seasonsservice.ErrSeasonNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, seasonsservice.ErrInvalidOffset):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to get standings", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to get standings"))
+
+				return
+			}
+
+			log.Error("failed to get standings", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		standingsRes := make([]ResponseStanding, 0, len(standings))
+		for _, standing := range standings {
+			standingsRes = append(standingsRes, ResponseStanding{
+				Rank:     standing.Rank,
+				UserID:   standing.UserID,
+				Username: standing.Username,
+				ClassID:  standing.ClassID,
+				Score:    standing.Score,
+				Prize:    standing.Prize,
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response:  resp.OK(),
+			Season:    seasons.ToResponse(season, time.Now()),
+			Standings: standingsRes,
+		})
+	}
+}
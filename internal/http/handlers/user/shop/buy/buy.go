@@ -0,0 +1,101 @@
+package buy
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	shopservice "github.com/k6mil6/hackathon-game-backend/internal/service/shop"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type Response struct {
+	resp.Response
+	ID        int       `json:"id"`
+	ItemID    int       `json:"item_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func New(ctx context.Context, log *slog.Logger, shop httpserver.Shop) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.shop.buy.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		itemID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		purchase, err := shop.Buy(ctx, userID, itemID)
+		if err != nil {
+			switch {
+			case errors.Is(err, shopservice.ErrItemNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, shopservice.ErrLevelTooLow):
+				w.WriteHeader(http.StatusForbidden)
+			case errors.Is(err, shopservice.ErrItemSoldOut):
+				w.WriteHeader(http.StatusConflict)
+			case errors.Is(err, shopservice.ErrInsufficientFunds):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to buy item", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to buy item"))
+
+				return
+			}
+
+			log.Error("failed to buy item", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response:  resp.OK(),
+			ID:        purchase.ID,
+			ItemID:    purchase.ShopItemID,
+			CreatedAt: purchase.CreatedAt,
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package items
+
+import (
+	"context"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"log/slog"
+	"net/http"
+)
+
+type Response struct {
+	resp.Response
+	Items []ResponseItem `json:"items"`
+}
+
+type ResponseItem struct {
+	ID               int     `json:"id"`
+	Name             string  `json:"name"`
+	Description      string  `json:"description"`
+	Price            float64 `json:"price"`
+	InStock          int     `json:"in_stock"`
+	MinLevel         int     `json:"min_level,omitempty"`
+	StreakFreezes    int     `json:"streak_freezes,omitempty"`
+	ResourceID       int     `json:"resource_id,omitempty"`
+	ResourceQuantity int     `json:"resource_quantity,omitempty"`
+}
+
+func New(ctx context.Context, log *slog.Logger, shop httpserver.Shop) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.shop.items.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		items, err := shop.GetItems(ctx)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get items", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get items"))
+
+			return
+		}
+
+		itemsRes := make([]ResponseItem, 0, len(items))
+		for _, item := range items {
+			itemsRes = append(itemsRes, ResponseItem{
+				ID:               item.ID,
+				Name:             item.Name,
+				Description:      item.Description,
+				Price:            item.Price,
+				InStock:          item.InStock,
+				MinLevel:         item.MinLevel,
+				StreakFreezes:    item.StreakFreezes,
+				ResourceID:       item.ResourceID,
+				ResourceQuantity: item.ResourceQuantity,
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Items:    itemsRes,
+		})
+	}
+}
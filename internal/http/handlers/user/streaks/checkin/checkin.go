@@ -0,0 +1,68 @@
+package checkin
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/streaks"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	streaksservice "github.com/k6mil6/hackathon-game-backend/internal/service/streaks"
+	"log/slog"
+	"net/http"
+)
+
+type Response struct {
+	resp.Response
+	Bonus  float64                `json:"bonus"`
+	Streak streaks.ResponseStreak `json:"streak"`
+}
+
+func New(ctx context.Context, log *slog.Logger, streaksService httpserver.Streaks) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.streaks.checkin.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		checkIn, streak, err := streaksService.CheckIn(ctx, userID)
+		if err != nil {
+			if errors.Is(err, streaksservice.ErrAlreadyCheckedIn) {
+				w.WriteHeader(http.StatusConflict)
+
+				log.Error("already checked in", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error(err.Error()))
+
+				return
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to check in", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to check in"))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Bonus:    checkIn.Bonus,
+			Streak:   streaks.ToResponse(streak, true, 0),
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package streaks
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"time"
+)
+
+type ResponseStreak struct {
+	Current        int     `json:"current"`
+	Longest        int     `json:"longest"`
+	Freezes        int     `json:"freezes"`
+	LastActiveOn   string  `json:"last_active_on,omitempty"`
+	CheckedInToday bool    `json:"checked_in_today"`
+	NextBonus      float64 `json:"next_bonus,omitempty"`
+}
+
+func ToResponse(streak model.Streak, checkedIn bool, nextBonus float64) ResponseStreak {
+	res := ResponseStreak{
+		Current:        streak.Current,
+		Longest:        streak.Longest,
+		Freezes:        streak.Freezes,
+		CheckedInToday: checkedIn,
+		NextBonus:      nextBonus,
+	}
+
+	if !streak.LastActiveOn.IsZero() {
+		res.LastActiveOn = streak.LastActiveOn.Format(time.DateOnly)
+	}
+
+	return res
+}
@@ -2,10 +2,14 @@ package all
 
 import (
 	"context"
+	"errors"
 	"github.com/go-chi/render"
 	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
 	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/request"
 	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	taskservice "github.com/k6mil6/hackathon-game-backend/internal/service/tasks"
 	"log/slog"
 	"net/http"
 	"time"
@@ -13,19 +17,29 @@ import (
 
 type Response struct {
 	resp.Response
-	Tasks []ResponseTask `json:"tasks"`
+	Tasks      []ResponseTask `json:"tasks"`
+	Total      int            `json:"total"`
+	NextCursor string         `json:"next_cursor,omitempty"`
 }
 
 type ResponseTask struct {
-	ID        int       `json:"id"`
-	Name      string    `json:"name"`
-	Amount    float64   `json:"amount"`
-	CreatedAt time.Time `json:"created_at"`
+	ID           int        `json:"id"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description,omitempty"`
+	StatusID     int        `json:"status_id"`
+	Amount       float64    `json:"amount"`
+	CategoryID   int        `json:"category_id"`
+	ForGroupID   int        `json:"for_group_id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	CancelReason string     `json:"cancel_reason,omitempty"`
+	Deadline     *time.Time `json:"deadline,omitempty"`
+	XP           int        `json:"xp,omitempty"`
+	MinLevel     int        `json:"min_level,omitempty"`
 }
 
 func New(ctx context.Context, log *slog.Logger, tasks httpserver.Tasks) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		op := "handlers.admin.tasks.all.New"
+		op := "handlers.user.tasks.all.New"
 
 		log = log.With(
 			slog.String("op", op),
@@ -42,36 +56,80 @@ func New(ctx context.Context, log *slog.Logger, tasks httpserver.Tasks) http.Han
 			return
 		}
 
-		tasksRes := make([]ResponseTask, 0)
+		filter, err := request.ParseTaskFilter(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse filter", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		// users only see their own and shared tasks, so filtering by another user makes no sense
+		filter.UserID = 0
 
-		tasks, err := tasks.GetAllUserTasks(ctx, userID)
+		page, err := tasks.GetAllUserTasks(ctx, userID, filter)
 		if err != nil {
+			if errors.Is(err, taskservice.ErrInvalidFilter) {
+				w.WriteHeader(http.StatusBadRequest)
+
+				log.Error("invalid filter", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error(err.Error()))
+
+				return
+			}
+
 			w.WriteHeader(http.StatusInternalServerError)
 
 			log.Error("failed to get tasks", slog.String("error", err.Error()))
 
-			return
-		}
+			render.JSON(w, r, resp.Error("failed to get tasks"))
 
-		for _, task := range tasks {
-			tasksRes = append(tasksRes, ResponseTask{
-				ID:        task.ID,
-				Name:      task.Name,
-				Amount:    task.Amount,
-				CreatedAt: task.CreatedAt,
-			})
+			return
 		}
 
 		log.Info("response sent")
 
-		responseOK(w, r, tasksRes)
+		responseOK(w, r, page)
 	}
 }
 
-func responseOK(w http.ResponseWriter, r *http.Request, tasks []ResponseTask) {
+func responseOK(w http.ResponseWriter, r *http.Request, page model.TaskPage) {
+	tasksRes := make([]ResponseTask, 0, len(page.Tasks))
+
+	for _, task := range page.Tasks {
+		tasksRes = append(tasksRes, ResponseTask{
+			ID:           task.ID,
+			Name:         task.Name,
+			Description:  task.Description,
+			StatusID:     task.StatusID,
+			Amount:       task.Amount,
+			CategoryID:   task.CategoryID,
+			ForGroupID:   task.ForGroupID,
+			CreatedAt:    task.CreatedAt,
+			CancelReason: task.CancelReason,
+			Deadline:     deadline(task),
+			XP:           task.XP,
+			MinLevel:     task.MinLevel,
+		})
+	}
+
 	w.WriteHeader(http.StatusOK)
 	render.JSON(w, r, Response{
-		Response: resp.OK(),
-		Tasks:    tasks,
+		Response:   resp.OK(),
+		Tasks:      tasksRes,
+		Total:      page.Total,
+		NextCursor: page.NextCursor,
 	})
 }
+
+func deadline(task model.Task) *time.Time {
+	if task.Deadline.IsZero() {
+		return nil
+	}
+
+	return &task.Deadline
+}
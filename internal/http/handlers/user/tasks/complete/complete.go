@@ -2,11 +2,13 @@ package complete
 
 import (
 	"context"
+	"errors"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
 	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
 	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	taskservice "github.com/k6mil6/hackathon-game-backend/internal/service/tasks"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -60,6 +62,36 @@ func New(ctx context.Context, log *slog.Logger, tasks httpserver.Tasks) http.Han
 		}
 
 		if err := tasks.MarkAsWaitingForAcceptance(ctx, taskID, userID); err != nil {
+			if errors.Is(err, taskservice.ErrDeadlinePassed) {
+				w.WriteHeader(http.StatusBadRequest)
+
+				log.Error("task deadline has passed", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error(err.Error()))
+
+				return
+			}
+
+			if errors.Is(err, taskservice.ErrNotEnoughPermission) {
+				w.WriteHeader(http.StatusForbidden)
+
+				log.Error("task is not in progress by the user", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error(err.Error()))
+
+				return
+			}
+
+			if errors.Is(err, taskservice.ErrLevelTooLow) {
+				w.WriteHeader(http.StatusForbidden)
+
+				log.Error("level is too low", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error(err.Error()))
+
+				return
+			}
+
 			w.WriteHeader(http.StatusInternalServerError)
 
 			log.Error("failed to mark as waiting for acceptance", slog.String("error", err.Error()))
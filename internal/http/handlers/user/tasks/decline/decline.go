@@ -2,11 +2,13 @@ package decline
 
 import (
 	"context"
+	"errors"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
 	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
 	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	taskservice "github.com/k6mil6/hackathon-game-backend/internal/service/tasks"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -60,6 +62,16 @@ func New(ctx context.Context, log *slog.Logger, tasks httpserver.Tasks) http.Han
 		}
 
 		if err := tasks.MarkAsCancelled(ctx, taskID, userID); err != nil {
+			if errors.Is(err, taskservice.ErrTaskClosed) {
+				w.WriteHeader(http.StatusBadRequest)
+
+				log.Error("task is already closed", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error(err.Error()))
+
+				return
+			}
+
 			w.WriteHeader(http.StatusInternalServerError)
 
 			log.Error("failed to decline task", slog.String("error", err.Error()))
@@ -0,0 +1,150 @@
+package get
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	taskservice "github.com/k6mil6/hackathon-game-backend/internal/service/tasks"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type Response struct {
+	resp.Response
+	Task ResponseTask `json:"task"`
+}
+
+type ResponseTask struct {
+	ID           int        `json:"id"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description,omitempty"`
+	StatusID     int        `json:"status_id"`
+	Amount       float64    `json:"amount"`
+	Reward       float64    `json:"reward"`
+	CategoryID   int        `json:"category_id"`
+	ForGroupID   int        `json:"for_group_id"`
+	UserID       int        `json:"user_id,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	CancelReason string     `json:"cancel_reason,omitempty"`
+	Deadline     *time.Time `json:"deadline,omitempty"`
+	XP           int        `json:"xp,omitempty"`
+	MinLevel     int        `json:"min_level,omitempty"`
+}
+
+func New(ctx context.Context, log *slog.Logger, tasks httpserver.Tasks) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.tasks.get.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		taskID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		task, err := tasks.GetUserTask(ctx, taskID, userID)
+		if err != nil {
+			if errors.Is(err, taskservice.ErrTaskNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+
+				log.Error("task not found", slog.Int("taskID", taskID))
+
+				render.JSON(w, r, resp.Error("task not found"))
+
+				return
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get task", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get task"))
+
+			return
+		}
+
+		// the reward is shown for the requesting user, a foreign task pays its assignee by their own class
+		rewardTask := task
+		rewardTask.UserID = userID
+
+		reward, err := tasks.Reward(ctx, rewardTask)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to calculate reward", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to calculate reward"))
+
+			return
+		}
+
+		log.Info("response sent")
+
+		responseOK(w, r, task, reward)
+	}
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, task model.Task, reward float64) {
+	res := ResponseTask{
+		ID:           task.ID,
+		Name:         task.Name,
+		Description:  task.Description,
+		StatusID:     task.StatusID,
+		Amount:       task.Amount,
+		Reward:       reward,
+		CategoryID:   task.CategoryID,
+		ForGroupID:   task.ForGroupID,
+		UserID:       task.UserID,
+		CreatedAt:    task.CreatedAt,
+		CancelReason: task.CancelReason,
+		XP:           task.XP,
+		MinLevel:     task.MinLevel,
+	}
+
+	if !task.Deadline.IsZero() {
+		res.Deadline = &task.Deadline
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, Response{
+		Response: resp.OK(),
+		Task:     res,
+	})
+}
@@ -0,0 +1,101 @@
+package intercept
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/intercepts"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	interceptsservice "github.com/k6mil6/hackathon-game-backend/internal/service/intercepts"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Response struct {
+	resp.Response
+	Intercept intercepts.ResponseIntercept `json:"intercept"`
+}
+
+func New(ctx context.Context, log *slog.Logger, interceptsService httpserver.Intercepts) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.tasks.intercept.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		taskID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		intercept, err := interceptsService.Intercept(ctx, taskID, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, interceptsservice.ErrNotRacoon):
+				w.WriteHeader(http.StatusForbidden)
+			case errors.Is(err, interceptsservice.ErrTaskNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, interceptsservice.ErrTaskNotInterceptable):
+				w.WriteHeader(http.StatusConflict)
+			case errors.Is(err, interceptsservice.ErrCooldown), errors.Is(err, interceptsservice.ErrVictimProtected):
+				w.WriteHeader(http.StatusTooManyRequests)
+			case errors.Is(err, interceptsservice.ErrInsufficientFunds):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to intercept task", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to intercept task"))
+
+				return
+			}
+
+			log.Error("failed to intercept task", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		log.Info("intercept attempted", slog.Bool("success", intercept.Success))
+
+		render.JSON(w, r, Response{
+			Response:  resp.OK(),
+			Intercept: intercepts.ToResponse(intercept),
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package accept
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/teams"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	teamsservice "github.com/k6mil6/hackathon-game-backend/internal/service/teams"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Response struct {
+	resp.Response
+	Team teams.ResponseTeam `json:"team"`
+}
+
+func New(ctx context.Context, log *slog.Logger, teamsService httpserver.Teams) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.teams.accept.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		urlParam := chi.URLParam(r, "id")
+		if urlParam == "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("no id")
+
+			render.JSON(w, r, resp.Error("no id"))
+
+			return
+		}
+
+		invitationID, err := strconv.Atoi(urlParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse id", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to parse id"))
+
+			return
+		}
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		team, err := teamsService.AcceptInvitation(ctx, invitationID, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, teamsservice.ErrInvitationNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, teamsservice.ErrTeamFull),
+				errors.Is(err, teamsservice.ErrAlreadyInTeam):
+				w.WriteHeader(http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to accept invitation", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to accept invitation"))
+
+				return
+			}
+
+			log.Error("failed to accept invitation", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Team:     teams.ToResponse(team),
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package contribute
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/teams"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	teamsservice "github.com/k6mil6/hackathon-game-backend/internal/service/teams"
+	"log/slog"
+	"net/http"
+)
+
+type Request struct {
+	Amount float64 `json:"amount"`
+}
+
+type Response struct {
+	resp.Response
+	Entry teams.ResponseWalletEntry `json:"entry"`
+}
+
+func New(ctx context.Context, log *slog.Logger, teamsService httpserver.Teams) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.teams.contribute.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		entry, err := teamsService.Contribute(ctx, userID, req.Amount)
+		if err != nil {
+			switch {
+			case errors.Is(err, teamsservice.ErrNotInTeam):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, teamsservice.ErrInvalidAmount),
+				errors.Is(err, teamsservice.ErrInsufficientFunds):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to contribute", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to contribute"))
+
+				return
+			}
+
+			log.Error("failed to contribute", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Entry:    teams.ToResponseWalletEntry(entry),
+		})
+	}
+}
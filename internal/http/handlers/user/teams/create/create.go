@@ -0,0 +1,87 @@
+package create
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/handlers/user/teams"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	teamsservice "github.com/k6mil6/hackathon-game-backend/internal/service/teams"
+	"log/slog"
+	"net/http"
+)
+
+type Request struct {
+	Name string `json:"name"`
+}
+
+type Response struct {
+	resp.Response
+	Team teams.ResponseTeam `json:"team"`
+}
+
+func New(ctx context.Context, log *slog.Logger, teamsService httpserver.Teams) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.teams.create.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("error decoding JSON request", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("error decoding JSON request"))
+
+			return
+		}
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		team, err := teamsService.Create(ctx, userID, req.Name)
+		if err != nil {
+			switch {
+			case errors.Is(err, teamsservice.ErrNameTaken),
+				errors.Is(err, teamsservice.ErrAlreadyInTeam):
+				w.WriteHeader(http.StatusConflict)
+			case errors.Is(err, teamsservice.ErrInvalidName),
+				errors.Is(err, teamsservice.ErrNameTooLong):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to create team", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to create team"))
+
+				return
+			}
+
+			log.Error("failed to create team", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Team:     teams.ToResponse(team),
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/request"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	teamsservice "github.com/k6mil6/hackathon-game-backend/internal/service/teams"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type Response struct {
+	resp.Response
+	Entries []ResponseEntry `json:"entries"`
+}
+
+type ResponseEntry struct {
+	Rank    int     `json:"rank"`
+	TeamID  int     `json:"team_id"`
+	Name    string  `json:"name"`
+	Members int     `json:"members"`
+	Score   float64 `json:"score"`
+}
+
+func New(ctx context.Context, log *slog.Logger, teamsService httpserver.Teams) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.teams.leaderboard.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		metric := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("metric")))
+
+		limit, err := request.QueryInt(r, "limit")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse limit", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		offset, err := request.QueryInt(r, "offset")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse offset", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		entries, err := teamsService.GetLeaderboard(ctx, metric, limit, offset)
+		if err != nil {
+			switch {
+			case errors.Is(err, teamsservice.ErrUnknownMetric),
+				errors.Is(err, teamsservice.ErrInvalidOffset):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+
+				log.Error("failed to get team leaderboard", slog.String("error", err.Error()))
+
+				render.JSON(w, r, resp.Error("failed to get team leaderboard"))
+
+				return
+			}
+
+			log.Error("failed to get team leaderboard", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		entriesRes := make([]ResponseEntry, 0, len(entries))
+		for _, entry := range entries {
+			entriesRes = append(entriesRes, ResponseEntry(entry))
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Entries:  entriesRes,
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package teams
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	teamsstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/teams"
+	"time"
+)
+
+const (
+	RoleLeader = "leader"
+	RoleMember = "member"
+)
+
+const (
+	TaskStatusOpen      = "open"
+	TaskStatusCompleted = "waiting for acceptance"
+	TaskStatusAccepted  = "accepted"
+)
+
+type ResponseTeam struct {
+	ID         int       `json:"id"`
+	Name       string    `json:"name"`
+	LeaderID   int       `json:"leader_id"`
+	LeaderName string    `json:"leader_name"`
+	Members    int       `json:"members"`
+	Wallet     float64   `json:"wallet"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ResponseMember struct {
+	UserID   int       `json:"user_id"`
+	Username string    `json:"username"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type ResponseInvitation struct {
+	ID        int       `json:"id"`
+	TeamID    int       `json:"team_id"`
+	TeamName  string    `json:"team_name"`
+	InvitedBy int       `json:"invited_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ResponseWalletEntry struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	Username  string    `json:"username"`
+	Amount    float64   `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ResponseTask struct {
+	ID              int        `json:"id"`
+	Name            string     `json:"name"`
+	Description     string     `json:"description"`
+	Amount          float64    `json:"amount"`
+	RequiredMembers int        `json:"required_members"`
+	Submitted       int        `json:"submitted"`
+	SubmittedByMe   bool       `json:"submitted_by_me"`
+	Status          string     `json:"status"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	AcceptedAt      *time.Time `json:"accepted_at,omitempty"`
+}
+
+func ToResponse(team model.Team) ResponseTeam {
+	return ResponseTeam{
+		ID:         team.ID,
+		Name:       team.Name,
+		LeaderID:   team.LeaderID,
+		LeaderName: team.LeaderName,
+		Members:    team.Members,
+		Wallet:     team.Wallet,
+		CreatedAt:  team.CreatedAt,
+	}
+}
+
+func ToResponseMembers(members []model.TeamMember) []ResponseMember {
+	membersRes := make([]ResponseMember, 0, len(members))
+
+	for _, member := range members {
+		role := RoleMember
+		if member.RoleID == teamsstorage.LeaderRoleID {
+			role = RoleLeader
+		}
+
+		membersRes = append(membersRes, ResponseMember{
+			UserID:   member.UserID,
+			Username: member.Username,
+			Role:     role,
+			JoinedAt: member.JoinedAt,
+		})
+	}
+
+	return membersRes
+}
+
+func ToResponseInvitation(invitation model.TeamInvitation) ResponseInvitation {
+	return ResponseInvitation{
+		ID:        invitation.ID,
+		TeamID:    invitation.TeamID,
+		TeamName:  invitation.TeamName,
+		InvitedBy: invitation.InvitedBy,
+		CreatedAt: invitation.CreatedAt,
+	}
+}
+
+func ToResponseWalletEntry(entry model.TeamWalletEntry) ResponseWalletEntry {
+	return ResponseWalletEntry{
+		ID:        entry.ID,
+		UserID:    entry.UserID,
+		Username:  entry.Username,
+		Amount:    entry.Amount,
+		CreatedAt: entry.CreatedAt,
+	}
+}
+
+func ToResponseTask(progress model.TeamTaskProgress) ResponseTask {
+	res := ResponseTask{
+		ID:              progress.Task.ID,
+		Name:            progress.Task.Name,
+		Description:     progress.Task.Description,
+		Amount:          progress.Task.Amount,
+		RequiredMembers: progress.Task.RequiredMembers,
+		Submitted:       progress.Submitted,
+		SubmittedByMe:   progress.SubmittedByMe,
+		Status:          TaskStatusOpen,
+	}
+
+	if !progress.CompletedAt.IsZero() {
+		res.Status = TaskStatusCompleted
+		res.CompletedAt = &progress.CompletedAt
+	}
+
+	if !progress.AcceptedAt.IsZero() {
+		res.Status = TaskStatusAccepted
+		res.AcceptedAt = &progress.AcceptedAt
+	}
+
+	return res
+}
+
+func ToResponseInvitations(invitations []model.TeamInvitation) []ResponseInvitation {
+	invitationsRes := make([]ResponseInvitation, 0, len(invitations))
+
+	for _, invitation := range invitations {
+		invitationsRes = append(invitationsRes, ToResponseInvitation(invitation))
+	}
+
+	return invitationsRes
+}
+
+func ToResponseWallet(entries []model.TeamWalletEntry) []ResponseWalletEntry {
+	entriesRes := make([]ResponseWalletEntry, 0, len(entries))
+
+	for _, entry := range entries {
+		entriesRes = append(entriesRes, ToResponseWalletEntry(entry))
+	}
+
+	return entriesRes
+}
+
+func ToResponseTasks(tasks []model.TeamTaskProgress) []ResponseTask {
+	tasksRes := make([]ResponseTask, 0, len(tasks))
+
+	for _, task := range tasks {
+		tasksRes = append(tasksRes, ToResponseTask(task))
+	}
+
+	return tasksRes
+}
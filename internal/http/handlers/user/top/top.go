@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/go-chi/render"
 	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/request"
 	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
 	"log/slog"
 	"net/http"
@@ -15,6 +16,7 @@ type Response struct {
 }
 
 type ResponseUser struct {
+	Rank     int     `json:"rank"`
 	Username string  `json:"username"`
 	Balance  float64 `json:"balance"`
 }
@@ -29,7 +31,18 @@ func New(ctx context.Context, log *slog.Logger, users httpserver.Users) http.Han
 
 		log.Info("request received")
 
-		users, err := users.GetTopByBalance(ctx)
+		limit, err := request.QueryInt(r, "limit")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse limit", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		users, err := users.GetTopByBalance(ctx, limit)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 
@@ -40,8 +53,9 @@ func New(ctx context.Context, log *slog.Logger, users httpserver.Users) http.Han
 
 		usersRes := make([]ResponseUser, 0)
 
-		for _, user := range users {
+		for i, user := range users {
 			usersRes = append(usersRes, ResponseUser{
+				Rank:     i + 1,
 				Username: user.Username,
 				Balance:  user.Balance,
 			})
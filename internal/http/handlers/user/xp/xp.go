@@ -0,0 +1,86 @@
+package xp
+
+import (
+	"context"
+	"github.com/go-chi/render"
+	httpserver "github.com/k6mil6/hackathon-game-backend/internal/http"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/middleware/identity"
+	"github.com/k6mil6/hackathon-game-backend/internal/http/request"
+	resp "github.com/k6mil6/hackathon-game-backend/internal/http/response"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type Response struct {
+	resp.Response
+	Entries []ResponseEntry `json:"entries"`
+}
+
+type ResponseEntry struct {
+	ID        int       `json:"id"`
+	SourceID  int       `json:"source_id"`
+	TaskID    int       `json:"task_id,omitempty"`
+	Amount    int       `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func New(ctx context.Context, log *slog.Logger, levels httpserver.Levels) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := "handlers.user.xp.New"
+
+		log = log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("request received")
+
+		userID, err := identity.GetID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			log.Error("failed to get user ID", slog.String("error", err.Error()))
+
+			return
+		}
+
+		limit, err := request.QueryInt(r, "limit")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			log.Error("failed to parse limit", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		entries, err := levels.GetLedger(ctx, userID, limit)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			log.Error("failed to get xp ledger", slog.String("error", err.Error()))
+
+			render.JSON(w, r, resp.Error("failed to get xp ledger"))
+
+			return
+		}
+
+		entriesRes := make([]ResponseEntry, 0, len(entries))
+
+		for _, entry := range entries {
+			entriesRes = append(entriesRes, ResponseEntry{
+				ID:        entry.ID,
+				SourceID:  entry.SourceID,
+				TaskID:    entry.TaskID,
+				Amount:    entry.Amount,
+				CreatedAt: entry.CreatedAt,
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Entries:  entriesRes,
+		})
+	}
+}
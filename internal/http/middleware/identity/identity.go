@@ -32,7 +32,7 @@ func New(secret string) func(next http.Handler) http.Handler {
 				return
 			}
 
-			id, err := jwt.GetID(headerParts[1], secret)
+			id, role, err := jwt.GetIdentity(headerParts[1], secret)
 			if err != nil {
 				w.WriteHeader(http.StatusUnauthorized)
 				render.JSON(w, r, resp.Error(err.Error()))
@@ -41,6 +41,7 @@ func New(secret string) func(next http.Handler) http.Handler {
 
 			ctx := r.Context()
 			ctx = context.WithValue(ctx, "id", id)
+			ctx = context.WithValue(ctx, "role", role)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		}
 
@@ -54,3 +55,80 @@ func GetID(ctx context.Context) (int, error) {
 	}
 	return ctx.Value("id").(int), nil
 }
+
+func GetRole(ctx context.Context) (string, error) {
+	if ctx.Value("role") == nil {
+		return "", errors.New("role not found in context")
+	}
+	return ctx.Value("role").(string), nil
+}
+
+// RequireRole lets through only requests whose token carries the role. Ids of users and admins
+// come from separate sequences, so the role is what tells them apart.
+func RequireRole(role string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			got, err := GetRole(r.Context())
+			if err != nil || got != role {
+				w.WriteHeader(http.StatusForbidden)
+				render.JSON(w, r, resp.Error("access denied"))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// SuperAdmins tells super admins apart from other admins.
+type SuperAdmins interface {
+	IsSuperAdmin(ctx context.Context, adminID int) (bool, error)
+}
+
+// RequireSuperAdmin lets through only admins with the super admin role. It goes after
+// RequireRole(jwt.RoleAdmin), so the id of the token is the id of an admin.
+func RequireSuperAdmin(superAdmins SuperAdmins) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			id, err := GetAdminID(r.Context())
+			if err != nil {
+				w.WriteHeader(http.StatusForbidden)
+				render.JSON(w, r, resp.Error("access denied"))
+				return
+			}
+
+			isSuperAdmin, err := superAdmins.IsSuperAdmin(r.Context(), id)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("failed to check admin role"))
+				return
+			}
+
+			if !isSuperAdmin {
+				w.WriteHeader(http.StatusForbidden)
+				render.JSON(w, r, resp.Error("access denied"))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// GetAdminID returns the id of the admin the request is made by, it fails for tokens of users.
+func GetAdminID(ctx context.Context) (int, error) {
+	role, err := GetRole(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if role != jwt.RoleAdmin {
+		return 0, errors.New("token is not an admin token")
+	}
+
+	return GetID(ctx)
+}
@@ -0,0 +1,141 @@
+package request
+
+import (
+	"fmt"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseTaskFilter reads task list filters from the query string.
+//
+// Supported parameters: status_id (comma separated), group_id, category_id, user_id,
+// min_amount, max_amount, created_from, created_to (RFC 3339), q, sort, order, limit, cursor.
+func ParseTaskFilter(r *http.Request) (model.TaskFilter, error) {
+	query := r.URL.Query()
+
+	var filter model.TaskFilter
+	var err error
+
+	if raw := query.Get("status_id"); raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			statusID, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				return model.TaskFilter{}, fmt.Errorf("invalid status_id: %w", err)
+			}
+			filter.StatusIDs = append(filter.StatusIDs, statusID)
+		}
+	}
+
+	if filter.GroupID, err = QueryInt(r, "group_id"); err != nil {
+		return model.TaskFilter{}, err
+	}
+
+	if filter.CategoryID, err = QueryInt(r, "category_id"); err != nil {
+		return model.TaskFilter{}, err
+	}
+
+	if filter.UserID, err = QueryInt(r, "user_id"); err != nil {
+		return model.TaskFilter{}, err
+	}
+
+	if filter.Limit, err = QueryInt(r, "limit"); err != nil {
+		return model.TaskFilter{}, err
+	}
+
+	if filter.MinAmount, err = QueryFloat(r, "min_amount"); err != nil {
+		return model.TaskFilter{}, err
+	}
+
+	if filter.MaxAmount, err = QueryFloat(r, "max_amount"); err != nil {
+		return model.TaskFilter{}, err
+	}
+
+	if filter.CreatedFrom, err = QueryTime(r, "created_from"); err != nil {
+		return model.TaskFilter{}, err
+	}
+
+	if filter.CreatedTo, err = QueryTime(r, "created_to"); err != nil {
+		return model.TaskFilter{}, err
+	}
+
+	filter.Search = strings.TrimSpace(query.Get("q"))
+	filter.Sort = query.Get("sort")
+	filter.Order = strings.ToLower(query.Get("order"))
+	filter.Cursor = query.Get("cursor")
+
+	return filter, nil
+}
+
+// ParseLeaderboardFilter reads leaderboard filters from the query string.
+//
+// Supported parameters: metric, class_id, group_id, limit, offset.
+func ParseLeaderboardFilter(r *http.Request) (model.LeaderboardFilter, error) {
+	filter := model.LeaderboardFilter{
+		Metric: strings.ToLower(strings.TrimSpace(r.URL.Query().Get("metric"))),
+	}
+
+	var err error
+
+	if filter.ClassID, err = QueryInt(r, "class_id"); err != nil {
+		return model.LeaderboardFilter{}, err
+	}
+
+	if filter.GroupID, err = QueryInt(r, "group_id"); err != nil {
+		return model.LeaderboardFilter{}, err
+	}
+
+	if filter.Limit, err = QueryInt(r, "limit"); err != nil {
+		return model.LeaderboardFilter{}, err
+	}
+
+	if filter.Offset, err = QueryInt(r, "offset"); err != nil {
+		return model.LeaderboardFilter{}, err
+	}
+
+	return filter, nil
+}
+
+func QueryInt(r *http.Request, key string) (int, error) {
+	raw := r.URL.Query().Get(key)
+	if raw == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+
+	return value, nil
+}
+
+func QueryFloat(r *http.Request, key string) (float64, error) {
+	raw := r.URL.Query().Get(key)
+	if raw == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+
+	return value, nil
+}
+
+func QueryTime(r *http.Request, key string) (time.Time, error) {
+	raw := r.URL.Query().Get(key)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+
+	value, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s: %w", key, err)
+	}
+
+	return value, nil
+}
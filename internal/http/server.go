@@ -3,6 +3,7 @@ package http
 import (
 	"context"
 	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"time"
 )
 
 type Auth interface {
@@ -13,13 +14,19 @@ type Auth interface {
 }
 
 type Tasks interface {
-	GetAllUserTasks(ctx context.Context, userID int) ([]model.Task, error)
-	GetAllAdminTasks(ctx context.Context, adminID int) ([]model.Task, error)
+	GetAllUserTasks(ctx context.Context, userID int, filter model.TaskFilter) (model.TaskPage, error)
+	GetAllAdminTasks(ctx context.Context, adminID int, filter model.TaskFilter) (model.TaskPage, error)
 	GetByID(ctx context.Context, taskID int) (model.Task, error)
 	Add(ctx context.Context, task model.Task) (int, error)
 	MarkAsCompleted(ctx context.Context, taskID, adminID int) (model.Task, error)
 	MarkAsCancelled(ctx context.Context, taskID, userID int) error
 	MarkAsWaitingForAcceptance(ctx context.Context, taskID, userID int) error
+	Update(ctx context.Context, taskID, adminID int, update model.TaskUpdate) (model.Task, error)
+	Reassign(ctx context.Context, taskID, adminID, forGroupID, userID int) (model.Task, error)
+	Cancel(ctx context.Context, taskID, adminID int, reason string) error
+	RequiresApproval(amount float64) bool
+	GetUserTask(ctx context.Context, taskID, userID int) (model.Task, error)
+	Reward(ctx context.Context, task model.Task) (float64, error)
 }
 
 type Transactions interface {
@@ -31,5 +38,211 @@ type Transactions interface {
 type Users interface {
 	GetAll(ctx context.Context) ([]model.User, error)
 	CreateBalance(ctx context.Context, userID int) error
-	GetTopByBalance(ctx context.Context) ([]model.User, error)
+	GetTopByBalance(ctx context.Context, limit int) ([]model.User, error)
+	GetProfile(ctx context.Context, userID int) (model.User, error)
+}
+
+type Search interface {
+	Search(ctx context.Context, callerID int, role string, query model.SearchQuery) ([]model.SearchResult, error)
+}
+
+type Notifications interface {
+	GetAllUserNotifications(ctx context.Context, userID int, unreadOnly bool, limit int) ([]model.Notification, error)
+	MarkAsRead(ctx context.Context, notificationID, userID int) error
+}
+
+type Reviews interface {
+	GetQueue(ctx context.Context, adminID int, limit int) ([]model.ReviewQueueItem, error)
+	AssignTaskReviewer(ctx context.Context, taskID, reviewerID, adminID int) error
+	AssignGroupReviewer(ctx context.Context, groupID, reviewerID, adminID int) error
+	Delegate(ctx context.Context, fromAdminID, toAdminID int, startsAt, endsAt time.Time) (int, error)
+	GetDelegations(ctx context.Context, adminID int) ([]model.ReviewDelegation, error)
+	RevokeDelegation(ctx context.Context, delegationID, adminID int) error
+}
+
+type Approvals interface {
+	GetAll(ctx context.Context, statusID, taskID, limit int) ([]model.Approval, error)
+	Approve(ctx context.Context, approvalID, adminID int) (model.Approval, error)
+	Deny(ctx context.Context, approvalID, adminID int, reason string) (model.Approval, error)
+}
+
+type Budgets interface {
+	Allocate(ctx context.Context, superAdminID int, budget model.Budget, period string, at time.Time) (int, error)
+	GetActive(ctx context.Context, adminID int) ([]model.Budget, error)
+	GetAll(ctx context.Context, superAdminID, adminID, groupID, limit int) ([]model.Budget, error)
+}
+
+type Perks interface {
+	GetUserPerks(ctx context.Context, userID int) ([]model.Perk, error)
+}
+
+type Intercepts interface {
+	Intercept(ctx context.Context, taskID, thiefID int) (model.Intercept, error)
+	GetAll(ctx context.Context, userID, taskID, limit int) ([]model.Intercept, error)
+	NextAttemptAt(ctx context.Context, userID int) (time.Time, error)
+}
+
+type Intel interface {
+	Buy(ctx context.Context, buyerID, targetID int) (model.Intel, error)
+	GetTasks(ctx context.Context, intelID, buyerID int) (model.Intel, []model.Task, error)
+	GetAll(ctx context.Context, buyerID int, activeOnly bool, limit int) ([]model.Intel, error)
+}
+
+type Levels interface {
+	GetProgress(ctx context.Context, userID int) (model.LevelProgress, error)
+	GetLedger(ctx context.Context, userID, limit int) ([]model.XPEntry, error)
+	GetLevelUps(ctx context.Context, userID, limit int) ([]model.LevelUp, error)
+}
+
+type Achievements interface {
+	Evaluate(ctx context.Context, userID int, event string) ([]model.UserAchievement, error)
+	GetUserAchievements(ctx context.Context, userID int) ([]model.UserAchievement, error)
+	GetProgress(ctx context.Context, userID int) ([]model.AchievementProgress, error)
+}
+
+type Leaderboards interface {
+	Get(ctx context.Context, filter model.LeaderboardFilter) ([]model.LeaderboardEntry, error)
+	GetAround(ctx context.Context, userID int, filter model.LeaderboardFilter, neighbours int) ([]model.LeaderboardEntry, error)
+}
+
+type Seasons interface {
+	Create(ctx context.Context, superAdminID int, season model.Season) (int, error)
+	GetAll(ctx context.Context) ([]model.Season, error)
+	GetStandings(ctx context.Context, seasonID, limit, offset int) (model.Season, []model.SeasonStanding, error)
+}
+
+type Streaks interface {
+	Record(ctx context.Context, userID int) (model.Streak, error)
+	CheckIn(ctx context.Context, userID int) (model.CheckIn, model.Streak, error)
+	Get(ctx context.Context, userID int) (model.Streak, bool, float64, error)
+}
+
+type Shop interface {
+	GetItems(ctx context.Context) ([]model.ShopItem, error)
+	Buy(ctx context.Context, userID, itemID int) (model.Purchase, error)
+}
+
+type Kudos interface {
+	Give(ctx context.Context, senderID, receiverID, amount int, message string) (model.Kudos, error)
+	GetAllowance(ctx context.Context, userID int) (model.KudosAllowance, error)
+	GetFeed(ctx context.Context, limit, offset int) ([]model.Kudos, error)
+	GetReceived(ctx context.Context, userID, limit, offset int) ([]model.Kudos, error)
+}
+
+type Teams interface {
+	Create(ctx context.Context, userID int, name string) (model.Team, error)
+	GetMine(ctx context.Context, userID int) (model.Team, []model.TeamMember, error)
+	Get(ctx context.Context, teamID int) (model.Team, []model.TeamMember, error)
+	Invite(ctx context.Context, leaderID, userID int) (model.TeamInvitation, error)
+	GetInvitations(ctx context.Context, userID int) ([]model.TeamInvitation, error)
+	AcceptInvitation(ctx context.Context, invitationID, userID int) (model.Team, error)
+	DeclineInvitation(ctx context.Context, invitationID, userID int) error
+	Leave(ctx context.Context, userID int) error
+	Contribute(ctx context.Context, userID int, amount float64) (model.TeamWalletEntry, error)
+	Payout(ctx context.Context, leaderID, memberID int, amount float64) (model.TeamWalletEntry, error)
+	GetWallet(ctx context.Context, userID, limit, offset int) (model.Team, []model.TeamWalletEntry, error)
+	CreateTask(ctx context.Context, adminID int, task model.TeamTask) (int, error)
+	GetTasks(ctx context.Context, userID int) ([]model.TeamTaskProgress, error)
+	SubmitTask(ctx context.Context, taskID, userID int) (model.TeamTaskProgress, error)
+	GetCompletions(ctx context.Context, adminID, limit int) ([]model.TeamTaskCompletion, error)
+	AcceptCompletion(ctx context.Context, completionID, adminID int) (model.TeamTaskCompletion, error)
+	GetLeaderboard(ctx context.Context, metric string, limit, offset int) ([]model.TeamLeaderboardEntry, error)
+}
+
+type Quests interface {
+	Create(ctx context.Context, adminID int, quest model.Quest, ordered bool) (int, error)
+	Update(ctx context.Context, adminID int, quest model.Quest, ordered bool) error
+	Publish(ctx context.Context, adminID, id int) error
+	GetAll(ctx context.Context, adminID int) ([]model.Quest, error)
+	GetPublished(ctx context.Context, userID int) ([]model.UserQuest, error)
+	Get(ctx context.Context, userID, questID int) (model.UserQuest, error)
+	Start(ctx context.Context, userID, questID int) (model.QuestProgress, error)
+	Advance(ctx context.Context, taskID int) (model.QuestAdvance, error)
+}
+
+type Duels interface {
+	Challenge(ctx context.Context, challengerID int, duel model.Duel) (model.Duel, error)
+	Accept(ctx context.Context, duelID, userID int) (model.Duel, error)
+	Decline(ctx context.Context, duelID, userID int) (model.Duel, error)
+	Resolve(ctx context.Context, duelID, adminID, winnerID int) (model.Duel, error)
+	SettleTask(ctx context.Context, task model.Task) ([]model.Duel, error)
+	Get(ctx context.Context, duelID, userID int) (model.Duel, error)
+	GetMine(ctx context.Context, userID, statusID, limit, offset int) ([]model.Duel, error)
+	GetHistory(ctx context.Context, userID, limit, offset int) ([]model.Duel, model.DuelRecord, error)
+	GetAll(ctx context.Context, adminID, statusID, limit int) ([]model.Duel, error)
+}
+
+type Bounties interface {
+	Post(ctx context.Context, posterID int, bounty model.Bounty) (model.Bounty, error)
+	Cancel(ctx context.Context, id, userID int) (model.Bounty, error)
+	Claim(ctx context.Context, id, userID int) (model.Bounty, error)
+	Abandon(ctx context.Context, id, userID int) (model.Bounty, error)
+	Submit(ctx context.Context, id, userID int, submission string) (model.Bounty, error)
+	Approve(ctx context.Context, id, userID int) (model.Bounty, error)
+	Reject(ctx context.Context, id, userID int, reason string) (model.Bounty, error)
+	Dispute(ctx context.Context, id, userID int, reason string) (model.BountyDispute, error)
+	ResolveDispute(ctx context.Context, adminID, disputeID int, paid bool, note string) (model.BountyDispute, error)
+	Get(ctx context.Context, id, userID int) (model.Bounty, error)
+	GetOpen(ctx context.Context, limit, offset int) ([]model.Bounty, error)
+	GetMine(ctx context.Context, userID, limit, offset int) ([]model.Bounty, error)
+	GetDisputes(ctx context.Context, adminID int, pending bool, limit int) ([]model.BountyDispute, error)
+}
+
+type Drops interface {
+	CreateCampaign(ctx context.Context, adminID int, campaign model.DropCampaign) (model.DropCampaign, error)
+	StopCampaign(ctx context.Context, adminID, id int) (model.DropCampaign, error)
+	GetCampaigns(ctx context.Context, adminID int, running bool, limit int) ([]model.DropCampaign, error)
+	Claim(ctx context.Context, dropID, userID int) (model.Drop, error)
+	GetActive(ctx context.Context, userID int) ([]model.Drop, error)
+}
+
+type Raffles interface {
+	Create(ctx context.Context, adminID int, raffle model.Raffle) (model.Raffle, error)
+	BuyTickets(ctx context.Context, raffleID, userID, count int) (model.Raffle, error)
+	Cancel(ctx context.Context, adminID, raffleID int) (model.Raffle, error)
+	Get(ctx context.Context, raffleID, userID int) (model.Raffle, error)
+	GetAll(ctx context.Context, statusID, userID, limit, offset int) ([]model.Raffle, error)
+	GetAllForAdmin(ctx context.Context, adminID, statusID, limit, offset int) ([]model.Raffle, error)
+	GetTickets(ctx context.Context, raffleID int) ([]model.RaffleTicket, error)
+}
+
+type Events interface {
+	Create(ctx context.Context, adminID int, event model.Event) (model.Event, error)
+	Cancel(ctx context.Context, adminID, id int) (model.Event, error)
+	CreateCode(ctx context.Context, adminID, eventID int, oneTime bool, ttl time.Duration) (model.EventCode, error)
+	RSVP(ctx context.Context, eventID, userID int) (model.Event, error)
+	CancelRSVP(ctx context.Context, eventID, userID int) error
+	CheckIn(ctx context.Context, userID int, token string) (model.Event, error)
+	Get(ctx context.Context, id, userID int) (model.Event, error)
+	GetUpcoming(ctx context.Context, userID, limit, offset int) ([]model.Event, error)
+	GetAll(ctx context.Context, adminID, limit, offset int) ([]model.Event, error)
+	GetRSVPs(ctx context.Context, adminID, eventID int) ([]model.EventRSVP, error)
+}
+
+type Quizzes interface {
+	Create(ctx context.Context, adminID int, quiz model.Quiz) (model.Quiz, error)
+	Close(ctx context.Context, adminID, id int) (model.Quiz, error)
+	Submit(ctx context.Context, quizID, userID int, answers []model.QuizAnswer) (model.QuizSubmission, error)
+	Get(ctx context.Context, id, userID int) (model.Quiz, error)
+	GetForAdmin(ctx context.Context, adminID, id int) (model.Quiz, error)
+	GetOpen(ctx context.Context, userID, limit, offset int) ([]model.Quiz, error)
+	GetAll(ctx context.Context, adminID, limit, offset int) ([]model.Quiz, error)
+	GetSubmission(ctx context.Context, quizID, userID int) (model.QuizSubmission, error)
+	GetResults(ctx context.Context, adminID, quizID int) ([]model.QuizQuestionResult, error)
+}
+
+type Production interface {
+	GetBusinesses(ctx context.Context) ([]model.Business, error)
+	GetOwned(ctx context.Context, userID int) ([]model.Business, error)
+	BuyBusiness(ctx context.Context, userID, businessID int) (model.Business, error)
+	SetRecipe(ctx context.Context, userID, businessID, recipeID int) (model.Business, error)
+	GetRecipes(ctx context.Context) ([]model.Recipe, error)
+	GetInventory(ctx context.Context, userID int) (model.Inventory, error)
+	Sell(ctx context.Context, userID, resourceID, quantity int) (model.ResourceSale, error)
+}
+
+type Companions interface {
+	Get(ctx context.Context, userID int) (model.Companion, error)
+	Feed(ctx context.Context, userID, resourceID, quantity int) (model.Companion, error)
+	Play(ctx context.Context, userID int) (model.Companion, error)
 }
@@ -0,0 +1,77 @@
+package companions
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"math"
+	"time"
+)
+
+// Rates are how many points the hunger of a companion grows and its happiness falls per hour.
+type Rates struct {
+	HungerPerHour    float64
+	HappinessPerHour float64
+}
+
+// At returns the companion as of now: hunger and happiness decay for the time since UpdatedAt.
+// A starving companion loses happiness twice as fast.
+func At(companion model.Companion, rates Rates, now time.Time) model.Companion {
+	if !now.After(companion.UpdatedAt) {
+		return withWellBeing(companion)
+	}
+
+	hours := now.Sub(companion.UpdatedAt).Hours()
+
+	hunger := companion.Hunger + rates.HungerPerHour*hours
+	sadness := rates.HappinessPerHour * hours
+	if hunger > 100 && rates.HungerPerHour > 0 {
+		starving := (hunger - 100) / rates.HungerPerHour
+		sadness += rates.HappinessPerHour * math.Min(starving, hours)
+	}
+
+	companion.Hunger = clamp(hunger)
+	companion.Happiness = clamp(companion.Happiness - sadness)
+	companion.UpdatedAt = now
+
+	return withWellBeing(companion)
+}
+
+// Feed returns the companion after eating quantity units of food with the nutrition and joy.
+func Feed(companion model.Companion, nutrition, joy, quantity int, now time.Time) model.Companion {
+	companion.Hunger = clamp(companion.Hunger - float64(nutrition*quantity))
+	companion.Happiness = clamp(companion.Happiness + float64(joy*quantity))
+	companion.FedAt = now
+	companion.UpdatedAt = now
+
+	return withWellBeing(companion)
+}
+
+// Play returns the companion after playing: it gets happier and hungrier.
+func Play(companion model.Companion, happiness, hunger float64, now time.Time) model.Companion {
+	companion.Happiness = clamp(companion.Happiness + happiness)
+	companion.Hunger = clamp(companion.Hunger + hunger)
+	companion.PlayedAt = now
+	companion.UpdatedAt = now
+
+	return withWellBeing(companion)
+}
+
+// WellBeing returns how well the companion is, 1 for a full and happy one and 0 for a starving and sad one.
+func WellBeing(companion model.Companion) float64 {
+	return math.Round(((100-companion.Hunger)+companion.Happiness)/2) / 100
+}
+
+func withWellBeing(companion model.Companion) model.Companion {
+	companion.Hunger = round(companion.Hunger)
+	companion.Happiness = round(companion.Happiness)
+	companion.WellBeing = WellBeing(companion)
+
+	return companion
+}
+
+func clamp(value float64) float64 {
+	return math.Max(0, math.Min(100, value))
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
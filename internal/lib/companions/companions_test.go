@@ -0,0 +1,155 @@
+package companions
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"testing"
+	"time"
+)
+
+func TestAt(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	rates := Rates{HungerPerHour: 2, HappinessPerHour: 1}
+
+	tests := []struct {
+		name          string
+		companion     model.Companion
+		rates         Rates
+		elapsed       time.Duration
+		wantHunger    float64
+		wantHappiness float64
+		wantWellBeing float64
+	}{
+		{
+			name:          "no time passed",
+			companion:     model.Companion{Hunger: 10, Happiness: 90},
+			rates:         rates,
+			wantHunger:    10,
+			wantHappiness: 90,
+			wantWellBeing: 0.9,
+		},
+		{
+			name:          "clock behind the last update",
+			companion:     model.Companion{Hunger: 10, Happiness: 90},
+			rates:         rates,
+			elapsed:       -time.Hour,
+			wantHunger:    10,
+			wantHappiness: 90,
+			wantWellBeing: 0.9,
+		},
+		{
+			name:          "decay",
+			companion:     model.Companion{Hunger: 10, Happiness: 90},
+			rates:         rates,
+			elapsed:       10 * time.Hour,
+			wantHunger:    30,
+			wantHappiness: 80,
+			wantWellBeing: 0.75,
+		},
+		{
+			name:          "starving companion gets sad twice as fast",
+			companion:     model.Companion{Hunger: 90, Happiness: 80},
+			rates:         rates,
+			elapsed:       10 * time.Hour,
+			wantHunger:    100,
+			wantHappiness: 65,
+			wantWellBeing: 0.33,
+		},
+		{
+			name:          "bounded after a long time",
+			companion:     model.Companion{Hunger: 0, Happiness: 100},
+			rates:         rates,
+			elapsed:       1000 * time.Hour,
+			wantHunger:    100,
+			wantHappiness: 0,
+			wantWellBeing: 0,
+		},
+		{
+			name:          "no hunger rate",
+			companion:     model.Companion{Hunger: 100, Happiness: 50},
+			rates:         Rates{HappinessPerHour: 1},
+			elapsed:       10 * time.Hour,
+			wantHunger:    100,
+			wantHappiness: 40,
+			wantWellBeing: 0.2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.companion.UpdatedAt = start
+
+			got := At(tt.companion, tt.rates, start.Add(tt.elapsed))
+
+			if got.Hunger != tt.wantHunger || got.Happiness != tt.wantHappiness || got.WellBeing != tt.wantWellBeing {
+				t.Errorf("At() = hunger %v, happiness %v, well-being %v, want %v, %v, %v",
+					got.Hunger, got.Happiness, got.WellBeing, tt.wantHunger, tt.wantHappiness, tt.wantWellBeing)
+			}
+		})
+	}
+}
+
+func TestFeed(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		companion     model.Companion
+		nutrition     int
+		joy           int
+		quantity      int
+		wantHunger    float64
+		wantHappiness float64
+	}{
+		{name: "one unit", companion: model.Companion{Hunger: 50, Happiness: 50}, nutrition: 10, joy: 5, quantity: 1, wantHunger: 40, wantHappiness: 55},
+		{name: "several units", companion: model.Companion{Hunger: 50, Happiness: 50}, nutrition: 10, joy: 5, quantity: 3, wantHunger: 20, wantHappiness: 65},
+		{name: "bounded", companion: model.Companion{Hunger: 10, Happiness: 95}, nutrition: 15, joy: 15, quantity: 2, wantHunger: 0, wantHappiness: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Feed(tt.companion, tt.nutrition, tt.joy, tt.quantity, now)
+
+			if got.Hunger != tt.wantHunger || got.Happiness != tt.wantHappiness {
+				t.Errorf("Feed() = hunger %v, happiness %v, want %v, %v", got.Hunger, got.Happiness, tt.wantHunger, tt.wantHappiness)
+			}
+
+			if !got.FedAt.Equal(now) || !got.UpdatedAt.Equal(now) {
+				t.Errorf("Feed() fed at %v, updated at %v, want %v", got.FedAt, got.UpdatedAt, now)
+			}
+		})
+	}
+}
+
+func TestPlay(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	got := Play(model.Companion{Hunger: 97, Happiness: 30}, 20, 5, now)
+
+	if got.Hunger != 100 || got.Happiness != 50 {
+		t.Errorf("Play() = hunger %v, happiness %v, want 100, 50", got.Hunger, got.Happiness)
+	}
+
+	if !got.PlayedAt.Equal(now) {
+		t.Errorf("Play() played at %v, want %v", got.PlayedAt, now)
+	}
+}
+
+func TestWellBeing(t *testing.T) {
+	tests := []struct {
+		name      string
+		companion model.Companion
+		want      float64
+	}{
+		{name: "full and happy", companion: model.Companion{Hunger: 0, Happiness: 100}, want: 1},
+		{name: "starving and sad", companion: model.Companion{Hunger: 100, Happiness: 0}, want: 0},
+		{name: "halfway", companion: model.Companion{Hunger: 50, Happiness: 50}, want: 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WellBeing(tt.companion); got != tt.want {
+				t.Errorf("WellBeing() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
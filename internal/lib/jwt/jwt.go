@@ -6,18 +6,36 @@ import (
 	"time"
 )
 
-func NewToken(id int, username string, duration time.Duration, secret string) (string, error) {
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+const eventCodeKind = "event code"
+
+// ErrExpired is returned for a token that is well signed but past its expiry.
+var ErrExpired = jwt.ErrTokenExpired
+
+func NewToken(id int, username, role string, duration time.Duration, secret string) (string, error) {
 	token := jwt.New(jwt.SigningMethodHS256)
 
 	claims := token.Claims.(jwt.MapClaims)
 	claims["id"] = id
 	claims["username"] = username
+	claims["role"] = role
 	claims["exp"] = time.Now().Add(duration).Unix()
 
 	return token.SignedString([]byte(secret))
 }
 
 func GetID(jwtToken string, secret string) (int, error) {
+	id, _, err := GetIdentity(jwtToken, secret)
+	return id, err
+}
+
+// GetIdentity returns id and role from the token.
+// Tokens issued before roles were introduced are treated as user tokens.
+func GetIdentity(jwtToken string, secret string) (int, string, error) {
 	token, err := jwt.Parse(jwtToken, func(t *jwt.Token) (interface{}, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, jwt.ErrSignatureInvalid
@@ -25,19 +43,72 @@ func GetID(jwtToken string, secret string) (int, error) {
 		return []byte(secret), nil
 	})
 	if err != nil {
-		return 0, err
+		return 0, "", err
 	}
 
 	if !token.Valid {
-		return 0, errors.New("token is invalid")
+		return 0, "", errors.New("token is invalid")
 	}
 
 	claims := token.Claims.(jwt.MapClaims)
 
 	idFloat, ok := claims["id"].(float64)
 	if !ok {
-		return 0, errors.New("ID claim is not a number")
+		return 0, "", errors.New("ID claim is not a number")
+	}
+
+	role, ok := claims["role"].(string)
+	if !ok || role == "" {
+		role = RoleUser
+	}
+
+	return int(idFloat), role, nil
+}
+
+// NewEventCodeToken signs the check-in code of the event, the token is valid until expiresAt.
+func NewEventCodeToken(eventID, codeID int, expiresAt time.Time, secret string) (string, error) {
+	token := jwt.New(jwt.SigningMethodHS256)
+
+	claims := token.Claims.(jwt.MapClaims)
+	claims["kind"] = eventCodeKind
+	claims["event_id"] = eventID
+	claims["code_id"] = codeID
+	claims["exp"] = expiresAt.Unix()
+
+	return token.SignedString([]byte(secret))
+}
+
+// GetEventCode returns the event and code IDs from a check-in token. An expired token returns ErrExpired.
+func GetEventCode(eventToken string, secret string) (int, int, error) {
+	token, err := jwt.Parse(eventToken, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return []byte(secret), nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if !token.Valid {
+		return 0, 0, errors.New("token is invalid")
+	}
+
+	claims := token.Claims.(jwt.MapClaims)
+
+	if kind, _ := claims["kind"].(string); kind != eventCodeKind {
+		return 0, 0, errors.New("token is not an event code")
+	}
+
+	eventID, ok := claims["event_id"].(float64)
+	if !ok {
+		return 0, 0, errors.New("event ID claim is not a number")
+	}
+
+	codeID, ok := claims["code_id"].(float64)
+	if !ok {
+		return 0, 0, errors.New("code ID claim is not a number")
 	}
 
-	return int(idFloat), nil
+	return int(eventID), int(codeID), nil
 }
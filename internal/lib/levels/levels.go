@@ -0,0 +1,60 @@
+package levels
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	ErrInvalidBase     = errors.New("base xp must be above zero")
+	ErrInvalidGrowth   = errors.New("growth must be at least 1")
+	ErrInvalidMaxLevel = errors.New("max level can not be negative")
+)
+
+// Curve maps total XP to a level. Reaching level n takes Base * (n-1)^Growth XP in total,
+// so level 1 is free and every next level costs more than the previous one. Base must be
+// above zero and Growth at least 1, see Validate.
+type Curve struct {
+	Base     float64
+	Growth   float64
+	MaxLevel int
+}
+
+// Validate reports whether the curve grows, otherwise the levels would never end.
+func (c Curve) Validate() error {
+	switch {
+	case c.Base <= 0:
+		return ErrInvalidBase
+	case c.Growth < 1:
+		return ErrInvalidGrowth
+	case c.MaxLevel < 0:
+		return ErrInvalidMaxLevel
+	}
+
+	return nil
+}
+
+// Threshold returns the total XP needed to reach the level.
+func (c Curve) Threshold(level int) int {
+	if level <= 1 {
+		return 0
+	}
+
+	return int(math.Round(c.Base * math.Pow(float64(level-1), c.Growth)))
+}
+
+// Level returns the level reached with the total XP. Levels end where the threshold stops growing,
+// so even an invalid curve gives a level.
+func (c Curve) Level(xp int) int {
+	level := 1
+	for c.MaxLevel == 0 || level < c.MaxLevel {
+		next := c.Threshold(level + 1)
+		if xp < next || next <= c.Threshold(level) {
+			break
+		}
+
+		level++
+	}
+
+	return level
+}
@@ -0,0 +1,88 @@
+package levels
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestThreshold(t *testing.T) {
+	curve := Curve{Base: 100, Growth: 1.5, MaxLevel: 50}
+
+	tests := []struct {
+		level int
+		want  int
+	}{
+		{level: 0, want: 0},
+		{level: 1, want: 0},
+		{level: 2, want: 100},
+		{level: 3, want: 283},
+		{level: 5, want: 800},
+		{level: 10, want: 2700},
+	}
+
+	for _, tt := range tests {
+		if got := curve.Threshold(tt.level); got != tt.want {
+			t.Errorf("Threshold(%d) = %d, want %d", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		name  string
+		curve Curve
+		xp    int
+		want  int
+	}{
+		{name: "no xp", curve: Curve{Base: 100, Growth: 1.5, MaxLevel: 50}, xp: 0, want: 1},
+		{name: "just below level 2", curve: Curve{Base: 100, Growth: 1.5, MaxLevel: 50}, xp: 99, want: 1},
+		{name: "exactly level 2", curve: Curve{Base: 100, Growth: 1.5, MaxLevel: 50}, xp: 100, want: 2},
+		{name: "level 5", curve: Curve{Base: 100, Growth: 1.5, MaxLevel: 50}, xp: 1000, want: 5},
+		{name: "capped", curve: Curve{Base: 100, Growth: 1.5, MaxLevel: 3}, xp: 1_000_000, want: 3},
+		{name: "linear", curve: Curve{Base: 10, Growth: 1}, xp: 95, want: 10},
+		{name: "zero base ends at level 1", curve: Curve{Base: 0, Growth: 1.5}, xp: 1000, want: 1},
+		{name: "zero growth ends at level 2", curve: Curve{Base: 100, Growth: 0}, xp: 1000, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.curve.Level(tt.xp); got != tt.want {
+				t.Errorf("Level(%d) = %d, want %d", tt.xp, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLevelMatchesThreshold(t *testing.T) {
+	curve := Curve{Base: 100, Growth: 1.5, MaxLevel: 50}
+
+	for level := 1; level <= curve.MaxLevel; level++ {
+		if got := curve.Level(curve.Threshold(level)); got != level {
+			t.Errorf("Level(Threshold(%d)) = %d", level, got)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		curve Curve
+		want  error
+	}{
+		{name: "valid", curve: Curve{Base: 100, Growth: 1.5, MaxLevel: 50}},
+		{name: "no max level", curve: Curve{Base: 100, Growth: 1}},
+		{name: "zero base", curve: Curve{Base: 0, Growth: 1.5}, want: ErrInvalidBase},
+		{name: "negative base", curve: Curve{Base: -1, Growth: 1.5}, want: ErrInvalidBase},
+		{name: "zero growth", curve: Curve{Base: 100, Growth: 0}, want: ErrInvalidGrowth},
+		{name: "shrinking growth", curve: Curve{Base: 100, Growth: 0.5}, want: ErrInvalidGrowth},
+		{name: "negative max level", curve: Curve{Base: 100, Growth: 1.5, MaxLevel: -1}, want: ErrInvalidMaxLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.curve.Validate(); !errors.Is(err, tt.want) {
+				t.Errorf("Validate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
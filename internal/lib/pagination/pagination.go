@@ -0,0 +1,64 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor points at the last row of a page. Value holds the sort key of that row
+// and ID breaks ties between rows with equal sort keys. Sort and Order are the ones
+// the page was listed with, the cursor is only valid for the next page of the same listing.
+type Cursor struct {
+	Sort  string `json:"s"`
+	Order string `json:"o"`
+	Value string `json:"v"`
+	ID    int    `json:"id"`
+}
+
+func Encode(cursor Cursor) string {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func Decode(s string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	if cursor.ID <= 0 || cursor.Sort == "" || cursor.Order == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+// Limit clamps requested page size to [1, MaxLimit], zero means DefaultLimit.
+func Limit(limit int) int {
+	if limit <= 0 {
+		return DefaultLimit
+	}
+
+	if limit > MaxLimit {
+		return MaxLimit
+	}
+
+	return limit
+}
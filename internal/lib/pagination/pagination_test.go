@@ -0,0 +1,59 @@
+package pagination
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	cursor := Cursor{Sort: "amount", Order: "desc", Value: "12.5", ID: 42}
+
+	got, err := Decode(Encode(cursor))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	if got != cursor {
+		t.Errorf("Decode(Encode()) = %+v, want %+v", got, cursor)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "not base64", cursor: "!!!"},
+		{name: "not json", cursor: "bm90IGpzb24"},
+		{name: "no id", cursor: Encode(Cursor{Sort: "amount", Order: "desc", Value: "1"})},
+		{name: "no sort", cursor: Encode(Cursor{Order: "desc", Value: "1", ID: 1})},
+		{name: "no order", cursor: Encode(Cursor{Sort: "amount", Value: "1", ID: 1})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode(tt.cursor); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("Decode() error = %v, want %v", err, ErrInvalidCursor)
+			}
+		})
+	}
+}
+
+func TestLimit(t *testing.T) {
+	tests := []struct {
+		limit int
+		want  int
+	}{
+		{limit: 0, want: DefaultLimit},
+		{limit: -1, want: DefaultLimit},
+		{limit: 1, want: 1},
+		{limit: MaxLimit, want: MaxLimit},
+		{limit: MaxLimit + 1, want: MaxLimit},
+	}
+
+	for _, tt := range tests {
+		if got := Limit(tt.limit); got != tt.want {
+			t.Errorf("Limit(%d) = %d, want %d", tt.limit, got, tt.want)
+		}
+	}
+}
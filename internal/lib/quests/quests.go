@@ -0,0 +1,112 @@
+package quests
+
+import (
+	"errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+)
+
+var (
+	ErrNoSteps          = errors.New("quest must have at least one step")
+	ErrUnknownStep      = errors.New("step requires a step that is not in the quest")
+	ErrSelfPrerequisite = errors.New("step can not require itself")
+	ErrCycle            = errors.New("step prerequisites form a cycle")
+)
+
+// Chain makes every step require the one before it, turning the steps into an ordered chain.
+func Chain(steps []model.QuestStep) []model.QuestStep {
+	for i := range steps {
+		steps[i].Requires = nil
+		if i > 0 {
+			steps[i].Requires = []int{steps[i-1].Position}
+		}
+	}
+
+	return steps
+}
+
+// Validate checks that the prerequisites of the steps refer to other steps of the quest
+// and do not form a cycle, so every step can eventually be unlocked.
+func Validate(steps []model.QuestStep) error {
+	if len(steps) == 0 {
+		return ErrNoSteps
+	}
+
+	requires := make(map[int][]int, len(steps))
+	for _, step := range steps {
+		requires[step.Position] = step.Requires
+	}
+
+	for _, step := range steps {
+		for _, position := range step.Requires {
+			if position == step.Position {
+				return ErrSelfPrerequisite
+			}
+
+			if _, ok := requires[position]; !ok {
+				return ErrUnknownStep
+			}
+		}
+	}
+
+	// depth-first search, a step met again while its prerequisites are still being visited closes a cycle
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	state := make(map[int]int, len(steps))
+
+	var visit func(position int) bool
+	visit = func(position int) bool {
+		switch state[position] {
+		case visiting:
+			return false
+		case visited:
+			return true
+		}
+
+		state[position] = visiting
+		for _, required := range requires[position] {
+			if !visit(required) {
+				return false
+			}
+		}
+		state[position] = visited
+
+		return true
+	}
+
+	for _, step := range steps {
+		if !visit(step.Position) {
+			return ErrCycle
+		}
+	}
+
+	return nil
+}
+
+// Unlockable returns the positions of the steps that are not unlocked yet
+// and whose prerequisites are all completed.
+func Unlockable(steps []model.QuestStep, unlocked, completed map[int]bool) []int {
+	var positions []int
+
+	for _, step := range steps {
+		if unlocked[step.Position] {
+			continue
+		}
+
+		ready := true
+		for _, required := range step.Requires {
+			if !completed[required] {
+				ready = false
+				break
+			}
+		}
+
+		if ready {
+			positions = append(positions, step.Position)
+		}
+	}
+
+	return positions
+}
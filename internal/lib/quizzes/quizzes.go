@@ -0,0 +1,231 @@
+package quizzes
+
+import (
+	"errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	SingleChoiceKindID   = 1
+	MultipleChoiceKindID = 2
+	NumericKindID        = 3
+	ShortTextKindID      = 4
+)
+
+var (
+	ErrNoQuestions        = errors.New("quiz must have at least one question")
+	ErrQuestionText       = errors.New("question text is required")
+	ErrUnknownKind        = errors.New("unknown question kind")
+	ErrTooFewOptions      = errors.New("choice question must have at least two options")
+	ErrEmptyOption        = errors.New("option can not be empty")
+	ErrCorrectOption      = errors.New("correct options do not fit the question")
+	ErrCorrectNumber      = errors.New("numeric question needs a correct number and a non-negative tolerance")
+	ErrCorrectText        = errors.New("short text question needs at least one accepted answer")
+	ErrInvalidPoints      = errors.New("question points must be positive")
+	ErrUnansweredQuestion = errors.New("every question must be answered")
+	ErrUnknownQuestion    = errors.New("answer to a question that is not in the quiz")
+	ErrDuplicateAnswer    = errors.New("question is answered more than once")
+	ErrInvalidAnswer      = errors.New("answer does not fit the question")
+)
+
+// Validate numbers the questions by their order and checks them. Graded questions must have
+// correct answers, the correct answers of the others are dropped as polls have none.
+func Validate(questions []model.QuizQuestion, graded bool) ([]model.QuizQuestion, error) {
+	if len(questions) == 0 {
+		return nil, ErrNoQuestions
+	}
+
+	for i := range questions {
+		q := &questions[i]
+		q.Position = i + 1
+		q.Text = strings.TrimSpace(q.Text)
+
+		if q.Text == "" {
+			return nil, ErrQuestionText
+		}
+
+		if q.Points == 0 {
+			q.Points = 1
+		}
+
+		if q.Points < 0 {
+			return nil, ErrInvalidPoints
+		}
+
+		if !graded {
+			q.CorrectOptions = nil
+			q.CorrectNumber = nil
+			q.Tolerance = 0
+			q.CorrectTexts = nil
+		}
+
+		switch q.KindID {
+		case SingleChoiceKindID, MultipleChoiceKindID:
+			if len(q.Options) < 2 {
+				return nil, ErrTooFewOptions
+			}
+
+			for j := range q.Options {
+				q.Options[j] = strings.TrimSpace(q.Options[j])
+				if q.Options[j] == "" {
+					return nil, ErrEmptyOption
+				}
+			}
+
+			if graded && !validOptions(q.CorrectOptions, len(q.Options), q.KindID == SingleChoiceKindID) {
+				return nil, ErrCorrectOption
+			}
+
+			sort.Ints(q.CorrectOptions)
+			q.CorrectNumber, q.Tolerance, q.CorrectTexts = nil, 0, nil
+		case NumericKindID:
+			if graded && (q.CorrectNumber == nil || q.Tolerance < 0) {
+				return nil, ErrCorrectNumber
+			}
+
+			q.Options, q.CorrectOptions, q.CorrectTexts = nil, nil, nil
+		case ShortTextKindID:
+			texts := make([]string, 0, len(q.CorrectTexts))
+			for _, text := range q.CorrectTexts {
+				if text = Normalize(text); text != "" {
+					texts = append(texts, text)
+				}
+			}
+
+			if graded && len(texts) == 0 {
+				return nil, ErrCorrectText
+			}
+
+			q.CorrectTexts = texts
+			q.Options, q.CorrectOptions, q.CorrectNumber, q.Tolerance = nil, nil, nil, 0
+		default:
+			return nil, ErrUnknownKind
+		}
+	}
+
+	return questions, nil
+}
+
+// Grade matches the answers to the questions and marks the correct ones. It returns the answers in
+// the order of the questions and the score: the points of the correct answers in percent of all points.
+// Every question must be answered once in a form that fits it.
+func Grade(questions []model.QuizQuestion, answers []model.QuizAnswer) ([]model.QuizAnswer, float64, error) {
+	byPosition := make(map[int]model.QuizAnswer, len(answers))
+	for _, answer := range answers {
+		if _, ok := byPosition[answer.Position]; ok {
+			return nil, 0, ErrDuplicateAnswer
+		}
+		byPosition[answer.Position] = answer
+	}
+
+	if len(byPosition) > len(questions) {
+		return nil, 0, ErrUnknownQuestion
+	}
+
+	graded := make([]model.QuizAnswer, 0, len(questions))
+	var total, earned int
+
+	for _, q := range questions {
+		answer, ok := byPosition[q.Position]
+		if !ok {
+			return nil, 0, ErrUnansweredQuestion
+		}
+
+		answer, err := check(q, answer)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		total += q.Points
+		if answer.Correct {
+			earned += q.Points
+		}
+
+		graded = append(graded, answer)
+	}
+
+	if total == 0 {
+		return graded, 0, nil
+	}
+
+	return graded, math.Round(float64(earned)/float64(total)*10000) / 100, nil
+}
+
+// Normalize makes short texts comparable: trimmed, lower-cased and with single spaces between words.
+func Normalize(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+// check validates the answer to the question and tells whether it is correct.
+func check(q model.QuizQuestion, answer model.QuizAnswer) (model.QuizAnswer, error) {
+	switch q.KindID {
+	case SingleChoiceKindID, MultipleChoiceKindID:
+		if !validOptions(answer.Options, len(q.Options), q.KindID == SingleChoiceKindID) {
+			return model.QuizAnswer{}, ErrInvalidAnswer
+		}
+
+		sort.Ints(answer.Options)
+		answer.Number, answer.Text = nil, ""
+		answer.Correct = len(q.CorrectOptions) > 0 && equal(answer.Options, q.CorrectOptions)
+	case NumericKindID:
+		if answer.Number == nil || math.IsNaN(*answer.Number) || math.IsInf(*answer.Number, 0) {
+			return model.QuizAnswer{}, ErrInvalidAnswer
+		}
+
+		answer.Options, answer.Text = nil, ""
+		answer.Correct = q.CorrectNumber != nil && math.Abs(*answer.Number-*q.CorrectNumber) <= q.Tolerance
+	case ShortTextKindID:
+		answer.Text = strings.TrimSpace(answer.Text)
+		if answer.Text == "" {
+			return model.QuizAnswer{}, ErrInvalidAnswer
+		}
+
+		answer.Options, answer.Number = nil, nil
+		answer.Correct = false
+		normalized := Normalize(answer.Text)
+		for _, text := range q.CorrectTexts {
+			if text == normalized {
+				answer.Correct = true
+				break
+			}
+		}
+	default:
+		return model.QuizAnswer{}, ErrUnknownKind
+	}
+
+	return answer, nil
+}
+
+// validOptions checks that options are distinct indexes of count options, exactly one if single is set.
+func validOptions(options []int, count int, single bool) bool {
+	if len(options) == 0 || (single && len(options) != 1) {
+		return false
+	}
+
+	seen := make(map[int]bool, len(options))
+	for _, option := range options {
+		if option < 0 || option >= count || seen[option] {
+			return false
+		}
+		seen[option] = true
+	}
+
+	return true
+}
+
+func equal(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,275 @@
+package quizzes
+
+import (
+	"errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"testing"
+)
+
+func number(n float64) *float64 {
+	return &n
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		questions []model.QuizQuestion
+		graded    bool
+		want      error
+	}{
+		{name: "no questions", graded: true, want: ErrNoQuestions},
+		{
+			name:      "blank text",
+			questions: []model.QuizQuestion{{Text: "  ", KindID: NumericKindID, CorrectNumber: number(1)}},
+			graded:    true,
+			want:      ErrQuestionText,
+		},
+		{
+			name:      "negative points",
+			questions: []model.QuizQuestion{{Text: "q", KindID: NumericKindID, CorrectNumber: number(1), Points: -1}},
+			graded:    true,
+			want:      ErrInvalidPoints,
+		},
+		{
+			name:      "unknown kind",
+			questions: []model.QuizQuestion{{Text: "q", KindID: 99}},
+			graded:    true,
+			want:      ErrUnknownKind,
+		},
+		{
+			name:      "one option",
+			questions: []model.QuizQuestion{{Text: "q", KindID: SingleChoiceKindID, Options: []string{"a"}, CorrectOptions: []int{0}}},
+			graded:    true,
+			want:      ErrTooFewOptions,
+		},
+		{
+			name:      "blank option",
+			questions: []model.QuizQuestion{{Text: "q", KindID: SingleChoiceKindID, Options: []string{"a", " "}, CorrectOptions: []int{0}}},
+			graded:    true,
+			want:      ErrEmptyOption,
+		},
+		{
+			name:      "two correct options of a single choice",
+			questions: []model.QuizQuestion{{Text: "q", KindID: SingleChoiceKindID, Options: []string{"a", "b"}, CorrectOptions: []int{0, 1}}},
+			graded:    true,
+			want:      ErrCorrectOption,
+		},
+		{
+			name:      "correct option out of range",
+			questions: []model.QuizQuestion{{Text: "q", KindID: MultipleChoiceKindID, Options: []string{"a", "b"}, CorrectOptions: []int{2}}},
+			graded:    true,
+			want:      ErrCorrectOption,
+		},
+		{
+			name:      "numeric without correct number",
+			questions: []model.QuizQuestion{{Text: "q", KindID: NumericKindID}},
+			graded:    true,
+			want:      ErrCorrectNumber,
+		},
+		{
+			name:      "numeric with negative tolerance",
+			questions: []model.QuizQuestion{{Text: "q", KindID: NumericKindID, CorrectNumber: number(1), Tolerance: -1}},
+			graded:    true,
+			want:      ErrCorrectNumber,
+		},
+		{
+			name:      "short text with blank answers only",
+			questions: []model.QuizQuestion{{Text: "q", KindID: ShortTextKindID, CorrectTexts: []string{" "}}},
+			graded:    true,
+			want:      ErrCorrectText,
+		},
+		{
+			name: "poll needs no correct answers",
+			questions: []model.QuizQuestion{
+				{Text: "q", KindID: SingleChoiceKindID, Options: []string{"a", "b"}},
+				{Text: "q", KindID: NumericKindID},
+				{Text: "q", KindID: ShortTextKindID},
+			},
+		},
+		{
+			name: "valid quiz",
+			questions: []model.QuizQuestion{
+				{Text: "q", KindID: MultipleChoiceKindID, Options: []string{"a", "b", "c"}, CorrectOptions: []int{2, 0}},
+				{Text: "q", KindID: NumericKindID, CorrectNumber: number(0), Tolerance: 0.5},
+				{Text: "q", KindID: ShortTextKindID, CorrectTexts: []string{"answer"}},
+			},
+			graded: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Validate(tt.questions, tt.graded); !errors.Is(err, tt.want) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateNormalizes(t *testing.T) {
+	questions, err := Validate([]model.QuizQuestion{
+		{Text: " first ", KindID: MultipleChoiceKindID, Options: []string{" a ", "b", "c"}, CorrectOptions: []int{2, 0}, CorrectTexts: []string{"x"}},
+		{Text: "second", KindID: ShortTextKindID, CorrectTexts: []string{"  New   York ", " "}, Points: 3},
+	}, true)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	first, second := questions[0], questions[1]
+
+	if first.Position != 1 || second.Position != 2 {
+		t.Errorf("positions = %d, %d, want 1, 2", first.Position, second.Position)
+	}
+
+	if first.Text != "first" || first.Options[0] != "a" {
+		t.Errorf("text and options are not trimmed: %q, %q", first.Text, first.Options[0])
+	}
+
+	if first.Points != 1 || second.Points != 3 {
+		t.Errorf("points = %d, %d, want 1, 3", first.Points, second.Points)
+	}
+
+	if !equal(first.CorrectOptions, []int{0, 2}) || first.CorrectTexts != nil {
+		t.Errorf("correct answers of a choice = %v, %v, want [0 2] only", first.CorrectOptions, first.CorrectTexts)
+	}
+
+	if len(second.CorrectTexts) != 1 || second.CorrectTexts[0] != "new york" {
+		t.Errorf("correct texts = %q, want [new york]", second.CorrectTexts)
+	}
+}
+
+func TestValidatePollDropsCorrectAnswers(t *testing.T) {
+	questions, err := Validate([]model.QuizQuestion{
+		{Text: "q", KindID: SingleChoiceKindID, Options: []string{"a", "b"}, CorrectOptions: []int{0}},
+		{Text: "q", KindID: NumericKindID, CorrectNumber: number(1), Tolerance: 1},
+	}, false)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	if questions[0].CorrectOptions != nil || questions[1].CorrectNumber != nil || questions[1].Tolerance != 0 {
+		t.Errorf("poll kept correct answers: %+v", questions)
+	}
+}
+
+func TestGrade(t *testing.T) {
+	questions, err := Validate([]model.QuizQuestion{
+		{Text: "single", KindID: SingleChoiceKindID, Options: []string{"a", "b", "c"}, CorrectOptions: []int{1}},
+		{Text: "multiple", KindID: MultipleChoiceKindID, Options: []string{"a", "b", "c"}, CorrectOptions: []int{0, 2}, Points: 2},
+		{Text: "numeric", KindID: NumericKindID, CorrectNumber: number(10), Tolerance: 0.5},
+		{Text: "text", KindID: ShortTextKindID, CorrectTexts: []string{"new york"}},
+	}, true)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	correct := []model.QuizAnswer{
+		{Position: 1, Options: []int{1}},
+		{Position: 2, Options: []int{0, 2}},
+		{Position: 3, Number: number(10)},
+		{Position: 4, Text: "new york"},
+	}
+
+	tests := []struct {
+		name      string
+		answers   []model.QuizAnswer
+		wantScore float64
+		want      error
+	}{
+		{name: "all correct", answers: correct, wantScore: 100},
+		{
+			name: "in any order and form",
+			answers: []model.QuizAnswer{
+				{Position: 4, Text: "  New   York "},
+				{Position: 3, Number: number(10.5)},
+				{Position: 2, Options: []int{2, 0}},
+				{Position: 1, Options: []int{1}},
+			},
+			wantScore: 100,
+		},
+		{
+			name: "wrong choice",
+			answers: []model.QuizAnswer{
+				{Position: 1, Options: []int{0}},
+				{Position: 2, Options: []int{0, 2}},
+				{Position: 3, Number: number(10)},
+				{Position: 4, Text: "new york"},
+			},
+			wantScore: 80,
+		},
+		{
+			name: "partly chosen multiple choice and number out of tolerance",
+			answers: []model.QuizAnswer{
+				{Position: 1, Options: []int{1}},
+				{Position: 2, Options: []int{0}},
+				{Position: 3, Number: number(10.6)},
+				{Position: 4, Text: "york"},
+			},
+			wantScore: 20,
+		},
+		{name: "unanswered question", answers: correct[:3], want: ErrUnansweredQuestion},
+		{name: "duplicate answer", answers: append([]model.QuizAnswer{{Position: 1, Options: []int{1}}}, correct...), want: ErrDuplicateAnswer},
+		{name: "unknown question", answers: append([]model.QuizAnswer{{Position: 5, Text: "x"}}, correct...), want: ErrUnknownQuestion},
+		{
+			name: "two options of a single choice",
+			answers: []model.QuizAnswer{
+				{Position: 1, Options: []int{0, 1}},
+				{Position: 2, Options: []int{0, 2}},
+				{Position: 3, Number: number(10)},
+				{Position: 4, Text: "new york"},
+			},
+			want: ErrInvalidAnswer,
+		},
+		{
+			name: "missing number",
+			answers: []model.QuizAnswer{
+				{Position: 1, Options: []int{1}},
+				{Position: 2, Options: []int{0, 2}},
+				{Position: 3},
+				{Position: 4, Text: "new york"},
+			},
+			want: ErrInvalidAnswer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graded, score, err := Grade(questions, tt.answers)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Grade() error = %v, want %v", err, tt.want)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if score != tt.wantScore {
+				t.Errorf("Grade() score = %v, want %v", score, tt.wantScore)
+			}
+
+			for i, answer := range graded {
+				if answer.Position != i+1 {
+					t.Errorf("answer %d is for question %d", i, answer.Position)
+				}
+			}
+		})
+	}
+}
+
+func TestGradePoll(t *testing.T) {
+	questions, err := Validate([]model.QuizQuestion{
+		{Text: "q", KindID: SingleChoiceKindID, Options: []string{"a", "b"}},
+	}, false)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	graded, score, err := Grade(questions, []model.QuizAnswer{{Position: 1, Options: []int{0}}})
+	if err != nil {
+		t.Fatalf("Grade() error = %v", err)
+	}
+
+	if score != 0 || graded[0].Correct {
+		t.Errorf("Grade() of a poll = %v, correct %v, want 0, false", score, graded[0].Correct)
+	}
+}
@@ -0,0 +1,51 @@
+package raffles
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"strconv"
+)
+
+// NewSeed returns a random secret seed, hex encoded. It is kept hidden until the raffle is drawn.
+func NewSeed() (string, error) {
+	var buf [32]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf[:]), nil
+}
+
+// Commit returns the commitment to the seed that is published before any ticket is sold:
+// the hex encoded SHA-256 of the seed. Once the seed is revealed anyone can check it against the commitment.
+func Commit(seed string) string {
+	sum := sha256.Sum256([]byte(seed))
+	return hex.EncodeToString(sum[:])
+}
+
+// TicketsHash returns the hex encoded SHA-256 of the ticket list, where holders[i] is the user who holds
+// ticket number i+1. Every ticket adds a "number:user" line. The list is fixed only when ticket sales close.
+func TicketsHash(holders []int) string {
+	h := sha256.New()
+	for i, userID := range holders {
+		h.Write([]byte(strconv.Itoa(i+1) + ":" + strconv.Itoa(userID) + "\n"))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Winner returns the number of the winning ticket among the tickets of holders, numbered from 1. It takes
+// the first 8 bytes of the SHA-256 of "draw:", the seed, ":" and TicketsHash as a big-endian number modulo
+// the number of tickets. The seed is committed before any ticket is sold and the ticket list is known only
+// after the sales, so neither the admin who knows the seed nor the buyers can predict the winner, and
+// anyone who knows the revealed seed and the tickets can repeat the draw.
+func Winner(seed string, holders []int) int {
+	if len(holders) == 0 {
+		return 0
+	}
+
+	sum := sha256.Sum256([]byte("draw:" + seed + ":" + TicketsHash(holders)))
+	return int(binary.BigEndian.Uint64(sum[:8])%uint64(len(holders))) + 1
+}
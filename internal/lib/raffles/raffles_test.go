@@ -0,0 +1,108 @@
+package raffles
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestCommit(t *testing.T) {
+	seed := "seed"
+	sum := sha256.Sum256([]byte(seed))
+
+	if got, want := Commit(seed), hex.EncodeToString(sum[:]); got != want {
+		t.Errorf("Commit(%q) = %s, want %s", seed, got, want)
+	}
+}
+
+func TestNewSeed(t *testing.T) {
+	a, err := NewSeed()
+	if err != nil {
+		t.Fatalf("NewSeed() error = %v", err)
+	}
+
+	b, err := NewSeed()
+	if err != nil {
+		t.Fatalf("NewSeed() error = %v", err)
+	}
+
+	if len(a) != 64 {
+		t.Errorf("len(NewSeed()) = %d, want 64", len(a))
+	}
+
+	if a == b {
+		t.Errorf("NewSeed() returned %s twice", a)
+	}
+}
+
+func TestWinner(t *testing.T) {
+	tests := []struct {
+		name    string
+		seed    string
+		holders []int
+	}{
+		{name: "no tickets", seed: "seed"},
+		{name: "one ticket", seed: "seed", holders: []int{7}},
+		{name: "many tickets", seed: "seed", holders: []int{1, 2, 3, 1, 2, 3, 4}},
+		{name: "other seed", seed: "other", holders: []int{1, 2, 3, 1, 2, 3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Winner(tt.seed, tt.holders)
+
+			if len(tt.holders) == 0 {
+				if got != 0 {
+					t.Errorf("Winner() = %d, want 0", got)
+				}
+				return
+			}
+
+			if got < 1 || got > len(tt.holders) {
+				t.Errorf("Winner() = %d, want a number from 1 to %d", got, len(tt.holders))
+			}
+
+			if again := Winner(tt.seed, tt.holders); again != got {
+				t.Errorf("Winner() = %d on a repeated draw, want %d", again, got)
+			}
+		})
+	}
+}
+
+func TestWinnerDependsOnTickets(t *testing.T) {
+	holders := []int{1, 2, 3, 4, 5, 6, 7, 8}
+
+	// the same seed must not always pick the same number once the ticket list changes
+	first := Winner("seed", holders)
+	for userID := 9; userID < 100; userID++ {
+		changed := append([]int(nil), holders...)
+		changed[0] = userID
+
+		if Winner("seed", changed) != first {
+			return
+		}
+	}
+
+	t.Errorf("Winner() picks ticket %d whoever holds the tickets", first)
+}
+
+func TestTicketsHash(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []int
+		same bool
+	}{
+		{name: "same tickets", a: []int{1, 2, 3}, b: []int{1, 2, 3}, same: true},
+		{name: "other order", a: []int{1, 2, 3}, b: []int{3, 2, 1}},
+		{name: "one more ticket", a: []int{1, 2, 3}, b: []int{1, 2, 3, 3}},
+		{name: "ambiguous concatenation", a: []int{1, 12}, b: []int{11, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if same := TicketsHash(tt.a) == TicketsHash(tt.b); same != tt.same {
+				t.Errorf("TicketsHash(%v) == TicketsHash(%v) is %v, want %v", tt.a, tt.b, same, tt.same)
+			}
+		})
+	}
+}
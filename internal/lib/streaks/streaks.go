@@ -0,0 +1,71 @@
+package streaks
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"time"
+)
+
+// Day returns the date of the moment in the location, as midnight UTC the way DATE columns are read.
+func Day(now time.Time, location *time.Location) time.Time {
+	local := now.In(location)
+
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Advance returns the streak after activity on the day. Missed days since the last activity are
+// covered by freezes if there are enough of them, otherwise the streak starts over. Activity on a day
+// that is already counted changes nothing.
+func Advance(streak model.Streak, day time.Time) model.Streak {
+	switch {
+	case streak.LastActiveOn.IsZero():
+		streak.Current = 1
+	case !day.After(streak.LastActiveOn):
+		return streak
+	default:
+		missed := daysBetween(streak.LastActiveOn, day) - 1
+		if missed <= streak.Freezes {
+			streak.Freezes -= missed
+			streak.Current++
+		} else {
+			streak.Current = 1
+		}
+	}
+
+	streak.LastActiveOn = day
+	if streak.Current > streak.Longest {
+		streak.Longest = streak.Current
+	}
+
+	return streak
+}
+
+// At returns the streak as seen on the day: a streak with more missed days than freezes is broken
+// and its current length is zero. Freezes are only spent by Advance.
+func At(streak model.Streak, day time.Time) model.Streak {
+	if streak.LastActiveOn.IsZero() {
+		return streak
+	}
+
+	if missed := daysBetween(streak.LastActiveOn, day) - 1; missed > streak.Freezes {
+		streak.Current = 0
+	}
+
+	return streak
+}
+
+// Bonus returns the check-in bonus for the day of the streak, the last bonus applies to longer streaks.
+func Bonus(bonuses []float64, day int) float64 {
+	if len(bonuses) == 0 || day <= 0 {
+		return 0
+	}
+
+	if day > len(bonuses) {
+		day = len(bonuses)
+	}
+
+	return bonuses[day-1]
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
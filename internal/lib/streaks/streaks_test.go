@@ -0,0 +1,140 @@
+package streaks
+
+import (
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"testing"
+	"time"
+)
+
+func date(day int) time.Time {
+	return time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestDay(t *testing.T) {
+	now := time.Date(2024, 1, 1, 22, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		location *time.Location
+		want     time.Time
+	}{
+		{name: "utc", location: time.UTC, want: date(1)},
+		{name: "east of utc", location: time.FixedZone("UTC+3", 3*60*60), want: date(2)},
+		{name: "west of utc", location: time.FixedZone("UTC-5", -5*60*60), want: date(1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Day(now, tt.location); !got.Equal(tt.want) {
+				t.Errorf("Day() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdvance(t *testing.T) {
+	tests := []struct {
+		name   string
+		streak model.Streak
+		day    time.Time
+		want   model.Streak
+	}{
+		{
+			name: "first activity",
+			day:  date(1),
+			want: model.Streak{Current: 1, Longest: 1, LastActiveOn: date(1)},
+		},
+		{
+			name:   "same day",
+			streak: model.Streak{Current: 3, Longest: 5, LastActiveOn: date(5)},
+			day:    date(5),
+			want:   model.Streak{Current: 3, Longest: 5, LastActiveOn: date(5)},
+		},
+		{
+			name:   "next day",
+			streak: model.Streak{Current: 3, Longest: 5, LastActiveOn: date(5)},
+			day:    date(6),
+			want:   model.Streak{Current: 4, Longest: 5, LastActiveOn: date(6)},
+		},
+		{
+			name:   "new longest",
+			streak: model.Streak{Current: 5, Longest: 5, LastActiveOn: date(5)},
+			day:    date(6),
+			want:   model.Streak{Current: 6, Longest: 6, LastActiveOn: date(6)},
+		},
+		{
+			name:   "missed days covered by freezes",
+			streak: model.Streak{Current: 3, Longest: 3, Freezes: 3, LastActiveOn: date(5)},
+			day:    date(8),
+			want:   model.Streak{Current: 4, Longest: 4, Freezes: 1, LastActiveOn: date(8)},
+		},
+		{
+			name:   "not enough freezes",
+			streak: model.Streak{Current: 3, Longest: 3, Freezes: 1, LastActiveOn: date(5)},
+			day:    date(8),
+			want:   model.Streak{Current: 1, Longest: 3, Freezes: 1, LastActiveOn: date(8)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Advance(tt.streak, tt.day); got != tt.want {
+				t.Errorf("Advance() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAt(t *testing.T) {
+	tests := []struct {
+		name   string
+		streak model.Streak
+		day    time.Time
+		want   int
+	}{
+		{name: "no activity", day: date(5), want: 0},
+		{name: "active today", streak: model.Streak{Current: 3, LastActiveOn: date(5)}, day: date(5), want: 3},
+		{name: "active yesterday", streak: model.Streak{Current: 3, LastActiveOn: date(5)}, day: date(6), want: 3},
+		{name: "missed day covered by a freeze", streak: model.Streak{Current: 3, Freezes: 1, LastActiveOn: date(5)}, day: date(7), want: 3},
+		{name: "broken", streak: model.Streak{Current: 3, LastActiveOn: date(5)}, day: date(7), want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := At(tt.streak, tt.day)
+
+			if got.Current != tt.want {
+				t.Errorf("At() current = %d, want %d", got.Current, tt.want)
+			}
+
+			if got.Freezes != tt.streak.Freezes {
+				t.Errorf("At() spent freezes: %d, want %d", got.Freezes, tt.streak.Freezes)
+			}
+		})
+	}
+}
+
+func TestBonus(t *testing.T) {
+	bonuses := []float64{1, 2, 5}
+
+	tests := []struct {
+		name    string
+		bonuses []float64
+		day     int
+		want    float64
+	}{
+		{name: "no bonuses", day: 1, want: 0},
+		{name: "no streak", bonuses: bonuses, day: 0, want: 0},
+		{name: "first day", bonuses: bonuses, day: 1, want: 1},
+		{name: "last listed day", bonuses: bonuses, day: 3, want: 5},
+		{name: "longer streak", bonuses: bonuses, day: 10, want: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Bonus(tt.bonuses, tt.day); got != tt.want {
+				t.Errorf("Bonus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -11,6 +11,8 @@ type User struct {
 	ClassID      int
 	RegisteredAt time.Time
 	HiredAt      time.Time
+	XP           int
+	Level        int
 }
 
 type Transaction struct {
@@ -20,15 +22,21 @@ type Transaction struct {
 	Amount     float64
 	TypeID     int
 	StatusID   int
-	CreatedAt  time.Time
+	// TaskID is set for rewards paid for a task
+	TaskID    int
+	CreatedAt time.Time
 }
 
 type ShopItem struct {
-	ID          int
-	Name        string
-	Description string
-	Price       float64
-	InStock     int
+	ID               int
+	Name             string
+	Description      string
+	Price            float64
+	InStock          int
+	MinLevel         int
+	StreakFreezes    int
+	ResourceID       int
+	ResourceQuantity int
 }
 
 type Purchase struct {
@@ -49,27 +57,828 @@ type Admin struct {
 }
 
 type Task struct {
+	ID           int
+	Name         string
+	Description  string
+	StatusID     int
+	Amount       float64
+	CreatedAt    time.Time
+	CreatedBy    int
+	ForGroupID   int
+	UserID       int
+	CategoryID   int
+	CancelReason string
+	SubmittedAt  time.Time
+	BudgetID     int
+	Deadline     time.Time
+	// UserDeadline is the deadline of the assignee, fixed with their perks when the task became theirs
+	UserDeadline time.Time
+	// XP is awarded on acceptance, zero means it is derived from Amount
+	XP       int
+	MinLevel int
+}
+
+// TaskPayout is what accepting a task pays: Reward goes from SenderID to the assignee, the budget
+// of the task releases Reserved coins and spends Reward, so perk bonuses count against it.
+type TaskPayout struct {
+	SenderID int
+	Reward   float64
+	Reserved float64
+}
+
+// TaskUpdate holds fields an admin may change on an existing task, nil fields stay untouched.
+type TaskUpdate struct {
+	Name        *string
+	Description *string
+	Amount      *float64
+	CategoryID  *int
+	XP          *int
+	MinLevel    *int
+}
+
+type TaskFilter struct {
+	StatusIDs   []int
+	GroupID     int
+	CategoryID  int
+	UserID      int
+	MinAmount   float64
+	MaxAmount   float64
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	Search      string
+	Sort        string
+	Order       string
+	Limit       int
+	Cursor      string
+	AfterValue  string
+	AfterID     int
+}
+
+type TaskPage struct {
+	Tasks      []Task
+	Total      int
+	NextCursor string
+}
+
+// Business produces with its recipe once per recipe duration since ProducedAt. Zero OwnerID means
+// the business is for sale.
+type Business struct {
 	ID         int
 	Name       string
-	StatusID   int
-	Amount     float64
-	CreatedAt  time.Time
-	CreatedBy  int
-	ForGroupID int
-	UserID     int
+	TypeID     int
+	TypeName   string
+	OwnerID    int
+	Price      float64
+	RecipeID   int
+	ProducedAt time.Time
 }
 
-type Business struct {
+// BusinessType with Capacity raises the storage capacity of the owner, as warehouses do.
+type BusinessType struct {
+	ID          int
+	Name        string
+	Description string
+	Profit      float64
+	Capacity    int
+}
+
+type SearchQuery struct {
+	Text  string
+	Types []string
+	Limit int
+}
+
+type SearchResult struct {
+	Type    string
 	ID      int
+	Title   string
+	Snippet string
+	Rank    float64
+}
+
+type Notification struct {
+	ID        int
+	UserID    int
+	TypeID    int
+	Message   string
+	TaskID    int
+	CreatedAt time.Time
+	ReadAt    time.Time
+}
+
+type ReviewDelegation struct {
+	ID          int
+	FromAdminID int
+	ToAdminID   int
+	StartsAt    time.Time
+	EndsAt      time.Time
+	RevokedAt   time.Time
+	CreatedAt   time.Time
+}
+
+// Budget limits how many coins an admin, or all admins creating tasks for a group, may hand out during a period.
+type Budget struct {
+	ID          int
+	AdminID     int
+	GroupID     int
+	PeriodID    int
+	StartsAt    time.Time
+	EndsAt      time.Time
+	Amount      float64
+	Reserved    float64
+	Spent       float64
+	AllocatedBy int
+	CreatedAt   time.Time
+}
+
+// Perk is a passive ability granted to every user of a class.
+type Perk struct {
+	Name        string
+	Description string
+	MinLevel    int
+	Unlocked    bool
+	Strength    float64
+}
+
+// Approval is a request for a second admin to confirm creation or acceptance of a high-value task.
+type Approval struct {
+	ID          int
+	TaskID      int
+	ActionID    int
+	StatusID    int
+	Amount      float64
+	RequestedBy int
+	DecidedBy   int
+	Comment     string
+	CreatedAt   time.Time
+	DecidedAt   time.Time
+}
+
+// Intercept is an attempt of a racoon to take over a task another user is working on.
+// Roll is derived from Seed, so anyone can replay the outcome.
+type Intercept struct {
+	ID        int
+	TaskID    int
+	ThiefID   int
+	VictimID  int
+	Seed      int64
+	Roll      float64
+	Chance    float64
+	Success   bool
+	Penalty   float64
+	CreatedAt time.Time
+}
+
+// Intel is a paid look at the tasks another user is working on, valid until ExpiresAt.
+type Intel struct {
+	ID            int
+	BuyerID       int
+	TargetID      int
+	TransactionID int
+	Fee           float64
+	ExpiresAt     time.Time
+	CreatedAt     time.Time
+}
+
+// XPEntry is a record of the XP ledger. XP is never spent, so the ledger only grows.
+type XPEntry struct {
+	ID            int
+	UserID        int
+	SourceID      int
+	TaskID        int
+	AchievementID int
+	QuizID        int
+	Amount        int
+	CreatedAt     time.Time
+}
+
+// XPAward is the result of adding XP: the new total and the levels before and after it.
+type XPAward struct {
+	Amount   int
+	TotalXP  int
+	OldLevel int
+	NewLevel int
+}
+
+type LevelUp struct {
+	ID        int
+	UserID    int
+	Level     int
+	CreatedAt time.Time
+}
+
+// LevelProgress shows where the user is between the current and the next level.
+type LevelProgress struct {
+	XP          int
+	Level       int
+	LevelXP     int
+	NextLevelXP int
+}
+
+// LeaderboardFilter selects a leaderboard. GroupID limits task based metrics to tasks of the group,
+// Since and Until limit earnings to those received in between. Zero Until means no upper bound.
+type LeaderboardFilter struct {
+	Metric  string
+	ClassID int
+	GroupID int
+	Since   time.Time
+	Until   time.Time
+	Limit   int
+	Offset  int
+}
+
+// LeaderboardEntry is a place on a leaderboard. Rank is unique, ties are broken by user ID.
+type LeaderboardEntry struct {
+	Rank     int
+	UserID   int
+	Username string
+	ClassID  int
+	Score    float64
+}
+
+// Achievement is a badge the user gets once the value of Metric reaches Threshold.
+// Period "month" counts the current calendar month only.
+type Achievement struct {
+	ID             int
+	Code           string
+	Name           string
+	Description    string
+	Metric         string
+	Threshold      int
+	Period         string
+	BusinessTypeID int
+	RewardCoins    float64
+	RewardXP       int
+}
+
+type UserAchievement struct {
+	Achievement   Achievement
+	UserID        int
+	TransactionID int
+	AwardedAt     time.Time
+}
+
+// AchievementProgress is the current value of the achievement metric for the user.
+type AchievementProgress struct {
+	Achievement Achievement
+	Value       int
+	Awarded     bool
+	AwardedAt   time.Time
+}
+
+// ReviewQueueItem is a task waiting for acceptance together with how long it has been waiting.
+type ReviewQueueItem struct {
+	Task      Task
+	Age       time.Duration
+	SLAStatus string
+}
+
+// Season is a competition period. Earnings between StartsAt and EndsAt make up the season leaderboard,
+// FinalizedAt is set once the final standings are saved and the prizes are paid.
+type Season struct {
+	ID          int
+	Name        string
+	StartsAt    time.Time
+	EndsAt      time.Time
+	CreatedBy   int
+	FinalizedAt time.Time
+	CreatedAt   time.Time
+}
+
+// SeasonStanding is a place in the final standings of a season. TransactionID is set for prize winners.
+type SeasonStanding struct {
+	SeasonID      int
+	Rank          int
+	UserID        int
+	Username      string
+	ClassID       int
+	Score         float64
+	Prize         float64
+	TransactionID int
+}
+
+// Streak counts consecutive days with a completed task or a check-in. Days are dates in the company
+// time zone, LastActiveOn is the last such day. A freeze covers one missed day.
+type Streak struct {
+	UserID       int
+	Current      int
+	Longest      int
+	Freezes      int
+	LastActiveOn time.Time
+}
+
+// CheckIn is the daily check-in of a user, Bonus is paid for day Streak of the streak.
+type CheckIn struct {
+	ID            int
+	UserID        int
+	Day           time.Time
+	Streak        int
+	Bonus         float64
+	TransactionID int
+	CreatedAt     time.Time
+}
+
+// Kudos is a thank-you from one user to another. Amount is taken from the sender's monthly allowance
+// of Period, the receiver gets Coins for it.
+type Kudos struct {
+	ID            int
+	SenderID      int
+	SenderName    string
+	ReceiverID    int
+	ReceiverName  string
+	Amount        int
+	Coins         float64
+	Message       string
+	Period        time.Time
+	TransactionID int
+	CreatedAt     time.Time
+}
+
+// KudosAllowance is what a user may still give this month. It does not carry over, ExpiresAt is
+// the start of the next month in the company time zone.
+type KudosAllowance struct {
+	Allowance int
+	Given     int
+	Remaining int
+	ExpiresAt time.Time
+}
+
+// Team is a group of users led by one of them. Wallet holds the coins members contributed
+// that the leader has not paid out yet.
+type Team struct {
+	ID         int
+	Name       string
+	LeaderID   int
+	LeaderName string
+	Members    int
+	Wallet     float64
+	CreatedBy  int
+	CreatedAt  time.Time
+}
+
+type TeamMember struct {
+	TeamID   int
+	UserID   int
+	Username string
+	RoleID   int
+	JoinedAt time.Time
+}
+
+type TeamInvitation struct {
+	ID          int
+	TeamID      int
+	TeamName    string
+	UserID      int
+	InvitedBy   int
+	StatusID    int
+	CreatedAt   time.Time
+	RespondedAt time.Time
+}
+
+// TeamWalletEntry is a movement of the team wallet: a contribution of UserID when Amount is positive,
+// a payout to UserID when it is negative.
+type TeamWalletEntry struct {
+	ID            int
+	TeamID        int
+	UserID        int
+	Username      string
+	Amount        float64
+	TransactionID int
+	CreatedAt     time.Time
+}
+
+// TeamTask is completed by a team once RequiredMembers of its members have each submitted it,
+// Amount is then split between them. A task without TeamID is open to every team.
+type TeamTask struct {
+	ID              int
+	Name            string
+	Description     string
+	Amount          float64
+	RequiredMembers int
+	TeamID          int
+	BudgetID        int
+	CreatedBy       int
+	CreatedAt       time.Time
+}
+
+// TeamTaskProgress is how far a team is with a team task.
+type TeamTaskProgress struct {
+	Task          TeamTask
+	Submitted     int
+	SubmittedByMe bool
+	CompletedAt   time.Time
+	AcceptedAt    time.Time
+}
+
+// TeamTaskCompletion is a team task completed by a team, waiting for an admin to accept it.
+// Share is what each of the submitters got once it was accepted.
+type TeamTaskCompletion struct {
+	ID           int
+	TeamTaskID   int
+	TaskName     string
+	TeamID       int
+	TeamName     string
+	Amount       float64
+	Share        float64
+	SubmitterIDs []int
+	CompletedAt  time.Time
+	AcceptedAt   time.Time
+	AcceptedBy   int
+}
+
+type TeamLeaderboardEntry struct {
+	Rank    int
+	TeamID  int
 	Name    string
-	TypeID  int
-	OwnerID int
-	Price   float64
+	Members int
+	Score   float64
 }
 
-type BusinessType struct {
+// Quest is a set of steps, each done as a task. A step is unlocked for a user once all the steps
+// it requires are completed, Bonus is paid on top of the task rewards for completing every step.
+// Users only see quests that have been published.
+type Quest struct {
 	ID          int
 	Name        string
 	Description string
-	Profit      float64
+	Bonus       float64
+	CreatedBy   int
+	BudgetID    int
+	PublishedAt time.Time
+	CreatedAt   time.Time
+	Steps       []QuestStep
+}
+
+// QuestStep is identified by its position in the quest, Requires holds the positions of its prerequisites.
+type QuestStep struct {
+	QuestID     int
+	Position    int
+	Name        string
+	Description string
+	Amount      float64
+	Requires    []int
+}
+
+// QuestProgress is how far a user is with a quest. Steps holds the unlocked steps only.
+type QuestProgress struct {
+	QuestID            int
+	UserID             int
+	StartedAt          time.Time
+	CompletedAt        time.Time
+	BonusTransactionID int
+	Steps              []QuestStepProgress
+}
+
+// QuestStepProgress is an unlocked step of a user, done as the task TaskID.
+type QuestStepProgress struct {
+	Position     int
+	TaskID       int
+	TaskStatusID int
+	UnlockedAt   time.Time
+	CompletedAt  time.Time
+}
+
+// QuestAdvance is what the completion of a quest task changed: the steps it unlocked and
+// whether it completed the quest.
+type QuestAdvance struct {
+	QuestID   int
+	QuestName string
+	UserID    int
+	Unlocked  []QuestStepProgress
+	Completed bool
+	Bonus     float64
+}
+
+// UserQuest is a published quest as seen by a user, Progress is empty until the user starts it.
+type UserQuest struct {
+	Quest    Quest
+	Progress QuestProgress
+}
+
+// Duel is a challenge between two users with the same stake escrowed from both. A race is won by the
+// participant whose submission of TaskID is accepted, a wager is decided by an admin. The winner takes
+// both stakes, a duel that ends without a winner refunds them.
+type Duel struct {
+	ID             int
+	KindID         int
+	ChallengerID   int
+	ChallengerName string
+	OpponentID     int
+	OpponentName   string
+	Stake          float64
+	TaskID         int
+	Terms          string
+	StatusID       int
+	WinnerID       int
+	ResolvedBy     int
+	RespondBy      time.Time
+	Deadline       time.Time
+	CreatedAt      time.Time
+	AcceptedAt     time.Time
+	ResolvedAt     time.Time
+}
+
+// DuelRecord sums up the resolved duels of a user. Net is the coins won minus the stakes lost.
+type DuelRecord struct {
+	Wins     int
+	Losses   int
+	Refunded int
+	Net      float64
+}
+
+// Bounty is a job a user posts for other users, funded from the poster's own balance. The reward is
+// held until the poster approves the submission of the hunter who claimed it, or an admin decides
+// a dispute between them.
+type Bounty struct {
+	ID           int
+	PosterID     int
+	PosterName   string
+	Title        string
+	Description  string
+	Reward       float64
+	StatusID     int
+	HunterID     int
+	HunterName   string
+	Submission   string
+	RejectReason string
+	CreatedAt    time.Time
+	ClaimedAt    time.Time
+	SubmittedAt  time.Time
+	ClosedAt     time.Time
+}
+
+// BountyDispute escalates a bounty to admins. Paid tells whether the admin paid the hunter
+// or refunded the poster, it is only meaningful once the dispute is resolved.
+type BountyDispute struct {
+	ID          int
+	BountyID    int
+	BountyTitle string
+	Reward      float64
+	PosterID    int
+	HunterID    int
+	RaisedBy    int
+	Reason      string
+	Paid        bool
+	Note        string
+	ResolvedBy  int
+	CreatedAt   time.Time
+	ResolvedAt  time.Time
+}
+
+// DropCampaign spawns coin drops at random, on average Rate drops per hour between StartsAt and EndsAt.
+// Each drop pays Reward to each of the first ClaimsPerDrop users who claim it within Lifetime,
+// and no drop is spawned unless the rest of the Budget still covers it.
+type DropCampaign struct {
+	ID            int
+	Name          string
+	Budget        float64
+	Spent         float64
+	Reward        float64
+	ClaimsPerDrop int
+	Rate          float64
+	Lifetime      time.Duration
+	StartsAt      time.Time
+	EndsAt        time.Time
+	CreatedBy     int
+	CreatedAt     time.Time
+	StoppedAt     time.Time
+}
+
+// Drop is coins lying around for a while. Claimed tells whether the user the drop was fetched for has claimed it.
+type Drop struct {
+	ID           int
+	CampaignID   int
+	CampaignName string
+	Reward       float64
+	MaxClaims    int
+	Claims       int
+	AppearsAt    time.Time
+	ExpiresAt    time.Time
+	Claimed      bool
+}
+
+// Raffle sells tickets for a prize, a shop item, coins or both, and draws the winning ticket at DrawAt.
+// Commitment is published from the start, Seed is kept secret until the raffle is closed, then
+// the draw can be verified with the raffles library and the sold tickets. MyTickets counts the tickets of the user
+// the raffle was fetched for.
+type Raffle struct {
+	ID                int
+	Name              string
+	Description       string
+	TicketPrice       float64
+	MaxTicketsPerUser int
+	ItemID            int
+	ItemName          string
+	PrizeAmount       float64
+	StatusID          int
+	Seed              string
+	Commitment        string
+	DrawAt            time.Time
+	TicketsSold       int
+	WinningNumber     int
+	WinnerID          int
+	WinnerName        string
+	PurchaseID        int
+	CreatedBy         int
+	CreatedAt         time.Time
+	ClosedAt          time.Time
+	MyTickets         int
+}
+
+type RaffleTicket struct {
+	Number    int
+	UserID    int
+	Username  string
+	CreatedAt time.Time
+}
+
+// Event is a company event users RSVP to and check in at with a code shown at the venue. Zero Capacity
+// takes any number of RSVPs, Reward is paid once to each user who checks in. RSVPed and CheckedInAt
+// describe the user the event was fetched for.
+type Event struct {
+	ID          int
+	Title       string
+	Description string
+	Location    string
+	StartsAt    time.Time
+	EndsAt      time.Time
+	Capacity    int
+	Reward      float64
+	RSVPs       int
+	CheckedIn   int
+	CreatedBy   int
+	BudgetID    int
+	CreatedAt   time.Time
+	CancelledAt time.Time
+	RSVPed      bool
+	CheckedInAt time.Time
+}
+
+type EventRSVP struct {
+	EventID     int
+	UserID      int
+	Username    string
+	CreatedAt   time.Time
+	CheckedInAt time.Time
+}
+
+// EventCode lets users check in to the event until ExpiresAt. A OneTime code is good for a single check-in.
+// Token is the signed form of the code that is shown to users, it is not stored.
+type EventCode struct {
+	ID        int
+	EventID   int
+	OneTime   bool
+	ExpiresAt time.Time
+	Token     string
+	CreatedBy int
+	CreatedAt time.Time
+}
+
+// Quiz is a set of questions users answer once. A quiz is graded and pays Amount when the score in
+// percent reaches PassScore, a poll pays Amount to everyone who answers. Submitted tells whether the
+// user the quiz was fetched for has answered it.
+type Quiz struct {
+	ID          int
+	TypeID      int
+	Name        string
+	Description string
+	Amount      float64
+	PassScore   int
+	ClosesAt    time.Time
+	CreatedBy   int
+	BudgetID    int
+	CreatedAt   time.Time
+	ClosedAt    time.Time
+	Submissions int
+	Submitted   bool
+	Questions   []QuizQuestion
+}
+
+// QuizQuestion is identified by its position in the quiz. CorrectOptions holds option indexes from 0,
+// an answer to a numeric question is correct within Tolerance of CorrectNumber and a short text one
+// when it matches one of CorrectTexts. Polls have no correct answers.
+type QuizQuestion struct {
+	Position       int
+	KindID         int
+	Text           string
+	Options        []string
+	CorrectOptions []int
+	CorrectNumber  *float64
+	Tolerance      float64
+	CorrectTexts   []string
+	Points         int
+}
+
+type QuizAnswer struct {
+	Position int
+	Options  []int
+	Number   *float64
+	Text     string
+	Correct  bool
+}
+
+type QuizSubmission struct {
+	ID          int
+	QuizID      int
+	UserID      int
+	Score       float64
+	Passed      bool
+	Reward      float64
+	SubmittedAt time.Time
+	Answers     []QuizAnswer
+}
+
+// QuizQuestionResult aggregates the answers to a question. OptionCounts counts the choices of every
+// option, Average, Min and Max describe numeric answers and Texts holds the most frequent short texts.
+type QuizQuestionResult struct {
+	Position     int
+	KindID       int
+	Text         string
+	Answers      int
+	Options      []string
+	OptionCounts []int
+	Average      float64
+	Min          float64
+	Max          float64
+	Texts        []QuizTextCount
+}
+
+type QuizTextCount struct {
+	Text  string
+	Count int
+}
+
+// Resource is a good businesses produce and users keep in their inventories, Price is what
+// the game pays for a unit of it.
+// Resource is sold for Price per unit. A resource with Nutrition or Joy is food for companions.
+type Resource struct {
+	ID          int
+	Name        string
+	Description string
+	Price       float64
+	Nutrition   int
+	Joy         int
+}
+
+// Recipe turns the Inputs into the Output on a business of the type once per Duration.
+type Recipe struct {
+	ID             int
+	BusinessTypeID int
+	Name           string
+	Output         RecipeItem
+	Inputs         []RecipeItem
+	Duration       time.Duration
+}
+
+type RecipeItem struct {
+	ResourceID int
+	Name       string
+	Quantity   int
+}
+
+// Inventory holds the resources of the user, Used units out of Capacity.
+type Inventory struct {
+	UserID   int
+	Items    []InventoryItem
+	Used     int
+	Capacity int
+}
+
+type InventoryItem struct {
+	ResourceID int
+	Name       string
+	Price      float64
+	Nutrition  int
+	Joy        int
+	Quantity   int
+}
+
+type Production struct {
+	BusinessID int
+	OwnerID    int
+	RecipeID   int
+	Output     RecipeItem
+	ProducedAt time.Time
+}
+
+type ResourceSale struct {
+	ResourceID    int
+	Quantity      int
+	Amount        float64
+	TransactionID int
+	SoldAt        time.Time
+}
+
+// Companion is the animal of the user's class. Hunger and Happiness go from 0 to 100 and are as of
+// UpdatedAt, WellBeing is their combined share from 0 to 1 that scales the class perks.
+type Companion struct {
+	UserID    int
+	ClassID   int
+	Animal    string
+	Hunger    float64
+	Happiness float64
+	WellBeing float64
+	FedAt     time.Time
+	PlayedAt  time.Time
+	UpdatedAt time.Time
 }
@@ -0,0 +1,229 @@
+package achievements
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	notificationsstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/notifications"
+	"log/slog"
+	"time"
+)
+
+// Domain events achievements are evaluated on.
+const (
+	EventTaskAccepted   = "task_accepted"
+	EventPurchase       = "purchase"
+	EventTransfer       = "transfer"
+	EventBusinessBought = "business_bought"
+)
+
+type Achievements struct {
+	log                  *slog.Logger
+	storage              Storage
+	notificationsStorage NotificationsStorage
+	levels               Levels
+	metrics              map[string]Metric
+}
+
+type Storage interface {
+	GetAll(ctx context.Context) ([]model.Achievement, error)
+	GetByUser(ctx context.Context, userID int) ([]model.UserAchievement, error)
+	Award(ctx context.Context, userID int, achievement model.Achievement) (model.UserAchievement, error)
+	CountCompletedTasks(ctx context.Context, userID int, since time.Time) (int, error)
+	CountPurchases(ctx context.Context, userID int, since time.Time) (int, error)
+	CountTransfers(ctx context.Context, userID int, since time.Time) (int, error)
+	CountBusinesses(ctx context.Context, userID, typeID int) (int, error)
+	BalanceRank(ctx context.Context, userID int) (int, error)
+}
+
+type NotificationsStorage interface {
+	Add(ctx context.Context, notification model.Notification) error
+}
+
+type Levels interface {
+	AwardForAchievement(ctx context.Context, userID int, achievement model.Achievement) (model.XPAward, error)
+}
+
+// Metric measures the progress of a user towards achievements that refer to it by name.
+// Achievements are checked only on the events the metric depends on.
+type Metric interface {
+	Name() string
+	Events() []string
+	Value(ctx context.Context, userID int, achievement model.Achievement, now time.Time) (int, error)
+	Reached(value int, achievement model.Achievement) bool
+}
+
+func New(
+	log *slog.Logger,
+	storage Storage,
+	notificationsStorage NotificationsStorage,
+	levels Levels,
+) *Achievements {
+	a := &Achievements{
+		log:                  log,
+		storage:              storage,
+		notificationsStorage: notificationsStorage,
+		levels:               levels,
+		metrics:              make(map[string]Metric),
+	}
+
+	a.Register(counter{name: "tasks_completed", events: []string{EventTaskAccepted}, count: storage.CountCompletedTasks})
+	a.Register(counter{name: "purchases", events: []string{EventPurchase}, count: storage.CountPurchases})
+	a.Register(counter{name: "transfers", events: []string{EventTransfer}, count: storage.CountTransfers})
+	a.Register(businessesOwned{storage: storage})
+	a.Register(balanceRank{storage: storage})
+
+	return a
+}
+
+// Register makes the metric available to achievements. A metric registered under a taken name replaces the old one.
+func (a *Achievements) Register(metric Metric) {
+	a.metrics[metric.Name()] = metric
+}
+
+// Evaluate checks the achievements that depend on the event and awards those the user has reached.
+// Evaluation is idempotent: an achievement is awarded once, however often the event comes.
+func (a *Achievements) Evaluate(ctx context.Context, userID int, event string) ([]model.UserAchievement, error) {
+	op := "achievements.Evaluate"
+
+	log := a.log.With(slog.String("op", op), slog.Int("userID", userID), slog.String("event", event))
+
+	progress, err := a.progress(ctx, log, userID, func(metric Metric) bool {
+		for _, e := range metric.Events() {
+			if e == event {
+				return true
+			}
+		}
+
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return a.awardReached(ctx, log, userID, progress), nil
+}
+
+// GetUserAchievements returns the achievements awarded to the user, newest first.
+func (a *Achievements) GetUserAchievements(ctx context.Context, userID int) ([]model.UserAchievement, error) {
+	op := "achievements.GetUserAchievements"
+
+	log := a.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	userAchievements, err := a.storage.GetByUser(ctx, userID)
+	if err != nil {
+		log.Error("failed to get user achievements", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return userAchievements, nil
+}
+
+// GetProgress returns all active achievements with the progress of the user. It awards nothing,
+// achievements are awarded only by Evaluate on the events their metrics depend on.
+func (a *Achievements) GetProgress(ctx context.Context, userID int) ([]model.AchievementProgress, error) {
+	op := "achievements.GetProgress"
+
+	log := a.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	return a.progress(ctx, log, userID, func(Metric) bool { return true })
+}
+
+// progress computes the metric values of the achievements whose metric matches. Awarded achievements
+// are returned as such without computing their metric.
+func (a *Achievements) progress(ctx context.Context, log *slog.Logger, userID int, match func(Metric) bool) ([]model.AchievementProgress, error) {
+	achievements, err := a.storage.GetAll(ctx)
+	if err != nil {
+		log.Error("failed to get achievements", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	userAchievements, err := a.storage.GetByUser(ctx, userID)
+	if err != nil {
+		log.Error("failed to get user achievements", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	awarded := make(map[int]time.Time, len(userAchievements))
+	for _, userAchievement := range userAchievements {
+		awarded[userAchievement.Achievement.ID] = userAchievement.AwardedAt
+	}
+
+	now := time.Now()
+
+	progress := make([]model.AchievementProgress, 0, len(achievements))
+	for _, achievement := range achievements {
+		metric, ok := a.metrics[achievement.Metric]
+		if !ok {
+			log.Warn("unknown achievement metric", slog.String("code", achievement.Code), slog.String("metric", achievement.Metric))
+			continue
+		}
+
+		if !match(metric) {
+			continue
+		}
+
+		item := model.AchievementProgress{Achievement: achievement}
+
+		if awardedAt, ok := awarded[achievement.ID]; ok {
+			item.Awarded = true
+			item.AwardedAt = awardedAt
+			item.Value = achievement.Threshold
+			progress = append(progress, item)
+			continue
+		}
+
+		item.Value, err = metric.Value(ctx, userID, achievement, now)
+		if err != nil {
+			log.Error("failed to get metric value", slog.String("code", achievement.Code), slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		progress = append(progress, item)
+	}
+
+	return progress, nil
+}
+
+// awardReached awards the reached achievements that are not awarded yet. Failures are logged,
+// the achievement stays unawarded and is picked up by the next evaluation.
+func (a *Achievements) awardReached(ctx context.Context, log *slog.Logger, userID int, progress []model.AchievementProgress) []model.UserAchievement {
+	var awarded []model.UserAchievement
+
+	for _, item := range progress {
+		if item.Awarded || !a.metrics[item.Achievement.Metric].Reached(item.Value, item.Achievement) {
+			continue
+		}
+
+		userAchievement, err := a.storage.Award(ctx, userID, item.Achievement)
+		if err != nil {
+			if errors.Is(err, errs.ErrAchievementAlreadyAwarded) {
+				continue
+			}
+
+			log.Error("failed to award achievement", slog.String("code", item.Achievement.Code), slog.String("error", err.Error()))
+			continue
+		}
+
+		log.Info("achievement unlocked", slog.String("code", item.Achievement.Code))
+
+		if _, err := a.levels.AwardForAchievement(ctx, userID, item.Achievement); err != nil {
+			log.Error("failed to award achievement xp", slog.String("code", item.Achievement.Code), slog.String("error", err.Error()))
+		}
+
+		err = a.notificationsStorage.Add(ctx, model.Notification{
+			UserID:  userID,
+			TypeID:  notificationsstorage.AchievementUnlockedTypeID,
+			Message: fmt.Sprintf("Achievement unlocked: %s", item.Achievement.Name),
+		})
+		if err != nil {
+			log.Error("failed to notify about achievement", slog.String("error", err.Error()))
+		}
+
+		awarded = append(awarded, userAchievement)
+	}
+
+	return awarded
+}
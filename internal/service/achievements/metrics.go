@@ -0,0 +1,82 @@
+package achievements
+
+import (
+	"context"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	achievementsstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/achievements"
+	"time"
+)
+
+// counter is a metric that counts things the user did, optionally within the current month.
+type counter struct {
+	name   string
+	events []string
+	count  func(ctx context.Context, userID int, since time.Time) (int, error)
+}
+
+func (c counter) Name() string {
+	return c.name
+}
+
+func (c counter) Events() []string {
+	return c.events
+}
+
+func (c counter) Value(ctx context.Context, userID int, achievement model.Achievement, now time.Time) (int, error) {
+	return c.count(ctx, userID, periodStart(achievement.Period, now))
+}
+
+func (c counter) Reached(value int, achievement model.Achievement) bool {
+	return value >= achievement.Threshold
+}
+
+// businessesOwned counts businesses the user owns, of the achievement's business type if it has one.
+type businessesOwned struct {
+	storage Storage
+}
+
+func (b businessesOwned) Name() string {
+	return "businesses_owned"
+}
+
+func (b businessesOwned) Events() []string {
+	return []string{EventBusinessBought}
+}
+
+func (b businessesOwned) Value(ctx context.Context, userID int, achievement model.Achievement, _ time.Time) (int, error) {
+	return b.storage.CountBusinesses(ctx, userID, achievement.BusinessTypeID)
+}
+
+func (b businessesOwned) Reached(value int, achievement model.Achievement) bool {
+	return value >= achievement.Threshold
+}
+
+// balanceRank is the place of the user on the balance leaderboard, the threshold is the lowest place that counts.
+type balanceRank struct {
+	storage Storage
+}
+
+func (b balanceRank) Name() string {
+	return "balance_rank"
+}
+
+// the rank changes with any payment, so it is checked on every event that moves coins
+func (b balanceRank) Events() []string {
+	return []string{EventTaskAccepted, EventPurchase, EventTransfer, EventBusinessBought}
+}
+
+func (b balanceRank) Value(ctx context.Context, userID int, _ model.Achievement, _ time.Time) (int, error) {
+	return b.storage.BalanceRank(ctx, userID)
+}
+
+func (b balanceRank) Reached(value int, achievement model.Achievement) bool {
+	return value > 0 && value <= achievement.Threshold
+}
+
+func periodStart(period string, now time.Time) time.Time {
+	if period == achievementsstorage.PeriodMonth {
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,262 @@
+package approvals
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/pagination"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	approvalsstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/approvals"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	notificationsstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/notifications"
+	"log/slog"
+)
+
+var (
+	ErrApprovalNotFound = errors.New("approval not found")
+	ErrApprovalStale    = errors.New("task is no longer waiting for this approval")
+	ErrSelfApproval     = errors.New("approval must be decided by another admin")
+	ErrCreatorApproval  = errors.New("approval must be decided by an admin who did not create the task")
+	ErrBudgetExceeded   = errors.New("budget exceeded")
+)
+
+type Approvals struct {
+	log                  *slog.Logger
+	storage              Storage
+	tasksStorage         TasksStorage
+	notificationsStorage NotificationsStorage
+	budgetsStorage       BudgetsStorage
+	perks                Perks
+	tasks                Tasks
+}
+
+type Storage interface {
+	GetByID(ctx context.Context, approvalID int) (model.Approval, error)
+	GetAll(ctx context.Context, statusID, taskID, limit int) ([]model.Approval, error)
+	Approve(ctx context.Context, approvalID, adminID int, payout model.TaskPayout) (model.Approval, error)
+	Deny(ctx context.Context, approvalID, adminID int, reason string) (model.Approval, error)
+	CancelPending(ctx context.Context, taskID int) error
+}
+
+type TasksStorage interface {
+	GetByID(ctx context.Context, taskID int) (model.Task, error)
+}
+
+type NotificationsStorage interface {
+	Add(ctx context.Context, notification model.Notification) error
+}
+
+type Perks interface {
+	Reward(ctx context.Context, userID int, task model.Task) (float64, error)
+}
+
+// Tasks runs what follows the payout of an accepted task.
+type Tasks interface {
+	OnAccepted(ctx context.Context, task model.Task)
+}
+
+type BudgetsStorage interface {
+	Adjust(ctx context.Context, budgetID int, delta float64) error
+}
+
+func New(
+	log *slog.Logger,
+	storage Storage,
+	tasksStorage TasksStorage,
+	notificationsStorage NotificationsStorage,
+	budgetsStorage BudgetsStorage,
+	perks Perks,
+	tasks Tasks,
+) *Approvals {
+	return &Approvals{
+		log:                  log,
+		storage:              storage,
+		tasksStorage:         tasksStorage,
+		notificationsStorage: notificationsStorage,
+		budgetsStorage:       budgetsStorage,
+		perks:                perks,
+		tasks:                tasks,
+	}
+}
+
+// GetAll returns the approval audit log. Zero statusID or taskID returns approvals of any status or task.
+func (a *Approvals) GetAll(ctx context.Context, statusID, taskID, limit int) ([]model.Approval, error) {
+	op := "approvals.GetAll"
+
+	log := a.log.With(slog.String("op", op))
+
+	approvals, err := a.storage.GetAll(ctx, statusID, taskID, pagination.Limit(limit))
+	if err != nil {
+		log.Error("failed to get approvals", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return approvals, nil
+}
+
+// Approve confirms the request of another admin. An approved acceptance pays the reward to the assignee.
+func (a *Approvals) Approve(ctx context.Context, approvalID, adminID int) (model.Approval, error) {
+	op := "approvals.Approve"
+
+	log := a.log.With(slog.String("op", op), slog.Int("approvalID", approvalID), slog.Int("adminID", adminID))
+
+	log.Info("approving")
+
+	pending, err := a.getPending(ctx, approvalID, adminID)
+	if err != nil {
+		log.Error("cannot decide approval", slog.String("error", err.Error()))
+		return model.Approval{}, err
+	}
+
+	var (
+		task   model.Task
+		payout model.TaskPayout
+	)
+
+	if pending.ActionID == approvalsstorage.AcceptanceActionID {
+		task, err = a.tasksStorage.GetByID(ctx, pending.TaskID)
+		if err != nil {
+			log.Error("failed to get task", slog.String("error", err.Error()))
+			return model.Approval{}, err
+		}
+
+		// the approved amount is what the approver saw, class perks are applied on top of it
+		task.Amount = pending.Amount
+
+		reward, err := a.perks.Reward(ctx, task.UserID, task)
+		if err != nil {
+			log.Error("failed to calculate reward", slog.String("error", err.Error()))
+			return model.Approval{}, err
+		}
+
+		// the reward is sent on behalf of the admin who accepted the task
+		payout = model.TaskPayout{
+			SenderID: pending.RequestedBy,
+			Reward:   reward,
+			Reserved: pending.Amount,
+		}
+	}
+
+	approval, err := a.storage.Approve(ctx, approvalID, adminID, payout)
+	if err != nil {
+		log.Error("failed to approve", slog.String("error", err.Error()))
+		a.closeStale(ctx, log, pending, err)
+		return model.Approval{}, mapError(err)
+	}
+
+	if approval.ActionID == approvalsstorage.AcceptanceActionID {
+		a.tasks.OnAccepted(ctx, task)
+	}
+
+	log.Info("approved")
+
+	return approval, nil
+}
+
+// Deny rejects the request of another admin. A denied creation cancels the task,
+// a denied acceptance returns it to the assignee, who is notified about it.
+func (a *Approvals) Deny(ctx context.Context, approvalID, adminID int, reason string) (model.Approval, error) {
+	op := "approvals.Deny"
+
+	log := a.log.With(slog.String("op", op), slog.Int("approvalID", approvalID), slog.Int("adminID", adminID))
+
+	log.Info("denying")
+
+	pending, err := a.getPending(ctx, approvalID, adminID)
+	if err != nil {
+		log.Error("cannot decide approval", slog.String("error", err.Error()))
+		return model.Approval{}, err
+	}
+
+	approval, err := a.storage.Deny(ctx, approvalID, adminID, reason)
+	if err != nil {
+		log.Error("failed to deny", slog.String("error", err.Error()))
+		a.closeStale(ctx, log, pending, err)
+		return model.Approval{}, mapError(err)
+	}
+
+	task, err := a.tasksStorage.GetByID(ctx, approval.TaskID)
+	if err != nil {
+		log.Error("failed to get task", slog.String("error", err.Error()))
+		return approval, nil
+	}
+
+	// a denied creation cancels the task, so the coins reserved for it are free again
+	if approval.ActionID == approvalsstorage.CreationActionID && task.BudgetID != 0 {
+		if err := a.budgetsStorage.Adjust(ctx, task.BudgetID, -task.Amount); err != nil {
+			log.Error("failed to release budget", slog.String("error", err.Error()))
+		}
+	}
+
+	if approval.ActionID == approvalsstorage.AcceptanceActionID {
+		if task.UserID != 0 {
+			err = a.notificationsStorage.Add(ctx, model.Notification{
+				UserID:  task.UserID,
+				TypeID:  notificationsstorage.TaskAcceptanceDeniedTypeID,
+				Message: fmt.Sprintf("Acceptance of task \"%s\" was denied: %s", task.Name, reason),
+				TaskID:  task.ID,
+			})
+			if err != nil {
+				log.Error("failed to notify assignee", slog.String("error", err.Error()))
+			}
+		}
+	}
+
+	log.Info("denied")
+
+	return approval, nil
+}
+
+// getPending returns the approval if it still waits for a decision and adminID is allowed to make it:
+// neither the admin who requested it nor the creator of the task may decide.
+func (a *Approvals) getPending(ctx context.Context, approvalID, adminID int) (model.Approval, error) {
+	approval, err := a.storage.GetByID(ctx, approvalID)
+	if err != nil {
+		return model.Approval{}, mapError(err)
+	}
+
+	if approval.StatusID != approvalsstorage.PendingStatusID {
+		return model.Approval{}, ErrApprovalNotFound
+	}
+
+	if approval.RequestedBy == adminID {
+		return model.Approval{}, ErrSelfApproval
+	}
+
+	task, err := a.tasksStorage.GetByID(ctx, approval.TaskID)
+	if err != nil {
+		return model.Approval{}, err
+	}
+
+	if task.CreatedBy == adminID {
+		return model.Approval{}, ErrCreatorApproval
+	}
+
+	return approval, nil
+}
+
+// closeStale drops an approval whose task was closed or changed in the meantime, so it leaves the pending list.
+func (a *Approvals) closeStale(ctx context.Context, log *slog.Logger, approval model.Approval, err error) {
+	if !errors.Is(err, errs.ErrApprovalStale) {
+		return
+	}
+
+	if err := a.storage.CancelPending(ctx, approval.TaskID); err != nil {
+		log.Error("failed to cancel stale approval", slog.String("error", err.Error()))
+	}
+}
+
+func mapError(err error) error {
+	switch {
+	case errors.Is(err, errs.ErrApprovalNotFound):
+		return ErrApprovalNotFound
+	case errors.Is(err, errs.ErrApprovalStale):
+		return ErrApprovalStale
+	case errors.Is(err, errs.ErrSelfApproval):
+		return ErrSelfApproval
+	case errors.Is(err, errs.ErrBudgetExceeded):
+		return ErrBudgetExceeded
+	default:
+		return err
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/k6mil6/hackathon-game-backend/internal/lib/jwt"
 	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/admins"
 	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
 	"golang.org/x/crypto/bcrypt"
 	"log/slog"
@@ -13,10 +14,12 @@ import (
 )
 
 var (
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrUserExists         = errors.New("user already exists")
-	ErrAdminNotFound      = errors.New("admin not found")
-	ErrAdminExists        = errors.New("admin already exists")
+	ErrInvalidCredentials  = errors.New("invalid credentials")
+	ErrUserExists          = errors.New("user already exists")
+	ErrAdminNotFound       = errors.New("admin not found")
+	ErrAdminExists         = errors.New("admin already exists")
+	ErrNotEnoughPermission = errors.New("not enough permission")
+	ErrInvalidClass        = errors.New("invalid class")
 )
 
 type Auth struct {
@@ -83,7 +86,7 @@ func (a *Auth) LoginUser(ctx context.Context, username string, password string)
 
 	log.Info("user logged in")
 
-	token, err := jwt.NewToken(user.ID, user.Username, a.tokenTTL, a.secret)
+	token, err := jwt.NewToken(user.ID, user.Username, jwt.RoleUser, a.tokenTTL, a.secret)
 	if err != nil {
 		log.Error("failed to create token", err)
 		return "", fmt.Errorf("%s: %w", op, err)
@@ -121,6 +124,11 @@ func (a *Auth) RegisterUser(ctx context.Context, username string, password strin
 			return 0, fmt.Errorf("%s: %w", op, ErrUserExists)
 		}
 
+		if errors.Is(err, errs.ErrClassNotFound) {
+			log.Error("class not found", slog.Int("classID", classID))
+			return 0, fmt.Errorf("%s: %w", op, ErrInvalidClass)
+		}
+
 		log.Error("failed to save user", err)
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
@@ -154,7 +162,7 @@ func (a *Auth) LoginAdmin(ctx context.Context, username string, password string)
 
 	log.Info("admin logged in")
 
-	token, err := jwt.NewToken(admin.ID, admin.Username, a.tokenTTL, a.secret)
+	token, err := jwt.NewToken(admin.ID, admin.Username, jwt.RoleAdmin, a.tokenTTL, a.secret)
 	if err != nil {
 		log.Error("failed to create token", err)
 		return "", fmt.Errorf("%s: %w", op, err)
@@ -181,6 +189,12 @@ func (a *Auth) RegisterAdmin(ctx context.Context, username, password string, reg
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
+	// only super admins can grant the right to allocate budgets
+	if roleID == admins.SuperAdminRoleID && registrant.RoleID != admins.SuperAdminRoleID {
+		log.Error("registrant is not a super admin")
+		return 0, fmt.Errorf("%s: %w", op, ErrNotEnoughPermission)
+	}
+
 	log.Info("attempting registration")
 	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
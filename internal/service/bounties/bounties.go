@@ -0,0 +1,449 @@
+package bounties
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/k6mil6/hackathon-game-backend/internal/config"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/pagination"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	bountiesstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/bounties"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	notificationsstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/notifications"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrNotEnoughPermission    = errors.New("not enough permission")
+	ErrTitleRequired          = errors.New("title is required")
+	ErrTitleTooLong           = errors.New("title is too long")
+	ErrTextTooLong            = errors.New("text is too long")
+	ErrInvalidReward          = errors.New("reward must be positive")
+	ErrRewardTooHigh          = errors.New("reward is too high")
+	ErrSubmissionRequired     = errors.New("submission is required")
+	ErrReasonRequired         = errors.New("reason is required")
+	ErrUserNotFound           = errors.New("user not found")
+	ErrInsufficientFunds      = errors.New("insufficient funds")
+	ErrBountyNotFound         = errors.New("bounty not found")
+	ErrBountyNotOpen          = errors.New("bounty is not open")
+	ErrBountyNotClaimed       = errors.New("bounty is not claimed by you")
+	ErrBountyNotSubmitted     = errors.New("bounty has no submission to review")
+	ErrBountyNotDisputable    = errors.New("only claimed or submitted bounties can be disputed")
+	ErrNotPoster              = errors.New("only the poster can do this")
+	ErrNotHunter              = errors.New("only the hunter can do this")
+	ErrOwnBounty              = errors.New("you can not claim your own bounty")
+	ErrDisputeNotFound        = errors.New("dispute not found")
+	ErrDisputeAlreadyResolved = errors.New("dispute was already resolved")
+	ErrInvalidOffset          = errors.New("offset can not be negative")
+)
+
+type Bounties struct {
+	log                  *slog.Logger
+	storage              Storage
+	notificationsStorage NotificationsStorage
+	cfg                  config.BountyConfig
+}
+
+type Storage interface {
+	Post(ctx context.Context, bounty model.Bounty) (model.Bounty, error)
+	Cancel(ctx context.Context, id, posterID int) (model.Bounty, error)
+	Claim(ctx context.Context, id, hunterID int) (model.Bounty, error)
+	Abandon(ctx context.Context, id, hunterID int) (model.Bounty, error)
+	Submit(ctx context.Context, id, hunterID int, submission string) (model.Bounty, error)
+	Approve(ctx context.Context, id, posterID int) (model.Bounty, error)
+	Reject(ctx context.Context, id, posterID int, reason string) (model.Bounty, error)
+	Dispute(ctx context.Context, id, userID int, reason string) (model.BountyDispute, error)
+	ResolveDispute(ctx context.Context, disputeID, adminID int, paid bool, note string) (model.BountyDispute, error)
+	GetByID(ctx context.Context, id int) (model.Bounty, error)
+	GetOpen(ctx context.Context, limit, offset int) ([]model.Bounty, error)
+	GetByUser(ctx context.Context, userID, limit, offset int) ([]model.Bounty, error)
+	GetDisputes(ctx context.Context, pending bool, limit int) ([]model.BountyDispute, error)
+}
+
+type NotificationsStorage interface {
+	Add(ctx context.Context, notification model.Notification) error
+}
+
+func New(
+	log *slog.Logger,
+	storage Storage,
+	notificationsStorage NotificationsStorage,
+	cfg config.BountyConfig,
+) *Bounties {
+	return &Bounties{
+		log:                  log,
+		storage:              storage,
+		notificationsStorage: notificationsStorage,
+		cfg:                  cfg,
+	}
+}
+
+// Post escrows the reward from the balance of the poster and publishes the bounty for other users.
+func (b *Bounties) Post(ctx context.Context, posterID int, bounty model.Bounty) (model.Bounty, error) {
+	op := "bounties.Post"
+
+	log := b.log.With(slog.String("op", op), slog.Int("posterID", posterID))
+
+	bounty.PosterID = posterID
+	bounty.Title = strings.TrimSpace(bounty.Title)
+	bounty.Description = strings.TrimSpace(bounty.Description)
+
+	if err := b.validate(bounty); err != nil {
+		log.Error("invalid bounty", slog.String("error", err.Error()))
+		return model.Bounty{}, err
+	}
+
+	posted, err := b.storage.Post(ctx, bounty)
+	if err != nil {
+		log.Error("failed to post bounty", slog.String("error", err.Error()))
+		return model.Bounty{}, mapError(err)
+	}
+
+	log.Info("bounty posted", slog.Int("id", posted.ID))
+
+	return posted, nil
+}
+
+// Cancel withdraws the open bounty of the poster and refunds the reward.
+func (b *Bounties) Cancel(ctx context.Context, id, userID int) (model.Bounty, error) {
+	op := "bounties.Cancel"
+
+	log := b.log.With(slog.String("op", op), slog.Int("id", id), slog.Int("userID", userID))
+
+	bounty, err := b.storage.Cancel(ctx, id, userID)
+	if err != nil {
+		log.Error("failed to cancel bounty", slog.String("error", err.Error()))
+		return model.Bounty{}, mapError(err)
+	}
+
+	log.Info("bounty cancelled")
+
+	return bounty, nil
+}
+
+// Claim reserves the open bounty for the user, nobody else can work on it until it is abandoned.
+func (b *Bounties) Claim(ctx context.Context, id, userID int) (model.Bounty, error) {
+	op := "bounties.Claim"
+
+	log := b.log.With(slog.String("op", op), slog.Int("id", id), slog.Int("userID", userID))
+
+	bounty, err := b.storage.Claim(ctx, id, userID)
+	if err != nil {
+		log.Error("failed to claim bounty", slog.String("error", err.Error()))
+		return model.Bounty{}, mapError(err)
+	}
+
+	b.notify(ctx, log, bounty.PosterID, fmt.Sprintf("%s claimed your bounty %q", bounty.HunterName, bounty.Title))
+
+	log.Info("bounty claimed")
+
+	return bounty, nil
+}
+
+// Abandon gives the claim of the user up, the bounty is open for others again.
+func (b *Bounties) Abandon(ctx context.Context, id, userID int) (model.Bounty, error) {
+	op := "bounties.Abandon"
+
+	log := b.log.With(slog.String("op", op), slog.Int("id", id), slog.Int("userID", userID))
+
+	bounty, err := b.storage.Abandon(ctx, id, userID)
+	if err != nil {
+		log.Error("failed to abandon bounty", slog.String("error", err.Error()))
+		return model.Bounty{}, mapError(err)
+	}
+
+	b.notify(ctx, log, bounty.PosterID, fmt.Sprintf("Your bounty %q was abandoned and is open again", bounty.Title))
+
+	log.Info("bounty abandoned")
+
+	return bounty, nil
+}
+
+// Submit hands the work of the hunter over to the poster, who approves or rejects it.
+func (b *Bounties) Submit(ctx context.Context, id, userID int, submission string) (model.Bounty, error) {
+	op := "bounties.Submit"
+
+	log := b.log.With(slog.String("op", op), slog.Int("id", id), slog.Int("userID", userID))
+
+	submission = strings.TrimSpace(submission)
+	if err := b.validateText(submission, ErrSubmissionRequired); err != nil {
+		log.Error("invalid submission", slog.String("error", err.Error()))
+		return model.Bounty{}, err
+	}
+
+	bounty, err := b.storage.Submit(ctx, id, userID, submission)
+	if err != nil {
+		log.Error("failed to submit bounty", slog.String("error", err.Error()))
+		return model.Bounty{}, mapError(err)
+	}
+
+	b.notify(ctx, log, bounty.PosterID, fmt.Sprintf("%s submitted your bounty %q for approval", bounty.HunterName, bounty.Title))
+
+	log.Info("bounty submitted")
+
+	return bounty, nil
+}
+
+// Approve accepts the submission on behalf of the poster and pays the reward to the hunter.
+func (b *Bounties) Approve(ctx context.Context, id, userID int) (model.Bounty, error) {
+	op := "bounties.Approve"
+
+	log := b.log.With(slog.String("op", op), slog.Int("id", id), slog.Int("userID", userID))
+
+	bounty, err := b.storage.Approve(ctx, id, userID)
+	if err != nil {
+		log.Error("failed to approve bounty", slog.String("error", err.Error()))
+		return model.Bounty{}, mapError(err)
+	}
+
+	b.notify(ctx, log, bounty.HunterID, fmt.Sprintf("Your work on the bounty %q was approved, you got %.2f coins", bounty.Title, bounty.Reward))
+
+	log.Info("bounty approved")
+
+	return bounty, nil
+}
+
+// Reject sends the submission back to the hunter with the reason of the poster.
+func (b *Bounties) Reject(ctx context.Context, id, userID int, reason string) (model.Bounty, error) {
+	op := "bounties.Reject"
+
+	log := b.log.With(slog.String("op", op), slog.Int("id", id), slog.Int("userID", userID))
+
+	reason = strings.TrimSpace(reason)
+	if err := b.validateText(reason, ErrReasonRequired); err != nil {
+		log.Error("invalid reason", slog.String("error", err.Error()))
+		return model.Bounty{}, err
+	}
+
+	bounty, err := b.storage.Reject(ctx, id, userID, reason)
+	if err != nil {
+		log.Error("failed to reject bounty", slog.String("error", err.Error()))
+		return model.Bounty{}, mapError(err)
+	}
+
+	b.notify(ctx, log, bounty.HunterID, fmt.Sprintf("Your work on the bounty %q was rejected: %s", bounty.Title, reason))
+
+	log.Info("bounty rejected")
+
+	return bounty, nil
+}
+
+// Dispute escalates the bounty to admins when its poster and hunter do not agree on the work.
+func (b *Bounties) Dispute(ctx context.Context, id, userID int, reason string) (model.BountyDispute, error) {
+	op := "bounties.Dispute"
+
+	log := b.log.With(slog.String("op", op), slog.Int("id", id), slog.Int("userID", userID))
+
+	reason = strings.TrimSpace(reason)
+	if err := b.validateText(reason, ErrReasonRequired); err != nil {
+		log.Error("invalid reason", slog.String("error", err.Error()))
+		return model.BountyDispute{}, err
+	}
+
+	dispute, err := b.storage.Dispute(ctx, id, userID, reason)
+	if err != nil {
+		log.Error("failed to dispute bounty", slog.String("error", err.Error()))
+		return model.BountyDispute{}, mapError(err)
+	}
+
+	otherID := dispute.PosterID
+	if userID == dispute.PosterID {
+		otherID = dispute.HunterID
+	}
+
+	b.notify(ctx, log, otherID, fmt.Sprintf("The bounty %q is disputed and will be decided by an admin", dispute.BountyTitle))
+
+	log.Info("bounty disputed", slog.Int("disputeID", dispute.ID))
+
+	return dispute, nil
+}
+
+// ResolveDispute decides the dispute by an admin, either paying the hunter or refunding the poster.
+func (b *Bounties) ResolveDispute(ctx context.Context, adminID, disputeID int, paid bool, note string) (model.BountyDispute, error) {
+	op := "bounties.ResolveDispute"
+
+	log := b.log.With(slog.String("op", op), slog.Int("adminID", adminID), slog.Int("disputeID", disputeID))
+
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > b.cfg.MaxTextLength {
+		return model.BountyDispute{}, ErrTextTooLong
+	}
+
+	dispute, err := b.storage.ResolveDispute(ctx, disputeID, adminID, paid, note)
+	if err != nil {
+		log.Error("failed to resolve dispute", slog.String("error", err.Error()))
+		return model.BountyDispute{}, mapError(err)
+	}
+
+	posterMessage := fmt.Sprintf("The dispute over the bounty %q was decided for the hunter, the reward was paid", dispute.BountyTitle)
+	hunterMessage := fmt.Sprintf("The dispute over the bounty %q was decided for you, you got %.2f coins", dispute.BountyTitle, dispute.Reward)
+	if !paid {
+		posterMessage = fmt.Sprintf("The dispute over the bounty %q was decided for you, %.2f coins were refunded", dispute.BountyTitle, dispute.Reward)
+		hunterMessage = fmt.Sprintf("The dispute over the bounty %q was decided for the poster, the reward was refunded", dispute.BountyTitle)
+	}
+
+	b.notify(ctx, log, dispute.PosterID, posterMessage)
+	b.notify(ctx, log, dispute.HunterID, hunterMessage)
+
+	log.Info("dispute resolved", slog.Bool("paid", paid))
+
+	return dispute, nil
+}
+
+// Get returns the bounty. Open bounties are visible to everyone, the others only to their poster and hunter.
+func (b *Bounties) Get(ctx context.Context, id, userID int) (model.Bounty, error) {
+	op := "bounties.Get"
+
+	log := b.log.With(slog.String("op", op), slog.Int("id", id), slog.Int("userID", userID))
+
+	bounty, err := b.storage.GetByID(ctx, id)
+	if err != nil {
+		log.Error("failed to get bounty", slog.String("error", err.Error()))
+		return model.Bounty{}, mapError(err)
+	}
+
+	if bounty.StatusID != bountiesstorage.OpenStatusID && bounty.PosterID != userID && bounty.HunterID != userID {
+		return model.Bounty{}, ErrBountyNotFound
+	}
+
+	return bounty, nil
+}
+
+// GetOpen returns a page of the bounties anyone can claim.
+func (b *Bounties) GetOpen(ctx context.Context, limit, offset int) ([]model.Bounty, error) {
+	op := "bounties.GetOpen"
+
+	log := b.log.With(slog.String("op", op))
+
+	if offset < 0 {
+		return nil, ErrInvalidOffset
+	}
+
+	bounties, err := b.storage.GetOpen(ctx, pagination.Limit(limit), offset)
+	if err != nil {
+		log.Error("failed to get bounties", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return bounties, nil
+}
+
+// GetMine returns a page of the bounties the user posted or claimed.
+func (b *Bounties) GetMine(ctx context.Context, userID, limit, offset int) ([]model.Bounty, error) {
+	op := "bounties.GetMine"
+
+	log := b.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	if offset < 0 {
+		return nil, ErrInvalidOffset
+	}
+
+	bounties, err := b.storage.GetByUser(ctx, userID, pagination.Limit(limit), offset)
+	if err != nil {
+		log.Error("failed to get bounties", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return bounties, nil
+}
+
+// GetDisputes returns the disputes for admins, only the unresolved ones if pending is set.
+func (b *Bounties) GetDisputes(ctx context.Context, adminID int, pending bool, limit int) ([]model.BountyDispute, error) {
+	op := "bounties.GetDisputes"
+
+	log := b.log.With(slog.String("op", op), slog.Int("adminID", adminID))
+
+	disputes, err := b.storage.GetDisputes(ctx, pending, pagination.Limit(limit))
+	if err != nil {
+		log.Error("failed to get disputes", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return disputes, nil
+}
+
+func (b *Bounties) validate(bounty model.Bounty) error {
+	if bounty.Title == "" {
+		return ErrTitleRequired
+	}
+
+	if utf8.RuneCountInString(bounty.Title) > b.cfg.MaxTitleLength {
+		return ErrTitleTooLong
+	}
+
+	if utf8.RuneCountInString(bounty.Description) > b.cfg.MaxTextLength {
+		return ErrTextTooLong
+	}
+
+	if bounty.Reward <= 0 {
+		return ErrInvalidReward
+	}
+
+	if b.cfg.MaxReward > 0 && bounty.Reward > b.cfg.MaxReward {
+		return ErrRewardTooHigh
+	}
+
+	return nil
+}
+
+// validateText checks a required text, empty returns errEmpty.
+func (b *Bounties) validateText(text string, errEmpty error) error {
+	if text == "" {
+		return errEmpty
+	}
+
+	if utf8.RuneCountInString(text) > b.cfg.MaxTextLength {
+		return ErrTextTooLong
+	}
+
+	return nil
+}
+
+// notify tells the user about a change of the bounty, failures are only logged.
+func (b *Bounties) notify(ctx context.Context, log *slog.Logger, userID int, message string) {
+	err := b.notificationsStorage.Add(ctx, model.Notification{
+		UserID:  userID,
+		TypeID:  notificationsstorage.BountyUpdateTypeID,
+		Message: message,
+	})
+	if err != nil {
+		log.Error("failed to notify about bounty", slog.Int("userID", userID), slog.String("error", err.Error()))
+	}
+}
+
+func mapError(err error) error {
+	switch {
+	case errors.Is(err, errs.ErrUserNotFound):
+		return ErrUserNotFound
+	case errors.Is(err, errs.ErrInsufficientFunds):
+		return ErrInsufficientFunds
+	case errors.Is(err, errs.ErrBountyNotFound):
+		return ErrBountyNotFound
+	case errors.Is(err, errs.ErrBountyNotOpen):
+		return ErrBountyNotOpen
+	case errors.Is(err, errs.ErrBountyNotClaimed):
+		return ErrBountyNotClaimed
+	case errors.Is(err, errs.ErrBountyNotSubmitted):
+		return ErrBountyNotSubmitted
+	case errors.Is(err, errs.ErrBountyNotDisputable):
+		return ErrBountyNotDisputable
+	case errors.Is(err, errs.ErrNotBountyPoster):
+		return ErrNotPoster
+	case errors.Is(err, errs.ErrNotBountyHunter):
+		return ErrNotHunter
+	case errors.Is(err, errs.ErrNotBountyParticipant):
+		return ErrBountyNotFound
+	case errors.Is(err, errs.ErrOwnBounty):
+		return ErrOwnBounty
+	case errors.Is(err, errs.ErrDisputeNotFound):
+		return ErrDisputeNotFound
+	case errors.Is(err, errs.ErrDisputeAlreadyResolved):
+		return ErrDisputeAlreadyResolved
+	case errors.Is(err, errs.ErrAdminNotFound):
+		return ErrNotEnoughPermission
+	}
+
+	return err
+}
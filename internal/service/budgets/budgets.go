@@ -0,0 +1,146 @@
+package budgets
+
+import (
+	"context"
+	"errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/pagination"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	budgetsstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/budgets"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"log/slog"
+	"time"
+)
+
+const (
+	PeriodMonth   = "month"
+	PeriodQuarter = "quarter"
+)
+
+var (
+	ErrInvalidOwner     = errors.New("exactly one of admin_id and group_id must be set")
+	ErrInvalidPeriod    = errors.New("period must be month or quarter")
+	ErrInvalidAmount    = errors.New("amount must be positive")
+	ErrBudgetBelowUsage = errors.New("budget is lower than already reserved and spent coins")
+	ErrOwnerNotFound    = errors.New("admin or group not found")
+)
+
+// Budgets bound the coins admins mint as rewards: task, team task, quest, quiz and event rewards
+// reserve coins from the budget of the admin who offers them and spend them when they are paid.
+// Out of scope are coins minted under other limits: drop campaigns carry their own budget, raffle
+// prizes are set per raffle, season prizes are set by super admins, achievement, streak and
+// resource sale coins come from the game configuration, and kudos, duels, bounties and team
+// wallets only move coins users already have.
+type Budgets struct {
+	log     *slog.Logger
+	storage Storage
+}
+
+type Storage interface {
+	Allocate(ctx context.Context, budget model.Budget) (int, error)
+	GetActive(ctx context.Context, adminID int, at time.Time) ([]model.Budget, error)
+	GetAll(ctx context.Context, adminID, groupID, limit int) ([]model.Budget, error)
+}
+
+func New(log *slog.Logger, storage Storage) *Budgets {
+	return &Budgets{
+		log:     log,
+		storage: storage,
+	}
+}
+
+// Allocate sets the budget of an admin or a group for the month or quarter containing at.
+// The router lets only super admins allocate budgets.
+func (b *Budgets) Allocate(ctx context.Context, superAdminID int, budget model.Budget, period string, at time.Time) (int, error) {
+	op := "budgets.Allocate"
+
+	log := b.log.With(slog.String("op", op), slog.Int("superAdminID", superAdminID))
+
+	log.Info("allocating budget")
+
+	if (budget.AdminID == 0) == (budget.GroupID == 0) {
+		return 0, ErrInvalidOwner
+	}
+
+	if budget.Amount <= 0 {
+		return 0, ErrInvalidAmount
+	}
+
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	periodID, startsAt, endsAt, err := periodBounds(period, at)
+	if err != nil {
+		return 0, err
+	}
+
+	budget.PeriodID = periodID
+	budget.StartsAt = startsAt
+	budget.EndsAt = endsAt
+	budget.AllocatedBy = superAdminID
+
+	id, err := b.storage.Allocate(ctx, budget)
+	if err != nil {
+		log.Error("failed to allocate budget", slog.String("error", err.Error()))
+
+		switch {
+		case errors.Is(err, errs.ErrBudgetBelowUsage):
+			return 0, ErrBudgetBelowUsage
+		case errors.Is(err, errs.ErrBudgetOwnerNotFound):
+			return 0, ErrOwnerNotFound
+		default:
+			return 0, err
+		}
+	}
+
+	log.Info("allocated budget", slog.Int("id", id))
+
+	return id, nil
+}
+
+// GetActive returns the current budgets the admin creates tasks against.
+func (b *Budgets) GetActive(ctx context.Context, adminID int) ([]model.Budget, error) {
+	op := "budgets.GetActive"
+
+	log := b.log.With(slog.String("op", op), slog.Int("adminID", adminID))
+
+	budgets, err := b.storage.GetActive(ctx, adminID, time.Now())
+	if err != nil {
+		log.Error("failed to get budgets", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return budgets, nil
+}
+
+// GetAll returns budgets of all admins and groups, the router shows them to super admins only.
+func (b *Budgets) GetAll(ctx context.Context, superAdminID, adminID, groupID, limit int) ([]model.Budget, error) {
+	op := "budgets.GetAll"
+
+	log := b.log.With(slog.String("op", op), slog.Int("superAdminID", superAdminID))
+
+	budgets, err := b.storage.GetAll(ctx, adminID, groupID, pagination.Limit(limit))
+	if err != nil {
+		log.Error("failed to get budgets", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return budgets, nil
+}
+
+// periodBounds returns the calendar month or quarter containing at, in UTC.
+func periodBounds(period string, at time.Time) (int, time.Time, time.Time, error) {
+	at = at.UTC()
+
+	switch period {
+	case PeriodMonth:
+		startsAt := time.Date(at.Year(), at.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return budgetsstorage.MonthPeriodID, startsAt, startsAt.AddDate(0, 1, 0), nil
+	case PeriodQuarter:
+		firstMonth := (at.Month()-1)/3*3 + 1
+		startsAt := time.Date(at.Year(), firstMonth, 1, 0, 0, 0, 0, time.UTC)
+		return budgetsstorage.QuarterPeriodID, startsAt, startsAt.AddDate(0, 3, 0), nil
+	default:
+		return 0, time.Time{}, time.Time{}, ErrInvalidPeriod
+	}
+}
@@ -0,0 +1,128 @@
+package companions
+
+import (
+	"context"
+	"errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/config"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/companions"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"log/slog"
+	"time"
+)
+
+var (
+	ErrInvalidQuantity    = errors.New("invalid quantity")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrResourceNotFound   = errors.New("resource not found")
+	ErrNotFood            = errors.New("resource is not food")
+	ErrNotEnoughResources = errors.New("not enough resources")
+	ErrTooSoon            = errors.New("companion does not want to play yet")
+)
+
+type Companions struct {
+	log     *slog.Logger
+	storage Storage
+	cfg     config.CompanionConfig
+}
+
+type Storage interface {
+	Get(ctx context.Context, userID int) (model.Companion, error)
+	Feed(ctx context.Context, userID, resourceID, quantity int, rates companions.Rates, now time.Time) (model.Companion, error)
+	Play(ctx context.Context, userID int, happiness, hunger float64, cooldown time.Duration, rates companions.Rates, now time.Time) (model.Companion, error)
+}
+
+func New(log *slog.Logger, storage Storage, cfg config.CompanionConfig) *Companions {
+	return &Companions{
+		log:     log,
+		storage: storage,
+		cfg:     cfg,
+	}
+}
+
+// Get returns the companion of the user as of now.
+func (c *Companions) Get(ctx context.Context, userID int) (model.Companion, error) {
+	op := "companions.Get"
+
+	log := c.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	companion, err := c.storage.Get(ctx, userID)
+	if err != nil {
+		log.Error("failed to get companion", slog.String("error", err.Error()))
+		return model.Companion{}, mapError(err)
+	}
+
+	return companions.At(companion, c.rates(), time.Now()), nil
+}
+
+// WellBeing returns the well-being of the companion of the user as of now.
+func (c *Companions) WellBeing(ctx context.Context, userID int) (float64, error) {
+	companion, err := c.Get(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return companion.WellBeing, nil
+}
+
+// Feed feeds the companion of the user with the food from their inventory.
+func (c *Companions) Feed(ctx context.Context, userID, resourceID, quantity int) (model.Companion, error) {
+	op := "companions.Feed"
+
+	log := c.log.With(slog.String("op", op), slog.Int("userID", userID), slog.Int("resourceID", resourceID))
+
+	if quantity <= 0 || quantity > c.cfg.MaxFeed {
+		return model.Companion{}, ErrInvalidQuantity
+	}
+
+	companion, err := c.storage.Feed(ctx, userID, resourceID, quantity, c.rates(), time.Now())
+	if err != nil {
+		log.Error("failed to feed companion", slog.String("error", err.Error()))
+		return model.Companion{}, mapError(err)
+	}
+
+	log.Info("companion fed", slog.Int("quantity", quantity), slog.Float64("wellBeing", companion.WellBeing))
+
+	return companion, nil
+}
+
+// Play plays with the companion of the user, at most once per cooldown.
+func (c *Companions) Play(ctx context.Context, userID int) (model.Companion, error) {
+	op := "companions.Play"
+
+	log := c.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	companion, err := c.storage.Play(ctx, userID, c.cfg.PlayHappiness, c.cfg.PlayHunger, c.cfg.PlayCooldown, c.rates(), time.Now())
+	if err != nil {
+		log.Error("failed to play with companion", slog.String("error", err.Error()))
+		return model.Companion{}, mapError(err)
+	}
+
+	log.Info("played with companion", slog.Float64("wellBeing", companion.WellBeing))
+
+	return companion, nil
+}
+
+func (c *Companions) rates() companions.Rates {
+	return companions.Rates{
+		HungerPerHour:    c.cfg.HungerPerHour,
+		HappinessPerHour: c.cfg.HappinessPerHour,
+	}
+}
+
+func mapError(err error) error {
+	switch {
+	case errors.Is(err, errs.ErrUserNotFound):
+		return ErrUserNotFound
+	case errors.Is(err, errs.ErrResourceNotFound):
+		return ErrResourceNotFound
+	case errors.Is(err, errs.ErrNotFood):
+		return ErrNotFood
+	case errors.Is(err, errs.ErrNotEnoughResources):
+		return ErrNotEnoughResources
+	case errors.Is(err, errs.ErrCompanionTooSoon):
+		return ErrTooSoon
+	}
+
+	return err
+}
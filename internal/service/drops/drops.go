@@ -0,0 +1,261 @@
+package drops
+
+import (
+	"context"
+	"errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/config"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/pagination"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"log/slog"
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNotEnoughPermission = errors.New("not enough permission")
+	ErrNameRequired        = errors.New("name is required")
+	ErrInvalidBudget       = errors.New("budget must be positive")
+	ErrInvalidReward       = errors.New("reward must be positive")
+	ErrInvalidClaims       = errors.New("claims per drop must be positive")
+	ErrInvalidRate         = errors.New("rate must be positive")
+	ErrInvalidLifetime     = errors.New("lifetime must be at least a second")
+	ErrInvalidPeriod       = errors.New("campaign must end after it starts")
+	ErrBudgetTooLow        = errors.New("budget does not cover a single drop")
+	ErrCampaignNotFound    = errors.New("campaign not found")
+	ErrCampaignStopped     = errors.New("campaign is already stopped")
+	ErrUserNotFound        = errors.New("user not found")
+	ErrDropNotFound        = errors.New("drop not found")
+	ErrDropExpired         = errors.New("drop is gone")
+	ErrDropExhausted       = errors.New("drop was already picked up by others")
+	ErrDropAlreadyClaimed  = errors.New("you have already picked this drop up")
+)
+
+type Drops struct {
+	log     *slog.Logger
+	storage Storage
+	cfg     config.DropConfig
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+type Storage interface {
+	AddCampaign(ctx context.Context, campaign model.DropCampaign) (model.DropCampaign, error)
+	StopCampaign(ctx context.Context, id int, now time.Time) (model.DropCampaign, error)
+	GetCampaigns(ctx context.Context, running bool, now time.Time, limit int) ([]model.DropCampaign, error)
+	Spawn(ctx context.Context, campaignID int, now, expiresAt time.Time) (model.Drop, error)
+	Claim(ctx context.Context, dropID, userID int, now time.Time) (model.Drop, error)
+	GetActive(ctx context.Context, userID int, now time.Time) ([]model.Drop, error)
+}
+
+func New(
+	log *slog.Logger,
+	storage Storage,
+	cfg config.DropConfig,
+) *Drops {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	return &Drops{
+		log:     log,
+		storage: storage,
+		cfg:     cfg,
+		rng:     rand.New(rand.NewSource(seed)),
+	}
+}
+
+// CreateCampaign starts a drop campaign, it begins right away unless StartsAt is set.
+func (d *Drops) CreateCampaign(ctx context.Context, adminID int, campaign model.DropCampaign) (model.DropCampaign, error) {
+	op := "drops.CreateCampaign"
+
+	log := d.log.With(slog.String("op", op), slog.Int("adminID", adminID))
+
+	campaign.CreatedBy = adminID
+	campaign.Name = strings.TrimSpace(campaign.Name)
+
+	if campaign.StartsAt.IsZero() {
+		campaign.StartsAt = time.Now()
+	}
+
+	if err := validate(campaign); err != nil {
+		log.Error("invalid campaign", slog.String("error", err.Error()))
+		return model.DropCampaign{}, err
+	}
+
+	created, err := d.storage.AddCampaign(ctx, campaign)
+	if err != nil {
+		log.Error("failed to add campaign", slog.String("error", err.Error()))
+		return model.DropCampaign{}, mapError(err)
+	}
+
+	log.Info("campaign created", slog.Int("id", created.ID))
+
+	return created, nil
+}
+
+// StopCampaign ends the campaign early, the drops lying around disappear with it.
+func (d *Drops) StopCampaign(ctx context.Context, adminID, id int) (model.DropCampaign, error) {
+	op := "drops.StopCampaign"
+
+	log := d.log.With(slog.String("op", op), slog.Int("adminID", adminID), slog.Int("id", id))
+
+	campaign, err := d.storage.StopCampaign(ctx, id, time.Now())
+	if err != nil {
+		log.Error("failed to stop campaign", slog.String("error", err.Error()))
+		return model.DropCampaign{}, mapError(err)
+	}
+
+	log.Info("campaign stopped")
+
+	return campaign, nil
+}
+
+// GetCampaigns returns the campaigns for admins, only the running ones if running is set.
+func (d *Drops) GetCampaigns(ctx context.Context, adminID int, running bool, limit int) ([]model.DropCampaign, error) {
+	op := "drops.GetCampaigns"
+
+	log := d.log.With(slog.String("op", op), slog.Int("adminID", adminID))
+
+	campaigns, err := d.storage.GetCampaigns(ctx, running, time.Now(), pagination.Limit(limit))
+	if err != nil {
+		log.Error("failed to get campaigns", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return campaigns, nil
+}
+
+// Spawn rolls the dice for every running campaign and drops coins accordingly. A campaign
+// that ran out of budget is skipped until its unclaimed drops expire.
+func (d *Drops) Spawn(ctx context.Context) error {
+	op := "drops.Spawn"
+
+	log := d.log.With(slog.String("op", op))
+
+	now := time.Now()
+
+	campaigns, err := d.storage.GetCampaigns(ctx, true, now, pagination.MaxLimit)
+	if err != nil {
+		log.Error("failed to get running campaigns", slog.String("error", err.Error()))
+		return err
+	}
+
+	var errList []error
+
+	for _, campaign := range campaigns {
+		for i := d.count(campaign.Rate); i > 0; i-- {
+			drop, err := d.storage.Spawn(ctx, campaign.ID, now, now.Add(campaign.Lifetime))
+			if err != nil {
+				if !errors.Is(err, errs.ErrDropBudgetExhausted) && !errors.Is(err, errs.ErrDropCampaignStopped) {
+					log.Error("failed to spawn drop", slog.Int("campaignID", campaign.ID), slog.String("error", err.Error()))
+					errList = append(errList, err)
+				}
+				break
+			}
+
+			log.Info("drop spawned", slog.Int("campaignID", campaign.ID), slog.Int("dropID", drop.ID))
+		}
+	}
+
+	return errors.Join(errList...)
+}
+
+// Claim picks the drop up for the user.
+func (d *Drops) Claim(ctx context.Context, dropID, userID int) (model.Drop, error) {
+	op := "drops.Claim"
+
+	log := d.log.With(slog.String("op", op), slog.Int("dropID", dropID), slog.Int("userID", userID))
+
+	drop, err := d.storage.Claim(ctx, dropID, userID, time.Now())
+	if err != nil {
+		log.Error("failed to claim drop", slog.String("error", err.Error()))
+		return model.Drop{}, mapError(err)
+	}
+
+	log.Info("drop claimed")
+
+	return drop, nil
+}
+
+// GetActive returns the drops the user can see right now.
+func (d *Drops) GetActive(ctx context.Context, userID int) ([]model.Drop, error) {
+	op := "drops.GetActive"
+
+	log := d.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	drops, err := d.storage.GetActive(ctx, userID, time.Now())
+	if err != nil {
+		log.Error("failed to get drops", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return drops, nil
+}
+
+// count returns how many drops a campaign with the rate spawns in one run. The expected number
+// of drops per run is rate scaled to SpawnInterval, its fraction is the chance of one more drop.
+func (d *Drops) count(rate float64) int {
+	expected := rate * d.cfg.SpawnInterval.Hours()
+	whole, fraction := math.Modf(expected)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.rng.Float64() < fraction {
+		whole++
+	}
+
+	return int(whole)
+}
+
+func validate(campaign model.DropCampaign) error {
+	switch {
+	case campaign.Name == "":
+		return ErrNameRequired
+	case campaign.Budget <= 0:
+		return ErrInvalidBudget
+	case campaign.Reward <= 0:
+		return ErrInvalidReward
+	case campaign.ClaimsPerDrop <= 0:
+		return ErrInvalidClaims
+	case campaign.Rate <= 0:
+		return ErrInvalidRate
+	case campaign.Lifetime < time.Second:
+		return ErrInvalidLifetime
+	case !campaign.EndsAt.After(campaign.StartsAt):
+		return ErrInvalidPeriod
+	case campaign.Budget < campaign.Reward*float64(campaign.ClaimsPerDrop):
+		return ErrBudgetTooLow
+	}
+
+	return nil
+}
+
+func mapError(err error) error {
+	switch {
+	case errors.Is(err, errs.ErrAdminNotFound):
+		return ErrNotEnoughPermission
+	case errors.Is(err, errs.ErrUserNotFound):
+		return ErrUserNotFound
+	case errors.Is(err, errs.ErrDropCampaignNotFound):
+		return ErrCampaignNotFound
+	case errors.Is(err, errs.ErrDropCampaignStopped):
+		return ErrCampaignStopped
+	case errors.Is(err, errs.ErrDropNotFound):
+		return ErrDropNotFound
+	case errors.Is(err, errs.ErrDropExpired):
+		return ErrDropExpired
+	case errors.Is(err, errs.ErrDropExhausted):
+		return ErrDropExhausted
+	case errors.Is(err, errs.ErrDropAlreadyClaimed):
+		return ErrDropAlreadyClaimed
+	}
+
+	return err
+}
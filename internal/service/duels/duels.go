@@ -0,0 +1,470 @@
+package duels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/k6mil6/hackathon-game-backend/internal/config"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/pagination"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	duelsstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/duels"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	notificationsstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/notifications"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrUnknownKind       = errors.New("unknown kind, use one of race, wager")
+	ErrSelfChallenge     = errors.New("you can not challenge yourself")
+	ErrInvalidStake      = errors.New("stake must be positive")
+	ErrStakeTooHigh      = errors.New("stake is too high")
+	ErrTaskRequired      = errors.New("a race needs a task")
+	ErrTermsRequired     = errors.New("a wager needs terms")
+	ErrWagerTask         = errors.New("only races are played on a task")
+	ErrTermsTooLong      = errors.New("terms are too long")
+	ErrUserNotFound      = errors.New("user not found")
+	ErrTaskNotFound      = errors.New("task not found")
+	ErrTaskUnavailable   = errors.New("task must be open to everyone and not taken yet")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrDuelNotFound      = errors.New("duel not found")
+	ErrDuelNotPending    = errors.New("duel is not waiting for a response")
+	ErrDuelNotActive     = errors.New("duel is not active")
+	ErrDuelExpired       = errors.New("duel response time is over")
+	ErrNotParticipant    = errors.New("winner must take part in the duel")
+	ErrInvalidOffset     = errors.New("offset can not be negative")
+)
+
+type Duels struct {
+	log                  *slog.Logger
+	storage              Storage
+	notificationsStorage NotificationsStorage
+	cfg                  config.DuelConfig
+}
+
+type Storage interface {
+	Create(ctx context.Context, duel model.Duel) (model.Duel, error)
+	Accept(ctx context.Context, duelID, userID int, now, deadline time.Time) (model.Duel, error)
+	Decline(ctx context.Context, duelID, userID int) (model.Duel, error)
+	Resolve(ctx context.Context, duelID, winnerID, adminID int) (model.Duel, error)
+	Refund(ctx context.Context, duelID int) (model.Duel, error)
+	GetByID(ctx context.Context, id int) (model.Duel, error)
+	GetOpenByTask(ctx context.Context, taskID int) ([]model.Duel, error)
+	GetExpired(ctx context.Context, now time.Time) ([]model.Duel, error)
+	GetByUser(ctx context.Context, userID, statusID, limit, offset int) ([]model.Duel, error)
+	GetAll(ctx context.Context, statusID, limit int) ([]model.Duel, error)
+	GetRecord(ctx context.Context, userID int) (model.DuelRecord, error)
+}
+
+type NotificationsStorage interface {
+	Add(ctx context.Context, notification model.Notification) error
+}
+
+func New(
+	log *slog.Logger,
+	storage Storage,
+	notificationsStorage NotificationsStorage,
+	cfg config.DuelConfig,
+) *Duels {
+	return &Duels{
+		log:                  log,
+		storage:              storage,
+		notificationsStorage: notificationsStorage,
+		cfg:                  cfg,
+	}
+}
+
+// Challenge escrows the stake of the challenger and invites the opponent, who has the configured
+// response time to answer. A race is played on a task open to everyone, a wager on terms an admin judges.
+func (d *Duels) Challenge(ctx context.Context, challengerID int, duel model.Duel) (model.Duel, error) {
+	op := "duels.Challenge"
+
+	log := d.log.With(slog.String("op", op), slog.Int("challengerID", challengerID), slog.Int("opponentID", duel.OpponentID))
+
+	duel.ChallengerID = challengerID
+	duel.Terms = strings.TrimSpace(duel.Terms)
+
+	if err := d.validate(duel); err != nil {
+		log.Error("invalid duel", slog.String("error", err.Error()))
+		return model.Duel{}, err
+	}
+
+	duel.RespondBy = time.Now().Add(d.cfg.ResponseTimeout)
+
+	created, err := d.storage.Create(ctx, duel)
+	if err != nil {
+		log.Error("failed to create duel", slog.String("error", err.Error()))
+		return model.Duel{}, mapError(err)
+	}
+
+	err = d.notificationsStorage.Add(ctx, model.Notification{
+		UserID:  created.OpponentID,
+		TypeID:  notificationsstorage.DuelChallengeTypeID,
+		Message: fmt.Sprintf("%s challenges you to a duel for %.2f coins", created.ChallengerName, created.Stake),
+		TaskID:  created.TaskID,
+	})
+	if err != nil {
+		log.Error("failed to notify about challenge", slog.String("error", err.Error()))
+	}
+
+	log.Info("duel created", slog.Int("id", created.ID))
+
+	return created, nil
+}
+
+// Accept escrows the stake of the opponent and starts the duel.
+func (d *Duels) Accept(ctx context.Context, duelID, userID int) (model.Duel, error) {
+	op := "duels.Accept"
+
+	log := d.log.With(slog.String("op", op), slog.Int("duelID", duelID), slog.Int("userID", userID))
+
+	now := time.Now()
+
+	duel, err := d.storage.Accept(ctx, duelID, userID, now, now.Add(d.cfg.Duration))
+	if err != nil {
+		log.Error("failed to accept duel", slog.String("error", err.Error()))
+		return model.Duel{}, mapError(err)
+	}
+
+	err = d.notificationsStorage.Add(ctx, model.Notification{
+		UserID:  duel.ChallengerID,
+		TypeID:  notificationsstorage.DuelChallengeTypeID,
+		Message: fmt.Sprintf("%s accepted your duel for %.2f coins", duel.OpponentName, duel.Stake),
+		TaskID:  duel.TaskID,
+	})
+	if err != nil {
+		log.Error("failed to notify about acceptance", slog.String("error", err.Error()))
+	}
+
+	log.Info("duel accepted")
+
+	return duel, nil
+}
+
+// Decline turns the challenge down, the challenger gets the stake back.
+func (d *Duels) Decline(ctx context.Context, duelID, userID int) (model.Duel, error) {
+	op := "duels.Decline"
+
+	log := d.log.With(slog.String("op", op), slog.Int("duelID", duelID), slog.Int("userID", userID))
+
+	duel, err := d.storage.Decline(ctx, duelID, userID)
+	if err != nil {
+		log.Error("failed to decline duel", slog.String("error", err.Error()))
+		return model.Duel{}, mapError(err)
+	}
+
+	err = d.notificationsStorage.Add(ctx, model.Notification{
+		UserID:  duel.ChallengerID,
+		TypeID:  notificationsstorage.DuelRefundedTypeID,
+		Message: fmt.Sprintf("%s declined your duel, your stake of %.2f coins is refunded", duel.OpponentName, duel.Stake),
+	})
+	if err != nil {
+		log.Error("failed to notify about decline", slog.String("error", err.Error()))
+	}
+
+	log.Info("duel declined")
+
+	return duel, nil
+}
+
+// Resolve lets an admin decide the active duel, the winner takes both stakes.
+func (d *Duels) Resolve(ctx context.Context, duelID, adminID, winnerID int) (model.Duel, error) {
+	op := "duels.Resolve"
+
+	log := d.log.With(slog.String("op", op), slog.Int("duelID", duelID), slog.Int("adminID", adminID))
+
+	duel, err := d.storage.Resolve(ctx, duelID, winnerID, adminID)
+	if err != nil {
+		log.Error("failed to resolve duel", slog.String("error", err.Error()))
+		return model.Duel{}, mapError(err)
+	}
+
+	d.notifyResolved(ctx, log, duel)
+
+	log.Info("duel resolved", slog.Int("winnerID", winnerID))
+
+	return duel, nil
+}
+
+// SettleTask closes the races on the accepted task: a participant who completed it wins, otherwise
+// the race can not be won anymore and the stakes are refunded.
+func (d *Duels) SettleTask(ctx context.Context, task model.Task) ([]model.Duel, error) {
+	op := "duels.SettleTask"
+
+	log := d.log.With(slog.String("op", op), slog.Int("taskID", task.ID))
+
+	open, err := d.storage.GetOpenByTask(ctx, task.ID)
+	if err != nil {
+		log.Error("failed to get duels", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var (
+		settled []model.Duel
+		errList []error
+	)
+
+	for _, duel := range open {
+		won := duel.StatusID == duelsstorage.ActiveStatusID &&
+			(task.UserID == duel.ChallengerID || task.UserID == duel.OpponentID)
+
+		var (
+			closed model.Duel
+			err    error
+		)
+		if won {
+			closed, err = d.storage.Resolve(ctx, duel.ID, task.UserID, 0)
+		} else {
+			closed, err = d.storage.Refund(ctx, duel.ID)
+		}
+		if err != nil {
+			if errors.Is(err, errs.ErrDuelNotActive) || errors.Is(err, errs.ErrDuelNotOpen) {
+				continue
+			}
+
+			log.Error("failed to settle duel", slog.Int("duelID", duel.ID), slog.String("error", err.Error()))
+			errList = append(errList, err)
+			continue
+		}
+
+		if won {
+			d.notifyResolved(ctx, log, closed)
+		} else {
+			d.notifyRefunded(ctx, log, closed, "its task was completed by someone else")
+		}
+
+		settled = append(settled, closed)
+	}
+
+	return settled, errors.Join(errList...)
+}
+
+// Expire refunds the challenges that were not answered in time and the duels that were not resolved
+// by their deadline. A duel that fails is retried on the next run.
+func (d *Duels) Expire(ctx context.Context) error {
+	op := "duels.Expire"
+
+	log := d.log.With(slog.String("op", op))
+
+	expired, err := d.storage.GetExpired(ctx, time.Now())
+	if err != nil {
+		log.Error("failed to get expired duels", slog.String("error", err.Error()))
+		return err
+	}
+
+	var errList []error
+
+	for _, duel := range expired {
+		refunded, err := d.storage.Refund(ctx, duel.ID)
+		if err != nil {
+			if errors.Is(err, errs.ErrDuelNotOpen) {
+				continue
+			}
+
+			log.Error("failed to refund duel", slog.Int("duelID", duel.ID), slog.String("error", err.Error()))
+			errList = append(errList, err)
+			continue
+		}
+
+		reason := "it was not resolved in time"
+		if duel.StatusID == duelsstorage.PendingStatusID {
+			reason = "it was not answered in time"
+		}
+
+		d.notifyRefunded(ctx, log, refunded, reason)
+
+		log.Info("duel expired", slog.Int("duelID", duel.ID))
+	}
+
+	return errors.Join(errList...)
+}
+
+// Get returns the duel to one of its participants.
+func (d *Duels) Get(ctx context.Context, duelID, userID int) (model.Duel, error) {
+	op := "duels.Get"
+
+	log := d.log.With(slog.String("op", op), slog.Int("duelID", duelID), slog.Int("userID", userID))
+
+	duel, err := d.storage.GetByID(ctx, duelID)
+	if err != nil {
+		log.Error("failed to get duel", slog.String("error", err.Error()))
+		return model.Duel{}, mapError(err)
+	}
+
+	if duel.ChallengerID != userID && duel.OpponentID != userID {
+		return model.Duel{}, ErrDuelNotFound
+	}
+
+	return duel, nil
+}
+
+// GetMine returns a page of the duels of the user. Zero statusID returns duels of any status.
+func (d *Duels) GetMine(ctx context.Context, userID, statusID, limit, offset int) ([]model.Duel, error) {
+	op := "duels.GetMine"
+
+	log := d.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	if offset < 0 {
+		return nil, ErrInvalidOffset
+	}
+
+	duels, err := d.storage.GetByUser(ctx, userID, statusID, pagination.Limit(limit), offset)
+	if err != nil {
+		log.Error("failed to get duels", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return duels, nil
+}
+
+// GetHistory returns a page of the resolved duels of the user with the totals of all of them.
+func (d *Duels) GetHistory(ctx context.Context, userID, limit, offset int) ([]model.Duel, model.DuelRecord, error) {
+	op := "duels.GetHistory"
+
+	log := d.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	if offset < 0 {
+		return nil, model.DuelRecord{}, ErrInvalidOffset
+	}
+
+	duels, err := d.storage.GetByUser(ctx, userID, duelsstorage.ResolvedStatusID, pagination.Limit(limit), offset)
+	if err != nil {
+		log.Error("failed to get duels", slog.String("error", err.Error()))
+		return nil, model.DuelRecord{}, err
+	}
+
+	record, err := d.storage.GetRecord(ctx, userID)
+	if err != nil {
+		log.Error("failed to get record", slog.String("error", err.Error()))
+		return nil, model.DuelRecord{}, err
+	}
+
+	return duels, record, nil
+}
+
+// GetAll returns the duels of the status for admins. Zero statusID returns duels of any status.
+func (d *Duels) GetAll(ctx context.Context, adminID, statusID, limit int) ([]model.Duel, error) {
+	op := "duels.GetAll"
+
+	log := d.log.With(slog.String("op", op), slog.Int("adminID", adminID))
+
+	duels, err := d.storage.GetAll(ctx, statusID, pagination.Limit(limit))
+	if err != nil {
+		log.Error("failed to get duels", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return duels, nil
+}
+
+func (d *Duels) validate(duel model.Duel) error {
+	if duel.ChallengerID == duel.OpponentID {
+		return ErrSelfChallenge
+	}
+
+	if duel.Stake <= 0 {
+		return ErrInvalidStake
+	}
+
+	if d.cfg.MaxStake > 0 && duel.Stake > d.cfg.MaxStake {
+		return ErrStakeTooHigh
+	}
+
+	if utf8.RuneCountInString(duel.Terms) > d.cfg.MaxTermsLength {
+		return ErrTermsTooLong
+	}
+
+	switch duel.KindID {
+	case duelsstorage.RaceKindID:
+		if duel.TaskID == 0 {
+			return ErrTaskRequired
+		}
+	case duelsstorage.WagerKindID:
+		if duel.TaskID != 0 {
+			return ErrWagerTask
+		}
+
+		if duel.Terms == "" {
+			return ErrTermsRequired
+		}
+	default:
+		return ErrUnknownKind
+	}
+
+	return nil
+}
+
+// notifyResolved tells both participants how the duel ended, failures are only logged.
+func (d *Duels) notifyResolved(ctx context.Context, log *slog.Logger, duel model.Duel) {
+	winner, loser := duel.ChallengerName, duel.OpponentName
+	loserID := duel.OpponentID
+	if duel.WinnerID == duel.OpponentID {
+		winner, loser = loser, winner
+		loserID = duel.ChallengerID
+	}
+
+	notifications := []model.Notification{
+		{
+			UserID:  duel.WinnerID,
+			TypeID:  notificationsstorage.DuelResolvedTypeID,
+			Message: fmt.Sprintf("You won the duel against %s and got %.2f coins", loser, duel.Stake*2),
+		},
+		{
+			UserID:  loserID,
+			TypeID:  notificationsstorage.DuelResolvedTypeID,
+			Message: fmt.Sprintf("You lost the duel against %s", winner),
+		},
+	}
+
+	for _, notification := range notifications {
+		if err := d.notificationsStorage.Add(ctx, notification); err != nil {
+			log.Error("failed to notify about duel result", slog.Int("userID", notification.UserID), slog.String("error", err.Error()))
+		}
+	}
+}
+
+// notifyRefunded tells the participants who got their stake back why, failures are only logged.
+func (d *Duels) notifyRefunded(ctx context.Context, log *slog.Logger, duel model.Duel, reason string) {
+	userIDs := []int{duel.ChallengerID}
+	if !duel.AcceptedAt.IsZero() {
+		userIDs = append(userIDs, duel.OpponentID)
+	}
+
+	for _, userID := range userIDs {
+		err := d.notificationsStorage.Add(ctx, model.Notification{
+			UserID:  userID,
+			TypeID:  notificationsstorage.DuelRefundedTypeID,
+			Message: fmt.Sprintf("The duel between %s and %s is called off because %s, your stake of %.2f coins is refunded", duel.ChallengerName, duel.OpponentName, reason, duel.Stake),
+		})
+		if err != nil {
+			log.Error("failed to notify about refund", slog.Int("userID", userID), slog.String("error", err.Error()))
+		}
+	}
+}
+
+func mapError(err error) error {
+	switch {
+	case errors.Is(err, errs.ErrUserNotFound):
+		return ErrUserNotFound
+	case errors.Is(err, errs.ErrTaskNotFound):
+		return ErrTaskNotFound
+	case errors.Is(err, errs.ErrDuelTaskUnavailable):
+		return ErrTaskUnavailable
+	case errors.Is(err, errs.ErrInsufficientFunds):
+		return ErrInsufficientFunds
+	case errors.Is(err, errs.ErrDuelNotFound):
+		return ErrDuelNotFound
+	case errors.Is(err, errs.ErrDuelNotPending):
+		return ErrDuelNotPending
+	case errors.Is(err, errs.ErrDuelNotActive):
+		return ErrDuelNotActive
+	case errors.Is(err, errs.ErrDuelExpired):
+		return ErrDuelExpired
+	case errors.Is(err, errs.ErrNotDuelParticipant):
+		return ErrNotParticipant
+	}
+
+	return err
+}
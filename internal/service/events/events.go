@@ -0,0 +1,404 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/k6mil6/hackathon-game-backend/internal/config"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/jwt"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/pagination"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	notificationsstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/notifications"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotEnoughPermission = errors.New("not enough permission")
+	ErrTitleRequired       = errors.New("title is required")
+	ErrInvalidPeriod       = errors.New("event must end after it starts")
+	ErrEventInPast         = errors.New("event must end in the future")
+	ErrInvalidCapacity     = errors.New("capacity can not be negative")
+	ErrInvalidReward       = errors.New("reward can not be negative")
+	ErrInvalidTTL          = errors.New("invalid code lifetime")
+	ErrInvalidOffset       = errors.New("offset can not be negative")
+	ErrCodeRequired        = errors.New("code is required")
+	ErrInvalidCode         = errors.New("invalid code")
+	ErrCodeExpired         = errors.New("code is expired")
+	ErrCodeAlreadyUsed     = errors.New("code was already used")
+	ErrUserNotFound        = errors.New("user not found")
+	ErrEventNotFound       = errors.New("event not found")
+	ErrEventCancelled      = errors.New("event is cancelled")
+	ErrEventOver           = errors.New("event is over")
+	ErrEventFull           = errors.New("event is full")
+	ErrAlreadyRSVPed       = errors.New("you have already RSVPed to this event")
+	ErrNotRSVPed           = errors.New("you have not RSVPed to this event")
+	ErrAlreadyCheckedIn    = errors.New("you have already checked in to this event")
+	ErrNoBudget            = errors.New("no budget allocated for this period")
+	ErrBudgetExceeded      = errors.New("event budget is exhausted")
+)
+
+type Events struct {
+	log                  *slog.Logger
+	storage              Storage
+	notificationsStorage NotificationsStorage
+	budgetsStorage       BudgetsStorage
+	cfg                  config.EventConfig
+	enforceBudget        bool
+	secret               string
+}
+
+type Storage interface {
+	Create(ctx context.Context, event model.Event) (model.Event, error)
+	Cancel(ctx context.Context, id int, now time.Time) (model.Event, error)
+	RSVP(ctx context.Context, eventID, userID int, now time.Time) (model.Event, error)
+	CancelRSVP(ctx context.Context, eventID, userID int) error
+	AddCode(ctx context.Context, code model.EventCode, now time.Time) (model.EventCode, error)
+	CheckIn(ctx context.Context, eventID, codeID, userID int, now time.Time) (model.Event, error)
+	GetByID(ctx context.Context, id, userID int) (model.Event, error)
+	GetUpcoming(ctx context.Context, userID int, now time.Time, limit, offset int) ([]model.Event, error)
+	GetAll(ctx context.Context, limit, offset int) ([]model.Event, error)
+	GetRSVPs(ctx context.Context, eventID int) ([]model.EventRSVP, error)
+}
+
+type NotificationsStorage interface {
+	Add(ctx context.Context, notification model.Notification) error
+}
+
+type BudgetsStorage interface {
+	Reserve(ctx context.Context, adminID, groupID int, amount float64, at time.Time) (int, error)
+}
+
+func New(
+	log *slog.Logger,
+	storage Storage,
+	notificationsStorage NotificationsStorage,
+	budgetsStorage BudgetsStorage,
+	cfg config.EventConfig,
+	budgetCfg config.BudgetConfig,
+	secret string,
+) *Events {
+	return &Events{
+		log:                  log,
+		storage:              storage,
+		notificationsStorage: notificationsStorage,
+		budgetsStorage:       budgetsStorage,
+		cfg:                  cfg,
+		enforceBudget:        budgetCfg.Enforce,
+		secret:               secret,
+	}
+}
+
+// Create adds an event. Check-in rewards are charged to the budget of the admin at the time
+// of creation, how many RSVPs show up is unknown, so nothing is reserved up front.
+func (e *Events) Create(ctx context.Context, adminID int, event model.Event) (model.Event, error) {
+	op := "events.Create"
+
+	log := e.log.With(slog.String("op", op), slog.Int("adminID", adminID))
+
+	event.CreatedBy = adminID
+	event.Title = strings.TrimSpace(event.Title)
+	event.Description = strings.TrimSpace(event.Description)
+	event.Location = strings.TrimSpace(event.Location)
+
+	if err := validate(event, time.Now()); err != nil {
+		log.Error("invalid event", slog.String("error", err.Error()))
+		return model.Event{}, err
+	}
+
+	if event.Reward > 0 {
+		budgetID, err := e.budgetsStorage.Reserve(ctx, adminID, 0, 0, time.Now())
+		if err != nil {
+			log.Error("failed to find budget", slog.String("error", err.Error()))
+			return model.Event{}, err
+		}
+
+		if budgetID == 0 && e.enforceBudget {
+			log.Error("no budget allocated")
+			return model.Event{}, ErrNoBudget
+		}
+
+		event.BudgetID = budgetID
+	}
+
+	created, err := e.storage.Create(ctx, event)
+	if err != nil {
+		log.Error("failed to create event", slog.String("error", err.Error()))
+		return model.Event{}, mapError(err)
+	}
+
+	log.Info("event created", slog.Int("id", created.ID))
+
+	return created, nil
+}
+
+// Cancel calls the event off and tells everyone who RSVPed.
+func (e *Events) Cancel(ctx context.Context, adminID, id int) (model.Event, error) {
+	op := "events.Cancel"
+
+	log := e.log.With(slog.String("op", op), slog.Int("adminID", adminID), slog.Int("id", id))
+
+	event, err := e.storage.Cancel(ctx, id, time.Now())
+	if err != nil {
+		log.Error("failed to cancel event", slog.String("error", err.Error()))
+		return model.Event{}, mapError(err)
+	}
+
+	rsvps, err := e.storage.GetRSVPs(ctx, id)
+	if err != nil {
+		log.Error("failed to get RSVPs", slog.String("error", err.Error()))
+	}
+
+	for _, rsvp := range rsvps {
+		e.notify(ctx, log, rsvp.UserID, fmt.Sprintf("The event %q you RSVPed to was cancelled", event.Title))
+	}
+
+	log.Info("event cancelled")
+
+	return event, nil
+}
+
+// CreateCode issues a check-in code for admins to show at the venue. The code lives for ttl,
+// zero ttl means the configured CodeTTL. A oneTime code is good for a single check-in.
+func (e *Events) CreateCode(ctx context.Context, adminID, eventID int, oneTime bool, ttl time.Duration) (model.EventCode, error) {
+	op := "events.CreateCode"
+
+	log := e.log.With(slog.String("op", op), slog.Int("adminID", adminID), slog.Int("eventID", eventID))
+
+	if ttl == 0 {
+		ttl = e.cfg.CodeTTL
+	}
+
+	if ttl < time.Second || ttl > e.cfg.MaxCodeTTL {
+		return model.EventCode{}, ErrInvalidTTL
+	}
+
+	now := time.Now()
+
+	code, err := e.storage.AddCode(ctx, model.EventCode{
+		EventID:   eventID,
+		OneTime:   oneTime,
+		ExpiresAt: now.Add(ttl),
+		CreatedBy: adminID,
+	}, now)
+	if err != nil {
+		log.Error("failed to add code", slog.String("error", err.Error()))
+		return model.EventCode{}, mapError(err)
+	}
+
+	code.Token, err = jwt.NewEventCodeToken(code.EventID, code.ID, code.ExpiresAt, e.secret)
+	if err != nil {
+		log.Error("failed to sign code", slog.String("error", err.Error()))
+		return model.EventCode{}, err
+	}
+
+	log.Info("code created", slog.Int("codeID", code.ID), slog.Bool("oneTime", oneTime))
+
+	return code, nil
+}
+
+// RSVP reserves a place at the event for the user.
+func (e *Events) RSVP(ctx context.Context, eventID, userID int) (model.Event, error) {
+	op := "events.RSVP"
+
+	log := e.log.With(slog.String("op", op), slog.Int("eventID", eventID), slog.Int("userID", userID))
+
+	event, err := e.storage.RSVP(ctx, eventID, userID, time.Now())
+	if err != nil {
+		log.Error("failed to RSVP", slog.String("error", err.Error()))
+		return model.Event{}, mapError(err)
+	}
+
+	log.Info("RSVPed")
+
+	return event, nil
+}
+
+// CancelRSVP gives the place of the user back, which is no longer possible after checking in.
+func (e *Events) CancelRSVP(ctx context.Context, eventID, userID int) error {
+	op := "events.CancelRSVP"
+
+	log := e.log.With(slog.String("op", op), slog.Int("eventID", eventID), slog.Int("userID", userID))
+
+	if err := e.storage.CancelRSVP(ctx, eventID, userID); err != nil {
+		log.Error("failed to cancel RSVP", slog.String("error", err.Error()))
+		return mapError(err)
+	}
+
+	log.Info("RSVP cancelled")
+
+	return nil
+}
+
+// CheckIn checks the user in with the code shown at the venue and pays the attendance reward once.
+func (e *Events) CheckIn(ctx context.Context, userID int, token string) (model.Event, error) {
+	op := "events.CheckIn"
+
+	log := e.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return model.Event{}, ErrCodeRequired
+	}
+
+	eventID, codeID, err := jwt.GetEventCode(token, e.secret)
+	if err != nil {
+		log.Error("failed to parse code", slog.String("error", err.Error()))
+
+		if errors.Is(err, jwt.ErrExpired) {
+			return model.Event{}, ErrCodeExpired
+		}
+		return model.Event{}, ErrInvalidCode
+	}
+
+	event, err := e.storage.CheckIn(ctx, eventID, codeID, userID, time.Now())
+	if err != nil {
+		log.Error("failed to check in", slog.Int("eventID", eventID), slog.String("error", err.Error()))
+		return model.Event{}, mapError(err)
+	}
+
+	if event.Reward > 0 {
+		e.notify(ctx, log, userID, fmt.Sprintf("Thanks for coming to %q, you got %.2f coins", event.Title, event.Reward))
+	}
+
+	log.Info("checked in", slog.Int("eventID", eventID))
+
+	return event, nil
+}
+
+// Get returns the event with the RSVP of the user.
+func (e *Events) Get(ctx context.Context, id, userID int) (model.Event, error) {
+	op := "events.Get"
+
+	log := e.log.With(slog.String("op", op), slog.Int("id", id), slog.Int("userID", userID))
+
+	event, err := e.storage.GetByID(ctx, id, userID)
+	if err != nil {
+		log.Error("failed to get event", slog.String("error", err.Error()))
+		return model.Event{}, mapError(err)
+	}
+
+	return event, nil
+}
+
+// GetUpcoming returns a page of the events users can still go to.
+func (e *Events) GetUpcoming(ctx context.Context, userID, limit, offset int) ([]model.Event, error) {
+	op := "events.GetUpcoming"
+
+	log := e.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	if offset < 0 {
+		return nil, ErrInvalidOffset
+	}
+
+	events, err := e.storage.GetUpcoming(ctx, userID, time.Now(), pagination.Limit(limit), offset)
+	if err != nil {
+		log.Error("failed to get events", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// GetAll returns a page of all the events for admins, cancelled and past ones included.
+func (e *Events) GetAll(ctx context.Context, adminID, limit, offset int) ([]model.Event, error) {
+	op := "events.GetAll"
+
+	log := e.log.With(slog.String("op", op), slog.Int("adminID", adminID))
+
+	if offset < 0 {
+		return nil, ErrInvalidOffset
+	}
+
+	events, err := e.storage.GetAll(ctx, pagination.Limit(limit), offset)
+	if err != nil {
+		log.Error("failed to get events", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// GetRSVPs returns who RSVPed to the event and who of them checked in.
+func (e *Events) GetRSVPs(ctx context.Context, adminID, eventID int) ([]model.EventRSVP, error) {
+	op := "events.GetRSVPs"
+
+	log := e.log.With(slog.String("op", op), slog.Int("adminID", adminID), slog.Int("eventID", eventID))
+
+	if _, err := e.storage.GetByID(ctx, eventID, 0); err != nil {
+		log.Error("failed to get event", slog.String("error", err.Error()))
+		return nil, mapError(err)
+	}
+
+	rsvps, err := e.storage.GetRSVPs(ctx, eventID)
+	if err != nil {
+		log.Error("failed to get RSVPs", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return rsvps, nil
+}
+
+func validate(event model.Event, now time.Time) error {
+	switch {
+	case event.Title == "":
+		return ErrTitleRequired
+	case !event.EndsAt.After(event.StartsAt):
+		return ErrInvalidPeriod
+	case !event.EndsAt.After(now):
+		return ErrEventInPast
+	case event.Capacity < 0:
+		return ErrInvalidCapacity
+	case event.Reward < 0:
+		return ErrInvalidReward
+	}
+
+	return nil
+}
+
+// notify tells the user about the event, failures are only logged.
+func (e *Events) notify(ctx context.Context, log *slog.Logger, userID int, message string) {
+	err := e.notificationsStorage.Add(ctx, model.Notification{
+		UserID:  userID,
+		TypeID:  notificationsstorage.EventUpdateTypeID,
+		Message: message,
+	})
+	if err != nil {
+		log.Error("failed to notify about event", slog.Int("userID", userID), slog.String("error", err.Error()))
+	}
+}
+
+func mapError(err error) error {
+	switch {
+	case errors.Is(err, errs.ErrAdminNotFound):
+		return ErrNotEnoughPermission
+	case errors.Is(err, errs.ErrUserNotFound):
+		return ErrUserNotFound
+	case errors.Is(err, errs.ErrEventNotFound):
+		return ErrEventNotFound
+	case errors.Is(err, errs.ErrEventCancelled):
+		return ErrEventCancelled
+	case errors.Is(err, errs.ErrEventOver):
+		return ErrEventOver
+	case errors.Is(err, errs.ErrEventFull):
+		return ErrEventFull
+	case errors.Is(err, errs.ErrAlreadyRSVPed):
+		return ErrAlreadyRSVPed
+	case errors.Is(err, errs.ErrNotRSVPed):
+		return ErrNotRSVPed
+	case errors.Is(err, errs.ErrEventAlreadyAttended):
+		return ErrAlreadyCheckedIn
+	case errors.Is(err, errs.ErrEventCodeNotFound):
+		return ErrInvalidCode
+	case errors.Is(err, errs.ErrEventCodeExpired):
+		return ErrCodeExpired
+	case errors.Is(err, errs.ErrEventCodeAlreadyUsed):
+		return ErrCodeAlreadyUsed
+	case errors.Is(err, errs.ErrBudgetExceeded):
+		return ErrBudgetExceeded
+	}
+
+	return err
+}
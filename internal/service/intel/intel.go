@@ -0,0 +1,200 @@
+package intel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/k6mil6/hackathon-game-backend/internal/config"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/pagination"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	notificationsstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/notifications"
+	"log/slog"
+	"time"
+)
+
+var (
+	ErrClassNotAllowed   = errors.New("intel is not available for your class")
+	ErrLevelTooLow       = errors.New("level is too low to buy intel")
+	ErrTargetNotFound    = errors.New("target user not found")
+	ErrSelfIntel         = errors.New("can not buy intel on yourself")
+	ErrIntelActive       = errors.New("intel on this user is still active")
+	ErrIntelNotFound     = errors.New("intel not found")
+	ErrIntelExpired      = errors.New("intel has expired")
+	ErrInsufficientFunds = errors.New("not enough coins to pay the fee")
+)
+
+type Intel struct {
+	log                  *slog.Logger
+	storage              Storage
+	usersStorage         UsersStorage
+	tasksStorage         TasksStorage
+	notificationsStorage NotificationsStorage
+	cfg                  config.IntelConfig
+}
+
+type Storage interface {
+	Buy(ctx context.Context, intel model.Intel) (model.Intel, error)
+	GetByID(ctx context.Context, intelID int) (model.Intel, error)
+	GetAll(ctx context.Context, buyerID int, activeOnly bool, limit int) ([]model.Intel, error)
+}
+
+type UsersStorage interface {
+	GetByID(ctx context.Context, id int) (model.User, error)
+}
+
+type TasksStorage interface {
+	GetInProgressByUser(ctx context.Context, userID int, limit int) ([]model.Task, error)
+}
+
+type NotificationsStorage interface {
+	Add(ctx context.Context, notification model.Notification) error
+}
+
+func New(
+	log *slog.Logger,
+	storage Storage,
+	usersStorage UsersStorage,
+	tasksStorage TasksStorage,
+	notificationsStorage NotificationsStorage,
+	cfg config.IntelConfig,
+) *Intel {
+	return &Intel{
+		log:                  log,
+		storage:              storage,
+		usersStorage:         usersStorage,
+		tasksStorage:         tasksStorage,
+		notificationsStorage: notificationsStorage,
+		cfg:                  cfg,
+	}
+}
+
+// Buy charges the fee and opens a window in which the buyer sees the target's tasks in progress.
+func (i *Intel) Buy(ctx context.Context, buyerID, targetID int) (model.Intel, error) {
+	op := "intel.Buy"
+
+	log := i.log.With(slog.String("op", op), slog.Int("buyerID", buyerID), slog.Int("targetID", targetID))
+
+	if buyerID == targetID {
+		log.Error("buyer is the target")
+		return model.Intel{}, ErrSelfIntel
+	}
+
+	buyer, err := i.usersStorage.GetByID(ctx, buyerID)
+	if err != nil {
+		log.Error("failed to get buyer", slog.String("error", err.Error()))
+		return model.Intel{}, err
+	}
+
+	if !i.classAllowed(buyer.ClassID) {
+		log.Error("class is not allowed to buy intel", slog.Int("classID", buyer.ClassID))
+		return model.Intel{}, ErrClassNotAllowed
+	}
+
+	if buyer.Level < i.cfg.MinLevel {
+		log.Error("level is too low to buy intel", slog.Int("level", buyer.Level))
+		return model.Intel{}, ErrLevelTooLow
+	}
+
+	if _, err := i.usersStorage.GetByID(ctx, targetID); err != nil {
+		log.Error("failed to get target", slog.String("error", err.Error()))
+		if errors.Is(err, errs.ErrUserNotFound) {
+			return model.Intel{}, ErrTargetNotFound
+		}
+		return model.Intel{}, err
+	}
+
+	intel, err := i.storage.Buy(ctx, model.Intel{
+		BuyerID:   buyerID,
+		TargetID:  targetID,
+		Fee:       i.cfg.Fee,
+		ExpiresAt: time.Now().Add(i.cfg.Window),
+	})
+	if err != nil {
+		log.Error("failed to buy intel", slog.String("error", err.Error()))
+		switch {
+		case errors.Is(err, errs.ErrIntelActive):
+			return model.Intel{}, ErrIntelActive
+		case errors.Is(err, errs.ErrInsufficientFunds):
+			return model.Intel{}, ErrInsufficientFunds
+		default:
+			return model.Intel{}, err
+		}
+	}
+
+	log.Info("bought intel", slog.Int("id", intel.ID))
+
+	// the alert does not name the buyer, the target only learns that somebody is watching
+	if i.cfg.AlertTarget {
+		err = i.notificationsStorage.Add(ctx, model.Notification{
+			UserID:  targetID,
+			TypeID:  notificationsstorage.IntelPurchasedTypeID,
+			Message: fmt.Sprintf("Somebody is gathering intel on your tasks until %s", intel.ExpiresAt.Format(time.RFC3339)),
+		})
+		if err != nil {
+			log.Error("failed to alert target", slog.String("error", err.Error()))
+		}
+	}
+
+	return intel, nil
+}
+
+// GetTasks returns the tasks the target of the intel is working on, as long as the intel is active.
+func (i *Intel) GetTasks(ctx context.Context, intelID, buyerID int) (model.Intel, []model.Task, error) {
+	op := "intel.GetTasks"
+
+	log := i.log.With(slog.String("op", op), slog.Int("intelID", intelID), slog.Int("buyerID", buyerID))
+
+	intel, err := i.storage.GetByID(ctx, intelID)
+	if err != nil {
+		log.Error("failed to get intel", slog.String("error", err.Error()))
+		if errors.Is(err, errs.ErrIntelNotFound) {
+			return model.Intel{}, nil, ErrIntelNotFound
+		}
+		return model.Intel{}, nil, err
+	}
+
+	// somebody else's purchase is reported as missing
+	if intel.BuyerID != buyerID {
+		log.Error("intel belongs to another user")
+		return model.Intel{}, nil, ErrIntelNotFound
+	}
+
+	if !time.Now().Before(intel.ExpiresAt) {
+		log.Error("intel has expired", slog.Time("expiresAt", intel.ExpiresAt))
+		return model.Intel{}, nil, ErrIntelExpired
+	}
+
+	tasks, err := i.tasksStorage.GetInProgressByUser(ctx, intel.TargetID, pagination.MaxLimit)
+	if err != nil {
+		log.Error("failed to get tasks", slog.String("error", err.Error()))
+		return model.Intel{}, nil, err
+	}
+
+	return intel, tasks, nil
+}
+
+// GetAll returns the intel the user bought, newest first.
+func (i *Intel) GetAll(ctx context.Context, buyerID int, activeOnly bool, limit int) ([]model.Intel, error) {
+	op := "intel.GetAll"
+
+	log := i.log.With(slog.String("op", op), slog.Int("buyerID", buyerID))
+
+	intels, err := i.storage.GetAll(ctx, buyerID, activeOnly, pagination.Limit(limit))
+	if err != nil {
+		log.Error("failed to get intel", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return intels, nil
+}
+
+func (i *Intel) classAllowed(classID int) bool {
+	for _, allowed := range i.cfg.ClassIDs {
+		if allowed == classID {
+			return true
+		}
+	}
+
+	return false
+}
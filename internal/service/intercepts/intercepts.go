@@ -0,0 +1,268 @@
+package intercepts
+
+import (
+	"context"
+	crand "crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/k6mil6/hackathon-game-backend/internal/config"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/pagination"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"github.com/k6mil6/hackathon-game-backend/internal/service/perks"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	notificationsstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/notifications"
+	taskstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/tasks"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/users"
+	"log/slog"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNotRacoon            = errors.New("only racoons can intercept tasks")
+	ErrLevelTooLow          = errors.New("level is too low or companion is too weak to intercept tasks")
+	ErrTaskNotFound         = errors.New("task not found")
+	ErrTaskNotInterceptable = errors.New("task is not in progress by another user")
+	ErrCooldown             = errors.New("intercept is on cooldown")
+	ErrVictimProtected      = errors.New("user was intercepted recently")
+	ErrInsufficientFunds    = errors.New("not enough coins to cover the penalty")
+)
+
+type Intercepts struct {
+	log                  *slog.Logger
+	storage              Storage
+	usersStorage         UsersStorage
+	tasksStorage         TasksStorage
+	notificationsStorage NotificationsStorage
+	perks                Perks
+	cfg                  config.InterceptConfig
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+type Storage interface {
+	Attempt(ctx context.Context, intercept model.Intercept, cooldown, victimCooldown time.Duration) (model.Intercept, error)
+	GetAll(ctx context.Context, userID, taskID, limit int) ([]model.Intercept, error)
+	LastAttemptAt(ctx context.Context, thiefID int) (time.Time, error)
+}
+
+type UsersStorage interface {
+	GetByID(ctx context.Context, id int) (model.User, error)
+}
+
+type TasksStorage interface {
+	GetByID(ctx context.Context, taskID int) (model.Task, error)
+	SetUserDeadline(ctx context.Context, taskID, userID int, deadline time.Time) error
+}
+
+type NotificationsStorage interface {
+	Add(ctx context.Context, notification model.Notification) error
+}
+
+type Perks interface {
+	For(ctx context.Context, userID int) (perks.Holder, error)
+}
+
+func New(
+	log *slog.Logger,
+	storage Storage,
+	usersStorage UsersStorage,
+	tasksStorage TasksStorage,
+	notificationsStorage NotificationsStorage,
+	perks Perks,
+	cfg config.InterceptConfig,
+) *Intercepts {
+	i := &Intercepts{
+		log:                  log,
+		storage:              storage,
+		usersStorage:         usersStorage,
+		tasksStorage:         tasksStorage,
+		notificationsStorage: notificationsStorage,
+		perks:                perks,
+		cfg:                  cfg,
+	}
+
+	// a configured seed makes the sequence of attempt seeds reproducible, e.g. on a test stand
+	if cfg.Seed != 0 {
+		i.rng = rand.New(rand.NewSource(cfg.Seed))
+	}
+
+	return i
+}
+
+// Roll returns the number in [0, 1) the outcome of an attempt is decided by.
+// The attempt succeeds when the roll is below its chance, so storing the seed is enough to audit it.
+func Roll(seed int64) float64 {
+	return rand.New(rand.NewSource(seed)).Float64()
+}
+
+// Intercept lets a racoon try to take over a task another user is working on.
+// If caught, the racoon pays the penalty to the victim. The victim is notified either way.
+func (i *Intercepts) Intercept(ctx context.Context, taskID, thiefID int) (model.Intercept, error) {
+	op := "intercepts.Intercept"
+
+	log := i.log.With(slog.String("op", op), slog.Int("taskID", taskID), slog.Int("thiefID", thiefID))
+
+	thief, err := i.usersStorage.GetByID(ctx, thiefID)
+	if err != nil {
+		log.Error("failed to get user", slog.String("error", err.Error()))
+		return model.Intercept{}, err
+	}
+
+	if thief.ClassID != users.RacoonClassID {
+		log.Error("user is not a racoon", slog.Int("classID", thief.ClassID))
+		return model.Intercept{}, ErrNotRacoon
+	}
+
+	holder, err := i.perks.For(ctx, thiefID)
+	if err != nil {
+		log.Error("failed to get perks", slog.String("error", err.Error()))
+		return model.Intercept{}, err
+	}
+
+	if !holder.HasPerk(perks.RacoonInterceptName) {
+		log.Error("intercept is not active", slog.Int("level", thief.Level))
+		return model.Intercept{}, ErrLevelTooLow
+	}
+
+	task, err := i.tasksStorage.GetByID(ctx, taskID)
+	if err != nil {
+		log.Error("failed to get task", slog.String("error", err.Error()))
+		if errors.Is(err, errs.ErrTaskNotFound) {
+			return model.Intercept{}, ErrTaskNotFound
+		}
+		return model.Intercept{}, err
+	}
+
+	if !holder.CanSee(task) {
+		log.Error("task is hidden from the user")
+		return model.Intercept{}, ErrTaskNotFound
+	}
+
+	if task.StatusID != taskstorage.InProgressStatusID || task.UserID == 0 || task.UserID == thiefID {
+		log.Error("task can not be intercepted", slog.Int("statusID", task.StatusID), slog.Int("userID", task.UserID))
+		return model.Intercept{}, ErrTaskNotInterceptable
+	}
+
+	seed, err := i.nextSeed()
+	if err != nil {
+		log.Error("failed to generate seed", slog.String("error", err.Error()))
+		return model.Intercept{}, err
+	}
+
+	roll := Roll(seed)
+
+	intercept, err := i.storage.Attempt(ctx, model.Intercept{
+		TaskID:   task.ID,
+		ThiefID:  thiefID,
+		VictimID: task.UserID,
+		Seed:     seed,
+		Roll:     roll,
+		Chance:   i.cfg.SuccessChance,
+		Success:  roll < i.cfg.SuccessChance,
+		Penalty:  i.cfg.Penalty,
+	}, i.cfg.Cooldown, i.cfg.VictimCooldown)
+	if err != nil {
+		log.Error("failed to attempt intercept", slog.String("error", err.Error()))
+		return model.Intercept{}, mapError(err)
+	}
+
+	log.Info("intercept attempted", slog.Int("id", intercept.ID), slog.Bool("success", intercept.Success))
+
+	// the task is the racoon's now, its deadline is fixed with the perks of the racoon
+	if intercept.Success && !task.Deadline.IsZero() {
+		taken := task
+		taken.UserID = thiefID
+		taken.UserDeadline = time.Time{}
+
+		if err := i.tasksStorage.SetUserDeadline(ctx, task.ID, thiefID, holder.Deadline(taken)); err != nil {
+			log.Error("failed to fix deadline", slog.String("error", err.Error()))
+		}
+	}
+
+	notification := model.Notification{
+		UserID:  intercept.VictimID,
+		TypeID:  notificationsstorage.TaskInterceptedTypeID,
+		Message: fmt.Sprintf("Task \"%s\" was intercepted by %s", task.Name, thief.Username),
+		TaskID:  task.ID,
+	}
+
+	if !intercept.Success {
+		notification.TypeID = notificationsstorage.TaskInterceptFailedTypeID
+		notification.Message = fmt.Sprintf("%s was caught trying to intercept task \"%s\" and paid you %.2f coins", thief.Username, task.Name, intercept.Penalty)
+	}
+
+	if err := i.notificationsStorage.Add(ctx, notification); err != nil {
+		log.Error("failed to notify victim", slog.String("error", err.Error()))
+	}
+
+	return intercept, nil
+}
+
+// GetAll returns the intercept audit log. Zero userID or taskID returns attempts of any user or task.
+func (i *Intercepts) GetAll(ctx context.Context, userID, taskID, limit int) ([]model.Intercept, error) {
+	op := "intercepts.GetAll"
+
+	log := i.log.With(slog.String("op", op))
+
+	intercepts, err := i.storage.GetAll(ctx, userID, taskID, pagination.Limit(limit))
+	if err != nil {
+		log.Error("failed to get intercepts", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return intercepts, nil
+}
+
+// NextAttemptAt returns when the user may try to intercept a task again, zero if right away.
+func (i *Intercepts) NextAttemptAt(ctx context.Context, userID int) (time.Time, error) {
+	op := "intercepts.NextAttemptAt"
+
+	log := i.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	lastAttempt, err := i.storage.LastAttemptAt(ctx, userID)
+	if err != nil {
+		log.Error("failed to get last attempt", slog.String("error", err.Error()))
+		return time.Time{}, err
+	}
+
+	if lastAttempt.IsZero() || time.Since(lastAttempt) >= i.cfg.Cooldown {
+		return time.Time{}, nil
+	}
+
+	return lastAttempt.Add(i.cfg.Cooldown), nil
+}
+
+func (i *Intercepts) nextSeed() (int64, error) {
+	if i.rng != nil {
+		i.mu.Lock()
+		defer i.mu.Unlock()
+
+		return i.rng.Int63(), nil
+	}
+
+	var buf [8]byte
+	if _, err := crand.Read(buf[:]); err != nil {
+		return 0, err
+	}
+
+	return int64(binary.BigEndian.Uint64(buf[:]) >> 1), nil
+}
+
+func mapError(err error) error {
+	switch {
+	case errors.Is(err, errs.ErrInterceptCooldown):
+		return ErrCooldown
+	case errors.Is(err, errs.ErrVictimProtected):
+		return ErrVictimProtected
+	case errors.Is(err, errs.ErrInsufficientFunds):
+		return ErrInsufficientFunds
+	case errors.Is(err, errs.ErrTaskNotInterceptable):
+		return ErrTaskNotInterceptable
+	default:
+		return err
+	}
+}
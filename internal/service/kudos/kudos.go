@@ -0,0 +1,187 @@
+package kudos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/k6mil6/hackathon-game-backend/internal/config"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/pagination"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	notificationsstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/notifications"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrSelfKudos         = errors.New("you can not give kudos to yourself")
+	ErrInvalidAmount     = errors.New("amount must be positive")
+	ErrEmptyMessage      = errors.New("message can not be empty")
+	ErrMessageTooLong    = errors.New("message is too long")
+	ErrAllowanceExceeded = errors.New("not enough kudos left this month")
+	ErrReceiverNotFound  = errors.New("receiver not found")
+	ErrInvalidOffset     = errors.New("offset can not be negative")
+)
+
+type Kudos struct {
+	log                  *slog.Logger
+	storage              Storage
+	notificationsStorage NotificationsStorage
+	location             *time.Location
+	cfg                  config.KudosConfig
+}
+
+type Storage interface {
+	Give(ctx context.Context, kudos model.Kudos, allowance int) (model.Kudos, error)
+	Given(ctx context.Context, senderID int, period time.Time) (int, error)
+	GetFeed(ctx context.Context, limit, offset int) ([]model.Kudos, error)
+	GetReceived(ctx context.Context, receiverID, limit, offset int) ([]model.Kudos, error)
+}
+
+type NotificationsStorage interface {
+	Add(ctx context.Context, notification model.Notification) error
+}
+
+func New(
+	log *slog.Logger,
+	storage Storage,
+	notificationsStorage NotificationsStorage,
+	location *time.Location,
+	cfg config.KudosConfig,
+) *Kudos {
+	return &Kudos{
+		log:                  log,
+		storage:              storage,
+		notificationsStorage: notificationsStorage,
+		location:             location,
+		cfg:                  cfg,
+	}
+}
+
+// Give spends amount of the sender's monthly allowance on kudos to the receiver,
+// who gets CoinsPerKudo coins for each of them.
+func (k *Kudos) Give(ctx context.Context, senderID, receiverID, amount int, message string) (model.Kudos, error) {
+	op := "kudos.Give"
+
+	log := k.log.With(slog.String("op", op), slog.Int("senderID", senderID), slog.Int("receiverID", receiverID))
+
+	message = strings.TrimSpace(message)
+
+	switch {
+	case senderID == receiverID:
+		return model.Kudos{}, ErrSelfKudos
+	case amount <= 0:
+		return model.Kudos{}, ErrInvalidAmount
+	case message == "":
+		return model.Kudos{}, ErrEmptyMessage
+	case utf8.RuneCountInString(message) > k.cfg.MaxMessageLength:
+		return model.Kudos{}, ErrMessageTooLong
+	}
+
+	period, _ := monthBounds(time.Now(), k.location)
+
+	kudos, err := k.storage.Give(ctx, model.Kudos{
+		SenderID:   senderID,
+		ReceiverID: receiverID,
+		Amount:     amount,
+		Coins:      float64(amount) * k.cfg.CoinsPerKudo,
+		Message:    message,
+		Period:     period,
+	}, k.cfg.MonthlyAllowance)
+	if err != nil {
+		log.Error("failed to give kudos", slog.String("error", err.Error()))
+
+		switch {
+		case errors.Is(err, errs.ErrKudosAllowanceExceeded):
+			return model.Kudos{}, ErrAllowanceExceeded
+		case errors.Is(err, errs.ErrUserNotFound):
+			return model.Kudos{}, ErrReceiverNotFound
+		default:
+			return model.Kudos{}, err
+		}
+	}
+
+	err = k.notificationsStorage.Add(ctx, model.Notification{
+		UserID:  receiverID,
+		TypeID:  notificationsstorage.KudosReceivedTypeID,
+		Message: fmt.Sprintf("%s gave you %d kudos: %s", kudos.SenderName, kudos.Amount, kudos.Message),
+	})
+	if err != nil {
+		log.Error("failed to notify about kudos", slog.String("error", err.Error()))
+	}
+
+	return kudos, nil
+}
+
+// GetAllowance returns how many kudos the user may still give this month.
+func (k *Kudos) GetAllowance(ctx context.Context, userID int) (model.KudosAllowance, error) {
+	op := "kudos.GetAllowance"
+
+	log := k.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	period, expiresAt := monthBounds(time.Now(), k.location)
+
+	given, err := k.storage.Given(ctx, userID, period)
+	if err != nil {
+		log.Error("failed to get given kudos", slog.String("error", err.Error()))
+		return model.KudosAllowance{}, err
+	}
+
+	return model.KudosAllowance{
+		Allowance: k.cfg.MonthlyAllowance,
+		Given:     given,
+		Remaining: max(k.cfg.MonthlyAllowance-given, 0),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// GetFeed returns a page of the public kudos feed, newest first.
+func (k *Kudos) GetFeed(ctx context.Context, limit, offset int) ([]model.Kudos, error) {
+	op := "kudos.GetFeed"
+
+	log := k.log.With(slog.String("op", op))
+
+	if offset < 0 {
+		return nil, ErrInvalidOffset
+	}
+
+	kudos, err := k.storage.GetFeed(ctx, pagination.Limit(limit), offset)
+	if err != nil {
+		log.Error("failed to get kudos feed", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return kudos, nil
+}
+
+// GetReceived returns a page of kudos the user has received, newest first.
+func (k *Kudos) GetReceived(ctx context.Context, userID, limit, offset int) ([]model.Kudos, error) {
+	op := "kudos.GetReceived"
+
+	log := k.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	if offset < 0 {
+		return nil, ErrInvalidOffset
+	}
+
+	kudos, err := k.storage.GetReceived(ctx, userID, pagination.Limit(limit), offset)
+	if err != nil {
+		log.Error("failed to get received kudos", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return kudos, nil
+}
+
+// monthBounds returns the month of the moment in the location: its first day as a date
+// and the moment the next month starts.
+func monthBounds(now time.Time, location *time.Location) (time.Time, time.Time) {
+	local := now.In(location)
+
+	period := time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, time.UTC)
+	expiresAt := time.Date(local.Year(), local.Month()+1, 1, 0, 0, 0, 0, location)
+
+	return period, expiresAt
+}
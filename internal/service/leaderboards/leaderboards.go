@@ -0,0 +1,159 @@
+package leaderboards
+
+import (
+	"context"
+	"errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/pagination"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	leaderboardsstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/leaderboards"
+	"log/slog"
+	"time"
+)
+
+const (
+	DefaultNeighbours = 2
+	MaxNeighbours     = 10
+)
+
+var (
+	ErrUnknownMetric     = errors.New("unknown metric, use one of balance, earned, tasks, xp, weekly_earned, season_earned")
+	ErrGroupNotSupported = errors.New("group leaderboards are available for earned, tasks, weekly_earned and season_earned only")
+	ErrInvalidOffset     = errors.New("offset can not be negative")
+	ErrInvalidNeighbours = errors.New("neighbours can not be negative")
+	ErrNotRanked         = errors.New("you are not on this leaderboard")
+	ErrNoActiveSeason    = errors.New("no season is running now")
+)
+
+type Leaderboards struct {
+	log            *slog.Logger
+	storage        Storage
+	seasonsStorage SeasonsStorage
+}
+
+type Storage interface {
+	Get(ctx context.Context, filter model.LeaderboardFilter) ([]model.LeaderboardEntry, error)
+	GetAround(ctx context.Context, filter model.LeaderboardFilter, userID, neighbours int) ([]model.LeaderboardEntry, error)
+}
+
+type SeasonsStorage interface {
+	GetActive(ctx context.Context, at time.Time) (model.Season, error)
+}
+
+func New(log *slog.Logger, storage Storage, seasonsStorage SeasonsStorage) *Leaderboards {
+	return &Leaderboards{
+		log:            log,
+		storage:        storage,
+		seasonsStorage: seasonsStorage,
+	}
+}
+
+// Get returns a page of the leaderboard. An empty metric means the balance leaderboard.
+func (l *Leaderboards) Get(ctx context.Context, filter model.LeaderboardFilter) ([]model.LeaderboardEntry, error) {
+	op := "leaderboards.Get"
+
+	log := l.log.With(slog.String("op", op), slog.String("metric", filter.Metric))
+
+	filter, err := l.prepare(ctx, filter, time.Now())
+	if err != nil {
+		log.Error("invalid filter", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if filter.Offset < 0 {
+		log.Error("invalid offset", slog.Int("offset", filter.Offset))
+		return nil, ErrInvalidOffset
+	}
+
+	filter.Limit = pagination.Limit(filter.Limit)
+
+	entries, err := l.storage.Get(ctx, filter)
+	if err != nil {
+		log.Error("failed to get leaderboard", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// GetAround returns the place of the user on the leaderboard together with the neighbours above and below.
+// Zero neighbours means DefaultNeighbours.
+func (l *Leaderboards) GetAround(ctx context.Context, userID int, filter model.LeaderboardFilter, neighbours int) ([]model.LeaderboardEntry, error) {
+	op := "leaderboards.GetAround"
+
+	log := l.log.With(slog.String("op", op), slog.String("metric", filter.Metric), slog.Int("userID", userID))
+
+	filter, err := l.prepare(ctx, filter, time.Now())
+	if err != nil {
+		log.Error("invalid filter", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	switch {
+	case neighbours < 0:
+		log.Error("invalid neighbours", slog.Int("neighbours", neighbours))
+		return nil, ErrInvalidNeighbours
+	case neighbours == 0:
+		neighbours = DefaultNeighbours
+	case neighbours > MaxNeighbours:
+		neighbours = MaxNeighbours
+	}
+
+	entries, err := l.storage.GetAround(ctx, filter, userID, neighbours)
+	if err != nil {
+		log.Error("failed to get leaderboard", slog.String("error", err.Error()))
+		if errors.Is(err, errs.ErrUserNotRanked) {
+			return nil, ErrNotRanked
+		}
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// prepare validates the metric and sets the bounds of the period for periodic metrics.
+func (l *Leaderboards) prepare(ctx context.Context, filter model.LeaderboardFilter, now time.Time) (model.LeaderboardFilter, error) {
+	switch filter.Metric {
+	case "":
+		filter.Metric = leaderboardsstorage.MetricBalance
+	case leaderboardsstorage.MetricBalance,
+		leaderboardsstorage.MetricEarned,
+		leaderboardsstorage.MetricTasks,
+		leaderboardsstorage.MetricXP,
+		leaderboardsstorage.MetricWeeklyEarned,
+		leaderboardsstorage.MetricSeasonEarned:
+	default:
+		return model.LeaderboardFilter{}, ErrUnknownMetric
+	}
+
+	// balance and XP belong to the user, not to tasks, so they can not be split by group
+	if filter.GroupID != 0 && (filter.Metric == leaderboardsstorage.MetricBalance || filter.Metric == leaderboardsstorage.MetricXP) {
+		return model.LeaderboardFilter{}, ErrGroupNotSupported
+	}
+
+	filter.Since, filter.Until = time.Time{}, time.Time{}
+
+	switch filter.Metric {
+	case leaderboardsstorage.MetricWeeklyEarned:
+		filter.Since = weekStart(now)
+	case leaderboardsstorage.MetricSeasonEarned:
+		season, err := l.seasonsStorage.GetActive(ctx, now)
+		if err != nil {
+			if errors.Is(err, errs.ErrSeasonNotFound) {
+				return model.LeaderboardFilter{}, ErrNoActiveSeason
+			}
+			return model.LeaderboardFilter{}, err
+		}
+
+		filter.Since, filter.Until = season.StartsAt, season.EndsAt
+	}
+
+	return filter, nil
+}
+
+// weekStart returns midnight of the Monday of the week the time falls in.
+func weekStart(now time.Time) time.Time {
+	daysSinceMonday := (int(now.Weekday()) + 6) % 7
+
+	return time.Date(now.Year(), now.Month(), now.Day()-daysSinceMonday, 0, 0, 0, 0, now.Location())
+}
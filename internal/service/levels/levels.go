@@ -0,0 +1,221 @@
+package levels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/k6mil6/hackathon-game-backend/internal/config"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/levels"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/pagination"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	notificationsstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/notifications"
+	xpstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/xp"
+	"log/slog"
+	"math"
+)
+
+type Levels struct {
+	log                  *slog.Logger
+	storage              Storage
+	usersStorage         UsersStorage
+	notificationsStorage NotificationsStorage
+	curve                levels.Curve
+	xpPerCoin            float64
+}
+
+type Storage interface {
+	Award(ctx context.Context, entry model.XPEntry, curve levels.Curve) (model.XPAward, error)
+	GetLedger(ctx context.Context, userID, limit int) ([]model.XPEntry, error)
+	GetLevelUps(ctx context.Context, userID, limit int) ([]model.LevelUp, error)
+}
+
+type UsersStorage interface {
+	GetByID(ctx context.Context, id int) (model.User, error)
+}
+
+type NotificationsStorage interface {
+	Add(ctx context.Context, notification model.Notification) error
+}
+
+func New(
+	log *slog.Logger,
+	storage Storage,
+	usersStorage UsersStorage,
+	notificationsStorage NotificationsStorage,
+	cfg config.LevelsConfig,
+) *Levels {
+	return &Levels{
+		log:                  log,
+		storage:              storage,
+		usersStorage:         usersStorage,
+		notificationsStorage: notificationsStorage,
+		curve: levels.Curve{
+			Base:     cfg.BaseXP,
+			Growth:   cfg.Growth,
+			MaxLevel: cfg.MaxLevel,
+		},
+		xpPerCoin: cfg.XPPerCoin,
+	}
+}
+
+// TaskXP returns the XP the task gives: its own XP if set, otherwise XP derived from the reward.
+func (l *Levels) TaskXP(task model.Task) int {
+	if task.XP > 0 {
+		return task.XP
+	}
+
+	return int(math.Round(task.Amount * l.xpPerCoin))
+}
+
+// AwardForTask gives the assignee XP for the accepted task. A task gives XP only once,
+// a repeated call returns an empty award.
+func (l *Levels) AwardForTask(ctx context.Context, task model.Task) (model.XPAward, error) {
+	op := "levels.AwardForTask"
+
+	log := l.log.With(slog.String("op", op), slog.Int("taskID", task.ID), slog.Int("userID", task.UserID))
+
+	amount := l.TaskXP(task)
+	if amount <= 0 || task.UserID == 0 {
+		return model.XPAward{}, nil
+	}
+
+	return l.award(ctx, log, model.XPEntry{
+		UserID:   task.UserID,
+		SourceID: xpstorage.TaskSourceID,
+		TaskID:   task.ID,
+		Amount:   amount,
+	})
+}
+
+// AwardForAchievement gives the user the XP reward of the achievement. An achievement gives XP
+// only once per user, a repeated call returns an empty award.
+func (l *Levels) AwardForAchievement(ctx context.Context, userID int, achievement model.Achievement) (model.XPAward, error) {
+	op := "levels.AwardForAchievement"
+
+	log := l.log.With(slog.String("op", op), slog.Int("achievementID", achievement.ID), slog.Int("userID", userID))
+
+	if achievement.RewardXP <= 0 {
+		return model.XPAward{}, nil
+	}
+
+	return l.award(ctx, log, model.XPEntry{
+		UserID:        userID,
+		SourceID:      xpstorage.AchievementSourceID,
+		AchievementID: achievement.ID,
+		Amount:        achievement.RewardXP,
+	})
+}
+
+// AwardForQuiz gives the user XP for the reward of the passed quiz, like a task with the same reward.
+// A quiz gives XP only once per user, a repeated call returns an empty award.
+func (l *Levels) AwardForQuiz(ctx context.Context, userID, quizID int, reward float64) (model.XPAward, error) {
+	op := "levels.AwardForQuiz"
+
+	log := l.log.With(slog.String("op", op), slog.Int("quizID", quizID), slog.Int("userID", userID))
+
+	amount := int(math.Round(reward * l.xpPerCoin))
+	if amount <= 0 {
+		return model.XPAward{}, nil
+	}
+
+	return l.award(ctx, log, model.XPEntry{
+		UserID:   userID,
+		SourceID: xpstorage.QuizSourceID,
+		QuizID:   quizID,
+		Amount:   amount,
+	})
+}
+
+// Level returns the current level of the user.
+func (l *Levels) Level(ctx context.Context, userID int) (int, error) {
+	user, err := l.usersStorage.GetByID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return user.Level, nil
+}
+
+// GetProgress returns the XP of the user and the XP bounds of the current level.
+// NextLevelXP is zero at the maximum level.
+func (l *Levels) GetProgress(ctx context.Context, userID int) (model.LevelProgress, error) {
+	op := "levels.GetProgress"
+
+	log := l.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	user, err := l.usersStorage.GetByID(ctx, userID)
+	if err != nil {
+		log.Error("failed to get user", slog.String("error", err.Error()))
+		return model.LevelProgress{}, err
+	}
+
+	progress := model.LevelProgress{
+		XP:      user.XP,
+		Level:   user.Level,
+		LevelXP: l.curve.Threshold(user.Level),
+	}
+
+	if l.curve.MaxLevel == 0 || user.Level < l.curve.MaxLevel {
+		progress.NextLevelXP = l.curve.Threshold(user.Level + 1)
+	}
+
+	return progress, nil
+}
+
+func (l *Levels) GetLedger(ctx context.Context, userID, limit int) ([]model.XPEntry, error) {
+	op := "levels.GetLedger"
+
+	log := l.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	entries, err := l.storage.GetLedger(ctx, userID, pagination.Limit(limit))
+	if err != nil {
+		log.Error("failed to get ledger", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (l *Levels) GetLevelUps(ctx context.Context, userID, limit int) ([]model.LevelUp, error) {
+	op := "levels.GetLevelUps"
+
+	log := l.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	levelUps, err := l.storage.GetLevelUps(ctx, userID, pagination.Limit(limit))
+	if err != nil {
+		log.Error("failed to get level ups", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return levelUps, nil
+}
+
+func (l *Levels) award(ctx context.Context, log *slog.Logger, entry model.XPEntry) (model.XPAward, error) {
+	award, err := l.storage.Award(ctx, entry, l.curve)
+	if err != nil {
+		if errors.Is(err, errs.ErrXPAlreadyAwarded) {
+			log.Info("xp was already awarded")
+			return model.XPAward{}, nil
+		}
+
+		log.Error("failed to award xp", slog.String("error", err.Error()))
+		return model.XPAward{}, err
+	}
+
+	if award.NewLevel > award.OldLevel {
+		log.Info("level up", slog.Int("level", award.NewLevel))
+
+		err = l.notificationsStorage.Add(ctx, model.Notification{
+			UserID:  entry.UserID,
+			TypeID:  notificationsstorage.LevelUpTypeID,
+			Message: fmt.Sprintf("You have reached level %d", award.NewLevel),
+			TaskID:  entry.TaskID,
+		})
+		if err != nil {
+			log.Error("failed to notify about level up", slog.String("error", err.Error()))
+		}
+	}
+
+	return award, nil
+}
@@ -0,0 +1,70 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/pagination"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"log/slog"
+)
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+)
+
+type Notifications struct {
+	log     *slog.Logger
+	storage Storage
+}
+
+type Storage interface {
+	GetAllUserNotifications(ctx context.Context, userID int, unreadOnly bool, limit int) ([]model.Notification, error)
+	MarkAsRead(ctx context.Context, notificationID, userID int) error
+}
+
+func New(log *slog.Logger, storage Storage) *Notifications {
+	return &Notifications{
+		log:     log,
+		storage: storage,
+	}
+}
+
+func (n *Notifications) GetAllUserNotifications(ctx context.Context, userID int, unreadOnly bool, limit int) ([]model.Notification, error) {
+	op := "notifications.GetAllUserNotifications"
+
+	log := n.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	log.Info("getting notifications")
+
+	notifications, err := n.storage.GetAllUserNotifications(ctx, userID, unreadOnly, pagination.Limit(limit))
+	if err != nil {
+		log.Error("failed to get notifications", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("got notifications")
+
+	return notifications, nil
+}
+
+func (n *Notifications) MarkAsRead(ctx context.Context, notificationID, userID int) error {
+	op := "notifications.MarkAsRead"
+
+	log := n.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	log.Info("marking notification as read")
+
+	if err := n.storage.MarkAsRead(ctx, notificationID, userID); err != nil {
+		if errors.Is(err, errs.ErrNotificationNotFound) {
+			return ErrNotificationNotFound
+		}
+
+		log.Error("failed to mark notification as read", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("marked notification as read")
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package perks
+
+import (
+	"fmt"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	taskstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/tasks"
+	"math"
+	"time"
+)
+
+// catReward pays cats more coins for every accepted task.
+type catReward struct {
+	multiplier float64
+}
+
+func (c catReward) Name() string {
+	return "purring tycoon"
+}
+
+func (c catReward) Description() string {
+	return fmt.Sprintf("rewards for tasks are multiplied by %.2f", c.multiplier)
+}
+
+func (c catReward) Scale(strength float64) Perk {
+	return catReward{multiplier: 1 + (c.multiplier-1)*strength}
+}
+
+func (c catReward) ModifyReward(_ model.Task, reward float64) float64 {
+	return math.Round(reward*c.multiplier*100) / 100
+}
+
+// dogDeadline gives dogs more time: the deadline moves by a share of the time window of the task.
+type dogDeadline struct {
+	extension float64
+}
+
+func (d dogDeadline) Name() string {
+	return "rescue mission"
+}
+
+func (d dogDeadline) Description() string {
+	return fmt.Sprintf("deadlines are extended by %.0f%% of the task time window", d.extension*100)
+}
+
+func (d dogDeadline) Scale(strength float64) Perk {
+	return dogDeadline{extension: d.extension * strength}
+}
+
+func (d dogDeadline) ModifyDeadline(task model.Task, deadline time.Time) time.Time {
+	window := deadline.Sub(task.CreatedAt)
+	if window <= 0 {
+		return deadline
+	}
+
+	return deadline.Add(time.Duration(float64(window) * d.extension))
+}
+
+// racoonIntercept lets racoons see tasks other users are working on, so they can try to intercept them.
+type racoonIntercept struct{}
+
+// RacoonInterceptName is the name of the perk that lets racoons intercept tasks.
+const RacoonInterceptName = "secret thief"
+
+func (r racoonIntercept) Name() string {
+	return RacoonInterceptName
+}
+
+func (r racoonIntercept) Description() string {
+	return "can see and intercept tasks other users are working on"
+}
+
+func (r racoonIntercept) ModifyVisibility(user model.User, task model.Task, visible bool) bool {
+	if visible {
+		return true
+	}
+
+	return task.StatusID == taskstorage.InProgressStatusID && task.UserID != 0 && task.UserID != user.ID
+}
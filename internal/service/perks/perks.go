@@ -0,0 +1,247 @@
+package perks
+
+import (
+	"context"
+	"github.com/k6mil6/hackathon-game-backend/internal/config"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	taskstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/tasks"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/users"
+	"log/slog"
+	"time"
+)
+
+// Perk is a class ability. To take part in the game rules it implements one or more of
+// the hook interfaces below, each called at its own point of the task lifecycle.
+type Perk interface {
+	Name() string
+	Description() string
+}
+
+// RewardModifier changes the coins paid to the assignee when a task is accepted.
+type RewardModifier interface {
+	ModifyReward(task model.Task, reward float64) float64
+}
+
+// DeadlineModifier changes the moment until which the user may submit the task.
+type DeadlineModifier interface {
+	ModifyDeadline(task model.Task, deadline time.Time) time.Time
+}
+
+// VisibilityModifier decides whether the user may see and act on a task that is not theirs.
+type VisibilityModifier interface {
+	ModifyVisibility(user model.User, task model.Task, visible bool) bool
+}
+
+// Scaler is a perk whose effect grows with the strength, the well-being of the companion of the user.
+// Perks that do not scale work in full as long as the strength is above zero.
+type Scaler interface {
+	Scale(strength float64) Perk
+}
+
+type Perks struct {
+	log          *slog.Logger
+	usersStorage UsersStorage
+	companions   Companions
+	minWellBeing float64
+	perks        map[int][]classPerk
+}
+
+// classPerk is a perk of a class, unlocked from minLevel on.
+type classPerk struct {
+	perk     Perk
+	minLevel int
+}
+
+type UsersStorage interface {
+	GetByID(ctx context.Context, id int) (model.User, error)
+}
+
+type Companions interface {
+	WellBeing(ctx context.Context, userID int) (float64, error)
+}
+
+// New returns the engine with the perks of the built-in classes registered.
+func New(log *slog.Logger, usersStorage UsersStorage, companions Companions, cfg config.PerksConfig) *Perks {
+	p := &Perks{
+		log:          log,
+		usersStorage: usersStorage,
+		companions:   companions,
+		minWellBeing: cfg.MinWellBeing,
+		perks:        make(map[int][]classPerk),
+	}
+
+	p.Register(users.CatClassID, catReward{multiplier: cfg.CatRewardMultiplier}, cfg.CatRewardLevel)
+	p.Register(users.DogClassID, dogDeadline{extension: cfg.DogDeadlineExtension}, cfg.DogDeadlineLevel)
+	p.Register(users.RacoonClassID, racoonIntercept{}, cfg.RacoonInterceptLevel)
+
+	return p
+}
+
+// Register adds a perk to the class, unlocked from minLevel on.
+// Perks of a class are applied in registration order.
+func (p *Perks) Register(classID int, perk Perk, minLevel int) {
+	p.perks[classID] = append(p.perks[classID], classPerk{perk: perk, minLevel: minLevel})
+}
+
+// GetUserPerks returns the perks of the user's class, locked ones included, with the strength
+// the companion of the user gives them.
+func (p *Perks) GetUserPerks(ctx context.Context, userID int) ([]model.Perk, error) {
+	user, strength, err := p.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	perks := make([]model.Perk, 0, len(p.perks[user.ClassID]))
+	for _, registered := range p.perks[user.ClassID] {
+		perks = append(perks, model.Perk{
+			Name:        registered.perk.Name(),
+			Description: registered.perk.Description(),
+			MinLevel:    registered.minLevel,
+			Unlocked:    user.Level >= registered.minLevel,
+			Strength:    strength,
+		})
+	}
+
+	return perks, nil
+}
+
+// Holder is a user with the strength of their perks as of the moment it was fetched. It answers
+// any number of questions about tasks without going to the storage again, so a request fetches it once.
+type Holder struct {
+	perks    *Perks
+	user     model.User
+	strength float64
+}
+
+// For returns the perks of the user as they are now.
+func (p *Perks) For(ctx context.Context, userID int) (Holder, error) {
+	user, strength, err := p.user(ctx, userID)
+	if err != nil {
+		return Holder{}, err
+	}
+
+	return Holder{perks: p, user: user, strength: strength}, nil
+}
+
+// Reward returns the coins the user gets for the task.
+func (p *Perks) Reward(ctx context.Context, userID int, task model.Task) (float64, error) {
+	holder, err := p.For(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return holder.Reward(task), nil
+}
+
+// Deadline returns the deadline of the task for the user, zero if the task has none.
+func (p *Perks) Deadline(ctx context.Context, userID int, task model.Task) (time.Time, error) {
+	holder, err := p.For(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return holder.Deadline(task), nil
+}
+
+// HasPerk reports whether the perk with the name works for the user: it is unlocked
+// and the companion of the user is well enough.
+func (h Holder) HasPerk(name string) bool {
+	for _, perk := range h.perks.unlocked(h.user, h.strength) {
+		if perk.Name() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Reward returns the coins the user gets for the task.
+func (h Holder) Reward(task model.Task) float64 {
+	reward := task.Amount
+	for _, perk := range h.perks.unlocked(h.user, h.strength) {
+		if modifier, ok := perk.(RewardModifier); ok {
+			reward = modifier.ModifyReward(task, reward)
+		}
+	}
+
+	return reward
+}
+
+// Deadline returns the deadline of the task for the user, zero if the task has none. The deadline
+// fixed for the assignee when the task became theirs is kept, whatever the perks are now.
+func (h Holder) Deadline(task model.Task) time.Time {
+	if task.Deadline.IsZero() {
+		return time.Time{}
+	}
+
+	if task.UserID == h.user.ID && !task.UserDeadline.IsZero() {
+		return task.UserDeadline
+	}
+
+	deadline := task.Deadline
+	for _, perk := range h.perks.unlocked(h.user, h.strength) {
+		if modifier, ok := perk.(DeadlineModifier); ok {
+			deadline = modifier.ModifyDeadline(task, deadline)
+		}
+	}
+
+	return deadline
+}
+
+// CanSee reports whether the user may see the task. Everybody sees their own and shared
+// published tasks, perks may open up more.
+func (h Holder) CanSee(task model.Task) bool {
+	visible := task.StatusID != taskstorage.CreationApprovalStatusID &&
+		(task.UserID == h.user.ID || task.ForGroupID == taskstorage.AllGroupID)
+
+	for _, perk := range h.perks.unlocked(h.user, h.strength) {
+		if modifier, ok := perk.(VisibilityModifier); ok {
+			visible = modifier.ModifyVisibility(h.user, task, visible)
+		}
+	}
+
+	return visible
+}
+
+// user returns the user and the strength of their perks: the well-being of the companion,
+// zero while it is below the minimum.
+func (p *Perks) user(ctx context.Context, userID int) (model.User, float64, error) {
+	user, err := p.usersStorage.GetByID(ctx, userID)
+	if err != nil {
+		return model.User{}, 0, err
+	}
+
+	wellBeing, err := p.companions.WellBeing(ctx, userID)
+	if err != nil {
+		return model.User{}, 0, err
+	}
+
+	if wellBeing < p.minWellBeing {
+		return user, 0, nil
+	}
+
+	return user, wellBeing, nil
+}
+
+// unlocked returns the perks of the user's class the user has reached the level for,
+// scaled by the strength. Without strength no perk works.
+func (p *Perks) unlocked(user model.User, strength float64) []Perk {
+	if strength <= 0 {
+		return nil
+	}
+
+	perks := make([]Perk, 0, len(p.perks[user.ClassID]))
+	for _, registered := range p.perks[user.ClassID] {
+		if user.Level < registered.minLevel {
+			continue
+		}
+
+		if scaler, ok := registered.perk.(Scaler); ok {
+			perks = append(perks, scaler.Scale(strength))
+		} else {
+			perks = append(perks, registered.perk)
+		}
+	}
+
+	return perks
+}
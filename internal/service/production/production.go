@@ -0,0 +1,252 @@
+package production
+
+import (
+	"context"
+	"errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/config"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	achievementsservice "github.com/k6mil6/hackathon-game-backend/internal/service/achievements"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"log/slog"
+	"time"
+)
+
+var (
+	ErrInvalidQuantity    = errors.New("invalid quantity")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrBusinessNotFound   = errors.New("business not found")
+	ErrAlreadyOwned       = errors.New("business is already owned")
+	ErrNotOwner           = errors.New("you do not own this business")
+	ErrRecipeNotFound     = errors.New("recipe not found for this business")
+	ErrResourceNotFound   = errors.New("resource not found")
+	ErrNotEnoughResources = errors.New("not enough resources")
+	ErrInsufficientFunds  = errors.New("insufficient funds")
+)
+
+type Production struct {
+	log               *slog.Logger
+	storage           Storage
+	businessesStorage BusinessesStorage
+	achievements      Achievements
+	cfg               config.ProductionConfig
+}
+
+type Storage interface {
+	GetRecipes(ctx context.Context) ([]model.Recipe, error)
+	GetInventory(ctx context.Context, userID, baseCapacity int) (model.Inventory, error)
+	GetDue(ctx context.Context, now time.Time) ([]model.Business, error)
+	Produce(ctx context.Context, businessID, baseCapacity int, now time.Time) (model.Production, error)
+	Sell(ctx context.Context, userID, resourceID, quantity int, now time.Time) (model.ResourceSale, error)
+}
+
+type BusinessesStorage interface {
+	GetAll(ctx context.Context) ([]model.Business, error)
+	GetByOwner(ctx context.Context, ownerID int) ([]model.Business, error)
+	Buy(ctx context.Context, id, userID int, now time.Time) (model.Business, error)
+	SetRecipe(ctx context.Context, id, ownerID, recipeID int, now time.Time) (model.Business, error)
+}
+
+type Achievements interface {
+	Evaluate(ctx context.Context, userID int, event string) ([]model.UserAchievement, error)
+}
+
+func New(
+	log *slog.Logger,
+	storage Storage,
+	businessesStorage BusinessesStorage,
+	achievements Achievements,
+	cfg config.ProductionConfig,
+) *Production {
+	return &Production{
+		log:               log,
+		storage:           storage,
+		businessesStorage: businessesStorage,
+		achievements:      achievements,
+		cfg:               cfg,
+	}
+}
+
+// GetBusinesses returns all the businesses, those for sale have no owner.
+func (p *Production) GetBusinesses(ctx context.Context) ([]model.Business, error) {
+	op := "production.GetBusinesses"
+
+	log := p.log.With(slog.String("op", op))
+
+	businesses, err := p.businessesStorage.GetAll(ctx)
+	if err != nil {
+		log.Error("failed to get businesses", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return businesses, nil
+}
+
+// GetOwned returns the businesses the user owns.
+func (p *Production) GetOwned(ctx context.Context, userID int) ([]model.Business, error) {
+	op := "production.GetOwned"
+
+	log := p.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	businesses, err := p.businessesStorage.GetByOwner(ctx, userID)
+	if err != nil {
+		log.Error("failed to get businesses", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return businesses, nil
+}
+
+// BuyBusiness sells the business to the user, it starts producing with the first recipe of its type.
+func (p *Production) BuyBusiness(ctx context.Context, userID, businessID int) (model.Business, error) {
+	op := "production.BuyBusiness"
+
+	log := p.log.With(slog.String("op", op), slog.Int("userID", userID), slog.Int("businessID", businessID))
+
+	business, err := p.businessesStorage.Buy(ctx, businessID, userID, time.Now())
+	if err != nil {
+		log.Error("failed to buy business", slog.String("error", err.Error()))
+		return model.Business{}, mapError(err)
+	}
+
+	if _, err := p.achievements.Evaluate(ctx, userID, achievementsservice.EventBusinessBought); err != nil {
+		log.Error("failed to evaluate achievements", slog.String("error", err.Error()))
+	}
+
+	log.Info("business bought")
+
+	return business, nil
+}
+
+// SetRecipe switches what the business of the user produces, the production cycle starts over.
+func (p *Production) SetRecipe(ctx context.Context, userID, businessID, recipeID int) (model.Business, error) {
+	op := "production.SetRecipe"
+
+	log := p.log.With(slog.String("op", op), slog.Int("userID", userID), slog.Int("businessID", businessID))
+
+	business, err := p.businessesStorage.SetRecipe(ctx, businessID, userID, recipeID, time.Now())
+	if err != nil {
+		log.Error("failed to set recipe", slog.String("error", err.Error()))
+		return model.Business{}, mapError(err)
+	}
+
+	log.Info("recipe set", slog.Int("recipeID", recipeID))
+
+	return business, nil
+}
+
+// GetRecipes returns what every business type can produce.
+func (p *Production) GetRecipes(ctx context.Context) ([]model.Recipe, error) {
+	op := "production.GetRecipes"
+
+	log := p.log.With(slog.String("op", op))
+
+	recipes, err := p.storage.GetRecipes(ctx)
+	if err != nil {
+		log.Error("failed to get recipes", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return recipes, nil
+}
+
+// GetInventory returns the resources of the user and the storage capacity warehouses add to.
+func (p *Production) GetInventory(ctx context.Context, userID int) (model.Inventory, error) {
+	op := "production.GetInventory"
+
+	log := p.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	inventory, err := p.storage.GetInventory(ctx, userID, p.cfg.BaseCapacity)
+	if err != nil {
+		log.Error("failed to get inventory", slog.String("error", err.Error()))
+		return model.Inventory{}, err
+	}
+
+	return inventory, nil
+}
+
+// Sell sells the resource from the inventory of the user for its price.
+func (p *Production) Sell(ctx context.Context, userID, resourceID, quantity int) (model.ResourceSale, error) {
+	op := "production.Sell"
+
+	log := p.log.With(slog.String("op", op), slog.Int("userID", userID), slog.Int("resourceID", resourceID))
+
+	if quantity <= 0 || quantity > p.cfg.MaxSell {
+		return model.ResourceSale{}, ErrInvalidQuantity
+	}
+
+	sale, err := p.storage.Sell(ctx, userID, resourceID, quantity, time.Now())
+	if err != nil {
+		log.Error("failed to sell resource", slog.String("error", err.Error()))
+		return model.ResourceSale{}, mapError(err)
+	}
+
+	log.Info("resource sold", slog.Int("quantity", quantity), slog.Float64("amount", sale.Amount))
+
+	return sale, nil
+}
+
+// Produce runs the recipes of the businesses that are due. A business that lacks inputs or room
+// in the storage of its owner is skipped and tried again on the next run.
+func (p *Production) Produce(ctx context.Context) error {
+	op := "production.Produce"
+
+	log := p.log.With(slog.String("op", op))
+
+	now := time.Now()
+
+	due, err := p.storage.GetDue(ctx, now)
+	if err != nil {
+		log.Error("failed to get due businesses", slog.String("error", err.Error()))
+		return err
+	}
+
+	var errList []error
+
+	for _, business := range due {
+		production, err := p.storage.Produce(ctx, business.ID, p.cfg.BaseCapacity, now)
+		if err != nil {
+			switch {
+			case errors.Is(err, errs.ErrNotEnoughResources), errors.Is(err, errs.ErrStorageFull):
+				log.Info("business skipped", slog.Int("businessID", business.ID), slog.String("reason", err.Error()))
+			case errors.Is(err, errs.ErrProductionNotDue):
+				// produced by a concurrent run or switched to another recipe in the meantime
+			default:
+				log.Error("failed to produce", slog.Int("businessID", business.ID), slog.String("error", err.Error()))
+				errList = append(errList, err)
+			}
+			continue
+		}
+
+		log.Info("business produced",
+			slog.Int("businessID", business.ID),
+			slog.Int("ownerID", production.OwnerID),
+			slog.Int("resourceID", production.Output.ResourceID),
+			slog.Int("quantity", production.Output.Quantity),
+		)
+	}
+
+	return errors.Join(errList...)
+}
+
+func mapError(err error) error {
+	switch {
+	case errors.Is(err, errs.ErrUserNotFound):
+		return ErrUserNotFound
+	case errors.Is(err, errs.ErrBusinessNotFound):
+		return ErrBusinessNotFound
+	case errors.Is(err, errs.ErrBusinessAlreadyOwned):
+		return ErrAlreadyOwned
+	case errors.Is(err, errs.ErrNotBusinessOwner):
+		return ErrNotOwner
+	case errors.Is(err, errs.ErrRecipeNotFound):
+		return ErrRecipeNotFound
+	case errors.Is(err, errs.ErrResourceNotFound):
+		return ErrResourceNotFound
+	case errors.Is(err, errs.ErrNotEnoughResources):
+		return ErrNotEnoughResources
+	case errors.Is(err, errs.ErrInsufficientFunds):
+		return ErrInsufficientFunds
+	}
+
+	return err
+}
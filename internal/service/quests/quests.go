@@ -0,0 +1,427 @@
+package quests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/k6mil6/hackathon-game-backend/internal/config"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/quests"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	notificationsstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/notifications"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotEnoughPermission = errors.New("not enough permission")
+	ErrInvalidName         = errors.New("quest name can not be empty")
+	ErrInvalidStepName     = errors.New("step name can not be empty")
+	ErrInvalidAmount       = errors.New("amount can not be negative")
+	ErrInvalidBonus        = errors.New("bonus can not be negative")
+	ErrQuestNotFound       = errors.New("quest not found")
+	ErrAlreadyPublished    = errors.New("quest was already published")
+	ErrAlreadyStarted      = errors.New("quest was already started")
+	ErrUserNotFound        = errors.New("user not found")
+	ErrNoBudget            = errors.New("no budget allocated for this period")
+	ErrBudgetExceeded      = errors.New("quest budget is exhausted")
+)
+
+type Quests struct {
+	log                  *slog.Logger
+	storage              Storage
+	notificationsStorage NotificationsStorage
+	budgetsStorage       BudgetsStorage
+	enforceBudget        bool
+}
+
+type Storage interface {
+	Add(ctx context.Context, quest model.Quest) (int, error)
+	Update(ctx context.Context, quest model.Quest) error
+	Publish(ctx context.Context, id, budgetID int) error
+	GetByID(ctx context.Context, id int) (model.Quest, error)
+	GetAll(ctx context.Context, publishedOnly bool) ([]model.Quest, error)
+	GetProgress(ctx context.Context, userID int) ([]model.QuestProgress, error)
+	Start(ctx context.Context, userID, questID int) (model.QuestProgress, error)
+	Advance(ctx context.Context, taskID int) (model.QuestAdvance, error)
+	GetStalledSteps(ctx context.Context) ([]int, error)
+}
+
+type NotificationsStorage interface {
+	Add(ctx context.Context, notification model.Notification) error
+}
+
+type BudgetsStorage interface {
+	Reserve(ctx context.Context, adminID, groupID int, amount float64, at time.Time) (int, error)
+}
+
+func New(
+	log *slog.Logger,
+	storage Storage,
+	notificationsStorage NotificationsStorage,
+	budgetsStorage BudgetsStorage,
+	budgetCfg config.BudgetConfig,
+) *Quests {
+	return &Quests{
+		log:                  log,
+		storage:              storage,
+		notificationsStorage: notificationsStorage,
+		budgetsStorage:       budgetsStorage,
+		enforceBudget:        budgetCfg.Enforce,
+	}
+}
+
+// Create stores a new unpublished quest. Steps are numbered by their order, an ordered quest makes
+// every step require the previous one, otherwise the steps keep the prerequisites they were given.
+func (q *Quests) Create(ctx context.Context, adminID int, quest model.Quest, ordered bool) (int, error) {
+	op := "quests.Create"
+
+	log := q.log.With(slog.String("op", op), slog.Int("adminID", adminID))
+
+	quest, err := prepare(quest, ordered)
+	if err != nil {
+		log.Error("invalid quest", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	quest.CreatedBy = adminID
+
+	id, err := q.storage.Add(ctx, quest)
+	if err != nil {
+		log.Error("failed to add quest", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	log.Info("quest created", slog.Int("id", id))
+
+	return id, nil
+}
+
+// Update replaces the quest and its steps, only its author may change it and only until it is published.
+func (q *Quests) Update(ctx context.Context, adminID int, quest model.Quest, ordered bool) error {
+	op := "quests.Update"
+
+	log := q.log.With(slog.String("op", op), slog.Int("adminID", adminID), slog.Int("id", quest.ID))
+
+	if err := q.checkAuthor(ctx, adminID, quest.ID); err != nil {
+		log.Error("cannot update quest", slog.String("error", err.Error()))
+		return err
+	}
+
+	quest, err := prepare(quest, ordered)
+	if err != nil {
+		log.Error("invalid quest", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := q.storage.Update(ctx, quest); err != nil {
+		log.Error("failed to update quest", slog.String("error", err.Error()))
+		return mapError(err)
+	}
+
+	log.Info("quest updated")
+
+	return nil
+}
+
+// Publish makes the quest available to users, only its author may publish it. Its rewards are charged to
+// the budget the author has at the time, how many users start the quest is unknown, so the rewards
+// a user may earn are reserved when the user starts it.
+func (q *Quests) Publish(ctx context.Context, adminID, id int) error {
+	op := "quests.Publish"
+
+	log := q.log.With(slog.String("op", op), slog.Int("adminID", adminID), slog.Int("id", id))
+
+	if err := q.checkAuthor(ctx, adminID, id); err != nil {
+		log.Error("cannot publish quest", slog.String("error", err.Error()))
+		return err
+	}
+
+	quest, err := q.storage.GetByID(ctx, id)
+	if err != nil {
+		log.Error("failed to get quest", slog.String("error", err.Error()))
+		return mapError(err)
+	}
+
+	budgetID, err := q.budgetsStorage.Reserve(ctx, adminID, 0, 0, time.Now())
+	if err != nil {
+		log.Error("failed to find budget", slog.String("error", err.Error()))
+		return err
+	}
+
+	if budgetID == 0 && q.enforceBudget && rewards(quest) > 0 {
+		log.Error("no budget allocated")
+		return ErrNoBudget
+	}
+
+	if err := q.storage.Publish(ctx, id, budgetID); err != nil {
+		log.Error("failed to publish quest", slog.String("error", err.Error()))
+		return mapError(err)
+	}
+
+	log.Info("quest published")
+
+	return nil
+}
+
+// GetAll returns every quest, published or not, for admins.
+func (q *Quests) GetAll(ctx context.Context, adminID int) ([]model.Quest, error) {
+	op := "quests.GetAll"
+
+	log := q.log.With(slog.String("op", op), slog.Int("adminID", adminID))
+
+	result, err := q.storage.GetAll(ctx, false)
+	if err != nil {
+		log.Error("failed to get quests", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetPublished returns the published quests with the progress of the user in them.
+func (q *Quests) GetPublished(ctx context.Context, userID int) ([]model.UserQuest, error) {
+	op := "quests.GetPublished"
+
+	log := q.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	published, err := q.storage.GetAll(ctx, true)
+	if err != nil {
+		log.Error("failed to get quests", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	progress, err := q.progress(ctx, userID)
+	if err != nil {
+		log.Error("failed to get progress", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	result := make([]model.UserQuest, 0, len(published))
+	for _, quest := range published {
+		result = append(result, model.UserQuest{
+			Quest:    quest,
+			Progress: progress[quest.ID],
+		})
+	}
+
+	return result, nil
+}
+
+// Get returns the published quest with the progress of the user in it.
+func (q *Quests) Get(ctx context.Context, userID, questID int) (model.UserQuest, error) {
+	op := "quests.Get"
+
+	log := q.log.With(slog.String("op", op), slog.Int("userID", userID), slog.Int("questID", questID))
+
+	quest, err := q.storage.GetByID(ctx, questID)
+	if err != nil {
+		log.Error("failed to get quest", slog.String("error", err.Error()))
+		return model.UserQuest{}, mapError(err)
+	}
+
+	if quest.PublishedAt.IsZero() {
+		return model.UserQuest{}, ErrQuestNotFound
+	}
+
+	progress, err := q.progress(ctx, userID)
+	if err != nil {
+		log.Error("failed to get progress", slog.String("error", err.Error()))
+		return model.UserQuest{}, err
+	}
+
+	return model.UserQuest{
+		Quest:    quest,
+		Progress: progress[questID],
+	}, nil
+}
+
+// Start begins the quest for the user, who is assigned the tasks of the steps without prerequisites.
+func (q *Quests) Start(ctx context.Context, userID, questID int) (model.QuestProgress, error) {
+	op := "quests.Start"
+
+	log := q.log.With(slog.String("op", op), slog.Int("userID", userID), slog.Int("questID", questID))
+
+	progress, err := q.storage.Start(ctx, userID, questID)
+	if err != nil {
+		log.Error("failed to start quest", slog.String("error", err.Error()))
+		return model.QuestProgress{}, mapError(err)
+	}
+
+	q.notifyUnlocked(ctx, log, userID, progress.Steps)
+
+	log.Info("quest started")
+
+	return progress, nil
+}
+
+// Advance moves the quest on once its step task is accepted: it assigns the tasks of the steps that
+// got unlocked and pays the bonus when the whole quest is completed. Tasks outside quests change nothing.
+func (q *Quests) Advance(ctx context.Context, taskID int) (model.QuestAdvance, error) {
+	op := "quests.Advance"
+
+	log := q.log.With(slog.String("op", op), slog.Int("taskID", taskID))
+
+	advance, err := q.storage.Advance(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, errs.ErrQuestStepNotFound) {
+			return model.QuestAdvance{}, nil
+		}
+
+		log.Error("failed to advance quest", slog.String("error", err.Error()))
+		return model.QuestAdvance{}, err
+	}
+
+	q.notifyUnlocked(ctx, log, advance.UserID, advance.Unlocked)
+
+	if advance.Completed {
+		message := fmt.Sprintf("You have completed quest %s", advance.QuestName)
+		if advance.Bonus > 0 {
+			message = fmt.Sprintf("You have completed quest %s and got a bonus of %.2f coins", advance.QuestName, advance.Bonus)
+		}
+
+		err := q.notificationsStorage.Add(ctx, model.Notification{
+			UserID:  advance.UserID,
+			TypeID:  notificationsstorage.QuestCompletedTypeID,
+			Message: message,
+		})
+		if err != nil {
+			log.Error("failed to notify about quest completion", slog.String("error", err.Error()))
+		}
+	}
+
+	log.Info("quest advanced", slog.Int("questID", advance.QuestID), slog.Bool("completed", advance.Completed))
+
+	return advance, nil
+}
+
+// Reconcile advances the quests whose step tasks were accepted while advancing them failed,
+// so a lost advance only delays the next steps and the bonus.
+func (q *Quests) Reconcile(ctx context.Context) error {
+	op := "quests.Reconcile"
+
+	log := q.log.With(slog.String("op", op))
+
+	taskIDs, err := q.storage.GetStalledSteps(ctx)
+	if err != nil {
+		log.Error("failed to get stalled steps", slog.String("error", err.Error()))
+		return err
+	}
+
+	var errList []error
+
+	for _, taskID := range taskIDs {
+		if _, err := q.Advance(ctx, taskID); err != nil {
+			errList = append(errList, err)
+		}
+	}
+
+	return errors.Join(errList...)
+}
+
+// notifyUnlocked tells the user about the tasks assigned for the unlocked steps, failures are only logged.
+func (q *Quests) notifyUnlocked(ctx context.Context, log *slog.Logger, userID int, steps []model.QuestStepProgress) {
+	for _, step := range steps {
+		err := q.notificationsStorage.Add(ctx, model.Notification{
+			UserID:  userID,
+			TypeID:  notificationsstorage.TaskAssignedTypeID,
+			Message: "A new quest step is unlocked for you",
+			TaskID:  step.TaskID,
+		})
+		if err != nil {
+			log.Error("failed to notify about unlocked step", slog.Int("taskID", step.TaskID), slog.String("error", err.Error()))
+		}
+	}
+}
+
+// progress returns the progress of the user by quest.
+func (q *Quests) progress(ctx context.Context, userID int) (map[int]model.QuestProgress, error) {
+	progress, err := q.storage.GetProgress(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byQuest := make(map[int]model.QuestProgress, len(progress))
+	for _, p := range progress {
+		byQuest[p.QuestID] = p
+	}
+
+	return byQuest, nil
+}
+
+func (q *Quests) checkAuthor(ctx context.Context, adminID, questID int) error {
+	quest, err := q.storage.GetByID(ctx, questID)
+	if err != nil {
+		return mapError(err)
+	}
+
+	if quest.CreatedBy != adminID {
+		return ErrNotEnoughPermission
+	}
+
+	return nil
+}
+
+// rewards returns the coins a user earns for completing the whole quest.
+func rewards(quest model.Quest) float64 {
+	total := quest.Bonus
+	for _, step := range quest.Steps {
+		total += step.Amount
+	}
+
+	return total
+}
+
+// prepare validates the quest and numbers its steps, chaining them if the quest is ordered.
+// Invalid prerequisites are reported with the errors of the quests lib.
+func prepare(quest model.Quest, ordered bool) (model.Quest, error) {
+	quest.Name = strings.TrimSpace(quest.Name)
+	if quest.Name == "" {
+		return model.Quest{}, ErrInvalidName
+	}
+
+	if quest.Bonus < 0 {
+		return model.Quest{}, ErrInvalidBonus
+	}
+
+	for i := range quest.Steps {
+		quest.Steps[i].Position = i + 1
+
+		quest.Steps[i].Name = strings.TrimSpace(quest.Steps[i].Name)
+		if quest.Steps[i].Name == "" {
+			return model.Quest{}, ErrInvalidStepName
+		}
+
+		if quest.Steps[i].Amount < 0 {
+			return model.Quest{}, ErrInvalidAmount
+		}
+	}
+
+	if ordered {
+		quest.Steps = quests.Chain(quest.Steps)
+	}
+
+	if err := quests.Validate(quest.Steps); err != nil {
+		return model.Quest{}, err
+	}
+
+	return quest, nil
+}
+
+func mapError(err error) error {
+	switch {
+	case errors.Is(err, errs.ErrQuestNotFound):
+		return ErrQuestNotFound
+	case errors.Is(err, errs.ErrQuestAlreadyPublished):
+		return ErrAlreadyPublished
+	case errors.Is(err, errs.ErrQuestAlreadyStarted):
+		return ErrAlreadyStarted
+	case errors.Is(err, errs.ErrUserNotFound):
+		return ErrUserNotFound
+	case errors.Is(err, errs.ErrBudgetExceeded):
+		return ErrBudgetExceeded
+	}
+
+	return err
+}
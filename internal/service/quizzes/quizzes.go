@@ -0,0 +1,389 @@
+package quizzes
+
+import (
+	"context"
+	"errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/config"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/pagination"
+	quizzeslib "github.com/k6mil6/hackathon-game-backend/internal/lib/quizzes"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	quizzesstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/quizzes"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotEnoughPermission = errors.New("not enough permission")
+	ErrNameRequired        = errors.New("name is required")
+	ErrUnknownType         = errors.New("unknown quiz type")
+	ErrInvalidAmount       = errors.New("amount can not be negative")
+	ErrInvalidPassScore    = errors.New("pass score must be between 1 and 100")
+	ErrClosesInPast        = errors.New("quiz must close in the future")
+	ErrTooManyQuestions    = errors.New("too many questions")
+	ErrTooManyOptions      = errors.New("too many options")
+	ErrTextTooLong         = errors.New("text is too long")
+	ErrInvalidOffset       = errors.New("offset can not be negative")
+	ErrUserNotFound        = errors.New("user not found")
+	ErrQuizNotFound        = errors.New("quiz not found")
+	ErrQuizClosed          = errors.New("quiz is closed")
+	ErrAlreadySubmitted    = errors.New("you have already submitted this quiz")
+	ErrNotSubmitted        = errors.New("you have not submitted this quiz")
+	ErrNoBudget            = errors.New("no budget allocated for this period")
+	ErrBudgetExceeded      = errors.New("budget exceeded")
+)
+
+type Quizzes struct {
+	log            *slog.Logger
+	storage        Storage
+	budgetsStorage BudgetsStorage
+	levels         Levels
+	streaks        Streaks
+	cfg            config.QuizConfig
+	enforceBudget  bool
+}
+
+type Storage interface {
+	Create(ctx context.Context, quiz model.Quiz) (model.Quiz, error)
+	Finish(ctx context.Context, id int, now time.Time) (model.Quiz, error)
+	Submit(ctx context.Context, submission model.QuizSubmission, now time.Time) (model.QuizSubmission, error)
+	GetByID(ctx context.Context, id, userID int) (model.Quiz, error)
+	GetOpen(ctx context.Context, userID int, now time.Time, limit, offset int) ([]model.Quiz, error)
+	GetAll(ctx context.Context, limit, offset int) ([]model.Quiz, error)
+	GetSubmission(ctx context.Context, quizID, userID int) (model.QuizSubmission, error)
+	GetResults(ctx context.Context, quizID, maxTexts int) ([]model.QuizQuestionResult, error)
+}
+
+type BudgetsStorage interface {
+	Reserve(ctx context.Context, adminID, groupID int, amount float64, at time.Time) (int, error)
+}
+
+type Levels interface {
+	AwardForQuiz(ctx context.Context, userID, quizID int, reward float64) (model.XPAward, error)
+}
+
+type Streaks interface {
+	Record(ctx context.Context, userID int) (model.Streak, error)
+}
+
+func New(
+	log *slog.Logger,
+	storage Storage,
+	budgetsStorage BudgetsStorage,
+	levels Levels,
+	streaks Streaks,
+	cfg config.QuizConfig,
+	budgetCfg config.BudgetConfig,
+) *Quizzes {
+	return &Quizzes{
+		log:            log,
+		storage:        storage,
+		budgetsStorage: budgetsStorage,
+		levels:         levels,
+		streaks:        streaks,
+		cfg:            cfg,
+		enforceBudget:  budgetCfg.Enforce,
+	}
+}
+
+// Create adds a quiz or a poll. Polls have no pass score and no correct answers, they pay
+// Amount to everyone who takes part. Rewards are charged to the budget of the admin at the time
+// of creation, the number of submissions is unknown, so nothing is reserved up front.
+func (q *Quizzes) Create(ctx context.Context, adminID int, quiz model.Quiz) (model.Quiz, error) {
+	op := "quizzes.Create"
+
+	log := q.log.With(slog.String("op", op), slog.Int("adminID", adminID))
+
+	quiz.CreatedBy = adminID
+	quiz.Name = strings.TrimSpace(quiz.Name)
+	quiz.Description = strings.TrimSpace(quiz.Description)
+
+	if quiz.TypeID == quizzesstorage.PollTypeID {
+		quiz.PassScore = 0
+	}
+
+	if err := q.validate(quiz, time.Now()); err != nil {
+		log.Error("invalid quiz", slog.String("error", err.Error()))
+		return model.Quiz{}, err
+	}
+
+	questions, err := quizzeslib.Validate(quiz.Questions, quiz.TypeID == quizzesstorage.QuizTypeID)
+	if err != nil {
+		log.Error("invalid questions", slog.String("error", err.Error()))
+		return model.Quiz{}, err
+	}
+	quiz.Questions = questions
+
+	if quiz.Amount > 0 {
+		budgetID, err := q.budgetsStorage.Reserve(ctx, adminID, 0, 0, time.Now())
+		if err != nil {
+			log.Error("failed to find budget", slog.String("error", err.Error()))
+			return model.Quiz{}, err
+		}
+
+		if budgetID == 0 && q.enforceBudget {
+			log.Error("no budget allocated")
+			return model.Quiz{}, ErrNoBudget
+		}
+
+		quiz.BudgetID = budgetID
+	}
+
+	created, err := q.storage.Create(ctx, quiz)
+	if err != nil {
+		log.Error("failed to create quiz", slog.String("error", err.Error()))
+		return model.Quiz{}, mapError(err)
+	}
+
+	log.Info("quiz created", slog.Int("id", created.ID), slog.Int("typeID", created.TypeID))
+
+	return created, nil
+}
+
+// Close stops the quiz from taking submissions before its closing time.
+func (q *Quizzes) Close(ctx context.Context, adminID, id int) (model.Quiz, error) {
+	op := "quizzes.Close"
+
+	log := q.log.With(slog.String("op", op), slog.Int("adminID", adminID), slog.Int("id", id))
+
+	quiz, err := q.storage.Finish(ctx, id, time.Now())
+	if err != nil {
+		log.Error("failed to close quiz", slog.String("error", err.Error()))
+		return model.Quiz{}, mapError(err)
+	}
+
+	log.Info("quiz closed")
+
+	return quiz, nil
+}
+
+// Submit grades the answers of the user and pays the reward: a quiz pays when the score reaches
+// its pass score, a poll pays for taking part. A paid submission also gives XP and counts towards
+// the streak, like an accepted task. Every quiz can be submitted once.
+func (q *Quizzes) Submit(ctx context.Context, quizID, userID int, answers []model.QuizAnswer) (model.QuizSubmission, error) {
+	op := "quizzes.Submit"
+
+	log := q.log.With(slog.String("op", op), slog.Int("quizID", quizID), slog.Int("userID", userID))
+
+	quiz, err := q.storage.GetByID(ctx, quizID, userID)
+	if err != nil {
+		log.Error("failed to get quiz", slog.String("error", err.Error()))
+		return model.QuizSubmission{}, mapError(err)
+	}
+
+	if quiz.Submitted {
+		return model.QuizSubmission{}, ErrAlreadySubmitted
+	}
+
+	for _, answer := range answers {
+		if len(answer.Text) > q.cfg.MaxTextLength {
+			return model.QuizSubmission{}, ErrTextTooLong
+		}
+	}
+
+	graded, score, err := quizzeslib.Grade(quiz.Questions, answers)
+	if err != nil {
+		log.Error("invalid answers", slog.String("error", err.Error()))
+		return model.QuizSubmission{}, err
+	}
+
+	submission := model.QuizSubmission{
+		QuizID:  quizID,
+		UserID:  userID,
+		Score:   score,
+		Passed:  quiz.TypeID == quizzesstorage.PollTypeID || score >= float64(quiz.PassScore),
+		Answers: graded,
+	}
+
+	if submission.Passed {
+		submission.Reward = quiz.Amount
+	}
+
+	submission, err = q.storage.Submit(ctx, submission, time.Now())
+	if err != nil {
+		log.Error("failed to submit quiz", slog.String("error", err.Error()))
+		return model.QuizSubmission{}, mapError(err)
+	}
+
+	log.Info("quiz submitted", slog.Float64("score", submission.Score), slog.Bool("passed", submission.Passed))
+
+	if submission.Reward > 0 {
+		// the reward is already paid, so failures are only logged
+		if _, err := q.levels.AwardForQuiz(ctx, userID, quizID, submission.Reward); err != nil {
+			log.Error("failed to award xp", slog.String("error", err.Error()))
+		}
+
+		if _, err := q.streaks.Record(ctx, userID); err != nil {
+			log.Error("failed to record streak", slog.String("error", err.Error()))
+		}
+	}
+
+	return submission, nil
+}
+
+// Get returns the quiz for the user to answer, the correct answers are left out.
+func (q *Quizzes) Get(ctx context.Context, id, userID int) (model.Quiz, error) {
+	op := "quizzes.Get"
+
+	log := q.log.With(slog.String("op", op), slog.Int("id", id), slog.Int("userID", userID))
+
+	quiz, err := q.storage.GetByID(ctx, id, userID)
+	if err != nil {
+		log.Error("failed to get quiz", slog.String("error", err.Error()))
+		return model.Quiz{}, mapError(err)
+	}
+
+	for i := range quiz.Questions {
+		question := &quiz.Questions[i]
+		question.CorrectOptions, question.CorrectNumber, question.Tolerance, question.CorrectTexts = nil, nil, 0, nil
+	}
+
+	return quiz, nil
+}
+
+// GetForAdmin returns the quiz with the correct answers.
+func (q *Quizzes) GetForAdmin(ctx context.Context, adminID, id int) (model.Quiz, error) {
+	op := "quizzes.GetForAdmin"
+
+	log := q.log.With(slog.String("op", op), slog.Int("adminID", adminID), slog.Int("id", id))
+
+	quiz, err := q.storage.GetByID(ctx, id, 0)
+	if err != nil {
+		log.Error("failed to get quiz", slog.String("error", err.Error()))
+		return model.Quiz{}, mapError(err)
+	}
+
+	return quiz, nil
+}
+
+// GetOpen returns a page of the quizzes and polls taking submissions.
+func (q *Quizzes) GetOpen(ctx context.Context, userID, limit, offset int) ([]model.Quiz, error) {
+	op := "quizzes.GetOpen"
+
+	log := q.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	if offset < 0 {
+		return nil, ErrInvalidOffset
+	}
+
+	quizzes, err := q.storage.GetOpen(ctx, userID, time.Now(), pagination.Limit(limit), offset)
+	if err != nil {
+		log.Error("failed to get quizzes", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return quizzes, nil
+}
+
+// GetAll returns a page of all the quizzes and polls for admins, closed ones included.
+func (q *Quizzes) GetAll(ctx context.Context, adminID, limit, offset int) ([]model.Quiz, error) {
+	op := "quizzes.GetAll"
+
+	log := q.log.With(slog.String("op", op), slog.Int("adminID", adminID))
+
+	if offset < 0 {
+		return nil, ErrInvalidOffset
+	}
+
+	quizzes, err := q.storage.GetAll(ctx, pagination.Limit(limit), offset)
+	if err != nil {
+		log.Error("failed to get quizzes", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return quizzes, nil
+}
+
+// GetSubmission returns the graded answers of the user.
+func (q *Quizzes) GetSubmission(ctx context.Context, quizID, userID int) (model.QuizSubmission, error) {
+	op := "quizzes.GetSubmission"
+
+	log := q.log.With(slog.String("op", op), slog.Int("quizID", quizID), slog.Int("userID", userID))
+
+	submission, err := q.storage.GetSubmission(ctx, quizID, userID)
+	if err != nil {
+		log.Error("failed to get submission", slog.String("error", err.Error()))
+		return model.QuizSubmission{}, mapError(err)
+	}
+
+	return submission, nil
+}
+
+// GetResults aggregates the answers to every question of the quiz for admins.
+func (q *Quizzes) GetResults(ctx context.Context, adminID, quizID int) ([]model.QuizQuestionResult, error) {
+	op := "quizzes.GetResults"
+
+	log := q.log.With(slog.String("op", op), slog.Int("adminID", adminID), slog.Int("quizID", quizID))
+
+	if _, err := q.storage.GetByID(ctx, quizID, 0); err != nil {
+		log.Error("failed to get quiz", slog.String("error", err.Error()))
+		return nil, mapError(err)
+	}
+
+	results, err := q.storage.GetResults(ctx, quizID, q.cfg.MaxResultTexts)
+	if err != nil {
+		log.Error("failed to get results", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (q *Quizzes) validate(quiz model.Quiz, now time.Time) error {
+	switch {
+	case quiz.Name == "":
+		return ErrNameRequired
+	case quiz.TypeID != quizzesstorage.QuizTypeID && quiz.TypeID != quizzesstorage.PollTypeID:
+		return ErrUnknownType
+	case quiz.Amount < 0:
+		return ErrInvalidAmount
+	case quiz.TypeID == quizzesstorage.QuizTypeID && (quiz.PassScore < 1 || quiz.PassScore > 100):
+		return ErrInvalidPassScore
+	case !quiz.ClosesAt.IsZero() && !quiz.ClosesAt.After(now):
+		return ErrClosesInPast
+	case len(quiz.Questions) > q.cfg.MaxQuestions:
+		return ErrTooManyQuestions
+	}
+
+	for _, question := range quiz.Questions {
+		if len(question.Options) > q.cfg.MaxOptions {
+			return ErrTooManyOptions
+		}
+
+		if len(question.Text) > q.cfg.MaxTextLength {
+			return ErrTextTooLong
+		}
+
+		for _, texts := range [][]string{question.Options, question.CorrectTexts} {
+			for _, text := range texts {
+				if len(text) > q.cfg.MaxTextLength {
+					return ErrTextTooLong
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func mapError(err error) error {
+	switch {
+	case errors.Is(err, errs.ErrAdminNotFound):
+		return ErrNotEnoughPermission
+	case errors.Is(err, errs.ErrUserNotFound):
+		return ErrUserNotFound
+	case errors.Is(err, errs.ErrQuizNotFound):
+		return ErrQuizNotFound
+	case errors.Is(err, errs.ErrQuizClosed):
+		return ErrQuizClosed
+	case errors.Is(err, errs.ErrQuizAlreadySubmitted):
+		return ErrAlreadySubmitted
+	case errors.Is(err, errs.ErrQuizNotSubmitted):
+		return ErrNotSubmitted
+	case errors.Is(err, errs.ErrBudgetExceeded):
+		return ErrBudgetExceeded
+	}
+
+	return err
+}
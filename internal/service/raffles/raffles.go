@@ -0,0 +1,377 @@
+package raffles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/k6mil6/hackathon-game-backend/internal/config"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/pagination"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/raffles"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	notificationsstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/notifications"
+	rafflesstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/raffles"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotEnoughPermission = errors.New("not enough permission")
+	ErrNameRequired        = errors.New("name is required")
+	ErrInvalidPrice        = errors.New("ticket price must be positive")
+	ErrInvalidTicketLimit  = errors.New("tickets per user must be positive")
+	ErrPrizeRequired       = errors.New("prize item or amount is required")
+	ErrInvalidPrizeAmount  = errors.New("prize amount can not be negative")
+	ErrInvalidDrawTime     = errors.New("draw time must be in the future")
+	ErrInvalidCount        = errors.New("ticket count must be positive")
+	ErrTooManyTickets      = errors.New("too many tickets at once")
+	ErrInvalidStatus       = errors.New("invalid status")
+	ErrInvalidOffset       = errors.New("offset can not be negative")
+	ErrUserNotFound        = errors.New("user not found")
+	ErrItemNotFound        = errors.New("item not found")
+	ErrItemSoldOut         = errors.New("item is sold out")
+	ErrInsufficientFunds   = errors.New("insufficient funds")
+	ErrRaffleNotFound      = errors.New("raffle not found")
+	ErrRaffleNotOpen       = errors.New("raffle is not open")
+	ErrSalesClosed         = errors.New("ticket sales are closed")
+	ErrTicketLimit         = errors.New("you can not hold more tickets of this raffle")
+)
+
+type Raffles struct {
+	log                  *slog.Logger
+	storage              Storage
+	notificationsStorage NotificationsStorage
+	cfg                  config.RaffleConfig
+}
+
+type Storage interface {
+	Create(ctx context.Context, raffle model.Raffle) (model.Raffle, error)
+	BuyTickets(ctx context.Context, raffleID, userID, count int, now time.Time) (model.Raffle, error)
+	Draw(ctx context.Context, raffleID int, now time.Time) (model.Raffle, error)
+	Cancel(ctx context.Context, raffleID int) (model.Raffle, error)
+	GetByID(ctx context.Context, id, userID int) (model.Raffle, error)
+	GetAll(ctx context.Context, statusID, userID, limit, offset int) ([]model.Raffle, error)
+	GetDue(ctx context.Context, now time.Time) ([]model.Raffle, error)
+	GetTickets(ctx context.Context, raffleID int) ([]model.RaffleTicket, error)
+	GetParticipants(ctx context.Context, raffleID int) ([]int, error)
+}
+
+type NotificationsStorage interface {
+	Add(ctx context.Context, notification model.Notification) error
+}
+
+func New(
+	log *slog.Logger,
+	storage Storage,
+	notificationsStorage NotificationsStorage,
+	cfg config.RaffleConfig,
+) *Raffles {
+	return &Raffles{
+		log:                  log,
+		storage:              storage,
+		notificationsStorage: notificationsStorage,
+		cfg:                  cfg,
+	}
+}
+
+// Create opens a raffle. Its seed is generated here and kept secret until the draw, only the commitment
+// to it is shown while tickets are on sale.
+func (r *Raffles) Create(ctx context.Context, adminID int, raffle model.Raffle) (model.Raffle, error) {
+	op := "raffles.Create"
+
+	log := r.log.With(slog.String("op", op), slog.Int("adminID", adminID))
+
+	raffle.CreatedBy = adminID
+	raffle.Name = strings.TrimSpace(raffle.Name)
+	raffle.Description = strings.TrimSpace(raffle.Description)
+
+	if err := validate(raffle, time.Now()); err != nil {
+		log.Error("invalid raffle", slog.String("error", err.Error()))
+		return model.Raffle{}, err
+	}
+
+	seed, err := raffles.NewSeed()
+	if err != nil {
+		log.Error("failed to generate seed", slog.String("error", err.Error()))
+		return model.Raffle{}, err
+	}
+
+	raffle.Seed = seed
+	raffle.Commitment = raffles.Commit(seed)
+
+	created, err := r.storage.Create(ctx, raffle)
+	if err != nil {
+		log.Error("failed to create raffle", slog.String("error", err.Error()))
+		return model.Raffle{}, mapError(err)
+	}
+
+	log.Info("raffle created", slog.Int("id", created.ID))
+
+	return hideSeed(created), nil
+}
+
+// BuyTickets sells count tickets of the raffle to the user.
+func (r *Raffles) BuyTickets(ctx context.Context, raffleID, userID, count int) (model.Raffle, error) {
+	op := "raffles.BuyTickets"
+
+	log := r.log.With(slog.String("op", op), slog.Int("raffleID", raffleID), slog.Int("userID", userID))
+
+	if count <= 0 {
+		return model.Raffle{}, ErrInvalidCount
+	}
+
+	if r.cfg.MaxTicketsBuy > 0 && count > r.cfg.MaxTicketsBuy {
+		return model.Raffle{}, ErrTooManyTickets
+	}
+
+	raffle, err := r.storage.BuyTickets(ctx, raffleID, userID, count, time.Now())
+	if err != nil {
+		log.Error("failed to buy tickets", slog.String("error", err.Error()))
+		return model.Raffle{}, mapError(err)
+	}
+
+	log.Info("tickets bought", slog.Int("count", count))
+
+	return hideSeed(raffle), nil
+}
+
+// Cancel calls the open raffle off and refunds the tickets to everyone who bought them.
+func (r *Raffles) Cancel(ctx context.Context, adminID, raffleID int) (model.Raffle, error) {
+	op := "raffles.Cancel"
+
+	log := r.log.With(slog.String("op", op), slog.Int("adminID", adminID), slog.Int("raffleID", raffleID))
+
+	raffle, err := r.storage.Cancel(ctx, raffleID)
+	if err != nil {
+		log.Error("failed to cancel raffle", slog.String("error", err.Error()))
+		return model.Raffle{}, mapError(err)
+	}
+
+	r.notifyParticipants(ctx, log, raffle, 0, fmt.Sprintf("The raffle %q was cancelled, your tickets were refunded", raffle.Name))
+
+	log.Info("raffle cancelled")
+
+	return raffle, nil
+}
+
+// Draw draws every raffle whose draw time has come and tells the holders of its tickets the result.
+func (r *Raffles) Draw(ctx context.Context) error {
+	op := "raffles.Draw"
+
+	log := r.log.With(slog.String("op", op))
+
+	now := time.Now()
+
+	due, err := r.storage.GetDue(ctx, now)
+	if err != nil {
+		log.Error("failed to get due raffles", slog.String("error", err.Error()))
+		return err
+	}
+
+	var errList []error
+
+	for _, raffle := range due {
+		drawn, err := r.storage.Draw(ctx, raffle.ID, now)
+		if err != nil {
+			if !errors.Is(err, errs.ErrRaffleNotOpen) {
+				log.Error("failed to draw raffle", slog.Int("raffleID", raffle.ID), slog.String("error", err.Error()))
+				errList = append(errList, err)
+			}
+			continue
+		}
+
+		if drawn.StatusID == rafflesstorage.CancelledStatusID {
+			log.Info("raffle cancelled without tickets", slog.Int("raffleID", raffle.ID))
+			continue
+		}
+
+		r.notify(ctx, log, drawn.WinnerID, fmt.Sprintf("Your ticket #%d won the raffle %q, you got %s", drawn.WinningNumber, drawn.Name, prize(drawn)))
+		r.notifyParticipants(ctx, log, drawn, drawn.WinnerID, fmt.Sprintf("The raffle %q was won by %s with the ticket #%d", drawn.Name, drawn.WinnerName, drawn.WinningNumber))
+
+		log.Info("raffle drawn", slog.Int("raffleID", raffle.ID), slog.Int("winnerID", drawn.WinnerID))
+	}
+
+	return errors.Join(errList...)
+}
+
+// Get returns the raffle with the number of tickets the user holds in it.
+func (r *Raffles) Get(ctx context.Context, raffleID, userID int) (model.Raffle, error) {
+	op := "raffles.Get"
+
+	log := r.log.With(slog.String("op", op), slog.Int("raffleID", raffleID), slog.Int("userID", userID))
+
+	raffle, err := r.storage.GetByID(ctx, raffleID, userID)
+	if err != nil {
+		log.Error("failed to get raffle", slog.String("error", err.Error()))
+		return model.Raffle{}, mapError(err)
+	}
+
+	return hideSeed(raffle), nil
+}
+
+// GetAll returns a page of the raffles of the status, zero statusID returns raffles of any status.
+func (r *Raffles) GetAll(ctx context.Context, statusID, userID, limit, offset int) ([]model.Raffle, error) {
+	op := "raffles.GetAll"
+
+	log := r.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	if statusID < 0 || statusID > rafflesstorage.CancelledStatusID {
+		return nil, ErrInvalidStatus
+	}
+
+	if offset < 0 {
+		return nil, ErrInvalidOffset
+	}
+
+	all, err := r.storage.GetAll(ctx, statusID, userID, pagination.Limit(limit), offset)
+	if err != nil {
+		log.Error("failed to get raffles", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	for i := range all {
+		all[i] = hideSeed(all[i])
+	}
+
+	return all, nil
+}
+
+// GetAllForAdmin returns a page of the raffles of the status for admins. Seeds of open raffles are hidden
+// from admins too, only the commitment is published until the draw.
+func (r *Raffles) GetAllForAdmin(ctx context.Context, adminID, statusID, limit, offset int) ([]model.Raffle, error) {
+	op := "raffles.GetAllForAdmin"
+
+	log := r.log.With(slog.String("op", op), slog.Int("adminID", adminID))
+
+	if statusID < 0 || statusID > rafflesstorage.CancelledStatusID {
+		return nil, ErrInvalidStatus
+	}
+
+	if offset < 0 {
+		return nil, ErrInvalidOffset
+	}
+
+	all, err := r.storage.GetAll(ctx, statusID, 0, pagination.Limit(limit), offset)
+	if err != nil {
+		log.Error("failed to get raffles", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	for i := range all {
+		all[i] = hideSeed(all[i])
+	}
+
+	return all, nil
+}
+
+// GetTickets returns the tickets sold for the raffle, so a drawn raffle can be checked by anyone.
+func (r *Raffles) GetTickets(ctx context.Context, raffleID int) ([]model.RaffleTicket, error) {
+	op := "raffles.GetTickets"
+
+	log := r.log.With(slog.String("op", op), slog.Int("raffleID", raffleID))
+
+	if _, err := r.storage.GetByID(ctx, raffleID, 0); err != nil {
+		log.Error("failed to get raffle", slog.String("error", err.Error()))
+		return nil, mapError(err)
+	}
+
+	tickets, err := r.storage.GetTickets(ctx, raffleID)
+	if err != nil {
+		log.Error("failed to get tickets", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return tickets, nil
+}
+
+func validate(raffle model.Raffle, now time.Time) error {
+	switch {
+	case raffle.Name == "":
+		return ErrNameRequired
+	case raffle.TicketPrice <= 0:
+		return ErrInvalidPrice
+	case raffle.MaxTicketsPerUser <= 0:
+		return ErrInvalidTicketLimit
+	case raffle.PrizeAmount < 0:
+		return ErrInvalidPrizeAmount
+	case raffle.ItemID == 0 && raffle.PrizeAmount == 0:
+		return ErrPrizeRequired
+	case !raffle.DrawAt.After(now):
+		return ErrInvalidDrawTime
+	}
+
+	return nil
+}
+
+// hideSeed clears the seed of the raffle that is not drawn yet, otherwise the winner could be known in advance.
+func hideSeed(raffle model.Raffle) model.Raffle {
+	if raffle.StatusID == rafflesstorage.OpenStatusID {
+		raffle.Seed = ""
+	}
+
+	return raffle
+}
+
+func prize(raffle model.Raffle) string {
+	switch {
+	case raffle.ItemName != "" && raffle.PrizeAmount > 0:
+		return fmt.Sprintf("%s and %.2f coins", raffle.ItemName, raffle.PrizeAmount)
+	case raffle.ItemName != "":
+		return raffle.ItemName
+	}
+
+	return fmt.Sprintf("%.2f coins", raffle.PrizeAmount)
+}
+
+// notifyParticipants tells every holder of the raffle's tickets but skipID the message.
+func (r *Raffles) notifyParticipants(ctx context.Context, log *slog.Logger, raffle model.Raffle, skipID int, message string) {
+	userIDs, err := r.storage.GetParticipants(ctx, raffle.ID)
+	if err != nil {
+		log.Error("failed to get participants", slog.Int("raffleID", raffle.ID), slog.String("error", err.Error()))
+		return
+	}
+
+	for _, userID := range userIDs {
+		if userID != skipID {
+			r.notify(ctx, log, userID, message)
+		}
+	}
+}
+
+// notify tells the user about the raffle, failures are only logged.
+func (r *Raffles) notify(ctx context.Context, log *slog.Logger, userID int, message string) {
+	err := r.notificationsStorage.Add(ctx, model.Notification{
+		UserID:  userID,
+		TypeID:  notificationsstorage.RaffleUpdateTypeID,
+		Message: message,
+	})
+	if err != nil {
+		log.Error("failed to notify about raffle", slog.Int("userID", userID), slog.String("error", err.Error()))
+	}
+}
+
+func mapError(err error) error {
+	switch {
+	case errors.Is(err, errs.ErrAdminNotFound):
+		return ErrNotEnoughPermission
+	case errors.Is(err, errs.ErrUserNotFound):
+		return ErrUserNotFound
+	case errors.Is(err, errs.ErrShopItemNotFound):
+		return ErrItemNotFound
+	case errors.Is(err, errs.ErrShopItemSoldOut):
+		return ErrItemSoldOut
+	case errors.Is(err, errs.ErrInsufficientFunds):
+		return ErrInsufficientFunds
+	case errors.Is(err, errs.ErrRaffleNotFound):
+		return ErrRaffleNotFound
+	case errors.Is(err, errs.ErrRaffleNotOpen):
+		return ErrRaffleNotOpen
+	case errors.Is(err, errs.ErrRaffleSalesClosed):
+		return ErrSalesClosed
+	case errors.Is(err, errs.ErrTicketLimit):
+		return ErrTicketLimit
+	}
+
+	return err
+}
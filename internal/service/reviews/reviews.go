@@ -0,0 +1,236 @@
+package reviews
+
+import (
+	"context"
+	"errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/config"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/pagination"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	taskstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/tasks"
+	"log/slog"
+	"time"
+)
+
+const (
+	SLAStatusOK      = "ok"
+	SLAStatusWarning = "warning"
+	SLAStatusOverdue = "overdue"
+)
+
+var (
+	ErrTaskNotFound        = errors.New("task not found")
+	ErrAdminNotFound       = errors.New("admin not found")
+	ErrDelegationNotFound  = errors.New("delegation not found")
+	ErrNotEnoughPermission = errors.New("not enough permission")
+	ErrInvalidPeriod       = errors.New("invalid delegation period")
+	ErrSelfDelegation      = errors.New("can not delegate to yourself")
+	ErrInvalidGroup        = errors.New("invalid group")
+)
+
+type Reviews struct {
+	log          *slog.Logger
+	storage      Storage
+	tasksStorage TasksStorage
+	cfg          config.ReviewConfig
+}
+
+type Storage interface {
+	AddTaskReviewer(ctx context.Context, taskID, adminID, assignedBy int) error
+	AddGroupReviewer(ctx context.Context, groupID, adminID, assignedBy int) error
+	AddDelegation(ctx context.Context, delegation model.ReviewDelegation) (int, error)
+	GetDelegations(ctx context.Context, adminID int) ([]model.ReviewDelegation, error)
+	RevokeDelegation(ctx context.Context, delegationID, fromAdminID int) error
+}
+
+type TasksStorage interface {
+	GetByID(ctx context.Context, taskID int) (model.Task, error)
+	GetReviewQueue(ctx context.Context, adminID int, limit int) ([]model.Task, error)
+}
+
+func New(log *slog.Logger, storage Storage, tasksStorage TasksStorage, cfg config.ReviewConfig) *Reviews {
+	return &Reviews{
+		log:          log,
+		storage:      storage,
+		tasksStorage: tasksStorage,
+		cfg:          cfg,
+	}
+}
+
+// GetQueue returns submissions the admin may accept together with their SLA aging.
+func (r *Reviews) GetQueue(ctx context.Context, adminID int, limit int) ([]model.ReviewQueueItem, error) {
+	op := "reviews.GetQueue"
+
+	log := r.log.With(slog.String("op", op), slog.Int("adminID", adminID))
+
+	log.Info("getting review queue")
+
+	tasks, err := r.tasksStorage.GetReviewQueue(ctx, adminID, pagination.Limit(limit))
+	if err != nil {
+		log.Error("failed to get review queue", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	now := time.Now()
+
+	items := make([]model.ReviewQueueItem, 0, len(tasks))
+	for _, task := range tasks {
+		age := time.Duration(0)
+		if !task.SubmittedAt.IsZero() {
+			age = now.Sub(task.SubmittedAt)
+		}
+
+		items = append(items, model.ReviewQueueItem{
+			Task:      task,
+			Age:       age,
+			SLAStatus: r.slaStatus(age),
+		})
+	}
+
+	log.Info("got review queue", slog.Int("count", len(items)))
+
+	return items, nil
+}
+
+// AssignTaskReviewer lets the task author share acceptance of the task with another admin.
+func (r *Reviews) AssignTaskReviewer(ctx context.Context, taskID, reviewerID, adminID int) error {
+	op := "reviews.AssignTaskReviewer"
+
+	log := r.log.With(slog.String("op", op), slog.Int("taskID", taskID), slog.Int("adminID", adminID))
+
+	task, err := r.tasksStorage.GetByID(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, errs.ErrTaskNotFound) {
+			return ErrTaskNotFound
+		}
+
+		log.Error("failed to get task", slog.String("error", err.Error()))
+		return err
+	}
+
+	if task.CreatedBy != adminID {
+		log.Error("only task author can assign reviewers")
+		return ErrNotEnoughPermission
+	}
+
+	if err := r.storage.AddTaskReviewer(ctx, taskID, reviewerID, adminID); err != nil {
+		if errors.Is(err, errs.ErrAdminNotFound) {
+			return ErrAdminNotFound
+		}
+
+		log.Error("failed to assign task reviewer", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("assigned task reviewer", slog.Int("reviewerID", reviewerID))
+
+	return nil
+}
+
+// AssignGroupReviewer lets another admin accept tasks of the whole group. A group has no author,
+// so the router lets only super admins assign its reviewers.
+func (r *Reviews) AssignGroupReviewer(ctx context.Context, groupID, reviewerID, adminID int) error {
+	op := "reviews.AssignGroupReviewer"
+
+	log := r.log.With(slog.String("op", op), slog.Int("groupID", groupID), slog.Int("adminID", adminID))
+
+	if groupID != taskstorage.AllGroupID && groupID != taskstorage.UserGroupID {
+		return ErrInvalidGroup
+	}
+
+	if err := r.storage.AddGroupReviewer(ctx, groupID, reviewerID, adminID); err != nil {
+		if errors.Is(err, errs.ErrAdminNotFound) {
+			return ErrAdminNotFound
+		}
+
+		log.Error("failed to assign group reviewer", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("assigned group reviewer", slog.Int("reviewerID", reviewerID))
+
+	return nil
+}
+
+// Delegate hands the admin's review rights over to another admin for the given period,
+// e.g. while the admin is on vacation.
+func (r *Reviews) Delegate(ctx context.Context, fromAdminID, toAdminID int, startsAt, endsAt time.Time) (int, error) {
+	op := "reviews.Delegate"
+
+	log := r.log.With(slog.String("op", op), slog.Int("fromAdminID", fromAdminID), slog.Int("toAdminID", toAdminID))
+
+	if fromAdminID == toAdminID {
+		return 0, ErrSelfDelegation
+	}
+
+	if startsAt.IsZero() {
+		startsAt = time.Now()
+	}
+
+	if !endsAt.After(startsAt) || endsAt.Before(time.Now()) {
+		return 0, ErrInvalidPeriod
+	}
+
+	id, err := r.storage.AddDelegation(ctx, model.ReviewDelegation{
+		FromAdminID: fromAdminID,
+		ToAdminID:   toAdminID,
+		StartsAt:    startsAt,
+		EndsAt:      endsAt,
+	})
+	if err != nil {
+		if errors.Is(err, errs.ErrAdminNotFound) {
+			return 0, ErrAdminNotFound
+		}
+
+		log.Error("failed to add delegation", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	log.Info("delegated review rights", slog.Int("delegationID", id))
+
+	return id, nil
+}
+
+func (r *Reviews) GetDelegations(ctx context.Context, adminID int) ([]model.ReviewDelegation, error) {
+	op := "reviews.GetDelegations"
+
+	log := r.log.With(slog.String("op", op), slog.Int("adminID", adminID))
+
+	delegations, err := r.storage.GetDelegations(ctx, adminID)
+	if err != nil {
+		log.Error("failed to get delegations", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return delegations, nil
+}
+
+func (r *Reviews) RevokeDelegation(ctx context.Context, delegationID, adminID int) error {
+	op := "reviews.RevokeDelegation"
+
+	log := r.log.With(slog.String("op", op), slog.Int("delegationID", delegationID), slog.Int("adminID", adminID))
+
+	if err := r.storage.RevokeDelegation(ctx, delegationID, adminID); err != nil {
+		if errors.Is(err, errs.ErrDelegationNotFound) {
+			return ErrDelegationNotFound
+		}
+
+		log.Error("failed to revoke delegation", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("revoked delegation")
+
+	return nil
+}
+
+func (r *Reviews) slaStatus(age time.Duration) string {
+	switch {
+	case r.cfg.SLAOverdue > 0 && age >= r.cfg.SLAOverdue:
+		return SLAStatusOverdue
+	case r.cfg.SLAWarning > 0 && age >= r.cfg.SLAWarning:
+		return SLAStatusWarning
+	default:
+		return SLAStatusOK
+	}
+}
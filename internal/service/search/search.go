@@ -0,0 +1,101 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/jwt"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/pagination"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	searchstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/search"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrEmptyQuery  = errors.New("empty query")
+	ErrUnknownType = errors.New("unknown type")
+)
+
+type Search struct {
+	log     *slog.Logger
+	storage Storage
+}
+
+type Storage interface {
+	SearchTasks(ctx context.Context, text string, userID int, limit int) ([]model.SearchResult, error)
+	SearchShopItems(ctx context.Context, text string, limit int) ([]model.SearchResult, error)
+	SearchUsers(ctx context.Context, text string, limit int) ([]model.SearchResult, error)
+}
+
+func New(log *slog.Logger, storage Storage) *Search {
+	return &Search{
+		log:     log,
+		storage: storage,
+	}
+}
+
+// Search looks for query text in every requested entity type and merges results by rank.
+// Admins search through all tasks, users only through tasks assigned to them or to everyone.
+func (s *Search) Search(ctx context.Context, callerID int, role string, query model.SearchQuery) ([]model.SearchResult, error) {
+	op := "search.Search"
+
+	log := s.log.With(slog.String("op", op), slog.Int("callerID", callerID), slog.String("role", role))
+
+	query.Text = strings.TrimSpace(query.Text)
+	if query.Text == "" {
+		return nil, ErrEmptyQuery
+	}
+
+	query.Limit = pagination.Limit(query.Limit)
+
+	types := query.Types
+	if len(types) == 0 {
+		types = []string{searchstorage.TaskType, searchstorage.ShopItemType, searchstorage.UserType}
+	}
+
+	taskOwnerID := callerID
+	if role == jwt.RoleAdmin {
+		taskOwnerID = 0
+	}
+
+	log.Info("searching", slog.String("text", query.Text), slog.Any("types", types))
+
+	var results []model.SearchResult
+
+	for _, entityType := range types {
+		var found []model.SearchResult
+		var err error
+
+		switch entityType {
+		case searchstorage.TaskType:
+			found, err = s.storage.SearchTasks(ctx, query.Text, taskOwnerID, query.Limit)
+		case searchstorage.ShopItemType:
+			found, err = s.storage.SearchShopItems(ctx, query.Text, query.Limit)
+		case searchstorage.UserType:
+			found, err = s.storage.SearchUsers(ctx, query.Text, query.Limit)
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrUnknownType, entityType)
+		}
+
+		if err != nil {
+			log.Error("failed to search", slog.String("type", entityType), slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		results = append(results, found...)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Rank > results[j].Rank
+	})
+
+	if len(results) > query.Limit {
+		results = results[:query.Limit]
+	}
+
+	log.Info("search finished", slog.Int("found", len(results)))
+
+	return results, nil
+}
@@ -0,0 +1,222 @@
+package seasons
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/k6mil6/hackathon-game-backend/internal/config"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/pagination"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	leaderboardsstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/leaderboards"
+	notificationsstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/notifications"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidName    = errors.New("season name can not be empty")
+	ErrInvalidPeriod  = errors.New("season must end after it starts")
+	ErrSeasonOverlap  = errors.New("season overlaps another season")
+	ErrSeasonNotFound = errors.New("season not found")
+	ErrInvalidOffset  = errors.New("offset can not be negative")
+)
+
+type Seasons struct {
+	log                  *slog.Logger
+	storage              Storage
+	leaderboardsStorage  LeaderboardsStorage
+	notificationsStorage NotificationsStorage
+	cfg                  config.SeasonConfig
+}
+
+type Storage interface {
+	Add(ctx context.Context, season model.Season) (int, error)
+	GetAll(ctx context.Context) ([]model.Season, error)
+	GetByID(ctx context.Context, id int) (model.Season, error)
+	GetDueForRollover(ctx context.Context, at time.Time) ([]model.Season, error)
+	Finalize(ctx context.Context, seasonID int, prizes []float64) ([]model.SeasonStanding, error)
+	GetStandings(ctx context.Context, seasonID, limit, offset int) ([]model.SeasonStanding, error)
+}
+
+type LeaderboardsStorage interface {
+	Get(ctx context.Context, filter model.LeaderboardFilter) ([]model.LeaderboardEntry, error)
+}
+
+type NotificationsStorage interface {
+	Add(ctx context.Context, notification model.Notification) error
+}
+
+func New(
+	log *slog.Logger,
+	storage Storage,
+	leaderboardsStorage LeaderboardsStorage,
+	notificationsStorage NotificationsStorage,
+	cfg config.SeasonConfig,
+) *Seasons {
+	return &Seasons{
+		log:                  log,
+		storage:              storage,
+		leaderboardsStorage:  leaderboardsStorage,
+		notificationsStorage: notificationsStorage,
+		cfg:                  cfg,
+	}
+}
+
+// Create adds a season, the router lets only super admins create them. Seasons may not overlap.
+func (s *Seasons) Create(ctx context.Context, superAdminID int, season model.Season) (int, error) {
+	op := "seasons.Create"
+
+	log := s.log.With(slog.String("op", op), slog.Int("superAdminID", superAdminID))
+
+	log.Info("creating season")
+
+	season.Name = strings.TrimSpace(season.Name)
+	if season.Name == "" {
+		return 0, ErrInvalidName
+	}
+
+	if !season.EndsAt.After(season.StartsAt) {
+		return 0, ErrInvalidPeriod
+	}
+
+	season.CreatedBy = superAdminID
+
+	id, err := s.storage.Add(ctx, season)
+	if err != nil {
+		log.Error("failed to add season", slog.String("error", err.Error()))
+		if errors.Is(err, errs.ErrSeasonOverlap) {
+			return 0, ErrSeasonOverlap
+		}
+		return 0, err
+	}
+
+	log.Info("created season", slog.Int("id", id))
+
+	return id, nil
+}
+
+// GetAll returns the history of seasons, latest first, including the running and upcoming ones.
+func (s *Seasons) GetAll(ctx context.Context) ([]model.Season, error) {
+	op := "seasons.GetAll"
+
+	log := s.log.With(slog.String("op", op))
+
+	seasons, err := s.storage.GetAll(ctx)
+	if err != nil {
+		log.Error("failed to get seasons", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return seasons, nil
+}
+
+// GetStandings returns the season with a page of its standings. Finalized seasons return the saved
+// final standings, a season that is still running returns the live leaderboard of its earnings
+// and an upcoming season has no standings yet.
+func (s *Seasons) GetStandings(ctx context.Context, seasonID, limit, offset int) (model.Season, []model.SeasonStanding, error) {
+	op := "seasons.GetStandings"
+
+	log := s.log.With(slog.String("op", op), slog.Int("seasonID", seasonID))
+
+	if offset < 0 {
+		log.Error("invalid offset", slog.Int("offset", offset))
+		return model.Season{}, nil, ErrInvalidOffset
+	}
+
+	season, err := s.storage.GetByID(ctx, seasonID)
+	if err != nil {
+		log.Error("failed to get season", slog.String("error", err.Error()))
+		if errors.Is(err, errs.ErrSeasonNotFound) {
+			return model.Season{}, nil, ErrSeasonNotFound
+		}
+		return model.Season{}, nil, err
+	}
+
+	limit = pagination.Limit(limit)
+
+	if !season.FinalizedAt.IsZero() {
+		standings, err := s.storage.GetStandings(ctx, seasonID, limit, offset)
+		if err != nil {
+			log.Error("failed to get standings", slog.String("error", err.Error()))
+			return model.Season{}, nil, err
+		}
+
+		return season, standings, nil
+	}
+
+	if season.StartsAt.After(time.Now()) {
+		return season, []model.SeasonStanding{}, nil
+	}
+
+	entries, err := s.leaderboardsStorage.Get(ctx, model.LeaderboardFilter{
+		Metric: leaderboardsstorage.MetricSeasonEarned,
+		Since:  season.StartsAt,
+		Until:  season.EndsAt,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		log.Error("failed to get leaderboard", slog.String("error", err.Error()))
+		return model.Season{}, nil, err
+	}
+
+	standings := make([]model.SeasonStanding, 0, len(entries))
+	for _, entry := range entries {
+		standings = append(standings, model.SeasonStanding{
+			SeasonID: season.ID,
+			Rank:     entry.Rank,
+			UserID:   entry.UserID,
+			Username: entry.Username,
+			ClassID:  entry.ClassID,
+			Score:    entry.Score,
+		})
+	}
+
+	return season, standings, nil
+}
+
+// Rollover finalizes the seasons that have ended: it saves their final standings, pays the configured
+// prizes to the top places and notifies the winners. A season that fails is retried on the next run.
+func (s *Seasons) Rollover(ctx context.Context) error {
+	op := "seasons.Rollover"
+
+	log := s.log.With(slog.String("op", op))
+
+	seasons, err := s.storage.GetDueForRollover(ctx, time.Now())
+	if err != nil {
+		log.Error("failed to get seasons", slog.String("error", err.Error()))
+		return err
+	}
+
+	var errList []error
+
+	for _, season := range seasons {
+		winners, err := s.storage.Finalize(ctx, season.ID, s.cfg.Prizes)
+		if err != nil {
+			if errors.Is(err, errs.ErrSeasonAlreadyFinalized) {
+				continue
+			}
+
+			log.Error("failed to finalize season", slog.Int("seasonID", season.ID), slog.String("error", err.Error()))
+			errList = append(errList, err)
+			continue
+		}
+
+		log.Info("season rolled over", slog.Int("seasonID", season.ID), slog.Int("winners", len(winners)))
+
+		for _, winner := range winners {
+			err := s.notificationsStorage.Add(ctx, model.Notification{
+				UserID:  winner.UserID,
+				TypeID:  notificationsstorage.SeasonPrizeTypeID,
+				Message: fmt.Sprintf("You took place %d in season %s and won %.2f coins", winner.Rank, season.Name, winner.Prize),
+			})
+			if err != nil {
+				log.Error("failed to notify about season prize", slog.Int("userID", winner.UserID), slog.String("error", err.Error()))
+			}
+		}
+	}
+
+	return errors.Join(errList...)
+}
@@ -0,0 +1,91 @@
+package shop
+
+import (
+	"context"
+	"errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	achievementsservice "github.com/k6mil6/hackathon-game-backend/internal/service/achievements"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"log/slog"
+)
+
+var (
+	ErrItemNotFound      = errors.New("item not found")
+	ErrItemSoldOut       = errors.New("item is sold out")
+	ErrLevelTooLow       = errors.New("your level is too low for this item")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+)
+
+type Shop struct {
+	log              *slog.Logger
+	itemsStorage     ItemsStorage
+	purchasesStorage PurchasesStorage
+	achievements     Achievements
+}
+
+type ItemsStorage interface {
+	GetAll(ctx context.Context) ([]model.ShopItem, error)
+}
+
+type PurchasesStorage interface {
+	Buy(ctx context.Context, userID, itemID int) (model.Purchase, error)
+}
+
+type Achievements interface {
+	Evaluate(ctx context.Context, userID int, event string) ([]model.UserAchievement, error)
+}
+
+func New(log *slog.Logger, itemsStorage ItemsStorage, purchasesStorage PurchasesStorage, achievements Achievements) *Shop {
+	return &Shop{
+		log:              log,
+		itemsStorage:     itemsStorage,
+		purchasesStorage: purchasesStorage,
+		achievements:     achievements,
+	}
+}
+
+// GetItems returns the items of the shop.
+func (s *Shop) GetItems(ctx context.Context) ([]model.ShopItem, error) {
+	op := "shop.GetItems"
+
+	log := s.log.With(slog.String("op", op))
+
+	items, err := s.itemsStorage.GetAll(ctx)
+	if err != nil {
+		log.Error("failed to get items", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Buy charges the user for the item and applies its effects.
+func (s *Shop) Buy(ctx context.Context, userID, itemID int) (model.Purchase, error) {
+	op := "shop.Buy"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID), slog.Int("itemID", itemID))
+
+	purchase, err := s.purchasesStorage.Buy(ctx, userID, itemID)
+	if err != nil {
+		log.Error("failed to buy item", slog.String("error", err.Error()))
+
+		switch {
+		case errors.Is(err, errs.ErrShopItemNotFound):
+			return model.Purchase{}, ErrItemNotFound
+		case errors.Is(err, errs.ErrShopItemSoldOut):
+			return model.Purchase{}, ErrItemSoldOut
+		case errors.Is(err, errs.ErrLevelTooLow):
+			return model.Purchase{}, ErrLevelTooLow
+		case errors.Is(err, errs.ErrInsufficientFunds):
+			return model.Purchase{}, ErrInsufficientFunds
+		default:
+			return model.Purchase{}, err
+		}
+	}
+
+	if _, err := s.achievements.Evaluate(ctx, userID, achievementsservice.EventPurchase); err != nil {
+		log.Error("failed to evaluate achievements", slog.String("error", err.Error()))
+	}
+
+	return purchase, nil
+}
@@ -0,0 +1,101 @@
+package streaks
+
+import (
+	"context"
+	"errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/config"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/streaks"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"log/slog"
+	"time"
+)
+
+var (
+	ErrAlreadyCheckedIn = errors.New("you have already checked in today")
+)
+
+type Streaks struct {
+	log      *slog.Logger
+	storage  Storage
+	location *time.Location
+	cfg      config.StreakConfig
+}
+
+type Storage interface {
+	Get(ctx context.Context, userID int) (model.Streak, error)
+	Touch(ctx context.Context, userID int, day time.Time) (model.Streak, error)
+	CheckIn(ctx context.Context, userID int, day time.Time, bonuses []float64) (model.CheckIn, model.Streak, error)
+	IsCheckedIn(ctx context.Context, userID int, day time.Time) (bool, error)
+}
+
+func New(log *slog.Logger, storage Storage, location *time.Location, cfg config.StreakConfig) *Streaks {
+	return &Streaks{
+		log:      log,
+		storage:  storage,
+		location: location,
+		cfg:      cfg,
+	}
+}
+
+// Record counts today as an active day of the user, e.g. when a task of the user is accepted.
+func (s *Streaks) Record(ctx context.Context, userID int) (model.Streak, error) {
+	op := "streaks.Record"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	streak, err := s.storage.Touch(ctx, userID, streaks.Day(time.Now(), s.location))
+	if err != nil {
+		log.Error("failed to record activity", slog.String("error", err.Error()))
+		return model.Streak{}, err
+	}
+
+	return streak, nil
+}
+
+// CheckIn checks the user in for today and pays the bonus for the day of the streak.
+func (s *Streaks) CheckIn(ctx context.Context, userID int) (model.CheckIn, model.Streak, error) {
+	op := "streaks.CheckIn"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	checkIn, streak, err := s.storage.CheckIn(ctx, userID, streaks.Day(time.Now(), s.location), s.cfg.Bonuses)
+	if err != nil {
+		log.Error("failed to check in", slog.String("error", err.Error()))
+		if errors.Is(err, errs.ErrAlreadyCheckedIn) {
+			return model.CheckIn{}, model.Streak{}, ErrAlreadyCheckedIn
+		}
+		return model.CheckIn{}, model.Streak{}, err
+	}
+
+	return checkIn, streak, nil
+}
+
+// Get returns the streak of the user as of today, whether the user has checked in today
+// and the bonus the next check-in pays.
+func (s *Streaks) Get(ctx context.Context, userID int) (model.Streak, bool, float64, error) {
+	op := "streaks.Get"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	today := streaks.Day(time.Now(), s.location)
+
+	streak, err := s.storage.Get(ctx, userID)
+	if err != nil {
+		log.Error("failed to get streak", slog.String("error", err.Error()))
+		return model.Streak{}, false, 0, err
+	}
+
+	checkedIn, err := s.storage.IsCheckedIn(ctx, userID, today)
+	if err != nil {
+		log.Error("failed to get check-in", slog.String("error", err.Error()))
+		return model.Streak{}, false, 0, err
+	}
+
+	var nextBonus float64
+	if !checkedIn {
+		nextBonus = streaks.Bonus(s.cfg.Bonuses, streaks.Advance(streak, today).Current)
+	}
+
+	return streaks.At(streak, today), checkedIn, nextBonus, nil
+}
@@ -3,83 +3,231 @@ package tasks
 import (
 	"context"
 	"errors"
+	"fmt"
+	"github.com/k6mil6/hackathon-game-backend/internal/config"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/pagination"
 	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	achievementsservice "github.com/k6mil6/hackathon-game-backend/internal/service/achievements"
+	perksservice "github.com/k6mil6/hackathon-game-backend/internal/service/perks"
+	approvalsstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/approvals"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	notificationsstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/notifications"
 	taskstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/tasks"
 	"log/slog"
+	"strconv"
+	"time"
 )
 
 var (
 	ErrNoTasks             = errors.New("no tasks")
 	ErrNotEnoughPermission = errors.New("not enough permission")
+	ErrInvalidFilter       = errors.New("invalid filter")
+	ErrTaskNotFound        = errors.New("task not found")
+	ErrTaskClosed          = errors.New("task is already completed or cancelled")
+	ErrUserRequired        = errors.New("user_id is required for the user group")
+	ErrInvalidGroup        = errors.New("invalid group")
+	ErrNothingToUpdate     = errors.New("nothing to update")
+	ErrApprovalRequired    = errors.New("approval of another admin is required")
+	ErrPendingApproval     = errors.New("task is waiting for approval")
+	ErrBudgetExceeded      = errors.New("budget exceeded")
+	ErrNoBudget            = errors.New("no budget allocated for this period")
+	ErrDeadlinePassed      = errors.New("task deadline has passed")
+	ErrLevelTooLow         = errors.New("level is too low for this task")
+	ErrTaskNotWaiting      = errors.New("task is not waiting for acceptance")
 )
 
 type Tasks struct {
-	log     *slog.Logger
-	storage Storage
+	log                  *slog.Logger
+	storage              Storage
+	notificationsStorage NotificationsStorage
+	approvalsStorage     ApprovalsStorage
+	budgetsStorage       BudgetsStorage
+	perks                Perks
+	levels               Levels
+	achievements         Achievements
+	streaks              Streaks
+	quests               Quests
+	duels                Duels
+	approvalThreshold    float64
+	enforceBudget        bool
 }
 
 type Storage interface {
-	GetAllUserTasks(ctx context.Context, userID int) ([]model.Task, error)
-	GetAllAdminTasks(ctx context.Context, adminID int) ([]model.Task, error)
+	GetAllUserTasks(ctx context.Context, userID int, othersInProgress bool, filter model.TaskFilter) ([]model.Task, error)
+	CountUserTasks(ctx context.Context, userID int, othersInProgress bool, filter model.TaskFilter) (int, error)
+	GetAllAdminTasks(ctx context.Context, adminID int, filter model.TaskFilter) ([]model.Task, error)
+	CountAdminTasks(ctx context.Context, adminID int, filter model.TaskFilter) (int, error)
 	Add(ctx context.Context, task model.Task) (int, error)
-	MarkAsCompleted(ctx context.Context, taskID int) error
+	Complete(ctx context.Context, taskID int, payout model.TaskPayout) error
 	MarkAsCancelled(ctx context.Context, taskID int) error
 	GetByID(ctx context.Context, taskID int) (model.Task, error)
 	MarkAsInProgress(ctx context.Context, taskID int) error
-	MarkAsWaitingForAcceptance(ctx context.Context, taskID int) error
+	MarkAsWaitingForAcceptance(ctx context.Context, taskID, userID int) error
+	Update(ctx context.Context, taskID int, update model.TaskUpdate) error
+	Reassign(ctx context.Context, taskID, forGroupID, userID int) error
+	SetUserDeadline(ctx context.Context, taskID, userID int, deadline time.Time) error
+	Cancel(ctx context.Context, taskID, adminID int, reason string) error
+	CanReview(ctx context.Context, taskID, adminID int) (bool, error)
 }
 
-func New(log *slog.Logger, storage Storage) *Tasks {
+type NotificationsStorage interface {
+	Add(ctx context.Context, notification model.Notification) error
+	AddForAllUsers(ctx context.Context, notification model.Notification) error
+}
+
+type ApprovalsStorage interface {
+	Request(ctx context.Context, approval model.Approval) (int, error)
+	CancelPending(ctx context.Context, taskID int) error
+}
+
+type BudgetsStorage interface {
+	Reserve(ctx context.Context, adminID, groupID int, amount float64, at time.Time) (int, error)
+	Adjust(ctx context.Context, budgetID int, delta float64) error
+}
+
+// Perks applies class perks at the hook points of the task lifecycle.
+type Perks interface {
+	For(ctx context.Context, userID int) (perksservice.Holder, error)
+	Reward(ctx context.Context, userID int, task model.Task) (float64, error)
+	Deadline(ctx context.Context, userID int, task model.Task) (time.Time, error)
+}
+
+// Levels tracks the XP of users, which gates tasks by level.
+type Levels interface {
+	Level(ctx context.Context, userID int) (int, error)
+	AwardForTask(ctx context.Context, task model.Task) (model.XPAward, error)
+}
+
+type Achievements interface {
+	Evaluate(ctx context.Context, userID int, event string) ([]model.UserAchievement, error)
+}
+
+type Streaks interface {
+	Record(ctx context.Context, userID int) (model.Streak, error)
+}
+
+type Quests interface {
+	Advance(ctx context.Context, taskID int) (model.QuestAdvance, error)
+}
+
+type Duels interface {
+	SettleTask(ctx context.Context, task model.Task) ([]model.Duel, error)
+}
+
+func New(
+	log *slog.Logger,
+	storage Storage,
+	notificationsStorage NotificationsStorage,
+	approvalsStorage ApprovalsStorage,
+	budgetsStorage BudgetsStorage,
+	perks Perks,
+	levels Levels,
+	achievements Achievements,
+	streaks Streaks,
+	quests Quests,
+	duels Duels,
+	approvalCfg config.ApprovalConfig,
+	budgetCfg config.BudgetConfig,
+) *Tasks {
 	return &Tasks{
-		log:     log,
-		storage: storage,
+		log:                  log,
+		storage:              storage,
+		notificationsStorage: notificationsStorage,
+		approvalsStorage:     approvalsStorage,
+		budgetsStorage:       budgetsStorage,
+		perks:                perks,
+		levels:               levels,
+		achievements:         achievements,
+		streaks:              streaks,
+		quests:               quests,
+		duels:                duels,
+		approvalThreshold:    approvalCfg.Threshold,
+		enforceBudget:        budgetCfg.Enforce,
 	}
 }
 
-func (t *Tasks) GetAllUserTasks(ctx context.Context, userID int) ([]model.Task, error) {
+// RequiresApproval reports whether a task with this reward needs a second admin to confirm it.
+func (t *Tasks) RequiresApproval(amount float64) bool {
+	return t.approvalThreshold > 0 && amount >= t.approvalThreshold
+}
+
+func (t *Tasks) GetAllUserTasks(ctx context.Context, userID int, filter model.TaskFilter) (model.TaskPage, error) {
 	op := "tasks.GetAllUserTasks"
 
 	log := t.log.With(slog.String("op", op))
 
 	log.Info("getting all tasks from storage")
 
-	tasks, err := t.storage.GetAllUserTasks(ctx, userID)
+	filter, err := normalizeFilter(filter)
+	if err != nil {
+		log.Error("invalid filter", slog.String("error", err.Error()))
+		return model.TaskPage{}, err
+	}
+
+	holder, err := t.perks.For(ctx, userID)
+	if err != nil {
+		log.Error("failed to get perks", slog.String("error", err.Error()))
+		return model.TaskPage{}, err
+	}
+
+	// racoons see the tasks other users are working on, so they can pick one to intercept
+	othersInProgress := holder.HasPerk(perksservice.RacoonInterceptName)
+
+	tasks, err := t.storage.GetAllUserTasks(ctx, userID, othersInProgress, withLookahead(filter))
 	if err != nil {
 		log.Error("failed to get all tasks", slog.String("error", err.Error()))
-		return nil, err
+		return model.TaskPage{}, err
+	}
+
+	visible := tasks[:0]
+	for _, task := range tasks {
+		if !holder.CanSee(task) {
+			continue
+		}
+
+		task.Deadline = holder.Deadline(task)
+		visible = append(visible, task)
 	}
 
-	if len(tasks) == 0 {
-		log.Info("no tasks found")
-		return nil, ErrNoTasks
+	total, err := t.storage.CountUserTasks(ctx, userID, othersInProgress, filter)
+	if err != nil {
+		log.Error("failed to count tasks", slog.String("error", err.Error()))
+		return model.TaskPage{}, err
 	}
 
 	log.Info("got all tasks from storage")
 
-	return tasks, nil
+	return newPage(visible, total, filter), nil
 }
 
-func (t *Tasks) GetAllAdminTasks(ctx context.Context, adminID int) ([]model.Task, error) {
+func (t *Tasks) GetAllAdminTasks(ctx context.Context, adminID int, filter model.TaskFilter) (model.TaskPage, error) {
 	op := "tasks.GetAllAdminTasks"
 
 	log := t.log.With(slog.String("op", op))
 
 	log.Info("getting all tasks from storage")
 
-	tasks, err := t.storage.GetAllAdminTasks(ctx, adminID)
+	filter, err := normalizeFilter(filter)
+	if err != nil {
+		log.Error("invalid filter", slog.String("error", err.Error()))
+		return model.TaskPage{}, err
+	}
+
+	tasks, err := t.storage.GetAllAdminTasks(ctx, adminID, withLookahead(filter))
 	if err != nil {
 		log.Error("failed to get all tasks", slog.String("error", err.Error()))
-		return nil, err
+		return model.TaskPage{}, err
 	}
 
-	if len(tasks) == 0 {
-		log.Info("no tasks found")
-		return nil, ErrNoTasks
+	total, err := t.storage.CountAdminTasks(ctx, adminID, filter)
+	if err != nil {
+		log.Error("failed to count tasks", slog.String("error", err.Error()))
+		return model.TaskPage{}, err
 	}
 
 	log.Info("got all tasks from storage")
 
-	return tasks, nil
+	return newPage(tasks, total, filter), nil
 }
 
 func (t *Tasks) GetByID(ctx context.Context, taskID int) (model.Task, error) {
@@ -100,6 +248,73 @@ func (t *Tasks) GetByID(ctx context.Context, taskID int) (model.Task, error) {
 	return task, nil
 }
 
+// GetUserTask returns the task as the user sees it, with the deadline of their class.
+func (t *Tasks) GetUserTask(ctx context.Context, taskID, userID int) (model.Task, error) {
+	op := "tasks.GetUserTask"
+
+	log := t.log.With(slog.String("op", op), slog.Int("taskID", taskID), slog.Int("userID", userID))
+
+	task, err := t.storage.GetByID(ctx, taskID)
+	if err != nil {
+		log.Error("failed to get task", slog.String("error", err.Error()))
+		if errors.Is(err, errs.ErrTaskNotFound) {
+			return model.Task{}, ErrTaskNotFound
+		}
+		return model.Task{}, err
+	}
+
+	holder, err := t.perks.For(ctx, userID)
+	if err != nil {
+		log.Error("failed to get perks", slog.String("error", err.Error()))
+		return model.Task{}, err
+	}
+
+	// hidden tasks are reported as missing, so their IDs cannot be probed
+	if !holder.CanSee(task) {
+		return model.Task{}, ErrTaskNotFound
+	}
+
+	task.Deadline = holder.Deadline(task)
+
+	return task, nil
+}
+
+// OnAccepted runs what follows the payout of an accepted task, however it was accepted: the assignee
+// gets XP, achievements and the streak are updated, quests advance and duels on the task are settled.
+// The reward is already paid, so failures are only logged, quests that failed to advance are picked up
+// by the quest reconcile job.
+func (t *Tasks) OnAccepted(ctx context.Context, task model.Task) {
+	op := "tasks.OnAccepted"
+
+	log := t.log.With(slog.String("op", op), slog.Int("taskID", task.ID), slog.Int("userID", task.UserID))
+
+	if _, err := t.levels.AwardForTask(ctx, task); err != nil {
+		log.Error("failed to award xp", slog.String("error", err.Error()))
+	}
+
+	if _, err := t.achievements.Evaluate(ctx, task.UserID, achievementsservice.EventTaskAccepted); err != nil {
+		log.Error("failed to evaluate achievements", slog.String("error", err.Error()))
+	}
+
+	if _, err := t.streaks.Record(ctx, task.UserID); err != nil {
+		log.Error("failed to record streak", slog.String("error", err.Error()))
+	}
+
+	if _, err := t.quests.Advance(ctx, task.ID); err != nil {
+		log.Error("failed to advance quest", slog.String("error", err.Error()))
+	}
+
+	if _, err := t.duels.SettleTask(ctx, task); err != nil {
+		log.Error("failed to settle duels", slog.String("error", err.Error()))
+	}
+}
+
+// Reward returns the coins the assignee gets for the accepted task, class perks included.
+// The budget of the task is charged the whole reward.
+func (t *Tasks) Reward(ctx context.Context, task model.Task) (float64, error) {
+	return t.perks.Reward(ctx, task.UserID, task)
+}
+
 func (t *Tasks) Add(ctx context.Context, task model.Task) (int, error) {
 	op := "tasks.Add"
 
@@ -107,12 +322,52 @@ func (t *Tasks) Add(ctx context.Context, task model.Task) (int, error) {
 
 	log.Info("adding task to storage")
 
+	approvalRequired := t.RequiresApproval(task.Amount)
+	if approvalRequired {
+		// the task stays hidden from users until another admin approves it
+		task.StatusID = taskstorage.CreationApprovalStatusID
+	}
+
+	budgetID, err := t.budgetsStorage.Reserve(ctx, task.CreatedBy, task.ForGroupID, task.Amount, time.Now())
+	if err != nil {
+		log.Error("failed to reserve budget", slog.String("error", err.Error()))
+		if errors.Is(err, errs.ErrBudgetExceeded) {
+			return 0, ErrBudgetExceeded
+		}
+		return 0, err
+	}
+
+	if budgetID == 0 && t.enforceBudget {
+		log.Error("no budget allocated")
+		return 0, ErrNoBudget
+	}
+
+	task.BudgetID = budgetID
+
 	taskID, err := t.storage.Add(ctx, task)
 	if err != nil {
 		log.Error("failed to add task", slog.String("error", err.Error()))
+		t.releaseBudget(ctx, log, task)
 		return 0, err
 	}
 
+	if approvalRequired {
+		if err := t.requestApproval(ctx, taskID, approvalsstorage.CreationActionID, task.Amount, task.CreatedBy); err != nil {
+			log.Error("failed to request creation approval", slog.String("error", err.Error()))
+			return 0, err
+		}
+	}
+
+	if task.UserID != 0 && !task.Deadline.IsZero() {
+		// the deadline is extended from the creation time, so it is fixed for the stored task
+		created, err := t.storage.GetByID(ctx, taskID)
+		if err != nil {
+			log.Error("failed to get added task", slog.String("error", err.Error()))
+		} else {
+			t.fixDeadline(ctx, log, &created)
+		}
+	}
+
 	log.Info("added task to storage")
 
 	return taskID, nil
@@ -136,6 +391,11 @@ func (t *Tasks) MarkAsInProgress(ctx context.Context, taskID, userID int) error
 		return ErrNotEnoughPermission
 	}
 
+	if err := t.checkLevel(ctx, userID, task); err != nil {
+		log.Error("cannot take the task", slog.String("error", err.Error()))
+		return err
+	}
+
 	err = t.storage.MarkAsInProgress(ctx, taskID)
 	if err != nil {
 		log.Error("failed to mark task as in progress", slog.String("error", err.Error()))
@@ -172,9 +432,28 @@ func (t *Tasks) MarkAsWaitingForAcceptance(ctx context.Context, taskID, userID i
 		return ErrNotEnoughPermission
 	}
 
-	err = t.storage.MarkAsWaitingForAcceptance(ctx, taskID)
+	if err := t.checkLevel(ctx, userID, task); err != nil {
+		log.Error("cannot submit the task", slog.String("error", err.Error()))
+		return err
+	}
+
+	deadline, err := t.perks.Deadline(ctx, userID, task)
+	if err != nil {
+		log.Error("failed to get deadline", slog.String("error", err.Error()))
+		return err
+	}
+
+	if !deadline.IsZero() && time.Now().After(deadline) {
+		log.Error("task deadline has passed", slog.Time("deadline", deadline))
+		return ErrDeadlinePassed
+	}
+
+	err = t.storage.MarkAsWaitingForAcceptance(ctx, taskID, userID)
 	if err != nil {
 		log.Error("failed to mark task as waiting for acceptance", slog.String("error", err.Error()))
+		if errors.Is(err, errs.ErrTaskNotInProgress) {
+			return ErrNotEnoughPermission
+		}
 		return err
 	}
 
@@ -196,7 +475,13 @@ func (t *Tasks) MarkAsCompleted(ctx context.Context, taskID, adminID int) (model
 		return model.Task{}, err
 	}
 
-	if task.CreatedBy != adminID {
+	canReview, err := t.storage.CanReview(ctx, taskID, adminID)
+	if err != nil {
+		log.Error("failed to check review permission", slog.String("error", err.Error()))
+		return model.Task{}, err
+	}
+
+	if !canReview {
 		log.Error("admin does not have permission to mark task as completed")
 		return model.Task{}, ErrNotEnoughPermission
 	}
@@ -206,13 +491,43 @@ func (t *Tasks) MarkAsCompleted(ctx context.Context, taskID, adminID int) (model
 		return model.Task{}, ErrNotEnoughPermission
 	}
 
-	err = t.storage.MarkAsCompleted(ctx, taskID)
+	if t.RequiresApproval(task.Amount) {
+		if err := t.requestApproval(ctx, taskID, approvalsstorage.AcceptanceActionID, task.Amount, adminID); err != nil {
+			log.Error("failed to request acceptance approval", slog.String("error", err.Error()))
+			return model.Task{}, err
+		}
+
+		log.Info("task acceptance is waiting for approval")
+
+		return task, ErrApprovalRequired
+	}
+
+	reward, err := t.Reward(ctx, task)
+	if err != nil {
+		log.Error("failed to calculate reward", slog.String("error", err.Error()))
+		return model.Task{}, err
+	}
+
+	// the reward is sent on behalf of the admin who accepts the task
+	err = t.storage.Complete(ctx, taskID, model.TaskPayout{
+		SenderID: adminID,
+		Reward:   reward,
+		Reserved: task.Amount,
+	})
 	if err != nil {
 		log.Error("failed to mark task as completed", slog.String("error", err.Error()))
+		switch {
+		case errors.Is(err, errs.ErrTaskNotWaiting):
+			return model.Task{}, ErrTaskNotWaiting
+		case errors.Is(err, errs.ErrBudgetExceeded):
+			return model.Task{}, ErrBudgetExceeded
+		}
 		return model.Task{}, err
 	}
 
-	log.Info("marked task as completed")
+	log.Info("marked task as completed", slog.Float64("reward", reward))
+
+	t.OnAccepted(ctx, task)
 
 	return task, nil
 }
@@ -235,13 +550,408 @@ func (t *Tasks) MarkAsCancelled(ctx context.Context, taskID, userID int) error {
 		return ErrNotEnoughPermission
 	}
 
+	if task.StatusID == taskstorage.CompletedStatusID || task.StatusID == taskstorage.CancelledStatusID {
+		log.Error("task is already closed")
+		return ErrTaskClosed
+	}
+
 	err = t.storage.MarkAsCancelled(ctx, taskID)
 	if err != nil {
 		log.Error("failed to mark task as cancelled", slog.String("error", err.Error()))
+		if errors.Is(err, errs.ErrTaskClosed) {
+			return ErrTaskClosed
+		}
 		return err
 	}
 
+	t.releaseBudget(ctx, log, task)
+
 	log.Info("marked task as cancelled")
 
 	return nil
 }
+
+func (t *Tasks) Update(ctx context.Context, taskID, adminID int, update model.TaskUpdate) (model.Task, error) {
+	op := "tasks.Update"
+
+	log := t.log.With(slog.String("op", op), slog.Int("taskID", taskID), slog.Int("adminID", adminID))
+
+	log.Info("updating task")
+
+	if update.Name == nil && update.Description == nil && update.Amount == nil && update.CategoryID == nil &&
+		update.XP == nil && update.MinLevel == nil {
+		return model.Task{}, ErrNothingToUpdate
+	}
+
+	task, err := t.getOwnOpenTask(ctx, taskID, adminID)
+	if err != nil {
+		log.Error("failed to get task", slog.String("error", err.Error()))
+		return model.Task{}, err
+	}
+
+	amountChanged := update.Amount != nil && *update.Amount != task.Amount
+
+	// an approval is given for a specific reward, so it must not change under the approver
+	if amountChanged && isPendingApproval(task) {
+		log.Error("task is waiting for approval")
+		return model.Task{}, ErrPendingApproval
+	}
+
+	var budgetDelta float64
+	if amountChanged && task.BudgetID != 0 {
+		budgetDelta = *update.Amount - task.Amount
+
+		if err := t.budgetsStorage.Adjust(ctx, task.BudgetID, budgetDelta); err != nil {
+			log.Error("failed to adjust budget", slog.String("error", err.Error()))
+			if errors.Is(err, errs.ErrBudgetExceeded) {
+				return model.Task{}, ErrBudgetExceeded
+			}
+			return model.Task{}, err
+		}
+	}
+
+	if err := t.storage.Update(ctx, taskID, update); err != nil {
+		log.Error("failed to update task", slog.String("error", err.Error()))
+		if budgetDelta != 0 {
+			if err := t.budgetsStorage.Adjust(ctx, task.BudgetID, -budgetDelta); err != nil {
+				log.Error("failed to revert budget", slog.String("error", err.Error()))
+			}
+		}
+		return model.Task{}, err
+	}
+
+	// raising the reward of an open task over the threshold unpublishes it until approved,
+	// submitted tasks are covered by the acceptance approval
+	if amountChanged && task.StatusID == taskstorage.InProgressStatusID &&
+		!t.RequiresApproval(task.Amount) && t.RequiresApproval(*update.Amount) {
+		if err := t.requestApproval(ctx, taskID, approvalsstorage.CreationActionID, *update.Amount, adminID); err != nil {
+			log.Error("failed to request creation approval", slog.String("error", err.Error()))
+			return model.Task{}, err
+		}
+	}
+
+	task, err = t.storage.GetByID(ctx, taskID)
+	if err != nil {
+		log.Error("failed to get updated task", slog.String("error", err.Error()))
+		return model.Task{}, err
+	}
+
+	t.notifyAssignees(ctx, log, task, notificationsstorage.TaskUpdatedTypeID,
+		fmt.Sprintf("Task \"%s\" was updated", task.Name))
+
+	log.Info("updated task")
+
+	return task, nil
+}
+
+// Reassign hands the task over to another user or group. Previous assignees are notified
+// that the task was taken away, new ones that it was given to them.
+func (t *Tasks) Reassign(ctx context.Context, taskID, adminID, forGroupID, userID int) (model.Task, error) {
+	op := "tasks.Reassign"
+
+	log := t.log.With(slog.String("op", op), slog.Int("taskID", taskID), slog.Int("adminID", adminID))
+
+	log.Info("reassigning task")
+
+	switch forGroupID {
+	case taskstorage.UserGroupID:
+		if userID == 0 {
+			return model.Task{}, ErrUserRequired
+		}
+	case taskstorage.AllGroupID:
+		// shared tasks are not bound to anybody
+		userID = 0
+	default:
+		return model.Task{}, ErrInvalidGroup
+	}
+
+	previous, err := t.getOwnOpenTask(ctx, taskID, adminID)
+	if err != nil {
+		log.Error("failed to get task", slog.String("error", err.Error()))
+		return model.Task{}, err
+	}
+
+	// reassigning reopens the task, which would skip the pending approval
+	if isPendingApproval(previous) {
+		log.Error("task is waiting for approval")
+		return model.Task{}, ErrPendingApproval
+	}
+
+	if err := t.storage.Reassign(ctx, taskID, forGroupID, userID); err != nil {
+		if errors.Is(err, errs.ErrTaskUserRequired) {
+			return model.Task{}, ErrUserRequired
+		}
+
+		log.Error("failed to reassign task", slog.String("error", err.Error()))
+		return model.Task{}, err
+	}
+
+	task, err := t.storage.GetByID(ctx, taskID)
+	if err != nil {
+		log.Error("failed to get reassigned task", slog.String("error", err.Error()))
+		return model.Task{}, err
+	}
+
+	t.fixDeadline(ctx, log, &task)
+
+	if previous.UserID != 0 && previous.UserID != task.UserID {
+		t.notifyAssignees(ctx, log, previous, notificationsstorage.TaskUnassignedTypeID,
+			fmt.Sprintf("Task \"%s\" was reassigned to someone else", task.Name))
+	}
+
+	if previous.UserID != task.UserID || previous.ForGroupID != task.ForGroupID {
+		t.notifyAssignees(ctx, log, task, notificationsstorage.TaskAssignedTypeID,
+			fmt.Sprintf("Task \"%s\" was assigned to you", task.Name))
+	}
+
+	log.Info("reassigned task")
+
+	return task, nil
+}
+
+func (t *Tasks) Cancel(ctx context.Context, taskID, adminID int, reason string) error {
+	op := "tasks.Cancel"
+
+	log := t.log.With(slog.String("op", op), slog.Int("taskID", taskID), slog.Int("adminID", adminID))
+
+	log.Info("cancelling task")
+
+	task, err := t.getOwnOpenTask(ctx, taskID, adminID)
+	if err != nil {
+		log.Error("failed to get task", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := t.storage.Cancel(ctx, taskID, adminID, reason); err != nil {
+		log.Error("failed to cancel task", slog.String("error", err.Error()))
+		if errors.Is(err, errs.ErrTaskClosed) {
+			return ErrTaskClosed
+		}
+		return err
+	}
+
+	if isPendingApproval(task) {
+		if err := t.approvalsStorage.CancelPending(ctx, taskID); err != nil {
+			log.Error("failed to cancel pending approval", slog.String("error", err.Error()))
+		}
+	}
+
+	t.releaseBudget(ctx, log, task)
+
+	t.notifyAssignees(ctx, log, task, notificationsstorage.TaskCancelledTypeID,
+		fmt.Sprintf("Task \"%s\" was cancelled: %s", task.Name, reason))
+
+	log.Info("cancelled task")
+
+	return nil
+}
+
+// getOwnOpenTask returns the task if it was created by the admin and can still be changed.
+func (t *Tasks) getOwnOpenTask(ctx context.Context, taskID, adminID int) (model.Task, error) {
+	task, err := t.storage.GetByID(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, errs.ErrTaskNotFound) {
+			return model.Task{}, ErrTaskNotFound
+		}
+		return model.Task{}, err
+	}
+
+	if task.CreatedBy != adminID {
+		return model.Task{}, ErrNotEnoughPermission
+	}
+
+	if task.StatusID == taskstorage.CompletedStatusID || task.StatusID == taskstorage.CancelledStatusID {
+		return model.Task{}, ErrTaskClosed
+	}
+
+	return task, nil
+}
+
+// fixDeadline fixes the deadline of the task for its assignee with the perks they have when the task
+// becomes theirs, later changes of their companion do not move it. Until it is fixed the deadline
+// follows the current perks of the assignee, so a failure is only logged.
+func (t *Tasks) fixDeadline(ctx context.Context, log *slog.Logger, task *model.Task) {
+	if task.UserID == 0 || task.Deadline.IsZero() {
+		return
+	}
+
+	deadline, err := t.perks.Deadline(ctx, task.UserID, *task)
+	if err != nil {
+		log.Error("failed to get deadline", slog.String("error", err.Error()))
+		return
+	}
+
+	if err := t.storage.SetUserDeadline(ctx, task.ID, task.UserID, deadline); err != nil {
+		log.Error("failed to fix deadline", slog.String("error", err.Error()))
+		return
+	}
+
+	task.UserDeadline = deadline
+}
+
+func (t *Tasks) requestApproval(ctx context.Context, taskID, actionID int, amount float64, adminID int) error {
+	_, err := t.approvalsStorage.Request(ctx, model.Approval{
+		TaskID:      taskID,
+		ActionID:    actionID,
+		Amount:      amount,
+		RequestedBy: adminID,
+	})
+	if errors.Is(err, errs.ErrApprovalPending) {
+		return ErrPendingApproval
+	}
+
+	return err
+}
+
+// releaseBudget returns coins reserved for a task that will not be paid out.
+func (t *Tasks) releaseBudget(ctx context.Context, log *slog.Logger, task model.Task) {
+	if task.BudgetID == 0 {
+		return
+	}
+
+	if err := t.budgetsStorage.Adjust(ctx, task.BudgetID, -task.Amount); err != nil {
+		log.Error("failed to release budget", slog.String("error", err.Error()))
+	}
+}
+
+func isPendingApproval(task model.Task) bool {
+	return task.StatusID == taskstorage.CreationApprovalStatusID || task.StatusID == taskstorage.AcceptanceApprovalStatusID
+}
+
+// notifyAssignees is best effort, a failed notification must not roll back the change itself.
+func (t *Tasks) notifyAssignees(ctx context.Context, log *slog.Logger, task model.Task, typeID int, message string) {
+	notification := model.Notification{
+		UserID:  task.UserID,
+		TypeID:  typeID,
+		Message: message,
+		TaskID:  task.ID,
+	}
+
+	var err error
+	if task.ForGroupID == taskstorage.AllGroupID {
+		err = t.notificationsStorage.AddForAllUsers(ctx, notification)
+	} else if task.UserID != 0 {
+		err = t.notificationsStorage.Add(ctx, notification)
+	}
+
+	if err != nil {
+		log.Error("failed to notify assignees", slog.String("error", err.Error()))
+	}
+}
+
+func normalizeFilter(filter model.TaskFilter) (model.TaskFilter, error) {
+	filter.Limit = pagination.Limit(filter.Limit)
+
+	if filter.Sort == "" {
+		filter.Sort = taskstorage.SortByCreatedAt
+	}
+
+	if !taskstorage.IsSortable(filter.Sort) {
+		return model.TaskFilter{}, fmt.Errorf("%w: unknown sort %q", ErrInvalidFilter, filter.Sort)
+	}
+
+	switch filter.Order {
+	case "":
+		filter.Order = taskstorage.OrderDesc
+	case taskstorage.OrderAsc, taskstorage.OrderDesc:
+	default:
+		return model.TaskFilter{}, fmt.Errorf("%w: unknown order %q", ErrInvalidFilter, filter.Order)
+	}
+
+	if filter.MaxAmount != 0 && filter.MinAmount > filter.MaxAmount {
+		return model.TaskFilter{}, fmt.Errorf("%w: min_amount is greater than max_amount", ErrInvalidFilter)
+	}
+
+	if !filter.CreatedTo.IsZero() && filter.CreatedFrom.After(filter.CreatedTo) {
+		return model.TaskFilter{}, fmt.Errorf("%w: created_from is after created_to", ErrInvalidFilter)
+	}
+
+	if filter.Cursor != "" {
+		cursor, err := pagination.Decode(filter.Cursor)
+		if err != nil {
+			return model.TaskFilter{}, fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+		}
+
+		if cursor.Sort != filter.Sort || cursor.Order != filter.Order {
+			return model.TaskFilter{}, fmt.Errorf("%w: cursor is for another sort or order", ErrInvalidFilter)
+		}
+
+		if !isSortValue(cursor.Value, filter.Sort) {
+			return model.TaskFilter{}, fmt.Errorf("%w: %w", ErrInvalidFilter, pagination.ErrInvalidCursor)
+		}
+
+		filter.AfterValue = cursor.Value
+		filter.AfterID = cursor.ID
+	}
+
+	return filter, nil
+}
+
+// withLookahead asks storage for one extra row so we know whether there is a next page.
+func withLookahead(filter model.TaskFilter) model.TaskFilter {
+	filter.Limit++
+	return filter
+}
+
+func newPage(tasks []model.Task, total int, filter model.TaskFilter) model.TaskPage {
+	page := model.TaskPage{
+		Tasks: tasks,
+		Total: total,
+	}
+
+	if len(tasks) > filter.Limit {
+		page.Tasks = tasks[:filter.Limit]
+		last := page.Tasks[len(page.Tasks)-1]
+		page.NextCursor = pagination.Encode(pagination.Cursor{
+			Sort:  filter.Sort,
+			Order: filter.Order,
+			Value: sortValue(last, filter.Sort),
+			ID:    last.ID,
+		})
+	}
+
+	return page
+}
+
+func sortValue(task model.Task, sort string) string {
+	switch sort {
+	case taskstorage.SortByAmount:
+		return strconv.FormatFloat(task.Amount, 'f', -1, 64)
+	case taskstorage.SortByName:
+		return task.Name
+	default:
+		return task.CreatedAt.Format(time.RFC3339Nano)
+	}
+}
+
+// isSortValue reports whether the value of a cursor can be compared with the sort column,
+// so a forged cursor is rejected instead of failing the query.
+func isSortValue(value, sort string) bool {
+	switch sort {
+	case taskstorage.SortByAmount:
+		_, err := strconv.ParseFloat(value, 64)
+		return err == nil
+	case taskstorage.SortByName:
+		return true
+	default:
+		_, err := time.Parse(time.RFC3339Nano, value)
+		return err == nil
+	}
+}
+
+func (t *Tasks) checkLevel(ctx context.Context, userID int, task model.Task) error {
+	if task.MinLevel <= 1 {
+		return nil
+	}
+
+	level, err := t.levels.Level(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if level < task.MinLevel {
+		return ErrLevelTooLow
+	}
+
+	return nil
+}
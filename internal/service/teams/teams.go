@@ -0,0 +1,597 @@
+package teams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/k6mil6/hackathon-game-backend/internal/config"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/pagination"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	notificationsstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/notifications"
+	teamsstorage "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/teams"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidName            = errors.New("team name can not be empty")
+	ErrNameTooLong            = errors.New("team name is too long")
+	ErrNameTaken              = errors.New("team name is already taken")
+	ErrTeamNotFound           = errors.New("team not found")
+	ErrTeamFull               = errors.New("team is full")
+	ErrAlreadyInTeam          = errors.New("user is already in a team")
+	ErrNotInTeam              = errors.New("you are not in a team")
+	ErrNotMember              = errors.New("user is not a member of your team")
+	ErrNotLeader              = errors.New("only the team leader can do this")
+	ErrLastMember             = errors.New("the last member can not leave the team")
+	ErrUserNotFound           = errors.New("user not found")
+	ErrInvitationNotFound     = errors.New("invitation not found")
+	ErrInvitationPending      = errors.New("user already has a pending invitation to your team")
+	ErrInvalidAmount          = errors.New("amount must be positive")
+	ErrInsufficientFunds      = errors.New("insufficient funds")
+	ErrWalletInsufficient     = errors.New("not enough coins in the team wallet")
+	ErrInvalidRequiredMembers = errors.New("required members must be between 1 and the team size limit")
+	ErrTaskNotFound           = errors.New("team task not found")
+	ErrTaskAlreadySubmitted   = errors.New("you have already submitted this task")
+	ErrTaskAlreadyCompleted   = errors.New("your team has already completed this task")
+	ErrCompletionNotFound     = errors.New("team task completion not found")
+	ErrCompletionAccepted     = errors.New("team task completion was already accepted")
+	ErrUnknownMetric          = errors.New("unknown metric, use one of wallet, tasks, earned")
+	ErrInvalidOffset          = errors.New("offset can not be negative")
+	ErrAmountNeedsApproval    = errors.New("amount needs a second admin's approval, which team tasks can not get")
+	ErrNoBudget               = errors.New("no budget allocated for this period")
+	ErrBudgetExceeded         = errors.New("budget exceeded")
+)
+
+type Teams struct {
+	log                  *slog.Logger
+	storage              Storage
+	notificationsStorage NotificationsStorage
+	budgetsStorage       BudgetsStorage
+	cfg                  config.TeamConfig
+	approvalThreshold    float64
+	enforceBudget        bool
+}
+
+type Storage interface {
+	Create(ctx context.Context, name string, leaderID int) (int, error)
+	GetByID(ctx context.Context, id int) (model.Team, error)
+	GetByMember(ctx context.Context, userID int) (model.Team, error)
+	GetMembers(ctx context.Context, teamID int) ([]model.TeamMember, error)
+	Invite(ctx context.Context, leaderID, userID int) (model.TeamInvitation, error)
+	GetInvitations(ctx context.Context, userID int) ([]model.TeamInvitation, error)
+	Accept(ctx context.Context, invitationID, userID, maxMembers int) (int, error)
+	Decline(ctx context.Context, invitationID, userID int) error
+	Leave(ctx context.Context, userID int) error
+	Contribute(ctx context.Context, userID int, amount float64) (model.TeamWalletEntry, error)
+	Payout(ctx context.Context, leaderID, memberID int, amount float64) (model.TeamWalletEntry, error)
+	GetWallet(ctx context.Context, teamID, limit, offset int) ([]model.TeamWalletEntry, error)
+	AddTask(ctx context.Context, task model.TeamTask) (int, error)
+	GetTasks(ctx context.Context, teamID, userID int) ([]model.TeamTaskProgress, error)
+	Submit(ctx context.Context, taskID, userID int) (model.TeamTaskProgress, error)
+	GetCompletions(ctx context.Context, limit int) ([]model.TeamTaskCompletion, error)
+	AcceptCompletion(ctx context.Context, completionID, adminID int) (model.TeamTaskCompletion, error)
+	GetLeaderboard(ctx context.Context, metric string, limit, offset int) ([]model.TeamLeaderboardEntry, error)
+}
+
+type NotificationsStorage interface {
+	Add(ctx context.Context, notification model.Notification) error
+}
+
+type BudgetsStorage interface {
+	Reserve(ctx context.Context, adminID, groupID int, amount float64, at time.Time) (int, error)
+	Adjust(ctx context.Context, budgetID int, delta float64) error
+}
+
+func New(
+	log *slog.Logger,
+	storage Storage,
+	notificationsStorage NotificationsStorage,
+	budgetsStorage BudgetsStorage,
+	cfg config.TeamConfig,
+	approvalCfg config.ApprovalConfig,
+	budgetCfg config.BudgetConfig,
+) *Teams {
+	return &Teams{
+		log:                  log,
+		storage:              storage,
+		notificationsStorage: notificationsStorage,
+		budgetsStorage:       budgetsStorage,
+		cfg:                  cfg,
+		approvalThreshold:    approvalCfg.Threshold,
+		enforceBudget:        budgetCfg.Enforce,
+	}
+}
+
+// Create adds a team led by the user. A user can be in one team at a time.
+func (t *Teams) Create(ctx context.Context, userID int, name string) (model.Team, error) {
+	op := "teams.Create"
+
+	log := t.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	name = strings.TrimSpace(name)
+
+	switch {
+	case name == "":
+		return model.Team{}, ErrInvalidName
+	case utf8.RuneCountInString(name) > t.cfg.MaxNameLength:
+		return model.Team{}, ErrNameTooLong
+	}
+
+	id, err := t.storage.Create(ctx, name, userID)
+	if err != nil {
+		log.Error("failed to create team", slog.String("error", err.Error()))
+
+		switch {
+		case errors.Is(err, errs.ErrTeamNameTaken):
+			return model.Team{}, ErrNameTaken
+		case errors.Is(err, errs.ErrAlreadyInTeam):
+			return model.Team{}, ErrAlreadyInTeam
+		default:
+			return model.Team{}, err
+		}
+	}
+
+	team, err := t.storage.GetByID(ctx, id)
+	if err != nil {
+		log.Error("failed to get team", slog.String("error", err.Error()))
+		return model.Team{}, err
+	}
+
+	return team, nil
+}
+
+// GetMine returns the team of the user with its members.
+func (t *Teams) GetMine(ctx context.Context, userID int) (model.Team, []model.TeamMember, error) {
+	op := "teams.GetMine"
+
+	log := t.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	team, err := t.storage.GetByMember(ctx, userID)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotTeamMember) {
+			return model.Team{}, nil, ErrNotInTeam
+		}
+
+		log.Error("failed to get team", slog.String("error", err.Error()))
+		return model.Team{}, nil, err
+	}
+
+	return t.withMembers(ctx, log, team)
+}
+
+// Get returns the team with its members.
+func (t *Teams) Get(ctx context.Context, teamID int) (model.Team, []model.TeamMember, error) {
+	op := "teams.Get"
+
+	log := t.log.With(slog.String("op", op), slog.Int("teamID", teamID))
+
+	team, err := t.storage.GetByID(ctx, teamID)
+	if err != nil {
+		if errors.Is(err, errs.ErrTeamNotFound) {
+			return model.Team{}, nil, ErrTeamNotFound
+		}
+
+		log.Error("failed to get team", slog.String("error", err.Error()))
+		return model.Team{}, nil, err
+	}
+
+	return t.withMembers(ctx, log, team)
+}
+
+// Invite invites the user to the team of the leader and notifies them.
+func (t *Teams) Invite(ctx context.Context, leaderID, userID int) (model.TeamInvitation, error) {
+	op := "teams.Invite"
+
+	log := t.log.With(slog.String("op", op), slog.Int("leaderID", leaderID), slog.Int("userID", userID))
+
+	invitation, err := t.storage.Invite(ctx, leaderID, userID)
+	if err != nil {
+		log.Error("failed to invite to team", slog.String("error", err.Error()))
+
+		switch {
+		case errors.Is(err, errs.ErrNotTeamMember):
+			return model.TeamInvitation{}, ErrNotInTeam
+		case errors.Is(err, errs.ErrNotTeamLeader):
+			return model.TeamInvitation{}, ErrNotLeader
+		case errors.Is(err, errs.ErrAlreadyInTeam):
+			return model.TeamInvitation{}, ErrAlreadyInTeam
+		case errors.Is(err, errs.ErrInvitationPending):
+			return model.TeamInvitation{}, ErrInvitationPending
+		case errors.Is(err, errs.ErrUserNotFound):
+			return model.TeamInvitation{}, ErrUserNotFound
+		default:
+			return model.TeamInvitation{}, err
+		}
+	}
+
+	err = t.notificationsStorage.Add(ctx, model.Notification{
+		UserID:  userID,
+		TypeID:  notificationsstorage.TeamInvitationTypeID,
+		Message: fmt.Sprintf("You are invited to join team %s", invitation.TeamName),
+	})
+	if err != nil {
+		log.Error("failed to notify about invitation", slog.String("error", err.Error()))
+	}
+
+	return invitation, nil
+}
+
+// GetInvitations returns the pending invitations of the user.
+func (t *Teams) GetInvitations(ctx context.Context, userID int) ([]model.TeamInvitation, error) {
+	op := "teams.GetInvitations"
+
+	log := t.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	invitations, err := t.storage.GetInvitations(ctx, userID)
+	if err != nil {
+		log.Error("failed to get invitations", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+// AcceptInvitation joins the team of the invitation and returns it.
+func (t *Teams) AcceptInvitation(ctx context.Context, invitationID, userID int) (model.Team, error) {
+	op := "teams.AcceptInvitation"
+
+	log := t.log.With(slog.String("op", op), slog.Int("invitationID", invitationID), slog.Int("userID", userID))
+
+	teamID, err := t.storage.Accept(ctx, invitationID, userID, t.cfg.MaxMembers)
+	if err != nil {
+		log.Error("failed to accept invitation", slog.String("error", err.Error()))
+
+		switch {
+		case errors.Is(err, errs.ErrInvitationNotFound):
+			return model.Team{}, ErrInvitationNotFound
+		case errors.Is(err, errs.ErrTeamFull):
+			return model.Team{}, ErrTeamFull
+		case errors.Is(err, errs.ErrAlreadyInTeam):
+			return model.Team{}, ErrAlreadyInTeam
+		default:
+			return model.Team{}, err
+		}
+	}
+
+	team, err := t.storage.GetByID(ctx, teamID)
+	if err != nil {
+		log.Error("failed to get team", slog.String("error", err.Error()))
+		return model.Team{}, err
+	}
+
+	return team, nil
+}
+
+// DeclineInvitation declines the pending invitation of the user.
+func (t *Teams) DeclineInvitation(ctx context.Context, invitationID, userID int) error {
+	op := "teams.DeclineInvitation"
+
+	log := t.log.With(slog.String("op", op), slog.Int("invitationID", invitationID), slog.Int("userID", userID))
+
+	if err := t.storage.Decline(ctx, invitationID, userID); err != nil {
+		log.Error("failed to decline invitation", slog.String("error", err.Error()))
+		if errors.Is(err, errs.ErrInvitationNotFound) {
+			return ErrInvitationNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Leave takes the user out of their team. When the leader leaves, the member who joined first leads.
+func (t *Teams) Leave(ctx context.Context, userID int) error {
+	op := "teams.Leave"
+
+	log := t.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	if err := t.storage.Leave(ctx, userID); err != nil {
+		log.Error("failed to leave team", slog.String("error", err.Error()))
+
+		switch {
+		case errors.Is(err, errs.ErrNotTeamMember):
+			return ErrNotInTeam
+		case errors.Is(err, errs.ErrLastTeamMember):
+			return ErrLastMember
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Contribute moves coins from the balance of the user to the wallet of their team.
+func (t *Teams) Contribute(ctx context.Context, userID int, amount float64) (model.TeamWalletEntry, error) {
+	op := "teams.Contribute"
+
+	log := t.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	if amount <= 0 {
+		return model.TeamWalletEntry{}, ErrInvalidAmount
+	}
+
+	entry, err := t.storage.Contribute(ctx, userID, amount)
+	if err != nil {
+		log.Error("failed to contribute", slog.String("error", err.Error()))
+
+		switch {
+		case errors.Is(err, errs.ErrNotTeamMember):
+			return model.TeamWalletEntry{}, ErrNotInTeam
+		case errors.Is(err, errs.ErrInsufficientFunds):
+			return model.TeamWalletEntry{}, ErrInsufficientFunds
+		default:
+			return model.TeamWalletEntry{}, err
+		}
+	}
+
+	return entry, nil
+}
+
+// Payout moves coins from the team wallet to a member. Only the leader may pay out.
+func (t *Teams) Payout(ctx context.Context, leaderID, memberID int, amount float64) (model.TeamWalletEntry, error) {
+	op := "teams.Payout"
+
+	log := t.log.With(slog.String("op", op), slog.Int("leaderID", leaderID), slog.Int("memberID", memberID))
+
+	if amount <= 0 {
+		return model.TeamWalletEntry{}, ErrInvalidAmount
+	}
+
+	entry, err := t.storage.Payout(ctx, leaderID, memberID, amount)
+	if err != nil {
+		log.Error("failed to pay out", slog.String("error", err.Error()))
+
+		switch {
+		case errors.Is(err, errs.ErrNotTeamLeader):
+			return model.TeamWalletEntry{}, ErrNotLeader
+		case errors.Is(err, errs.ErrNotTeamMember):
+			// the leader is always a member, so it is the receiver who is not
+			return model.TeamWalletEntry{}, ErrNotMember
+		case errors.Is(err, errs.ErrTeamWalletInsufficient):
+			return model.TeamWalletEntry{}, ErrWalletInsufficient
+		default:
+			return model.TeamWalletEntry{}, err
+		}
+	}
+
+	return entry, nil
+}
+
+// GetWallet returns the team of the user with a page of its wallet ledger.
+func (t *Teams) GetWallet(ctx context.Context, userID, limit, offset int) (model.Team, []model.TeamWalletEntry, error) {
+	op := "teams.GetWallet"
+
+	log := t.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	if offset < 0 {
+		return model.Team{}, nil, ErrInvalidOffset
+	}
+
+	team, err := t.storage.GetByMember(ctx, userID)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotTeamMember) {
+			return model.Team{}, nil, ErrNotInTeam
+		}
+
+		log.Error("failed to get team", slog.String("error", err.Error()))
+		return model.Team{}, nil, err
+	}
+
+	entries, err := t.storage.GetWallet(ctx, team.ID, pagination.Limit(limit), offset)
+	if err != nil {
+		log.Error("failed to get wallet", slog.String("error", err.Error()))
+		return model.Team{}, nil, err
+	}
+
+	return team, entries, nil
+}
+
+// CreateTask adds a team task. A task with a team is only open to that team and its reward is reserved
+// from the budget of the admin, a task open to all teams may be paid many times, so it is charged as it is paid.
+// Team tasks have no approval step, so rewards from the approval threshold on are refused.
+func (t *Teams) CreateTask(ctx context.Context, adminID int, task model.TeamTask) (int, error) {
+	op := "teams.CreateTask"
+
+	log := t.log.With(slog.String("op", op), slog.Int("adminID", adminID))
+
+	task.Name = strings.TrimSpace(task.Name)
+
+	switch {
+	case task.Name == "":
+		return 0, ErrInvalidName
+	case task.Amount <= 0:
+		return 0, ErrInvalidAmount
+	case task.RequiredMembers <= 0 || (t.cfg.MaxMembers > 0 && task.RequiredMembers > t.cfg.MaxMembers):
+		return 0, ErrInvalidRequiredMembers
+	case t.approvalThreshold > 0 && task.Amount >= t.approvalThreshold:
+		return 0, ErrAmountNeedsApproval
+	}
+
+	task.CreatedBy = adminID
+
+	reserved := 0.0
+	if task.TeamID != 0 {
+		reserved = task.Amount
+	}
+
+	budgetID, err := t.budgetsStorage.Reserve(ctx, adminID, 0, reserved, time.Now())
+	if err != nil {
+		log.Error("failed to reserve budget", slog.String("error", err.Error()))
+		if errors.Is(err, errs.ErrBudgetExceeded) {
+			return 0, ErrBudgetExceeded
+		}
+		return 0, err
+	}
+
+	if budgetID == 0 && t.enforceBudget {
+		log.Error("no budget allocated")
+		return 0, ErrNoBudget
+	}
+
+	task.BudgetID = budgetID
+
+	id, err := t.storage.AddTask(ctx, task)
+	if err != nil {
+		log.Error("failed to add team task", slog.String("error", err.Error()))
+
+		if budgetID != 0 && reserved > 0 {
+			if err := t.budgetsStorage.Adjust(ctx, budgetID, -reserved); err != nil {
+				log.Error("failed to release budget", slog.String("error", err.Error()))
+			}
+		}
+
+		if errors.Is(err, errs.ErrTeamNotFound) {
+			return 0, ErrTeamNotFound
+		}
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// GetTasks returns the team tasks open to the team of the user with the progress of the team.
+func (t *Teams) GetTasks(ctx context.Context, userID int) ([]model.TeamTaskProgress, error) {
+	op := "teams.GetTasks"
+
+	log := t.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	team, err := t.storage.GetByMember(ctx, userID)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotTeamMember) {
+			return nil, ErrNotInTeam
+		}
+
+		log.Error("failed to get team", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	tasks, err := t.storage.GetTasks(ctx, team.ID, userID)
+	if err != nil {
+		log.Error("failed to get team tasks", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// SubmitTask submits the team task for the user. The task is completed for the team once
+// enough of its members have submitted it, and is then waiting for an admin to accept it.
+func (t *Teams) SubmitTask(ctx context.Context, taskID, userID int) (model.TeamTaskProgress, error) {
+	op := "teams.SubmitTask"
+
+	log := t.log.With(slog.String("op", op), slog.Int("taskID", taskID), slog.Int("userID", userID))
+
+	progress, err := t.storage.Submit(ctx, taskID, userID)
+	if err != nil {
+		log.Error("failed to submit team task", slog.String("error", err.Error()))
+
+		switch {
+		case errors.Is(err, errs.ErrNotTeamMember):
+			return model.TeamTaskProgress{}, ErrNotInTeam
+		case errors.Is(err, errs.ErrTeamTaskNotFound):
+			return model.TeamTaskProgress{}, ErrTaskNotFound
+		case errors.Is(err, errs.ErrTeamTaskAlreadySubmitted):
+			return model.TeamTaskProgress{}, ErrTaskAlreadySubmitted
+		case errors.Is(err, errs.ErrTeamTaskAlreadyCompleted):
+			return model.TeamTaskProgress{}, ErrTaskAlreadyCompleted
+		default:
+			return model.TeamTaskProgress{}, err
+		}
+	}
+
+	return progress, nil
+}
+
+// GetCompletions returns team task completions waiting for acceptance, oldest first.
+func (t *Teams) GetCompletions(ctx context.Context, adminID, limit int) ([]model.TeamTaskCompletion, error) {
+	op := "teams.GetCompletions"
+
+	log := t.log.With(slog.String("op", op), slog.Int("adminID", adminID))
+
+	completions, err := t.storage.GetCompletions(ctx, pagination.Limit(limit))
+	if err != nil {
+		log.Error("failed to get completions", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return completions, nil
+}
+
+// AcceptCompletion accepts the completion, splits the reward between the members who submitted
+// the task and notifies them.
+func (t *Teams) AcceptCompletion(ctx context.Context, completionID, adminID int) (model.TeamTaskCompletion, error) {
+	op := "teams.AcceptCompletion"
+
+	log := t.log.With(slog.String("op", op), slog.Int("completionID", completionID), slog.Int("adminID", adminID))
+
+	completion, err := t.storage.AcceptCompletion(ctx, completionID, adminID)
+	if err != nil {
+		log.Error("failed to accept completion", slog.String("error", err.Error()))
+
+		switch {
+		case errors.Is(err, errs.ErrTeamCompletionNotFound):
+			return model.TeamTaskCompletion{}, ErrCompletionNotFound
+		case errors.Is(err, errs.ErrTeamCompletionAlreadyAccepted):
+			return model.TeamTaskCompletion{}, ErrCompletionAccepted
+		case errors.Is(err, errs.ErrBudgetExceeded):
+			return model.TeamTaskCompletion{}, ErrBudgetExceeded
+		default:
+			return model.TeamTaskCompletion{}, err
+		}
+	}
+
+	for _, userID := range completion.SubmitterIDs {
+		err := t.notificationsStorage.Add(ctx, model.Notification{
+			UserID:  userID,
+			TypeID:  notificationsstorage.TeamTaskRewardTypeID,
+			Message: fmt.Sprintf("Team task %s was accepted, your share is %.2f coins", completion.TaskName, completion.Share),
+		})
+		if err != nil {
+			log.Error("failed to notify about team task reward", slog.Int("userID", userID), slog.String("error", err.Error()))
+		}
+	}
+
+	return completion, nil
+}
+
+// GetLeaderboard returns a page of teams ranked by the metric, the wallet by default.
+func (t *Teams) GetLeaderboard(ctx context.Context, metric string, limit, offset int) ([]model.TeamLeaderboardEntry, error) {
+	op := "teams.GetLeaderboard"
+
+	log := t.log.With(slog.String("op", op), slog.String("metric", metric))
+
+	if offset < 0 {
+		return nil, ErrInvalidOffset
+	}
+
+	if metric == "" {
+		metric = teamsstorage.MetricWallet
+	}
+
+	entries, err := t.storage.GetLeaderboard(ctx, metric, pagination.Limit(limit), offset)
+	if err != nil {
+		if errors.Is(err, errs.ErrUnknownMetric) {
+			return nil, ErrUnknownMetric
+		}
+
+		log.Error("failed to get team leaderboard", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (t *Teams) withMembers(ctx context.Context, log *slog.Logger, team model.Team) (model.Team, []model.TeamMember, error) {
+	members, err := t.storage.GetMembers(ctx, team.ID)
+	if err != nil {
+		log.Error("failed to get members", slog.String("error", err.Error()))
+		return model.Team{}, nil, err
+	}
+
+	return team, members, nil
+}
@@ -3,12 +3,14 @@ package transactions
 import (
 	"context"
 	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"github.com/k6mil6/hackathon-game-backend/internal/service/achievements"
 	"log/slog"
 )
 
 type Transactions struct {
-	log     *slog.Logger
-	storage Storage
+	log          *slog.Logger
+	storage      Storage
+	achievements Achievements
 }
 
 type Storage interface {
@@ -17,10 +19,15 @@ type Storage interface {
 	GetUserTransactions(ctx context.Context, userID int) ([]model.Transaction, error)
 }
 
-func New(log *slog.Logger, storage Storage) *Transactions {
+type Achievements interface {
+	Evaluate(ctx context.Context, userID int, event string) ([]model.UserAchievement, error)
+}
+
+func New(log *slog.Logger, storage Storage, achievements Achievements) *Transactions {
 	return &Transactions{
-		log:     log,
-		storage: storage,
+		log:          log,
+		storage:      storage,
+		achievements: achievements,
 	}
 }
 
@@ -39,6 +46,13 @@ func (t *Transactions) AddUserTransaction(ctx context.Context, transaction *mode
 
 	log.Info("added user transaction")
 
+	// the transfer moves both users on the leaderboard, so both are evaluated
+	for _, userID := range []int{transaction.SenderID, transaction.ReceiverID} {
+		if _, err := t.achievements.Evaluate(ctx, userID, achievements.EventTransfer); err != nil {
+			log.Error("failed to evaluate achievements", slog.String("error", err.Error()))
+		}
+	}
+
 	return nil
 }
 
@@ -2,10 +2,17 @@ package users
 
 import (
 	"context"
+	"errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/pagination"
 	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
 	"log/slog"
 )
 
+var (
+	ErrUserNotFound = errors.New("user not found")
+)
+
 type Users struct {
 	log *slog.Logger
 
@@ -16,11 +23,13 @@ type Users struct {
 
 type Storage interface {
 	GetAll(ctx context.Context) ([]model.User, error)
-	GetTopByBalance(ctx context.Context) ([]model.User, error)
+	GetTopByBalance(ctx context.Context, limit int) ([]model.User, error)
+	GetByID(ctx context.Context, id int) (model.User, error)
 }
 
 type BalanceStorage interface {
 	CreateBalance(ctx context.Context, userID int) error
+	GetBalance(ctx context.Context, userID int) (float64, error)
 }
 
 func New(log *slog.Logger, storage Storage, balanceStorage BalanceStorage) *Users {
@@ -72,7 +81,7 @@ func (u *Users) CreateBalance(ctx context.Context, userID int) error {
 	return nil
 }
 
-func (u *Users) GetTopByBalance(ctx context.Context) ([]model.User, error) {
+func (u *Users) GetTopByBalance(ctx context.Context, limit int) ([]model.User, error) {
 	op := "users.GetTopByBalance"
 
 	log := u.log.With(
@@ -81,7 +90,7 @@ func (u *Users) GetTopByBalance(ctx context.Context) ([]model.User, error) {
 
 	log.Info("request received")
 
-	users, err := u.storage.GetTopByBalance(ctx)
+	users, err := u.storage.GetTopByBalance(ctx, pagination.Limit(limit))
 	if err != nil {
 		log.Error("failed to get top users by balance", slog.String("error", err.Error()))
 		return nil, err
@@ -91,3 +100,30 @@ func (u *Users) GetTopByBalance(ctx context.Context) ([]model.User, error) {
 
 	return users, nil
 }
+
+// GetProfile returns the user with the current balance.
+func (u *Users) GetProfile(ctx context.Context, userID int) (model.User, error) {
+	op := "users.GetProfile"
+
+	log := u.log.With(
+		slog.String("op", op),
+		slog.Int("userID", userID),
+	)
+
+	user, err := u.storage.GetByID(ctx, userID)
+	if err != nil {
+		log.Error("failed to get user", slog.String("error", err.Error()))
+		if errors.Is(err, errs.ErrUserNotFound) {
+			return model.User{}, ErrUserNotFound
+		}
+		return model.User{}, err
+	}
+
+	user.Balance, err = u.balanceStorage.GetBalance(ctx, userID)
+	if err != nil {
+		log.Error("failed to get balance", slog.String("error", err.Error()))
+		return model.User{}, err
+	}
+
+	return user, nil
+}
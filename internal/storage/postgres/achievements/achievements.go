@@ -0,0 +1,307 @@
+package achievements
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/tasks"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/transactions"
+	"log/slog"
+	"time"
+)
+
+const (
+	PeriodAll   = "all"
+	PeriodMonth = "month"
+)
+
+type Storage struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
+	return &Storage{
+		db:  db,
+		log: log,
+	}
+}
+
+// GetAll returns the active achievements.
+func (s *Storage) GetAll(ctx context.Context) ([]model.Achievement, error) {
+	op := "achievements.GetAll"
+
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT ` + achievementColumns + ` FROM achievements
+			  WHERE is_active
+			  ORDER BY id`
+
+	var dbAchievements []dbAchievement
+	if err := conn.SelectContext(ctx, &dbAchievements, query); err != nil {
+		log.Error("failed to get achievements", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	achievements := make([]model.Achievement, 0, len(dbAchievements))
+	for _, achievement := range dbAchievements {
+		achievements = append(achievements, achievement.toModel())
+	}
+
+	return achievements, nil
+}
+
+// GetByUser returns the achievements awarded to the user, newest first.
+func (s *Storage) GetByUser(ctx context.Context, userID int) ([]model.UserAchievement, error) {
+	op := "achievements.GetByUser"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT ua.user_id, COALESCE(ua.transaction_id, 0) AS transaction_id, ua.created_at AS awarded_at,
+			  a.id, a.code, a.name, a.description, a.metric, a.threshold, a.period,
+			  COALESCE(a.business_type_id, 0) AS business_type_id, a.reward_coins, a.reward_xp
+			  FROM users_achievements ua
+			  JOIN achievements a ON a.id = ua.achievement_id
+			  WHERE ua.user_id = $1
+			  ORDER BY ua.created_at DESC, ua.id DESC`
+
+	var dbUserAchievements []dbUserAchievement
+	if err := conn.SelectContext(ctx, &dbUserAchievements, query, userID); err != nil {
+		log.Error("failed to get user achievements", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	userAchievements := make([]model.UserAchievement, 0, len(dbUserAchievements))
+	for _, userAchievement := range dbUserAchievements {
+		userAchievements = append(userAchievements, model.UserAchievement{
+			Achievement:   userAchievement.dbAchievement.toModel(),
+			UserID:        userAchievement.UserID,
+			TransactionID: userAchievement.TransactionID,
+			AwardedAt:     userAchievement.AwardedAt,
+		})
+	}
+
+	return userAchievements, nil
+}
+
+// Award gives the achievement to the user and pays its coin reward in one transaction.
+// An achievement is awarded only once, a repeated call returns ErrAchievementAlreadyAwarded.
+func (s *Storage) Award(ctx context.Context, userID int, achievement model.Achievement) (model.UserAchievement, error) {
+	op := "achievements.Award"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID), slog.Int("achievementID", achievement.ID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.UserAchievement{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.UserAchievement{}, err
+	}
+
+	userAchievement := model.UserAchievement{
+		Achievement: achievement,
+		UserID:      userID,
+	}
+
+	var id int64
+	err = tx.QueryRowxContext(ctx,
+		`INSERT INTO users_achievements (user_id, achievement_id) VALUES ($1, $2)
+		 ON CONFLICT (user_id, achievement_id) DO NOTHING
+		 RETURNING id, created_at`,
+		userID, achievement.ID,
+	).Scan(&id, &userAchievement.AwardedAt)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.UserAchievement{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.UserAchievement{}, errs.ErrAchievementAlreadyAwarded
+		}
+
+		log.Error("failed to add user achievement", slog.String("error", err.Error()))
+		return model.UserAchievement{}, err
+	}
+
+	if achievement.RewardCoins > 0 {
+		userAchievement.TransactionID, err = transactions.Credit(ctx, tx, userID, achievement.RewardCoins, transactions.RewardTypeID)
+		if err != nil {
+			log.Error("failed to pay reward", slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return model.UserAchievement{}, err
+			}
+			return model.UserAchievement{}, err
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE users_achievements SET transaction_id = $1 WHERE id = $2`, userAchievement.TransactionID, id)
+		if err != nil {
+			log.Error("failed to link reward transaction", slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return model.UserAchievement{}, err
+			}
+			return model.UserAchievement{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.UserAchievement{}, err
+	}
+
+	log.Info("awarded achievement", slog.String("code", achievement.Code))
+
+	return userAchievement, nil
+}
+
+// CountCompletedTasks returns how many tasks of the user were accepted since the time.
+func (s *Storage) CountCompletedTasks(ctx context.Context, userID int, since time.Time) (int, error) {
+	return s.count(ctx, "achievements.CountCompletedTasks",
+		`SELECT COUNT(*) FROM tasks WHERE user_id = $1 AND status_id = $2 AND updated_at >= $3`,
+		userID, tasks.CompletedStatusID, since,
+	)
+}
+
+// CountPurchases returns how many shop items the user bought since the time.
+func (s *Storage) CountPurchases(ctx context.Context, userID int, since time.Time) (int, error) {
+	return s.count(ctx, "achievements.CountPurchases",
+		`SELECT COUNT(*) FROM purchases WHERE user_id = $1 AND created_at >= $2`,
+		userID, since,
+	)
+}
+
+// CountTransfers returns how many completed transfers the user sent since the time.
+func (s *Storage) CountTransfers(ctx context.Context, userID int, since time.Time) (int, error) {
+	return s.count(ctx, "achievements.CountTransfers",
+		`SELECT COUNT(*) FROM transactions WHERE sender_id = $1 AND type_id = $2 AND status_id = $3 AND created_at >= $4`,
+		userID, transactions.TransferTypeID, transactions.CompletedStatusID, since,
+	)
+}
+
+// CountBusinesses returns how many businesses the user owns. Zero typeID counts businesses of any type.
+func (s *Storage) CountBusinesses(ctx context.Context, userID, typeID int) (int, error) {
+	return s.count(ctx, "achievements.CountBusinesses",
+		`SELECT COUNT(*) FROM businesses WHERE owner_id = $1 AND ($2 = 0 OR type_id = $2)`,
+		userID, typeID,
+	)
+}
+
+// BalanceRank returns the place of the user on the balance leaderboard, ties are broken by user ID.
+func (s *Storage) BalanceRank(ctx context.Context, userID int) (int, error) {
+	return s.count(ctx, "achievements.BalanceRank",
+		`SELECT COUNT(*) + 1 FROM balances b, (SELECT balance FROM balances WHERE user_id = $1) me
+		 WHERE b.balance > me.balance OR (b.balance = me.balance AND b.user_id < $1)`,
+		userID,
+	)
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+func (s *Storage) count(ctx context.Context, op, query string, args ...interface{}) (int, error) {
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var count int
+	if err := conn.GetContext(ctx, &count, query, args...); err != nil {
+		log.Error("failed to count", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	return count, nil
+}
+
+const achievementColumns = `id, code, name, description, metric, threshold, period,
+	COALESCE(business_type_id, 0) AS business_type_id, reward_coins, reward_xp`
+
+type dbAchievement struct {
+	ID             int     `db:"id"`
+	Code           string  `db:"code"`
+	Name           string  `db:"name"`
+	Description    string  `db:"description"`
+	Metric         string  `db:"metric"`
+	Threshold      int     `db:"threshold"`
+	Period         string  `db:"period"`
+	BusinessTypeID int     `db:"business_type_id"`
+	RewardCoins    float64 `db:"reward_coins"`
+	RewardXP       int     `db:"reward_xp"`
+}
+
+type dbUserAchievement struct {
+	dbAchievement
+	UserID        int       `db:"user_id"`
+	TransactionID int       `db:"transaction_id"`
+	AwardedAt     time.Time `db:"awarded_at"`
+}
+
+func (a dbAchievement) toModel() model.Achievement {
+	return model.Achievement{
+		ID:             a.ID,
+		Code:           a.Code,
+		Name:           a.Name,
+		Description:    a.Description,
+		Metric:         a.Metric,
+		Threshold:      a.Threshold,
+		Period:         a.Period,
+		BusinessTypeID: a.BusinessTypeID,
+		RewardCoins:    a.RewardCoins,
+		RewardXP:       a.RewardXP,
+	}
+}
@@ -25,7 +25,8 @@ func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
 }
 
 const (
-	AdminRoleID = 1
+	AdminRoleID      = 1
+	SuperAdminRoleID = 2
 )
 
 func (s *Storage) Save(ctx context.Context, admin *model.Admin) (int, error) {
@@ -143,6 +144,37 @@ func (s *Storage) GetByID(ctx context.Context, id int) (model.Admin, error) {
 	return model.Admin(admin), nil
 }
 
+// IsSuperAdmin reports whether the admin has the super admin role, unknown admins do not.
+func (s *Storage) IsSuperAdmin(ctx context.Context, id int) (bool, error) {
+	op := "admins.IsSuperAdmin"
+
+	log := s.log.With(slog.String("op", op), slog.Int("id", id))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return false, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT EXISTS (SELECT 1 FROM admins WHERE id = $1 AND role_id = $2)`
+
+	var isSuperAdmin bool
+	if err := conn.GetContext(ctx, &isSuperAdmin, query, id, SuperAdminRoleID); err != nil {
+		log.Error("failed to get admin role", slog.String("error", err.Error()))
+		return false, err
+	}
+
+	return isSuperAdmin, nil
+}
+
 func (s *Storage) UpdateEmail(ctx context.Context, id int, email string) error {
 	op := "admins.UpdateEmail"
 
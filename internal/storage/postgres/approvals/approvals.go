@@ -0,0 +1,435 @@
+package approvals
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/tasks"
+	"github.com/lib/pq"
+	"log/slog"
+	"time"
+)
+
+const (
+	CreationActionID   = 1
+	AcceptanceActionID = 2
+)
+
+const (
+	PendingStatusID   = 1
+	ApprovedStatusID  = 2
+	DeniedStatusID    = 3
+	CancelledStatusID = 4
+)
+
+type Storage struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
+	return &Storage{
+		db:  db,
+		log: log,
+	}
+}
+
+// Request records a pending approval and moves the task into the matching waiting status.
+func (s *Storage) Request(ctx context.Context, approval model.Approval) (int, error) {
+	op := "approvals.Request"
+
+	log := s.log.With(slog.String("op", op), slog.Int("taskID", approval.TaskID), slog.Int("actionID", approval.ActionID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	query := `INSERT INTO tasks_approvals (task_id, action_id, status_id, amount, requested_by)
+			  VALUES ($1, $2, $3, $4, $5)
+			  RETURNING id`
+
+	var id int
+	err = tx.QueryRowxContext(ctx, query,
+		approval.TaskID,
+		approval.ActionID,
+		PendingStatusID,
+		approval.Amount,
+		approval.RequestedBy,
+	).Scan(&id)
+	if err != nil {
+		log.Error("failed to add approval", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return 0, errs.ErrApprovalPending
+		}
+
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE tasks SET status_id = $1, updated_at = NOW() WHERE id = $2`, waitingStatusID(approval.ActionID), approval.TaskID)
+	if err != nil {
+		log.Error("failed to update task status", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	log.Info("requested approval", slog.Int("id", id))
+
+	return id, nil
+}
+
+func (s *Storage) GetByID(ctx context.Context, approvalID int) (model.Approval, error) {
+	op := "approvals.GetByID"
+
+	log := s.log.With(slog.String("op", op), slog.Int("approvalID", approvalID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Approval{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var approval dbApproval
+	if err := conn.GetContext(ctx, &approval, `SELECT `+approvalColumns+` FROM tasks_approvals WHERE id = $1`, approvalID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Approval{}, errs.ErrApprovalNotFound
+		}
+
+		log.Error("failed to get approval", slog.String("error", err.Error()))
+		return model.Approval{}, err
+	}
+
+	return approval.toModel(), nil
+}
+
+// GetAll returns approvals newest first. Zero statusID or taskID disables the corresponding filter.
+func (s *Storage) GetAll(ctx context.Context, statusID, taskID, limit int) ([]model.Approval, error) {
+	op := "approvals.GetAll"
+
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT ` + approvalColumns + `
+			  FROM tasks_approvals
+			  WHERE ($1 = 0 OR status_id = $1) AND ($2 = 0 OR task_id = $2)
+			  ORDER BY created_at DESC, id DESC
+			  LIMIT $3`
+
+	var dbApprovals []dbApproval
+	if err := conn.SelectContext(ctx, &dbApprovals, query, statusID, taskID, limit); err != nil {
+		log.Error("failed to get approvals", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	approvals := make([]model.Approval, 0, len(dbApprovals))
+	for _, approval := range dbApprovals {
+		approvals = append(approvals, approval.toModel())
+	}
+
+	return approvals, nil
+}
+
+// Approve marks a pending approval as approved by adminID and releases the task:
+// an approved creation opens the task, an approved acceptance completes it and pays the payout
+// in the same transaction.
+func (s *Storage) Approve(ctx context.Context, approvalID, adminID int, payout model.TaskPayout) (model.Approval, error) {
+	op := "approvals.Approve"
+
+	log := s.log.With(slog.String("op", op), slog.Int("approvalID", approvalID), slog.Int("adminID", adminID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Approval{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Approval{}, err
+	}
+
+	approval, err := decide(ctx, tx, approvalID, adminID, ApprovedStatusID, "")
+	if err != nil {
+		log.Error("failed to decide approval", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Approval{}, err
+		}
+		return model.Approval{}, err
+	}
+
+	if approval.ActionID == AcceptanceActionID {
+		err = tasks.Complete(ctx, tx, approval.TaskID, tasks.AcceptanceApprovalStatusID, payout)
+		if errors.Is(err, errs.ErrTaskNotWaiting) {
+			err = errs.ErrApprovalStale
+		}
+	} else {
+		var res sql.Result
+		res, err = tx.ExecContext(ctx,
+			`UPDATE tasks SET status_id = $1, updated_at = NOW() WHERE id = $2 AND status_id = $3`,
+			tasks.InProgressStatusID, approval.TaskID, tasks.CreationApprovalStatusID,
+		)
+		if err == nil {
+			err = checkUpdated(res)
+		}
+	}
+	if err != nil {
+		log.Error("failed to update task", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Approval{}, err
+		}
+		return model.Approval{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Approval{}, err
+	}
+
+	log.Info("approved")
+
+	return approval, nil
+}
+
+// Deny marks a pending approval as denied by adminID. A denied creation cancels the task with
+// the given reason, a denied acceptance sends the task back to the assignee.
+func (s *Storage) Deny(ctx context.Context, approvalID, adminID int, reason string) (model.Approval, error) {
+	op := "approvals.Deny"
+
+	log := s.log.With(slog.String("op", op), slog.Int("approvalID", approvalID), slog.Int("adminID", adminID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Approval{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Approval{}, err
+	}
+
+	approval, err := decide(ctx, tx, approvalID, adminID, DeniedStatusID, reason)
+	if err != nil {
+		log.Error("failed to decide approval", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Approval{}, err
+		}
+		return model.Approval{}, err
+	}
+
+	var res sql.Result
+	if approval.ActionID == AcceptanceActionID {
+		res, err = tx.ExecContext(ctx,
+			`UPDATE tasks SET status_id = $1, submitted_at = NULL, updated_at = NOW() WHERE id = $2 AND status_id = $3`,
+			tasks.InProgressStatusID, approval.TaskID, tasks.AcceptanceApprovalStatusID,
+		)
+	} else {
+		res, err = tx.ExecContext(ctx,
+			`UPDATE tasks SET status_id = $1, cancel_reason = $2, cancelled_by = $3, updated_at = NOW() WHERE id = $4 AND status_id = $5`,
+			tasks.CancelledStatusID, reason, adminID, approval.TaskID, tasks.CreationApprovalStatusID,
+		)
+	}
+	if err != nil {
+		log.Error("failed to update task status", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Approval{}, err
+		}
+		return model.Approval{}, err
+	}
+
+	if err := checkUpdated(res); err != nil {
+		log.Error("task is not waiting for approval", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Approval{}, err
+		}
+		return model.Approval{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Approval{}, err
+	}
+
+	log.Info("denied")
+
+	return approval, nil
+}
+
+// CancelPending closes the pending approval of the task, if any, without a decision.
+func (s *Storage) CancelPending(ctx context.Context, taskID int) error {
+	op := "approvals.CancelPending"
+
+	log := s.log.With(slog.String("op", op), slog.Int("taskID", taskID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `UPDATE tasks_approvals SET status_id = $1, decided_at = NOW() WHERE task_id = $2 AND status_id = $3`
+
+	if _, err := conn.ExecContext(ctx, query, CancelledStatusID, taskID, PendingStatusID); err != nil {
+		log.Error("failed to cancel pending approval", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+func decide(ctx context.Context, tx *sqlx.Tx, approvalID, adminID, statusID int, comment string) (model.Approval, error) {
+	query := `UPDATE tasks_approvals
+			  SET status_id = $1, decided_by = $2, comment = $3, decided_at = NOW()
+			  WHERE id = $4 AND status_id = $5
+			  RETURNING ` + approvalColumns
+
+	var approval dbApproval
+	err := tx.GetContext(ctx, &approval, query, statusID, adminID, comment, approvalID, PendingStatusID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Approval{}, errs.ErrApprovalNotFound
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23514" {
+			return model.Approval{}, errs.ErrSelfApproval
+		}
+
+		return model.Approval{}, err
+	}
+
+	return approval.toModel(), nil
+}
+
+// checkUpdated reports a stale approval when the task was moved out of the waiting status in the meantime.
+func checkUpdated(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return errs.ErrApprovalStale
+	}
+
+	return nil
+}
+
+func waitingStatusID(actionID int) int {
+	if actionID == AcceptanceActionID {
+		return tasks.AcceptanceApprovalStatusID
+	}
+
+	return tasks.CreationApprovalStatusID
+}
+
+const approvalColumns = `id, task_id, action_id, status_id, amount, requested_by, decided_by, comment, created_at, decided_at`
+
+type dbApproval struct {
+	ID          int           `db:"id"`
+	TaskID      int           `db:"task_id"`
+	ActionID    int           `db:"action_id"`
+	StatusID    int           `db:"status_id"`
+	Amount      float64       `db:"amount"`
+	RequestedBy int           `db:"requested_by"`
+	DecidedBy   sql.NullInt64 `db:"decided_by"`
+	Comment     string        `db:"comment"`
+	CreatedAt   time.Time     `db:"created_at"`
+	DecidedAt   sql.NullTime  `db:"decided_at"`
+}
+
+func (a dbApproval) toModel() model.Approval {
+	return model.Approval{
+		ID:          a.ID,
+		TaskID:      a.TaskID,
+		ActionID:    a.ActionID,
+		StatusID:    a.StatusID,
+		Amount:      a.Amount,
+		RequestedBy: a.RequestedBy,
+		DecidedBy:   int(a.DecidedBy.Int64),
+		Comment:     a.Comment,
+		CreatedAt:   a.CreatedAt,
+		DecidedAt:   a.DecidedAt.Time,
+	}
+}
@@ -0,0 +1,727 @@
+package bounties
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/transactions"
+	"github.com/lib/pq"
+	"log/slog"
+	"time"
+)
+
+const (
+	OpenStatusID      = 1
+	ClaimedStatusID   = 2
+	SubmittedStatusID = 3
+	CompletedStatusID = 4
+	CancelledStatusID = 5
+	DisputedStatusID  = 6
+)
+
+type Storage struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
+	return &Storage{
+		db:  db,
+		log: log,
+	}
+}
+
+// Post escrows the reward from the balance of the poster and opens the bounty for other users.
+func (s *Storage) Post(ctx context.Context, bounty model.Bounty) (model.Bounty, error) {
+	op := "bounties.Post"
+
+	log := s.log.With(slog.String("op", op), slog.Int("posterID", bounty.PosterID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Bounty{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Bounty{}, err
+	}
+
+	transactionID, err := transactions.Debit(ctx, tx, bounty.PosterID, bounty.Reward, transactions.BountyEscrowTypeID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Bounty{}, err
+		}
+
+		if !errors.Is(err, errs.ErrInsufficientFunds) && !errors.Is(err, errs.ErrUserNotFound) {
+			log.Error("failed to escrow reward", slog.String("error", err.Error()))
+		}
+		return model.Bounty{}, err
+	}
+
+	query := `INSERT INTO bounties (poster_id, title, description, reward, status_id, escrow_transaction_id)
+			  VALUES ($1, $2, $3, $4, $5, $6)
+			  RETURNING id`
+
+	err = tx.QueryRowxContext(ctx, query,
+		bounty.PosterID,
+		bounty.Title,
+		bounty.Description,
+		bounty.Reward,
+		OpenStatusID,
+		transactionID,
+	).Scan(&bounty.ID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Bounty{}, err
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return model.Bounty{}, errs.ErrUserNotFound
+		}
+
+		log.Error("failed to add bounty", slog.String("error", err.Error()))
+		return model.Bounty{}, err
+	}
+
+	posted, err := getBounty(ctx, tx, bounty.ID)
+	if err != nil {
+		log.Error("failed to get bounty", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Bounty{}, err
+		}
+		return model.Bounty{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Bounty{}, err
+	}
+
+	log.Info("posted bounty", slog.Int("id", bounty.ID))
+
+	return posted, nil
+}
+
+// Cancel withdraws the open bounty and refunds the reward to its poster.
+func (s *Storage) Cancel(ctx context.Context, id, posterID int) (model.Bounty, error) {
+	return s.change(ctx, "bounties.Cancel", id, func(tx *sqlx.Tx, bounty model.Bounty) error {
+		if bounty.PosterID != posterID {
+			return errs.ErrNotBountyPoster
+		}
+
+		if bounty.StatusID != OpenStatusID {
+			return errs.ErrBountyNotOpen
+		}
+
+		return settle(ctx, tx, bounty, bounty.PosterID, transactions.RefundTypeID, CancelledStatusID)
+	})
+}
+
+// Claim reserves the open bounty for the hunter, who can not be its poster.
+func (s *Storage) Claim(ctx context.Context, id, hunterID int) (model.Bounty, error) {
+	return s.change(ctx, "bounties.Claim", id, func(tx *sqlx.Tx, bounty model.Bounty) error {
+		if bounty.PosterID == hunterID {
+			return errs.ErrOwnBounty
+		}
+
+		if bounty.StatusID != OpenStatusID {
+			return errs.ErrBountyNotOpen
+		}
+
+		_, err := tx.ExecContext(ctx,
+			`UPDATE bounties SET status_id = $1, hunter_id = $2, claimed_at = NOW() WHERE id = $3`,
+			ClaimedStatusID, hunterID, bounty.ID,
+		)
+		return err
+	})
+}
+
+// Abandon gives the claimed bounty up and opens it for other users again.
+func (s *Storage) Abandon(ctx context.Context, id, hunterID int) (model.Bounty, error) {
+	return s.change(ctx, "bounties.Abandon", id, func(tx *sqlx.Tx, bounty model.Bounty) error {
+		if bounty.HunterID != hunterID {
+			return errs.ErrNotBountyHunter
+		}
+
+		if bounty.StatusID != ClaimedStatusID {
+			return errs.ErrBountyNotClaimed
+		}
+
+		_, err := tx.ExecContext(ctx,
+			`UPDATE bounties SET status_id = $1, hunter_id = NULL, submission = '', reject_reason = '', claimed_at = NULL WHERE id = $2`,
+			OpenStatusID, bounty.ID,
+		)
+		return err
+	})
+}
+
+// Submit hands the work of the hunter over to the poster for approval.
+func (s *Storage) Submit(ctx context.Context, id, hunterID int, submission string) (model.Bounty, error) {
+	return s.change(ctx, "bounties.Submit", id, func(tx *sqlx.Tx, bounty model.Bounty) error {
+		if bounty.HunterID != hunterID {
+			return errs.ErrNotBountyHunter
+		}
+
+		if bounty.StatusID != ClaimedStatusID {
+			return errs.ErrBountyNotClaimed
+		}
+
+		_, err := tx.ExecContext(ctx,
+			`UPDATE bounties SET status_id = $1, submission = $2, submitted_at = NOW() WHERE id = $3`,
+			SubmittedStatusID, submission, bounty.ID,
+		)
+		return err
+	})
+}
+
+// Approve accepts the submission and pays the escrowed reward to the hunter.
+func (s *Storage) Approve(ctx context.Context, id, posterID int) (model.Bounty, error) {
+	return s.change(ctx, "bounties.Approve", id, func(tx *sqlx.Tx, bounty model.Bounty) error {
+		if bounty.PosterID != posterID {
+			return errs.ErrNotBountyPoster
+		}
+
+		if bounty.StatusID != SubmittedStatusID {
+			return errs.ErrBountyNotSubmitted
+		}
+
+		return settle(ctx, tx, bounty, bounty.HunterID, transactions.BountyPayoutTypeID, CompletedStatusID)
+	})
+}
+
+// Reject sends the submission back to the hunter with the reason, the hunter keeps the claim and may submit again.
+func (s *Storage) Reject(ctx context.Context, id, posterID int, reason string) (model.Bounty, error) {
+	return s.change(ctx, "bounties.Reject", id, func(tx *sqlx.Tx, bounty model.Bounty) error {
+		if bounty.PosterID != posterID {
+			return errs.ErrNotBountyPoster
+		}
+
+		if bounty.StatusID != SubmittedStatusID {
+			return errs.ErrBountyNotSubmitted
+		}
+
+		_, err := tx.ExecContext(ctx,
+			`UPDATE bounties SET status_id = $1, reject_reason = $2 WHERE id = $3`,
+			ClaimedStatusID, reason, bounty.ID,
+		)
+		return err
+	})
+}
+
+// Dispute escalates the claimed or submitted bounty to admins on behalf of its poster or hunter.
+// The bounty stays frozen until an admin resolves the dispute.
+func (s *Storage) Dispute(ctx context.Context, id, userID int, reason string) (model.BountyDispute, error) {
+	var disputeID int
+	_, err := s.change(ctx, "bounties.Dispute", id, func(tx *sqlx.Tx, bounty model.Bounty) error {
+		if bounty.PosterID != userID && bounty.HunterID != userID {
+			return errs.ErrNotBountyParticipant
+		}
+
+		if bounty.StatusID != ClaimedStatusID && bounty.StatusID != SubmittedStatusID {
+			return errs.ErrBountyNotDisputable
+		}
+
+		err := tx.QueryRowxContext(ctx,
+			`INSERT INTO bounties_disputes (bounty_id, raised_by, reason) VALUES ($1, $2, $3) RETURNING id`,
+			bounty.ID, userID, reason,
+		).Scan(&disputeID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE bounties SET status_id = $1 WHERE id = $2`, DisputedStatusID, bounty.ID)
+		return err
+	})
+	if err != nil {
+		return model.BountyDispute{}, err
+	}
+
+	return s.GetDispute(ctx, disputeID)
+}
+
+// ResolveDispute closes the dispute by an admin: paid bounties go to the hunter as if the poster
+// approved them, otherwise the reward is refunded to the poster and the bounty is cancelled.
+func (s *Storage) ResolveDispute(ctx context.Context, disputeID, adminID int, paid bool, note string) (model.BountyDispute, error) {
+	op := "bounties.ResolveDispute"
+
+	log := s.log.With(slog.String("op", op), slog.Int("disputeID", disputeID), slog.Int("adminID", adminID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.BountyDispute{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.BountyDispute{}, err
+	}
+
+	var dispute dbDispute
+	err = tx.GetContext(ctx, &dispute, `SELECT `+disputeColumns+` FROM `+disputeTables+` WHERE d.id = $1 FOR UPDATE OF d, b`, disputeID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.BountyDispute{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.BountyDispute{}, errs.ErrDisputeNotFound
+		}
+
+		log.Error("failed to lock dispute", slog.String("error", err.Error()))
+		return model.BountyDispute{}, err
+	}
+
+	if dispute.ResolvedAt.Valid {
+		if err := tx.Rollback(); err != nil {
+			return model.BountyDispute{}, err
+		}
+		return model.BountyDispute{}, errs.ErrDisputeAlreadyResolved
+	}
+
+	bounty, err := getBounty(ctx, tx, dispute.BountyID)
+	if err != nil {
+		log.Error("failed to get bounty", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.BountyDispute{}, err
+		}
+		return model.BountyDispute{}, err
+	}
+
+	receiverID, typeID, statusID := bounty.PosterID, transactions.RefundTypeID, CancelledStatusID
+	if paid {
+		receiverID, typeID, statusID = bounty.HunterID, transactions.BountyPayoutTypeID, CompletedStatusID
+	}
+
+	if err := settle(ctx, tx, bounty, receiverID, typeID, statusID); err != nil {
+		log.Error("failed to settle bounty", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.BountyDispute{}, err
+		}
+		return model.BountyDispute{}, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE bounties_disputes SET paid = $1, note = $2, resolved_by = $3, resolved_at = NOW() WHERE id = $4`,
+		paid, note, adminID, disputeID,
+	)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.BountyDispute{}, err
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return model.BountyDispute{}, errs.ErrAdminNotFound
+		}
+
+		log.Error("failed to resolve dispute", slog.String("error", err.Error()))
+		return model.BountyDispute{}, err
+	}
+
+	resolved, err := getDispute(ctx, tx, disputeID)
+	if err != nil {
+		log.Error("failed to get dispute", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.BountyDispute{}, err
+		}
+		return model.BountyDispute{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.BountyDispute{}, err
+	}
+
+	log.Info("resolved dispute", slog.Bool("paid", paid))
+
+	return resolved, nil
+}
+
+// GetByID returns the bounty with the names of its poster and hunter.
+func (s *Storage) GetByID(ctx context.Context, id int) (model.Bounty, error) {
+	op := "bounties.GetByID"
+
+	log := s.log.With(slog.String("op", op), slog.Int("id", id))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Bounty{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	bounty, err := getBounty(ctx, conn, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Bounty{}, errs.ErrBountyNotFound
+		}
+
+		log.Error("failed to get bounty", slog.String("error", err.Error()))
+		return model.Bounty{}, err
+	}
+
+	return bounty, nil
+}
+
+// GetOpen returns a page of the bounties nobody claimed yet, newest first.
+func (s *Storage) GetOpen(ctx context.Context, limit, offset int) ([]model.Bounty, error) {
+	return s.get(ctx, "bounties.GetOpen",
+		`b.status_id = $1 ORDER BY b.created_at DESC, b.id DESC LIMIT $2 OFFSET $3`,
+		OpenStatusID, limit, offset,
+	)
+}
+
+// GetByUser returns a page of the bounties the user posted or claimed, newest first.
+func (s *Storage) GetByUser(ctx context.Context, userID, limit, offset int) ([]model.Bounty, error) {
+	return s.get(ctx, "bounties.GetByUser",
+		`(b.poster_id = $1 OR b.hunter_id = $1) ORDER BY b.created_at DESC, b.id DESC LIMIT $2 OFFSET $3`,
+		userID, limit, offset,
+	)
+}
+
+// GetDispute returns the dispute with the bounty it is about.
+func (s *Storage) GetDispute(ctx context.Context, id int) (model.BountyDispute, error) {
+	op := "bounties.GetDispute"
+
+	log := s.log.With(slog.String("op", op), slog.Int("id", id))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.BountyDispute{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	dispute, err := getDispute(ctx, conn, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.BountyDispute{}, errs.ErrDisputeNotFound
+		}
+
+		log.Error("failed to get dispute", slog.String("error", err.Error()))
+		return model.BountyDispute{}, err
+	}
+
+	return dispute, nil
+}
+
+// GetDisputes returns the disputes, oldest first, only the unresolved ones if pending is set.
+func (s *Storage) GetDisputes(ctx context.Context, pending bool, limit int) ([]model.BountyDispute, error) {
+	op := "bounties.GetDisputes"
+
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT ` + disputeColumns + ` FROM ` + disputeTables + `
+			  WHERE NOT $1 OR d.resolved_at IS NULL
+			  ORDER BY d.created_at, d.id
+			  LIMIT $2`
+
+	var dbDisputes []dbDispute
+	if err := conn.SelectContext(ctx, &dbDisputes, query, pending, limit); err != nil {
+		log.Error("failed to get disputes", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	disputes := make([]model.BountyDispute, 0, len(dbDisputes))
+	for _, d := range dbDisputes {
+		disputes = append(disputes, d.toModel())
+	}
+
+	return disputes, nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+// change locks the bounty and lets apply check and update it within one transaction, then returns
+// the bounty as it is afterwards. The errors of the storage apply returns are not logged.
+func (s *Storage) change(ctx context.Context, op string, id int, apply func(tx *sqlx.Tx, bounty model.Bounty) error) (model.Bounty, error) {
+	log := s.log.With(slog.String("op", op), slog.Int("id", id))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Bounty{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Bounty{}, err
+	}
+
+	var locked dbBounty
+	if err := tx.GetContext(ctx, &locked, `SELECT `+bountyColumns+` FROM `+bountyTables+` WHERE b.id = $1 FOR UPDATE OF b`, id); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Bounty{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Bounty{}, errs.ErrBountyNotFound
+		}
+
+		log.Error("failed to lock bounty", slog.String("error", err.Error()))
+		return model.Bounty{}, err
+	}
+
+	if err := apply(tx, locked.toModel()); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Bounty{}, err
+		}
+
+		if !isKnown(err) {
+			log.Error("failed to change bounty", slog.String("error", err.Error()))
+		}
+		return model.Bounty{}, err
+	}
+
+	changed, err := getBounty(ctx, tx, id)
+	if err != nil {
+		log.Error("failed to get bounty", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Bounty{}, err
+		}
+		return model.Bounty{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Bounty{}, err
+	}
+
+	log.Info("changed bounty", slog.Int("statusID", changed.StatusID))
+
+	return changed, nil
+}
+
+// get selects the bounties matching the condition, which may also order and limit them.
+func (s *Storage) get(ctx context.Context, op, condition string, args ...interface{}) ([]model.Bounty, error) {
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var dbBounties []dbBounty
+	if err := conn.SelectContext(ctx, &dbBounties, `SELECT `+bountyColumns+` FROM `+bountyTables+` WHERE `+condition, args...); err != nil {
+		log.Error("failed to get bounties", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	bounties := make([]model.Bounty, 0, len(dbBounties))
+	for _, b := range dbBounties {
+		bounties = append(bounties, b.toModel())
+	}
+
+	return bounties, nil
+}
+
+func isKnown(err error) bool {
+	for _, known := range []error{
+		errs.ErrNotBountyPoster,
+		errs.ErrNotBountyHunter,
+		errs.ErrNotBountyParticipant,
+		errs.ErrOwnBounty,
+		errs.ErrBountyNotOpen,
+		errs.ErrBountyNotClaimed,
+		errs.ErrBountyNotSubmitted,
+		errs.ErrBountyNotDisputable,
+	} {
+		if errors.Is(err, known) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getBounty(ctx context.Context, q sqlx.QueryerContext, id int) (model.Bounty, error) {
+	var bounty dbBounty
+	if err := sqlx.GetContext(ctx, q, &bounty, `SELECT `+bountyColumns+` FROM `+bountyTables+` WHERE b.id = $1`, id); err != nil {
+		return model.Bounty{}, err
+	}
+
+	return bounty.toModel(), nil
+}
+
+func getDispute(ctx context.Context, q sqlx.QueryerContext, id int) (model.BountyDispute, error) {
+	var dispute dbDispute
+	if err := sqlx.GetContext(ctx, q, &dispute, `SELECT `+disputeColumns+` FROM `+disputeTables+` WHERE d.id = $1`, id); err != nil {
+		return model.BountyDispute{}, err
+	}
+
+	return dispute.toModel(), nil
+}
+
+// settle pays the escrowed reward out to the user and closes the bounty with the status.
+func settle(ctx context.Context, tx *sqlx.Tx, bounty model.Bounty, userID, typeID, statusID int) error {
+	transactionID, err := transactions.Credit(ctx, tx, userID, bounty.Reward, typeID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE bounties SET status_id = $1, settlement_transaction_id = $2, closed_at = NOW() WHERE id = $3`,
+		statusID, transactionID, bounty.ID,
+	)
+	return err
+}
+
+const bountyColumns = `b.id, b.poster_id, p.username AS poster_name, b.title, b.description, b.reward, b.status_id,
+	COALESCE(b.hunter_id, 0) AS hunter_id, COALESCE(h.username, '') AS hunter_name, b.submission, b.reject_reason,
+	b.created_at, b.claimed_at, b.submitted_at, b.closed_at`
+
+const bountyTables = `bounties b
+	JOIN users p ON p.id = b.poster_id
+	LEFT JOIN users h ON h.id = b.hunter_id`
+
+const disputeColumns = `d.id, d.bounty_id, b.title AS bounty_title, b.reward, b.poster_id, COALESCE(b.hunter_id, 0) AS hunter_id,
+	d.raised_by, d.reason, COALESCE(d.paid, FALSE) AS paid, d.note, COALESCE(d.resolved_by, 0) AS resolved_by,
+	d.created_at, d.resolved_at`
+
+const disputeTables = `bounties_disputes d
+	JOIN bounties b ON b.id = d.bounty_id`
+
+type dbBounty struct {
+	ID           int          `db:"id"`
+	PosterID     int          `db:"poster_id"`
+	PosterName   string       `db:"poster_name"`
+	Title        string       `db:"title"`
+	Description  string       `db:"description"`
+	Reward       float64      `db:"reward"`
+	StatusID     int          `db:"status_id"`
+	HunterID     int          `db:"hunter_id"`
+	HunterName   string       `db:"hunter_name"`
+	Submission   string       `db:"submission"`
+	RejectReason string       `db:"reject_reason"`
+	CreatedAt    time.Time    `db:"created_at"`
+	ClaimedAt    sql.NullTime `db:"claimed_at"`
+	SubmittedAt  sql.NullTime `db:"submitted_at"`
+	ClosedAt     sql.NullTime `db:"closed_at"`
+}
+
+func (b dbBounty) toModel() model.Bounty {
+	return model.Bounty{
+		ID:           b.ID,
+		PosterID:     b.PosterID,
+		PosterName:   b.PosterName,
+		Title:        b.Title,
+		Description:  b.Description,
+		Reward:       b.Reward,
+		StatusID:     b.StatusID,
+		HunterID:     b.HunterID,
+		HunterName:   b.HunterName,
+		Submission:   b.Submission,
+		RejectReason: b.RejectReason,
+		CreatedAt:    b.CreatedAt,
+		ClaimedAt:    b.ClaimedAt.Time,
+		SubmittedAt:  b.SubmittedAt.Time,
+		ClosedAt:     b.ClosedAt.Time,
+	}
+}
+
+type dbDispute struct {
+	ID          int          `db:"id"`
+	BountyID    int          `db:"bounty_id"`
+	BountyTitle string       `db:"bounty_title"`
+	Reward      float64      `db:"reward"`
+	PosterID    int          `db:"poster_id"`
+	HunterID    int          `db:"hunter_id"`
+	RaisedBy    int          `db:"raised_by"`
+	Reason      string       `db:"reason"`
+	Paid        bool         `db:"paid"`
+	Note        string       `db:"note"`
+	ResolvedBy  int          `db:"resolved_by"`
+	CreatedAt   time.Time    `db:"created_at"`
+	ResolvedAt  sql.NullTime `db:"resolved_at"`
+}
+
+func (d dbDispute) toModel() model.BountyDispute {
+	return model.BountyDispute{
+		ID:          d.ID,
+		BountyID:    d.BountyID,
+		BountyTitle: d.BountyTitle,
+		Reward:      d.Reward,
+		PosterID:    d.PosterID,
+		HunterID:    d.HunterID,
+		RaisedBy:    d.RaisedBy,
+		Reason:      d.Reason,
+		Paid:        d.Paid,
+		Note:        d.Note,
+		ResolvedBy:  d.ResolvedBy,
+		CreatedAt:   d.CreatedAt,
+		ResolvedAt:  d.ResolvedAt.Time,
+	}
+}
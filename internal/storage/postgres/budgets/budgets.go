@@ -0,0 +1,325 @@
+package budgets
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/nullable"
+	"github.com/lib/pq"
+	"log/slog"
+	"time"
+)
+
+const (
+	MonthPeriodID   = 1
+	QuarterPeriodID = 2
+)
+
+type Storage struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
+	return &Storage{
+		db:  db,
+		log: log,
+	}
+}
+
+// Allocate creates the budget or replaces the amount of an existing one for the same owner and period.
+func (s *Storage) Allocate(ctx context.Context, budget model.Budget) (int, error) {
+	op := "budgets.Allocate"
+
+	log := s.log.With(slog.String("op", op), slog.Int("adminID", budget.AdminID), slog.Int("groupID", budget.GroupID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	owner := "admin_id"
+	if budget.AdminID == 0 {
+		owner = "group_id"
+	}
+
+	query := `INSERT INTO budgets (admin_id, group_id, period_id, starts_at, ends_at, amount, allocated_by)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7)
+			  ON CONFLICT (` + owner + `, period_id, starts_at)
+			  DO UPDATE SET amount = EXCLUDED.amount, allocated_by = EXCLUDED.allocated_by, updated_at = NOW()
+			  RETURNING id`
+
+	var id int
+	err = conn.QueryRowxContext(ctx, query,
+		nullable.ID(budget.AdminID),
+		nullable.ID(budget.GroupID),
+		budget.PeriodID,
+		budget.StartsAt,
+		budget.EndsAt,
+		budget.Amount,
+		budget.AllocatedBy,
+	).Scan(&id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			switch {
+			case pqErr.Code == "23503":
+				return 0, errs.ErrBudgetOwnerNotFound
+			case pqErr.Constraint == usageConstraint:
+				return 0, errs.ErrBudgetBelowUsage
+			}
+		}
+
+		log.Error("failed to allocate budget", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	log.Info("allocated budget", slog.Int("id", id))
+
+	return id, nil
+}
+
+// Reserve puts amount aside from the budget covering the moment at. An admin's own budget
+// takes precedence over the budget of the group, and a shorter period over a longer one.
+// Zero ID without an error means that no budget applies.
+func (s *Storage) Reserve(ctx context.Context, adminID, groupID int, amount float64, at time.Time) (int, error) {
+	op := "budgets.Reserve"
+
+	log := s.log.With(slog.String("op", op), slog.Int("adminID", adminID), slog.Int("groupID", groupID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT id FROM budgets
+			  WHERE (admin_id = $1 OR group_id = $2) AND starts_at <= $3 AND ends_at > $3
+			  ORDER BY admin_id IS NULL, ends_at - starts_at, id
+			  LIMIT 1`
+
+	var id int
+	if err := conn.GetContext(ctx, &id, query, adminID, groupID, at); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+
+		log.Error("failed to find budget", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	if err := adjust(ctx, conn, id, amount); err != nil {
+		log.Error("failed to reserve", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	log.Info("reserved", slog.Int("budgetID", id), slog.Float64("amount", amount))
+
+	return id, nil
+}
+
+// Adjust changes the reserved part of the budget by delta, a negative delta releases coins.
+func (s *Storage) Adjust(ctx context.Context, budgetID int, delta float64) error {
+	op := "budgets.Adjust"
+
+	log := s.log.With(slog.String("op", op), slog.Int("budgetID", budgetID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	if err := adjust(ctx, conn, budgetID, delta); err != nil {
+		log.Error("failed to adjust budget", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// GetActive returns budgets covering the moment at that apply to the admin: their own and the groups' ones.
+func (s *Storage) GetActive(ctx context.Context, adminID int, at time.Time) ([]model.Budget, error) {
+	op := "budgets.GetActive"
+
+	log := s.log.With(slog.String("op", op), slog.Int("adminID", adminID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT ` + budgetColumns + ` FROM budgets
+			  WHERE (admin_id = $1 OR group_id IS NOT NULL) AND starts_at <= $2 AND ends_at > $2
+			  ORDER BY admin_id IS NULL, ends_at - starts_at, id`
+
+	var dbBudgets []dbBudget
+	if err := conn.SelectContext(ctx, &dbBudgets, query, adminID, at); err != nil {
+		log.Error("failed to get budgets", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return toModelBudgets(dbBudgets), nil
+}
+
+// GetAll returns all budgets, newest periods first. Zero adminID or groupID disables the corresponding filter.
+func (s *Storage) GetAll(ctx context.Context, adminID, groupID, limit int) ([]model.Budget, error) {
+	op := "budgets.GetAll"
+
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT ` + budgetColumns + ` FROM budgets
+			  WHERE ($1 = 0 OR admin_id = $1) AND ($2 = 0 OR group_id = $2)
+			  ORDER BY starts_at DESC, id DESC
+			  LIMIT $3`
+
+	var dbBudgets []dbBudget
+	if err := conn.SelectContext(ctx, &dbBudgets, query, adminID, groupID, limit); err != nil {
+		log.Error("failed to get budgets", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return toModelBudgets(dbBudgets), nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+const (
+	usageConstraint    = "budgets_usage_check"
+	reservedConstraint = "budgets_reserved_check"
+)
+
+// Spend turns reserved coins of the budget into spent ones within the transaction. It returns
+// ErrBudgetExceeded if the spent coins do not fit into the budget and ErrBudgetOverReleased
+// if fewer coins than reserved are left.
+func Spend(ctx context.Context, tx *sqlx.Tx, budgetID int, reserved, spent float64) error {
+	query := `UPDATE budgets SET reserved = reserved - $1, spent = spent + $2, updated_at = NOW() WHERE id = $3`
+
+	if _, err := tx.ExecContext(ctx, query, reserved, spent, budgetID); err != nil {
+		return mapUsageError(err)
+	}
+
+	return nil
+}
+
+// Hold puts amount aside from the budget within the transaction. It returns ErrBudgetExceeded
+// if the amount does not fit into the budget.
+func Hold(ctx context.Context, tx *sqlx.Tx, budgetID int, amount float64) error {
+	return adjust(ctx, tx, budgetID, amount)
+}
+
+// adjust relies on the usage constraints, so concurrent reservations can never overdraw the budget
+// and releases never free more than was reserved.
+func adjust(ctx context.Context, execer sqlx.ExecerContext, budgetID int, delta float64) error {
+	query := `UPDATE budgets SET reserved = reserved + $1, updated_at = NOW() WHERE id = $2`
+
+	if _, err := execer.ExecContext(ctx, query, delta, budgetID); err != nil {
+		return mapUsageError(err)
+	}
+
+	return nil
+}
+
+func mapUsageError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Constraint {
+		case usageConstraint:
+			return errs.ErrBudgetExceeded
+		case reservedConstraint:
+			return errs.ErrBudgetOverReleased
+		}
+	}
+
+	return err
+}
+
+const budgetColumns = `id, admin_id, group_id, period_id, starts_at, ends_at, amount, reserved, spent, allocated_by, created_at`
+
+type dbBudget struct {
+	ID          int           `db:"id"`
+	AdminID     sql.NullInt64 `db:"admin_id"`
+	GroupID     sql.NullInt64 `db:"group_id"`
+	PeriodID    int           `db:"period_id"`
+	StartsAt    time.Time     `db:"starts_at"`
+	EndsAt      time.Time     `db:"ends_at"`
+	Amount      float64       `db:"amount"`
+	Reserved    float64       `db:"reserved"`
+	Spent       float64       `db:"spent"`
+	AllocatedBy int           `db:"allocated_by"`
+	CreatedAt   time.Time     `db:"created_at"`
+}
+
+func toModelBudgets(dbBudgets []dbBudget) []model.Budget {
+	budgets := make([]model.Budget, 0, len(dbBudgets))
+	for _, budget := range dbBudgets {
+		budgets = append(budgets, model.Budget{
+			ID:          budget.ID,
+			AdminID:     int(budget.AdminID.Int64),
+			GroupID:     int(budget.GroupID.Int64),
+			PeriodID:    budget.PeriodID,
+			StartsAt:    budget.StartsAt,
+			EndsAt:      budget.EndsAt,
+			Amount:      budget.Amount,
+			Reserved:    budget.Reserved,
+			Spent:       budget.Spent,
+			AllocatedBy: budget.AllocatedBy,
+			CreatedAt:   budget.CreatedAt,
+		})
+	}
+
+	return budgets
+}
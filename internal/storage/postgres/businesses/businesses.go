@@ -3,9 +3,13 @@ package businesses
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"github.com/jmoiron/sqlx"
 	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/transactions"
 	"log/slog"
+	"time"
 )
 
 const (
@@ -61,57 +65,282 @@ func (s *Storage) Save(ctx context.Context, business *model.Business) (int, erro
 	return id, nil
 }
 
+// GetAll returns all the businesses, those for sale have zero OwnerID.
 func (s *Storage) GetAll(ctx context.Context) ([]model.Business, error) {
-	op := "businesses.GetAll"
+	return s.get(ctx, "businesses.GetAll", `TRUE ORDER BY b.id`)
+}
 
-	log := s.log.With("op", op)
+// GetByOwner returns the businesses the user owns.
+func (s *Storage) GetByOwner(ctx context.Context, ownerID int) ([]model.Business, error) {
+	return s.get(ctx, "businesses.GetByOwner", `b.owner_id = $1 ORDER BY b.id`, ownerID)
+}
+
+func (s *Storage) GetByID(ctx context.Context, id int) (model.Business, error) {
+	op := "businesses.GetByID"
+
+	log := s.log.With(slog.String("op", op), slog.Int("id", id))
 
 	conn, err := s.db.Connx(ctx)
 	if err != nil {
 		log.Error("failed to get connection", slog.String("error", err.Error()))
-		return nil, err
+		return model.Business{}, err
 	}
+
 	defer func(conn *sqlx.Conn) {
 		err := conn.Close()
 		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
 			return
 		}
 	}(conn)
 
-	query := `SELECT id, name, type_id, price, owner_id FROM businesses`
+	business, err := getBusiness(ctx, conn, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Business{}, errs.ErrBusinessNotFound
+		}
 
-	var dbBusinesses []dbBusiness
+		log.Error("failed to get business", slog.String("error", err.Error()))
+		return model.Business{}, err
+	}
 
-	if err := conn.SelectContext(ctx, &dbBusinesses, query); err != nil {
-		log.Error("failed to get all businesses", slog.String("error", err.Error()))
-		return nil, err
+	return business, nil
+}
+
+// Buy sells the business to the user for its price. The business starts producing with the first
+// recipe of its type right away.
+func (s *Storage) Buy(ctx context.Context, id, userID int, now time.Time) (model.Business, error) {
+	op := "businesses.Buy"
+
+	log := s.log.With(slog.String("op", op), slog.Int("id", id), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Business{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Business{}, err
 	}
 
-	var businesses []model.Business
+	var business struct {
+		Price   float64       `db:"price"`
+		OwnerID sql.NullInt64 `db:"owner_id"`
+	}
+	err = tx.GetContext(ctx, &business, `SELECT COALESCE(price, 0) AS price, owner_id FROM businesses WHERE id = $1 FOR UPDATE`, id)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Business{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Business{}, errs.ErrBusinessNotFound
+		}
+
+		log.Error("failed to get business", slog.String("error", err.Error()))
+		return model.Business{}, err
+	}
 
-	for _, dbBusiness := range dbBusinesses {
-		if !dbBusiness.OwnerID.Valid {
-			dbBusiness.OwnerID = sql.NullInt64{Int64: 0, Valid: true}
+	if business.OwnerID.Valid {
+		if err := tx.Rollback(); err != nil {
+			return model.Business{}, err
 		}
+		return model.Business{}, errs.ErrBusinessAlreadyOwned
+	}
 
-		businesses = append(businesses, model.Business{
-			ID:      dbBusiness.ID,
-			Name:    dbBusiness.Name,
-			TypeID:  dbBusiness.TypeID,
-			Price:   dbBusiness.Price,
-			OwnerID: int(dbBusiness.OwnerID.Int64),
-		})
+	if business.Price > 0 {
+		if _, err := transactions.Debit(ctx, tx, userID, business.Price, transactions.PurchaseTypeID); err != nil {
+			if err := tx.Rollback(); err != nil {
+				return model.Business{}, err
+			}
+
+			if errors.Is(err, errs.ErrUserNotFound) || errors.Is(err, errs.ErrInsufficientFunds) {
+				return model.Business{}, err
+			}
+
+			log.Error("failed to pay for business", slog.String("error", err.Error()))
+			return model.Business{}, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE businesses b
+		 SET owner_id = $1,
+		 recipe_id = (SELECT MIN(r.id) FROM recipes r WHERE r.business_type_id = b.type_id),
+		 produced_at = $2
+		 WHERE b.id = $3`,
+		userID, now, id,
+	)
+	if err != nil {
+		log.Error("failed to update owner", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Business{}, err
+		}
+		return model.Business{}, err
+	}
+
+	bought, err := getBusiness(ctx, tx, id)
+	if err != nil {
+		log.Error("failed to get business", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Business{}, err
+		}
+		return model.Business{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Business{}, err
+	}
+
+	log.Info("bought business", slog.Float64("price", business.Price))
+
+	return bought, nil
+}
+
+// SetRecipe switches what the business of the owner produces. The production cycle starts over.
+func (s *Storage) SetRecipe(ctx context.Context, id, ownerID, recipeID int, now time.Time) (model.Business, error) {
+	op := "businesses.SetRecipe"
+
+	log := s.log.With(slog.String("op", op), slog.Int("id", id), slog.Int("ownerID", ownerID), slog.Int("recipeID", recipeID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Business{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	res, err := conn.ExecContext(ctx,
+		`UPDATE businesses b SET recipe_id = r.id, produced_at = $1
+		 FROM recipes r
+		 WHERE b.id = $2 AND b.owner_id = $3 AND r.id = $4 AND r.business_type_id = b.type_id`,
+		now, id, ownerID, recipeID,
+	)
+	if err != nil {
+		log.Error("failed to set recipe", slog.String("error", err.Error()))
+		return model.Business{}, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		log.Error("failed to get affected rows", slog.String("error", err.Error()))
+		return model.Business{}, err
+	}
+
+	business, err := getBusiness(ctx, conn, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Business{}, errs.ErrBusinessNotFound
+		}
+
+		log.Error("failed to get business", slog.String("error", err.Error()))
+		return model.Business{}, err
+	}
+
+	if affected == 0 {
+		if business.OwnerID != ownerID {
+			return model.Business{}, errs.ErrNotBusinessOwner
+		}
+		return model.Business{}, errs.ErrRecipeNotFound
+	}
+
+	log.Info("set recipe")
+
+	return business, nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+// get selects the businesses matching the condition, which may also order them.
+func (s *Storage) get(ctx context.Context, op, condition string, args ...interface{}) ([]model.Business, error) {
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var dbBusinesses []dbBusiness
+	if err := conn.SelectContext(ctx, &dbBusinesses, `SELECT `+businessColumns+` FROM businesses b JOIN businesses_types bt ON bt.id = b.type_id WHERE `+condition, args...); err != nil {
+		log.Error("failed to get businesses", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	businesses := make([]model.Business, 0, len(dbBusinesses))
+	for _, b := range dbBusinesses {
+		businesses = append(businesses, b.toModel())
 	}
 
 	return businesses, nil
 }
 
+func getBusiness(ctx context.Context, q sqlx.QueryerContext, id int) (model.Business, error) {
+	var business dbBusiness
+	err := sqlx.GetContext(ctx, q, &business,
+		`SELECT `+businessColumns+` FROM businesses b JOIN businesses_types bt ON bt.id = b.type_id WHERE b.id = $1`,
+		id,
+	)
+	if err != nil {
+		return model.Business{}, err
+	}
+
+	return business.toModel(), nil
+}
+
+const businessColumns = `b.id, b.name, b.type_id, bt.name AS type_name, COALESCE(b.price, 0) AS price, b.owner_id, b.recipe_id, b.produced_at`
+
 type dbBusiness struct {
-	ID      int           `db:"id"`
-	Name    string        `db:"name"`
-	TypeID  int           `db:"type_id"`
-	Price   float64       `db:"price"`
-	OwnerID sql.NullInt64 `db:"owner_id"`
+	ID         int           `db:"id"`
+	Name       string        `db:"name"`
+	TypeID     int           `db:"type_id"`
+	TypeName   string        `db:"type_name"`
+	Price      float64       `db:"price"`
+	OwnerID    sql.NullInt64 `db:"owner_id"`
+	RecipeID   sql.NullInt64 `db:"recipe_id"`
+	ProducedAt sql.NullTime  `db:"produced_at"`
+}
+
+func (b dbBusiness) toModel() model.Business {
+	return model.Business{
+		ID:         b.ID,
+		Name:       b.Name,
+		TypeID:     b.TypeID,
+		TypeName:   b.TypeName,
+		OwnerID:    int(b.OwnerID.Int64),
+		Price:      b.Price,
+		RecipeID:   int(b.RecipeID.Int64),
+		ProducedAt: b.ProducedAt.Time,
+	}
 }
 
 type dbBusinessType struct {
@@ -0,0 +1,340 @@
+package companions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/companions"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/production"
+	"log/slog"
+	"time"
+)
+
+type Storage struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
+	return &Storage{
+		db:  db,
+		log: log,
+	}
+}
+
+// Get returns the companion of the user as stored. A user who has not cared for the companion yet
+// has a full and happy one as of registration.
+func (s *Storage) Get(ctx context.Context, userID int) (model.Companion, error) {
+	op := "companions.Get"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Companion{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	companion, err := getCompanion(ctx, conn, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Companion{}, errs.ErrUserNotFound
+		}
+
+		log.Error("failed to get companion", slog.String("error", err.Error()))
+		return model.Companion{}, err
+	}
+
+	return companion, nil
+}
+
+// Feed takes the quantity of the food from the inventory of the user and feeds it to the companion,
+// hunger and happiness decay by the rates up to now first.
+func (s *Storage) Feed(ctx context.Context, userID, resourceID, quantity int, rates companions.Rates, now time.Time) (model.Companion, error) {
+	op := "companions.Feed"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID), slog.Int("resourceID", resourceID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Companion{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Companion{}, err
+	}
+
+	if err := production.LockInventory(ctx, tx, userID); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Companion{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Companion{}, errs.ErrUserNotFound
+		}
+
+		log.Error("failed to lock inventory", slog.String("error", err.Error()))
+		return model.Companion{}, err
+	}
+
+	var food struct {
+		Quantity  int `db:"quantity"`
+		Nutrition int `db:"nutrition"`
+		Joy       int `db:"joy"`
+	}
+	err = tx.GetContext(ctx, &food,
+		`SELECT COALESCE(i.quantity, 0) AS quantity, r.nutrition, r.joy
+		 FROM resources r
+		 LEFT JOIN users_inventories i ON i.resource_id = r.id AND i.user_id = $1
+		 WHERE r.id = $2`,
+		userID, resourceID,
+	)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Companion{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Companion{}, errs.ErrResourceNotFound
+		}
+
+		log.Error("failed to get resource", slog.String("error", err.Error()))
+		return model.Companion{}, err
+	}
+
+	if food.Nutrition == 0 && food.Joy == 0 {
+		if err := tx.Rollback(); err != nil {
+			return model.Companion{}, err
+		}
+		return model.Companion{}, errs.ErrNotFood
+	}
+
+	if food.Quantity < quantity {
+		if err := tx.Rollback(); err != nil {
+			return model.Companion{}, err
+		}
+		return model.Companion{}, errs.ErrNotEnoughResources
+	}
+
+	companion, err := lock(ctx, tx, userID)
+	if err != nil {
+		log.Error("failed to lock companion", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Companion{}, err
+		}
+		return model.Companion{}, err
+	}
+
+	companion = companions.Feed(companions.At(companion, rates, now), food.Nutrition, food.Joy, quantity, now)
+
+	if err := production.AddToInventory(ctx, tx, userID, resourceID, -quantity); err != nil {
+		log.Error("failed to take food", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Companion{}, err
+		}
+		return model.Companion{}, err
+	}
+
+	if err := update(ctx, tx, companion); err != nil {
+		log.Error("failed to update companion", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Companion{}, err
+		}
+		return model.Companion{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Companion{}, err
+	}
+
+	log.Info("fed companion", slog.Int("quantity", quantity))
+
+	return companion, nil
+}
+
+// Play plays with the companion of the user. It does not want to play again until the cooldown
+// since the last game is over.
+func (s *Storage) Play(ctx context.Context, userID int, happiness, hunger float64, cooldown time.Duration, rates companions.Rates, now time.Time) (model.Companion, error) {
+	op := "companions.Play"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Companion{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Companion{}, err
+	}
+
+	companion, err := lock(ctx, tx, userID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Companion{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Companion{}, errs.ErrUserNotFound
+		}
+
+		log.Error("failed to lock companion", slog.String("error", err.Error()))
+		return model.Companion{}, err
+	}
+
+	if !companion.PlayedAt.IsZero() && now.Before(companion.PlayedAt.Add(cooldown)) {
+		if err := tx.Rollback(); err != nil {
+			return model.Companion{}, err
+		}
+		return model.Companion{}, errs.ErrCompanionTooSoon
+	}
+
+	companion = companions.Play(companions.At(companion, rates, now), happiness, hunger, now)
+
+	if err := update(ctx, tx, companion); err != nil {
+		log.Error("failed to update companion", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Companion{}, err
+		}
+		return model.Companion{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Companion{}, err
+	}
+
+	log.Info("played with companion")
+
+	return companion, nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+// lock locks the companion of the user, creating it as of registration on first care.
+func lock(ctx context.Context, tx *sqlx.Tx, userID int) (model.Companion, error) {
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO companions (user_id, updated_at) SELECT id, registered_at FROM users WHERE id = $1
+		 ON CONFLICT (user_id) DO NOTHING`,
+		userID,
+	)
+	if err != nil {
+		return model.Companion{}, err
+	}
+
+	var companion dbCompanion
+	err = tx.GetContext(ctx, &companion,
+		`SELECT `+companionColumns+`
+		 FROM companions c
+		 JOIN users u ON u.id = c.user_id
+		 LEFT JOIN classes cl ON cl.id = u.class_id
+		 WHERE c.user_id = $1
+		 FOR UPDATE OF c`,
+		userID,
+	)
+	if err != nil {
+		return model.Companion{}, err
+	}
+
+	return companion.toModel(), nil
+}
+
+func update(ctx context.Context, tx *sqlx.Tx, companion model.Companion) error {
+	_, err := tx.ExecContext(ctx,
+		`UPDATE companions SET hunger = $1, happiness = $2, fed_at = $3, played_at = $4, updated_at = $5 WHERE user_id = $6`,
+		companion.Hunger, companion.Happiness, nullableTime(companion.FedAt), nullableTime(companion.PlayedAt),
+		companion.UpdatedAt, companion.UserID,
+	)
+	return err
+}
+
+func getCompanion(ctx context.Context, q sqlx.QueryerContext, userID int) (model.Companion, error) {
+	var companion dbCompanion
+	err := sqlx.GetContext(ctx, q, &companion,
+		`SELECT `+companionColumns+`
+		 FROM users u
+		 LEFT JOIN classes cl ON cl.id = u.class_id
+		 LEFT JOIN companions c ON c.user_id = u.id
+		 WHERE u.id = $1`,
+		userID,
+	)
+	if err != nil {
+		return model.Companion{}, err
+	}
+
+	return companion.toModel(), nil
+}
+
+func nullableTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+
+	return t
+}
+
+// companionColumns fall back to a full and happy companion as of registration for users without a row.
+const companionColumns = `u.id AS user_id, COALESCE(u.class_id, 0) AS class_id, COALESCE(cl.name, '') AS animal,
+	COALESCE(c.hunger, 0) AS hunger, COALESCE(c.happiness, 100) AS happiness,
+	c.fed_at, c.played_at, COALESCE(c.updated_at, u.registered_at) AS updated_at`
+
+type dbCompanion struct {
+	UserID    int          `db:"user_id"`
+	ClassID   int          `db:"class_id"`
+	Animal    string       `db:"animal"`
+	Hunger    float64      `db:"hunger"`
+	Happiness float64      `db:"happiness"`
+	FedAt     sql.NullTime `db:"fed_at"`
+	PlayedAt  sql.NullTime `db:"played_at"`
+	UpdatedAt time.Time    `db:"updated_at"`
+}
+
+func (c dbCompanion) toModel() model.Companion {
+	return model.Companion{
+		UserID:    c.UserID,
+		ClassID:   c.ClassID,
+		Animal:    c.Animal,
+		Hunger:    c.Hunger,
+		Happiness: c.Happiness,
+		WellBeing: companions.WellBeing(model.Companion{Hunger: c.Hunger, Happiness: c.Happiness}),
+		FedAt:     c.FedAt.Time,
+		PlayedAt:  c.PlayedAt.Time,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
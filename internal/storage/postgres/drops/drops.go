@@ -0,0 +1,542 @@
+package drops
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/transactions"
+	"github.com/lib/pq"
+	"log/slog"
+	"time"
+)
+
+type Storage struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
+	return &Storage{
+		db:  db,
+		log: log,
+	}
+}
+
+// AddCampaign stores the campaign, its drops are spawned by Spawn.
+func (s *Storage) AddCampaign(ctx context.Context, campaign model.DropCampaign) (model.DropCampaign, error) {
+	op := "drops.AddCampaign"
+
+	log := s.log.With(slog.String("op", op), slog.Int("adminID", campaign.CreatedBy))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.DropCampaign{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `INSERT INTO drops_campaigns (name, budget, reward, claims_per_drop, rate, lifetime_seconds, starts_at, ends_at, created_by)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			  RETURNING ` + campaignColumns
+
+	var created dbCampaign
+	err = conn.QueryRowxContext(ctx, query,
+		campaign.Name,
+		campaign.Budget,
+		campaign.Reward,
+		campaign.ClaimsPerDrop,
+		campaign.Rate,
+		int(campaign.Lifetime/time.Second),
+		campaign.StartsAt,
+		campaign.EndsAt,
+		campaign.CreatedBy,
+	).StructScan(&created)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return model.DropCampaign{}, errs.ErrAdminNotFound
+		}
+
+		log.Error("failed to add campaign", slog.String("error", err.Error()))
+		return model.DropCampaign{}, err
+	}
+
+	log.Info("added campaign", slog.Int("id", created.ID))
+
+	return created.toModel(), nil
+}
+
+// StopCampaign ends the campaign at now, its drops still lying around expire at once.
+func (s *Storage) StopCampaign(ctx context.Context, id int, now time.Time) (model.DropCampaign, error) {
+	op := "drops.StopCampaign"
+
+	log := s.log.With(slog.String("op", op), slog.Int("id", id))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.DropCampaign{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.DropCampaign{}, err
+	}
+
+	var stopped dbCampaign
+	err = tx.QueryRowxContext(ctx,
+		`UPDATE drops_campaigns SET stopped_at = $1 WHERE id = $2 AND stopped_at IS NULL RETURNING `+campaignColumns,
+		now, id,
+	).StructScan(&stopped)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.DropCampaign{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			var exists bool
+			if err := conn.GetContext(ctx, &exists, `SELECT EXISTS (SELECT 1 FROM drops_campaigns WHERE id = $1)`, id); err != nil {
+				log.Error("failed to check campaign", slog.String("error", err.Error()))
+				return model.DropCampaign{}, err
+			}
+
+			if !exists {
+				return model.DropCampaign{}, errs.ErrDropCampaignNotFound
+			}
+			return model.DropCampaign{}, errs.ErrDropCampaignStopped
+		}
+
+		log.Error("failed to stop campaign", slog.String("error", err.Error()))
+		return model.DropCampaign{}, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE drops SET expires_at = $1 WHERE campaign_id = $2 AND appears_at < $1 AND expires_at > $1`,
+		now, id,
+	)
+	if err != nil {
+		log.Error("failed to expire drops", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.DropCampaign{}, err
+		}
+		return model.DropCampaign{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.DropCampaign{}, err
+	}
+
+	log.Info("stopped campaign")
+
+	return stopped.toModel(), nil
+}
+
+// GetCampaigns returns the campaigns, newest first. Only the ones running at now are returned if running is set.
+func (s *Storage) GetCampaigns(ctx context.Context, running bool, now time.Time, limit int) ([]model.DropCampaign, error) {
+	op := "drops.GetCampaigns"
+
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT ` + campaignColumns + ` FROM drops_campaigns
+			  WHERE NOT $1 OR (stopped_at IS NULL AND starts_at <= $2 AND ends_at > $2)
+			  ORDER BY created_at DESC, id DESC
+			  LIMIT $3`
+
+	var dbCampaigns []dbCampaign
+	if err := conn.SelectContext(ctx, &dbCampaigns, query, running, now, limit); err != nil {
+		log.Error("failed to get campaigns", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	campaigns := make([]model.DropCampaign, 0, len(dbCampaigns))
+	for _, c := range dbCampaigns {
+		campaigns = append(campaigns, c.toModel())
+	}
+
+	return campaigns, nil
+}
+
+// Spawn drops coins of the running campaign that lie around from now until expiresAt. The rest of
+// the budget has to cover the drop on top of everything the unexpired drops may still pay out.
+func (s *Storage) Spawn(ctx context.Context, campaignID int, now, expiresAt time.Time) (model.Drop, error) {
+	op := "drops.Spawn"
+
+	log := s.log.With(slog.String("op", op), slog.Int("campaignID", campaignID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Drop{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Drop{}, err
+	}
+
+	var campaign dbCampaign
+	err = tx.GetContext(ctx, &campaign, `SELECT `+campaignColumns+` FROM drops_campaigns WHERE id = $1 FOR UPDATE`, campaignID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Drop{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Drop{}, errs.ErrDropCampaignNotFound
+		}
+
+		log.Error("failed to lock campaign", slog.String("error", err.Error()))
+		return model.Drop{}, err
+	}
+
+	if campaign.StoppedAt.Valid || now.Before(campaign.StartsAt) || !now.Before(campaign.EndsAt) {
+		if err := tx.Rollback(); err != nil {
+			return model.Drop{}, err
+		}
+		return model.Drop{}, errs.ErrDropCampaignStopped
+	}
+
+	var outstanding float64
+	err = tx.GetContext(ctx, &outstanding,
+		`SELECT COALESCE(SUM((max_claims - claims) * reward), 0) FROM drops WHERE campaign_id = $1 AND expires_at > $2`,
+		campaignID, now,
+	)
+	if err != nil {
+		log.Error("failed to get outstanding rewards", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Drop{}, err
+		}
+		return model.Drop{}, err
+	}
+
+	if campaign.Budget-campaign.Spent-outstanding < campaign.Reward*float64(campaign.ClaimsPerDrop) {
+		if err := tx.Rollback(); err != nil {
+			return model.Drop{}, err
+		}
+		return model.Drop{}, errs.ErrDropBudgetExhausted
+	}
+
+	var dropID int
+	err = tx.QueryRowxContext(ctx,
+		`INSERT INTO drops (campaign_id, reward, max_claims, appears_at, expires_at) VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		campaignID, campaign.Reward, campaign.ClaimsPerDrop, now, expiresAt,
+	).Scan(&dropID)
+	if err != nil {
+		log.Error("failed to add drop", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Drop{}, err
+		}
+		return model.Drop{}, err
+	}
+
+	drop, err := getDrop(ctx, tx, dropID, 0)
+	if err != nil {
+		log.Error("failed to get drop", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Drop{}, err
+		}
+		return model.Drop{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Drop{}, err
+	}
+
+	log.Info("spawned drop", slog.Int("id", dropID))
+
+	return drop, nil
+}
+
+// Claim pays the reward of the drop to the user. The drop is locked while it is claimed, so only
+// its first MaxClaims users get the reward and nobody gets it twice.
+func (s *Storage) Claim(ctx context.Context, dropID, userID int, now time.Time) (model.Drop, error) {
+	op := "drops.Claim"
+
+	log := s.log.With(slog.String("op", op), slog.Int("dropID", dropID), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Drop{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Drop{}, err
+	}
+
+	var drop dbDrop
+	err = tx.GetContext(ctx, &drop, `SELECT `+dropColumns+` FROM `+dropTables+` WHERE d.id = $1 FOR UPDATE OF d`, dropID, userID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Drop{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Drop{}, errs.ErrDropNotFound
+		}
+
+		log.Error("failed to lock drop", slog.String("error", err.Error()))
+		return model.Drop{}, err
+	}
+
+	var claimErr error
+	switch {
+	case drop.Claimed:
+		claimErr = errs.ErrDropAlreadyClaimed
+	case now.Before(drop.AppearsAt):
+		claimErr = errs.ErrDropNotFound
+	case !now.Before(drop.ExpiresAt):
+		claimErr = errs.ErrDropExpired
+	case drop.Claims >= drop.MaxClaims:
+		claimErr = errs.ErrDropExhausted
+	}
+
+	if claimErr != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Drop{}, err
+		}
+		return model.Drop{}, claimErr
+	}
+
+	transactionID, err := transactions.Credit(ctx, tx, userID, drop.Reward, transactions.TreasureTypeID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Drop{}, err
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return model.Drop{}, errs.ErrUserNotFound
+		}
+
+		log.Error("failed to add transaction", slog.String("error", err.Error()))
+		return model.Drop{}, err
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO drops_claims (drop_id, user_id, transaction_id) VALUES ($1, $2, $3)`, dropID, userID, transactionID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Drop{}, err
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return model.Drop{}, errs.ErrDropAlreadyClaimed
+		}
+
+		log.Error("failed to add claim", slog.String("error", err.Error()))
+		return model.Drop{}, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE drops SET claims = claims + 1 WHERE id = $1`, dropID); err != nil {
+		log.Error("failed to count claim", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Drop{}, err
+		}
+		return model.Drop{}, err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE drops_campaigns SET spent = spent + $1 WHERE id = $2`, drop.Reward, drop.CampaignID)
+	if err != nil {
+		log.Error("failed to spend campaign budget", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Drop{}, err
+		}
+		return model.Drop{}, err
+	}
+
+	claimed, err := getDrop(ctx, tx, dropID, userID)
+	if err != nil {
+		log.Error("failed to get drop", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Drop{}, err
+		}
+		return model.Drop{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Drop{}, err
+	}
+
+	log.Info("claimed drop")
+
+	return claimed, nil
+}
+
+// GetActive returns the drops lying around at now that still have rewards left, soonest to expire first.
+// Claimed is set for the drops the user has already claimed.
+func (s *Storage) GetActive(ctx context.Context, userID int, now time.Time) ([]model.Drop, error) {
+	op := "drops.GetActive"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT ` + dropColumns + ` FROM ` + dropTables + `
+			  WHERE d.appears_at <= $1 AND d.expires_at > $1 AND d.claims < d.max_claims
+			  ORDER BY d.expires_at, d.id`
+
+	var dbDrops []dbDrop
+	if err := conn.SelectContext(ctx, &dbDrops, query, now, userID); err != nil {
+		log.Error("failed to get drops", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	drops := make([]model.Drop, 0, len(dbDrops))
+	for _, d := range dbDrops {
+		drops = append(drops, d.toModel())
+	}
+
+	return drops, nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+// getDrop returns the drop, Claimed tells whether the user claimed it.
+func getDrop(ctx context.Context, q sqlx.QueryerContext, id, userID int) (model.Drop, error) {
+	var drop dbDrop
+	if err := sqlx.GetContext(ctx, q, &drop, `SELECT `+dropColumns+` FROM `+dropTables+` WHERE d.id = $1`, id, userID); err != nil {
+		return model.Drop{}, err
+	}
+
+	return drop.toModel(), nil
+}
+
+const campaignColumns = `id, name, budget, spent, reward, claims_per_drop, rate, lifetime_seconds,
+	starts_at, ends_at, created_by, created_at, stopped_at`
+
+// dropColumns expects the ID of the user the claims are checked for as the second query parameter.
+const dropColumns = `d.id, d.campaign_id, c.name AS campaign_name, d.reward, d.max_claims, d.claims, d.appears_at, d.expires_at,
+	EXISTS (SELECT 1 FROM drops_claims dc WHERE dc.drop_id = d.id AND dc.user_id = $2) AS claimed`
+
+const dropTables = `drops d
+	JOIN drops_campaigns c ON c.id = d.campaign_id`
+
+type dbCampaign struct {
+	ID              int          `db:"id"`
+	Name            string       `db:"name"`
+	Budget          float64      `db:"budget"`
+	Spent           float64      `db:"spent"`
+	Reward          float64      `db:"reward"`
+	ClaimsPerDrop   int          `db:"claims_per_drop"`
+	Rate            float64      `db:"rate"`
+	LifetimeSeconds int          `db:"lifetime_seconds"`
+	StartsAt        time.Time    `db:"starts_at"`
+	EndsAt          time.Time    `db:"ends_at"`
+	CreatedBy       int          `db:"created_by"`
+	CreatedAt       time.Time    `db:"created_at"`
+	StoppedAt       sql.NullTime `db:"stopped_at"`
+}
+
+func (c dbCampaign) toModel() model.DropCampaign {
+	return model.DropCampaign{
+		ID:            c.ID,
+		Name:          c.Name,
+		Budget:        c.Budget,
+		Spent:         c.Spent,
+		Reward:        c.Reward,
+		ClaimsPerDrop: c.ClaimsPerDrop,
+		Rate:          c.Rate,
+		Lifetime:      time.Duration(c.LifetimeSeconds) * time.Second,
+		StartsAt:      c.StartsAt,
+		EndsAt:        c.EndsAt,
+		CreatedBy:     c.CreatedBy,
+		CreatedAt:     c.CreatedAt,
+		StoppedAt:     c.StoppedAt.Time,
+	}
+}
+
+type dbDrop struct {
+	ID           int       `db:"id"`
+	CampaignID   int       `db:"campaign_id"`
+	CampaignName string    `db:"campaign_name"`
+	Reward       float64   `db:"reward"`
+	MaxClaims    int       `db:"max_claims"`
+	Claims       int       `db:"claims"`
+	AppearsAt    time.Time `db:"appears_at"`
+	ExpiresAt    time.Time `db:"expires_at"`
+	Claimed      bool      `db:"claimed"`
+}
+
+func (d dbDrop) toModel() model.Drop {
+	return model.Drop{
+		ID:           d.ID,
+		CampaignID:   d.CampaignID,
+		CampaignName: d.CampaignName,
+		Reward:       d.Reward,
+		MaxClaims:    d.MaxClaims,
+		Claims:       d.Claims,
+		AppearsAt:    d.AppearsAt,
+		ExpiresAt:    d.ExpiresAt,
+		Claimed:      d.Claimed,
+	}
+}
@@ -0,0 +1,739 @@
+package duels
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/nullable"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/tasks"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/transactions"
+	"github.com/lib/pq"
+	"log/slog"
+	"time"
+)
+
+const (
+	RaceKindID  = 1
+	WagerKindID = 2
+)
+
+const (
+	PendingStatusID  = 1
+	ActiveStatusID   = 2
+	DeclinedStatusID = 3
+	ResolvedStatusID = 4
+	RefundedStatusID = 5
+)
+
+type Storage struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
+	return &Storage{
+		db:  db,
+		log: log,
+	}
+}
+
+// Create escrows the stake of the challenger and stores the duel as pending. The task of a race
+// must still be open to anyone, so both participants can go for it.
+func (s *Storage) Create(ctx context.Context, duel model.Duel) (model.Duel, error) {
+	op := "duels.Create"
+
+	log := s.log.With(slog.String("op", op), slog.Int("challengerID", duel.ChallengerID), slog.Int("opponentID", duel.OpponentID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Duel{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Duel{}, err
+	}
+
+	if duel.KindID == RaceKindID {
+		var open bool
+		err = tx.GetContext(ctx, &open,
+			`SELECT status_id = $2 AND for_group_id = $3 AND user_id IS NULL FROM tasks WHERE id = $1 FOR UPDATE`,
+			duel.TaskID, tasks.InProgressStatusID, tasks.AllGroupID,
+		)
+		if err != nil {
+			if err := tx.Rollback(); err != nil {
+				return model.Duel{}, err
+			}
+
+			if errors.Is(err, sql.ErrNoRows) {
+				return model.Duel{}, errs.ErrTaskNotFound
+			}
+
+			log.Error("failed to get task", slog.String("error", err.Error()))
+			return model.Duel{}, err
+		}
+
+		if !open {
+			if err := tx.Rollback(); err != nil {
+				return model.Duel{}, err
+			}
+			return model.Duel{}, errs.ErrDuelTaskUnavailable
+		}
+	}
+
+	transactionID, err := transactions.Debit(ctx, tx, duel.ChallengerID, duel.Stake, transactions.DuelStakeTypeID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Duel{}, err
+		}
+
+		if !errors.Is(err, errs.ErrInsufficientFunds) {
+			log.Error("failed to escrow stake", slog.String("error", err.Error()))
+		}
+		return model.Duel{}, err
+	}
+
+	query := `INSERT INTO duels (kind_id, challenger_id, opponent_id, stake, task_id, terms, respond_by, challenger_transaction_id)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			  RETURNING id`
+
+	err = tx.QueryRowxContext(ctx, query,
+		duel.KindID,
+		duel.ChallengerID,
+		duel.OpponentID,
+		duel.Stake,
+		nullable.ID(duel.TaskID),
+		duel.Terms,
+		duel.RespondBy,
+		transactionID,
+	).Scan(&duel.ID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Duel{}, err
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return model.Duel{}, errs.ErrUserNotFound
+		}
+
+		log.Error("failed to add duel", slog.String("error", err.Error()))
+		return model.Duel{}, err
+	}
+
+	created, err := getDuel(ctx, tx, duel.ID)
+	if err != nil {
+		log.Error("failed to get duel", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Duel{}, err
+		}
+		return model.Duel{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Duel{}, err
+	}
+
+	log.Info("created duel", slog.Int("id", duel.ID))
+
+	return created, nil
+}
+
+// Accept escrows the stake of the opponent and starts the duel, which has to be resolved by the deadline.
+// Only the opponent may accept, and only before the duel's response time is over at now.
+func (s *Storage) Accept(ctx context.Context, duelID, userID int, now, deadline time.Time) (model.Duel, error) {
+	op := "duels.Accept"
+
+	log := s.log.With(slog.String("op", op), slog.Int("duelID", duelID), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Duel{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Duel{}, err
+	}
+
+	duel, err := lockPending(ctx, tx, duelID, userID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Duel{}, err
+		}
+
+		if !errors.Is(err, errs.ErrDuelNotFound) && !errors.Is(err, errs.ErrDuelNotPending) {
+			log.Error("failed to lock duel", slog.String("error", err.Error()))
+		}
+		return model.Duel{}, err
+	}
+
+	if !now.Before(duel.RespondBy) {
+		if err := tx.Rollback(); err != nil {
+			return model.Duel{}, err
+		}
+		return model.Duel{}, errs.ErrDuelExpired
+	}
+
+	transactionID, err := transactions.Debit(ctx, tx, userID, duel.Stake, transactions.DuelStakeTypeID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Duel{}, err
+		}
+
+		if !errors.Is(err, errs.ErrInsufficientFunds) {
+			log.Error("failed to escrow stake", slog.String("error", err.Error()))
+		}
+		return model.Duel{}, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE duels SET status_id = $1, opponent_transaction_id = $2, deadline = $3, accepted_at = NOW() WHERE id = $4`,
+		ActiveStatusID, transactionID, deadline, duelID,
+	)
+	if err != nil {
+		log.Error("failed to accept duel", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Duel{}, err
+		}
+		return model.Duel{}, err
+	}
+
+	accepted, err := getDuel(ctx, tx, duelID)
+	if err != nil {
+		log.Error("failed to get duel", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Duel{}, err
+		}
+		return model.Duel{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Duel{}, err
+	}
+
+	log.Info("accepted duel")
+
+	return accepted, nil
+}
+
+// Decline turns the challenge down and refunds the stake of the challenger, only the opponent may decline.
+func (s *Storage) Decline(ctx context.Context, duelID, userID int) (model.Duel, error) {
+	op := "duels.Decline"
+
+	log := s.log.With(slog.String("op", op), slog.Int("duelID", duelID), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Duel{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Duel{}, err
+	}
+
+	duel, err := lockPending(ctx, tx, duelID, userID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Duel{}, err
+		}
+
+		if !errors.Is(err, errs.ErrDuelNotFound) && !errors.Is(err, errs.ErrDuelNotPending) {
+			log.Error("failed to lock duel", slog.String("error", err.Error()))
+		}
+		return model.Duel{}, err
+	}
+
+	if _, err := transactions.Credit(ctx, tx, duel.ChallengerID, duel.Stake, transactions.RefundTypeID); err != nil {
+		log.Error("failed to refund stake", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Duel{}, err
+		}
+		return model.Duel{}, err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE duels SET status_id = $1, resolved_at = NOW() WHERE id = $2`, DeclinedStatusID, duelID)
+	if err != nil {
+		log.Error("failed to decline duel", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Duel{}, err
+		}
+		return model.Duel{}, err
+	}
+
+	declined, err := getDuel(ctx, tx, duelID)
+	if err != nil {
+		log.Error("failed to get duel", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Duel{}, err
+		}
+		return model.Duel{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Duel{}, err
+	}
+
+	log.Info("declined duel")
+
+	return declined, nil
+}
+
+// Resolve pays both stakes of the active duel to the winner. Zero adminID means
+// the duel was resolved by the acceptance of its task.
+func (s *Storage) Resolve(ctx context.Context, duelID, winnerID, adminID int) (model.Duel, error) {
+	op := "duels.Resolve"
+
+	log := s.log.With(slog.String("op", op), slog.Int("duelID", duelID), slog.Int("winnerID", winnerID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Duel{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Duel{}, err
+	}
+
+	duel, err := lockDuel(ctx, tx, duelID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Duel{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Duel{}, errs.ErrDuelNotFound
+		}
+
+		log.Error("failed to lock duel", slog.String("error", err.Error()))
+		return model.Duel{}, err
+	}
+
+	if duel.StatusID != ActiveStatusID {
+		if err := tx.Rollback(); err != nil {
+			return model.Duel{}, err
+		}
+		return model.Duel{}, errs.ErrDuelNotActive
+	}
+
+	if winnerID != duel.ChallengerID && winnerID != duel.OpponentID {
+		if err := tx.Rollback(); err != nil {
+			return model.Duel{}, err
+		}
+		return model.Duel{}, errs.ErrNotDuelParticipant
+	}
+
+	transactionID, err := transactions.Credit(ctx, tx, winnerID, duel.Stake*2, transactions.DuelPayoutTypeID)
+	if err != nil {
+		log.Error("failed to pay winner", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Duel{}, err
+		}
+		return model.Duel{}, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE duels SET status_id = $1, winner_id = $2, resolved_by = $3, payout_transaction_id = $4, resolved_at = NOW() WHERE id = $5`,
+		ResolvedStatusID, winnerID, nullable.ID(adminID), transactionID, duelID,
+	)
+	if err != nil {
+		log.Error("failed to resolve duel", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Duel{}, err
+		}
+		return model.Duel{}, err
+	}
+
+	resolved, err := getDuel(ctx, tx, duelID)
+	if err != nil {
+		log.Error("failed to get duel", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Duel{}, err
+		}
+		return model.Duel{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Duel{}, err
+	}
+
+	log.Info("resolved duel")
+
+	return resolved, nil
+}
+
+// Refund closes the open duel without a winner and returns the escrowed stakes to their owners.
+func (s *Storage) Refund(ctx context.Context, duelID int) (model.Duel, error) {
+	op := "duels.Refund"
+
+	log := s.log.With(slog.String("op", op), slog.Int("duelID", duelID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Duel{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Duel{}, err
+	}
+
+	duel, err := lockDuel(ctx, tx, duelID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Duel{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Duel{}, errs.ErrDuelNotFound
+		}
+
+		log.Error("failed to lock duel", slog.String("error", err.Error()))
+		return model.Duel{}, err
+	}
+
+	refunds := []int{duel.ChallengerID}
+	switch duel.StatusID {
+	case PendingStatusID:
+	case ActiveStatusID:
+		refunds = append(refunds, duel.OpponentID)
+	default:
+		if err := tx.Rollback(); err != nil {
+			return model.Duel{}, err
+		}
+		return model.Duel{}, errs.ErrDuelNotOpen
+	}
+
+	for _, userID := range refunds {
+		if _, err := transactions.Credit(ctx, tx, userID, duel.Stake, transactions.RefundTypeID); err != nil {
+			log.Error("failed to refund stake", slog.Int("userID", userID), slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return model.Duel{}, err
+			}
+			return model.Duel{}, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE duels SET status_id = $1, resolved_at = NOW() WHERE id = $2`, RefundedStatusID, duelID)
+	if err != nil {
+		log.Error("failed to refund duel", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Duel{}, err
+		}
+		return model.Duel{}, err
+	}
+
+	refunded, err := getDuel(ctx, tx, duelID)
+	if err != nil {
+		log.Error("failed to get duel", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Duel{}, err
+		}
+		return model.Duel{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Duel{}, err
+	}
+
+	log.Info("refunded duel", slog.Int("refunds", len(refunds)))
+
+	return refunded, nil
+}
+
+// GetByID returns the duel with the names of both participants.
+func (s *Storage) GetByID(ctx context.Context, id int) (model.Duel, error) {
+	op := "duels.GetByID"
+
+	log := s.log.With(slog.String("op", op), slog.Int("id", id))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Duel{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	duel, err := getDuel(ctx, conn, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Duel{}, errs.ErrDuelNotFound
+		}
+
+		log.Error("failed to get duel", slog.String("error", err.Error()))
+		return model.Duel{}, err
+	}
+
+	return duel, nil
+}
+
+// GetOpenByTask returns the pending and active races for the task.
+func (s *Storage) GetOpenByTask(ctx context.Context, taskID int) ([]model.Duel, error) {
+	return s.get(ctx, "duels.GetOpenByTask",
+		`d.task_id = $1 AND d.status_id IN ($2, $3) ORDER BY d.id`,
+		taskID, PendingStatusID, ActiveStatusID,
+	)
+}
+
+// GetExpired returns the pending duels nobody answered and the active duels nobody won by now.
+func (s *Storage) GetExpired(ctx context.Context, now time.Time) ([]model.Duel, error) {
+	return s.get(ctx, "duels.GetExpired",
+		`(d.status_id = $1 AND d.respond_by <= $3) OR (d.status_id = $2 AND d.deadline <= $3) ORDER BY d.id`,
+		PendingStatusID, ActiveStatusID, now,
+	)
+}
+
+// GetByUser returns a page of the duels the user takes part in, newest first.
+// Zero statusID returns duels of any status.
+func (s *Storage) GetByUser(ctx context.Context, userID, statusID, limit, offset int) ([]model.Duel, error) {
+	return s.get(ctx, "duels.GetByUser",
+		`(d.challenger_id = $1 OR d.opponent_id = $1) AND ($2 = 0 OR d.status_id = $2)
+		 ORDER BY d.created_at DESC, d.id DESC LIMIT $3 OFFSET $4`,
+		userID, statusID, limit, offset,
+	)
+}
+
+// GetAll returns the duels of the status, newest first. Zero statusID returns duels of any status.
+func (s *Storage) GetAll(ctx context.Context, statusID, limit int) ([]model.Duel, error) {
+	return s.get(ctx, "duels.GetAll",
+		`($1 = 0 OR d.status_id = $1) ORDER BY d.created_at DESC, d.id DESC LIMIT $2`,
+		statusID, limit,
+	)
+}
+
+// GetRecord sums up the closed duels of the user that had been accepted.
+func (s *Storage) GetRecord(ctx context.Context, userID int) (model.DuelRecord, error) {
+	op := "duels.GetRecord"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.DuelRecord{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT
+			  COUNT(*) FILTER (WHERE status_id = $2 AND winner_id = $1) AS wins,
+			  COUNT(*) FILTER (WHERE status_id = $2 AND winner_id <> $1) AS losses,
+			  COUNT(*) FILTER (WHERE status_id = $3 AND accepted_at IS NOT NULL) AS refunded,
+			  COALESCE(SUM(CASE WHEN winner_id = $1 THEN stake ELSE -stake END) FILTER (WHERE status_id = $2), 0) AS net
+			  FROM duels
+			  WHERE challenger_id = $1 OR opponent_id = $1`
+
+	var record dbRecord
+	if err := conn.GetContext(ctx, &record, query, userID, ResolvedStatusID, RefundedStatusID); err != nil {
+		log.Error("failed to get record", slog.String("error", err.Error()))
+		return model.DuelRecord{}, err
+	}
+
+	return model.DuelRecord(record), nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+// get selects the duels matching the condition, which may also order and limit them.
+func (s *Storage) get(ctx context.Context, op, condition string, args ...interface{}) ([]model.Duel, error) {
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var dbDuels []dbDuel
+	if err := conn.SelectContext(ctx, &dbDuels, `SELECT `+duelColumns+` FROM `+duelTables+` WHERE `+condition, args...); err != nil {
+		log.Error("failed to get duels", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	duels := make([]model.Duel, 0, len(dbDuels))
+	for _, d := range dbDuels {
+		duels = append(duels, d.toModel())
+	}
+
+	return duels, nil
+}
+
+// lockDuel locks the duel until the end of the transaction, sql.ErrNoRows is returned as is.
+func lockDuel(ctx context.Context, tx *sqlx.Tx, id int) (model.Duel, error) {
+	var duel dbDuel
+	if err := tx.GetContext(ctx, &duel, `SELECT `+duelColumns+` FROM `+duelTables+` WHERE d.id = $1 FOR UPDATE OF d`, id); err != nil {
+		return model.Duel{}, err
+	}
+
+	return duel.toModel(), nil
+}
+
+// lockPending locks the pending duel the user is challenged to.
+func lockPending(ctx context.Context, tx *sqlx.Tx, id, opponentID int) (model.Duel, error) {
+	duel, err := lockDuel(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Duel{}, errs.ErrDuelNotFound
+		}
+		return model.Duel{}, err
+	}
+
+	if duel.OpponentID != opponentID {
+		return model.Duel{}, errs.ErrDuelNotFound
+	}
+
+	if duel.StatusID != PendingStatusID {
+		return model.Duel{}, errs.ErrDuelNotPending
+	}
+
+	return duel, nil
+}
+
+func getDuel(ctx context.Context, q sqlx.QueryerContext, id int) (model.Duel, error) {
+	var duel dbDuel
+	if err := sqlx.GetContext(ctx, q, &duel, `SELECT `+duelColumns+` FROM `+duelTables+` WHERE d.id = $1`, id); err != nil {
+		return model.Duel{}, err
+	}
+
+	return duel.toModel(), nil
+}
+
+const duelColumns = `d.id, d.kind_id, d.challenger_id, c.username AS challenger_name, d.opponent_id, o.username AS opponent_name,
+	d.stake, COALESCE(d.task_id, 0) AS task_id, d.terms, d.status_id, COALESCE(d.winner_id, 0) AS winner_id,
+	COALESCE(d.resolved_by, 0) AS resolved_by, d.respond_by, d.deadline, d.created_at, d.accepted_at, d.resolved_at`
+
+const duelTables = `duels d
+	JOIN users c ON c.id = d.challenger_id
+	JOIN users o ON o.id = d.opponent_id`
+
+type dbDuel struct {
+	ID             int          `db:"id"`
+	KindID         int          `db:"kind_id"`
+	ChallengerID   int          `db:"challenger_id"`
+	ChallengerName string       `db:"challenger_name"`
+	OpponentID     int          `db:"opponent_id"`
+	OpponentName   string       `db:"opponent_name"`
+	Stake          float64      `db:"stake"`
+	TaskID         int          `db:"task_id"`
+	Terms          string       `db:"terms"`
+	StatusID       int          `db:"status_id"`
+	WinnerID       int          `db:"winner_id"`
+	ResolvedBy     int          `db:"resolved_by"`
+	RespondBy      time.Time    `db:"respond_by"`
+	Deadline       sql.NullTime `db:"deadline"`
+	CreatedAt      time.Time    `db:"created_at"`
+	AcceptedAt     sql.NullTime `db:"accepted_at"`
+	ResolvedAt     sql.NullTime `db:"resolved_at"`
+}
+
+func (d dbDuel) toModel() model.Duel {
+	return model.Duel{
+		ID:             d.ID,
+		KindID:         d.KindID,
+		ChallengerID:   d.ChallengerID,
+		ChallengerName: d.ChallengerName,
+		OpponentID:     d.OpponentID,
+		OpponentName:   d.OpponentName,
+		Stake:          d.Stake,
+		TaskID:         d.TaskID,
+		Terms:          d.Terms,
+		StatusID:       d.StatusID,
+		WinnerID:       d.WinnerID,
+		ResolvedBy:     d.ResolvedBy,
+		RespondBy:      d.RespondBy,
+		Deadline:       d.Deadline.Time,
+		CreatedAt:      d.CreatedAt,
+		AcceptedAt:     d.AcceptedAt.Time,
+		ResolvedAt:     d.ResolvedAt.Time,
+	}
+}
+
+type dbRecord struct {
+	Wins     int     `db:"wins"`
+	Losses   int     `db:"losses"`
+	Refunded int     `db:"refunded"`
+	Net      float64 `db:"net"`
+}
@@ -3,11 +3,184 @@ package errors
 import "errors"
 
 var (
-	ErrUserExists   = errors.New("user already exists")
-	ErrUserNotFound = errors.New("user not found")
+	ErrUserExists    = errors.New("user already exists")
+	ErrUserNotFound  = errors.New("user not found")
+	ErrClassNotFound = errors.New("class not found")
 )
 
 var (
 	ErrAdminExists   = errors.New("admin already exists")
 	ErrAdminNotFound = errors.New("admin not found")
 )
+
+var (
+	ErrTaskNotFound      = errors.New("task not found")
+	ErrTaskUserRequired  = errors.New("user_id must be set for tasks in the user group")
+	ErrTaskNotWaiting    = errors.New("task is not waiting for acceptance")
+	ErrTaskClosed        = errors.New("task is already completed or cancelled")
+	ErrTaskNotInProgress = errors.New("task is not in progress by the user")
+)
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+	ErrDelegationNotFound   = errors.New("delegation not found")
+)
+
+var (
+	ErrApprovalNotFound = errors.New("approval not found")
+	ErrApprovalPending  = errors.New("task already has a pending approval")
+	ErrSelfApproval     = errors.New("approval must be decided by another admin")
+	ErrApprovalStale    = errors.New("task is no longer waiting for this approval")
+)
+
+var (
+	ErrBudgetExceeded      = errors.New("budget exceeded")
+	ErrBudgetBelowUsage    = errors.New("budget is lower than already reserved and spent coins")
+	ErrBudgetOwnerNotFound = errors.New("budget owner not found")
+	ErrBudgetOverReleased  = errors.New("budget releases more coins than are reserved")
+)
+
+var (
+	ErrInterceptCooldown    = errors.New("intercept is on cooldown")
+	ErrVictimProtected      = errors.New("user was intercepted recently")
+	ErrTaskNotInterceptable = errors.New("task is not in progress by another user")
+	ErrInsufficientFunds    = errors.New("insufficient funds")
+)
+
+var (
+	ErrIntelNotFound = errors.New("intel not found")
+	ErrIntelActive   = errors.New("intel on this user is still active")
+)
+
+var (
+	ErrLevelTooLow      = errors.New("level is too low")
+	ErrXPAlreadyAwarded = errors.New("xp was already awarded")
+)
+
+var (
+	ErrAchievementAlreadyAwarded = errors.New("achievement was already awarded")
+)
+
+var (
+	ErrUnknownMetric = errors.New("unknown leaderboard metric")
+	ErrUserNotRanked = errors.New("user is not on the leaderboard")
+)
+
+var (
+	ErrSeasonNotFound         = errors.New("season not found")
+	ErrSeasonOverlap          = errors.New("season overlaps another season")
+	ErrSeasonAlreadyFinalized = errors.New("season was already finalized")
+)
+
+var (
+	ErrAlreadyCheckedIn = errors.New("already checked in today")
+	ErrShopItemNotFound = errors.New("shop item not found")
+	ErrShopItemSoldOut  = errors.New("shop item is sold out")
+)
+
+var (
+	ErrKudosAllowanceExceeded = errors.New("kudos allowance exceeded")
+)
+
+var (
+	ErrTeamNotFound                  = errors.New("team not found")
+	ErrTeamNameTaken                 = errors.New("team name is already taken")
+	ErrTeamFull                      = errors.New("team is full")
+	ErrAlreadyInTeam                 = errors.New("user is already in a team")
+	ErrNotTeamMember                 = errors.New("user is not a member of the team")
+	ErrNotTeamLeader                 = errors.New("user is not the leader of the team")
+	ErrLastTeamMember                = errors.New("the last member can not leave the team")
+	ErrInvitationNotFound            = errors.New("invitation not found")
+	ErrInvitationPending             = errors.New("user already has a pending invitation to the team")
+	ErrTeamWalletInsufficient        = errors.New("not enough coins in the team wallet")
+	ErrTeamTaskNotFound              = errors.New("team task not found")
+	ErrTeamTaskAlreadySubmitted      = errors.New("team task was already submitted")
+	ErrTeamTaskAlreadyCompleted      = errors.New("team task was already completed by the team")
+	ErrTeamCompletionNotFound        = errors.New("team task completion not found")
+	ErrTeamCompletionAlreadyAccepted = errors.New("team task completion was already accepted")
+)
+
+var (
+	ErrQuestNotFound         = errors.New("quest not found")
+	ErrQuestAlreadyPublished = errors.New("quest was already published")
+	ErrQuestAlreadyStarted   = errors.New("quest was already started")
+	ErrQuestStepNotFound     = errors.New("task is not an open quest step")
+)
+
+var (
+	ErrDuelNotFound        = errors.New("duel not found")
+	ErrDuelNotPending      = errors.New("duel is not waiting for a response")
+	ErrDuelNotActive       = errors.New("duel is not active")
+	ErrDuelNotOpen         = errors.New("duel is already closed")
+	ErrDuelExpired         = errors.New("duel response time is over")
+	ErrNotDuelParticipant  = errors.New("user does not take part in the duel")
+	ErrDuelTaskUnavailable = errors.New("task is not open to both participants")
+)
+
+var (
+	ErrBountyNotFound         = errors.New("bounty not found")
+	ErrBountyNotOpen          = errors.New("bounty is not open")
+	ErrBountyNotClaimed       = errors.New("bounty is not claimed")
+	ErrBountyNotSubmitted     = errors.New("bounty is not submitted")
+	ErrBountyNotDisputable    = errors.New("bounty can not be disputed")
+	ErrNotBountyPoster        = errors.New("user is not the poster of the bounty")
+	ErrNotBountyHunter        = errors.New("user is not the hunter of the bounty")
+	ErrOwnBounty              = errors.New("user can not claim own bounty")
+	ErrNotBountyParticipant   = errors.New("user neither posted nor claimed the bounty")
+	ErrDisputeNotFound        = errors.New("dispute not found")
+	ErrDisputeAlreadyResolved = errors.New("dispute was already resolved")
+)
+
+var (
+	ErrDropCampaignNotFound = errors.New("drop campaign not found")
+	ErrDropCampaignStopped  = errors.New("drop campaign is stopped")
+	ErrDropBudgetExhausted  = errors.New("drop campaign budget is exhausted")
+	ErrDropNotFound         = errors.New("drop not found")
+	ErrDropExpired          = errors.New("drop is expired")
+	ErrDropExhausted        = errors.New("drop was already claimed by enough users")
+	ErrDropAlreadyClaimed   = errors.New("drop was already claimed by the user")
+)
+
+var (
+	ErrRaffleNotFound    = errors.New("raffle not found")
+	ErrRaffleNotOpen     = errors.New("raffle is not open")
+	ErrRaffleSalesClosed = errors.New("raffle ticket sales are closed")
+	ErrRaffleNotDue      = errors.New("raffle is not due to be drawn")
+	ErrTicketLimit       = errors.New("ticket limit per user exceeded")
+)
+
+var (
+	ErrEventNotFound        = errors.New("event not found")
+	ErrEventCancelled       = errors.New("event is cancelled")
+	ErrEventOver            = errors.New("event is over")
+	ErrEventFull            = errors.New("event is full")
+	ErrAlreadyRSVPed        = errors.New("user has already RSVPed to the event")
+	ErrNotRSVPed            = errors.New("user has not RSVPed to the event")
+	ErrEventAlreadyAttended = errors.New("user has already checked in to the event")
+	ErrEventCodeNotFound    = errors.New("event code not found")
+	ErrEventCodeExpired     = errors.New("event code is expired")
+	ErrEventCodeAlreadyUsed = errors.New("event code was already used")
+)
+
+var (
+	ErrQuizNotFound         = errors.New("quiz not found")
+	ErrQuizClosed           = errors.New("quiz is closed")
+	ErrQuizAlreadySubmitted = errors.New("user has already submitted the quiz")
+	ErrQuizNotSubmitted     = errors.New("user has not submitted the quiz")
+)
+
+var (
+	ErrBusinessNotFound     = errors.New("business not found")
+	ErrBusinessAlreadyOwned = errors.New("business is already owned")
+	ErrNotBusinessOwner     = errors.New("user does not own the business")
+	ErrRecipeNotFound       = errors.New("recipe not found")
+	ErrResourceNotFound     = errors.New("resource not found")
+	ErrNotEnoughResources   = errors.New("not enough resources")
+	ErrStorageFull          = errors.New("storage is full")
+	ErrProductionNotDue     = errors.New("business is not due to produce")
+)
+
+var (
+	ErrNotFood          = errors.New("resource is not food")
+	ErrCompanionTooSoon = errors.New("companion does not want to play yet")
+)
@@ -0,0 +1,725 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/budgets"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/nullable"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/transactions"
+	"github.com/lib/pq"
+	"log/slog"
+	"time"
+)
+
+type Storage struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
+	return &Storage{
+		db:  db,
+		log: log,
+	}
+}
+
+func (s *Storage) Create(ctx context.Context, event model.Event) (model.Event, error) {
+	op := "events.Create"
+
+	log := s.log.With(slog.String("op", op), slog.Int("adminID", event.CreatedBy))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Event{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `INSERT INTO events (title, description, location, starts_at, ends_at, capacity, reward, created_by, budget_id)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			  RETURNING id`
+
+	var id int
+	err = conn.QueryRowxContext(ctx, query,
+		event.Title,
+		event.Description,
+		event.Location,
+		event.StartsAt,
+		event.EndsAt,
+		event.Capacity,
+		event.Reward,
+		event.CreatedBy,
+		nullable.ID(event.BudgetID),
+	).Scan(&id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return model.Event{}, errs.ErrAdminNotFound
+		}
+
+		log.Error("failed to add event", slog.String("error", err.Error()))
+		return model.Event{}, err
+	}
+
+	created, err := getEvent(ctx, conn, id, 0)
+	if err != nil {
+		log.Error("failed to get event", slog.String("error", err.Error()))
+		return model.Event{}, err
+	}
+
+	log.Info("created event", slog.Int("id", id))
+
+	return created, nil
+}
+
+// Cancel calls off the event that is not over yet by now.
+func (s *Storage) Cancel(ctx context.Context, id int, now time.Time) (model.Event, error) {
+	op := "events.Cancel"
+
+	log := s.log.With(slog.String("op", op), slog.Int("id", id))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Event{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Event{}, err
+	}
+
+	event, err := lockEvent(ctx, tx, id, now)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Event{}, err
+		}
+		return model.Event{}, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE events SET cancelled_at = $1 WHERE id = $2`, now, id); err != nil {
+		log.Error("failed to cancel event", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Event{}, err
+		}
+		return model.Event{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Event{}, err
+	}
+
+	event.CancelledAt = now
+
+	log.Info("cancelled event")
+
+	return event, nil
+}
+
+// RSVP signs the user up for the event, as long as it is not over and has room left.
+func (s *Storage) RSVP(ctx context.Context, eventID, userID int, now time.Time) (model.Event, error) {
+	op := "events.RSVP"
+
+	log := s.log.With(slog.String("op", op), slog.Int("eventID", eventID), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Event{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Event{}, err
+	}
+
+	event, err := lockEvent(ctx, tx, eventID, now)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Event{}, err
+		}
+		return model.Event{}, err
+	}
+
+	if event.Capacity > 0 {
+		// counted after the lock, so the RSVPs committed while waiting for it are seen too
+		var rsvps int
+		if err := tx.GetContext(ctx, &rsvps, `SELECT COUNT(*) FROM events_rsvps WHERE event_id = $1`, eventID); err != nil {
+			log.Error("failed to count RSVPs", slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return model.Event{}, err
+			}
+			return model.Event{}, err
+		}
+
+		if rsvps >= event.Capacity {
+			if err := tx.Rollback(); err != nil {
+				return model.Event{}, err
+			}
+			return model.Event{}, errs.ErrEventFull
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO events_rsvps (event_id, user_id, created_at) VALUES ($1, $2, $3)`, eventID, userID, now)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Event{}, err
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			switch pqErr.Code {
+			case "23505":
+				return model.Event{}, errs.ErrAlreadyRSVPed
+			case "23503":
+				return model.Event{}, errs.ErrUserNotFound
+			}
+		}
+
+		log.Error("failed to add RSVP", slog.String("error", err.Error()))
+		return model.Event{}, err
+	}
+
+	updated, err := getEvent(ctx, tx, eventID, userID)
+	if err != nil {
+		log.Error("failed to get event", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Event{}, err
+		}
+		return model.Event{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Event{}, err
+	}
+
+	log.Info("RSVPed to event")
+
+	return updated, nil
+}
+
+// CancelRSVP frees the place of the user who has not checked in yet.
+func (s *Storage) CancelRSVP(ctx context.Context, eventID, userID int) error {
+	op := "events.CancelRSVP"
+
+	log := s.log.With(slog.String("op", op), slog.Int("eventID", eventID), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var checkedInAt sql.NullTime
+	err = conn.QueryRowxContext(ctx,
+		`DELETE FROM events_rsvps WHERE event_id = $1 AND user_id = $2 AND checked_in_at IS NULL RETURNING checked_in_at`,
+		eventID, userID,
+	).Scan(&checkedInAt)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Error("failed to delete RSVP", slog.String("error", err.Error()))
+			return err
+		}
+
+		var exists bool
+		if err := conn.GetContext(ctx, &exists, `SELECT EXISTS (SELECT 1 FROM events_rsvps WHERE event_id = $1 AND user_id = $2)`, eventID, userID); err != nil {
+			log.Error("failed to check RSVP", slog.String("error", err.Error()))
+			return err
+		}
+
+		if exists {
+			return errs.ErrEventAlreadyAttended
+		}
+		return errs.ErrNotRSVPed
+	}
+
+	log.Info("cancelled RSVP")
+
+	return nil
+}
+
+// AddCode adds a check-in code for the event that is not over yet by now.
+func (s *Storage) AddCode(ctx context.Context, code model.EventCode, now time.Time) (model.EventCode, error) {
+	op := "events.AddCode"
+
+	log := s.log.With(slog.String("op", op), slog.Int("eventID", code.EventID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.EventCode{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.EventCode{}, err
+	}
+
+	if _, err := lockEvent(ctx, tx, code.EventID, now); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.EventCode{}, err
+		}
+		return model.EventCode{}, err
+	}
+
+	query := `INSERT INTO events_codes (event_id, one_time, expires_at, created_by, created_at)
+			  VALUES ($1, $2, $3, $4, $5)
+			  RETURNING id`
+
+	err = tx.QueryRowxContext(ctx, query, code.EventID, code.OneTime, code.ExpiresAt, code.CreatedBy, now).Scan(&code.ID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.EventCode{}, err
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return model.EventCode{}, errs.ErrAdminNotFound
+		}
+
+		log.Error("failed to add code", slog.String("error", err.Error()))
+		return model.EventCode{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.EventCode{}, err
+	}
+
+	code.CreatedAt = now
+
+	log.Info("added code", slog.Int("codeID", code.ID))
+
+	return code, nil
+}
+
+// CheckIn checks the user in to the event with the code and pays the reward. The user must have RSVPed,
+// and the code must belong to the event, be unexpired and, if it is one-time, unused.
+func (s *Storage) CheckIn(ctx context.Context, eventID, codeID, userID int, now time.Time) (model.Event, error) {
+	op := "events.CheckIn"
+
+	log := s.log.With(slog.String("op", op), slog.Int("eventID", eventID), slog.Int("codeID", codeID), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Event{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Event{}, err
+	}
+
+	var code struct {
+		EventID   int          `db:"event_id"`
+		OneTime   bool         `db:"one_time"`
+		ExpiresAt time.Time    `db:"expires_at"`
+		UsedAt    sql.NullTime `db:"used_at"`
+	}
+	err = tx.GetContext(ctx, &code, `SELECT event_id, one_time, expires_at, used_at FROM events_codes WHERE id = $1 FOR UPDATE`, codeID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Event{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Event{}, errs.ErrEventCodeNotFound
+		}
+
+		log.Error("failed to get code", slog.String("error", err.Error()))
+		return model.Event{}, err
+	}
+
+	var codeErr error
+	switch {
+	case code.EventID != eventID:
+		codeErr = errs.ErrEventCodeNotFound
+	case !now.Before(code.ExpiresAt):
+		codeErr = errs.ErrEventCodeExpired
+	case code.OneTime && code.UsedAt.Valid:
+		codeErr = errs.ErrEventCodeAlreadyUsed
+	}
+
+	if codeErr != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Event{}, err
+		}
+		return model.Event{}, codeErr
+	}
+
+	event, err := lockEvent(ctx, tx, eventID, now)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Event{}, err
+		}
+		return model.Event{}, err
+	}
+
+	var checkedInAt sql.NullTime
+	err = tx.GetContext(ctx, &checkedInAt, `SELECT checked_in_at FROM events_rsvps WHERE event_id = $1 AND user_id = $2 FOR UPDATE`, eventID, userID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Event{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Event{}, errs.ErrNotRSVPed
+		}
+
+		log.Error("failed to get RSVP", slog.String("error", err.Error()))
+		return model.Event{}, err
+	}
+
+	if checkedInAt.Valid {
+		if err := tx.Rollback(); err != nil {
+			return model.Event{}, err
+		}
+		return model.Event{}, errs.ErrEventAlreadyAttended
+	}
+
+	var transactionID sql.NullInt64
+	if event.Reward > 0 {
+		id, err := transactions.Credit(ctx, tx, userID, event.Reward, transactions.EventRewardTypeID)
+		if err != nil {
+			log.Error("failed to pay reward", slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return model.Event{}, err
+			}
+			return model.Event{}, err
+		}
+
+		transactionID = sql.NullInt64{Int64: int64(id), Valid: true}
+
+		if event.BudgetID != 0 {
+			if err := budgets.Spend(ctx, tx, event.BudgetID, 0, event.Reward); err != nil {
+				log.Error("failed to spend budget", slog.String("error", err.Error()))
+				if err := tx.Rollback(); err != nil {
+					return model.Event{}, err
+				}
+				return model.Event{}, err
+			}
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE events_rsvps SET checked_in_at = $1, transaction_id = $2 WHERE event_id = $3 AND user_id = $4`,
+		now, transactionID, eventID, userID,
+	)
+	if err != nil {
+		log.Error("failed to check in", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Event{}, err
+		}
+		return model.Event{}, err
+	}
+
+	if code.OneTime {
+		_, err := tx.ExecContext(ctx, `UPDATE events_codes SET used_by = $1, used_at = $2 WHERE id = $3`, userID, now, codeID)
+		if err != nil {
+			log.Error("failed to use code", slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return model.Event{}, err
+			}
+			return model.Event{}, err
+		}
+	}
+
+	attended, err := getEvent(ctx, tx, eventID, userID)
+	if err != nil {
+		log.Error("failed to get event", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Event{}, err
+		}
+		return model.Event{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Event{}, err
+	}
+
+	log.Info("checked in to event")
+
+	return attended, nil
+}
+
+// GetByID returns the event with the RSVP of the user.
+func (s *Storage) GetByID(ctx context.Context, id, userID int) (model.Event, error) {
+	op := "events.GetByID"
+
+	log := s.log.With(slog.String("op", op), slog.Int("id", id))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Event{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	event, err := getEvent(ctx, conn, id, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Event{}, errs.ErrEventNotFound
+		}
+
+		log.Error("failed to get event", slog.String("error", err.Error()))
+		return model.Event{}, err
+	}
+
+	return event, nil
+}
+
+// GetUpcoming returns a page of the events that are neither cancelled nor over by now, the soonest first.
+func (s *Storage) GetUpcoming(ctx context.Context, userID int, now time.Time, limit, offset int) ([]model.Event, error) {
+	return s.get(ctx, "events.GetUpcoming",
+		`e.cancelled_at IS NULL AND e.ends_at > $2 ORDER BY e.starts_at, e.id LIMIT $3 OFFSET $4`,
+		userID, now, limit, offset,
+	)
+}
+
+// GetAll returns a page of all the events, the latest first.
+func (s *Storage) GetAll(ctx context.Context, limit, offset int) ([]model.Event, error) {
+	return s.get(ctx, "events.GetAll",
+		`TRUE ORDER BY e.starts_at DESC, e.id DESC LIMIT $2 OFFSET $3`,
+		0, limit, offset,
+	)
+}
+
+// GetRSVPs returns the users who RSVPed to the event, in the order they did.
+func (s *Storage) GetRSVPs(ctx context.Context, eventID int) ([]model.EventRSVP, error) {
+	op := "events.GetRSVPs"
+
+	log := s.log.With(slog.String("op", op), slog.Int("eventID", eventID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT r.event_id, r.user_id, u.username, r.created_at, r.checked_in_at
+			  FROM events_rsvps r
+			  JOIN users u ON u.id = r.user_id
+			  WHERE r.event_id = $1
+			  ORDER BY r.created_at, r.user_id`
+
+	var dbRSVPs []dbRSVP
+	if err := conn.SelectContext(ctx, &dbRSVPs, query, eventID); err != nil {
+		log.Error("failed to get RSVPs", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	rsvps := make([]model.EventRSVP, 0, len(dbRSVPs))
+	for _, r := range dbRSVPs {
+		rsvps = append(rsvps, r.toModel())
+	}
+
+	return rsvps, nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+// get selects the events matching the condition, which may also order and limit them.
+// The first argument is the ID of the user whose RSVP is fetched.
+func (s *Storage) get(ctx context.Context, op, condition string, args ...interface{}) ([]model.Event, error) {
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var dbEvents []dbEvent
+	if err := conn.SelectContext(ctx, &dbEvents, `SELECT `+eventColumns+` FROM `+eventTables+` WHERE `+condition, args...); err != nil {
+		log.Error("failed to get events", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	events := make([]model.Event, 0, len(dbEvents))
+	for _, e := range dbEvents {
+		events = append(events, e.toModel())
+	}
+
+	return events, nil
+}
+
+// lockEvent locks the event until the end of the transaction. It fails unless the event exists
+// and is neither cancelled nor over by now.
+func lockEvent(ctx context.Context, tx *sqlx.Tx, id int, now time.Time) (model.Event, error) {
+	var event dbEvent
+	if err := tx.GetContext(ctx, &event, `SELECT `+eventColumns+` FROM `+eventTables+` WHERE e.id = $2 FOR UPDATE OF e`, 0, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Event{}, errs.ErrEventNotFound
+		}
+		return model.Event{}, err
+	}
+
+	switch {
+	case event.CancelledAt.Valid:
+		return model.Event{}, errs.ErrEventCancelled
+	case !now.Before(event.EndsAt):
+		return model.Event{}, errs.ErrEventOver
+	}
+
+	return event.toModel(), nil
+}
+
+func getEvent(ctx context.Context, q sqlx.QueryerContext, id, userID int) (model.Event, error) {
+	var event dbEvent
+	if err := sqlx.GetContext(ctx, q, &event, `SELECT `+eventColumns+` FROM `+eventTables+` WHERE e.id = $2`, userID, id); err != nil {
+		return model.Event{}, err
+	}
+
+	return event.toModel(), nil
+}
+
+// eventColumns expects the ID of the user whose RSVP is fetched as the first query parameter.
+const eventColumns = `e.id, e.title, e.description, e.location, e.starts_at, e.ends_at, e.capacity, e.reward,
+	(SELECT COUNT(*) FROM events_rsvps r WHERE r.event_id = e.id) AS rsvps,
+	(SELECT COUNT(*) FROM events_rsvps r WHERE r.event_id = e.id AND r.checked_in_at IS NOT NULL) AS checked_in,
+	e.created_by, COALESCE(e.budget_id, 0) AS budget_id, e.created_at, e.cancelled_at, m.user_id IS NOT NULL AS rsvped, m.checked_in_at AS my_checked_in_at`
+
+const eventTables = `events e
+	LEFT JOIN events_rsvps m ON m.event_id = e.id AND m.user_id = $1`
+
+type dbEvent struct {
+	ID            int          `db:"id"`
+	Title         string       `db:"title"`
+	Description   string       `db:"description"`
+	Location      string       `db:"location"`
+	StartsAt      time.Time    `db:"starts_at"`
+	EndsAt        time.Time    `db:"ends_at"`
+	Capacity      int          `db:"capacity"`
+	Reward        float64      `db:"reward"`
+	RSVPs         int          `db:"rsvps"`
+	CheckedIn     int          `db:"checked_in"`
+	CreatedBy     int          `db:"created_by"`
+	BudgetID      int          `db:"budget_id"`
+	CreatedAt     time.Time    `db:"created_at"`
+	CancelledAt   sql.NullTime `db:"cancelled_at"`
+	RSVPed        bool         `db:"rsvped"`
+	MyCheckedInAt sql.NullTime `db:"my_checked_in_at"`
+}
+
+func (e dbEvent) toModel() model.Event {
+	return model.Event{
+		ID:          e.ID,
+		Title:       e.Title,
+		Description: e.Description,
+		Location:    e.Location,
+		StartsAt:    e.StartsAt,
+		EndsAt:      e.EndsAt,
+		Capacity:    e.Capacity,
+		Reward:      e.Reward,
+		RSVPs:       e.RSVPs,
+		CheckedIn:   e.CheckedIn,
+		CreatedBy:   e.CreatedBy,
+		BudgetID:    e.BudgetID,
+		CreatedAt:   e.CreatedAt,
+		CancelledAt: e.CancelledAt.Time,
+		RSVPed:      e.RSVPed,
+		CheckedInAt: e.MyCheckedInAt.Time,
+	}
+}
+
+type dbRSVP struct {
+	EventID     int          `db:"event_id"`
+	UserID      int          `db:"user_id"`
+	Username    string       `db:"username"`
+	CreatedAt   time.Time    `db:"created_at"`
+	CheckedInAt sql.NullTime `db:"checked_in_at"`
+}
+
+func (r dbRSVP) toModel() model.EventRSVP {
+	return model.EventRSVP{
+		EventID:     r.EventID,
+		UserID:      r.UserID,
+		Username:    r.Username,
+		CreatedAt:   r.CreatedAt,
+		CheckedInAt: r.CheckedInAt.Time,
+	}
+}
@@ -0,0 +1,227 @@
+package intel
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/transactions"
+	"log/slog"
+	"time"
+)
+
+type Storage struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
+	return &Storage{
+		db:  db,
+		log: log,
+	}
+}
+
+// Buy charges the fee and records the purchase in one transaction. A buyer may hold
+// only one active purchase per target, so the fee is never charged twice for the same window.
+func (s *Storage) Buy(ctx context.Context, intel model.Intel) (model.Intel, error) {
+	op := "intel.Buy"
+
+	log := s.log.With(slog.String("op", op), slog.Int("buyerID", intel.BuyerID), slog.Int("targetID", intel.TargetID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Intel{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Intel{}, err
+	}
+
+	// the balance of the buyer is locked first, so two purchases of the same intel can not both pass the check below
+	var balance float64
+	err = tx.GetContext(ctx, &balance, `SELECT balance FROM balances WHERE user_id = $1 FOR UPDATE`, intel.BuyerID)
+	if err != nil {
+		log.Error("failed to get buyer balance", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Intel{}, err
+		}
+		return model.Intel{}, err
+	}
+
+	var active bool
+	err = tx.GetContext(ctx, &active,
+		`SELECT EXISTS (SELECT 1 FROM intel_purchases WHERE buyer_id = $1 AND target_id = $2 AND expires_at > $3)`,
+		intel.BuyerID, intel.TargetID, time.Now(),
+	)
+	if err != nil {
+		log.Error("failed to check active intel", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Intel{}, err
+		}
+		return model.Intel{}, err
+	}
+
+	if active {
+		log.Error("intel is still active")
+		if err := tx.Rollback(); err != nil {
+			return model.Intel{}, err
+		}
+		return model.Intel{}, errs.ErrIntelActive
+	}
+
+	intel.TransactionID, err = transactions.Debit(ctx, tx, intel.BuyerID, intel.Fee, transactions.FeeTypeID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Intel{}, err
+		}
+
+		if errors.Is(err, errs.ErrInsufficientFunds) {
+			log.Error("insufficient funds for the fee")
+			return model.Intel{}, err
+		}
+
+		log.Error("failed to pay the fee", slog.String("error", err.Error()))
+		return model.Intel{}, err
+	}
+
+	query := `INSERT INTO intel_purchases (buyer_id, target_id, transaction_id, fee, expires_at)
+			  VALUES ($1, $2, $3, $4, $5)
+			  RETURNING id, created_at`
+
+	err = tx.QueryRowxContext(ctx, query,
+		intel.BuyerID,
+		intel.TargetID,
+		intel.TransactionID,
+		intel.Fee,
+		intel.ExpiresAt,
+	).Scan(&intel.ID, &intel.CreatedAt)
+	if err != nil {
+		log.Error("failed to add intel", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Intel{}, err
+		}
+		return model.Intel{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Intel{}, err
+	}
+
+	log.Info("bought intel", slog.Int("id", intel.ID))
+
+	return intel, nil
+}
+
+func (s *Storage) GetByID(ctx context.Context, intelID int) (model.Intel, error) {
+	op := "intel.GetByID"
+
+	log := s.log.With(slog.String("op", op), slog.Int("intelID", intelID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Intel{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var intel dbIntel
+	if err := conn.GetContext(ctx, &intel, `SELECT `+intelColumns+` FROM intel_purchases WHERE id = $1`, intelID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Intel{}, errs.ErrIntelNotFound
+		}
+
+		log.Error("failed to get intel", slog.String("error", err.Error()))
+		return model.Intel{}, err
+	}
+
+	return intel.toModel(), nil
+}
+
+// GetAll returns purchases of the buyer, newest first. With activeOnly expired ones are skipped.
+func (s *Storage) GetAll(ctx context.Context, buyerID int, activeOnly bool, limit int) ([]model.Intel, error) {
+	op := "intel.GetAll"
+
+	log := s.log.With(slog.String("op", op), slog.Int("buyerID", buyerID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT ` + intelColumns + ` FROM intel_purchases
+			  WHERE buyer_id = $1 AND (NOT $2 OR expires_at > $3)
+			  ORDER BY created_at DESC, id DESC
+			  LIMIT $4`
+
+	var dbIntels []dbIntel
+	if err := conn.SelectContext(ctx, &dbIntels, query, buyerID, activeOnly, time.Now(), limit); err != nil {
+		log.Error("failed to get intel", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	intels := make([]model.Intel, 0, len(dbIntels))
+	for _, intel := range dbIntels {
+		intels = append(intels, intel.toModel())
+	}
+
+	return intels, nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+const intelColumns = `id, buyer_id, target_id, transaction_id, fee, expires_at, created_at`
+
+type dbIntel struct {
+	ID            int       `db:"id"`
+	BuyerID       int       `db:"buyer_id"`
+	TargetID      int       `db:"target_id"`
+	TransactionID int       `db:"transaction_id"`
+	Fee           float64   `db:"fee"`
+	ExpiresAt     time.Time `db:"expires_at"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+func (i dbIntel) toModel() model.Intel {
+	return model.Intel{
+		ID:            i.ID,
+		BuyerID:       i.BuyerID,
+		TargetID:      i.TargetID,
+		TransactionID: i.TransactionID,
+		Fee:           i.Fee,
+		ExpiresAt:     i.ExpiresAt,
+		CreatedAt:     i.CreatedAt,
+	}
+}
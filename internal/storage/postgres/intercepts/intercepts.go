@@ -0,0 +1,338 @@
+package intercepts
+
+import (
+	"context"
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/tasks"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/transactions"
+	"log/slog"
+	"time"
+)
+
+type Storage struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
+	return &Storage{
+		db:  db,
+		log: log,
+	}
+}
+
+// Attempt records the intercept and applies its outcome: on success the task moves to the thief,
+// otherwise the thief pays the penalty to the victim. Cooldowns are checked under the balance
+// locks of both users, so parallel attempts can not slip through.
+func (s *Storage) Attempt(ctx context.Context, intercept model.Intercept, cooldown, victimCooldown time.Duration) (model.Intercept, error) {
+	op := "intercepts.Attempt"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int("taskID", intercept.TaskID),
+		slog.Int("thiefID", intercept.ThiefID),
+		slog.Int("victimID", intercept.VictimID),
+	)
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Intercept{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Intercept{}, err
+	}
+
+	// balances are locked in a fixed order, so two racoons robbing each other do not deadlock
+	var balances []struct {
+		UserID  int     `db:"user_id"`
+		Balance float64 `db:"balance"`
+	}
+	err = tx.SelectContext(ctx, &balances,
+		`SELECT user_id, balance FROM balances WHERE user_id IN ($1, $2) ORDER BY user_id FOR UPDATE`,
+		intercept.ThiefID, intercept.VictimID,
+	)
+	if err != nil {
+		log.Error("failed to lock balances", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Intercept{}, err
+		}
+		return model.Intercept{}, err
+	}
+
+	var thiefBalance float64
+	for _, balance := range balances {
+		if balance.UserID == intercept.ThiefID {
+			thiefBalance = balance.Balance
+		}
+	}
+
+	var lastAttempt sql.NullTime
+	err = tx.GetContext(ctx, &lastAttempt, `SELECT MAX(created_at) FROM tasks_intercepts WHERE thief_id = $1`, intercept.ThiefID)
+	if err != nil {
+		log.Error("failed to get last attempt", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Intercept{}, err
+		}
+		return model.Intercept{}, err
+	}
+
+	if lastAttempt.Valid && time.Since(lastAttempt.Time) < cooldown {
+		log.Error("thief is on cooldown", slog.Time("lastAttempt", lastAttempt.Time))
+		if err := tx.Rollback(); err != nil {
+			return model.Intercept{}, err
+		}
+		return model.Intercept{}, errs.ErrInterceptCooldown
+	}
+
+	var lastVictimAttempt sql.NullTime
+	err = tx.GetContext(ctx, &lastVictimAttempt, `SELECT MAX(created_at) FROM tasks_intercepts WHERE victim_id = $1`, intercept.VictimID)
+	if err != nil {
+		log.Error("failed to get last attempt on victim", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Intercept{}, err
+		}
+		return model.Intercept{}, err
+	}
+
+	if lastVictimAttempt.Valid && time.Since(lastVictimAttempt.Time) < victimCooldown {
+		log.Error("victim is protected", slog.Time("lastAttempt", lastVictimAttempt.Time))
+		if err := tx.Rollback(); err != nil {
+			return model.Intercept{}, err
+		}
+		return model.Intercept{}, errs.ErrVictimProtected
+	}
+
+	// the stake is checked before the roll is applied, a racoon who can not pay may not try
+	if thiefBalance < intercept.Penalty {
+		log.Error("insufficient funds for the penalty")
+		if err := tx.Rollback(); err != nil {
+			return model.Intercept{}, err
+		}
+		return model.Intercept{}, errs.ErrInsufficientFunds
+	}
+
+	var res sql.Result
+	if intercept.Success {
+		res, err = tx.ExecContext(ctx,
+			`UPDATE tasks SET user_id = $1, user_deadline = NULL, updated_at = NOW() WHERE id = $2 AND status_id = $3 AND user_id = $4`,
+			intercept.ThiefID, intercept.TaskID, tasks.InProgressStatusID, intercept.VictimID,
+		)
+	} else {
+		// the task is locked even when it stays with the victim, so it can not change under the attempt
+		res, err = tx.ExecContext(ctx,
+			`UPDATE tasks SET updated_at = updated_at WHERE id = $1 AND status_id = $2 AND user_id = $3`,
+			intercept.TaskID, tasks.InProgressStatusID, intercept.VictimID,
+		)
+	}
+	if err != nil {
+		log.Error("failed to update task", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Intercept{}, err
+		}
+		return model.Intercept{}, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		log.Error("failed to get affected rows", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Intercept{}, err
+		}
+		return model.Intercept{}, err
+	}
+
+	if affected == 0 {
+		log.Error("task is not in progress by the victim")
+		if err := tx.Rollback(); err != nil {
+			return model.Intercept{}, err
+		}
+		return model.Intercept{}, errs.ErrTaskNotInterceptable
+	}
+
+	penalty := intercept.Penalty
+	if intercept.Success {
+		penalty = 0
+	}
+
+	query := `INSERT INTO tasks_intercepts (task_id, thief_id, victim_id, seed, roll, chance, success, penalty)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			  RETURNING id, created_at`
+
+	err = tx.QueryRowxContext(ctx, query,
+		intercept.TaskID,
+		intercept.ThiefID,
+		intercept.VictimID,
+		intercept.Seed,
+		intercept.Roll,
+		intercept.Chance,
+		intercept.Success,
+		penalty,
+	).Scan(&intercept.ID, &intercept.CreatedAt)
+	if err != nil {
+		log.Error("failed to add intercept", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Intercept{}, err
+		}
+		return model.Intercept{}, err
+	}
+
+	intercept.Penalty = penalty
+
+	if penalty > 0 {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO transactions (sender_id, receiver_id, amount, type_id, status_id) VALUES ($1, $2, $3, $4, $5)`,
+			intercept.ThiefID, intercept.VictimID, penalty, transactions.PenaltyTypeID, transactions.CompletedStatusID,
+		)
+		if err != nil {
+			log.Error("failed to insert penalty transaction", slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return model.Intercept{}, err
+			}
+			return model.Intercept{}, err
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE balances SET balance = balance - $1 WHERE user_id = $2`, penalty, intercept.ThiefID)
+		if err != nil {
+			log.Error("failed to update thief balance", slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return model.Intercept{}, err
+			}
+			return model.Intercept{}, err
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE balances SET balance = balance + $1 WHERE user_id = $2`, penalty, intercept.VictimID)
+		if err != nil {
+			log.Error("failed to update victim balance", slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return model.Intercept{}, err
+			}
+			return model.Intercept{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Intercept{}, err
+	}
+
+	log.Info("intercept attempted", slog.Int("id", intercept.ID), slog.Bool("success", intercept.Success))
+
+	return intercept, nil
+}
+
+// GetAll returns attempts, newest first. userID matches both the thief and the victim.
+func (s *Storage) GetAll(ctx context.Context, userID, taskID, limit int) ([]model.Intercept, error) {
+	op := "intercepts.GetAll"
+
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT ` + interceptColumns + ` FROM tasks_intercepts
+			  WHERE ($1 = 0 OR thief_id = $1 OR victim_id = $1) AND ($2 = 0 OR task_id = $2)
+			  ORDER BY created_at DESC, id DESC
+			  LIMIT $3`
+
+	var dbIntercepts []dbIntercept
+	if err := conn.SelectContext(ctx, &dbIntercepts, query, userID, taskID, limit); err != nil {
+		log.Error("failed to get intercepts", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	intercepts := make([]model.Intercept, 0, len(dbIntercepts))
+	for _, intercept := range dbIntercepts {
+		intercepts = append(intercepts, intercept.toModel())
+	}
+
+	return intercepts, nil
+}
+
+// LastAttemptAt returns when the user last tried to intercept a task, zero if never.
+func (s *Storage) LastAttemptAt(ctx context.Context, thiefID int) (time.Time, error) {
+	op := "intercepts.LastAttemptAt"
+
+	log := s.log.With(slog.String("op", op), slog.Int("thiefID", thiefID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return time.Time{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var lastAttempt sql.NullTime
+	if err := conn.GetContext(ctx, &lastAttempt, `SELECT MAX(created_at) FROM tasks_intercepts WHERE thief_id = $1`, thiefID); err != nil {
+		log.Error("failed to get last attempt", slog.String("error", err.Error()))
+		return time.Time{}, err
+	}
+
+	return lastAttempt.Time, nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+const interceptColumns = `id, task_id, thief_id, victim_id, seed, roll, chance, success, penalty, created_at`
+
+type dbIntercept struct {
+	ID        int       `db:"id"`
+	TaskID    int       `db:"task_id"`
+	ThiefID   int       `db:"thief_id"`
+	VictimID  int       `db:"victim_id"`
+	Seed      int64     `db:"seed"`
+	Roll      float64   `db:"roll"`
+	Chance    float64   `db:"chance"`
+	Success   bool      `db:"success"`
+	Penalty   float64   `db:"penalty"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (i dbIntercept) toModel() model.Intercept {
+	return model.Intercept{
+		ID:        i.ID,
+		TaskID:    i.TaskID,
+		ThiefID:   i.ThiefID,
+		VictimID:  i.VictimID,
+		Seed:      i.Seed,
+		Roll:      i.Roll,
+		Chance:    i.Chance,
+		Success:   i.Success,
+		Penalty:   i.Penalty,
+		CreatedAt: i.CreatedAt,
+	}
+}
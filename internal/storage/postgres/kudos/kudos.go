@@ -0,0 +1,250 @@
+package kudos
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/nullable"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/transactions"
+	"log/slog"
+	"time"
+)
+
+type Storage struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
+	return &Storage{
+		db:  db,
+		log: log,
+	}
+}
+
+// Give records the kudos and pays its coins to the receiver in one transaction. The sender is locked
+// while the allowance of the period is checked, so concurrent kudos can not exceed it together.
+func (s *Storage) Give(ctx context.Context, kudos model.Kudos, allowance int) (model.Kudos, error) {
+	op := "kudos.Give"
+
+	log := s.log.With(slog.String("op", op), slog.Int("senderID", kudos.SenderID), slog.Int("receiverID", kudos.ReceiverID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Kudos{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Kudos{}, err
+	}
+
+	err = tx.GetContext(ctx, &kudos.SenderName, `SELECT username FROM users WHERE id = $1 FOR UPDATE`, kudos.SenderID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Kudos{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Kudos{}, errs.ErrUserNotFound
+		}
+
+		log.Error("failed to lock sender", slog.String("error", err.Error()))
+		return model.Kudos{}, err
+	}
+
+	err = tx.GetContext(ctx, &kudos.ReceiverName, `SELECT username FROM users WHERE id = $1`, kudos.ReceiverID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Kudos{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Kudos{}, errs.ErrUserNotFound
+		}
+
+		log.Error("failed to get receiver", slog.String("error", err.Error()))
+		return model.Kudos{}, err
+	}
+
+	var given int
+	err = tx.GetContext(ctx, &given,
+		`SELECT COALESCE(SUM(amount), 0) FROM kudos WHERE sender_id = $1 AND period = $2`,
+		kudos.SenderID, date(kudos.Period),
+	)
+	if err != nil {
+		log.Error("failed to get given kudos", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Kudos{}, err
+		}
+		return model.Kudos{}, err
+	}
+
+	if given+kudos.Amount > allowance {
+		if err := tx.Rollback(); err != nil {
+			return model.Kudos{}, err
+		}
+		return model.Kudos{}, errs.ErrKudosAllowanceExceeded
+	}
+
+	if kudos.Coins > 0 {
+		kudos.TransactionID, err = transactions.Credit(ctx, tx, kudos.ReceiverID, kudos.Coins, transactions.KudosTypeID)
+		if err != nil {
+			log.Error("failed to pay kudos coins", slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return model.Kudos{}, err
+			}
+			return model.Kudos{}, err
+		}
+	}
+
+	query := `INSERT INTO kudos (sender_id, receiver_id, amount, coins, message, period, transaction_id)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7)
+			  RETURNING id, created_at`
+
+	err = tx.QueryRowxContext(ctx, query,
+		kudos.SenderID,
+		kudos.ReceiverID,
+		kudos.Amount,
+		kudos.Coins,
+		kudos.Message,
+		date(kudos.Period),
+		nullable.ID(kudos.TransactionID),
+	).Scan(&kudos.ID, &kudos.CreatedAt)
+	if err != nil {
+		log.Error("failed to add kudos", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Kudos{}, err
+		}
+		return model.Kudos{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Kudos{}, err
+	}
+
+	log.Info("gave kudos", slog.Int("id", kudos.ID), slog.Int("amount", kudos.Amount))
+
+	return kudos, nil
+}
+
+// Given returns how many kudos the user has given in the period.
+func (s *Storage) Given(ctx context.Context, senderID int, period time.Time) (int, error) {
+	op := "kudos.Given"
+
+	log := s.log.With(slog.String("op", op), slog.Int("senderID", senderID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var given int
+	err = conn.GetContext(ctx, &given,
+		`SELECT COALESCE(SUM(amount), 0) FROM kudos WHERE sender_id = $1 AND period = $2`,
+		senderID, date(period),
+	)
+	if err != nil {
+		log.Error("failed to get given kudos", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	return given, nil
+}
+
+// GetFeed returns a page of all kudos, newest first.
+func (s *Storage) GetFeed(ctx context.Context, limit, offset int) ([]model.Kudos, error) {
+	return s.get(ctx, "kudos.GetFeed", `TRUE`, limit, offset)
+}
+
+// GetReceived returns a page of kudos the user has received, newest first.
+func (s *Storage) GetReceived(ctx context.Context, receiverID, limit, offset int) ([]model.Kudos, error) {
+	return s.get(ctx, "kudos.GetReceived", `k.receiver_id = $3`, limit, offset, receiverID)
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+// get selects a page of kudos matching the condition, which takes its parameters from $3 on.
+func (s *Storage) get(ctx context.Context, op, condition string, limit, offset int, args ...interface{}) ([]model.Kudos, error) {
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT k.id, k.sender_id, sender.username AS sender_name, k.receiver_id, receiver.username AS receiver_name,
+			  k.amount, k.coins, k.message, k.period, COALESCE(k.transaction_id, 0) AS transaction_id, k.created_at
+			  FROM kudos k
+			  JOIN users sender ON sender.id = k.sender_id
+			  JOIN users receiver ON receiver.id = k.receiver_id
+			  WHERE ` + condition + `
+			  ORDER BY k.created_at DESC, k.id DESC
+			  LIMIT $1 OFFSET $2`
+
+	var dbKudos []dbKudos
+	if err := conn.SelectContext(ctx, &dbKudos, query, append([]interface{}{limit, offset}, args...)...); err != nil {
+		log.Error("failed to get kudos", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	kudos := make([]model.Kudos, 0, len(dbKudos))
+	for _, k := range dbKudos {
+		kudos = append(kudos, model.Kudos(k))
+	}
+
+	return kudos, nil
+}
+
+// date formats the day for a DATE column, so the session time zone can not shift it.
+func date(day time.Time) string {
+	return day.Format(time.DateOnly)
+}
+
+type dbKudos struct {
+	ID            int       `db:"id"`
+	SenderID      int       `db:"sender_id"`
+	SenderName    string    `db:"sender_name"`
+	ReceiverID    int       `db:"receiver_id"`
+	ReceiverName  string    `db:"receiver_name"`
+	Amount        int       `db:"amount"`
+	Coins         float64   `db:"coins"`
+	Message       string    `db:"message"`
+	Period        time.Time `db:"period"`
+	TransactionID int       `db:"transaction_id"`
+	CreatedAt     time.Time `db:"created_at"`
+}
@@ -0,0 +1,215 @@
+package leaderboards
+
+import (
+	"context"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/tasks"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/transactions"
+	"log/slog"
+	"time"
+)
+
+const (
+	MetricBalance      = "balance"
+	MetricEarned       = "earned"
+	MetricTasks        = "tasks"
+	MetricXP           = "xp"
+	MetricWeeklyEarned = "weekly_earned"
+	MetricSeasonEarned = "season_earned"
+)
+
+// scores holds the score expression of every metric. Parameters are read from the params
+// relation, so each query references all of them whatever the metric.
+var scores = map[string]string{
+	MetricBalance: `COALESCE((SELECT b.balance FROM balances b WHERE b.user_id = u.id), 0)`,
+	MetricXP:      `u.xp`,
+	MetricTasks: fmt.Sprintf(`(SELECT COUNT(*) FROM tasks t
+		WHERE t.user_id = u.id AND t.status_id = %d AND (p.group_id = 0 OR t.for_group_id = p.group_id))`,
+		tasks.CompletedStatusID),
+	MetricEarned: earned,
+	// weekly earnings differ from the total only by the start of the period
+	MetricWeeklyEarned: earned,
+	MetricSeasonEarned: earned,
+}
+
+var earned = fmt.Sprintf(`(SELECT COALESCE(SUM(tr.amount), 0) FROM transactions tr
+	LEFT JOIN tasks t ON t.id = tr.task_id
+	WHERE tr.receiver_id = u.id AND tr.type_id = %d AND tr.status_id = %d AND tr.created_at >= p.since
+	AND (p.until IS NULL OR tr.created_at < p.until)
+	AND (p.group_id = 0 OR t.for_group_id = p.group_id))`,
+	transactions.RewardTypeID, transactions.CompletedStatusID)
+
+type Storage struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
+	return &Storage{
+		db:  db,
+		log: log,
+	}
+}
+
+// Get returns a page of the leaderboard, highest score first.
+func (s *Storage) Get(ctx context.Context, filter model.LeaderboardFilter) ([]model.LeaderboardEntry, error) {
+	op := "leaderboards.Get"
+
+	log := s.log.With(slog.String("op", op), slog.String("metric", filter.Metric))
+
+	query, err := pageQuery(filter.Metric)
+	if err != nil {
+		log.Error("failed to build query", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var dbEntries []dbEntry
+	err = conn.SelectContext(ctx, &dbEntries, query, append(Args(filter), filter.Limit, filter.Offset)...)
+	if err != nil {
+		log.Error("failed to get leaderboard", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return toModels(dbEntries), nil
+}
+
+// GetAround returns the entry of the user and up to neighbours entries above and below it.
+// It returns ErrUserNotRanked if the user is not on the leaderboard, e.g. filtered out by class.
+func (s *Storage) GetAround(ctx context.Context, filter model.LeaderboardFilter, userID, neighbours int) ([]model.LeaderboardEntry, error) {
+	op := "leaderboards.GetAround"
+
+	log := s.log.With(slog.String("op", op), slog.String("metric", filter.Metric), slog.Int("userID", userID))
+
+	query, err := aroundQuery(filter.Metric)
+	if err != nil {
+		log.Error("failed to build query", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var dbEntries []dbEntry
+	err = conn.SelectContext(ctx, &dbEntries, query, append(Args(filter), userID, neighbours)...)
+	if err != nil {
+		log.Error("failed to get leaderboard", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if len(dbEntries) == 0 {
+		return nil, errs.ErrUserNotRanked
+	}
+
+	return toModels(dbEntries), nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+// RankedQuery returns the WITH clause that ranks users by the metric. It takes the class ID,
+// the group ID, the start and the end of the period as $1 to $4, see Args. Queries built on it
+// number their own parameters from $5 on.
+func RankedQuery(metric string) (string, error) {
+	score, ok := scores[metric]
+	if !ok {
+		return "", errs.ErrUnknownMetric
+	}
+
+	return `WITH p AS (
+				SELECT $1::INTEGER AS class_id, $2::INTEGER AS group_id, $3::TIMESTAMP AS since, $4::TIMESTAMP AS until
+			  ), scores AS (
+				SELECT u.id AS user_id, u.username, COALESCE(u.class_id, 0) AS class_id, ` + score + ` AS score
+				FROM users u, p
+				WHERE p.class_id = 0 OR u.class_id = p.class_id
+			  ), ranked AS (
+				SELECT ROW_NUMBER() OVER (ORDER BY score DESC, user_id) AS rank, user_id, username, class_id, score
+				FROM scores
+			  )`, nil
+}
+
+// pageQuery returns the query of a leaderboard page, it takes the limit and the offset as $5 and $6.
+func pageQuery(metric string) (string, error) {
+	ranked, err := RankedQuery(metric)
+	if err != nil {
+		return "", err
+	}
+
+	return ranked + `
+			  SELECT rank, user_id, username, class_id, score FROM ranked
+			  ORDER BY rank
+			  LIMIT $5 OFFSET $6`, nil
+}
+
+// aroundQuery returns the query of the entries around a user, it takes the user ID and
+// the number of neighbours as $5 and $6.
+func aroundQuery(metric string) (string, error) {
+	ranked, err := RankedQuery(metric)
+	if err != nil {
+		return "", err
+	}
+
+	return ranked + `
+			  SELECT r.rank, r.user_id, r.username, r.class_id, r.score FROM ranked r
+			  JOIN ranked me ON me.user_id = $5
+			  WHERE r.rank BETWEEN me.rank - $6 AND me.rank + $6
+			  ORDER BY r.rank`, nil
+}
+
+// Args returns the parameters of RankedQuery for the filter.
+func Args(filter model.LeaderboardFilter) []interface{} {
+	return []interface{}{filter.ClassID, filter.GroupID, filter.Since, nullableTime(filter.Until)}
+}
+
+func nullableTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+
+	return t
+}
+
+type dbEntry struct {
+	Rank     int     `db:"rank"`
+	UserID   int     `db:"user_id"`
+	Username string  `db:"username"`
+	ClassID  int     `db:"class_id"`
+	Score    float64 `db:"score"`
+}
+
+func toModels(dbEntries []dbEntry) []model.LeaderboardEntry {
+	entries := make([]model.LeaderboardEntry, 0, len(dbEntries))
+	for _, entry := range dbEntries {
+		entries = append(entries, model.LeaderboardEntry(entry))
+	}
+
+	return entries
+}
@@ -0,0 +1,137 @@
+package leaderboards
+
+import (
+	"context"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	_ "github.com/lib/pq"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var placeholder = regexp.MustCompile(`\$(\d+)`)
+
+// placeholders returns the highest parameter number in the query and fails the test if any
+// number below it is not used.
+func placeholders(t *testing.T, query string) int {
+	t.Helper()
+
+	used := make(map[int]bool)
+	highest := 0
+	for _, match := range placeholder.FindAllStringSubmatch(query, -1) {
+		n, err := strconv.Atoi(match[1])
+		if err != nil {
+			t.Fatalf("bad placeholder %q: %v", match[0], err)
+		}
+
+		used[n] = true
+		if n > highest {
+			highest = n
+		}
+	}
+
+	for n := 1; n <= highest; n++ {
+		if !used[n] {
+			t.Errorf("placeholder $%d is not used", n)
+		}
+	}
+
+	return highest
+}
+
+func TestQueriesBindAllArgs(t *testing.T) {
+	filter := model.LeaderboardFilter{Since: time.Now().Add(-time.Hour), Until: time.Now()}
+
+	tests := []struct {
+		name  string
+		build func(metric string) (string, error)
+		args  int
+	}{
+		{name: "page", build: pageQuery, args: len(append(Args(filter), 10, 0))},
+		{name: "around", build: aroundQuery, args: len(append(Args(filter), 1, 2))},
+	}
+
+	for _, tt := range tests {
+		for metric := range scores {
+			t.Run(tt.name+"/"+metric, func(t *testing.T) {
+				query, err := tt.build(metric)
+				if err != nil {
+					t.Fatalf("build query: %v", err)
+				}
+
+				if got := placeholders(t, query); got != tt.args {
+					t.Errorf("query uses %d parameters, %d args are passed", got, tt.args)
+				}
+			})
+		}
+	}
+}
+
+func TestQueriesUnknownMetric(t *testing.T) {
+	for _, build := range []func(string) (string, error){pageQuery, aroundQuery} {
+		if _, err := build("unknown"); !errors.Is(err, errs.ErrUnknownMetric) {
+			t.Errorf("got %v, want ErrUnknownMetric", err)
+		}
+	}
+}
+
+// TestStorage runs the queries against a migrated database given by TEST_POSTGRES_DSN.
+func TestStorage(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	db, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+
+	s := NewStorage(db, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	t.Cleanup(func() { _ = s.Close() })
+
+	ctx := context.Background()
+
+	var userID int
+	if err := db.GetContext(ctx, &userID, `SELECT id FROM users ORDER BY id LIMIT 1`); err != nil {
+		t.Skipf("no users to rank: %v", err)
+	}
+
+	for metric := range scores {
+		t.Run(metric, func(t *testing.T) {
+			filter := model.LeaderboardFilter{
+				Metric: metric,
+				Since:  time.Now().AddDate(0, 0, -7),
+				Limit:  10,
+			}
+
+			entries, err := s.Get(ctx, filter)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if len(entries) == 0 || len(entries) > filter.Limit {
+				t.Errorf("Get returned %d entries, want 1 to %d", len(entries), filter.Limit)
+			}
+
+			around, err := s.GetAround(ctx, filter, userID, 1)
+			if err != nil {
+				t.Fatalf("GetAround: %v", err)
+			}
+
+			found := false
+			for _, entry := range around {
+				found = found || entry.UserID == userID
+			}
+			if !found || len(around) > 3 {
+				t.Errorf("GetAround returned %v, want the user and at most one neighbour on each side", around)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package like
+
+import "strings"
+
+var escaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Escape makes the text match itself in a LIKE pattern: % and _ typed by a user are not wildcards.
+// Backslash is the default escape character of LIKE, so patterns need no ESCAPE clause.
+func Escape(text string) string {
+	return escaper.Replace(text)
+}
@@ -0,0 +1,22 @@
+package like
+
+import "testing"
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "plain", want: "plain"},
+		{text: "%", want: `\%`},
+		{text: "a_b", want: `a\_b`},
+		{text: `back\slash`, want: `back\\slash`},
+		{text: `100%_\`, want: `100\%\_\\`},
+	}
+
+	for _, tt := range tests {
+		if got := Escape(tt.text); got != tt.want {
+			t.Errorf("Escape(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
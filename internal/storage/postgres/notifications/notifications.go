@@ -0,0 +1,215 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/nullable"
+	"log/slog"
+	"time"
+)
+
+const (
+	TaskCancelledTypeID        = 1
+	TaskAssignedTypeID         = 2
+	TaskUnassignedTypeID       = 3
+	TaskUpdatedTypeID          = 4
+	TaskAcceptanceDeniedTypeID = 5
+	TaskInterceptedTypeID      = 6
+	TaskInterceptFailedTypeID  = 7
+	IntelPurchasedTypeID       = 8
+	LevelUpTypeID              = 9
+	AchievementUnlockedTypeID  = 10
+	SeasonPrizeTypeID          = 11
+	KudosReceivedTypeID        = 12
+	TeamInvitationTypeID       = 13
+	TeamTaskRewardTypeID       = 14
+	QuestCompletedTypeID       = 15
+	DuelChallengeTypeID        = 16
+	DuelResolvedTypeID         = 17
+	DuelRefundedTypeID         = 18
+	BountyUpdateTypeID         = 19
+	RaffleUpdateTypeID         = 20
+	EventUpdateTypeID          = 21
+)
+
+type Storage struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
+	return &Storage{
+		db:  db,
+		log: log,
+	}
+}
+
+func (s *Storage) Add(ctx context.Context, notification model.Notification) error {
+	op := "notifications.Add"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", notification.UserID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `INSERT INTO notifications (user_id, type_id, message, task_id) VALUES ($1, $2, $3, $4)`
+
+	_, err = conn.ExecContext(ctx, query, notification.UserID, notification.TypeID, notification.Message, nullable.ID(notification.TaskID))
+	if err != nil {
+		log.Error("failed to add notification", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("added notification")
+
+	return nil
+}
+
+// AddForAllUsers sends the same notification to every user, UserID of the notification is ignored.
+func (s *Storage) AddForAllUsers(ctx context.Context, notification model.Notification) error {
+	op := "notifications.AddForAllUsers"
+
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `INSERT INTO notifications (user_id, type_id, message, task_id) SELECT id, $1, $2, $3 FROM users`
+
+	_, err = conn.ExecContext(ctx, query, notification.TypeID, notification.Message, nullable.ID(notification.TaskID))
+	if err != nil {
+		log.Error("failed to add notifications", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("added notifications for all users")
+
+	return nil
+}
+
+func (s *Storage) GetAllUserNotifications(ctx context.Context, userID int, unreadOnly bool, limit int) ([]model.Notification, error) {
+	op := "notifications.GetAllUserNotifications"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT id, user_id, type_id, message, task_id, created_at, read_at
+			  FROM notifications
+			  WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+			  ORDER BY created_at DESC, id DESC
+			  LIMIT $3`
+
+	var dbNotifications []dbNotification
+	if err := conn.SelectContext(ctx, &dbNotifications, query, userID, unreadOnly, limit); err != nil {
+		log.Error("failed to get notifications", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	notifications := make([]model.Notification, 0, len(dbNotifications))
+	for _, notification := range dbNotifications {
+		notifications = append(notifications, model.Notification{
+			ID:        notification.ID,
+			UserID:    notification.UserID,
+			TypeID:    notification.TypeID,
+			Message:   notification.Message,
+			TaskID:    int(notification.TaskID.Int64),
+			CreatedAt: notification.CreatedAt,
+			ReadAt:    notification.ReadAt.Time,
+		})
+	}
+
+	return notifications, nil
+}
+
+func (s *Storage) MarkAsRead(ctx context.Context, notificationID, userID int) error {
+	op := "notifications.MarkAsRead"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `UPDATE notifications SET read_at = COALESCE(read_at, NOW()) WHERE id = $1 AND user_id = $2`
+
+	res, err := conn.ExecContext(ctx, query, notificationID, userID)
+	if err != nil {
+		log.Error("failed to mark notification as read", slog.String("error", err.Error()))
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		log.Error("failed to get affected rows", slog.String("error", err.Error()))
+		return err
+	}
+
+	if affected == 0 {
+		log.Error("notification not found")
+		return errs.ErrNotificationNotFound
+	}
+
+	return nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+type dbNotification struct {
+	ID        int           `db:"id"`
+	UserID    int           `db:"user_id"`
+	TypeID    int           `db:"type_id"`
+	Message   string        `db:"message"`
+	TaskID    sql.NullInt64 `db:"task_id"`
+	CreatedAt time.Time     `db:"created_at"`
+	ReadAt    sql.NullTime  `db:"read_at"`
+}
@@ -0,0 +1,10 @@
+package nullable
+
+// ID returns the id as a query argument, NULL for zero, the id of nothing.
+func ID(id int) interface{} {
+	if id == 0 {
+		return nil
+	}
+
+	return id
+}
@@ -3,13 +3,37 @@ package postgres
 import (
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/achievements"
 	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/admins"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/approvals"
 	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/balances"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/bounties"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/budgets"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/businesses"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/companions"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/drops"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/duels"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/events"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/intel"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/intercepts"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/kudos"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/leaderboards"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/notifications"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/production"
 	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/purchases"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/quests"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/quizzes"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/raffles"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/reviews"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/search"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/seasons"
 	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/shop/items"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/streaks"
 	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/tasks"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/teams"
 	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/transactions"
 	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/users"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/xp"
 	"io"
 	"log/slog"
 	"reflect"
@@ -17,13 +41,37 @@ import (
 )
 
 type Storages struct {
-	UsersStorage        *users.Storage
-	BalancesStorage     *balances.Storage
-	TransactionsStorage *transactions.Storage
-	ShopItemsStorage    *items.Storage
-	PurchasesStorage    *purchases.Storage
-	AdminsStorage       *admins.Storage
-	TasksStorage        *tasks.Storage
+	UsersStorage         *users.Storage
+	BalancesStorage      *balances.Storage
+	TransactionsStorage  *transactions.Storage
+	ShopItemsStorage     *items.Storage
+	PurchasesStorage     *purchases.Storage
+	AdminsStorage        *admins.Storage
+	TasksStorage         *tasks.Storage
+	SearchStorage        *search.Storage
+	NotificationsStorage *notifications.Storage
+	ReviewsStorage       *reviews.Storage
+	ApprovalsStorage     *approvals.Storage
+	BudgetsStorage       *budgets.Storage
+	InterceptsStorage    *intercepts.Storage
+	IntelStorage         *intel.Storage
+	XPStorage            *xp.Storage
+	AchievementsStorage  *achievements.Storage
+	LeaderboardsStorage  *leaderboards.Storage
+	SeasonsStorage       *seasons.Storage
+	StreaksStorage       *streaks.Storage
+	KudosStorage         *kudos.Storage
+	TeamsStorage         *teams.Storage
+	QuestsStorage        *quests.Storage
+	DuelsStorage         *duels.Storage
+	BountiesStorage      *bounties.Storage
+	DropsStorage         *drops.Storage
+	RafflesStorage       *raffles.Storage
+	EventsStorage        *events.Storage
+	QuizzesStorage       *quizzes.Storage
+	BusinessesStorage    *businesses.Storage
+	ProductionStorage    *production.Storage
+	CompanionsStorage    *companions.Storage
 }
 
 func NewStorages(
@@ -50,13 +98,37 @@ func NewStorages(
 	}
 
 	return &Storages{
-		UsersStorage:        users.NewStorage(db, log),
-		BalancesStorage:     balances.NewStorage(db, log),
-		TransactionsStorage: transactions.NewStorage(db, log),
-		ShopItemsStorage:    items.NewStorage(db, log),
-		PurchasesStorage:    purchases.NewStorage(db, log),
-		AdminsStorage:       admins.NewStorage(db, log),
-		TasksStorage:        tasks.NewStorage(db, log),
+		UsersStorage:         users.NewStorage(db, log),
+		BalancesStorage:      balances.NewStorage(db, log),
+		TransactionsStorage:  transactions.NewStorage(db, log),
+		ShopItemsStorage:     items.NewStorage(db, log),
+		PurchasesStorage:     purchases.NewStorage(db, log),
+		AdminsStorage:        admins.NewStorage(db, log),
+		TasksStorage:         tasks.NewStorage(db, log),
+		SearchStorage:        search.NewStorage(db, log),
+		NotificationsStorage: notifications.NewStorage(db, log),
+		ReviewsStorage:       reviews.NewStorage(db, log),
+		ApprovalsStorage:     approvals.NewStorage(db, log),
+		BudgetsStorage:       budgets.NewStorage(db, log),
+		InterceptsStorage:    intercepts.NewStorage(db, log),
+		IntelStorage:         intel.NewStorage(db, log),
+		XPStorage:            xp.NewStorage(db, log),
+		AchievementsStorage:  achievements.NewStorage(db, log),
+		LeaderboardsStorage:  leaderboards.NewStorage(db, log),
+		SeasonsStorage:       seasons.NewStorage(db, log),
+		StreaksStorage:       streaks.NewStorage(db, log),
+		KudosStorage:         kudos.NewStorage(db, log),
+		TeamsStorage:         teams.NewStorage(db, log),
+		QuestsStorage:        quests.NewStorage(db, log),
+		DuelsStorage:         duels.NewStorage(db, log),
+		BountiesStorage:      bounties.NewStorage(db, log),
+		DropsStorage:         drops.NewStorage(db, log),
+		RafflesStorage:       raffles.NewStorage(db, log),
+		EventsStorage:        events.NewStorage(db, log),
+		QuizzesStorage:       quizzes.NewStorage(db, log),
+		BusinessesStorage:    businesses.NewStorage(db, log),
+		ProductionStorage:    production.NewStorage(db, log),
+		CompanionsStorage:    companions.NewStorage(db, log),
 	}, nil
 }
 
@@ -0,0 +1,572 @@
+package production
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/transactions"
+	"log/slog"
+	"math"
+	"time"
+)
+
+type Storage struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
+	return &Storage{
+		db:  db,
+		log: log,
+	}
+}
+
+// GetRecipes returns the recipes of all the business types with their inputs.
+func (s *Storage) GetRecipes(ctx context.Context) ([]model.Recipe, error) {
+	op := "production.GetRecipes"
+
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var dbRecipes []dbRecipe
+	if err := conn.SelectContext(ctx, &dbRecipes, `SELECT `+recipeColumns+` FROM recipes r JOIN resources res ON res.id = r.output_resource_id ORDER BY r.id`); err != nil {
+		log.Error("failed to get recipes", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var dbInputs []dbInput
+	err = conn.SelectContext(ctx, &dbInputs,
+		`SELECT i.recipe_id, i.resource_id, res.name, i.quantity
+		 FROM recipes_inputs i
+		 JOIN resources res ON res.id = i.resource_id
+		 ORDER BY i.recipe_id, i.resource_id`,
+	)
+	if err != nil {
+		log.Error("failed to get inputs", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	inputs := make(map[int][]model.RecipeItem)
+	for _, i := range dbInputs {
+		inputs[i.RecipeID] = append(inputs[i.RecipeID], i.toModel())
+	}
+
+	recipes := make([]model.Recipe, 0, len(dbRecipes))
+	for _, r := range dbRecipes {
+		recipe := r.toModel()
+		recipe.Inputs = inputs[r.ID]
+		recipes = append(recipes, recipe)
+	}
+
+	return recipes, nil
+}
+
+// GetInventory returns the quantity the user holds of every resource and the storage capacity:
+// baseCapacity plus the capacity of the businesses the user owns.
+func (s *Storage) GetInventory(ctx context.Context, userID, baseCapacity int) (model.Inventory, error) {
+	op := "production.GetInventory"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Inventory{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	inventory, err := getInventory(ctx, conn, userID, baseCapacity)
+	if err != nil {
+		log.Error("failed to get inventory", slog.String("error", err.Error()))
+		return model.Inventory{}, err
+	}
+
+	return inventory, nil
+}
+
+// GetDue returns the owned businesses whose recipe is due to run by now.
+func (s *Storage) GetDue(ctx context.Context, now time.Time) ([]model.Business, error) {
+	op := "production.GetDue"
+
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var dbBusinesses []struct {
+		ID       int `db:"id"`
+		OwnerID  int `db:"owner_id"`
+		RecipeID int `db:"recipe_id"`
+	}
+	err = conn.SelectContext(ctx, &dbBusinesses,
+		`SELECT b.id, b.owner_id, b.recipe_id
+		 FROM businesses b
+		 JOIN recipes r ON r.id = b.recipe_id
+		 WHERE b.owner_id IS NOT NULL AND (b.produced_at IS NULL OR b.produced_at + r.duration_minutes * INTERVAL '1 minute' <= $1)
+		 ORDER BY b.id`,
+		now,
+	)
+	if err != nil {
+		log.Error("failed to get due businesses", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	businesses := make([]model.Business, 0, len(dbBusinesses))
+	for _, b := range dbBusinesses {
+		businesses = append(businesses, model.Business{
+			ID:       b.ID,
+			OwnerID:  b.OwnerID,
+			RecipeID: b.RecipeID,
+		})
+	}
+
+	return businesses, nil
+}
+
+// Produce runs the recipe of the business once: it takes the inputs from the inventory of the owner
+// and puts the output there. It fails with ErrNotEnoughResources or ErrStorageFull leaving the business
+// due, so it produces on a later run once the owner has the inputs or the room.
+func (s *Storage) Produce(ctx context.Context, businessID, baseCapacity int, now time.Time) (model.Production, error) {
+	op := "production.Produce"
+
+	log := s.log.With(slog.String("op", op), slog.Int("businessID", businessID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Production{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Production{}, err
+	}
+
+	var business struct {
+		OwnerID  sql.NullInt64 `db:"owner_id"`
+		RecipeID sql.NullInt64 `db:"recipe_id"`
+		Due      bool          `db:"due"`
+	}
+	err = tx.GetContext(ctx, &business,
+		`SELECT b.owner_id, b.recipe_id,
+		 COALESCE(b.produced_at + r.duration_minutes * INTERVAL '1 minute' <= $2, TRUE) AS due
+		 FROM businesses b
+		 LEFT JOIN recipes r ON r.id = b.recipe_id
+		 WHERE b.id = $1
+		 FOR UPDATE OF b`,
+		businessID, now,
+	)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Production{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Production{}, errs.ErrBusinessNotFound
+		}
+
+		log.Error("failed to get business", slog.String("error", err.Error()))
+		return model.Production{}, err
+	}
+
+	if !business.OwnerID.Valid || !business.RecipeID.Valid || !business.Due {
+		if err := tx.Rollback(); err != nil {
+			return model.Production{}, err
+		}
+		return model.Production{}, errs.ErrProductionNotDue
+	}
+
+	ownerID := int(business.OwnerID.Int64)
+
+	if err := LockInventory(ctx, tx, ownerID); err != nil {
+		log.Error("failed to lock inventory", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Production{}, err
+		}
+		return model.Production{}, err
+	}
+
+	recipe, err := getRecipe(ctx, tx, int(business.RecipeID.Int64))
+	if err != nil {
+		log.Error("failed to get recipe", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Production{}, err
+		}
+		return model.Production{}, err
+	}
+
+	inventory, err := getInventory(ctx, tx, ownerID, baseCapacity)
+	if err != nil {
+		log.Error("failed to get inventory", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Production{}, err
+		}
+		return model.Production{}, err
+	}
+
+	held := make(map[int]int, len(inventory.Items))
+	for _, item := range inventory.Items {
+		held[item.ResourceID] = item.Quantity
+	}
+
+	used := inventory.Used + recipe.Output.Quantity
+	for _, input := range recipe.Inputs {
+		if held[input.ResourceID] < input.Quantity {
+			if err := tx.Rollback(); err != nil {
+				return model.Production{}, err
+			}
+			return model.Production{}, errs.ErrNotEnoughResources
+		}
+		used -= input.Quantity
+	}
+
+	if used > inventory.Capacity {
+		if err := tx.Rollback(); err != nil {
+			return model.Production{}, err
+		}
+		return model.Production{}, errs.ErrStorageFull
+	}
+
+	for _, input := range recipe.Inputs {
+		if err := AddToInventory(ctx, tx, ownerID, input.ResourceID, -input.Quantity); err != nil {
+			log.Error("failed to take input", slog.Int("resourceID", input.ResourceID), slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return model.Production{}, err
+			}
+			return model.Production{}, err
+		}
+	}
+
+	if err := AddToInventory(ctx, tx, ownerID, recipe.Output.ResourceID, recipe.Output.Quantity); err != nil {
+		log.Error("failed to add output", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Production{}, err
+		}
+		return model.Production{}, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE businesses SET produced_at = $1 WHERE id = $2`, now, businessID); err != nil {
+		log.Error("failed to update business", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Production{}, err
+		}
+		return model.Production{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Production{}, err
+	}
+
+	log.Info("produced", slog.Int("recipeID", recipe.ID), slog.Int("quantity", recipe.Output.Quantity))
+
+	return model.Production{
+		BusinessID: businessID,
+		OwnerID:    ownerID,
+		RecipeID:   recipe.ID,
+		Output:     recipe.Output,
+		ProducedAt: now,
+	}, nil
+}
+
+// Sell takes the quantity of the resource from the inventory of the user and pays its price for every unit.
+func (s *Storage) Sell(ctx context.Context, userID, resourceID, quantity int, now time.Time) (model.ResourceSale, error) {
+	op := "production.Sell"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID), slog.Int("resourceID", resourceID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.ResourceSale{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.ResourceSale{}, err
+	}
+
+	if err := LockInventory(ctx, tx, userID); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.ResourceSale{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.ResourceSale{}, errs.ErrUserNotFound
+		}
+
+		log.Error("failed to lock inventory", slog.String("error", err.Error()))
+		return model.ResourceSale{}, err
+	}
+
+	var held struct {
+		Quantity int     `db:"quantity"`
+		Price    float64 `db:"price"`
+	}
+	err = tx.GetContext(ctx, &held,
+		`SELECT COALESCE(i.quantity, 0) AS quantity, r.price
+		 FROM resources r
+		 LEFT JOIN users_inventories i ON i.resource_id = r.id AND i.user_id = $1
+		 WHERE r.id = $2`,
+		userID, resourceID,
+	)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.ResourceSale{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.ResourceSale{}, errs.ErrResourceNotFound
+		}
+
+		log.Error("failed to get resource", slog.String("error", err.Error()))
+		return model.ResourceSale{}, err
+	}
+
+	if held.Quantity < quantity {
+		if err := tx.Rollback(); err != nil {
+			return model.ResourceSale{}, err
+		}
+		return model.ResourceSale{}, errs.ErrNotEnoughResources
+	}
+
+	sale := model.ResourceSale{
+		ResourceID: resourceID,
+		Quantity:   quantity,
+		Amount:     math.Round(held.Price*float64(quantity)*100) / 100,
+		SoldAt:     now,
+	}
+
+	if err := AddToInventory(ctx, tx, userID, resourceID, -quantity); err != nil {
+		log.Error("failed to take resource", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.ResourceSale{}, err
+		}
+		return model.ResourceSale{}, err
+	}
+
+	if sale.Amount > 0 {
+		sale.TransactionID, err = transactions.Credit(ctx, tx, userID, sale.Amount, transactions.ResourceSaleTypeID)
+		if err != nil {
+			log.Error("failed to pay for resource", slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return model.ResourceSale{}, err
+			}
+			return model.ResourceSale{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.ResourceSale{}, err
+	}
+
+	log.Info("sold resource", slog.Int("quantity", quantity), slog.Float64("amount", sale.Amount))
+
+	return sale, nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+// LockInventory locks the balance of the user within tx, sql.ErrNoRows is returned as is for an unknown user.
+// Every change of an inventory takes this lock first, so the quantities and the capacity check of a user are never raced.
+func LockInventory(ctx context.Context, tx *sqlx.Tx, userID int) error {
+	var id int
+	return tx.GetContext(ctx, &id, `SELECT user_id FROM balances WHERE user_id = $1 FOR UPDATE`, userID)
+}
+
+// AddToInventory changes the quantity of the resource the user holds by delta.
+func AddToInventory(ctx context.Context, tx *sqlx.Tx, userID, resourceID, delta int) error {
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO users_inventories (user_id, resource_id, quantity) VALUES ($1, $2, $3)
+		 ON CONFLICT (user_id, resource_id) DO UPDATE SET quantity = users_inventories.quantity + EXCLUDED.quantity`,
+		userID, resourceID, delta,
+	)
+	return err
+}
+
+func getInventory(ctx context.Context, q sqlx.QueryerContext, userID, baseCapacity int) (model.Inventory, error) {
+	var dbItems []dbInventoryItem
+	err := sqlx.SelectContext(ctx, q, &dbItems,
+		`SELECT r.id AS resource_id, r.name, r.price, r.nutrition, r.joy, COALESCE(i.quantity, 0) AS quantity
+		 FROM resources r
+		 LEFT JOIN users_inventories i ON i.resource_id = r.id AND i.user_id = $1
+		 ORDER BY r.id`,
+		userID,
+	)
+	if err != nil {
+		return model.Inventory{}, err
+	}
+
+	var capacity int
+	err = sqlx.GetContext(ctx, q, &capacity,
+		`SELECT COALESCE(SUM(bt.capacity), 0) FROM businesses b JOIN businesses_types bt ON bt.id = b.type_id WHERE b.owner_id = $1`,
+		userID,
+	)
+	if err != nil {
+		return model.Inventory{}, err
+	}
+
+	inventory := model.Inventory{
+		UserID:   userID,
+		Items:    make([]model.InventoryItem, 0, len(dbItems)),
+		Capacity: baseCapacity + capacity,
+	}
+
+	for _, item := range dbItems {
+		inventory.Items = append(inventory.Items, item.toModel())
+		inventory.Used += item.Quantity
+	}
+
+	return inventory, nil
+}
+
+func getRecipe(ctx context.Context, q sqlx.QueryerContext, id int) (model.Recipe, error) {
+	var dbRecipe dbRecipe
+	err := sqlx.GetContext(ctx, q, &dbRecipe,
+		`SELECT `+recipeColumns+` FROM recipes r JOIN resources res ON res.id = r.output_resource_id WHERE r.id = $1`,
+		id,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Recipe{}, errs.ErrRecipeNotFound
+		}
+		return model.Recipe{}, err
+	}
+
+	var dbInputs []dbInput
+	err = sqlx.SelectContext(ctx, q, &dbInputs,
+		`SELECT i.recipe_id, i.resource_id, res.name, i.quantity
+		 FROM recipes_inputs i
+		 JOIN resources res ON res.id = i.resource_id
+		 WHERE i.recipe_id = $1
+		 ORDER BY i.resource_id`,
+		id,
+	)
+	if err != nil {
+		return model.Recipe{}, err
+	}
+
+	recipe := dbRecipe.toModel()
+	for _, i := range dbInputs {
+		recipe.Inputs = append(recipe.Inputs, i.toModel())
+	}
+
+	return recipe, nil
+}
+
+const recipeColumns = `r.id, r.business_type_id, r.name, r.output_resource_id, res.name AS output_name, r.output_quantity, r.duration_minutes`
+
+type dbRecipe struct {
+	ID               int    `db:"id"`
+	BusinessTypeID   int    `db:"business_type_id"`
+	Name             string `db:"name"`
+	OutputResourceID int    `db:"output_resource_id"`
+	OutputName       string `db:"output_name"`
+	OutputQuantity   int    `db:"output_quantity"`
+	DurationMinutes  int    `db:"duration_minutes"`
+}
+
+func (r dbRecipe) toModel() model.Recipe {
+	return model.Recipe{
+		ID:             r.ID,
+		BusinessTypeID: r.BusinessTypeID,
+		Name:           r.Name,
+		Output: model.RecipeItem{
+			ResourceID: r.OutputResourceID,
+			Name:       r.OutputName,
+			Quantity:   r.OutputQuantity,
+		},
+		Duration: time.Duration(r.DurationMinutes) * time.Minute,
+	}
+}
+
+type dbInput struct {
+	RecipeID   int    `db:"recipe_id"`
+	ResourceID int    `db:"resource_id"`
+	Name       string `db:"name"`
+	Quantity   int    `db:"quantity"`
+}
+
+func (i dbInput) toModel() model.RecipeItem {
+	return model.RecipeItem{
+		ResourceID: i.ResourceID,
+		Name:       i.Name,
+		Quantity:   i.Quantity,
+	}
+}
+
+type dbInventoryItem struct {
+	ResourceID int     `db:"resource_id"`
+	Name       string  `db:"name"`
+	Price      float64 `db:"price"`
+	Nutrition  int     `db:"nutrition"`
+	Joy        int     `db:"joy"`
+	Quantity   int     `db:"quantity"`
+}
+
+func (i dbInventoryItem) toModel() model.InventoryItem {
+	return model.InventoryItem{
+		ResourceID: i.ResourceID,
+		Name:       i.Name,
+		Price:      i.Price,
+		Nutrition:  i.Nutrition,
+		Joy:        i.Joy,
+		Quantity:   i.Quantity,
+	}
+}
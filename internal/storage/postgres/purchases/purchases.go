@@ -2,8 +2,13 @@ package purchases
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"github.com/jmoiron/sqlx"
 	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/production"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/transactions"
 	"log/slog"
 	"time"
 )
@@ -76,13 +81,67 @@ func (s *Storage) Add(ctx context.Context, purchase model.Purchase) (int, error)
 		}
 	}(conn)
 
-	query := `INSERT INTO purchases (item_id, user_id) VALUES ($1, $2) RETURNING id`
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	var minLevel int
+	err = tx.GetContext(ctx, &minLevel, `SELECT min_level FROM shop_items WHERE id = $1 FOR SHARE`, purchase.ShopItemID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errs.ErrShopItemNotFound
+		}
+
+		log.Error("failed to get item", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	// the buyer is locked, so the level can not change until the purchase is added
+	var level int
+	err = tx.GetContext(ctx, &level, `SELECT level FROM users WHERE id = $1 FOR SHARE`, purchase.BuyerID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errs.ErrUserNotFound
+		}
+
+		log.Error("failed to get buyer", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	if level < minLevel {
+		log.Error("level is too low for the item", slog.Int("level", level), slog.Int("minLevel", minLevel))
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+		return 0, errs.ErrLevelTooLow
+	}
 
 	var id int
 
-	err = conn.QueryRowxContext(ctx, query, purchase.ShopItemID, purchase.BuyerID).Scan(&id)
+	err = tx.QueryRowxContext(ctx,
+		`INSERT INTO purchases (item_id, user_id) VALUES ($1, $2) RETURNING id`,
+		purchase.ShopItemID, purchase.BuyerID,
+	).Scan(&id)
 	if err != nil {
 		log.Error("failed to add purchase", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
 		return 0, err
 	}
 
@@ -91,10 +150,181 @@ func (s *Storage) Add(ctx context.Context, purchase model.Purchase) (int, error)
 	return id, nil
 }
 
+// Buy sells the shop item to the user in one transaction: it checks the stock, the level requirement
+// and the balance, charges the price, takes the item from the stock and applies its effects, such as
+// adding streak freezes.
+func (s *Storage) Buy(ctx context.Context, userID, itemID int) (model.Purchase, error) {
+	op := "purchases.Buy"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID), slog.Int("itemID", itemID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Purchase{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Purchase{}, err
+	}
+
+	var item struct {
+		Price    float64 `db:"price"`
+		InStock  int     `db:"in_stock"`
+		MinLevel int     `db:"min_level"`
+	}
+	err = tx.GetContext(ctx, &item, `SELECT price, in_stock, min_level FROM shop_items WHERE id = $1 FOR UPDATE`, itemID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Purchase{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Purchase{}, errs.ErrShopItemNotFound
+		}
+
+		log.Error("failed to get item", slog.String("error", err.Error()))
+		return model.Purchase{}, err
+	}
+
+	if item.InStock <= 0 {
+		if err := tx.Rollback(); err != nil {
+			return model.Purchase{}, err
+		}
+		return model.Purchase{}, errs.ErrShopItemSoldOut
+	}
+
+	var level int
+	err = tx.GetContext(ctx, &level, `SELECT level FROM users WHERE id = $1`, userID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Purchase{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Purchase{}, errs.ErrUserNotFound
+		}
+
+		log.Error("failed to get buyer", slog.String("error", err.Error()))
+		return model.Purchase{}, err
+	}
+
+	if level < item.MinLevel {
+		if err := tx.Rollback(); err != nil {
+			return model.Purchase{}, err
+		}
+		return model.Purchase{}, errs.ErrLevelTooLow
+	}
+
+	if _, err := transactions.Debit(ctx, tx, userID, item.Price, transactions.PurchaseTypeID); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Purchase{}, err
+		}
+
+		if errors.Is(err, errs.ErrInsufficientFunds) {
+			return model.Purchase{}, err
+		}
+
+		log.Error("failed to pay for item", slog.String("error", err.Error()))
+		return model.Purchase{}, err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE shop_items SET in_stock = in_stock - 1 WHERE id = $1`, itemID)
+	if err != nil {
+		log.Error("failed to update stock", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Purchase{}, err
+		}
+		return model.Purchase{}, err
+	}
+
+	purchase, err := Deliver(ctx, tx, userID, itemID)
+	if err != nil {
+		log.Error("failed to deliver item", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Purchase{}, err
+		}
+		return model.Purchase{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Purchase{}, err
+	}
+
+	log.Info("bought item", slog.Int("purchaseID", purchase.ID))
+
+	return purchase, nil
+}
+
 func (s *Storage) Close() error {
 	return s.db.Close()
 }
 
+// Deliver hands the item over to the user within the transaction: it records the purchase and applies
+// the effects of the item, such as adding streak freezes or resources. Resources bought in the shop do
+// not count against the storage capacity. The item has to be paid for and taken from the stock by the caller.
+func Deliver(ctx context.Context, tx *sqlx.Tx, userID, itemID int) (model.Purchase, error) {
+	var effects struct {
+		StreakFreezes    int           `db:"streak_freezes"`
+		ResourceID       sql.NullInt64 `db:"resource_id"`
+		ResourceQuantity int           `db:"resource_quantity"`
+	}
+	err := tx.GetContext(ctx, &effects, `SELECT streak_freezes, resource_id, resource_quantity FROM shop_items WHERE id = $1`, itemID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Purchase{}, errs.ErrShopItemNotFound
+		}
+		return model.Purchase{}, err
+	}
+
+	purchase := model.Purchase{
+		ShopItemID: itemID,
+		BuyerID:    userID,
+	}
+
+	err = tx.QueryRowxContext(ctx,
+		`INSERT INTO purchases (item_id, user_id) VALUES ($1, $2) RETURNING id, created_at`,
+		itemID, userID,
+	).Scan(&purchase.ID, &purchase.CreatedAt)
+	if err != nil {
+		return model.Purchase{}, err
+	}
+
+	if effects.StreakFreezes > 0 {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO users_streaks (user_id, freezes) VALUES ($1, $2)
+			 ON CONFLICT (user_id) DO UPDATE SET freezes = users_streaks.freezes + EXCLUDED.freezes, updated_at = NOW()`,
+			userID, effects.StreakFreezes,
+		)
+		if err != nil {
+			return model.Purchase{}, err
+		}
+	}
+
+	if effects.ResourceID.Valid && effects.ResourceQuantity > 0 {
+		if err := production.LockInventory(ctx, tx, userID); err != nil {
+			return model.Purchase{}, err
+		}
+
+		if err := production.AddToInventory(ctx, tx, userID, int(effects.ResourceID.Int64), effects.ResourceQuantity); err != nil {
+			return model.Purchase{}, err
+		}
+	}
+
+	return purchase, nil
+}
+
 type dbPurchase struct {
 	ID         int
 	ShopItemID int
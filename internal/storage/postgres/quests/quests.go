@@ -0,0 +1,902 @@
+package quests
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/quests"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/budgets"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/nullable"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/tasks"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/transactions"
+	"github.com/lib/pq"
+	"log/slog"
+	"time"
+)
+
+type Storage struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
+	return &Storage{
+		db:  db,
+		log: log,
+	}
+}
+
+// Add stores the quest with its steps and their prerequisites, the quest stays hidden until published.
+func (s *Storage) Add(ctx context.Context, quest model.Quest) (int, error) {
+	op := "quests.Add"
+
+	log := s.log.With(slog.String("op", op), slog.Int("createdBy", quest.CreatedBy))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	var id int
+	err = tx.QueryRowxContext(ctx,
+		`INSERT INTO quests (name, description, bonus, created_by) VALUES ($1, $2, $3, $4) RETURNING id`,
+		quest.Name, quest.Description, quest.Bonus, quest.CreatedBy,
+	).Scan(&id)
+	if err != nil {
+		log.Error("failed to add quest", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+		return 0, err
+	}
+
+	if err := addSteps(ctx, tx, id, quest.Steps); err != nil {
+		log.Error("failed to add steps", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	log.Info("added quest", slog.Int("id", id))
+
+	return id, nil
+}
+
+// Update replaces the quest and its steps. Published quests may already be in progress,
+// so they can not be changed anymore.
+func (s *Storage) Update(ctx context.Context, quest model.Quest) error {
+	op := "quests.Update"
+
+	log := s.log.With(slog.String("op", op), slog.Int("id", quest.ID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return err
+	}
+
+	var publishedAt sql.NullTime
+	err = tx.GetContext(ctx, &publishedAt, `SELECT published_at FROM quests WHERE id = $1 FOR UPDATE`, quest.ID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.ErrQuestNotFound
+		}
+
+		log.Error("failed to lock quest", slog.String("error", err.Error()))
+		return err
+	}
+
+	if publishedAt.Valid {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+		return errs.ErrQuestAlreadyPublished
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE quests SET name = $1, description = $2, bonus = $3 WHERE id = $4`,
+		quest.Name, quest.Description, quest.Bonus, quest.ID,
+	)
+	if err != nil {
+		log.Error("failed to update quest", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+		return err
+	}
+
+	// prerequisites are removed with their steps
+	if _, err := tx.ExecContext(ctx, `DELETE FROM quests_steps WHERE quest_id = $1`, quest.ID); err != nil {
+		log.Error("failed to delete steps", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+		return err
+	}
+
+	if err := addSteps(ctx, tx, quest.ID, quest.Steps); err != nil {
+		log.Error("failed to add steps", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("updated quest")
+
+	return nil
+}
+
+// Publish makes the quest visible to users, its rewards are charged to the budget from then on.
+func (s *Storage) Publish(ctx context.Context, id, budgetID int) error {
+	op := "quests.Publish"
+
+	log := s.log.With(slog.String("op", op), slog.Int("id", id))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var publishedAt sql.NullTime
+	err = conn.GetContext(ctx, &publishedAt, `SELECT published_at FROM quests WHERE id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.ErrQuestNotFound
+		}
+
+		log.Error("failed to get quest", slog.String("error", err.Error()))
+		return err
+	}
+
+	res, err := conn.ExecContext(ctx,
+		`UPDATE quests SET published_at = NOW(), budget_id = $2 WHERE id = $1 AND published_at IS NULL`,
+		id, nullable.ID(budgetID),
+	)
+	if err != nil {
+		log.Error("failed to publish quest", slog.String("error", err.Error()))
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		log.Error("failed to get affected rows", slog.String("error", err.Error()))
+		return err
+	}
+
+	if affected == 0 {
+		return errs.ErrQuestAlreadyPublished
+	}
+
+	log.Info("published quest")
+
+	return nil
+}
+
+// GetByID returns the quest with its steps, published or not.
+func (s *Storage) GetByID(ctx context.Context, id int) (model.Quest, error) {
+	op := "quests.GetByID"
+
+	log := s.log.With(slog.String("op", op), slog.Int("id", id))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Quest{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	quest, err := getQuest(ctx, conn, id, false)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Quest{}, errs.ErrQuestNotFound
+		}
+
+		log.Error("failed to get quest", slog.String("error", err.Error()))
+		return model.Quest{}, err
+	}
+
+	return quest, nil
+}
+
+// GetAll returns the quests with their steps, newest first. Unpublished quests are left out if publishedOnly is set.
+func (s *Storage) GetAll(ctx context.Context, publishedOnly bool) ([]model.Quest, error) {
+	op := "quests.GetAll"
+
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var dbQuests []dbQuest
+	err = conn.SelectContext(ctx, &dbQuests,
+		`SELECT `+questColumns+` FROM quests WHERE NOT $1 OR published_at IS NOT NULL ORDER BY created_at DESC, id DESC`,
+		publishedOnly,
+	)
+	if err != nil {
+		log.Error("failed to get quests", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(dbQuests))
+	for _, q := range dbQuests {
+		ids = append(ids, int64(q.ID))
+	}
+
+	steps, err := getSteps(ctx, conn, ids)
+	if err != nil {
+		log.Error("failed to get steps", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	result := make([]model.Quest, 0, len(dbQuests))
+	for _, q := range dbQuests {
+		quest := q.toModel()
+		quest.Steps = steps[q.ID]
+		result = append(result, quest)
+	}
+
+	return result, nil
+}
+
+// GetProgress returns the progress of the user in every quest the user has started.
+func (s *Storage) GetProgress(ctx context.Context, userID int) ([]model.QuestProgress, error) {
+	op := "quests.GetProgress"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var dbProgress []dbProgress
+	err = conn.SelectContext(ctx, &dbProgress,
+		`SELECT user_id, quest_id, started_at, completed_at, COALESCE(bonus_transaction_id, 0) AS bonus_transaction_id
+		 FROM users_quests WHERE user_id = $1 ORDER BY started_at`,
+		userID,
+	)
+	if err != nil {
+		log.Error("failed to get progress", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var dbSteps []dbStepProgress
+	err = conn.SelectContext(ctx, &dbSteps,
+		`SELECT s.quest_id, s.position, s.task_id, t.status_id AS task_status_id, s.unlocked_at, s.completed_at
+		 FROM users_quests_steps s
+		 JOIN tasks t ON t.id = s.task_id
+		 WHERE s.user_id = $1
+		 ORDER BY s.quest_id, s.position`,
+		userID,
+	)
+	if err != nil {
+		log.Error("failed to get step progress", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	steps := make(map[int][]model.QuestStepProgress)
+	for _, step := range dbSteps {
+		steps[step.QuestID] = append(steps[step.QuestID], step.toModel())
+	}
+
+	progress := make([]model.QuestProgress, 0, len(dbProgress))
+	for _, p := range dbProgress {
+		questProgress := p.toModel()
+		questProgress.Steps = steps[p.QuestID]
+		progress = append(progress, questProgress)
+	}
+
+	return progress, nil
+}
+
+// Start begins the published quest for the user and assigns the tasks of the steps
+// that have no prerequisites. The step rewards and the bonus the user may earn are reserved
+// from the budget of the quest, it returns ErrBudgetExceeded if they do not fit into it.
+func (s *Storage) Start(ctx context.Context, userID, questID int) (model.QuestProgress, error) {
+	op := "quests.Start"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID), slog.Int("questID", questID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.QuestProgress{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.QuestProgress{}, err
+	}
+
+	quest, err := getQuest(ctx, tx, questID, true)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.QuestProgress{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.QuestProgress{}, errs.ErrQuestNotFound
+		}
+
+		log.Error("failed to get quest", slog.String("error", err.Error()))
+		return model.QuestProgress{}, err
+	}
+
+	progress := model.QuestProgress{
+		QuestID: questID,
+		UserID:  userID,
+	}
+
+	err = tx.QueryRowxContext(ctx,
+		`INSERT INTO users_quests (user_id, quest_id) VALUES ($1, $2) RETURNING started_at`,
+		userID, questID,
+	).Scan(&progress.StartedAt)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.QuestProgress{}, err
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			switch pqErr.Code {
+			case "23505":
+				return model.QuestProgress{}, errs.ErrQuestAlreadyStarted
+			case "23503":
+				return model.QuestProgress{}, errs.ErrUserNotFound
+			}
+		}
+
+		log.Error("failed to start quest", slog.String("error", err.Error()))
+		return model.QuestProgress{}, err
+	}
+
+	if quest.BudgetID != 0 {
+		cost := quest.Bonus
+		for _, step := range quest.Steps {
+			cost += step.Amount
+		}
+
+		if err := budgets.Hold(ctx, tx, quest.BudgetID, cost); err != nil {
+			if err := tx.Rollback(); err != nil {
+				return model.QuestProgress{}, err
+			}
+
+			if !errors.Is(err, errs.ErrBudgetExceeded) {
+				log.Error("failed to reserve budget", slog.String("error", err.Error()))
+			}
+			return model.QuestProgress{}, err
+		}
+	}
+
+	progress.Steps, err = unlock(ctx, tx, quest, userID, nil, nil)
+	if err != nil {
+		log.Error("failed to unlock steps", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.QuestProgress{}, err
+		}
+		return model.QuestProgress{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.QuestProgress{}, err
+	}
+
+	log.Info("started quest", slog.Int("unlocked", len(progress.Steps)))
+
+	return progress, nil
+}
+
+// GetStalledSteps returns the tasks of the quest steps that were accepted but not advanced,
+// because the quest failed to advance after the task was accepted.
+func (s *Storage) GetStalledSteps(ctx context.Context) ([]int, error) {
+	op := "quests.GetStalledSteps"
+
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var taskIDs []int
+	err = conn.SelectContext(ctx, &taskIDs,
+		`SELECT s.task_id
+		 FROM users_quests_steps s
+		 JOIN tasks t ON t.id = s.task_id
+		 WHERE s.completed_at IS NULL AND t.status_id = $1
+		 ORDER BY s.task_id`,
+		tasks.CompletedStatusID,
+	)
+	if err != nil {
+		log.Error("failed to get stalled steps", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return taskIDs, nil
+}
+
+// Advance completes the quest step done as the task, assigns the tasks of the steps it unlocks and,
+// once every step is completed, pays the quest bonus. The quest of the user is locked, so steps
+// completed concurrently can not unlock a step twice or pay the bonus twice. A task that is not
+// an open quest step returns ErrQuestStepNotFound.
+func (s *Storage) Advance(ctx context.Context, taskID int) (model.QuestAdvance, error) {
+	op := "quests.Advance"
+
+	log := s.log.With(slog.String("op", op), slog.Int("taskID", taskID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.QuestAdvance{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.QuestAdvance{}, err
+	}
+
+	var advance model.QuestAdvance
+	err = tx.QueryRowxContext(ctx,
+		`SELECT uq.user_id, uq.quest_id
+		 FROM users_quests uq
+		 JOIN users_quests_steps s ON s.user_id = uq.user_id AND s.quest_id = uq.quest_id
+		 WHERE s.task_id = $1 AND s.completed_at IS NULL
+		 FOR UPDATE OF uq`,
+		taskID,
+	).Scan(&advance.UserID, &advance.QuestID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.QuestAdvance{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.QuestAdvance{}, errs.ErrQuestStepNotFound
+		}
+
+		log.Error("failed to lock quest progress", slog.String("error", err.Error()))
+		return model.QuestAdvance{}, err
+	}
+
+	res, err := tx.ExecContext(ctx, `UPDATE users_quests_steps SET completed_at = NOW() WHERE task_id = $1 AND completed_at IS NULL`, taskID)
+	if err != nil {
+		log.Error("failed to complete step", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.QuestAdvance{}, err
+		}
+		return model.QuestAdvance{}, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		log.Error("failed to get affected rows", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.QuestAdvance{}, err
+		}
+		return model.QuestAdvance{}, err
+	}
+
+	// the step may have been completed while waiting for the lock
+	if affected == 0 {
+		if err := tx.Rollback(); err != nil {
+			return model.QuestAdvance{}, err
+		}
+		return model.QuestAdvance{}, errs.ErrQuestStepNotFound
+	}
+
+	quest, err := getQuest(ctx, tx, advance.QuestID, false)
+	if err != nil {
+		log.Error("failed to get quest", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.QuestAdvance{}, err
+		}
+		return model.QuestAdvance{}, err
+	}
+
+	advance.QuestName = quest.Name
+
+	var dbSteps []dbStepState
+	err = tx.SelectContext(ctx, &dbSteps,
+		`SELECT position, completed_at IS NOT NULL AS completed FROM users_quests_steps WHERE user_id = $1 AND quest_id = $2`,
+		advance.UserID, advance.QuestID,
+	)
+	if err != nil {
+		log.Error("failed to get step progress", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.QuestAdvance{}, err
+		}
+		return model.QuestAdvance{}, err
+	}
+
+	unlocked := make(map[int]bool, len(dbSteps))
+	completed := make(map[int]bool, len(dbSteps))
+	for _, step := range dbSteps {
+		unlocked[step.Position] = true
+		completed[step.Position] = step.Completed
+	}
+
+	advance.Unlocked, err = unlock(ctx, tx, quest, advance.UserID, unlocked, completed)
+	if err != nil {
+		log.Error("failed to unlock steps", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.QuestAdvance{}, err
+		}
+		return model.QuestAdvance{}, err
+	}
+
+	done := 0
+	for _, step := range dbSteps {
+		if step.Completed {
+			done++
+		}
+	}
+
+	if done == len(quest.Steps) {
+		advance.Completed = true
+		advance.Bonus = quest.Bonus
+
+		var transactionID int
+		if quest.Bonus > 0 {
+			transactionID, err = transactions.Credit(ctx, tx, advance.UserID, quest.Bonus, transactions.BonusTypeID)
+			if err != nil {
+				log.Error("failed to pay bonus", slog.String("error", err.Error()))
+				if err := tx.Rollback(); err != nil {
+					return model.QuestAdvance{}, err
+				}
+				return model.QuestAdvance{}, err
+			}
+
+			if quest.BudgetID != 0 {
+				if err := budgets.Spend(ctx, tx, quest.BudgetID, quest.Bonus, quest.Bonus); err != nil {
+					log.Error("failed to spend budget", slog.String("error", err.Error()))
+					if err := tx.Rollback(); err != nil {
+						return model.QuestAdvance{}, err
+					}
+					return model.QuestAdvance{}, err
+				}
+			}
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`UPDATE users_quests SET completed_at = NOW(), bonus_transaction_id = $1 WHERE user_id = $2 AND quest_id = $3`,
+			nullable.ID(transactionID), advance.UserID, advance.QuestID,
+		)
+		if err != nil {
+			log.Error("failed to complete quest", slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return model.QuestAdvance{}, err
+			}
+			return model.QuestAdvance{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.QuestAdvance{}, err
+	}
+
+	log.Info("advanced quest", slog.Int("unlocked", len(advance.Unlocked)), slog.Bool("completed", advance.Completed))
+
+	return advance, nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+// addSteps inserts the steps of the quest, then their prerequisites, which refer to the steps by position.
+func addSteps(ctx context.Context, tx *sqlx.Tx, questID int, steps []model.QuestStep) error {
+	for _, step := range steps {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO quests_steps (quest_id, position, name, description, amount) VALUES ($1, $2, $3, $4, $5)`,
+			questID, step.Position, step.Name, step.Description, step.Amount,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, step := range steps {
+		for _, required := range step.Requires {
+			_, err := tx.ExecContext(ctx,
+				`INSERT INTO quests_steps_prerequisites (quest_id, position, requires_position) VALUES ($1, $2, $3)
+				 ON CONFLICT DO NOTHING`,
+				questID, step.Position, required,
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// unlock assigns the user a task for every step that became unlockable and records it as unlocked.
+func unlock(ctx context.Context, tx *sqlx.Tx, quest model.Quest, userID int, unlocked, completed map[int]bool) ([]model.QuestStepProgress, error) {
+	steps := make(map[int]model.QuestStep, len(quest.Steps))
+	for _, step := range quest.Steps {
+		steps[step.Position] = step
+	}
+
+	var progress []model.QuestStepProgress
+	for _, position := range quests.Unlockable(quest.Steps, unlocked, completed) {
+		step := steps[position]
+
+		stepProgress := model.QuestStepProgress{
+			Position:     position,
+			TaskStatusID: tasks.InProgressStatusID,
+		}
+
+		err := tx.QueryRowxContext(ctx,
+			`INSERT INTO tasks (name, description, status_id, amount, created_by, for_group_id, user_id, category_id, budget_id)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`,
+			step.Name, step.Description, tasks.InProgressStatusID, step.Amount, quest.CreatedBy,
+			tasks.UserGroupID, userID, tasks.GeneralCategoryID, nullable.ID(quest.BudgetID),
+		).Scan(&stepProgress.TaskID)
+		if err != nil {
+			return nil, err
+		}
+
+		err = tx.QueryRowxContext(ctx,
+			`INSERT INTO users_quests_steps (user_id, quest_id, position, task_id) VALUES ($1, $2, $3, $4) RETURNING unlocked_at`,
+			userID, quest.ID, position, stepProgress.TaskID,
+		).Scan(&stepProgress.UnlockedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		progress = append(progress, stepProgress)
+	}
+
+	return progress, nil
+}
+
+// getQuest selects the quest with its steps, sql.ErrNoRows is returned as is.
+func getQuest(ctx context.Context, q sqlx.QueryerContext, id int, publishedOnly bool) (model.Quest, error) {
+	var quest dbQuest
+	err := sqlx.GetContext(ctx, q, &quest,
+		`SELECT `+questColumns+` FROM quests WHERE id = $1 AND (NOT $2 OR published_at IS NOT NULL)`,
+		id, publishedOnly,
+	)
+	if err != nil {
+		return model.Quest{}, err
+	}
+
+	steps, err := getSteps(ctx, q, []int64{int64(id)})
+	if err != nil {
+		return model.Quest{}, err
+	}
+
+	result := quest.toModel()
+	result.Steps = steps[id]
+
+	return result, nil
+}
+
+// getSteps selects the steps of the quests with their prerequisites, grouped by quest.
+func getSteps(ctx context.Context, q sqlx.QueryerContext, questIDs []int64) (map[int][]model.QuestStep, error) {
+	query := `SELECT s.quest_id, s.position, s.name, s.description, s.amount,
+			  COALESCE(ARRAY_AGG(p.requires_position ORDER BY p.requires_position) FILTER (WHERE p.requires_position IS NOT NULL), '{}') AS requires
+			  FROM quests_steps s
+			  LEFT JOIN quests_steps_prerequisites p ON p.quest_id = s.quest_id AND p.position = s.position
+			  WHERE s.quest_id = ANY($1)
+			  GROUP BY s.quest_id, s.position
+			  ORDER BY s.quest_id, s.position`
+
+	var dbSteps []dbStep
+	if err := sqlx.SelectContext(ctx, q, &dbSteps, query, pq.Array(questIDs)); err != nil {
+		return nil, err
+	}
+
+	steps := make(map[int][]model.QuestStep)
+	for _, step := range dbSteps {
+		steps[step.QuestID] = append(steps[step.QuestID], step.toModel())
+	}
+
+	return steps, nil
+}
+
+const questColumns = `id, name, description, bonus, created_by, COALESCE(budget_id, 0) AS budget_id, published_at, created_at`
+
+type dbQuest struct {
+	ID          int          `db:"id"`
+	Name        string       `db:"name"`
+	Description string       `db:"description"`
+	Bonus       float64      `db:"bonus"`
+	CreatedBy   int          `db:"created_by"`
+	BudgetID    int          `db:"budget_id"`
+	PublishedAt sql.NullTime `db:"published_at"`
+	CreatedAt   time.Time    `db:"created_at"`
+}
+
+func (q dbQuest) toModel() model.Quest {
+	return model.Quest{
+		ID:          q.ID,
+		Name:        q.Name,
+		Description: q.Description,
+		Bonus:       q.Bonus,
+		CreatedBy:   q.CreatedBy,
+		BudgetID:    q.BudgetID,
+		PublishedAt: q.PublishedAt.Time,
+		CreatedAt:   q.CreatedAt,
+	}
+}
+
+type dbStep struct {
+	QuestID     int           `db:"quest_id"`
+	Position    int           `db:"position"`
+	Name        string        `db:"name"`
+	Description string        `db:"description"`
+	Amount      float64       `db:"amount"`
+	Requires    pq.Int64Array `db:"requires"`
+}
+
+func (s dbStep) toModel() model.QuestStep {
+	requires := make([]int, 0, len(s.Requires))
+	for _, position := range s.Requires {
+		requires = append(requires, int(position))
+	}
+
+	return model.QuestStep{
+		QuestID:     s.QuestID,
+		Position:    s.Position,
+		Name:        s.Name,
+		Description: s.Description,
+		Amount:      s.Amount,
+		Requires:    requires,
+	}
+}
+
+type dbProgress struct {
+	UserID             int          `db:"user_id"`
+	QuestID            int          `db:"quest_id"`
+	StartedAt          time.Time    `db:"started_at"`
+	CompletedAt        sql.NullTime `db:"completed_at"`
+	BonusTransactionID int          `db:"bonus_transaction_id"`
+}
+
+func (p dbProgress) toModel() model.QuestProgress {
+	return model.QuestProgress{
+		QuestID:            p.QuestID,
+		UserID:             p.UserID,
+		StartedAt:          p.StartedAt,
+		CompletedAt:        p.CompletedAt.Time,
+		BonusTransactionID: p.BonusTransactionID,
+	}
+}
+
+type dbStepProgress struct {
+	QuestID      int          `db:"quest_id"`
+	Position     int          `db:"position"`
+	TaskID       int          `db:"task_id"`
+	TaskStatusID int          `db:"task_status_id"`
+	UnlockedAt   time.Time    `db:"unlocked_at"`
+	CompletedAt  sql.NullTime `db:"completed_at"`
+}
+
+func (s dbStepProgress) toModel() model.QuestStepProgress {
+	return model.QuestStepProgress{
+		Position:     s.Position,
+		TaskID:       s.TaskID,
+		TaskStatusID: s.TaskStatusID,
+		UnlockedAt:   s.UnlockedAt,
+		CompletedAt:  s.CompletedAt.Time,
+	}
+}
+
+type dbStepState struct {
+	Position  int  `db:"position"`
+	Completed bool `db:"completed"`
+}
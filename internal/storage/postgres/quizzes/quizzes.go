@@ -0,0 +1,782 @@
+package quizzes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	quizzeslib "github.com/k6mil6/hackathon-game-backend/internal/lib/quizzes"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/budgets"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/nullable"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/transactions"
+	"github.com/lib/pq"
+	"log/slog"
+	"time"
+)
+
+const (
+	QuizTypeID = 1
+	PollTypeID = 2
+)
+
+type Storage struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
+	return &Storage{
+		db:  db,
+		log: log,
+	}
+}
+
+// Create adds the quiz with its questions.
+func (s *Storage) Create(ctx context.Context, quiz model.Quiz) (model.Quiz, error) {
+	op := "quizzes.Create"
+
+	log := s.log.With(slog.String("op", op), slog.Int("adminID", quiz.CreatedBy))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Quiz{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Quiz{}, err
+	}
+
+	query := `INSERT INTO quizzes (type_id, name, description, amount, pass_score, closes_at, created_by, budget_id)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			  RETURNING id`
+
+	err = tx.QueryRowxContext(ctx, query,
+		quiz.TypeID,
+		quiz.Name,
+		quiz.Description,
+		quiz.Amount,
+		quiz.PassScore,
+		nullableTime(quiz.ClosesAt),
+		quiz.CreatedBy,
+		nullable.ID(quiz.BudgetID),
+	).Scan(&quiz.ID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Quiz{}, err
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return model.Quiz{}, errs.ErrAdminNotFound
+		}
+
+		log.Error("failed to add quiz", slog.String("error", err.Error()))
+		return model.Quiz{}, err
+	}
+
+	questionQuery := `INSERT INTO quizzes_questions (quiz_id, position, kind_id, text, options, correct_options, correct_number, tolerance, correct_texts, points)
+					  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	for _, q := range quiz.Questions {
+		_, err := tx.ExecContext(ctx, questionQuery,
+			quiz.ID,
+			q.Position,
+			q.KindID,
+			q.Text,
+			toStringArray(q.Options),
+			toInt64Array(q.CorrectOptions),
+			q.CorrectNumber,
+			q.Tolerance,
+			toStringArray(q.CorrectTexts),
+			q.Points,
+		)
+		if err != nil {
+			log.Error("failed to add question", slog.Int("position", q.Position), slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return model.Quiz{}, err
+			}
+			return model.Quiz{}, err
+		}
+	}
+
+	created, err := getQuiz(ctx, tx, quiz.ID, 0)
+	if err != nil {
+		log.Error("failed to get quiz", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Quiz{}, err
+		}
+		return model.Quiz{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Quiz{}, err
+	}
+
+	log.Info("created quiz", slog.Int("id", quiz.ID))
+
+	return created, nil
+}
+
+// Finish stops the quiz from taking submissions.
+func (s *Storage) Finish(ctx context.Context, id int, now time.Time) (model.Quiz, error) {
+	op := "quizzes.Finish"
+
+	log := s.log.With(slog.String("op", op), slog.Int("id", id))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Quiz{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	res, err := conn.ExecContext(ctx,
+		`UPDATE quizzes SET closed_at = $1 WHERE id = $2 AND closed_at IS NULL AND (closes_at IS NULL OR closes_at > $1)`,
+		now, id,
+	)
+	if err != nil {
+		log.Error("failed to close quiz", slog.String("error", err.Error()))
+		return model.Quiz{}, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		log.Error("failed to get affected rows", slog.String("error", err.Error()))
+		return model.Quiz{}, err
+	}
+
+	quiz, err := getQuiz(ctx, conn, id, 0)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Quiz{}, errs.ErrQuizNotFound
+		}
+
+		log.Error("failed to get quiz", slog.String("error", err.Error()))
+		return model.Quiz{}, err
+	}
+
+	if affected == 0 {
+		return model.Quiz{}, errs.ErrQuizClosed
+	}
+
+	log.Info("finished quiz")
+
+	return quiz, nil
+}
+
+// Submit saves the graded submission of the user and pays its reward, which is charged to the budget
+// of the quiz. The quiz is locked in share mode, so it can not be closed in the middle of the submission.
+// It returns ErrBudgetExceeded if the reward does not fit into the budget.
+func (s *Storage) Submit(ctx context.Context, submission model.QuizSubmission, now time.Time) (model.QuizSubmission, error) {
+	op := "quizzes.Submit"
+
+	log := s.log.With(slog.String("op", op), slog.Int("quizID", submission.QuizID), slog.Int("userID", submission.UserID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.QuizSubmission{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.QuizSubmission{}, err
+	}
+
+	var quiz struct {
+		Open     bool `db:"open"`
+		BudgetID int  `db:"budget_id"`
+	}
+	err = tx.GetContext(ctx, &quiz,
+		`SELECT closed_at IS NULL AND (closes_at IS NULL OR closes_at > $2) AS open, COALESCE(budget_id, 0) AS budget_id
+		 FROM quizzes WHERE id = $1 FOR SHARE`,
+		submission.QuizID, now,
+	)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.QuizSubmission{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.QuizSubmission{}, errs.ErrQuizNotFound
+		}
+
+		log.Error("failed to get quiz", slog.String("error", err.Error()))
+		return model.QuizSubmission{}, err
+	}
+
+	if !quiz.Open {
+		if err := tx.Rollback(); err != nil {
+			return model.QuizSubmission{}, err
+		}
+		return model.QuizSubmission{}, errs.ErrQuizClosed
+	}
+
+	var transactionID sql.NullInt64
+	if submission.Reward > 0 {
+		id, err := transactions.Credit(ctx, tx, submission.UserID, submission.Reward, transactions.QuizRewardTypeID)
+		if err != nil {
+			log.Error("failed to pay reward", slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return model.QuizSubmission{}, err
+			}
+			return model.QuizSubmission{}, err
+		}
+
+		transactionID = sql.NullInt64{Int64: int64(id), Valid: true}
+	}
+
+	query := `INSERT INTO quizzes_submissions (quiz_id, user_id, score, passed, reward, transaction_id, submitted_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7)
+			  RETURNING id`
+
+	err = tx.QueryRowxContext(ctx, query,
+		submission.QuizID,
+		submission.UserID,
+		submission.Score,
+		submission.Passed,
+		submission.Reward,
+		transactionID,
+		now,
+	).Scan(&submission.ID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.QuizSubmission{}, err
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			switch pqErr.Code {
+			case "23505":
+				return model.QuizSubmission{}, errs.ErrQuizAlreadySubmitted
+			case "23503":
+				return model.QuizSubmission{}, errs.ErrUserNotFound
+			}
+		}
+
+		log.Error("failed to add submission", slog.String("error", err.Error()))
+		return model.QuizSubmission{}, err
+	}
+
+	answerQuery := `INSERT INTO quizzes_answers (submission_id, quiz_id, position, options, number, text, correct)
+					VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	for _, answer := range submission.Answers {
+		_, err := tx.ExecContext(ctx, answerQuery,
+			submission.ID,
+			submission.QuizID,
+			answer.Position,
+			toInt64Array(answer.Options),
+			answer.Number,
+			answer.Text,
+			answer.Correct,
+		)
+		if err != nil {
+			log.Error("failed to add answer", slog.Int("position", answer.Position), slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return model.QuizSubmission{}, err
+			}
+			return model.QuizSubmission{}, err
+		}
+	}
+
+	if submission.Reward > 0 && quiz.BudgetID != 0 {
+		if err := budgets.Spend(ctx, tx, quiz.BudgetID, 0, submission.Reward); err != nil {
+			log.Error("failed to spend budget", slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return model.QuizSubmission{}, err
+			}
+			return model.QuizSubmission{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.QuizSubmission{}, err
+	}
+
+	submission.SubmittedAt = now
+
+	log.Info("submitted quiz", slog.Float64("score", submission.Score), slog.Bool("passed", submission.Passed))
+
+	return submission, nil
+}
+
+// GetByID returns the quiz with its questions, Submitted tells whether the user has answered it.
+func (s *Storage) GetByID(ctx context.Context, id, userID int) (model.Quiz, error) {
+	op := "quizzes.GetByID"
+
+	log := s.log.With(slog.String("op", op), slog.Int("id", id))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Quiz{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	quiz, err := getQuiz(ctx, conn, id, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Quiz{}, errs.ErrQuizNotFound
+		}
+
+		log.Error("failed to get quiz", slog.String("error", err.Error()))
+		return model.Quiz{}, err
+	}
+
+	return quiz, nil
+}
+
+// GetOpen returns a page of the quizzes taking submissions by now, without their questions.
+func (s *Storage) GetOpen(ctx context.Context, userID int, now time.Time, limit, offset int) ([]model.Quiz, error) {
+	return s.get(ctx, "quizzes.GetOpen",
+		`q.closed_at IS NULL AND (q.closes_at IS NULL OR q.closes_at > $2) ORDER BY q.created_at DESC, q.id DESC LIMIT $3 OFFSET $4`,
+		userID, now, limit, offset,
+	)
+}
+
+// GetAll returns a page of all the quizzes without their questions, the latest first.
+func (s *Storage) GetAll(ctx context.Context, limit, offset int) ([]model.Quiz, error) {
+	return s.get(ctx, "quizzes.GetAll",
+		`TRUE ORDER BY q.created_at DESC, q.id DESC LIMIT $2 OFFSET $3`,
+		0, limit, offset,
+	)
+}
+
+// GetSubmission returns the submission of the user with the answers.
+func (s *Storage) GetSubmission(ctx context.Context, quizID, userID int) (model.QuizSubmission, error) {
+	op := "quizzes.GetSubmission"
+
+	log := s.log.With(slog.String("op", op), slog.Int("quizID", quizID), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.QuizSubmission{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var submission dbSubmission
+	err = conn.GetContext(ctx, &submission,
+		`SELECT id, quiz_id, user_id, score, passed, reward, submitted_at FROM quizzes_submissions WHERE quiz_id = $1 AND user_id = $2`,
+		quizID, userID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.QuizSubmission{}, errs.ErrQuizNotSubmitted
+		}
+
+		log.Error("failed to get submission", slog.String("error", err.Error()))
+		return model.QuizSubmission{}, err
+	}
+
+	var dbAnswers []dbAnswer
+	err = conn.SelectContext(ctx, &dbAnswers,
+		`SELECT position, options, number, text, correct FROM quizzes_answers WHERE submission_id = $1 ORDER BY position`,
+		submission.ID,
+	)
+	if err != nil {
+		log.Error("failed to get answers", slog.String("error", err.Error()))
+		return model.QuizSubmission{}, err
+	}
+
+	result := submission.toModel()
+	result.Answers = make([]model.QuizAnswer, 0, len(dbAnswers))
+	for _, a := range dbAnswers {
+		result.Answers = append(result.Answers, a.toModel())
+	}
+
+	return result, nil
+}
+
+// GetResults aggregates the answers to every question of the quiz: choices are counted per option,
+// numbers are averaged and the maxTexts most frequent short texts are counted.
+func (s *Storage) GetResults(ctx context.Context, quizID, maxTexts int) ([]model.QuizQuestionResult, error) {
+	op := "quizzes.GetResults"
+
+	log := s.log.With(slog.String("op", op), slog.Int("quizID", quizID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	questionsQuery := `SELECT q.position, q.kind_id, q.text, q.options,
+					   COUNT(a.submission_id) AS answers,
+					   COALESCE(AVG(a.number), 0) AS average,
+					   COALESCE(MIN(a.number), 0) AS min,
+					   COALESCE(MAX(a.number), 0) AS max
+					   FROM quizzes_questions q
+					   LEFT JOIN quizzes_answers a ON a.quiz_id = q.quiz_id AND a.position = q.position
+					   WHERE q.quiz_id = $1
+					   GROUP BY q.quiz_id, q.position
+					   ORDER BY q.position`
+
+	var dbResults []dbResult
+	if err := conn.SelectContext(ctx, &dbResults, questionsQuery, quizID); err != nil {
+		log.Error("failed to get questions", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var options []struct {
+		Position int `db:"position"`
+		Option   int `db:"option"`
+		Count    int `db:"count"`
+	}
+	err = conn.SelectContext(ctx, &options,
+		`SELECT a.position, o.option, COUNT(*) AS count
+		 FROM quizzes_answers a
+		 CROSS JOIN unnest(a.options) AS o(option)
+		 WHERE a.quiz_id = $1
+		 GROUP BY a.position, o.option`,
+		quizID,
+	)
+	if err != nil {
+		log.Error("failed to count options", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var texts []struct {
+		Position int    `db:"position"`
+		Text     string `db:"text"`
+		Count    int    `db:"count"`
+	}
+	err = conn.SelectContext(ctx, &texts,
+		`SELECT position, text, count FROM (
+			SELECT position, LOWER(TRIM(text)) AS text, COUNT(*) AS count,
+			ROW_NUMBER() OVER (PARTITION BY position ORDER BY COUNT(*) DESC, LOWER(TRIM(text))) AS rank
+			FROM quizzes_answers
+			WHERE quiz_id = $1 AND text <> ''
+			GROUP BY position, LOWER(TRIM(text))
+		 ) t
+		 WHERE rank <= $2
+		 ORDER BY position, rank`,
+		quizID, maxTexts,
+	)
+	if err != nil {
+		log.Error("failed to count texts", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	results := make([]model.QuizQuestionResult, 0, len(dbResults))
+	index := make(map[int]int, len(dbResults))
+	for i, r := range dbResults {
+		result := r.toModel()
+		if result.KindID == quizzeslib.SingleChoiceKindID || result.KindID == quizzeslib.MultipleChoiceKindID {
+			result.OptionCounts = make([]int, len(result.Options))
+		}
+
+		results = append(results, result)
+		index[r.Position] = i
+	}
+
+	for _, o := range options {
+		i, ok := index[o.Position]
+		if ok && o.Option >= 0 && o.Option < len(results[i].OptionCounts) {
+			results[i].OptionCounts[o.Option] = o.Count
+		}
+	}
+
+	for _, t := range texts {
+		if i, ok := index[t.Position]; ok {
+			results[i].Texts = append(results[i].Texts, model.QuizTextCount{Text: t.Text, Count: t.Count})
+		}
+	}
+
+	return results, nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+// get selects the quizzes matching the condition, which may also order and limit them.
+// The first argument is the ID of the user whose submission is looked up.
+func (s *Storage) get(ctx context.Context, op, condition string, args ...interface{}) ([]model.Quiz, error) {
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var dbQuizzes []dbQuiz
+	if err := conn.SelectContext(ctx, &dbQuizzes, `SELECT `+quizColumns+` FROM quizzes q WHERE `+condition, args...); err != nil {
+		log.Error("failed to get quizzes", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	quizzes := make([]model.Quiz, 0, len(dbQuizzes))
+	for _, q := range dbQuizzes {
+		quizzes = append(quizzes, q.toModel())
+	}
+
+	return quizzes, nil
+}
+
+func getQuiz(ctx context.Context, q sqlx.QueryerContext, id, userID int) (model.Quiz, error) {
+	var dbQuiz dbQuiz
+	if err := sqlx.GetContext(ctx, q, &dbQuiz, `SELECT `+quizColumns+` FROM quizzes q WHERE q.id = $2`, userID, id); err != nil {
+		return model.Quiz{}, err
+	}
+
+	var dbQuestions []dbQuestion
+	err := sqlx.SelectContext(ctx, q, &dbQuestions,
+		`SELECT position, kind_id, text, options, correct_options, correct_number, tolerance, correct_texts, points
+		 FROM quizzes_questions
+		 WHERE quiz_id = $1
+		 ORDER BY position`,
+		id,
+	)
+	if err != nil {
+		return model.Quiz{}, err
+	}
+
+	quiz := dbQuiz.toModel()
+	quiz.Questions = make([]model.QuizQuestion, 0, len(dbQuestions))
+	for _, question := range dbQuestions {
+		quiz.Questions = append(quiz.Questions, question.toModel())
+	}
+
+	return quiz, nil
+}
+
+func nullableTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+
+	return t
+}
+
+// toStringArray never returns nil, which pq would store as NULL.
+func toStringArray(values []string) pq.StringArray {
+	if values == nil {
+		return pq.StringArray{}
+	}
+
+	return values
+}
+
+func toInt64Array(values []int) pq.Int64Array {
+	array := make(pq.Int64Array, 0, len(values))
+	for _, v := range values {
+		array = append(array, int64(v))
+	}
+
+	return array
+}
+
+func toInts(array pq.Int64Array) []int {
+	if len(array) == 0 {
+		return nil
+	}
+
+	values := make([]int, 0, len(array))
+	for _, v := range array {
+		values = append(values, int(v))
+	}
+
+	return values
+}
+
+func toFloatPointer(number sql.NullFloat64) *float64 {
+	if !number.Valid {
+		return nil
+	}
+
+	return &number.Float64
+}
+
+// quizColumns expects the ID of the user whose submission is looked up as the first query parameter.
+const quizColumns = `q.id, q.type_id, q.name, q.description, q.amount, q.pass_score, q.closes_at, q.created_by,
+	COALESCE(q.budget_id, 0) AS budget_id, q.created_at, q.closed_at,
+	(SELECT COUNT(*) FROM quizzes_submissions s WHERE s.quiz_id = q.id) AS submissions,
+	EXISTS (SELECT 1 FROM quizzes_submissions s WHERE s.quiz_id = q.id AND s.user_id = $1) AS submitted`
+
+type dbQuiz struct {
+	ID          int          `db:"id"`
+	TypeID      int          `db:"type_id"`
+	Name        string       `db:"name"`
+	Description string       `db:"description"`
+	Amount      float64      `db:"amount"`
+	PassScore   int          `db:"pass_score"`
+	ClosesAt    sql.NullTime `db:"closes_at"`
+	CreatedBy   int          `db:"created_by"`
+	BudgetID    int          `db:"budget_id"`
+	CreatedAt   time.Time    `db:"created_at"`
+	ClosedAt    sql.NullTime `db:"closed_at"`
+	Submissions int          `db:"submissions"`
+	Submitted   bool         `db:"submitted"`
+}
+
+func (q dbQuiz) toModel() model.Quiz {
+	return model.Quiz{
+		ID:          q.ID,
+		TypeID:      q.TypeID,
+		Name:        q.Name,
+		Description: q.Description,
+		Amount:      q.Amount,
+		PassScore:   q.PassScore,
+		ClosesAt:    q.ClosesAt.Time,
+		CreatedBy:   q.CreatedBy,
+		BudgetID:    q.BudgetID,
+		CreatedAt:   q.CreatedAt,
+		ClosedAt:    q.ClosedAt.Time,
+		Submissions: q.Submissions,
+		Submitted:   q.Submitted,
+	}
+}
+
+type dbQuestion struct {
+	Position       int             `db:"position"`
+	KindID         int             `db:"kind_id"`
+	Text           string          `db:"text"`
+	Options        pq.StringArray  `db:"options"`
+	CorrectOptions pq.Int64Array   `db:"correct_options"`
+	CorrectNumber  sql.NullFloat64 `db:"correct_number"`
+	Tolerance      float64         `db:"tolerance"`
+	CorrectTexts   pq.StringArray  `db:"correct_texts"`
+	Points         int             `db:"points"`
+}
+
+func (q dbQuestion) toModel() model.QuizQuestion {
+	return model.QuizQuestion{
+		Position:       q.Position,
+		KindID:         q.KindID,
+		Text:           q.Text,
+		Options:        q.Options,
+		CorrectOptions: toInts(q.CorrectOptions),
+		CorrectNumber:  toFloatPointer(q.CorrectNumber),
+		Tolerance:      q.Tolerance,
+		CorrectTexts:   q.CorrectTexts,
+		Points:         q.Points,
+	}
+}
+
+type dbSubmission struct {
+	ID          int       `db:"id"`
+	QuizID      int       `db:"quiz_id"`
+	UserID      int       `db:"user_id"`
+	Score       float64   `db:"score"`
+	Passed      bool      `db:"passed"`
+	Reward      float64   `db:"reward"`
+	SubmittedAt time.Time `db:"submitted_at"`
+}
+
+func (s dbSubmission) toModel() model.QuizSubmission {
+	return model.QuizSubmission{
+		ID:          s.ID,
+		QuizID:      s.QuizID,
+		UserID:      s.UserID,
+		Score:       s.Score,
+		Passed:      s.Passed,
+		Reward:      s.Reward,
+		SubmittedAt: s.SubmittedAt,
+	}
+}
+
+type dbAnswer struct {
+	Position int             `db:"position"`
+	Options  pq.Int64Array   `db:"options"`
+	Number   sql.NullFloat64 `db:"number"`
+	Text     string          `db:"text"`
+	Correct  bool            `db:"correct"`
+}
+
+func (a dbAnswer) toModel() model.QuizAnswer {
+	return model.QuizAnswer{
+		Position: a.Position,
+		Options:  toInts(a.Options),
+		Number:   toFloatPointer(a.Number),
+		Text:     a.Text,
+		Correct:  a.Correct,
+	}
+}
+
+type dbResult struct {
+	Position int            `db:"position"`
+	KindID   int            `db:"kind_id"`
+	Text     string         `db:"text"`
+	Options  pq.StringArray `db:"options"`
+	Answers  int            `db:"answers"`
+	Average  float64        `db:"average"`
+	Min      float64        `db:"min"`
+	Max      float64        `db:"max"`
+}
+
+func (r dbResult) toModel() model.QuizQuestionResult {
+	return model.QuizQuestionResult{
+		Position: r.Position,
+		KindID:   r.KindID,
+		Text:     r.Text,
+		Answers:  r.Answers,
+		Options:  r.Options,
+		Average:  r.Average,
+		Min:      r.Min,
+		Max:      r.Max,
+	}
+}
@@ -0,0 +1,740 @@
+package raffles
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/raffles"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/nullable"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/purchases"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/transactions"
+	"github.com/lib/pq"
+	"log/slog"
+	"time"
+)
+
+const (
+	OpenStatusID      = 1
+	DrawnStatusID     = 2
+	CancelledStatusID = 3
+)
+
+type Storage struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
+	return &Storage{
+		db:  db,
+		log: log,
+	}
+}
+
+// Create opens the raffle. A shop item given as the prize is taken from the stock right away,
+// so it is still there when the raffle is drawn.
+func (s *Storage) Create(ctx context.Context, raffle model.Raffle) (model.Raffle, error) {
+	op := "raffles.Create"
+
+	log := s.log.With(slog.String("op", op), slog.Int("adminID", raffle.CreatedBy))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Raffle{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Raffle{}, err
+	}
+
+	if raffle.ItemID != 0 {
+		var inStock int
+		err = tx.GetContext(ctx, &inStock, `SELECT in_stock FROM shop_items WHERE id = $1 FOR UPDATE`, raffle.ItemID)
+		if err != nil {
+			if err := tx.Rollback(); err != nil {
+				return model.Raffle{}, err
+			}
+
+			if errors.Is(err, sql.ErrNoRows) {
+				return model.Raffle{}, errs.ErrShopItemNotFound
+			}
+
+			log.Error("failed to get item", slog.String("error", err.Error()))
+			return model.Raffle{}, err
+		}
+
+		if inStock <= 0 {
+			if err := tx.Rollback(); err != nil {
+				return model.Raffle{}, err
+			}
+			return model.Raffle{}, errs.ErrShopItemSoldOut
+		}
+
+		if _, err := tx.ExecContext(ctx, `UPDATE shop_items SET in_stock = in_stock - 1 WHERE id = $1`, raffle.ItemID); err != nil {
+			log.Error("failed to update stock", slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return model.Raffle{}, err
+			}
+			return model.Raffle{}, err
+		}
+	}
+
+	query := `INSERT INTO raffles (name, description, ticket_price, max_tickets_per_user, item_id, prize_amount, seed, commitment, draw_at, created_by)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			  RETURNING id`
+
+	err = tx.QueryRowxContext(ctx, query,
+		raffle.Name,
+		raffle.Description,
+		raffle.TicketPrice,
+		raffle.MaxTicketsPerUser,
+		nullable.ID(raffle.ItemID),
+		raffle.PrizeAmount,
+		raffle.Seed,
+		raffle.Commitment,
+		raffle.DrawAt,
+		raffle.CreatedBy,
+	).Scan(&raffle.ID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Raffle{}, err
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return model.Raffle{}, errs.ErrAdminNotFound
+		}
+
+		log.Error("failed to add raffle", slog.String("error", err.Error()))
+		return model.Raffle{}, err
+	}
+
+	created, err := getRaffle(ctx, tx, raffle.ID, 0)
+	if err != nil {
+		log.Error("failed to get raffle", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Raffle{}, err
+		}
+		return model.Raffle{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Raffle{}, err
+	}
+
+	log.Info("created raffle", slog.Int("id", raffle.ID))
+
+	return created, nil
+}
+
+// BuyTickets charges the user for count tickets and numbers them after the tickets sold before.
+// Sales close at the draw time and nobody may hold more than the raffle's limit of tickets.
+func (s *Storage) BuyTickets(ctx context.Context, raffleID, userID, count int, now time.Time) (model.Raffle, error) {
+	op := "raffles.BuyTickets"
+
+	log := s.log.With(slog.String("op", op), slog.Int("raffleID", raffleID), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Raffle{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Raffle{}, err
+	}
+
+	raffle, err := lockRaffle(ctx, tx, raffleID, userID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Raffle{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Raffle{}, errs.ErrRaffleNotFound
+		}
+
+		log.Error("failed to lock raffle", slog.String("error", err.Error()))
+		return model.Raffle{}, err
+	}
+
+	var buyErr error
+	switch {
+	case raffle.StatusID != OpenStatusID:
+		buyErr = errs.ErrRaffleNotOpen
+	case !now.Before(raffle.DrawAt):
+		buyErr = errs.ErrRaffleSalesClosed
+	case raffle.MyTickets+count > raffle.MaxTicketsPerUser:
+		buyErr = errs.ErrTicketLimit
+	}
+
+	if buyErr != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Raffle{}, err
+		}
+		return model.Raffle{}, buyErr
+	}
+
+	price := raffle.TicketPrice * float64(count)
+
+	transactionID, err := transactions.Debit(ctx, tx, userID, price, transactions.RaffleTicketTypeID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Raffle{}, err
+		}
+
+		if errors.Is(err, errs.ErrUserNotFound) || errors.Is(err, errs.ErrInsufficientFunds) {
+			return model.Raffle{}, err
+		}
+
+		log.Error("failed to pay for tickets", slog.String("error", err.Error()))
+		return model.Raffle{}, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO raffles_tickets (raffle_id, number, user_id, transaction_id)
+		 SELECT $1, $2 + n, $3, $4 FROM generate_series(1, $5::int) AS n`,
+		raffleID, raffle.TicketsSold, userID, transactionID, count,
+	)
+	if err != nil {
+		log.Error("failed to add tickets", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Raffle{}, err
+		}
+		return model.Raffle{}, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE raffles SET tickets_sold = tickets_sold + $1 WHERE id = $2`, count, raffleID); err != nil {
+		log.Error("failed to count tickets", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Raffle{}, err
+		}
+		return model.Raffle{}, err
+	}
+
+	bought, err := getRaffle(ctx, tx, raffleID, userID)
+	if err != nil {
+		log.Error("failed to get raffle", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Raffle{}, err
+		}
+		return model.Raffle{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Raffle{}, err
+	}
+
+	log.Info("sold tickets", slog.Int("count", count))
+
+	return bought, nil
+}
+
+// Draw picks the winning ticket with the committed seed and the sold tickets and delivers the prize to its holder:
+// the item as a purchase and the coins as a prize transaction. A raffle nobody bought a ticket for
+// is cancelled instead and its item returns to the stock.
+func (s *Storage) Draw(ctx context.Context, raffleID int, now time.Time) (model.Raffle, error) {
+	op := "raffles.Draw"
+
+	log := s.log.With(slog.String("op", op), slog.Int("raffleID", raffleID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Raffle{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Raffle{}, err
+	}
+
+	raffle, err := lockRaffle(ctx, tx, raffleID, 0)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Raffle{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Raffle{}, errs.ErrRaffleNotFound
+		}
+
+		log.Error("failed to lock raffle", slog.String("error", err.Error()))
+		return model.Raffle{}, err
+	}
+
+	var drawErr error
+	switch {
+	case raffle.StatusID != OpenStatusID:
+		drawErr = errs.ErrRaffleNotOpen
+	case now.Before(raffle.DrawAt):
+		drawErr = errs.ErrRaffleNotDue
+	}
+
+	if drawErr != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Raffle{}, err
+		}
+		return model.Raffle{}, drawErr
+	}
+
+	if raffle.TicketsSold == 0 {
+		err = closeRaffle(ctx, tx, raffle)
+	} else {
+		err = award(ctx, tx, raffle)
+	}
+	if err != nil {
+		log.Error("failed to draw raffle", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Raffle{}, err
+		}
+		return model.Raffle{}, err
+	}
+
+	drawn, err := getRaffle(ctx, tx, raffleID, 0)
+	if err != nil {
+		log.Error("failed to get raffle", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Raffle{}, err
+		}
+		return model.Raffle{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Raffle{}, err
+	}
+
+	log.Info("drew raffle", slog.Int("winnerID", drawn.WinnerID), slog.Int("winningNumber", drawn.WinningNumber))
+
+	return drawn, nil
+}
+
+// Cancel closes the open raffle without a draw. Every holder gets the price of their tickets back
+// and the item returns to the stock.
+func (s *Storage) Cancel(ctx context.Context, raffleID int) (model.Raffle, error) {
+	op := "raffles.Cancel"
+
+	log := s.log.With(slog.String("op", op), slog.Int("raffleID", raffleID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Raffle{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Raffle{}, err
+	}
+
+	raffle, err := lockRaffle(ctx, tx, raffleID, 0)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.Raffle{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Raffle{}, errs.ErrRaffleNotFound
+		}
+
+		log.Error("failed to lock raffle", slog.String("error", err.Error()))
+		return model.Raffle{}, err
+	}
+
+	if raffle.StatusID != OpenStatusID {
+		if err := tx.Rollback(); err != nil {
+			return model.Raffle{}, err
+		}
+		return model.Raffle{}, errs.ErrRaffleNotOpen
+	}
+
+	var holders []struct {
+		UserID  int `db:"user_id"`
+		Tickets int `db:"tickets"`
+	}
+	err = tx.SelectContext(ctx, &holders,
+		`SELECT user_id, COUNT(*) AS tickets FROM raffles_tickets WHERE raffle_id = $1 GROUP BY user_id ORDER BY user_id`,
+		raffleID,
+	)
+	if err != nil {
+		log.Error("failed to get ticket holders", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Raffle{}, err
+		}
+		return model.Raffle{}, err
+	}
+
+	for _, holder := range holders {
+		_, err := transactions.Credit(ctx, tx, holder.UserID, raffle.TicketPrice*float64(holder.Tickets), transactions.RefundTypeID)
+		if err != nil {
+			log.Error("failed to refund tickets", slog.Int("userID", holder.UserID), slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return model.Raffle{}, err
+			}
+			return model.Raffle{}, err
+		}
+	}
+
+	if err := closeRaffle(ctx, tx, raffle); err != nil {
+		log.Error("failed to cancel raffle", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Raffle{}, err
+		}
+		return model.Raffle{}, err
+	}
+
+	cancelled, err := getRaffle(ctx, tx, raffleID, 0)
+	if err != nil {
+		log.Error("failed to get raffle", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Raffle{}, err
+		}
+		return model.Raffle{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Raffle{}, err
+	}
+
+	log.Info("cancelled raffle", slog.Int("refunds", len(holders)))
+
+	return cancelled, nil
+}
+
+// GetByID returns the raffle with the number of tickets the user holds in it.
+func (s *Storage) GetByID(ctx context.Context, id, userID int) (model.Raffle, error) {
+	op := "raffles.GetByID"
+
+	log := s.log.With(slog.String("op", op), slog.Int("id", id))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Raffle{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	raffle, err := getRaffle(ctx, conn, id, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Raffle{}, errs.ErrRaffleNotFound
+		}
+
+		log.Error("failed to get raffle", slog.String("error", err.Error()))
+		return model.Raffle{}, err
+	}
+
+	return raffle, nil
+}
+
+// GetAll returns a page of the raffles of the status, the soonest draws first.
+// Zero statusID returns raffles of any status.
+func (s *Storage) GetAll(ctx context.Context, statusID, userID, limit, offset int) ([]model.Raffle, error) {
+	return s.get(ctx, "raffles.GetAll",
+		`($1 = 0 OR r.status_id = $1) ORDER BY r.draw_at DESC, r.id DESC LIMIT $3 OFFSET $4`,
+		statusID, userID, limit, offset,
+	)
+}
+
+// GetDue returns the open raffles whose draw time has come by now.
+func (s *Storage) GetDue(ctx context.Context, now time.Time) ([]model.Raffle, error) {
+	return s.get(ctx, "raffles.GetDue",
+		`r.status_id = $1 AND r.draw_at <= $3 ORDER BY r.draw_at, r.id`,
+		OpenStatusID, 0, now,
+	)
+}
+
+// GetTickets returns the tickets of the raffle in the order of their numbers.
+func (s *Storage) GetTickets(ctx context.Context, raffleID int) ([]model.RaffleTicket, error) {
+	op := "raffles.GetTickets"
+
+	log := s.log.With(slog.String("op", op), slog.Int("raffleID", raffleID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT t.number, t.user_id, u.username, t.created_at
+			  FROM raffles_tickets t
+			  JOIN users u ON u.id = t.user_id
+			  WHERE t.raffle_id = $1
+			  ORDER BY t.number`
+
+	var dbTickets []dbTicket
+	if err := conn.SelectContext(ctx, &dbTickets, query, raffleID); err != nil {
+		log.Error("failed to get tickets", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	tickets := make([]model.RaffleTicket, 0, len(dbTickets))
+	for _, t := range dbTickets {
+		tickets = append(tickets, model.RaffleTicket(t))
+	}
+
+	return tickets, nil
+}
+
+// GetParticipants returns the IDs of the users holding tickets of the raffle.
+func (s *Storage) GetParticipants(ctx context.Context, raffleID int) ([]int, error) {
+	op := "raffles.GetParticipants"
+
+	log := s.log.With(slog.String("op", op), slog.Int("raffleID", raffleID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var userIDs []int
+	err = conn.SelectContext(ctx, &userIDs, `SELECT DISTINCT user_id FROM raffles_tickets WHERE raffle_id = $1 ORDER BY user_id`, raffleID)
+	if err != nil {
+		log.Error("failed to get participants", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return userIDs, nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+// get selects the raffles matching the condition, which may also order and limit them.
+// The second argument is the ID of the user whose tickets are counted.
+func (s *Storage) get(ctx context.Context, op, condition string, args ...interface{}) ([]model.Raffle, error) {
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var dbRaffles []dbRaffle
+	if err := conn.SelectContext(ctx, &dbRaffles, `SELECT `+raffleColumns+` FROM `+raffleTables+` WHERE `+condition, args...); err != nil {
+		log.Error("failed to get raffles", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	raffles := make([]model.Raffle, 0, len(dbRaffles))
+	for _, r := range dbRaffles {
+		raffles = append(raffles, r.toModel())
+	}
+
+	return raffles, nil
+}
+
+// award delivers the prize of the raffle to the holder of the winning ticket.
+func award(ctx context.Context, tx *sqlx.Tx, raffle model.Raffle) error {
+	var holders []int
+	if err := tx.SelectContext(ctx, &holders, `SELECT user_id FROM raffles_tickets WHERE raffle_id = $1 ORDER BY number`, raffle.ID); err != nil {
+		return err
+	}
+
+	number := raffles.Winner(raffle.Seed, holders)
+	if number == 0 {
+		return errors.New("raffle has no tickets")
+	}
+
+	winnerID := holders[number-1]
+
+	var purchaseID int
+	if raffle.ItemID != 0 {
+		purchase, err := purchases.Deliver(ctx, tx, winnerID, raffle.ItemID)
+		if err != nil {
+			return err
+		}
+		purchaseID = purchase.ID
+	}
+
+	if raffle.PrizeAmount > 0 {
+		if _, err := transactions.Credit(ctx, tx, winnerID, raffle.PrizeAmount, transactions.PrizeTypeID); err != nil {
+			return err
+		}
+	}
+
+	_, err := tx.ExecContext(ctx,
+		`UPDATE raffles SET status_id = $1, winning_number = $2, winner_id = $3, purchase_id = $4, closed_at = NOW() WHERE id = $5`,
+		DrawnStatusID, number, winnerID, nullable.ID(purchaseID), raffle.ID,
+	)
+	return err
+}
+
+// closeRaffle cancels the raffle and returns its item to the stock.
+func closeRaffle(ctx context.Context, tx *sqlx.Tx, raffle model.Raffle) error {
+	if raffle.ItemID != 0 {
+		if _, err := tx.ExecContext(ctx, `UPDATE shop_items SET in_stock = in_stock + 1 WHERE id = $1`, raffle.ItemID); err != nil {
+			return err
+		}
+	}
+
+	_, err := tx.ExecContext(ctx, `UPDATE raffles SET status_id = $1, closed_at = NOW() WHERE id = $2`, CancelledStatusID, raffle.ID)
+	return err
+}
+
+// lockRaffle locks the raffle until the end of the transaction, sql.ErrNoRows is returned as is.
+func lockRaffle(ctx context.Context, tx *sqlx.Tx, id, userID int) (model.Raffle, error) {
+	var raffle dbRaffle
+	if err := tx.GetContext(ctx, &raffle, `SELECT `+raffleColumns+` FROM `+raffleTables+` WHERE r.id = $1 FOR UPDATE OF r`, id, userID); err != nil {
+		return model.Raffle{}, err
+	}
+
+	return raffle.toModel(), nil
+}
+
+func getRaffle(ctx context.Context, q sqlx.QueryerContext, id, userID int) (model.Raffle, error) {
+	var raffle dbRaffle
+	if err := sqlx.GetContext(ctx, q, &raffle, `SELECT `+raffleColumns+` FROM `+raffleTables+` WHERE r.id = $1`, id, userID); err != nil {
+		return model.Raffle{}, err
+	}
+
+	return raffle.toModel(), nil
+}
+
+// raffleColumns expects the ID of the user whose tickets are counted as the second query parameter.
+const raffleColumns = `r.id, r.name, r.description, r.ticket_price, r.max_tickets_per_user, COALESCE(r.item_id, 0) AS item_id,
+	COALESCE(i.name, '') AS item_name, r.prize_amount, r.status_id, r.seed, r.commitment, r.draw_at, r.tickets_sold,
+	COALESCE(r.winning_number, 0) AS winning_number, COALESCE(r.winner_id, 0) AS winner_id, COALESCE(w.username, '') AS winner_name,
+	COALESCE(r.purchase_id, 0) AS purchase_id, r.created_by, r.created_at, r.closed_at,
+	(SELECT COUNT(*) FROM raffles_tickets t WHERE t.raffle_id = r.id AND t.user_id = $2) AS my_tickets`
+
+const raffleTables = `raffles r
+	LEFT JOIN shop_items i ON i.id = r.item_id
+	LEFT JOIN users w ON w.id = r.winner_id`
+
+type dbRaffle struct {
+	ID                int          `db:"id"`
+	Name              string       `db:"name"`
+	Description       string       `db:"description"`
+	TicketPrice       float64      `db:"ticket_price"`
+	MaxTicketsPerUser int          `db:"max_tickets_per_user"`
+	ItemID            int          `db:"item_id"`
+	ItemName          string       `db:"item_name"`
+	PrizeAmount       float64      `db:"prize_amount"`
+	StatusID          int          `db:"status_id"`
+	Seed              string       `db:"seed"`
+	Commitment        string       `db:"commitment"`
+	DrawAt            time.Time    `db:"draw_at"`
+	TicketsSold       int          `db:"tickets_sold"`
+	WinningNumber     int          `db:"winning_number"`
+	WinnerID          int          `db:"winner_id"`
+	WinnerName        string       `db:"winner_name"`
+	PurchaseID        int          `db:"purchase_id"`
+	CreatedBy         int          `db:"created_by"`
+	CreatedAt         time.Time    `db:"created_at"`
+	ClosedAt          sql.NullTime `db:"closed_at"`
+	MyTickets         int          `db:"my_tickets"`
+}
+
+func (r dbRaffle) toModel() model.Raffle {
+	return model.Raffle{
+		ID:                r.ID,
+		Name:              r.Name,
+		Description:       r.Description,
+		TicketPrice:       r.TicketPrice,
+		MaxTicketsPerUser: r.MaxTicketsPerUser,
+		ItemID:            r.ItemID,
+		ItemName:          r.ItemName,
+		PrizeAmount:       r.PrizeAmount,
+		StatusID:          r.StatusID,
+		Seed:              r.Seed,
+		Commitment:        r.Commitment,
+		DrawAt:            r.DrawAt,
+		TicketsSold:       r.TicketsSold,
+		WinningNumber:     r.WinningNumber,
+		WinnerID:          r.WinnerID,
+		WinnerName:        r.WinnerName,
+		PurchaseID:        r.PurchaseID,
+		CreatedBy:         r.CreatedBy,
+		CreatedAt:         r.CreatedAt,
+		ClosedAt:          r.ClosedAt.Time,
+		MyTickets:         r.MyTickets,
+	}
+}
+
+type dbTicket struct {
+	Number    int       `db:"number"`
+	UserID    int       `db:"user_id"`
+	Username  string    `db:"username"`
+	CreatedAt time.Time `db:"created_at"`
+}
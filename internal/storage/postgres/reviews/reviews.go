@@ -0,0 +1,245 @@
+package reviews
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/lib/pq"
+	"log/slog"
+	"time"
+)
+
+type Storage struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
+	return &Storage{
+		db:  db,
+		log: log,
+	}
+}
+
+func (s *Storage) AddTaskReviewer(ctx context.Context, taskID, adminID, assignedBy int) error {
+	op := "reviews.AddTaskReviewer"
+
+	log := s.log.With(slog.String("op", op), slog.Int("taskID", taskID), slog.Int("adminID", adminID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `INSERT INTO tasks_reviewers (task_id, admin_id, assigned_by) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`
+
+	if _, err := conn.ExecContext(ctx, query, taskID, adminID, assignedBy); err != nil {
+		if isForeignKeyViolation(err) {
+			log.Error("admin not found", slog.String("error", err.Error()))
+			return errs.ErrAdminNotFound
+		}
+
+		log.Error("failed to add task reviewer", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("added task reviewer")
+
+	return nil
+}
+
+func (s *Storage) AddGroupReviewer(ctx context.Context, groupID, adminID, assignedBy int) error {
+	op := "reviews.AddGroupReviewer"
+
+	log := s.log.With(slog.String("op", op), slog.Int("groupID", groupID), slog.Int("adminID", adminID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `INSERT INTO groups_reviewers (group_id, admin_id, assigned_by) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`
+
+	if _, err := conn.ExecContext(ctx, query, groupID, adminID, assignedBy); err != nil {
+		if isForeignKeyViolation(err) {
+			log.Error("admin or group not found", slog.String("error", err.Error()))
+			return errs.ErrAdminNotFound
+		}
+
+		log.Error("failed to add group reviewer", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("added group reviewer")
+
+	return nil
+}
+
+func (s *Storage) AddDelegation(ctx context.Context, delegation model.ReviewDelegation) (int, error) {
+	op := "reviews.AddDelegation"
+
+	log := s.log.With(slog.String("op", op), slog.Int("fromAdminID", delegation.FromAdminID), slog.Int("toAdminID", delegation.ToAdminID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `INSERT INTO reviews_delegations (from_admin_id, to_admin_id, starts_at, ends_at) VALUES ($1, $2, $3, $4) RETURNING id`
+
+	var id int
+	err = conn.QueryRowxContext(ctx, query, delegation.FromAdminID, delegation.ToAdminID, delegation.StartsAt, delegation.EndsAt).Scan(&id)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			log.Error("admin not found", slog.String("error", err.Error()))
+			return 0, errs.ErrAdminNotFound
+		}
+
+		log.Error("failed to add delegation", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	log.Info("added delegation")
+
+	return id, nil
+}
+
+// GetDelegations returns delegations given or received by the admin, newest first.
+func (s *Storage) GetDelegations(ctx context.Context, adminID int) ([]model.ReviewDelegation, error) {
+	op := "reviews.GetDelegations"
+
+	log := s.log.With(slog.String("op", op), slog.Int("adminID", adminID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT id, from_admin_id, to_admin_id, starts_at, ends_at, revoked_at, created_at
+			  FROM reviews_delegations
+			  WHERE from_admin_id = $1 OR to_admin_id = $1
+			  ORDER BY created_at DESC, id DESC`
+
+	var dbDelegations []dbDelegation
+	if err := conn.SelectContext(ctx, &dbDelegations, query, adminID); err != nil {
+		log.Error("failed to get delegations", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	delegations := make([]model.ReviewDelegation, 0, len(dbDelegations))
+	for _, delegation := range dbDelegations {
+		delegations = append(delegations, model.ReviewDelegation{
+			ID:          delegation.ID,
+			FromAdminID: delegation.FromAdminID,
+			ToAdminID:   delegation.ToAdminID,
+			StartsAt:    delegation.StartsAt,
+			EndsAt:      delegation.EndsAt,
+			RevokedAt:   delegation.RevokedAt.Time,
+			CreatedAt:   delegation.CreatedAt,
+		})
+	}
+
+	return delegations, nil
+}
+
+// RevokeDelegation revokes the delegation if it was given by fromAdminID.
+func (s *Storage) RevokeDelegation(ctx context.Context, delegationID, fromAdminID int) error {
+	op := "reviews.RevokeDelegation"
+
+	log := s.log.With(slog.String("op", op), slog.Int("delegationID", delegationID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `UPDATE reviews_delegations SET revoked_at = NOW() WHERE id = $1 AND from_admin_id = $2 AND revoked_at IS NULL`
+
+	res, err := conn.ExecContext(ctx, query, delegationID, fromAdminID)
+	if err != nil {
+		log.Error("failed to revoke delegation", slog.String("error", err.Error()))
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		log.Error("failed to get affected rows", slog.String("error", err.Error()))
+		return err
+	}
+
+	if affected == 0 {
+		log.Error("delegation not found")
+		return errs.ErrDelegationNotFound
+	}
+
+	log.Info("revoked delegation")
+
+	return nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+type dbDelegation struct {
+	ID          int          `db:"id"`
+	FromAdminID int          `db:"from_admin_id"`
+	ToAdminID   int          `db:"to_admin_id"`
+	StartsAt    time.Time    `db:"starts_at"`
+	EndsAt      time.Time    `db:"ends_at"`
+	RevokedAt   sql.NullTime `db:"revoked_at"`
+	CreatedAt   time.Time    `db:"created_at"`
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
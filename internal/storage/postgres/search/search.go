@@ -0,0 +1,167 @@
+package search
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/like"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/tasks"
+	"log/slog"
+)
+
+const (
+	TaskType     = "task"
+	ShopItemType = "shop_item"
+	UserType     = "user"
+)
+
+// tsQuery matches both russian and english stems of the same search string.
+const tsQuery = `(websearch_to_tsquery('russian', $1) || websearch_to_tsquery('english', $1))`
+
+type Storage struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
+	return &Storage{
+		db:  db,
+		log: log,
+	}
+}
+
+// SearchTasks looks through all tasks when userID is 0, otherwise only through tasks visible to the user.
+func (s *Storage) SearchTasks(ctx context.Context, text string, userID int, limit int) ([]model.SearchResult, error) {
+	op := "search.SearchTasks"
+
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT id, name AS title,
+			  ts_headline('russian', description, ` + tsQuery + `, 'MaxFragments=1, MaxWords=20, MinWords=5') AS snippet,
+			  ts_rank(search_vector, ` + tsQuery + `) AS rank
+			  FROM tasks
+			  WHERE search_vector @@ ` + tsQuery + `
+			  AND ($2 = 0 OR ((user_id = $2 OR for_group_id = $3) AND status_id <> $5))
+			  ORDER BY rank DESC, id DESC
+			  LIMIT $4`
+
+	var results []dbResult
+	if err := conn.SelectContext(ctx, &results, query, text, userID, tasks.AllGroupID, limit, tasks.CreationApprovalStatusID); err != nil {
+		log.Error("failed to search tasks", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return toModelResults(results, TaskType), nil
+}
+
+func (s *Storage) SearchShopItems(ctx context.Context, text string, limit int) ([]model.SearchResult, error) {
+	op := "search.SearchShopItems"
+
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT id, name AS title,
+			  ts_headline('russian', description, ` + tsQuery + `, 'MaxFragments=1, MaxWords=20, MinWords=5') AS snippet,
+			  ts_rank(search_vector, ` + tsQuery + `) AS rank
+			  FROM shop_items
+			  WHERE search_vector @@ ` + tsQuery + `
+			  ORDER BY rank DESC, id DESC
+			  LIMIT $2`
+
+	var results []dbResult
+	if err := conn.SelectContext(ctx, &results, query, text, limit); err != nil {
+		log.Error("failed to search shop items", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return toModelResults(results, ShopItemType), nil
+}
+
+func (s *Storage) SearchUsers(ctx context.Context, text string, limit int) ([]model.SearchResult, error) {
+	op := "search.SearchUsers"
+
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	// usernames are matched by prefix as well, people rarely type a full login
+	query := `SELECT id, username AS title, '' AS snippet,
+			  ts_rank(search_vector, websearch_to_tsquery('simple', $1)) AS rank
+			  FROM users
+			  WHERE search_vector @@ websearch_to_tsquery('simple', $1) OR username ILIKE $3 || '%'
+			  ORDER BY rank DESC, username
+			  LIMIT $2`
+
+	var results []dbResult
+	if err := conn.SelectContext(ctx, &results, query, text, limit, like.Escape(text)); err != nil {
+		log.Error("failed to search users", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return toModelResults(results, UserType), nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+type dbResult struct {
+	ID      int     `db:"id"`
+	Title   string  `db:"title"`
+	Snippet string  `db:"snippet"`
+	Rank    float64 `db:"rank"`
+}
+
+func toModelResults(dbResults []dbResult, resultType string) []model.SearchResult {
+	results := make([]model.SearchResult, 0, len(dbResults))
+	for _, result := range dbResults {
+		results = append(results, model.SearchResult{
+			Type:    resultType,
+			ID:      result.ID,
+			Title:   result.Title,
+			Snippet: result.Snippet,
+			Rank:    result.Rank,
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,408 @@
+package seasons
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/leaderboards"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/transactions"
+	"github.com/lib/pq"
+	"log/slog"
+	"time"
+)
+
+type Storage struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
+	return &Storage{
+		db:  db,
+		log: log,
+	}
+}
+
+// Add creates a season. It returns ErrSeasonOverlap if the season intersects another one.
+func (s *Storage) Add(ctx context.Context, season model.Season) (int, error) {
+	op := "seasons.Add"
+
+	log := s.log.With(slog.String("op", op), slog.String("name", season.Name))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `INSERT INTO seasons (name, starts_at, ends_at, created_by) VALUES ($1, $2, $3, $4) RETURNING id`
+
+	var id int
+	err = conn.QueryRowxContext(ctx, query, season.Name, season.StartsAt, season.EndsAt, season.CreatedBy).Scan(&id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23P01" {
+			return 0, errs.ErrSeasonOverlap
+		}
+
+		log.Error("failed to add season", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	log.Info("added season", slog.Int("id", id))
+
+	return id, nil
+}
+
+// GetAll returns the seasons, latest first.
+func (s *Storage) GetAll(ctx context.Context) ([]model.Season, error) {
+	op := "seasons.GetAll"
+
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT ` + seasonColumns + ` FROM seasons ORDER BY starts_at DESC`
+
+	var dbSeasons []dbSeason
+	if err := conn.SelectContext(ctx, &dbSeasons, query); err != nil {
+		log.Error("failed to get seasons", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return toModels(dbSeasons), nil
+}
+
+// GetByID returns the season or ErrSeasonNotFound.
+func (s *Storage) GetByID(ctx context.Context, id int) (model.Season, error) {
+	return s.getOne(ctx, "seasons.GetByID", `SELECT `+seasonColumns+` FROM seasons WHERE id = $1`, id)
+}
+
+// GetActive returns the season running at the time or ErrSeasonNotFound.
+func (s *Storage) GetActive(ctx context.Context, at time.Time) (model.Season, error) {
+	return s.getOne(ctx, "seasons.GetActive",
+		`SELECT `+seasonColumns+` FROM seasons WHERE starts_at <= $1 AND ends_at > $1`,
+		at,
+	)
+}
+
+// GetDueForRollover returns the seasons that ended by the time and are not finalized yet, oldest first.
+func (s *Storage) GetDueForRollover(ctx context.Context, at time.Time) ([]model.Season, error) {
+	op := "seasons.GetDueForRollover"
+
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT ` + seasonColumns + ` FROM seasons
+			  WHERE ends_at <= $1 AND finalized_at IS NULL
+			  ORDER BY ends_at`
+
+	var dbSeasons []dbSeason
+	if err := conn.SelectContext(ctx, &dbSeasons, query, at); err != nil {
+		log.Error("failed to get seasons", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return toModels(dbSeasons), nil
+}
+
+// Finalize saves the final standings of the season by earnings within it and pays prizes[i] to the
+// user on place i+1, skipping users who earned nothing. Everything happens in one transaction,
+// a season is finalized only once, a repeated call returns ErrSeasonAlreadyFinalized.
+// It returns the standings of the prize winners.
+func (s *Storage) Finalize(ctx context.Context, seasonID int, prizes []float64) ([]model.SeasonStanding, error) {
+	op := "seasons.Finalize"
+
+	log := s.log.With(slog.String("op", op), slog.Int("seasonID", seasonID))
+
+	ranked, err := leaderboards.RankedQuery(leaderboards.MetricSeasonEarned)
+	if err != nil {
+		log.Error("failed to build query", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var season dbSeason
+	err = tx.GetContext(ctx, &season, `SELECT `+seasonColumns+` FROM seasons WHERE id = $1 FOR UPDATE`, seasonID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return nil, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrSeasonNotFound
+		}
+
+		log.Error("failed to get season", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if season.FinalizedAt.Valid {
+		if err := tx.Rollback(); err != nil {
+			return nil, err
+		}
+		return nil, errs.ErrSeasonAlreadyFinalized
+	}
+
+	args := append(leaderboards.Args(model.LeaderboardFilter{
+		Since: season.StartsAt,
+		Until: season.EndsAt,
+	}), seasonID)
+
+	_, err = tx.ExecContext(ctx, ranked+`
+		INSERT INTO seasons_standings (season_id, rank, user_id, username, class_id, score)
+		SELECT $5, rank, user_id, username, class_id, score FROM ranked`,
+		args...,
+	)
+	if err != nil {
+		log.Error("failed to save standings", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+
+	var winners []dbStanding
+	err = tx.SelectContext(ctx, &winners,
+		`SELECT `+standingColumns+` FROM seasons_standings
+		 WHERE season_id = $1 AND rank <= $2 AND score > 0
+		 ORDER BY rank`,
+		seasonID, len(prizes),
+	)
+	if err != nil {
+		log.Error("failed to get winners", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+
+	for i := range winners {
+		prize := prizes[winners[i].Rank-1]
+		if prize <= 0 {
+			continue
+		}
+
+		winners[i].TransactionID, err = transactions.Credit(ctx, tx, winners[i].UserID, prize, transactions.PrizeTypeID)
+		if err != nil {
+			log.Error("failed to pay prize", slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return nil, err
+			}
+			return nil, err
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`UPDATE seasons_standings SET prize = $1, transaction_id = $2 WHERE season_id = $3 AND user_id = $4`,
+			prize, winners[i].TransactionID, seasonID, winners[i].UserID,
+		)
+		if err != nil {
+			log.Error("failed to link prize transaction", slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return nil, err
+			}
+			return nil, err
+		}
+
+		winners[i].Prize = prize
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE seasons SET finalized_at = NOW() WHERE id = $1`, seasonID)
+	if err != nil {
+		log.Error("failed to finalize season", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("finalized season", slog.Int("winners", len(winners)))
+
+	standings := make([]model.SeasonStanding, 0, len(winners))
+	for _, winner := range winners {
+		if winner.Prize > 0 {
+			standings = append(standings, model.SeasonStanding(winner))
+		}
+	}
+
+	return standings, nil
+}
+
+// GetStandings returns a page of the final standings of the season.
+func (s *Storage) GetStandings(ctx context.Context, seasonID, limit, offset int) ([]model.SeasonStanding, error) {
+	op := "seasons.GetStandings"
+
+	log := s.log.With(slog.String("op", op), slog.Int("seasonID", seasonID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT ` + standingColumns + ` FROM seasons_standings
+			  WHERE season_id = $1
+			  ORDER BY rank
+			  LIMIT $2 OFFSET $3`
+
+	var dbStandings []dbStanding
+	if err := conn.SelectContext(ctx, &dbStandings, query, seasonID, limit, offset); err != nil {
+		log.Error("failed to get standings", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	standings := make([]model.SeasonStanding, 0, len(dbStandings))
+	for _, standing := range dbStandings {
+		standings = append(standings, model.SeasonStanding(standing))
+	}
+
+	return standings, nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+func (s *Storage) getOne(ctx context.Context, op, query string, args ...interface{}) (model.Season, error) {
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Season{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var season dbSeason
+	if err := conn.GetContext(ctx, &season, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Season{}, errs.ErrSeasonNotFound
+		}
+
+		log.Error("failed to get season", slog.String("error", err.Error()))
+		return model.Season{}, err
+	}
+
+	return season.toModel(), nil
+}
+
+const seasonColumns = `id, name, starts_at, ends_at, created_by, finalized_at, created_at`
+
+const standingColumns = `season_id, rank, user_id, username, class_id, score, prize, COALESCE(transaction_id, 0) AS transaction_id`
+
+type dbSeason struct {
+	ID          int          `db:"id"`
+	Name        string       `db:"name"`
+	StartsAt    time.Time    `db:"starts_at"`
+	EndsAt      time.Time    `db:"ends_at"`
+	CreatedBy   int          `db:"created_by"`
+	FinalizedAt sql.NullTime `db:"finalized_at"`
+	CreatedAt   time.Time    `db:"created_at"`
+}
+
+type dbStanding struct {
+	SeasonID      int     `db:"season_id"`
+	Rank          int     `db:"rank"`
+	UserID        int     `db:"user_id"`
+	Username      string  `db:"username"`
+	ClassID       int     `db:"class_id"`
+	Score         float64 `db:"score"`
+	Prize         float64 `db:"prize"`
+	TransactionID int     `db:"transaction_id"`
+}
+
+func (s dbSeason) toModel() model.Season {
+	return model.Season{
+		ID:          s.ID,
+		Name:        s.Name,
+		StartsAt:    s.StartsAt,
+		EndsAt:      s.EndsAt,
+		CreatedBy:   s.CreatedBy,
+		FinalizedAt: s.FinalizedAt.Time,
+		CreatedAt:   s.CreatedAt,
+	}
+}
+
+func toModels(dbSeasons []dbSeason) []model.Season {
+	seasons := make([]model.Season, 0, len(dbSeasons))
+	for _, season := range dbSeasons {
+		seasons = append(seasons, season.toModel())
+	}
+
+	return seasons
+}
@@ -40,7 +40,7 @@ func (s *Storage) GetAll(ctx context.Context) ([]model.ShopItem, error) {
 	}(conn)
 
 	var items []dbShopItem
-	if err := conn.SelectContext(ctx, &items, "SELECT * FROM shop_items"); err != nil {
+	if err := conn.SelectContext(ctx, &items, "SELECT "+itemColumns+" FROM shop_items"); err != nil {
 		log.Error("failed to get all items", slog.String("error", err.Error()))
 		return nil, err
 	}
@@ -76,7 +76,7 @@ func (s *Storage) GetByID(ctx context.Context, id int) (model.ShopItem, error) {
 	}(conn)
 
 	var item dbShopItem
-	if err := conn.GetContext(ctx, &item, "SELECT * FROM shop_items WHERE id = $1", id); err != nil {
+	if err := conn.GetContext(ctx, &item, "SELECT "+itemColumns+" FROM shop_items WHERE id = $1", id); err != nil {
 		log.Error("failed to get item", slog.String("error", err.Error()))
 		return model.ShopItem{}, err
 	}
@@ -106,12 +106,12 @@ func (s *Storage) Add(ctx context.Context, item model.ShopItem) (int, error) {
 		}
 	}(conn)
 
-	query := `INSERT INTO shop_items (name, description, price, in_stock) 
-			  VALUES ($1, $2, $3, $4) 
+	query := `INSERT INTO shop_items (name, description, price, in_stock, min_level, streak_freezes, resource_id, resource_quantity) 
+			  VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, 0), $8) 
 			  RETURNING id`
 
 	var id int
-	err = conn.QueryRowxContext(ctx, query, item.Name, item.Description, item.Price, item.InStock).Scan(&id)
+	err = conn.QueryRowxContext(ctx, query, item.Name, item.Description, item.Price, item.InStock, item.MinLevel, item.StreakFreezes, item.ResourceID, item.ResourceQuantity).Scan(&id)
 	if err != nil {
 		log.Error("failed to add item", slog.String("error", err.Error()))
 		return 0, err
@@ -126,10 +126,16 @@ func (s *Storage) Close() error {
 	return s.db.Close()
 }
 
+const itemColumns = `id, name, description, price, in_stock, min_level, streak_freezes, COALESCE(resource_id, 0) AS resource_id, resource_quantity`
+
 type dbShopItem struct {
-	ID          int     `db:"id"`
-	Name        string  `db:"name"`
-	Description string  `db:"description"`
-	Price       float64 `db:"price"`
-	InStock     int     `db:"in_stock"`
+	ID               int     `db:"id"`
+	Name             string  `db:"name"`
+	Description      string  `db:"description"`
+	Price            float64 `db:"price"`
+	InStock          int     `db:"in_stock"`
+	MinLevel         int     `db:"min_level"`
+	StreakFreezes    int     `db:"streak_freezes"`
+	ResourceID       int     `db:"resource_id"`
+	ResourceQuantity int     `db:"resource_quantity"`
 }
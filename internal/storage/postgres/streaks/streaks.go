@@ -0,0 +1,284 @@
+package streaks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/streaks"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/nullable"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/transactions"
+	"log/slog"
+	"time"
+)
+
+type Storage struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
+	return &Storage{
+		db:  db,
+		log: log,
+	}
+}
+
+// Get returns the streak of the user as stored. A user without activity has an empty streak.
+func (s *Storage) Get(ctx context.Context, userID int) (model.Streak, error) {
+	op := "streaks.Get"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Streak{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var streak dbStreak
+	if err := conn.GetContext(ctx, &streak, `SELECT `+streakColumns+` FROM users_streaks WHERE user_id = $1`, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Streak{UserID: userID}, nil
+		}
+
+		log.Error("failed to get streak", slog.String("error", err.Error()))
+		return model.Streak{}, err
+	}
+
+	return streak.toModel(), nil
+}
+
+// Touch counts the day as active for the user and returns the updated streak.
+func (s *Storage) Touch(ctx context.Context, userID int, day time.Time) (model.Streak, error) {
+	op := "streaks.Touch"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Streak{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.Streak{}, err
+	}
+
+	streak, err := advance(ctx, tx, userID, day)
+	if err != nil {
+		log.Error("failed to advance streak", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.Streak{}, err
+		}
+		return model.Streak{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.Streak{}, err
+	}
+
+	return streak, nil
+}
+
+// CheckIn records the check-in of the user for the day, advances the streak and pays the bonus for
+// the new streak length in one transaction. A second check-in on the same day returns ErrAlreadyCheckedIn.
+func (s *Storage) CheckIn(ctx context.Context, userID int, day time.Time, bonuses []float64) (model.CheckIn, model.Streak, error) {
+	op := "streaks.CheckIn"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.CheckIn{}, model.Streak{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.CheckIn{}, model.Streak{}, err
+	}
+
+	checkIn := model.CheckIn{
+		UserID: userID,
+		Day:    day,
+	}
+
+	err = tx.QueryRowxContext(ctx,
+		`INSERT INTO check_ins (user_id, day) VALUES ($1, $2)
+		 ON CONFLICT (user_id, day) DO NOTHING
+		 RETURNING id, created_at`,
+		userID, date(day),
+	).Scan(&checkIn.ID, &checkIn.CreatedAt)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.CheckIn{}, model.Streak{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.CheckIn{}, model.Streak{}, errs.ErrAlreadyCheckedIn
+		}
+
+		log.Error("failed to add check-in", slog.String("error", err.Error()))
+		return model.CheckIn{}, model.Streak{}, err
+	}
+
+	streak, err := advance(ctx, tx, userID, day)
+	if err != nil {
+		log.Error("failed to advance streak", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.CheckIn{}, model.Streak{}, err
+		}
+		return model.CheckIn{}, model.Streak{}, err
+	}
+
+	checkIn.Streak = streak.Current
+	checkIn.Bonus = streaks.Bonus(bonuses, streak.Current)
+
+	if checkIn.Bonus > 0 {
+		checkIn.TransactionID, err = transactions.Credit(ctx, tx, userID, checkIn.Bonus, transactions.BonusTypeID)
+		if err != nil {
+			log.Error("failed to pay bonus", slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return model.CheckIn{}, model.Streak{}, err
+			}
+			return model.CheckIn{}, model.Streak{}, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE check_ins SET streak = $1, bonus = $2, transaction_id = $3 WHERE id = $4`,
+		checkIn.Streak, checkIn.Bonus, nullable.ID(checkIn.TransactionID), checkIn.ID,
+	)
+	if err != nil {
+		log.Error("failed to update check-in", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.CheckIn{}, model.Streak{}, err
+		}
+		return model.CheckIn{}, model.Streak{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.CheckIn{}, model.Streak{}, err
+	}
+
+	log.Info("checked in", slog.Int("streak", checkIn.Streak), slog.Float64("bonus", checkIn.Bonus))
+
+	return checkIn, streak, nil
+}
+
+// IsCheckedIn reports whether the user has checked in on the day.
+func (s *Storage) IsCheckedIn(ctx context.Context, userID int, day time.Time) (bool, error) {
+	op := "streaks.IsCheckedIn"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return false, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var checkedIn bool
+	err = conn.GetContext(ctx, &checkedIn, `SELECT EXISTS (SELECT 1 FROM check_ins WHERE user_id = $1 AND day = $2)`, userID, date(day))
+	if err != nil {
+		log.Error("failed to get check-in", slog.String("error", err.Error()))
+		return false, err
+	}
+
+	return checkedIn, nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+// advance locks the streak of the user, creating it on first activity, and counts the day as active.
+func advance(ctx context.Context, tx *sqlx.Tx, userID int, day time.Time) (model.Streak, error) {
+	_, err := tx.ExecContext(ctx, `INSERT INTO users_streaks (user_id) VALUES ($1) ON CONFLICT (user_id) DO NOTHING`, userID)
+	if err != nil {
+		return model.Streak{}, err
+	}
+
+	var current dbStreak
+	if err := tx.GetContext(ctx, &current, `SELECT `+streakColumns+` FROM users_streaks WHERE user_id = $1 FOR UPDATE`, userID); err != nil {
+		return model.Streak{}, err
+	}
+
+	streak := streaks.Advance(current.toModel(), day)
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE users_streaks SET current = $1, longest = $2, freezes = $3, last_active_on = $4, updated_at = NOW()
+		 WHERE user_id = $5`,
+		streak.Current, streak.Longest, streak.Freezes, date(streak.LastActiveOn), userID,
+	)
+	if err != nil {
+		return model.Streak{}, err
+	}
+
+	return streak, nil
+}
+
+// date formats the day for a DATE column, so the session time zone can not shift it.
+func date(day time.Time) string {
+	return day.Format(time.DateOnly)
+}
+
+const streakColumns = `user_id, current, longest, freezes, last_active_on`
+
+type dbStreak struct {
+	UserID       int          `db:"user_id"`
+	Current      int          `db:"current"`
+	Longest      int          `db:"longest"`
+	Freezes      int          `db:"freezes"`
+	LastActiveOn sql.NullTime `db:"last_active_on"`
+}
+
+func (s dbStreak) toModel() model.Streak {
+	return model.Streak{
+		UserID:       s.UserID,
+		Current:      s.Current,
+		Longest:      s.Longest,
+		Freezes:      s.Freezes,
+		LastActiveOn: s.LastActiveOn.Time,
+	}
+}
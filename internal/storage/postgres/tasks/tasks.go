@@ -3,9 +3,17 @@ package tasks
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"github.com/jmoiron/sqlx"
 	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/budgets"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/like"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/transactions"
+	"github.com/lib/pq"
 	"log/slog"
+	"strings"
 	"time"
 )
 
@@ -14,8 +22,19 @@ const (
 	WaitingForAcceptanceStatusID = 2
 	CompletedStatusID            = 3
 	CancelledStatusID            = 4
+	CreationApprovalStatusID     = 5
+	AcceptanceApprovalStatusID   = 6
 	AllGroupID                   = 1
 	UserGroupID                  = 2
+	GeneralCategoryID            = 1
+)
+
+const (
+	SortByCreatedAt = "created_at"
+	SortByAmount    = "amount"
+	SortByName      = "name"
+	OrderAsc        = "asc"
+	OrderDesc       = "desc"
 )
 
 type Storage struct {
@@ -30,7 +49,7 @@ func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
 	}
 }
 
-func (s *Storage) GetAllUserTasks(ctx context.Context, userID int) ([]model.Task, error) {
+func (s *Storage) GetAllUserTasks(ctx context.Context, userID int, othersInProgress bool, filter model.TaskFilter) ([]model.Task, error) {
 	op := "tasks.GetAllUserTasks"
 
 	log := s.log.With(slog.String("op", op))
@@ -50,32 +69,54 @@ func (s *Storage) GetAllUserTasks(ctx context.Context, userID int) ([]model.Task
 		}
 	}(conn)
 
-	query := `SELECT id, name, amount, created_at, created_by, for_group_id FROM tasks WHERE user_id = $1 OR for_group_id = $2 ORDER BY created_at DESC`
+	conditions, args := userConditions(userID, othersInProgress)
 
-	var tasks []dbTask
-	if err := conn.SelectContext(ctx, &tasks, query, userID, AllGroupID); err != nil {
+	query, args := pageQuery(filter, conditions, args)
+
+	var dbTasks []dbTask
+	if err := conn.SelectContext(ctx, &dbTasks, query, args...); err != nil {
 		log.Error("failed to get all tasks", slog.String("error", err.Error()))
 		return nil, err
 	}
 
 	log.Info("got all tasks from storage")
 
-	var shopTasks []model.Task
-	for _, task := range tasks {
-		shopTasks = append(shopTasks, model.Task{
-			ID:         task.ID,
-			Name:       task.Name,
-			Amount:     task.Amount,
-			CreatedAt:  task.CreatedAt,
-			CreatedBy:  task.CreatedBy,
-			ForGroupID: task.ForGroupID,
-		})
+	return toModelTasks(dbTasks), nil
+}
+
+func (s *Storage) CountUserTasks(ctx context.Context, userID int, othersInProgress bool, filter model.TaskFilter) (int, error) {
+	op := "tasks.CountUserTasks"
+
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	conditions, args := userConditions(userID, othersInProgress)
+
+	query, args := countQuery(filter, conditions, args)
+
+	var total int
+	if err := conn.GetContext(ctx, &total, query, args...); err != nil {
+		log.Error("failed to count tasks", slog.String("error", err.Error()))
+		return 0, err
 	}
 
-	return shopTasks, nil
+	return total, nil
 }
 
-func (s *Storage) GetAllAdminTasks(ctx context.Context, adminID int) ([]model.Task, error) {
+func (s *Storage) GetAllAdminTasks(ctx context.Context, adminID int, filter model.TaskFilter) ([]model.Task, error) {
 	op := "tasks.GetAllAdminTasks"
 
 	log := s.log.With(slog.String("op", op))
@@ -95,29 +136,53 @@ func (s *Storage) GetAllAdminTasks(ctx context.Context, adminID int) ([]model.Ta
 		}
 	}(conn)
 
-	query := `SELECT id, name, amount, created_at, created_by, status_id, for_group_id FROM tasks WHERE created_by = $1 ORDER BY created_at DESC`
+	conditions := []string{"created_by = $1"}
+	args := []interface{}{adminID}
+
+	query, args := pageQuery(filter, conditions, args)
 
 	var dbTasks []dbTask
-	if err := conn.SelectContext(ctx, &dbTasks, query, adminID); err != nil {
+	if err := conn.SelectContext(ctx, &dbTasks, query, args...); err != nil {
 		log.Error("failed to get all tasks", slog.String("error", err.Error()))
 		return nil, err
 	}
 
 	log.Info("got all tasks from storage")
 
-	var tasks []model.Task
-	for _, task := range dbTasks {
-		tasks = append(tasks, model.Task{
-			ID:         task.ID,
-			Name:       task.Name,
-			Amount:     task.Amount,
-			CreatedAt:  task.CreatedAt,
-			CreatedBy:  task.CreatedBy,
-			ForGroupID: task.ForGroupID,
-		})
+	return toModelTasks(dbTasks), nil
+}
+
+func (s *Storage) CountAdminTasks(ctx context.Context, adminID int, filter model.TaskFilter) (int, error) {
+	op := "tasks.CountAdminTasks"
+
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	conditions := []string{"created_by = $1"}
+	args := []interface{}{adminID}
+
+	query, args := countQuery(filter, conditions, args)
+
+	var total int
+	if err := conn.GetContext(ctx, &total, query, args...); err != nil {
+		log.Error("failed to count tasks", slog.String("error", err.Error()))
+		return 0, err
 	}
 
-	return tasks, nil
+	return total, nil
 }
 
 func (s *Storage) Add(ctx context.Context, task model.Task) (int, error) {
@@ -147,16 +212,48 @@ func (s *Storage) Add(ctx context.Context, task model.Task) (int, error) {
 
 	var taskID int
 
-	query := `INSERT INTO tasks (name, status_id, amount, created_by, for_group_id, user_id) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	categoryID := task.CategoryID
+	if categoryID == 0 {
+		categoryID = GeneralCategoryID
+	}
+
+	statusID := task.StatusID
+	if statusID == 0 {
+		statusID = InProgressStatusID
+	}
+
+	var budgetID interface{} = task.BudgetID
+	if task.BudgetID == 0 {
+		budgetID = nil
+	}
+
+	var deadline interface{} = task.Deadline
+	if task.Deadline.IsZero() {
+		deadline = nil
+	}
+
+	// tasks without their own XP get it derived from the reward
+	var xp interface{} = task.XP
+	if task.XP == 0 {
+		xp = nil
+	}
+
+	query := `INSERT INTO tasks (name, description, status_id, amount, created_by, for_group_id, user_id, category_id, budget_id, deadline, xp, min_level) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id`
 
 	err = conn.QueryRowxContext(ctx,
 		query,
 		task.Name,
-		InProgressStatusID,
+		task.Description,
+		statusID,
 		task.Amount,
 		task.CreatedBy,
 		task.ForGroupID,
 		userID,
+		categoryID,
+		budgetID,
+		deadline,
+		xp,
+		task.MinLevel,
 	).Scan(&taskID)
 	if err != nil {
 		log.Error("failed to add task", slog.String("error", err.Error()))
@@ -201,7 +298,9 @@ func (s *Storage) MarkAsInProgress(ctx context.Context, taskID int) error {
 	return nil
 }
 
-func (s *Storage) MarkAsWaitingForAcceptance(ctx context.Context, taskID int) error {
+// MarkAsWaitingForAcceptance submits the task. A shared task is bound to the user who submitted it.
+// It returns ErrTaskNotInProgress if the task is no longer in progress or was taken by another user.
+func (s *Storage) MarkAsWaitingForAcceptance(ctx context.Context, taskID, userID int) error {
 	op := "tasks.MarkAsWaitingForAcceptance"
 
 	log := s.log.With(slog.String("op", op))
@@ -221,26 +320,38 @@ func (s *Storage) MarkAsWaitingForAcceptance(ctx context.Context, taskID int) er
 		}
 	}(conn)
 
-	query := `UPDATE tasks SET status_id = $1 WHERE id = $2`
-
-	_, err = conn.ExecContext(ctx, query, WaitingForAcceptanceStatusID, taskID)
+	query := `UPDATE tasks SET status_id = $1, submitted_at = NOW(), user_id = COALESCE(user_id, $3)
+		WHERE id = $2 AND status_id = $4 AND (user_id IS NULL OR user_id = $3)`
 
+	res, err := conn.ExecContext(ctx, query, WaitingForAcceptanceStatusID, taskID, userID, InProgressStatusID)
 	if err != nil {
 		log.Error("failed to mark task as waiting for acceptance", slog.String("error", err.Error()))
 		return err
 	}
 
+	affected, err := res.RowsAffected()
+	if err != nil {
+		log.Error("failed to get affected rows", slog.String("error", err.Error()))
+		return err
+	}
+
+	if affected == 0 {
+		log.Error("task is not in progress by the user")
+		return errs.ErrTaskNotInProgress
+	}
+
 	log.Info("marked task as waiting for acceptance")
 
 	return nil
 }
 
-func (s *Storage) MarkAsCompleted(ctx context.Context, taskID int) error {
-	op := "tasks.MarkAsCompleted"
+// Complete accepts the task waiting for acceptance and pays the payout in one transaction.
+// It returns ErrTaskNotWaiting if the task was accepted or changed in the meantime.
+func (s *Storage) Complete(ctx context.Context, taskID int, payout model.TaskPayout) error {
+	op := "tasks.Complete"
 
-	log := s.log.With(slog.String("op", op))
+	log := s.log.With(slog.String("op", op), slog.Int("taskID", taskID))
 
-	log.Info("marking task as completed")
 	conn, err := s.db.Connx(ctx)
 	if err != nil {
 		log.Error("failed to get connection", slog.String("error", err.Error()))
@@ -255,15 +366,29 @@ func (s *Storage) MarkAsCompleted(ctx context.Context, taskID int) error {
 		}
 	}(conn)
 
-	query := `UPDATE tasks SET status_id = $1 WHERE id = $2`
-
-	_, err = conn.ExecContext(ctx, query, CompletedStatusID, taskID)
+	tx, err := conn.BeginTxx(ctx, nil)
 	if err != nil {
-		log.Error("failed to mark task as completed", slog.String("error", err.Error()))
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := Complete(ctx, tx, taskID, WaitingForAcceptanceStatusID, payout); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		if !errors.Is(err, errs.ErrTaskNotWaiting) {
+			log.Error("failed to complete task", slog.String("error", err.Error()))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
 		return err
 	}
 
-	log.Info("marked task as completed")
+	log.Info("completed task", slog.Float64("reward", payout.Reward))
 
 	return nil
 }
@@ -288,14 +413,25 @@ func (s *Storage) MarkAsCancelled(ctx context.Context, taskID int) error {
 		}
 	}(conn)
 
-	query := `UPDATE tasks SET status_id = $1 WHERE id = $2`
+	query := `UPDATE tasks SET status_id = $1 WHERE id = $2 AND status_id NOT IN ($3, $1)`
 
-	_, err = conn.ExecContext(ctx, query, CancelledStatusID, taskID)
+	res, err := conn.ExecContext(ctx, query, CancelledStatusID, taskID, CompletedStatusID)
 	if err != nil {
 		log.Error("failed to mark task as cancelled", slog.String("error", err.Error()))
 		return err
 	}
 
+	affected, err := res.RowsAffected()
+	if err != nil {
+		log.Error("failed to get affected rows", slog.String("error", err.Error()))
+		return err
+	}
+
+	if affected == 0 {
+		log.Error("task is already closed")
+		return errs.ErrTaskClosed
+	}
+
 	log.Info("marked task as cancelled")
 
 	return nil
@@ -322,10 +458,14 @@ func (s *Storage) GetByID(ctx context.Context, taskID int) (model.Task, error) {
 	}(conn)
 
 	var task dbTask
-	query := `SELECT id, name, status_id, amount, created_at, created_by, for_group_id, user_id FROM tasks WHERE id = $1`
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = $1`
 
 	err = conn.GetContext(ctx, &task, query, taskID)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Error("task not found", slog.String("error", err.Error()))
+			return model.Task{}, errs.ErrTaskNotFound
+		}
 		log.Error("failed to get task", slog.String("error", err.Error()))
 		return model.Task{}, err
 	}
@@ -337,28 +477,512 @@ func (s *Storage) GetByID(ctx context.Context, taskID int) (model.Task, error) {
 	}
 
 	return model.Task{
-		ID:         task.ID,
-		Name:       task.Name,
-		StatusID:   task.StatusID,
-		Amount:     task.Amount,
-		CreatedAt:  task.CreatedAt,
-		CreatedBy:  task.CreatedBy,
-		ForGroupID: task.ForGroupID,
-		UserID:     int(task.UserID.Int64),
+		ID:           task.ID,
+		Name:         task.Name,
+		Description:  task.Description,
+		StatusID:     task.StatusID,
+		Amount:       task.Amount,
+		CreatedAt:    task.CreatedAt,
+		CreatedBy:    task.CreatedBy,
+		ForGroupID:   task.ForGroupID,
+		UserID:       int(task.UserID.Int64),
+		CategoryID:   task.CategoryID,
+		CancelReason: task.CancelReason,
+		SubmittedAt:  task.SubmittedAt.Time,
+		BudgetID:     int(task.BudgetID.Int64),
+		Deadline:     task.Deadline.Time,
+		UserDeadline: task.UserDeadline.Time,
+		XP:           int(task.XP.Int64),
+		MinLevel:     task.MinLevel,
 	}, nil
 }
 
+func (s *Storage) Update(ctx context.Context, taskID int, update model.TaskUpdate) error {
+	op := "tasks.Update"
+
+	log := s.log.With(slog.String("op", op), slog.Int("taskID", taskID))
+
+	log.Info("updating task")
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `UPDATE tasks SET
+			  name = COALESCE($1, name),
+			  description = COALESCE($2, description),
+			  amount = COALESCE($3, amount),
+			  category_id = COALESCE($4, category_id),
+			  xp = COALESCE($5, xp),
+			  min_level = COALESCE($6, min_level),
+			  updated_at = NOW()
+			  WHERE id = $7`
+
+	_, err = conn.ExecContext(ctx, query, update.Name, update.Description, update.Amount, update.CategoryID, update.XP, update.MinLevel, taskID)
+	if err != nil {
+		log.Error("failed to update task", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("updated task")
+
+	return nil
+}
+
+// SetUserDeadline fixes the deadline of the task for its assignee. It does nothing if the task
+// has been given to another user in the meantime.
+func (s *Storage) SetUserDeadline(ctx context.Context, taskID, userID int, deadline time.Time) error {
+	op := "tasks.SetUserDeadline"
+
+	log := s.log.With(slog.String("op", op), slog.Int("taskID", taskID), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var value interface{} = deadline
+	if deadline.IsZero() {
+		value = nil
+	}
+
+	_, err = conn.ExecContext(ctx, `UPDATE tasks SET user_deadline = $1 WHERE id = $2 AND user_id = $3`, value, taskID, userID)
+	if err != nil {
+		log.Error("failed to set user deadline", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// Reassign moves the task to another group or user and restarts it,
+// a submission made by the previous assignee is no longer valid.
+func (s *Storage) Reassign(ctx context.Context, taskID, forGroupID, userID int) error {
+	op := "tasks.Reassign"
+
+	log := s.log.With(slog.String("op", op), slog.Int("taskID", taskID))
+
+	log.Info("reassigning task")
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var userIDValue interface{} = userID
+	if userID == 0 {
+		userIDValue = nil
+	}
+
+	query := `UPDATE tasks SET for_group_id = $1, user_id = $2, status_id = $3, submitted_at = NULL, user_deadline = NULL, updated_at = NOW() WHERE id = $4`
+
+	_, err = conn.ExecContext(ctx, query, forGroupID, userIDValue, InProgressStatusID, taskID)
+	if err != nil {
+		var pqErr *pq.Error
+		// check_user_group trigger raises when a task for the user group has no user
+		if errors.As(err, &pqErr) && pqErr.Code == "P0001" {
+			log.Error("user is required for the group", slog.String("error", err.Error()))
+			return errs.ErrTaskUserRequired
+		}
+
+		log.Error("failed to reassign task", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("reassigned task")
+
+	return nil
+}
+
+func (s *Storage) Cancel(ctx context.Context, taskID, adminID int, reason string) error {
+	op := "tasks.Cancel"
+
+	log := s.log.With(slog.String("op", op), slog.Int("taskID", taskID))
+
+	log.Info("cancelling task")
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `UPDATE tasks SET status_id = $1, cancel_reason = $2, cancelled_by = $3, updated_at = NOW()
+		WHERE id = $4 AND status_id NOT IN ($5, $1)`
+
+	res, err := conn.ExecContext(ctx, query, CancelledStatusID, reason, adminID, taskID, CompletedStatusID)
+	if err != nil {
+		log.Error("failed to cancel task", slog.String("error", err.Error()))
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		log.Error("failed to get affected rows", slog.String("error", err.Error()))
+		return err
+	}
+
+	if affected == 0 {
+		log.Error("task is already closed")
+		return errs.ErrTaskClosed
+	}
+
+	log.Info("cancelled task")
+
+	return nil
+}
+
+// GetReviewQueue returns tasks waiting for acceptance that the admin may review, oldest submissions first.
+func (s *Storage) GetReviewQueue(ctx context.Context, adminID int, limit int) ([]model.Task, error) {
+	op := "tasks.GetReviewQueue"
+
+	log := s.log.With(slog.String("op", op), slog.Int("adminID", adminID))
+
+	log.Info("getting review queue")
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT ` + taskColumns + ` FROM tasks
+			  WHERE status_id = $2 AND EXISTS ` + reviewableBy + `
+			  ORDER BY submitted_at ASC NULLS FIRST, id ASC
+			  LIMIT $3`
+
+	var dbTasks []dbTask
+	if err := conn.SelectContext(ctx, &dbTasks, query, adminID, WaitingForAcceptanceStatusID, limit); err != nil {
+		log.Error("failed to get review queue", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("got review queue")
+
+	return toModelTasks(dbTasks), nil
+}
+
+// GetInProgressByUser returns tasks the user is working on right now, newest first.
+func (s *Storage) GetInProgressByUser(ctx context.Context, userID int, limit int) ([]model.Task, error) {
+	op := "tasks.GetInProgressByUser"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT ` + taskColumns + ` FROM tasks
+			  WHERE user_id = $1 AND status_id = $2
+			  ORDER BY created_at DESC, id DESC
+			  LIMIT $3`
+
+	var dbTasks []dbTask
+	if err := conn.SelectContext(ctx, &dbTasks, query, userID, InProgressStatusID, limit); err != nil {
+		log.Error("failed to get tasks in progress", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return toModelTasks(dbTasks), nil
+}
+
+func (s *Storage) CanReview(ctx context.Context, taskID, adminID int) (bool, error) {
+	op := "tasks.CanReview"
+
+	log := s.log.With(slog.String("op", op), slog.Int("taskID", taskID), slog.Int("adminID", adminID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return false, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $2 AND EXISTS ` + reviewableBy + `)`
+
+	var canReview bool
+	if err := conn.GetContext(ctx, &canReview, query, adminID, taskID); err != nil {
+		log.Error("failed to check review permission", slog.String("error", err.Error()))
+		return false, err
+	}
+
+	return canReview, nil
+}
+
 func (s *Storage) Close() error {
 	return s.db.Close()
 }
 
 type dbTask struct {
-	ID         int           `db:"id"`
-	Name       string        `db:"name"`
-	StatusID   int           `db:"status_id"`
-	Amount     float64       `db:"amount"`
-	CreatedAt  time.Time     `db:"created_at"`
-	CreatedBy  int           `db:"created_by"`
-	ForGroupID int           `db:"for_group_id"`
-	UserID     sql.NullInt64 `db:"user_id"`
+	ID           int           `db:"id"`
+	Name         string        `db:"name"`
+	Description  string        `db:"description"`
+	StatusID     int           `db:"status_id"`
+	Amount       float64       `db:"amount"`
+	CreatedAt    time.Time     `db:"created_at"`
+	CreatedBy    int           `db:"created_by"`
+	ForGroupID   int           `db:"for_group_id"`
+	UserID       sql.NullInt64 `db:"user_id"`
+	CategoryID   int           `db:"category_id"`
+	CancelReason string        `db:"cancel_reason"`
+	SubmittedAt  sql.NullTime  `db:"submitted_at"`
+	BudgetID     sql.NullInt64 `db:"budget_id"`
+	Deadline     sql.NullTime  `db:"deadline"`
+	UserDeadline sql.NullTime  `db:"user_deadline"`
+	XP           sql.NullInt64 `db:"xp"`
+	MinLevel     int           `db:"min_level"`
+}
+
+const taskColumns = `id, name, description, status_id, amount, created_at, created_by, for_group_id, user_id, category_id, cancel_reason, submitted_at, budget_id, deadline, user_deadline, xp, min_level`
+
+// reviewableBy matches tasks that admin $1 may accept: the admin created the task, was assigned
+// as a reviewer of the task or of its group, or holds an active delegation from such an admin.
+const reviewableBy = `(
+	WITH principals AS (
+		SELECT $1::int AS admin_id
+		UNION
+		SELECT from_admin_id FROM reviews_delegations
+		WHERE to_admin_id = $1 AND revoked_at IS NULL AND NOW() >= starts_at AND NOW() < ends_at
+	)
+	SELECT 1 FROM principals p
+	WHERE p.admin_id = tasks.created_by
+	OR EXISTS (SELECT 1 FROM tasks_reviewers tr WHERE tr.task_id = tasks.id AND tr.admin_id = p.admin_id)
+	OR EXISTS (SELECT 1 FROM groups_reviewers gr WHERE gr.group_id = tasks.for_group_id AND gr.admin_id = p.admin_id)
+)`
+
+// sortColumns maps public sort keys to columns and the type used to cast cursor values back.
+var sortColumns = map[string]struct {
+	column string
+	cast   string
+}{
+	SortByCreatedAt: {column: "created_at", cast: "timestamp"},
+	SortByAmount:    {column: "amount", cast: "numeric"},
+	SortByName:      {column: "name", cast: "text"},
+}
+
+// Complete moves the task from the fromStatusID into completed within the transaction, pays the reward
+// to the assignee and charges the budget of the task: the reserved coins are released and the whole
+// reward, perk bonuses included, is spent. A reward that does not fit into the budget returns
+// ErrBudgetExceeded. The status only changes if the task is still in fromStatusID, so a task is never paid twice.
+func Complete(ctx context.Context, tx *sqlx.Tx, taskID, fromStatusID int, payout model.TaskPayout) error {
+	var task struct {
+		UserID   int `db:"user_id"`
+		BudgetID int `db:"budget_id"`
+	}
+	err := tx.GetContext(ctx, &task,
+		`UPDATE tasks SET status_id = $1, updated_at = NOW() WHERE id = $2 AND status_id = $3
+		 RETURNING COALESCE(user_id, 0) AS user_id, COALESCE(budget_id, 0) AS budget_id`,
+		CompletedStatusID, taskID, fromStatusID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.ErrTaskNotWaiting
+		}
+		return err
+	}
+
+	if _, err := transactions.Reward(ctx, tx, payout.SenderID, task.UserID, taskID, payout.Reward); err != nil {
+		return err
+	}
+
+	if task.BudgetID != 0 {
+		if err := budgets.Spend(ctx, tx, task.BudgetID, payout.Reserved, payout.Reward); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func IsSortable(sort string) bool {
+	_, ok := sortColumns[sort]
+	return ok
+}
+
+func filterConditions(filter model.TaskFilter, conditions []string, args []interface{}) ([]string, []interface{}) {
+	if len(filter.StatusIDs) > 0 {
+		args = append(args, pq.Array(filter.StatusIDs))
+		conditions = append(conditions, fmt.Sprintf("status_id = ANY($%d)", len(args)))
+	}
+
+	if filter.GroupID != 0 {
+		args = append(args, filter.GroupID)
+		conditions = append(conditions, fmt.Sprintf("for_group_id = $%d", len(args)))
+	}
+
+	if filter.CategoryID != 0 {
+		args = append(args, filter.CategoryID)
+		conditions = append(conditions, fmt.Sprintf("category_id = $%d", len(args)))
+	}
+
+	if filter.UserID != 0 {
+		args = append(args, filter.UserID)
+		conditions = append(conditions, fmt.Sprintf("user_id = $%d", len(args)))
+	}
+
+	if filter.MinAmount != 0 {
+		args = append(args, filter.MinAmount)
+		conditions = append(conditions, fmt.Sprintf("amount >= $%d", len(args)))
+	}
+
+	if filter.MaxAmount != 0 {
+		args = append(args, filter.MaxAmount)
+		conditions = append(conditions, fmt.Sprintf("amount <= $%d", len(args)))
+	}
+
+	if !filter.CreatedFrom.IsZero() {
+		args = append(args, filter.CreatedFrom)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+
+	if !filter.CreatedTo.IsZero() {
+		args = append(args, filter.CreatedTo)
+		conditions = append(conditions, fmt.Sprintf("created_at <= $%d", len(args)))
+	}
+
+	if filter.Search != "" {
+		args = append(args, filter.Search, like.Escape(filter.Search))
+		conditions = append(conditions, fmt.Sprintf(
+			"(search_vector @@ (websearch_to_tsquery('russian', $%d) || websearch_to_tsquery('english', $%[1]d)) OR name ILIKE '%%' || $%d || '%%')",
+			len(args)-1, len(args),
+		))
+	}
+
+	return conditions, args
+}
+
+func countQuery(filter model.TaskFilter, conditions []string, args []interface{}) (string, []interface{}) {
+	conditions, args = filterConditions(filter, conditions, args)
+
+	return `SELECT count(*) FROM tasks WHERE ` + strings.Join(conditions, " AND "), args
+}
+
+// userConditions returns the conditions of the tasks the user sees: their own and shared ones,
+// and with othersInProgress also the ones other users are working on.
+func userConditions(userID int, othersInProgress bool) ([]string, []interface{}) {
+	visible := "(user_id = $1 OR for_group_id = $2)"
+	args := []interface{}{userID, AllGroupID, CreationApprovalStatusID}
+
+	if othersInProgress {
+		visible = "(user_id = $1 OR for_group_id = $2 OR (status_id = $4 AND user_id IS NOT NULL))"
+		args = append(args, InProgressStatusID)
+	}
+
+	// tasks waiting for creation approval are not published yet
+	return []string{visible, "status_id <> $3"}, args
+}
+
+func pageQuery(filter model.TaskFilter, conditions []string, args []interface{}) (string, []interface{}) {
+	conditions, args = filterConditions(filter, conditions, args)
+
+	sort, ok := sortColumns[filter.Sort]
+	if !ok {
+		sort = sortColumns[SortByCreatedAt]
+	}
+
+	direction, comparison := "DESC", "<"
+	if filter.Order == OrderAsc {
+		direction, comparison = "ASC", ">"
+	}
+
+	if filter.AfterID != 0 {
+		args = append(args, filter.AfterValue, filter.AfterID)
+		conditions = append(conditions, fmt.Sprintf(
+			"(%s, id) %s ($%d::%s, $%d)",
+			sort.column, comparison, len(args)-1, sort.cast, len(args),
+		))
+	}
+
+	args = append(args, filter.Limit)
+
+	query := fmt.Sprintf(
+		`SELECT %s FROM tasks WHERE %s ORDER BY %s %s, id %s LIMIT $%d`,
+		taskColumns, strings.Join(conditions, " AND "), sort.column, direction, direction, len(args),
+	)
+
+	return query, args
+}
+
+func toModelTasks(dbTasks []dbTask) []model.Task {
+	tasks := make([]model.Task, 0, len(dbTasks))
+	for _, task := range dbTasks {
+		tasks = append(tasks, model.Task{
+			ID:           task.ID,
+			Name:         task.Name,
+			Description:  task.Description,
+			StatusID:     task.StatusID,
+			Amount:       task.Amount,
+			CreatedAt:    task.CreatedAt,
+			CreatedBy:    task.CreatedBy,
+			ForGroupID:   task.ForGroupID,
+			UserID:       int(task.UserID.Int64),
+			CategoryID:   task.CategoryID,
+			CancelReason: task.CancelReason,
+			SubmittedAt:  task.SubmittedAt.Time,
+			BudgetID:     int(task.BudgetID.Int64),
+			Deadline:     task.Deadline.Time,
+			UserDeadline: task.UserDeadline.Time,
+			XP:           int(task.XP.Int64),
+			MinLevel:     task.MinLevel,
+		})
+	}
+
+	return tasks
 }
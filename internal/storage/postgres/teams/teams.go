@@ -0,0 +1,1482 @@
+package teams
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/budgets"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/nullable"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/transactions"
+	"github.com/lib/pq"
+	"log/slog"
+	"math"
+	"time"
+)
+
+const (
+	LeaderRoleID               = 1
+	MemberRoleID               = 2
+	PendingInvitationStatusID  = 1
+	AcceptedInvitationStatusID = 2
+	DeclinedInvitationStatusID = 3
+)
+
+const (
+	MetricWallet = "wallet"
+	MetricTasks  = "tasks"
+	MetricEarned = "earned"
+)
+
+// scores holds the score expression of every team leaderboard metric.
+var scores = map[string]string{
+	MetricWallet: `t.wallet`,
+	MetricTasks:  `(SELECT COUNT(*) FROM teams_tasks_completions c WHERE c.team_id = t.id AND c.accepted_at IS NOT NULL)`,
+	MetricEarned: `(SELECT COALESCE(SUM(tt.amount), 0) FROM teams_tasks_completions c
+		JOIN teams_tasks tt ON tt.id = c.team_task_id
+		WHERE c.team_id = t.id AND c.accepted_at IS NOT NULL)`,
+}
+
+var teamQuery = fmt.Sprintf(`SELECT t.id, t.name, t.wallet, t.created_by, t.created_at,
+			  leader.user_id AS leader_id, u.username AS leader_name,
+			  (SELECT COUNT(*) FROM teams_members m WHERE m.team_id = t.id) AS members
+			  FROM teams t
+			  JOIN teams_members leader ON leader.team_id = t.id AND leader.role_id = %d
+			  JOIN users u ON u.id = leader.user_id`, LeaderRoleID)
+
+type Storage struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
+	return &Storage{
+		db:  db,
+		log: log,
+	}
+}
+
+// Create adds a team with the user as its leader.
+func (s *Storage) Create(ctx context.Context, name string, leaderID int) (int, error) {
+	op := "teams.Create"
+
+	log := s.log.With(slog.String("op", op), slog.Int("leaderID", leaderID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	var id int
+	err = tx.QueryRowxContext(ctx, `INSERT INTO teams (name, created_by) VALUES ($1, $2) RETURNING id`, name, leaderID).Scan(&id)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return 0, errs.ErrTeamNameTaken
+		}
+
+		log.Error("failed to add team", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO teams_members (team_id, user_id, role_id) VALUES ($1, $2, $3)`,
+		id, leaderID, LeaderRoleID,
+	)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return 0, errs.ErrAlreadyInTeam
+		}
+
+		log.Error("failed to add leader", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	log.Info("created team", slog.Int("id", id))
+
+	return id, nil
+}
+
+// GetByID returns the team.
+func (s *Storage) GetByID(ctx context.Context, id int) (model.Team, error) {
+	op := "teams.GetByID"
+
+	log := s.log.With(slog.String("op", op), slog.Int("id", id))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Team{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var team dbTeam
+	if err := conn.GetContext(ctx, &team, teamQuery+` WHERE t.id = $1`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Team{}, errs.ErrTeamNotFound
+		}
+
+		log.Error("failed to get team", slog.String("error", err.Error()))
+		return model.Team{}, err
+	}
+
+	return model.Team(team), nil
+}
+
+// GetByMember returns the team of the user, or ErrNotTeamMember if the user is in no team.
+func (s *Storage) GetByMember(ctx context.Context, userID int) (model.Team, error) {
+	op := "teams.GetByMember"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.Team{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	var team dbTeam
+	err = conn.GetContext(ctx, &team,
+		teamQuery+` WHERE t.id = (SELECT team_id FROM teams_members WHERE user_id = $1)`,
+		userID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Team{}, errs.ErrNotTeamMember
+		}
+
+		log.Error("failed to get team", slog.String("error", err.Error()))
+		return model.Team{}, err
+	}
+
+	return model.Team(team), nil
+}
+
+// GetMembers returns the members of the team, the leader first.
+func (s *Storage) GetMembers(ctx context.Context, teamID int) ([]model.TeamMember, error) {
+	op := "teams.GetMembers"
+
+	log := s.log.With(slog.String("op", op), slog.Int("teamID", teamID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT m.team_id, m.user_id, u.username, m.role_id, m.joined_at
+			  FROM teams_members m
+			  JOIN users u ON u.id = m.user_id
+			  WHERE m.team_id = $1
+			  ORDER BY m.role_id, m.joined_at, m.user_id`
+
+	var dbMembers []dbMember
+	if err := conn.SelectContext(ctx, &dbMembers, query, teamID); err != nil {
+		log.Error("failed to get members", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	members := make([]model.TeamMember, 0, len(dbMembers))
+	for _, member := range dbMembers {
+		members = append(members, model.TeamMember(member))
+	}
+
+	return members, nil
+}
+
+// Invite adds a pending invitation of the user to the team of the leader.
+func (s *Storage) Invite(ctx context.Context, leaderID, userID int) (model.TeamInvitation, error) {
+	op := "teams.Invite"
+
+	log := s.log.With(slog.String("op", op), slog.Int("leaderID", leaderID), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.TeamInvitation{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.TeamInvitation{}, err
+	}
+
+	leader, err := lockLeader(ctx, tx, leaderID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.TeamInvitation{}, err
+		}
+
+		if !errors.Is(err, errs.ErrNotTeamMember) && !errors.Is(err, errs.ErrNotTeamLeader) {
+			log.Error("failed to get leader", slog.String("error", err.Error()))
+		}
+		return model.TeamInvitation{}, err
+	}
+
+	var inTeam bool
+	err = tx.GetContext(ctx, &inTeam, `SELECT EXISTS (SELECT 1 FROM teams_members WHERE user_id = $1)`, userID)
+	if err != nil {
+		log.Error("failed to get membership", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.TeamInvitation{}, err
+		}
+		return model.TeamInvitation{}, err
+	}
+
+	if inTeam {
+		if err := tx.Rollback(); err != nil {
+			return model.TeamInvitation{}, err
+		}
+		return model.TeamInvitation{}, errs.ErrAlreadyInTeam
+	}
+
+	invitation := model.TeamInvitation{
+		TeamID:    leader.TeamID,
+		UserID:    userID,
+		InvitedBy: leaderID,
+		StatusID:  PendingInvitationStatusID,
+	}
+
+	err = tx.QueryRowxContext(ctx,
+		`INSERT INTO teams_invitations (team_id, user_id, invited_by, status_id) VALUES ($1, $2, $3, $4)
+		 RETURNING id, created_at, (SELECT name FROM teams WHERE id = $1)`,
+		invitation.TeamID, userID, leaderID, PendingInvitationStatusID,
+	).Scan(&invitation.ID, &invitation.CreatedAt, &invitation.TeamName)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.TeamInvitation{}, err
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			switch pqErr.Code {
+			case "23505":
+				return model.TeamInvitation{}, errs.ErrInvitationPending
+			case "23503":
+				return model.TeamInvitation{}, errs.ErrUserNotFound
+			}
+		}
+
+		log.Error("failed to add invitation", slog.String("error", err.Error()))
+		return model.TeamInvitation{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.TeamInvitation{}, err
+	}
+
+	log.Info("invited to team", slog.Int("id", invitation.ID), slog.Int("teamID", invitation.TeamID))
+
+	return invitation, nil
+}
+
+// GetInvitations returns the pending invitations of the user, newest first.
+func (s *Storage) GetInvitations(ctx context.Context, userID int) ([]model.TeamInvitation, error) {
+	op := "teams.GetInvitations"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT i.id, i.team_id, t.name AS team_name, i.user_id, i.invited_by, i.status_id, i.created_at, i.responded_at
+			  FROM teams_invitations i
+			  JOIN teams t ON t.id = i.team_id
+			  WHERE i.user_id = $1 AND i.status_id = $2
+			  ORDER BY i.created_at DESC, i.id DESC`
+
+	var dbInvitations []dbInvitation
+	if err := conn.SelectContext(ctx, &dbInvitations, query, userID, PendingInvitationStatusID); err != nil {
+		log.Error("failed to get invitations", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	invitations := make([]model.TeamInvitation, 0, len(dbInvitations))
+	for _, invitation := range dbInvitations {
+		invitations = append(invitations, invitation.toModel())
+	}
+
+	return invitations, nil
+}
+
+// Accept adds the user to the team of the pending invitation, unless the team already has maxMembers.
+// The other pending invitations of the user are declined.
+func (s *Storage) Accept(ctx context.Context, invitationID, userID, maxMembers int) (int, error) {
+	op := "teams.Accept"
+
+	log := s.log.With(slog.String("op", op), slog.Int("invitationID", invitationID), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	var teamID int
+	err = tx.GetContext(ctx, &teamID,
+		`SELECT team_id FROM teams_invitations WHERE id = $1 AND user_id = $2 AND status_id = $3 FOR UPDATE`,
+		invitationID, userID, PendingInvitationStatusID,
+	)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errs.ErrInvitationNotFound
+		}
+
+		log.Error("failed to get invitation", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	// the team is locked so concurrent joins can not overfill it
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM teams WHERE id = $1 FOR UPDATE`, teamID); err != nil {
+		log.Error("failed to lock team", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+		return 0, err
+	}
+
+	var members int
+	if err := tx.GetContext(ctx, &members, `SELECT COUNT(*) FROM teams_members WHERE team_id = $1`, teamID); err != nil {
+		log.Error("failed to count members", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+		return 0, err
+	}
+
+	if maxMembers > 0 && members >= maxMembers {
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+		return 0, errs.ErrTeamFull
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO teams_members (team_id, user_id, role_id) VALUES ($1, $2, $3)`,
+		teamID, userID, MemberRoleID,
+	)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return 0, errs.ErrAlreadyInTeam
+		}
+
+		log.Error("failed to add member", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE teams_invitations SET status_id = CASE WHEN id = $1 THEN $2::INTEGER ELSE $3::INTEGER END, responded_at = NOW()
+		 WHERE user_id = $4 AND status_id = $5`,
+		invitationID, AcceptedInvitationStatusID, DeclinedInvitationStatusID, userID, PendingInvitationStatusID,
+	)
+	if err != nil {
+		log.Error("failed to update invitations", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	log.Info("joined team", slog.Int("teamID", teamID))
+
+	return teamID, nil
+}
+
+// Decline declines the pending invitation of the user.
+func (s *Storage) Decline(ctx context.Context, invitationID, userID int) error {
+	op := "teams.Decline"
+
+	log := s.log.With(slog.String("op", op), slog.Int("invitationID", invitationID), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	res, err := conn.ExecContext(ctx,
+		`UPDATE teams_invitations SET status_id = $1, responded_at = NOW() WHERE id = $2 AND user_id = $3 AND status_id = $4`,
+		DeclinedInvitationStatusID, invitationID, userID, PendingInvitationStatusID,
+	)
+	if err != nil {
+		log.Error("failed to decline invitation", slog.String("error", err.Error()))
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		log.Error("failed to get affected rows", slog.String("error", err.Error()))
+		return err
+	}
+
+	if affected == 0 {
+		return errs.ErrInvitationNotFound
+	}
+
+	return nil
+}
+
+// Leave removes the user from the team. A leaving leader hands the team over to the member
+// who joined first, the last member can not leave. Submissions of the user to tasks the team
+// has not completed yet are dropped, shares of completed tasks are still paid to the user.
+func (s *Storage) Leave(ctx context.Context, userID int) error {
+	op := "teams.Leave"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return err
+	}
+
+	member, err := lockMember(ctx, tx, userID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		if !errors.Is(err, errs.ErrNotTeamMember) {
+			log.Error("failed to get member", slog.String("error", err.Error()))
+		}
+		return err
+	}
+
+	var successorID int
+	err = tx.GetContext(ctx, &successorID,
+		`SELECT user_id FROM teams_members WHERE team_id = $1 AND user_id <> $2 ORDER BY joined_at, user_id LIMIT 1`,
+		member.TeamID, userID,
+	)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.ErrLastTeamMember
+		}
+
+		log.Error("failed to get successor", slog.String("error", err.Error()))
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM teams_members WHERE user_id = $1`, userID); err != nil {
+		log.Error("failed to delete member", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`DELETE FROM teams_tasks_submissions s
+		 WHERE s.user_id = $1 AND s.team_id = $2 AND NOT EXISTS (
+			SELECT 1 FROM teams_tasks_completions c WHERE c.team_task_id = s.team_task_id AND c.team_id = s.team_id
+		 )`,
+		userID, member.TeamID,
+	)
+	if err != nil {
+		log.Error("failed to delete open submissions", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+		return err
+	}
+
+	if member.RoleID == LeaderRoleID {
+		_, err = tx.ExecContext(ctx, `UPDATE teams_members SET role_id = $1 WHERE user_id = $2`, LeaderRoleID, successorID)
+		if err != nil {
+			log.Error("failed to hand over team", slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return err
+			}
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("left team", slog.Int("teamID", member.TeamID))
+
+	return nil
+}
+
+// Contribute moves amount coins from the balance of the user to the wallet of their team.
+func (s *Storage) Contribute(ctx context.Context, userID int, amount float64) (model.TeamWalletEntry, error) {
+	op := "teams.Contribute"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.TeamWalletEntry{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.TeamWalletEntry{}, err
+	}
+
+	member, err := lockMember(ctx, tx, userID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.TeamWalletEntry{}, err
+		}
+
+		if !errors.Is(err, errs.ErrNotTeamMember) {
+			log.Error("failed to get member", slog.String("error", err.Error()))
+		}
+		return model.TeamWalletEntry{}, err
+	}
+
+	entry := model.TeamWalletEntry{
+		TeamID:   member.TeamID,
+		UserID:   userID,
+		Username: member.Username,
+		Amount:   amount,
+	}
+
+	entry.TransactionID, err = transactions.Debit(ctx, tx, userID, amount, transactions.TeamContributionTypeID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.TeamWalletEntry{}, err
+		}
+
+		if !errors.Is(err, errs.ErrInsufficientFunds) {
+			log.Error("failed to pay contribution", slog.String("error", err.Error()))
+		}
+		return model.TeamWalletEntry{}, err
+	}
+
+	if err := addWalletEntry(ctx, tx, &entry); err != nil {
+		log.Error("failed to add wallet entry", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.TeamWalletEntry{}, err
+		}
+		return model.TeamWalletEntry{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.TeamWalletEntry{}, err
+	}
+
+	log.Info("contributed to team", slog.Int("teamID", entry.TeamID), slog.Float64("amount", amount))
+
+	return entry, nil
+}
+
+// Payout moves amount coins from the team wallet to a member of the team. Only the leader may pay out.
+func (s *Storage) Payout(ctx context.Context, leaderID, memberID int, amount float64) (model.TeamWalletEntry, error) {
+	op := "teams.Payout"
+
+	log := s.log.With(slog.String("op", op), slog.Int("leaderID", leaderID), slog.Int("memberID", memberID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.TeamWalletEntry{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.TeamWalletEntry{}, err
+	}
+
+	leader, err := lockLeader(ctx, tx, leaderID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.TeamWalletEntry{}, err
+		}
+
+		if !errors.Is(err, errs.ErrNotTeamMember) && !errors.Is(err, errs.ErrNotTeamLeader) {
+			log.Error("failed to get leader", slog.String("error", err.Error()))
+		}
+		return model.TeamWalletEntry{}, err
+	}
+
+	entry := model.TeamWalletEntry{
+		TeamID: leader.TeamID,
+		UserID: memberID,
+		Amount: -amount,
+	}
+
+	err = tx.GetContext(ctx, &entry.Username,
+		`SELECT u.username FROM teams_members m JOIN users u ON u.id = m.user_id WHERE m.team_id = $1 AND m.user_id = $2`,
+		leader.TeamID, memberID,
+	)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.TeamWalletEntry{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.TeamWalletEntry{}, errs.ErrNotTeamMember
+		}
+
+		log.Error("failed to get member", slog.String("error", err.Error()))
+		return model.TeamWalletEntry{}, err
+	}
+
+	var wallet float64
+	if err := tx.GetContext(ctx, &wallet, `SELECT wallet FROM teams WHERE id = $1 FOR UPDATE`, leader.TeamID); err != nil {
+		log.Error("failed to get wallet", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.TeamWalletEntry{}, err
+		}
+		return model.TeamWalletEntry{}, err
+	}
+
+	if wallet < amount {
+		if err := tx.Rollback(); err != nil {
+			return model.TeamWalletEntry{}, err
+		}
+		return model.TeamWalletEntry{}, errs.ErrTeamWalletInsufficient
+	}
+
+	entry.TransactionID, err = transactions.Credit(ctx, tx, memberID, amount, transactions.TeamPayoutTypeID)
+	if err != nil {
+		log.Error("failed to pay out", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.TeamWalletEntry{}, err
+		}
+		return model.TeamWalletEntry{}, err
+	}
+
+	if err := addWalletEntry(ctx, tx, &entry); err != nil {
+		log.Error("failed to add wallet entry", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.TeamWalletEntry{}, err
+		}
+		return model.TeamWalletEntry{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.TeamWalletEntry{}, err
+	}
+
+	log.Info("paid out of team wallet", slog.Int("teamID", entry.TeamID), slog.Float64("amount", amount))
+
+	return entry, nil
+}
+
+// GetWallet returns a page of the wallet ledger of the team, newest first.
+func (s *Storage) GetWallet(ctx context.Context, teamID, limit, offset int) ([]model.TeamWalletEntry, error) {
+	op := "teams.GetWallet"
+
+	log := s.log.With(slog.String("op", op), slog.Int("teamID", teamID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT e.id, e.team_id, e.user_id, u.username, e.amount, e.transaction_id, e.created_at
+			  FROM teams_wallet_entries e
+			  JOIN users u ON u.id = e.user_id
+			  WHERE e.team_id = $1
+			  ORDER BY e.created_at DESC, e.id DESC
+			  LIMIT $2 OFFSET $3`
+
+	var dbEntries []dbWalletEntry
+	if err := conn.SelectContext(ctx, &dbEntries, query, teamID, limit, offset); err != nil {
+		log.Error("failed to get wallet entries", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	entries := make([]model.TeamWalletEntry, 0, len(dbEntries))
+	for _, entry := range dbEntries {
+		entries = append(entries, model.TeamWalletEntry(entry))
+	}
+
+	return entries, nil
+}
+
+// AddTask adds a team task.
+func (s *Storage) AddTask(ctx context.Context, task model.TeamTask) (int, error) {
+	op := "teams.AddTask"
+
+	log := s.log.With(slog.String("op", op), slog.Int("createdBy", task.CreatedBy))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `INSERT INTO teams_tasks (name, description, amount, required_members, team_id, budget_id, created_by)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7)
+			  RETURNING id`
+
+	var id int
+	err = conn.QueryRowxContext(ctx, query,
+		task.Name,
+		task.Description,
+		task.Amount,
+		task.RequiredMembers,
+		nullable.ID(task.TeamID),
+		nullable.ID(task.BudgetID),
+		task.CreatedBy,
+	).Scan(&id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return 0, errs.ErrTeamNotFound
+		}
+
+		log.Error("failed to add team task", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	log.Info("added team task", slog.Int("id", id))
+
+	return id, nil
+}
+
+// GetTasks returns the tasks open to the team with its progress on each, newest first.
+func (s *Storage) GetTasks(ctx context.Context, teamID, userID int) ([]model.TeamTaskProgress, error) {
+	op := "teams.GetTasks"
+
+	log := s.log.With(slog.String("op", op), slog.Int("teamID", teamID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT ` + taskColumns + `,
+			  (SELECT COUNT(*) FROM teams_tasks_submissions s WHERE s.team_task_id = tt.id AND s.team_id = $1) AS submitted,
+			  EXISTS (SELECT 1 FROM teams_tasks_submissions s WHERE s.team_task_id = tt.id AND s.user_id = $2) AS submitted_by_me,
+			  c.completed_at, c.accepted_at
+			  FROM teams_tasks tt
+			  LEFT JOIN teams_tasks_completions c ON c.team_task_id = tt.id AND c.team_id = $1
+			  WHERE tt.team_id IS NULL OR tt.team_id = $1
+			  ORDER BY tt.created_at DESC, tt.id DESC`
+
+	var dbProgress []dbTaskProgress
+	if err := conn.SelectContext(ctx, &dbProgress, query, teamID, userID); err != nil {
+		log.Error("failed to get team tasks", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	progress := make([]model.TeamTaskProgress, 0, len(dbProgress))
+	for _, p := range dbProgress {
+		progress = append(progress, p.toModel())
+	}
+
+	return progress, nil
+}
+
+// Submit records the submission of the team task by the user for their team. Once RequiredMembers
+// of the team have submitted, the task is completed for the team and waits for an admin.
+func (s *Storage) Submit(ctx context.Context, taskID, userID int) (model.TeamTaskProgress, error) {
+	op := "teams.Submit"
+
+	log := s.log.With(slog.String("op", op), slog.Int("taskID", taskID), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.TeamTaskProgress{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.TeamTaskProgress{}, err
+	}
+
+	member, err := lockMember(ctx, tx, userID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.TeamTaskProgress{}, err
+		}
+
+		if !errors.Is(err, errs.ErrNotTeamMember) {
+			log.Error("failed to get member", slog.String("error", err.Error()))
+		}
+		return model.TeamTaskProgress{}, err
+	}
+
+	// the team is locked so the submissions of its members are counted one at a time
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM teams WHERE id = $1 FOR UPDATE`, member.TeamID); err != nil {
+		log.Error("failed to lock team", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.TeamTaskProgress{}, err
+		}
+		return model.TeamTaskProgress{}, err
+	}
+
+	var task dbTask
+	err = tx.GetContext(ctx, &task,
+		`SELECT `+taskColumns+` FROM teams_tasks tt WHERE tt.id = $1 AND (tt.team_id IS NULL OR tt.team_id = $2)`,
+		taskID, member.TeamID,
+	)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.TeamTaskProgress{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.TeamTaskProgress{}, errs.ErrTeamTaskNotFound
+		}
+
+		log.Error("failed to get team task", slog.String("error", err.Error()))
+		return model.TeamTaskProgress{}, err
+	}
+
+	var completed bool
+	err = tx.GetContext(ctx, &completed,
+		`SELECT EXISTS (SELECT 1 FROM teams_tasks_completions WHERE team_task_id = $1 AND team_id = $2)`,
+		taskID, member.TeamID,
+	)
+	if err != nil {
+		log.Error("failed to get completion", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.TeamTaskProgress{}, err
+		}
+		return model.TeamTaskProgress{}, err
+	}
+
+	if completed {
+		if err := tx.Rollback(); err != nil {
+			return model.TeamTaskProgress{}, err
+		}
+		return model.TeamTaskProgress{}, errs.ErrTeamTaskAlreadyCompleted
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO teams_tasks_submissions (team_task_id, team_id, user_id) VALUES ($1, $2, $3)`,
+		taskID, member.TeamID, userID,
+	)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.TeamTaskProgress{}, err
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return model.TeamTaskProgress{}, errs.ErrTeamTaskAlreadySubmitted
+		}
+
+		log.Error("failed to add submission", slog.String("error", err.Error()))
+		return model.TeamTaskProgress{}, err
+	}
+
+	progress := model.TeamTaskProgress{
+		Task:          task.toModel(),
+		SubmittedByMe: true,
+	}
+
+	err = tx.GetContext(ctx, &progress.Submitted,
+		`SELECT COUNT(*) FROM teams_tasks_submissions WHERE team_task_id = $1 AND team_id = $2`,
+		taskID, member.TeamID,
+	)
+	if err != nil {
+		log.Error("failed to count submissions", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.TeamTaskProgress{}, err
+		}
+		return model.TeamTaskProgress{}, err
+	}
+
+	if progress.Submitted >= progress.Task.RequiredMembers {
+		err = tx.QueryRowxContext(ctx,
+			`INSERT INTO teams_tasks_completions (team_task_id, team_id) VALUES ($1, $2) RETURNING completed_at`,
+			taskID, member.TeamID,
+		).Scan(&progress.CompletedAt)
+		if err != nil {
+			log.Error("failed to add completion", slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return model.TeamTaskProgress{}, err
+			}
+			return model.TeamTaskProgress{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.TeamTaskProgress{}, err
+	}
+
+	log.Info("submitted team task", slog.Int("teamID", member.TeamID), slog.Int("submitted", progress.Submitted))
+
+	return progress, nil
+}
+
+// GetCompletions returns up to limit completions waiting for acceptance, oldest first.
+func (s *Storage) GetCompletions(ctx context.Context, limit int) ([]model.TeamTaskCompletion, error) {
+	op := "teams.GetCompletions"
+
+	log := s.log.With(slog.String("op", op))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := completionQuery + `
+			  WHERE c.accepted_at IS NULL
+			  ORDER BY c.completed_at, c.id
+			  LIMIT $1`
+
+	var dbCompletions []dbCompletion
+	if err := conn.SelectContext(ctx, &dbCompletions, query, limit); err != nil {
+		log.Error("failed to get completions", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	completions := make([]model.TeamTaskCompletion, 0, len(dbCompletions))
+	for _, completion := range dbCompletions {
+		completions = append(completions, completion.toModel())
+	}
+
+	return completions, nil
+}
+
+// AcceptCompletion accepts the completion and splits the reward of the task equally between
+// the members who submitted it, in one transaction. The paid coins are charged to the budget of
+// the task, it returns ErrBudgetExceeded if they do not fit into it.
+func (s *Storage) AcceptCompletion(ctx context.Context, completionID, adminID int) (model.TeamTaskCompletion, error) {
+	op := "teams.AcceptCompletion"
+
+	log := s.log.With(slog.String("op", op), slog.Int("completionID", completionID), slog.Int("adminID", adminID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.TeamTaskCompletion{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.TeamTaskCompletion{}, err
+	}
+
+	var dbCompletion dbCompletion
+	err = tx.GetContext(ctx, &dbCompletion, completionQuery+` WHERE c.id = $1 FOR UPDATE OF c`, completionID)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return model.TeamTaskCompletion{}, err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.TeamTaskCompletion{}, errs.ErrTeamCompletionNotFound
+		}
+
+		log.Error("failed to get completion", slog.String("error", err.Error()))
+		return model.TeamTaskCompletion{}, err
+	}
+
+	if dbCompletion.AcceptedAt.Valid {
+		if err := tx.Rollback(); err != nil {
+			return model.TeamTaskCompletion{}, err
+		}
+		return model.TeamTaskCompletion{}, errs.ErrTeamCompletionAlreadyAccepted
+	}
+
+	completion := dbCompletion.toModel()
+
+	err = tx.SelectContext(ctx, &completion.SubmitterIDs,
+		`SELECT user_id FROM teams_tasks_submissions WHERE team_task_id = $1 AND team_id = $2 ORDER BY submitted_at, user_id`,
+		completion.TeamTaskID, completion.TeamID,
+	)
+	if err != nil {
+		log.Error("failed to get submitters", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.TeamTaskCompletion{}, err
+		}
+		return model.TeamTaskCompletion{}, err
+	}
+
+	// the share is rounded down to whole cents, the remainder is not paid
+	completion.Share = math.Floor(completion.Amount/float64(len(completion.SubmitterIDs))*100) / 100
+
+	if dbCompletion.BudgetID != 0 {
+		// only a task of one team has its reward reserved, a task open to all teams is charged as it is paid
+		reserved := 0.0
+		if dbCompletion.Reserved {
+			reserved = completion.Amount
+		}
+
+		paid := completion.Share * float64(len(completion.SubmitterIDs))
+		if err := budgets.Spend(ctx, tx, dbCompletion.BudgetID, reserved, paid); err != nil {
+			log.Error("failed to spend budget", slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return model.TeamTaskCompletion{}, err
+			}
+			return model.TeamTaskCompletion{}, err
+		}
+	}
+
+	for _, userID := range completion.SubmitterIDs {
+		if _, err := transactions.Credit(ctx, tx, userID, completion.Share, transactions.RewardTypeID); err != nil {
+			log.Error("failed to pay share", slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return model.TeamTaskCompletion{}, err
+			}
+			return model.TeamTaskCompletion{}, err
+		}
+	}
+
+	err = tx.QueryRowxContext(ctx,
+		`UPDATE teams_tasks_completions SET share = $1, accepted_at = NOW(), accepted_by = $2 WHERE id = $3 RETURNING accepted_at`,
+		completion.Share, adminID, completionID,
+	).Scan(&completion.AcceptedAt)
+	if err != nil {
+		log.Error("failed to update completion", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.TeamTaskCompletion{}, err
+		}
+		return model.TeamTaskCompletion{}, err
+	}
+
+	completion.AcceptedBy = adminID
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.TeamTaskCompletion{}, err
+	}
+
+	log.Info("accepted team task completion", slog.Float64("share", completion.Share), slog.Int("submitters", len(completion.SubmitterIDs)))
+
+	return completion, nil
+}
+
+// GetLeaderboard returns a page of teams ranked by the metric, highest score first.
+func (s *Storage) GetLeaderboard(ctx context.Context, metric string, limit, offset int) ([]model.TeamLeaderboardEntry, error) {
+	op := "teams.GetLeaderboard"
+
+	log := s.log.With(slog.String("op", op), slog.String("metric", metric))
+
+	score, ok := scores[metric]
+	if !ok {
+		return nil, errs.ErrUnknownMetric
+	}
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `WITH scores AS (
+				SELECT t.id AS team_id, t.name,
+				(SELECT COUNT(*) FROM teams_members m WHERE m.team_id = t.id) AS members,
+				` + score + ` AS score
+				FROM teams t
+			  )
+			  SELECT ROW_NUMBER() OVER (ORDER BY score DESC, team_id) AS rank, team_id, name, members, score
+			  FROM scores
+			  ORDER BY rank
+			  LIMIT $1 OFFSET $2`
+
+	var dbEntries []dbLeaderboardEntry
+	if err := conn.SelectContext(ctx, &dbEntries, query, limit, offset); err != nil {
+		log.Error("failed to get team leaderboard", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	entries := make([]model.TeamLeaderboardEntry, 0, len(dbEntries))
+	for _, entry := range dbEntries {
+		entries = append(entries, model.TeamLeaderboardEntry(entry))
+	}
+
+	return entries, nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+// lockMember locks the membership of the user, so it can not change until the transaction ends.
+func lockMember(ctx context.Context, tx *sqlx.Tx, userID int) (dbMember, error) {
+	var member dbMember
+	err := tx.GetContext(ctx, &member,
+		`SELECT m.team_id, m.user_id, u.username, m.role_id, m.joined_at
+		 FROM teams_members m
+		 JOIN users u ON u.id = m.user_id
+		 WHERE m.user_id = $1
+		 FOR UPDATE OF m`,
+		userID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dbMember{}, errs.ErrNotTeamMember
+		}
+		return dbMember{}, err
+	}
+
+	return member, nil
+}
+
+// lockLeader locks the membership of the user and checks that they lead their team.
+func lockLeader(ctx context.Context, tx *sqlx.Tx, userID int) (dbMember, error) {
+	member, err := lockMember(ctx, tx, userID)
+	if err != nil {
+		return dbMember{}, err
+	}
+
+	if member.RoleID != LeaderRoleID {
+		return dbMember{}, errs.ErrNotTeamLeader
+	}
+
+	return member, nil
+}
+
+// addWalletEntry records the entry in the ledger and applies it to the team wallet.
+func addWalletEntry(ctx context.Context, tx *sqlx.Tx, entry *model.TeamWalletEntry) error {
+	_, err := tx.ExecContext(ctx, `UPDATE teams SET wallet = wallet + $1 WHERE id = $2`, entry.Amount, entry.TeamID)
+	if err != nil {
+		return err
+	}
+
+	return tx.QueryRowxContext(ctx,
+		`INSERT INTO teams_wallet_entries (team_id, user_id, amount, transaction_id) VALUES ($1, $2, $3, $4)
+		 RETURNING id, created_at`,
+		entry.TeamID, entry.UserID, entry.Amount, entry.TransactionID,
+	).Scan(&entry.ID, &entry.CreatedAt)
+}
+
+const taskColumns = `tt.id, tt.name, tt.description, tt.amount, tt.required_members,
+			  COALESCE(tt.team_id, 0) AS team_id, COALESCE(tt.budget_id, 0) AS budget_id, tt.created_by, tt.created_at`
+
+const completionQuery = `SELECT c.id, c.team_task_id, tt.name AS task_name, c.team_id, t.name AS team_name,
+			  tt.amount, COALESCE(tt.budget_id, 0) AS budget_id, tt.team_id IS NOT NULL AS reserved, COALESCE(c.share, 0) AS share, c.completed_at, c.accepted_at, COALESCE(c.accepted_by, 0) AS accepted_by
+			  FROM teams_tasks_completions c
+			  JOIN teams_tasks tt ON tt.id = c.team_task_id
+			  JOIN teams t ON t.id = c.team_id`
+
+type dbTeam struct {
+	ID         int       `db:"id"`
+	Name       string    `db:"name"`
+	LeaderID   int       `db:"leader_id"`
+	LeaderName string    `db:"leader_name"`
+	Members    int       `db:"members"`
+	Wallet     float64   `db:"wallet"`
+	CreatedBy  int       `db:"created_by"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+type dbMember struct {
+	TeamID   int       `db:"team_id"`
+	UserID   int       `db:"user_id"`
+	Username string    `db:"username"`
+	RoleID   int       `db:"role_id"`
+	JoinedAt time.Time `db:"joined_at"`
+}
+
+type dbInvitation struct {
+	ID          int          `db:"id"`
+	TeamID      int          `db:"team_id"`
+	TeamName    string       `db:"team_name"`
+	UserID      int          `db:"user_id"`
+	InvitedBy   int          `db:"invited_by"`
+	StatusID    int          `db:"status_id"`
+	CreatedAt   time.Time    `db:"created_at"`
+	RespondedAt sql.NullTime `db:"responded_at"`
+}
+
+func (i dbInvitation) toModel() model.TeamInvitation {
+	return model.TeamInvitation{
+		ID:          i.ID,
+		TeamID:      i.TeamID,
+		TeamName:    i.TeamName,
+		UserID:      i.UserID,
+		InvitedBy:   i.InvitedBy,
+		StatusID:    i.StatusID,
+		CreatedAt:   i.CreatedAt,
+		RespondedAt: i.RespondedAt.Time,
+	}
+}
+
+type dbWalletEntry struct {
+	ID            int       `db:"id"`
+	TeamID        int       `db:"team_id"`
+	UserID        int       `db:"user_id"`
+	Username      string    `db:"username"`
+	Amount        float64   `db:"amount"`
+	TransactionID int       `db:"transaction_id"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+type dbTask struct {
+	ID              int       `db:"id"`
+	Name            string    `db:"name"`
+	Description     string    `db:"description"`
+	Amount          float64   `db:"amount"`
+	RequiredMembers int       `db:"required_members"`
+	TeamID          int       `db:"team_id"`
+	BudgetID        int       `db:"budget_id"`
+	CreatedBy       int       `db:"created_by"`
+	CreatedAt       time.Time `db:"created_at"`
+}
+
+func (t dbTask) toModel() model.TeamTask {
+	return model.TeamTask(t)
+}
+
+type dbTaskProgress struct {
+	dbTask
+	Submitted     int          `db:"submitted"`
+	SubmittedByMe bool         `db:"submitted_by_me"`
+	CompletedAt   sql.NullTime `db:"completed_at"`
+	AcceptedAt    sql.NullTime `db:"accepted_at"`
+}
+
+func (p dbTaskProgress) toModel() model.TeamTaskProgress {
+	return model.TeamTaskProgress{
+		Task:          p.dbTask.toModel(),
+		Submitted:     p.Submitted,
+		SubmittedByMe: p.SubmittedByMe,
+		CompletedAt:   p.CompletedAt.Time,
+		AcceptedAt:    p.AcceptedAt.Time,
+	}
+}
+
+type dbCompletion struct {
+	ID          int          `db:"id"`
+	TeamTaskID  int          `db:"team_task_id"`
+	TaskName    string       `db:"task_name"`
+	TeamID      int          `db:"team_id"`
+	TeamName    string       `db:"team_name"`
+	Amount      float64      `db:"amount"`
+	BudgetID    int          `db:"budget_id"`
+	Reserved    bool         `db:"reserved"`
+	Share       float64      `db:"share"`
+	CompletedAt time.Time    `db:"completed_at"`
+	AcceptedAt  sql.NullTime `db:"accepted_at"`
+	AcceptedBy  int          `db:"accepted_by"`
+}
+
+func (c dbCompletion) toModel() model.TeamTaskCompletion {
+	return model.TeamTaskCompletion{
+		ID:          c.ID,
+		TeamTaskID:  c.TeamTaskID,
+		TaskName:    c.TaskName,
+		TeamID:      c.TeamID,
+		TeamName:    c.TeamName,
+		Amount:      c.Amount,
+		Share:       c.Share,
+		CompletedAt: c.CompletedAt,
+		AcceptedAt:  c.AcceptedAt.Time,
+		AcceptedBy:  c.AcceptedBy,
+	}
+}
+
+type dbLeaderboardEntry struct {
+	Rank    int     `db:"rank"`
+	TeamID  int     `db:"team_id"`
+	Name    string  `db:"name"`
+	Members int     `db:"members"`
+	Score   float64 `db:"score"`
+}
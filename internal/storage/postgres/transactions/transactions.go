@@ -3,8 +3,11 @@ package transactions
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"github.com/jmoiron/sqlx"
 	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/nullable"
 	"log/slog"
 	"time"
 )
@@ -22,14 +25,30 @@ func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
 }
 
 const (
-	TransferTypeID    = 1
-	PurchaseTypeID    = 2
-	DepositTypeID     = 3
-	RefundTypeID      = 4
-	RewardTypeID      = 5
-	PendingStatusID   = 1
-	CompletedStatusID = 2
-	CancelledStatusID = 3
+	TransferTypeID         = 1
+	PurchaseTypeID         = 2
+	DepositTypeID          = 3
+	RefundTypeID           = 4
+	RewardTypeID           = 5
+	PenaltyTypeID          = 6
+	FeeTypeID              = 7
+	PrizeTypeID            = 8
+	BonusTypeID            = 9
+	KudosTypeID            = 10
+	TeamContributionTypeID = 11
+	TeamPayoutTypeID       = 12
+	DuelStakeTypeID        = 13
+	DuelPayoutTypeID       = 14
+	BountyEscrowTypeID     = 15
+	BountyPayoutTypeID     = 16
+	TreasureTypeID         = 17
+	RaffleTicketTypeID     = 18
+	EventRewardTypeID      = 19
+	QuizRewardTypeID       = 20
+	ResourceSaleTypeID     = 21
+	PendingStatusID        = 1
+	CompletedStatusID      = 2
+	CancelledStatusID      = 3
 )
 
 func (s *Storage) AddUserTransaction(ctx context.Context, transaction *model.Transaction) error {
@@ -153,8 +172,8 @@ func (s *Storage) AddAdminTransaction(ctx context.Context, transaction *model.Tr
 	transaction.TypeID = RewardTypeID
 
 	var transactionID int64
-	err = tx.QueryRowContext(ctx, "INSERT INTO transactions (sender_id, receiver_id, amount, type_id, status_id) VALUES ($1, $2, $3, $4, $5) RETURNING id",
-		transaction.SenderID, transaction.ReceiverID, transaction.Amount, transaction.TypeID, PendingStatusID).Scan(&transactionID)
+	err = tx.QueryRowContext(ctx, "INSERT INTO transactions (sender_id, receiver_id, amount, type_id, status_id, task_id) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id",
+		transaction.SenderID, transaction.ReceiverID, transaction.Amount, transaction.TypeID, PendingStatusID, nullable.ID(transaction.TaskID)).Scan(&transactionID)
 	if err != nil {
 		log.Error("failed to insert transaction record", slog.String("error", err.Error()))
 		if err := tx.Rollback(); err != nil {
@@ -211,7 +230,7 @@ func (s *Storage) GetUserTransactions(ctx context.Context, userID int) ([]model.
 	}(conn)
 
 	var transactions []dbTransaction
-	err = conn.SelectContext(ctx, &transactions, "SELECT * FROM transactions WHERE sender_id = $1 OR receiver_id = $1", userID)
+	err = conn.SelectContext(ctx, &transactions, "SELECT id, sender_id, receiver_id, amount, type_id, status_id, COALESCE(task_id, 0) AS task_id, created_at FROM transactions WHERE sender_id = $1 OR receiver_id = $1", userID)
 	if err != nil {
 		log.Error("failed to get transactions", slog.String("error", err.Error()))
 		return nil, err
@@ -219,7 +238,16 @@ func (s *Storage) GetUserTransactions(ctx context.Context, userID int) ([]model.
 
 	var result []model.Transaction
 	for _, transaction := range transactions {
-		result = append(result, model.Transaction(transaction))
+		result = append(result, model.Transaction{
+			ID:         transaction.ID,
+			SenderID:   int(transaction.SenderID.Int64),
+			ReceiverID: int(transaction.ReceiverID.Int64),
+			Amount:     transaction.Amount,
+			TypeID:     transaction.TypeID,
+			StatusID:   transaction.StatusID,
+			TaskID:     transaction.TaskID,
+			CreatedAt:  transaction.CreatedAt,
+		})
 	}
 
 	return result, nil
@@ -229,12 +257,82 @@ func (s *Storage) Close() error {
 	return s.db.Close()
 }
 
+// Credit pays the coins to the user within tx and records the completed transaction of the type.
+// The id of the transaction is returned.
+func Credit(ctx context.Context, tx *sqlx.Tx, userID int, amount float64, typeID int) (int, error) {
+	var transactionID int
+	err := tx.QueryRowxContext(ctx,
+		`INSERT INTO transactions (sender_id, receiver_id, amount, type_id, status_id) VALUES (NULL, $1, $2, $3, $4) RETURNING id`,
+		userID, amount, typeID, CompletedStatusID,
+	).Scan(&transactionID)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE balances SET balance = balance + $1 WHERE user_id = $2`, amount, userID); err != nil {
+		return 0, err
+	}
+
+	return transactionID, nil
+}
+
+// Reward pays the reward of the task from the admin to the user within tx and records the completed transaction.
+// The id of the transaction is returned.
+func Reward(ctx context.Context, tx *sqlx.Tx, adminID, userID, taskID int, amount float64) (int, error) {
+	var transactionID int
+	err := tx.QueryRowxContext(ctx,
+		`INSERT INTO transactions (sender_id, receiver_id, amount, type_id, status_id, task_id) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		adminID, userID, amount, RewardTypeID, CompletedStatusID, taskID,
+	).Scan(&transactionID)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE balances SET balance = balance + $1 WHERE user_id = $2`, amount, userID); err != nil {
+		return 0, err
+	}
+
+	return transactionID, nil
+}
+
+// Debit takes the coins from the user within tx and records the completed transaction of the type.
+// The balance is locked until the end of tx and must cover the amount. The id of the transaction is returned.
+func Debit(ctx context.Context, tx *sqlx.Tx, userID int, amount float64, typeID int) (int, error) {
+	var balance float64
+	if err := tx.GetContext(ctx, &balance, `SELECT balance FROM balances WHERE user_id = $1 FOR UPDATE`, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errs.ErrUserNotFound
+		}
+		return 0, err
+	}
+
+	if balance < amount {
+		return 0, errs.ErrInsufficientFunds
+	}
+
+	var transactionID int
+	err := tx.QueryRowxContext(ctx,
+		`INSERT INTO transactions (sender_id, receiver_id, amount, type_id, status_id) VALUES ($1, NULL, $2, $3, $4) RETURNING id`,
+		userID, amount, typeID, CompletedStatusID,
+	).Scan(&transactionID)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE balances SET balance = balance - $1 WHERE user_id = $2`, amount, userID); err != nil {
+		return 0, err
+	}
+
+	return transactionID, nil
+}
+
 type dbTransaction struct {
-	ID         int       `db:"id"`
-	SenderID   int       `db:"sender_id"`
-	ReceiverID int       `db:"receiver_id"`
-	Amount     float64   `db:"amount"`
-	TypeID     int       `db:"type_id"`
-	StatusID   int       `db:"status_id"`
-	CreatedAt  time.Time `db:"created_at"`
+	ID         int           `db:"id"`
+	SenderID   sql.NullInt64 `db:"sender_id"`
+	ReceiverID sql.NullInt64 `db:"receiver_id"`
+	Amount     float64       `db:"amount"`
+	TypeID     int           `db:"type_id"`
+	StatusID   int           `db:"status_id"`
+	TaskID     int           `db:"task_id"`
+	CreatedAt  time.Time     `db:"created_at"`
 }
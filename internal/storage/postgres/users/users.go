@@ -49,7 +49,7 @@ func (s *Storage) Save(ctx context.Context, user *model.User) (int, error) {
 		}
 	}(conn)
 
-	query := `INSERT INTO users (username, password_hash) VALUES ($1, $2) RETURNING id`
+	query := `INSERT INTO users (username, password_hash, class_id) VALUES ($1, $2, $3) RETURNING id`
 
 	var id int
 
@@ -58,6 +58,7 @@ func (s *Storage) Save(ctx context.Context, user *model.User) (int, error) {
 		query,
 		user.Username,
 		user.PasswordHash,
+		user.ClassID,
 	).Scan(&id); err != nil {
 		var pqErr *pq.Error
 		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
@@ -65,6 +66,11 @@ func (s *Storage) Save(ctx context.Context, user *model.User) (int, error) {
 			return 0, errs.ErrUserExists
 		}
 
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			log.Error("class not found", slog.String("error", err.Error()))
+			return 0, errs.ErrClassNotFound
+		}
+
 		log.Error("failed to save user", slog.String("error", err.Error()))
 		return 0, err
 	}
@@ -90,7 +96,7 @@ func (s *Storage) GetByUsername(ctx context.Context, username string) (model.Use
 		}
 	}(conn)
 
-	query := `SELECT id, username, password_hash, registered_at, hired_at FROM users WHERE username = $1`
+	query := `SELECT id, username, password_hash, COALESCE(class_id, 0) AS class_id, registered_at, hired_at, xp, level FROM users WHERE username = $1`
 
 	var user dbUser
 
@@ -125,7 +131,7 @@ func (s *Storage) GetByID(ctx context.Context, id int) (model.User, error) {
 		}
 	}(conn)
 
-	query := `SELECT id, username, email, password_hash, registered_at, hired_at FROM users WHERE id = $1`
+	query := `SELECT id, username, COALESCE(email, '') AS email, password_hash, COALESCE(class_id, 0) AS class_id, registered_at, hired_at, xp, level FROM users WHERE id = $1`
 
 	var user dbUser
 
@@ -178,7 +184,8 @@ func (s *Storage) GetAll(ctx context.Context) ([]model.User, error) {
 	return users, nil
 }
 
-func (s *Storage) GetTopByBalance(ctx context.Context) ([]model.User, error) {
+// GetTopByBalance returns the richest users first, users with equal balance in the order they registered.
+func (s *Storage) GetTopByBalance(ctx context.Context, limit int) ([]model.User, error) {
 	op := "users.GetTopByBalance"
 
 	log := s.log.With("op", op)
@@ -196,14 +203,15 @@ func (s *Storage) GetTopByBalance(ctx context.Context) ([]model.User, error) {
 		}
 	}(conn)
 
-	query := `SELECT users.username, balances.balance 
+	query := `SELECT users.id, users.username, balances.balance
 			  FROM users
 			  JOIN balances ON balances.user_id = users.id
-			  ORDER BY balance`
+			  ORDER BY balances.balance DESC, users.id
+			  LIMIT $1`
 
 	var dbUsers []dbUser
 
-	if err := conn.SelectContext(ctx, &dbUsers, query); err != nil {
+	if err := conn.SelectContext(ctx, &dbUsers, query, limit); err != nil {
 		log.Error("failed to get users", slog.String("error", err.Error()))
 		return nil, err
 	}
@@ -317,4 +325,6 @@ type dbUser struct {
 	ClassID      int       `db:"class_id"`
 	RegisteredAt time.Time `db:"registered_at"`
 	HiredAt      time.Time `db:"hired_at"`
+	XP           int       `db:"xp"`
+	Level        int       `db:"level"`
 }
@@ -0,0 +1,232 @@
+package xp
+
+import (
+	"context"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/k6mil6/hackathon-game-backend/internal/lib/levels"
+	"github.com/k6mil6/hackathon-game-backend/internal/model"
+	errs "github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/errors"
+	"github.com/k6mil6/hackathon-game-backend/internal/storage/postgres/nullable"
+	"github.com/lib/pq"
+	"log/slog"
+	"time"
+)
+
+const (
+	TaskSourceID        = 1
+	AchievementSourceID = 2
+	QuizSourceID        = 3
+)
+
+type Storage struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewStorage(db *sqlx.DB, log *slog.Logger) *Storage {
+	return &Storage{
+		db:  db,
+		log: log,
+	}
+}
+
+// Award writes the entry to the ledger, adds it to the user's total and records every level
+// the user reached with it, all in one transaction. Levels never go down, even if the curve changes.
+func (s *Storage) Award(ctx context.Context, entry model.XPEntry, curve levels.Curve) (model.XPAward, error) {
+	op := "xp.Award"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", entry.UserID), slog.Int("taskID", entry.TaskID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return model.XPAward{}, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", slog.String("error", err.Error()))
+		return model.XPAward{}, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO xp_ledger (user_id, source_id, task_id, achievement_id, quiz_id, amount) VALUES ($1, $2, $3, $4, $5, $6)`,
+		entry.UserID, entry.SourceID, nullable.ID(entry.TaskID), nullable.ID(entry.AchievementID), nullable.ID(entry.QuizID), entry.Amount,
+	)
+	if err != nil {
+		log.Error("failed to add ledger entry", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.XPAward{}, err
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return model.XPAward{}, errs.ErrXPAlreadyAwarded
+		}
+
+		return model.XPAward{}, err
+	}
+
+	award := model.XPAward{Amount: entry.Amount}
+
+	err = tx.QueryRowxContext(ctx,
+		`UPDATE users SET xp = xp + $1 WHERE id = $2 RETURNING xp, level`,
+		entry.Amount, entry.UserID,
+	).Scan(&award.TotalXP, &award.OldLevel)
+	if err != nil {
+		log.Error("failed to update user xp", slog.String("error", err.Error()))
+		if err := tx.Rollback(); err != nil {
+			return model.XPAward{}, err
+		}
+		return model.XPAward{}, err
+	}
+
+	award.NewLevel = award.OldLevel
+	if level := curve.Level(award.TotalXP); level > award.OldLevel {
+		award.NewLevel = level
+	}
+
+	if award.NewLevel > award.OldLevel {
+		_, err = tx.ExecContext(ctx, `UPDATE users SET level = $1 WHERE id = $2`, award.NewLevel, entry.UserID)
+		if err != nil {
+			log.Error("failed to update user level", slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return model.XPAward{}, err
+			}
+			return model.XPAward{}, err
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO level_ups (user_id, level)
+			 SELECT $1, generate_series($2::INTEGER, $3::INTEGER)
+			 ON CONFLICT (user_id, level) DO NOTHING`,
+			entry.UserID, award.OldLevel+1, award.NewLevel,
+		)
+		if err != nil {
+			log.Error("failed to add level ups", slog.String("error", err.Error()))
+			if err := tx.Rollback(); err != nil {
+				return model.XPAward{}, err
+			}
+			return model.XPAward{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit transaction", slog.String("error", err.Error()))
+		return model.XPAward{}, err
+	}
+
+	log.Info("awarded xp", slog.Int("amount", entry.Amount), slog.Int("level", award.NewLevel))
+
+	return award, nil
+}
+
+// GetLedger returns XP entries of the user, newest first.
+func (s *Storage) GetLedger(ctx context.Context, userID, limit int) ([]model.XPEntry, error) {
+	op := "xp.GetLedger"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT id, user_id, source_id, COALESCE(task_id, 0) AS task_id, COALESCE(achievement_id, 0) AS achievement_id,
+			  COALESCE(quiz_id, 0) AS quiz_id, amount, created_at FROM xp_ledger
+			  WHERE user_id = $1
+			  ORDER BY created_at DESC, id DESC
+			  LIMIT $2`
+
+	var dbEntries []dbXPEntry
+	if err := conn.SelectContext(ctx, &dbEntries, query, userID, limit); err != nil {
+		log.Error("failed to get ledger", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	entries := make([]model.XPEntry, 0, len(dbEntries))
+	for _, entry := range dbEntries {
+		entries = append(entries, model.XPEntry(entry))
+	}
+
+	return entries, nil
+}
+
+// GetLevelUps returns levels the user reached, newest first.
+func (s *Storage) GetLevelUps(ctx context.Context, userID, limit int) ([]model.LevelUp, error) {
+	op := "xp.GetLevelUps"
+
+	log := s.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Error("failed to get connection", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	defer func(conn *sqlx.Conn) {
+		err := conn.Close()
+		if err != nil {
+			log.Error("failed to close connection", slog.String("error", err.Error()))
+			return
+		}
+	}(conn)
+
+	query := `SELECT id, user_id, level, created_at FROM level_ups
+			  WHERE user_id = $1
+			  ORDER BY level DESC
+			  LIMIT $2`
+
+	var dbLevelUps []dbLevelUp
+	if err := conn.SelectContext(ctx, &dbLevelUps, query, userID, limit); err != nil {
+		log.Error("failed to get level ups", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	levelUps := make([]model.LevelUp, 0, len(dbLevelUps))
+	for _, levelUp := range dbLevelUps {
+		levelUps = append(levelUps, model.LevelUp(levelUp))
+	}
+
+	return levelUps, nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+type dbXPEntry struct {
+	ID            int       `db:"id"`
+	UserID        int       `db:"user_id"`
+	SourceID      int       `db:"source_id"`
+	TaskID        int       `db:"task_id"`
+	AchievementID int       `db:"achievement_id"`
+	QuizID        int       `db:"quiz_id"`
+	Amount        int       `db:"amount"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+type dbLevelUp struct {
+	ID        int       `db:"id"`
+	UserID    int       `db:"user_id"`
+	Level     int       `db:"level"`
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS tasks_category_id_idx;
+DROP INDEX IF EXISTS tasks_status_id_idx;
+DROP INDEX IF EXISTS tasks_for_group_id_created_at_idx;
+DROP INDEX IF EXISTS tasks_user_id_created_at_idx;
+DROP INDEX IF EXISTS tasks_created_by_created_at_idx;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS category_id;
+
+DROP TABLE IF EXISTS tasks_categories;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS tasks_categories (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) UNIQUE NOT NULL
+);
+
+INSERT INTO tasks_categories (name) VALUES
+    ('general'),
+    ('work'),
+    ('education'),
+    ('sport'),
+    ('social')
+ON CONFLICT (name) DO NOTHING;
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS category_id INTEGER NOT NULL DEFAULT 1 REFERENCES tasks_categories(id);
+
+-- keyset pagination always ends with id as a tie-breaker
+CREATE INDEX IF NOT EXISTS tasks_created_by_created_at_idx ON tasks (created_by, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS tasks_user_id_created_at_idx ON tasks (user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS tasks_for_group_id_created_at_idx ON tasks (for_group_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS tasks_status_id_idx ON tasks (status_id);
+CREATE INDEX IF NOT EXISTS tasks_category_id_idx ON tasks (category_id);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS users_search_vector_idx;
+ALTER TABLE users DROP COLUMN IF EXISTS search_vector;
+
+DROP INDEX IF EXISTS shop_items_search_vector_idx;
+ALTER TABLE shop_items DROP COLUMN IF EXISTS search_vector;
+
+DROP INDEX IF EXISTS tasks_search_vector_idx;
+ALTER TABLE tasks DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE tasks DROP COLUMN IF EXISTS description;
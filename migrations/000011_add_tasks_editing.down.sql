@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS notifications_types;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS cancelled_by;
+ALTER TABLE tasks DROP COLUMN IF EXISTS cancel_reason;
+ALTER TABLE tasks DROP COLUMN IF EXISTS updated_at;
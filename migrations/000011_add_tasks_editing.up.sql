@@ -0,0 +1,27 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP NOT NULL DEFAULT NOW();
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS cancel_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS cancelled_by INTEGER REFERENCES admins(id);
+
+CREATE TABLE IF NOT EXISTS notifications_types (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) UNIQUE NOT NULL
+);
+
+INSERT INTO notifications_types (name) VALUES
+    ('task cancelled'),
+    ('task assigned'),
+    ('task unassigned'),
+    ('task updated')
+ON CONFLICT (name) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    type_id INTEGER NOT NULL REFERENCES notifications_types(id),
+    message TEXT NOT NULL,
+    task_id BIGINT REFERENCES tasks(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    read_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS notifications_user_id_created_at_idx ON notifications (user_id, created_at DESC, id DESC);
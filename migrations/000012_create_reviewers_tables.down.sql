@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS reviews_delegations;
+DROP TABLE IF EXISTS groups_reviewers;
+DROP TABLE IF EXISTS tasks_reviewers;
+
+DROP INDEX IF EXISTS tasks_status_id_submitted_at_idx;
+ALTER TABLE tasks DROP COLUMN IF EXISTS submitted_at;
@@ -0,0 +1,33 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS submitted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS tasks_status_id_submitted_at_idx ON tasks (status_id, submitted_at);
+
+CREATE TABLE IF NOT EXISTS tasks_reviewers (
+    task_id BIGINT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    admin_id INTEGER NOT NULL REFERENCES admins(id),
+    assigned_by INTEGER NOT NULL REFERENCES admins(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, admin_id)
+);
+
+CREATE TABLE IF NOT EXISTS groups_reviewers (
+    group_id INTEGER NOT NULL REFERENCES groups(id),
+    admin_id INTEGER NOT NULL REFERENCES admins(id),
+    assigned_by INTEGER NOT NULL REFERENCES admins(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (group_id, admin_id)
+);
+
+CREATE TABLE IF NOT EXISTS reviews_delegations (
+    id SERIAL PRIMARY KEY,
+    from_admin_id INTEGER NOT NULL REFERENCES admins(id),
+    to_admin_id INTEGER NOT NULL REFERENCES admins(id),
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (from_admin_id <> to_admin_id),
+    CHECK (starts_at < ends_at)
+);
+
+CREATE INDEX IF NOT EXISTS reviews_delegations_to_admin_id_idx ON reviews_delegations (to_admin_id, starts_at, ends_at);
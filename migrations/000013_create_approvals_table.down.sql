@@ -0,0 +1,9 @@
+DELETE FROM notifications WHERE type_id IN (SELECT id FROM notifications_types WHERE name = 'task acceptance denied');
+DELETE FROM notifications_types WHERE name = 'task acceptance denied';
+
+DROP TABLE IF EXISTS tasks_approvals;
+DROP TABLE IF EXISTS approvals_statuses;
+DROP TABLE IF EXISTS approvals_actions;
+
+UPDATE tasks SET status_id = 1 WHERE status_id IN (SELECT id FROM tasks_statuses WHERE name IN ('waiting for creation approval', 'waiting for acceptance approval'));
+DELETE FROM tasks_statuses WHERE name IN ('waiting for creation approval', 'waiting for acceptance approval');
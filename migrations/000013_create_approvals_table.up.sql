@@ -0,0 +1,50 @@
+INSERT INTO tasks_statuses (name) VALUES
+    ('waiting for creation approval'),
+    ('waiting for acceptance approval')
+ON CONFLICT (name) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS approvals_actions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) UNIQUE NOT NULL
+);
+
+INSERT INTO approvals_actions (name) VALUES
+    ('creation'),
+    ('acceptance')
+ON CONFLICT (name) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS approvals_statuses (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) UNIQUE NOT NULL
+);
+
+INSERT INTO approvals_statuses (name) VALUES
+    ('pending'),
+    ('approved'),
+    ('denied'),
+    ('cancelled')
+ON CONFLICT (name) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS tasks_approvals (
+    id BIGSERIAL PRIMARY KEY,
+    task_id BIGINT NOT NULL REFERENCES tasks(id),
+    action_id INTEGER NOT NULL REFERENCES approvals_actions(id),
+    status_id INTEGER NOT NULL REFERENCES approvals_statuses(id),
+    amount DECIMAL(10, 2) NOT NULL,
+    requested_by INTEGER NOT NULL REFERENCES admins(id),
+    decided_by INTEGER REFERENCES admins(id),
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    decided_at TIMESTAMP,
+    CHECK (decided_by IS NULL OR decided_by <> requested_by)
+);
+
+CREATE INDEX IF NOT EXISTS tasks_approvals_task_id_idx ON tasks_approvals (task_id);
+CREATE INDEX IF NOT EXISTS tasks_approvals_status_id_created_at_idx ON tasks_approvals (status_id, created_at DESC, id DESC);
+
+-- only one request per task may wait for a decision at a time
+CREATE UNIQUE INDEX IF NOT EXISTS tasks_approvals_pending_task_id_idx ON tasks_approvals (task_id) WHERE status_id = 1;
+
+INSERT INTO notifications_types (name) VALUES
+    ('task acceptance denied')
+ON CONFLICT (name) DO NOTHING;
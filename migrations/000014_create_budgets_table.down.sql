@@ -0,0 +1,8 @@
+ALTER TABLE tasks DROP COLUMN IF EXISTS budget_id;
+
+DROP TABLE IF EXISTS budgets;
+DROP TABLE IF EXISTS budgets_periods;
+
+UPDATE admins SET role_id = (SELECT id FROM roles WHERE name = 'admin')
+WHERE role_id = (SELECT id FROM roles WHERE name = 'super admin');
+DELETE FROM roles WHERE name = 'super admin';
@@ -0,0 +1,40 @@
+INSERT INTO roles (name) VALUES
+    ('super admin')
+ON CONFLICT (name) DO NOTHING;
+
+-- the seeded admin has to be able to allocate budgets to everybody else
+UPDATE admins SET role_id = (SELECT id FROM roles WHERE name = 'super admin') WHERE username = 'admin';
+
+CREATE TABLE IF NOT EXISTS budgets_periods (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) UNIQUE NOT NULL
+);
+
+INSERT INTO budgets_periods (name) VALUES
+    ('month'),
+    ('quarter')
+ON CONFLICT (name) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS budgets (
+    id BIGSERIAL PRIMARY KEY,
+    admin_id INTEGER REFERENCES admins(id),
+    group_id INTEGER REFERENCES groups(id),
+    period_id INTEGER NOT NULL REFERENCES budgets_periods(id),
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    reserved DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    spent DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    allocated_by INTEGER NOT NULL REFERENCES admins(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK ((admin_id IS NULL) <> (group_id IS NULL)),
+    CHECK (ends_at > starts_at),
+    CONSTRAINT budgets_usage_check CHECK (reserved >= 0 AND spent >= 0 AND reserved + spent <= amount),
+    UNIQUE (admin_id, period_id, starts_at),
+    UNIQUE (group_id, period_id, starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS budgets_starts_at_ends_at_idx ON budgets (starts_at, ends_at);
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS budget_id BIGINT REFERENCES budgets(id);
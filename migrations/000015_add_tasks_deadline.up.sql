@@ -0,0 +1 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS deadline TIMESTAMP;
@@ -0,0 +1,7 @@
+DELETE FROM notifications WHERE type_id IN (SELECT id FROM notifications_types WHERE name IN ('task intercepted', 'task intercept failed'));
+DELETE FROM notifications_types WHERE name IN ('task intercepted', 'task intercept failed');
+
+DROP TABLE IF EXISTS tasks_intercepts;
+
+DELETE FROM transactions WHERE type_id IN (SELECT id FROM transaction_types WHERE name = 'penalty');
+DELETE FROM transaction_types WHERE name = 'penalty';
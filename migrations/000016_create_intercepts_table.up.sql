@@ -0,0 +1,26 @@
+INSERT INTO transaction_types (name) VALUES
+    ('penalty')
+ON CONFLICT (name) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS tasks_intercepts (
+    id BIGSERIAL PRIMARY KEY,
+    task_id BIGINT NOT NULL REFERENCES tasks(id),
+    thief_id INTEGER NOT NULL REFERENCES users(id),
+    victim_id INTEGER NOT NULL REFERENCES users(id),
+    seed BIGINT NOT NULL,
+    roll DOUBLE PRECISION NOT NULL,
+    chance DOUBLE PRECISION NOT NULL,
+    success BOOLEAN NOT NULL,
+    penalty DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (thief_id <> victim_id)
+);
+
+CREATE INDEX IF NOT EXISTS tasks_intercepts_thief_id_created_at_idx ON tasks_intercepts (thief_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS tasks_intercepts_victim_id_created_at_idx ON tasks_intercepts (victim_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS tasks_intercepts_task_id_idx ON tasks_intercepts (task_id);
+
+INSERT INTO notifications_types (name) VALUES
+    ('task intercepted'),
+    ('task intercept failed')
+ON CONFLICT (name) DO NOTHING;
@@ -0,0 +1,9 @@
+DELETE FROM notifications WHERE type_id IN (SELECT id FROM notifications_types WHERE name = 'intel purchased');
+DELETE FROM notifications_types WHERE name = 'intel purchased';
+
+DROP TABLE IF EXISTS intel_purchases;
+
+DELETE FROM transactions WHERE receiver_id IS NULL;
+ALTER TABLE transactions ALTER COLUMN receiver_id SET NOT NULL;
+
+DELETE FROM transaction_types WHERE name = 'fee';
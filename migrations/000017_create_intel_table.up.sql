@@ -0,0 +1,23 @@
+INSERT INTO transaction_types (name) VALUES
+    ('fee')
+ON CONFLICT (name) DO NOTHING;
+
+-- fees are paid to the game, so they have no receiver
+ALTER TABLE transactions ALTER COLUMN receiver_id DROP NOT NULL;
+
+CREATE TABLE IF NOT EXISTS intel_purchases (
+    id BIGSERIAL PRIMARY KEY,
+    buyer_id INTEGER NOT NULL REFERENCES users(id),
+    target_id INTEGER NOT NULL REFERENCES users(id),
+    transaction_id INTEGER NOT NULL REFERENCES transactions(id),
+    fee DECIMAL(10, 2) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (buyer_id <> target_id)
+);
+
+CREATE INDEX IF NOT EXISTS intel_purchases_buyer_id_expires_at_idx ON intel_purchases (buyer_id, expires_at DESC);
+
+INSERT INTO notifications_types (name) VALUES
+    ('intel purchased')
+ON CONFLICT (name) DO NOTHING;
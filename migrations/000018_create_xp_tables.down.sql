@@ -0,0 +1,14 @@
+DELETE FROM notifications WHERE type_id IN (SELECT id FROM notifications_types WHERE name = 'level up');
+DELETE FROM notifications_types WHERE name = 'level up';
+
+DROP TABLE IF EXISTS level_ups;
+DROP TABLE IF EXISTS xp_ledger;
+DROP TABLE IF EXISTS xp_sources;
+
+ALTER TABLE shop_items DROP COLUMN IF EXISTS min_level;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS min_level;
+ALTER TABLE tasks DROP COLUMN IF EXISTS xp;
+
+ALTER TABLE users DROP COLUMN IF EXISTS level;
+ALTER TABLE users DROP COLUMN IF EXISTS xp;
@@ -0,0 +1,43 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS xp INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS level INTEGER NOT NULL DEFAULT 1;
+
+-- NULL xp means the task gives XP derived from its reward
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS xp INTEGER CHECK (xp >= 0);
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS min_level INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE shop_items ADD COLUMN IF NOT EXISTS min_level INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS xp_sources (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) UNIQUE NOT NULL
+);
+
+INSERT INTO xp_sources (name) VALUES
+    ('task')
+ON CONFLICT (name) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS xp_ledger (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    source_id INTEGER NOT NULL REFERENCES xp_sources(id),
+    task_id BIGINT REFERENCES tasks(id),
+    amount INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS xp_ledger_user_id_created_at_idx ON xp_ledger (user_id, created_at DESC);
+
+-- a task gives XP only once, whichever path accepted it
+CREATE UNIQUE INDEX IF NOT EXISTS xp_ledger_task_id_idx ON xp_ledger (task_id) WHERE source_id = 1;
+
+CREATE TABLE IF NOT EXISTS level_ups (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    level INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, level)
+);
+
+INSERT INTO notifications_types (name) VALUES
+    ('level up')
+ON CONFLICT (name) DO NOTHING;
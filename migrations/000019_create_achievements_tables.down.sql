@@ -0,0 +1,13 @@
+DELETE FROM notifications WHERE type_id IN (SELECT id FROM notifications_types WHERE name = 'achievement unlocked');
+DELETE FROM notifications_types WHERE name = 'achievement unlocked';
+
+DROP INDEX IF EXISTS xp_ledger_user_id_achievement_id_idx;
+DELETE FROM xp_ledger WHERE source_id IN (SELECT id FROM xp_sources WHERE name = 'achievement');
+ALTER TABLE xp_ledger DROP COLUMN IF EXISTS achievement_id;
+DELETE FROM xp_sources WHERE name = 'achievement';
+
+DROP TABLE IF EXISTS users_achievements;
+DROP TABLE IF EXISTS achievements;
+
+DELETE FROM transactions WHERE sender_id IS NULL;
+ALTER TABLE transactions ALTER COLUMN sender_id SET NOT NULL;
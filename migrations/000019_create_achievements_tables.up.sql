@@ -0,0 +1,50 @@
+-- rewards paid by the game have no sender
+ALTER TABLE transactions ALTER COLUMN sender_id DROP NOT NULL;
+
+-- an achievement is awarded once the metric of the user reaches the threshold,
+-- for rank metrics once the rank is at or above it. period 'month' counts the current calendar month only
+CREATE TABLE IF NOT EXISTS achievements (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(255) UNIQUE NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    metric VARCHAR(255) NOT NULL,
+    threshold INTEGER NOT NULL CHECK (threshold > 0),
+    period VARCHAR(16) NOT NULL DEFAULT 'all' CHECK (period IN ('all', 'month')),
+    business_type_id INTEGER REFERENCES businesses_types(id),
+    reward_coins DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (reward_coins >= 0),
+    reward_xp INTEGER NOT NULL DEFAULT 0 CHECK (reward_xp >= 0),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO achievements (code, name, description, metric, threshold, period, business_type_id, reward_coins, reward_xp) VALUES
+    ('first_task', 'First task', 'Get your first task accepted', 'tasks_completed', 1, 'all', NULL, 10.00, 10),
+    ('ten_tasks_month', 'Busy month', 'Get 10 tasks accepted in one month', 'tasks_completed', 10, 'month', NULL, 50.00, 50),
+    ('first_purchase', 'First purchase', 'Buy something in the shop', 'purchases', 1, 'all', NULL, 0, 10),
+    ('first_transfer', 'Generous', 'Send coins to another user', 'transfers', 1, 'all', NULL, 0, 10),
+    ('farm_owner', 'Farmer', 'Own a farm', 'businesses_owned', 1, 'all', 1, 0, 25),
+    ('top_three', 'Top 3', 'Reach the top 3 of the leaderboard', 'balance_rank', 3, 'all', NULL, 100.00, 100)
+ON CONFLICT (code) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS users_achievements (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    achievement_id INTEGER NOT NULL REFERENCES achievements(id),
+    transaction_id INTEGER REFERENCES transactions(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, achievement_id)
+);
+
+INSERT INTO xp_sources (name) VALUES
+    ('achievement')
+ON CONFLICT (name) DO NOTHING;
+
+ALTER TABLE xp_ledger ADD COLUMN IF NOT EXISTS achievement_id INTEGER REFERENCES achievements(id);
+
+-- an achievement gives XP only once per user
+CREATE UNIQUE INDEX IF NOT EXISTS xp_ledger_user_id_achievement_id_idx ON xp_ledger (user_id, achievement_id) WHERE source_id = 2;
+
+INSERT INTO notifications_types (name) VALUES
+    ('achievement unlocked')
+ON CONFLICT (name) DO NOTHING;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS balances_balance_idx;
+DROP INDEX IF EXISTS tasks_user_id_status_id_idx;
+DROP INDEX IF EXISTS transactions_receiver_id_type_id_created_at_idx;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS task_id;
@@ -0,0 +1,6 @@
+-- rewards for tasks are linked to the task, so earnings can be counted per group
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS task_id BIGINT REFERENCES tasks(id);
+
+CREATE INDEX IF NOT EXISTS transactions_receiver_id_type_id_created_at_idx ON transactions (receiver_id, type_id, created_at);
+CREATE INDEX IF NOT EXISTS tasks_user_id_status_id_idx ON tasks (user_id, status_id);
+CREATE INDEX IF NOT EXISTS balances_balance_idx ON balances (balance DESC, user_id);
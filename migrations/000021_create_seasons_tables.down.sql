@@ -0,0 +1,8 @@
+DELETE FROM notifications WHERE type_id IN (SELECT id FROM notifications_types WHERE name = 'season prize');
+DELETE FROM notifications_types WHERE name = 'season prize';
+
+DROP TABLE IF EXISTS seasons_standings;
+DROP TABLE IF EXISTS seasons;
+
+DELETE FROM transactions WHERE type_id IN (SELECT id FROM transaction_types WHERE name = 'prize');
+DELETE FROM transaction_types WHERE name = 'prize';
@@ -0,0 +1,34 @@
+INSERT INTO transaction_types (name) VALUES
+    ('prize')
+ON CONFLICT (name) DO NOTHING;
+
+-- seasons never overlap, so at most one season is running at any time
+CREATE TABLE IF NOT EXISTS seasons (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    created_by INTEGER NOT NULL REFERENCES admins(id),
+    finalized_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (ends_at > starts_at),
+    EXCLUDE USING gist (tsrange(starts_at, ends_at) WITH &&)
+);
+
+-- final standings are copied when the season is rolled over and do not change afterwards
+CREATE TABLE IF NOT EXISTS seasons_standings (
+    season_id INTEGER NOT NULL REFERENCES seasons(id),
+    rank INTEGER NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    username TEXT NOT NULL,
+    class_id INTEGER NOT NULL DEFAULT 0,
+    score DECIMAL(12, 2) NOT NULL,
+    prize DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    transaction_id INTEGER REFERENCES transactions(id),
+    PRIMARY KEY (season_id, user_id),
+    UNIQUE (season_id, rank)
+);
+
+INSERT INTO notifications_types (name) VALUES
+    ('season prize')
+ON CONFLICT (name) DO NOTHING;
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS check_ins;
+DROP TABLE IF EXISTS users_streaks;
+
+DELETE FROM purchases WHERE item_id IN (SELECT id FROM shop_items WHERE streak_freezes > 0);
+DELETE FROM shop_items WHERE streak_freezes > 0;
+ALTER TABLE shop_items DROP COLUMN IF EXISTS streak_freezes;
+
+DELETE FROM transactions WHERE type_id IN (SELECT id FROM transaction_types WHERE name = 'bonus');
+DELETE FROM transaction_types WHERE name = 'bonus';
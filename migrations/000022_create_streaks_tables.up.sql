@@ -0,0 +1,31 @@
+INSERT INTO transaction_types (name) VALUES
+    ('bonus')
+ON CONFLICT (name) DO NOTHING;
+
+-- a purchased item with streak_freezes > 0 adds that many freezes to the buyer's streak
+ALTER TABLE shop_items ADD COLUMN IF NOT EXISTS streak_freezes INTEGER NOT NULL DEFAULT 0;
+
+INSERT INTO shop_items (name, description, price, in_stock, streak_freezes) VALUES
+    ('Streak freeze', 'Keeps your streak alive for one day without activity', 150, 1000, 1),
+    ('Streak freeze pack', 'Keeps your streak alive for three days without activity', 400, 300, 3);
+
+-- days are dates in the company time zone
+CREATE TABLE IF NOT EXISTS users_streaks (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id),
+    current INTEGER NOT NULL DEFAULT 0,
+    longest INTEGER NOT NULL DEFAULT 0,
+    last_active_on DATE,
+    freezes INTEGER NOT NULL DEFAULT 0 CHECK (freezes >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS check_ins (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    day DATE NOT NULL,
+    streak INTEGER NOT NULL DEFAULT 0,
+    bonus DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    transaction_id INTEGER REFERENCES transactions(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, day)
+);
@@ -0,0 +1,7 @@
+DELETE FROM notifications WHERE type_id IN (SELECT id FROM notifications_types WHERE name = 'kudos received');
+DELETE FROM notifications_types WHERE name = 'kudos received';
+
+DROP TABLE IF EXISTS kudos;
+
+DELETE FROM transactions WHERE type_id IN (SELECT id FROM transaction_types WHERE name = 'kudos');
+DELETE FROM transaction_types WHERE name = 'kudos';
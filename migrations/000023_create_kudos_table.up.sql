@@ -0,0 +1,26 @@
+INSERT INTO transaction_types (name) VALUES
+    ('kudos')
+ON CONFLICT (name) DO NOTHING;
+
+-- the allowance is not stored: it is the monthly amount minus kudos given in the period,
+-- so whatever is left at the end of the month expires with it
+CREATE TABLE IF NOT EXISTS kudos (
+    id SERIAL PRIMARY KEY,
+    sender_id INTEGER NOT NULL REFERENCES users(id),
+    receiver_id INTEGER NOT NULL REFERENCES users(id),
+    amount INTEGER NOT NULL CHECK (amount > 0),
+    coins DECIMAL(10, 2) NOT NULL,
+    message TEXT NOT NULL,
+    period DATE NOT NULL,
+    transaction_id INTEGER REFERENCES transactions(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (sender_id <> receiver_id)
+);
+
+CREATE INDEX IF NOT EXISTS kudos_sender_id_period_idx ON kudos (sender_id, period);
+CREATE INDEX IF NOT EXISTS kudos_receiver_id_created_at_idx ON kudos (receiver_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS kudos_created_at_idx ON kudos (created_at DESC);
+
+INSERT INTO notifications_types (name) VALUES
+    ('kudos received')
+ON CONFLICT (name) DO NOTHING;
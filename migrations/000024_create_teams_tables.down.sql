@@ -0,0 +1,15 @@
+DELETE FROM notifications WHERE type_id IN (SELECT id FROM notifications_types WHERE name IN ('team invitation', 'team task reward'));
+DELETE FROM notifications_types WHERE name IN ('team invitation', 'team task reward');
+
+DROP TABLE IF EXISTS teams_tasks_completions;
+DROP TABLE IF EXISTS teams_tasks_submissions;
+DROP TABLE IF EXISTS teams_tasks;
+DROP TABLE IF EXISTS teams_wallet_entries;
+DROP TABLE IF EXISTS teams_invitations;
+DROP TABLE IF EXISTS teams_invitations_statuses;
+DROP TABLE IF EXISTS teams_members;
+DROP TABLE IF EXISTS teams;
+DROP TABLE IF EXISTS teams_roles;
+
+DELETE FROM transactions WHERE type_id IN (SELECT id FROM transaction_types WHERE name IN ('team contribution', 'team payout'));
+DELETE FROM transaction_types WHERE name IN ('team contribution', 'team payout');
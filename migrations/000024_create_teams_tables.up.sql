@@ -0,0 +1,108 @@
+INSERT INTO transaction_types (name) VALUES
+    ('team contribution'),
+    ('team payout')
+ON CONFLICT (name) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS teams_roles (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) UNIQUE NOT NULL
+);
+
+INSERT INTO teams_roles (name) VALUES
+    ('leader'),
+    ('member')
+ON CONFLICT (name) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS teams (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) UNIQUE NOT NULL,
+    wallet DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (wallet >= 0),
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- a user belongs to one team at most and every team has exactly one leader
+CREATE TABLE IF NOT EXISTS teams_members (
+    team_id INTEGER NOT NULL REFERENCES teams(id),
+    user_id INTEGER UNIQUE NOT NULL REFERENCES users(id),
+    role_id INTEGER NOT NULL REFERENCES teams_roles(id),
+    joined_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (team_id, user_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS teams_members_leader_idx ON teams_members (team_id) WHERE role_id = 1;
+
+CREATE TABLE IF NOT EXISTS teams_invitations_statuses (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) UNIQUE NOT NULL
+);
+
+INSERT INTO teams_invitations_statuses (name) VALUES
+    ('pending'),
+    ('accepted'),
+    ('declined')
+ON CONFLICT (name) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS teams_invitations (
+    id SERIAL PRIMARY KEY,
+    team_id INTEGER NOT NULL REFERENCES teams(id),
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    invited_by INTEGER NOT NULL REFERENCES users(id),
+    status_id INTEGER NOT NULL DEFAULT 1 REFERENCES teams_invitations_statuses(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    responded_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS teams_invitations_pending_idx ON teams_invitations (team_id, user_id) WHERE status_id = 1;
+CREATE INDEX IF NOT EXISTS teams_invitations_user_id_idx ON teams_invitations (user_id, status_id);
+
+-- the ledger of the team wallet: contributions are positive, payouts to members negative
+CREATE TABLE IF NOT EXISTS teams_wallet_entries (
+    id SERIAL PRIMARY KEY,
+    team_id INTEGER NOT NULL REFERENCES teams(id),
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount <> 0),
+    transaction_id INTEGER NOT NULL REFERENCES transactions(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS teams_wallet_entries_team_id_created_at_idx ON teams_wallet_entries (team_id, created_at DESC);
+
+-- a team task without team_id is open to every team, each team completes it once
+CREATE TABLE IF NOT EXISTS teams_tasks (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount > 0),
+    required_members INTEGER NOT NULL CHECK (required_members > 0),
+    team_id INTEGER REFERENCES teams(id),
+    created_by INTEGER NOT NULL REFERENCES admins(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS teams_tasks_submissions (
+    team_task_id INTEGER NOT NULL REFERENCES teams_tasks(id),
+    team_id INTEGER NOT NULL REFERENCES teams(id),
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    submitted_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (team_task_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS teams_tasks_submissions_team_id_idx ON teams_tasks_submissions (team_task_id, team_id);
+
+-- a completion is added once enough members have submitted, the reward is split when an admin accepts it
+CREATE TABLE IF NOT EXISTS teams_tasks_completions (
+    id SERIAL PRIMARY KEY,
+    team_task_id INTEGER NOT NULL REFERENCES teams_tasks(id),
+    team_id INTEGER NOT NULL REFERENCES teams(id),
+    share DECIMAL(10, 2),
+    completed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    accepted_at TIMESTAMP,
+    accepted_by INTEGER REFERENCES admins(id),
+    UNIQUE (team_task_id, team_id)
+);
+
+INSERT INTO notifications_types (name) VALUES
+    ('team invitation'),
+    ('team task reward')
+ON CONFLICT (name) DO NOTHING;
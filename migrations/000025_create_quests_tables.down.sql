@@ -0,0 +1,8 @@
+DELETE FROM notifications WHERE type_id IN (SELECT id FROM notifications_types WHERE name = 'quest completed');
+DELETE FROM notifications_types WHERE name = 'quest completed';
+
+DROP TABLE IF EXISTS users_quests_steps;
+DROP TABLE IF EXISTS users_quests;
+DROP TABLE IF EXISTS quests_steps_prerequisites;
+DROP TABLE IF EXISTS quests_steps;
+DROP TABLE IF EXISTS quests;
@@ -0,0 +1,55 @@
+CREATE TABLE IF NOT EXISTS quests (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    bonus DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (bonus >= 0),
+    created_by INTEGER NOT NULL REFERENCES admins(id),
+    published_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- steps are identified by their position in the quest, so prerequisites can be authored before ids exist
+CREATE TABLE IF NOT EXISTS quests_steps (
+    quest_id INTEGER NOT NULL REFERENCES quests(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL CHECK (position > 0),
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount >= 0),
+    PRIMARY KEY (quest_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS quests_steps_prerequisites (
+    quest_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    requires_position INTEGER NOT NULL,
+    PRIMARY KEY (quest_id, position, requires_position),
+    FOREIGN KEY (quest_id, position) REFERENCES quests_steps(quest_id, position) ON DELETE CASCADE,
+    FOREIGN KEY (quest_id, requires_position) REFERENCES quests_steps(quest_id, position) ON DELETE CASCADE,
+    CHECK (position <> requires_position)
+);
+
+CREATE TABLE IF NOT EXISTS users_quests (
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    quest_id INTEGER NOT NULL REFERENCES quests(id),
+    started_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP,
+    bonus_transaction_id INTEGER REFERENCES transactions(id),
+    PRIMARY KEY (user_id, quest_id)
+);
+
+-- a step of a user is unlocked by assigning its task, it is completed when the task is accepted
+CREATE TABLE IF NOT EXISTS users_quests_steps (
+    user_id INTEGER NOT NULL,
+    quest_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    task_id INTEGER UNIQUE NOT NULL REFERENCES tasks(id),
+    unlocked_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP,
+    PRIMARY KEY (user_id, quest_id, position),
+    FOREIGN KEY (user_id, quest_id) REFERENCES users_quests(user_id, quest_id),
+    FOREIGN KEY (quest_id, position) REFERENCES quests_steps(quest_id, position)
+);
+
+INSERT INTO notifications_types (name) VALUES
+    ('quest completed')
+ON CONFLICT (name) DO NOTHING;
@@ -0,0 +1,9 @@
+DELETE FROM notifications WHERE type_id IN (SELECT id FROM notifications_types WHERE name IN ('duel challenge', 'duel resolved', 'duel refunded'));
+DELETE FROM notifications_types WHERE name IN ('duel challenge', 'duel resolved', 'duel refunded');
+
+DROP TABLE IF EXISTS duels;
+DROP TABLE IF EXISTS duels_statuses;
+DROP TABLE IF EXISTS duels_kinds;
+
+DELETE FROM transactions WHERE type_id IN (SELECT id FROM transaction_types WHERE name IN ('duel stake', 'duel payout'));
+DELETE FROM transaction_types WHERE name IN ('duel stake', 'duel payout');
@@ -0,0 +1,63 @@
+INSERT INTO transaction_types (name) VALUES
+    ('duel stake'),
+    ('duel payout')
+ON CONFLICT (name) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS duels_kinds (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) UNIQUE NOT NULL
+);
+
+-- a race is won by the participant whose submission of the task is accepted,
+-- a wager is decided by an admin
+INSERT INTO duels_kinds (name) VALUES
+    ('race'),
+    ('wager')
+ON CONFLICT (name) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS duels_statuses (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) UNIQUE NOT NULL
+);
+
+INSERT INTO duels_statuses (name) VALUES
+    ('pending'),
+    ('active'),
+    ('declined'),
+    ('resolved'),
+    ('refunded')
+ON CONFLICT (name) DO NOTHING;
+
+-- both stakes are held by the duel until it is resolved or refunded
+CREATE TABLE IF NOT EXISTS duels (
+    id SERIAL PRIMARY KEY,
+    kind_id INTEGER NOT NULL REFERENCES duels_kinds(id),
+    challenger_id INTEGER NOT NULL REFERENCES users(id),
+    opponent_id INTEGER NOT NULL REFERENCES users(id),
+    stake DECIMAL(10, 2) NOT NULL CHECK (stake > 0),
+    task_id INTEGER REFERENCES tasks(id),
+    terms TEXT NOT NULL DEFAULT '',
+    status_id INTEGER NOT NULL DEFAULT 1 REFERENCES duels_statuses(id),
+    winner_id INTEGER REFERENCES users(id),
+    resolved_by INTEGER REFERENCES admins(id),
+    respond_by TIMESTAMP NOT NULL,
+    deadline TIMESTAMP,
+    challenger_transaction_id INTEGER NOT NULL REFERENCES transactions(id),
+    opponent_transaction_id INTEGER REFERENCES transactions(id),
+    payout_transaction_id INTEGER REFERENCES transactions(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    accepted_at TIMESTAMP,
+    resolved_at TIMESTAMP,
+    CHECK (challenger_id <> opponent_id),
+    CHECK (kind_id <> 1 OR task_id IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS duels_challenger_idx ON duels (challenger_id);
+CREATE INDEX IF NOT EXISTS duels_opponent_idx ON duels (opponent_id);
+CREATE INDEX IF NOT EXISTS duels_task_idx ON duels (task_id) WHERE task_id IS NOT NULL;
+
+INSERT INTO notifications_types (name) VALUES
+    ('duel challenge'),
+    ('duel resolved'),
+    ('duel refunded')
+ON CONFLICT (name) DO NOTHING;
@@ -0,0 +1,9 @@
+DELETE FROM notifications WHERE type_id IN (SELECT id FROM notifications_types WHERE name = 'bounty update');
+DELETE FROM notifications_types WHERE name = 'bounty update';
+
+DROP TABLE IF EXISTS bounties_disputes;
+DROP TABLE IF EXISTS bounties;
+DROP TABLE IF EXISTS bounties_statuses;
+
+DELETE FROM transactions WHERE type_id IN (SELECT id FROM transaction_types WHERE name IN ('bounty escrow', 'bounty payout'));
+DELETE FROM transaction_types WHERE name IN ('bounty escrow', 'bounty payout');
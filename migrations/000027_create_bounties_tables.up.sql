@@ -0,0 +1,61 @@
+INSERT INTO transaction_types (name) VALUES
+    ('bounty escrow'),
+    ('bounty payout')
+ON CONFLICT (name) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS bounties_statuses (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) UNIQUE NOT NULL
+);
+
+INSERT INTO bounties_statuses (name) VALUES
+    ('open'),
+    ('claimed'),
+    ('submitted'),
+    ('completed'),
+    ('cancelled'),
+    ('disputed')
+ON CONFLICT (name) DO NOTHING;
+
+-- the reward is held by the bounty from posting until it is paid to the hunter or refunded to the poster
+CREATE TABLE IF NOT EXISTS bounties (
+    id SERIAL PRIMARY KEY,
+    poster_id INTEGER NOT NULL REFERENCES users(id),
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    reward DECIMAL(10, 2) NOT NULL CHECK (reward > 0),
+    status_id INTEGER NOT NULL DEFAULT 1 REFERENCES bounties_statuses(id),
+    hunter_id INTEGER REFERENCES users(id),
+    submission TEXT NOT NULL DEFAULT '',
+    reject_reason TEXT NOT NULL DEFAULT '',
+    escrow_transaction_id INTEGER NOT NULL REFERENCES transactions(id),
+    settlement_transaction_id INTEGER REFERENCES transactions(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    claimed_at TIMESTAMP,
+    submitted_at TIMESTAMP,
+    closed_at TIMESTAMP,
+    CHECK (hunter_id IS NULL OR hunter_id <> poster_id)
+);
+
+CREATE INDEX IF NOT EXISTS bounties_status_idx ON bounties (status_id);
+CREATE INDEX IF NOT EXISTS bounties_poster_idx ON bounties (poster_id);
+CREATE INDEX IF NOT EXISTS bounties_hunter_idx ON bounties (hunter_id) WHERE hunter_id IS NOT NULL;
+
+-- a disputed bounty is decided by an admin, who either pays the hunter or refunds the poster
+CREATE TABLE IF NOT EXISTS bounties_disputes (
+    id SERIAL PRIMARY KEY,
+    bounty_id INTEGER NOT NULL REFERENCES bounties(id),
+    raised_by INTEGER NOT NULL REFERENCES users(id),
+    reason TEXT NOT NULL,
+    paid BOOLEAN,
+    note TEXT NOT NULL DEFAULT '',
+    resolved_by INTEGER REFERENCES admins(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS bounties_disputes_open_idx ON bounties_disputes (bounty_id) WHERE resolved_at IS NULL;
+
+INSERT INTO notifications_types (name) VALUES
+    ('bounty update')
+ON CONFLICT (name) DO NOTHING;
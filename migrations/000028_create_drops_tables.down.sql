@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS drops_claims;
+DROP TABLE IF EXISTS drops;
+DROP TABLE IF EXISTS drops_campaigns;
+
+DELETE FROM transactions WHERE type_id IN (SELECT id FROM transaction_types WHERE name = 'treasure');
+DELETE FROM transaction_types WHERE name = 'treasure';
@@ -0,0 +1,45 @@
+INSERT INTO transaction_types (name) VALUES
+    ('treasure')
+ON CONFLICT (name) DO NOTHING;
+
+-- a campaign spawns drops at random at about rate drops per hour until its budget is used up
+CREATE TABLE IF NOT EXISTS drops_campaigns (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    budget DECIMAL(10, 2) NOT NULL CHECK (budget > 0),
+    spent DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    reward DECIMAL(10, 2) NOT NULL CHECK (reward > 0),
+    claims_per_drop INTEGER NOT NULL CHECK (claims_per_drop > 0),
+    rate DECIMAL(10, 2) NOT NULL CHECK (rate > 0),
+    lifetime_seconds INTEGER NOT NULL CHECK (lifetime_seconds > 0),
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    created_by INTEGER NOT NULL REFERENCES admins(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    stopped_at TIMESTAMP,
+    CHECK (ends_at > starts_at),
+    CHECK (spent <= budget)
+);
+
+CREATE TABLE IF NOT EXISTS drops (
+    id SERIAL PRIMARY KEY,
+    campaign_id INTEGER NOT NULL REFERENCES drops_campaigns(id),
+    reward DECIMAL(10, 2) NOT NULL,
+    max_claims INTEGER NOT NULL,
+    claims INTEGER NOT NULL DEFAULT 0,
+    appears_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    CHECK (claims <= max_claims),
+    CHECK (expires_at > appears_at)
+);
+
+CREATE INDEX IF NOT EXISTS drops_expires_at_idx ON drops (expires_at);
+
+CREATE TABLE IF NOT EXISTS drops_claims (
+    id SERIAL PRIMARY KEY,
+    drop_id INTEGER NOT NULL REFERENCES drops(id),
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    transaction_id INTEGER NOT NULL REFERENCES transactions(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (drop_id, user_id)
+);
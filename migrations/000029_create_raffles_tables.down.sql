@@ -0,0 +1,9 @@
+DELETE FROM notifications WHERE type_id IN (SELECT id FROM notifications_types WHERE name = 'raffle update');
+DELETE FROM notifications_types WHERE name = 'raffle update';
+
+DROP TABLE IF EXISTS raffles_tickets;
+DROP TABLE IF EXISTS raffles;
+DROP TABLE IF EXISTS raffles_statuses;
+
+DELETE FROM transactions WHERE type_id IN (SELECT id FROM transaction_types WHERE name = 'raffle ticket');
+DELETE FROM transaction_types WHERE name = 'raffle ticket';
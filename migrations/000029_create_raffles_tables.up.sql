@@ -0,0 +1,56 @@
+INSERT INTO transaction_types (name) VALUES
+    ('raffle ticket')
+ON CONFLICT (name) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS raffles_statuses (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) UNIQUE NOT NULL
+);
+
+INSERT INTO raffles_statuses (name) VALUES
+    ('open'),
+    ('drawn'),
+    ('cancelled')
+ON CONFLICT (name) DO NOTHING;
+
+-- the commitment is the SHA-256 of the seed, it is published from the start while the seed
+-- is revealed only once the raffle is closed, so anyone can verify the draw
+CREATE TABLE IF NOT EXISTS raffles (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    ticket_price DECIMAL(10, 2) NOT NULL CHECK (ticket_price > 0),
+    max_tickets_per_user INTEGER NOT NULL CHECK (max_tickets_per_user > 0),
+    item_id INTEGER REFERENCES shop_items(id),
+    prize_amount DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (prize_amount >= 0),
+    status_id INTEGER NOT NULL DEFAULT 1 REFERENCES raffles_statuses(id),
+    seed VARCHAR(64) NOT NULL,
+    commitment VARCHAR(64) NOT NULL,
+    draw_at TIMESTAMP NOT NULL,
+    tickets_sold INTEGER NOT NULL DEFAULT 0,
+    winning_number INTEGER,
+    winner_id INTEGER REFERENCES users(id),
+    purchase_id INTEGER REFERENCES purchases(id),
+    created_by INTEGER NOT NULL REFERENCES admins(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    closed_at TIMESTAMP,
+    CHECK (item_id IS NOT NULL OR prize_amount > 0)
+);
+
+CREATE INDEX IF NOT EXISTS raffles_status_draw_at_idx ON raffles (status_id, draw_at);
+
+CREATE TABLE IF NOT EXISTS raffles_tickets (
+    id SERIAL PRIMARY KEY,
+    raffle_id INTEGER NOT NULL REFERENCES raffles(id),
+    number INTEGER NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    transaction_id INTEGER NOT NULL REFERENCES transactions(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (raffle_id, number)
+);
+
+CREATE INDEX IF NOT EXISTS raffles_tickets_raffle_id_user_id_idx ON raffles_tickets (raffle_id, user_id);
+
+INSERT INTO notifications_types (name) VALUES
+    ('raffle update')
+ON CONFLICT (name) DO NOTHING;
@@ -0,0 +1,9 @@
+DELETE FROM notifications WHERE type_id IN (SELECT id FROM notifications_types WHERE name = 'event update');
+DELETE FROM notifications_types WHERE name = 'event update';
+
+DROP TABLE IF EXISTS events_codes;
+DROP TABLE IF EXISTS events_rsvps;
+DROP TABLE IF EXISTS events;
+
+DELETE FROM transactions WHERE type_id IN (SELECT id FROM transaction_types WHERE name = 'event reward');
+DELETE FROM transaction_types WHERE name = 'event reward';
@@ -0,0 +1,47 @@
+INSERT INTO transaction_types (name) VALUES
+    ('event reward')
+ON CONFLICT (name) DO NOTHING;
+
+-- zero capacity means the event takes any number of RSVPs
+CREATE TABLE IF NOT EXISTS events (
+    id SERIAL PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    location VARCHAR(255) NOT NULL DEFAULT '',
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    capacity INTEGER NOT NULL DEFAULT 0 CHECK (capacity >= 0),
+    reward DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (reward >= 0),
+    created_by INTEGER NOT NULL REFERENCES admins(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    cancelled_at TIMESTAMP,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS events_starts_at_idx ON events (starts_at);
+
+CREATE TABLE IF NOT EXISTS events_rsvps (
+    event_id INTEGER NOT NULL REFERENCES events(id),
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    checked_in_at TIMESTAMP,
+    transaction_id INTEGER REFERENCES transactions(id),
+    PRIMARY KEY (event_id, user_id)
+);
+
+-- a check-in code is shown at the venue as a signed token, the row keeps its expiry
+-- and, for one-time codes, who has already used it
+CREATE TABLE IF NOT EXISTS events_codes (
+    id SERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL REFERENCES events(id),
+    one_time BOOLEAN NOT NULL DEFAULT FALSE,
+    expires_at TIMESTAMP NOT NULL,
+    used_by INTEGER REFERENCES users(id),
+    used_at TIMESTAMP,
+    created_by INTEGER NOT NULL REFERENCES admins(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO notifications_types (name) VALUES
+    ('event update')
+ON CONFLICT (name) DO NOTHING;
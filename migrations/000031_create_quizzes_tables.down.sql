@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS xp_ledger_user_id_quiz_id_idx;
+DELETE FROM xp_ledger WHERE source_id IN (SELECT id FROM xp_sources WHERE name = 'quiz');
+ALTER TABLE xp_ledger DROP COLUMN IF EXISTS quiz_id;
+DELETE FROM xp_sources WHERE name = 'quiz';
+
+DROP TABLE IF EXISTS quizzes_answers;
+DROP TABLE IF EXISTS quizzes_submissions;
+DROP TABLE IF EXISTS quizzes_questions;
+DROP TABLE IF EXISTS quizzes;
+DROP TABLE IF EXISTS quizzes_questions_kinds;
+DROP TABLE IF EXISTS quizzes_types;
+
+DELETE FROM transactions WHERE type_id IN (SELECT id FROM transaction_types WHERE name = 'quiz reward');
+DELETE FROM transaction_types WHERE name = 'quiz reward';
@@ -0,0 +1,92 @@
+INSERT INTO transaction_types (name) VALUES
+    ('quiz reward')
+ON CONFLICT (name) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS quizzes_types (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) UNIQUE NOT NULL
+);
+
+INSERT INTO quizzes_types (name) VALUES
+    ('quiz'),
+    ('poll')
+ON CONFLICT (name) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS quizzes_questions_kinds (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) UNIQUE NOT NULL
+);
+
+INSERT INTO quizzes_questions_kinds (name) VALUES
+    ('single choice'),
+    ('multiple choice'),
+    ('numeric'),
+    ('short text')
+ON CONFLICT (name) DO NOTHING;
+
+-- a quiz pays amount when the score in percent reaches pass_score, a poll pays it to everyone who answers
+CREATE TABLE IF NOT EXISTS quizzes (
+    id SERIAL PRIMARY KEY,
+    type_id INTEGER NOT NULL REFERENCES quizzes_types(id),
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    amount DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (amount >= 0),
+    pass_score INTEGER NOT NULL DEFAULT 0 CHECK (pass_score BETWEEN 0 AND 100),
+    closes_at TIMESTAMP,
+    created_by INTEGER NOT NULL REFERENCES admins(id),
+    budget_id INTEGER REFERENCES budgets(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    closed_at TIMESTAMP
+);
+
+-- correct answers are kept with the question and never shown to users: option indexes from 0
+-- for choice questions, a number with a tolerance for numeric ones and accepted texts for short ones
+CREATE TABLE IF NOT EXISTS quizzes_questions (
+    quiz_id INTEGER NOT NULL REFERENCES quizzes(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL CHECK (position > 0),
+    kind_id INTEGER NOT NULL REFERENCES quizzes_questions_kinds(id),
+    text TEXT NOT NULL,
+    options TEXT[] NOT NULL DEFAULT '{}',
+    correct_options INTEGER[] NOT NULL DEFAULT '{}',
+    correct_number DECIMAL(20, 6),
+    tolerance DECIMAL(20, 6) NOT NULL DEFAULT 0 CHECK (tolerance >= 0),
+    correct_texts TEXT[] NOT NULL DEFAULT '{}',
+    points INTEGER NOT NULL DEFAULT 1 CHECK (points > 0),
+    PRIMARY KEY (quiz_id, position)
+);
+
+-- every user submits a quiz once, so it is graded and paid once
+CREATE TABLE IF NOT EXISTS quizzes_submissions (
+    id SERIAL PRIMARY KEY,
+    quiz_id INTEGER NOT NULL REFERENCES quizzes(id),
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    score DECIMAL(5, 2) NOT NULL DEFAULT 0,
+    passed BOOLEAN NOT NULL DEFAULT FALSE,
+    reward DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    transaction_id INTEGER REFERENCES transactions(id),
+    submitted_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (quiz_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS quizzes_answers (
+    submission_id INTEGER NOT NULL REFERENCES quizzes_submissions(id),
+    quiz_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    options INTEGER[] NOT NULL DEFAULT '{}',
+    number DECIMAL(20, 6),
+    text TEXT NOT NULL DEFAULT '',
+    correct BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (submission_id, position),
+    FOREIGN KEY (quiz_id, position) REFERENCES quizzes_questions(quiz_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS quizzes_answers_quiz_id_position_idx ON quizzes_answers (quiz_id, position);
+
+INSERT INTO xp_sources (name) VALUES
+    ('quiz')
+ON CONFLICT (name) DO NOTHING;
+
+ALTER TABLE xp_ledger ADD COLUMN IF NOT EXISTS quiz_id INTEGER REFERENCES quizzes(id);
+
+-- a quiz gives XP only once per user, like its reward
+CREATE UNIQUE INDEX IF NOT EXISTS xp_ledger_user_id_quiz_id_idx ON xp_ledger (user_id, quiz_id) WHERE source_id = 3;
@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS users_inventories;
+
+DROP INDEX IF EXISTS businesses_owner_id_idx;
+ALTER TABLE businesses DROP COLUMN IF EXISTS produced_at;
+ALTER TABLE businesses DROP COLUMN IF EXISTS recipe_id;
+
+DROP TABLE IF EXISTS recipes_inputs;
+DROP TABLE IF EXISTS recipes;
+DROP TABLE IF EXISTS resources;
+
+ALTER TABLE businesses_types DROP COLUMN IF EXISTS capacity;
+
+DELETE FROM transactions WHERE type_id IN (SELECT id FROM transaction_types WHERE name = 'resource sale');
+DELETE FROM transaction_types WHERE name = 'resource sale';
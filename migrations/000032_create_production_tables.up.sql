@@ -0,0 +1,76 @@
+INSERT INTO transaction_types (name) VALUES
+    ('resource sale')
+ON CONFLICT (name) DO NOTHING;
+
+-- warehouses raise the storage capacity of their owner by capacity units
+ALTER TABLE businesses_types ADD COLUMN IF NOT EXISTS capacity INTEGER NOT NULL DEFAULT 0 CHECK (capacity >= 0);
+
+UPDATE businesses_types SET capacity = 500 WHERE name = 'warehouse';
+
+-- price is what the game pays for one unit of the resource
+CREATE TABLE IF NOT EXISTS resources (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) UNIQUE NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    price DECIMAL(10, 2) NOT NULL CHECK (price >= 0)
+);
+
+INSERT INTO resources (name, description, price) VALUES
+    ('grain', 'Raw grain grown on a farm', 2),
+    ('vegetables', 'Fresh vegetables grown on a farm', 3),
+    ('feed', 'Animal feed made from grain', 8),
+    ('canned food', 'Canned vegetables that keep for long', 12)
+ON CONFLICT (name) DO NOTHING;
+
+-- a recipe runs once per duration on a business of its type: it takes the inputs from the inventory
+-- of the owner and puts the output there, farm recipes have no inputs
+CREATE TABLE IF NOT EXISTS recipes (
+    id SERIAL PRIMARY KEY,
+    business_type_id INTEGER NOT NULL REFERENCES businesses_types(id),
+    name VARCHAR(255) UNIQUE NOT NULL,
+    output_resource_id INTEGER NOT NULL REFERENCES resources(id),
+    output_quantity INTEGER NOT NULL CHECK (output_quantity > 0),
+    duration_minutes INTEGER NOT NULL CHECK (duration_minutes > 0)
+);
+
+CREATE TABLE IF NOT EXISTS recipes_inputs (
+    recipe_id INTEGER NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    resource_id INTEGER NOT NULL REFERENCES resources(id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (recipe_id, resource_id)
+);
+
+INSERT INTO recipes (business_type_id, name, output_resource_id, output_quantity, duration_minutes)
+SELECT bt.id, r.name, res.id, r.output_quantity, r.duration_minutes
+FROM (VALUES
+    ('farm', 'grow grain', 'grain', 10, 60),
+    ('farm', 'grow vegetables', 'vegetables', 6, 60),
+    ('factory', 'mill feed', 'feed', 4, 60),
+    ('factory', 'can vegetables', 'canned food', 2, 90)
+) AS r (business_type, name, output, output_quantity, duration_minutes)
+JOIN businesses_types bt ON bt.name = r.business_type
+JOIN resources res ON res.name = r.output
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO recipes_inputs (recipe_id, resource_id, quantity)
+SELECT rec.id, res.id, i.quantity
+FROM (VALUES
+    ('mill feed', 'grain', 10),
+    ('can vegetables', 'vegetables', 6)
+) AS i (recipe, input, quantity)
+JOIN recipes rec ON rec.name = i.recipe
+JOIN resources res ON res.name = i.input
+ON CONFLICT (recipe_id, resource_id) DO NOTHING;
+
+-- the owner picks what the business produces, produced_at is when the current cycle started
+ALTER TABLE businesses ADD COLUMN IF NOT EXISTS recipe_id INTEGER REFERENCES recipes(id);
+ALTER TABLE businesses ADD COLUMN IF NOT EXISTS produced_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS businesses_owner_id_idx ON businesses (owner_id);
+
+CREATE TABLE IF NOT EXISTS users_inventories (
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    resource_id INTEGER NOT NULL REFERENCES resources(id),
+    quantity INTEGER NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    PRIMARY KEY (user_id, resource_id)
+);
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS companions;
+
+DELETE FROM purchases WHERE item_id IN (SELECT id FROM shop_items WHERE resource_id IS NOT NULL);
+DELETE FROM shop_items WHERE resource_id IS NOT NULL;
+ALTER TABLE shop_items DROP COLUMN IF EXISTS resource_quantity;
+ALTER TABLE shop_items DROP COLUMN IF EXISTS resource_id;
+
+DELETE FROM users_inventories WHERE resource_id IN (SELECT id FROM resources WHERE name = 'pet treats');
+DELETE FROM resources WHERE name = 'pet treats';
+ALTER TABLE resources DROP COLUMN IF EXISTS joy;
+ALTER TABLE resources DROP COLUMN IF EXISTS nutrition;
@@ -0,0 +1,47 @@
+-- nutrition lowers the hunger and joy raises the happiness of a companion fed one unit of the resource,
+-- a resource with neither is not food
+ALTER TABLE resources ADD COLUMN IF NOT EXISTS nutrition INTEGER NOT NULL DEFAULT 0 CHECK (nutrition >= 0);
+ALTER TABLE resources ADD COLUMN IF NOT EXISTS joy INTEGER NOT NULL DEFAULT 0 CHECK (joy >= 0);
+
+INSERT INTO resources (name, description, price) VALUES
+    ('pet treats', 'Tasty treats your companion loves', 4)
+ON CONFLICT (name) DO NOTHING;
+
+UPDATE resources SET nutrition = f.nutrition, joy = f.joy
+FROM (VALUES
+    ('grain', 2, 0),
+    ('vegetables', 4, 1),
+    ('feed', 15, 0),
+    ('canned food', 10, 5),
+    ('pet treats', 5, 15)
+) AS f (name, nutrition, joy)
+WHERE resources.name = f.name;
+
+-- a purchased item with a resource puts resource_quantity units of it into the inventory of the buyer
+ALTER TABLE shop_items ADD COLUMN IF NOT EXISTS resource_id INTEGER REFERENCES resources(id);
+ALTER TABLE shop_items ADD COLUMN IF NOT EXISTS resource_quantity INTEGER NOT NULL DEFAULT 0 CHECK (resource_quantity >= 0);
+
+INSERT INTO shop_items (name, description, price, in_stock, resource_id, resource_quantity)
+SELECT i.name, i.description, i.price, i.in_stock, r.id, i.quantity
+FROM (VALUES
+    ('Pet treats', 'Five treats for your companion', 30, 1000, 'pet treats', 5),
+    ('Feed bag', 'Ten units of feed for your companion', 100, 1000, 'feed', 10)
+) AS i (name, description, price, in_stock, resource, quantity)
+JOIN resources r ON r.name = i.resource;
+
+-- hunger and happiness are as of updated_at, both decay with time and are brought up to date on every read,
+-- a user without a row has a full and happy companion as of registration
+CREATE TABLE IF NOT EXISTS companions (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id),
+    hunger DECIMAL(5, 2) NOT NULL DEFAULT 0 CHECK (hunger >= 0 AND hunger <= 100),
+    happiness DECIMAL(5, 2) NOT NULL DEFAULT 100 CHECK (happiness >= 0 AND happiness <= 100),
+    fed_at TIMESTAMP,
+    played_at TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- users registered before companions existed get a full and happy one as of now, not as of their
+-- registration, otherwise their companions would be starving and their perks gone right away
+INSERT INTO companions (user_id, updated_at)
+SELECT id, NOW() FROM users
+ON CONFLICT (user_id) DO NOTHING;
@@ -0,0 +1,3 @@
+-- the deadline of the assignee with the perks they had when the task became theirs,
+-- so it does not move with the well-being of their companion afterwards
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS user_deadline TIMESTAMP;
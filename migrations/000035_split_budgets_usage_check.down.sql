@@ -0,0 +1,3 @@
+ALTER TABLE budgets DROP CONSTRAINT IF EXISTS budgets_usage_check;
+ALTER TABLE budgets DROP CONSTRAINT IF EXISTS budgets_reserved_check;
+ALTER TABLE budgets ADD CONSTRAINT budgets_usage_check CHECK (reserved >= 0 AND spent >= 0 AND reserved + spent <= amount);
@@ -0,0 +1,4 @@
+-- releasing more coins than are reserved is a bug to report, not something to round away
+ALTER TABLE budgets DROP CONSTRAINT IF EXISTS budgets_usage_check;
+ALTER TABLE budgets ADD CONSTRAINT budgets_reserved_check CHECK (reserved >= 0);
+ALTER TABLE budgets ADD CONSTRAINT budgets_usage_check CHECK (spent >= 0 AND reserved + spent <= amount);
@@ -0,0 +1 @@
+ALTER TABLE teams_tasks DROP COLUMN IF EXISTS budget_id;
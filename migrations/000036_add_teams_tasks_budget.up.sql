@@ -0,0 +1,2 @@
+-- team task rewards are charged to the budget of the admin who created the task
+ALTER TABLE teams_tasks ADD COLUMN IF NOT EXISTS budget_id BIGINT REFERENCES budgets(id);
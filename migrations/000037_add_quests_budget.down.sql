@@ -0,0 +1 @@
+ALTER TABLE quests DROP COLUMN IF EXISTS budget_id;
@@ -0,0 +1,2 @@
+-- step rewards and the bonus are charged to the budget of the author at the time of publishing
+ALTER TABLE quests ADD COLUMN IF NOT EXISTS budget_id BIGINT REFERENCES budgets(id);
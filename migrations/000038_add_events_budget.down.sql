@@ -0,0 +1 @@
+ALTER TABLE events DROP COLUMN IF EXISTS budget_id;
@@ -0,0 +1,2 @@
+-- check-in rewards are charged to the budget of the admin who created the event
+ALTER TABLE events ADD COLUMN IF NOT EXISTS budget_id BIGINT REFERENCES budgets(id);